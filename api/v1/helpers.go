@@ -49,6 +49,7 @@ const (
 
 	RFC1123DNSSubdomainNameRegex = `^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
 	RFC1035DNSLabelNameRegex     = `^[a-z]([a-z0-9\-]{0,61}[a-z0-9])?$`
+	ConfigParameterNameRegex     = `^[A-Za-z][A-Za-z0-9_]*$`
 
 	MainCluster = ""
 
@@ -71,6 +72,17 @@ const (
 	// Deployment methods
 	DeploymentMethodAT = "admintools"
 	DeploymentMethodVC = "vclusterops"
+
+	// The default number of seconds between two checks for runaway queries
+	DefaultHealthWatchdogCheckIntervalSeconds = 60
+
+	// The default number of days between two rotations of the superuser
+	// password
+	DefaultPasswordRotationIntervalDays = 90
+
+	// The client address range of a TLS authentication method that doesn't
+	// set one
+	DefaultTLSAuthMethodHost = "0.0.0.0/0"
 )
 
 // ExtractNamespacedName gets the name and returns it as a NamespacedName
@@ -409,6 +421,11 @@ func isValidRFC1035DNSLabelName(name string) bool {
 	return r.MatchString(name)
 }
 
+func isValidConfigParameterName(name string) bool {
+	r := regexp.MustCompile(ConfigParameterNameRegex)
+	return r.MatchString(name)
+}
+
 // IsValidSubclusterName validates the subcluster name is valid.  We have rules
 // about its name because it is included in the name of the statefulset, so we
 // must adhere to the Kubernetes rules for object names.
@@ -792,7 +809,54 @@ func (v *VerticaDB) IsAutoCertRotationEnabled(tlsConfig string) bool {
 	config := v.GetTLSConfigSpecByName(tlsConfig)
 	specSet := config != nil && config.AutoRotate != nil && len(config.AutoRotate.Secrets) > 0
 	statusSet := len(v.GetAutoRotateSecrets(tlsConfig)) > 0
-	return specSet || statusSet
+	return specSet || statusSet || v.IsAutoCertIssueEnabled(tlsConfig)
+}
+
+// IsAutoCertIssueEnabled returns true if the operator issues the replacement
+// certificates for a certain tlsconfig (clientServer or httpsNMA), rather than
+// rotating through a list of secrets.
+func (v *VerticaDB) IsAutoCertIssueEnabled(tlsConfig string) bool {
+	if !v.IsTLSAuthEnabledWithMinVersionForConfig(tlsConfig) {
+		return false
+	}
+	return v.GetTLSConfigAutoIssue(tlsConfig) != nil
+}
+
+// GetTLSConfigAutoIssue gets the TLSCertIssue from spec
+// for a certain tlsconfig (clientServer or httpsNMA)
+func (v *VerticaDB) GetTLSConfigAutoIssue(tlsConfig string) *TLSCertIssue {
+	autoRotate := v.GetTLSConfigAutoRotate(tlsConfig)
+	if autoRotate == nil {
+		return nil
+	}
+	return autoRotate.Issue
+}
+
+// GetCertRenewalTime returns the time at which a certificate valid from notBefore
+// to notAfter should be replaced, given the lifetime percentage from autoRotate.issue.
+func (t *TLSCertIssue) GetCertRenewalTime(notBefore, notAfter time.Time) time.Time {
+	pct := t.RenewAtLifetimePercent
+	if pct <= 0 || pct >= 100 {
+		pct = DefaultRenewAtLifetimePercent
+	}
+	lifetime := notAfter.Sub(notBefore)
+	return notBefore.Add(lifetime / 100 * time.Duration(pct))
+}
+
+// GetIssuerKind returns the kind of the cert-manager issuer, defaulting to Issuer.
+func (c *CertManagerIssuerRef) GetIssuerKind() string {
+	if c.Kind == "" {
+		return IssuerKind
+	}
+	return c.Kind
+}
+
+// GetIssuerGroup returns the API group of the cert-manager issuer.
+func (c *CertManagerIssuerRef) GetIssuerGroup() string {
+	if c.Group == "" {
+		return CertManagerGroup
+	}
+	return c.Group
 }
 
 // GetAutoRotateSecrets gets the list of auto-rotate secrets from status
@@ -1430,6 +1494,10 @@ func (v *VerticaDB) GetVerticaUser() string {
 
 // GetPasswordSecret returns the password secret
 func (v *VerticaDB) GetPasswordSecret() string {
+	// the secret of the last rotation holds the current password
+	if secret := v.getRotatedPasswordSecret(); secret != "" {
+		return secret
+	}
 	// status holds the current password
 	if v.Status.PasswordSecret != nil {
 		return *v.Status.PasswordSecret
@@ -1438,6 +1506,25 @@ func (v *VerticaDB) GetPasswordSecret() string {
 	return v.Spec.PasswordSecret
 }
 
+// GetDesiredPasswordSecret returns the secret with the password that must be
+// in effect in the database. While the password is rotated by the operator,
+// this is the secret of the last rotation rather than the one in the spec.
+func (v *VerticaDB) GetDesiredPasswordSecret() string {
+	if secret := v.getRotatedPasswordSecret(); secret != "" {
+		return secret
+	}
+	return v.Spec.PasswordSecret
+}
+
+// getRotatedPasswordSecret returns the secret of the last rotation of the
+// superuser password, if the password is rotated by the operator
+func (v *VerticaDB) getRotatedPasswordSecret() string {
+	if !v.IsPasswordRotationEnabled() || v.Status.PasswordRotation == nil {
+		return ""
+	}
+	return v.Status.PasswordRotation.CurrentSecret
+}
+
 // GetEncryptSpreadComm will return "vertica" if encryptSpreadComm is set to
 // an empty string, otherwise return the value of encryptSpreadComm
 func (v *VerticaDB) GetEncryptSpreadComm() string {
@@ -1873,13 +1960,17 @@ func (s *ScaleTrigger) GetMetadata() map[string]string {
 }
 
 func (s *ScaleTrigger) IsNil() bool {
-	return s.Prometheus == nil && s.Resource == nil
+	return s.Prometheus == nil && s.Resource == nil && s.Vertica == nil
 }
 
 func (s *ScaleTrigger) IsPrometheusMetric() bool {
 	return s.Type == PrometheusTriggerType || s.Type == ""
 }
 
+func (s *ScaleTrigger) IsVerticaMetric() bool {
+	return s.Type == VerticaTriggerType
+}
+
 func (s *ScaleTrigger) GetUnsafeSslStr() string {
 	return strconv.FormatBool(s.Prometheus.UnsafeSsl)
 }
@@ -1926,13 +2017,68 @@ func (v *VerticaAutoscaler) HasScaleInThreshold() bool {
 }
 
 // GetMinReplicas calculates the minReplicas based on the scale in
-// threshold, and returns it
+// threshold and the scaling floor, and returns it
 func (v *VerticaAutoscaler) GetMinReplicas() *int32 {
 	vasCopy := v.DeepCopy()
 	if v.HasScaleInThreshold() {
-		return &vasCopy.Spec.TargetSize
+		return v.ApplyScalingFloor(&vasCopy.Spec.TargetSize, vasCopy.Spec.CustomAutoscaler.Hpa.MaxReplicas)
 	}
-	return vasCopy.Spec.CustomAutoscaler.Hpa.MinReplicas
+	return v.ApplyScalingFloor(vasCopy.Spec.CustomAutoscaler.Hpa.MinReplicas, vasCopy.Spec.CustomAutoscaler.Hpa.MaxReplicas)
+}
+
+// GetScaledObjectMinReplicas returns the minReplicaCount of the scaledObject,
+// raised to the scaling floor if one applies.
+func (v *VerticaAutoscaler) GetScaledObjectMinReplicas() *int32 {
+	so := v.Spec.CustomAutoscaler.ScaledObject
+	if so.MaxReplicas == nil {
+		return so.MinReplicas
+	}
+	return v.ApplyScalingFloor(so.MinReplicas, *so.MaxReplicas)
+}
+
+// GetScalingFloor returns the pod count that metric-based scaling cannot go
+// below. It is the larger of the size required by the active schedule window
+// and the size predicted from the load profile. 0 means there is no floor.
+func (v *VerticaAutoscaler) GetScalingFloor() int32 {
+	var floor int32
+	if v.IsScheduleEnabled() {
+		floor = v.Status.ScheduledSize
+	}
+	if v.IsPredictiveEnabled() && v.Status.Forecast != nil {
+		floor = max(floor, v.Status.Forecast.PredictedSize)
+	}
+	return floor
+}
+
+// ApplyScalingFloor returns minReplicas raised to the scaling floor. The
+// result never exceeds maxReplicas.
+func (v *VerticaAutoscaler) ApplyScalingFloor(minReplicas *int32, maxReplicas int32) *int32 {
+	scalingFloor := v.GetScalingFloor()
+	if scalingFloor == 0 {
+		return minReplicas
+	}
+	if minReplicas != nil && *minReplicas >= scalingFloor {
+		return minReplicas
+	}
+	floor := min(scalingFloor, maxReplicas)
+	return &floor
+}
+
+// GetScalingPolicySize returns the pod count the hpa is pinned to when the
+// scaling policy is used. It is the last size the policy decided on, or the
+// targetSize until the policy is first evaluated. It is kept within the
+// replica range of the hpa and never goes below the scaling floor.
+func (v *VerticaAutoscaler) GetScalingPolicySize() int32 {
+	hpa := v.Spec.CustomAutoscaler.Hpa
+	size := v.Spec.TargetSize
+	if v.Status.PolicyEvaluation != nil {
+		size = v.Status.PolicyEvaluation.DesiredSize
+	}
+	if hpa.MinReplicas != nil {
+		size = max(size, *hpa.MinReplicas)
+	}
+	size = *v.ApplyScalingFloor(&size, hpa.MaxReplicas)
+	return min(size, hpa.MaxReplicas)
 }
 
 // GetMetricMap returns a map whose key is the metric name and the value is
@@ -2214,6 +2360,14 @@ func SetTLSConfigs(refs *[]TLSConfigStatus, newRef *TLSConfigStatus) (changed bo
 		existing.AutoRotateFailedSecret = newRef.AutoRotateFailedSecret
 		changed = true
 	}
+	if newRef.CertExpiry != nil && !newRef.CertExpiry.Equal(existing.CertExpiry) {
+		existing.CertExpiry = newRef.CertExpiry
+		changed = true
+	}
+	if newRef.NextRotation != nil && !newRef.NextRotation.Equal(existing.NextRotation) {
+		existing.NextRotation = newRef.NextRotation
+		changed = true
+	}
 
 	return changed
 }
@@ -2422,3 +2576,159 @@ func (v *VerticaDB) IsHTTPPollingError(err error) bool {
 
 	return false
 }
+
+// GetRunawayQueryAction returns what the operator does with the runaway
+// queries flagged by the health watchdog
+func (v *VerticaDB) GetRunawayQueryAction() RunawayQueryActionType {
+	if v.Spec.HealthWatchdog == nil || v.Spec.HealthWatchdog.RunawayQueryAction == "" {
+		return RunawayQueryActionNone
+	}
+	return v.Spec.HealthWatchdog.RunawayQueryAction
+}
+
+// IsHealthWatchdogCheckEnabled returns true if the operator must periodically
+// check the health watchdog for runaway queries
+func (v *VerticaDB) IsHealthWatchdogCheckEnabled() bool {
+	return v.GetRunawayQueryAction() != RunawayQueryActionNone
+}
+
+// IsHealthWatchdogCancelEnabled returns true if the operator must cancel the
+// runaway queries flagged by the health watchdog
+func (v *VerticaDB) IsHealthWatchdogCancelEnabled() bool {
+	return v.GetRunawayQueryAction() == RunawayQueryActionCancel
+}
+
+// GetHealthWatchdogCheckInterval returns the time between two checks for
+// runaway queries
+func (v *VerticaDB) GetHealthWatchdogCheckInterval() time.Duration {
+	secs := DefaultHealthWatchdogCheckIntervalSeconds
+	if v.Spec.HealthWatchdog != nil && v.Spec.HealthWatchdog.CheckIntervalSeconds > 0 {
+		secs = v.Spec.HealthWatchdog.CheckIntervalSeconds
+	}
+	return time.Duration(secs) * time.Second
+}
+
+// GetHealthWatchdogNextCheckIn returns how long to wait, from now, before the
+// next check for runaway queries is due. It returns zero if no check is
+// scheduled or if one is already due.
+func (v *VerticaDB) GetHealthWatchdogNextCheckIn(now time.Time) time.Duration {
+	if !v.IsHealthWatchdogCheckEnabled() {
+		return 0
+	}
+	if v.Status.HealthWatchdog == nil || v.Status.HealthWatchdog.LastCheckTime == nil {
+		return 0
+	}
+	next := v.Status.HealthWatchdog.LastCheckTime.Add(v.GetHealthWatchdogCheckInterval())
+	if !next.After(now) {
+		return 0
+	}
+	return next.Sub(now)
+}
+
+// GetCertExpiryNextCheckIn returns how long to wait, from now, before the
+// certificates of the TLS secrets must be checked again because one crosses a
+// warning threshold or expires. It returns zero if no check is scheduled or
+// if one is already due.
+func (v *VerticaDB) GetCertExpiryNextCheckIn(now time.Time) time.Duration {
+	if v.Status.CertExpiryNextCheckTime == nil || !v.Status.CertExpiryNextCheckTime.After(now) {
+		return 0
+	}
+	return v.Status.CertExpiryNextCheckTime.Sub(now)
+}
+
+// GetRebalanceShardsRetryIn returns how long to wait, from now, before a
+// rebalance that timed out can be tried again. It returns zero if the last
+// rebalance didn't time out or if the backoff is over.
+func (v *VerticaDB) GetRebalanceShardsRetryIn(now time.Time) time.Duration {
+	maxDuration := time.Duration(vmeta.GetRebalanceShardsMaxDuration(v.Annotations)) * time.Second
+	if maxDuration <= 0 {
+		return 0
+	}
+	cond := v.FindStatusCondition(RebalanceShardsInProgress)
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != RebalanceShardsTimedOutReason {
+		return 0
+	}
+	retry := cond.LastTransitionTime.Add(maxDuration)
+	if !retry.After(now) {
+		return 0
+	}
+	return retry.Sub(now)
+}
+
+// IsPasswordRotationEnabled returns true if the operator must rotate the
+// superuser password on an interval
+func (v *VerticaDB) IsPasswordRotationEnabled() bool {
+	return v.Spec.PasswordRotation != nil
+}
+
+// IsPasswordRotationPending returns true if a rotation of the superuser
+// password was started and not completed
+func (v *VerticaDB) IsPasswordRotationPending() bool {
+	return v.Status.PasswordRotation != nil && v.Status.PasswordRotation.PendingSecret != ""
+}
+
+// GetPasswordRotationInterval returns the time between two rotations of the
+// superuser password
+func (v *VerticaDB) GetPasswordRotationInterval() time.Duration {
+	days := DefaultPasswordRotationIntervalDays
+	if v.Spec.PasswordRotation != nil && v.Spec.PasswordRotation.IntervalDays > 0 {
+		days = v.Spec.PasswordRotation.IntervalDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// GetPasswordRotationNextIn returns how long to wait, from now, before the
+// next rotation of the superuser password is due. The first rotation is due
+// one interval after the policy was enabled. It returns zero if no rotation
+// is scheduled or if one is already due.
+func (v *VerticaDB) GetPasswordRotationNextIn(now time.Time) time.Duration {
+	if !v.IsPasswordRotationEnabled() || v.Status.PasswordRotation == nil {
+		return 0
+	}
+	last := v.Status.PasswordRotation.LastRotationTime
+	if last == nil {
+		last = v.Status.PasswordRotation.EnabledTime
+	}
+	if last == nil {
+		return 0
+	}
+	next := last.Add(v.GetPasswordRotationInterval())
+	if !next.After(now) {
+		return 0
+	}
+	return next.Sub(now)
+}
+
+// GetPasswordRotationSecretName returns the name of the secret that stores
+// the password of the given rotation
+func (v *VerticaDB) GetPasswordRotationSecretName(rotation int) string {
+	return fmt.Sprintf("%s-%d", v.Spec.PasswordRotation.TargetSecret, rotation)
+}
+
+// IsClientTLSAuthEnabled returns true if database users can log in with a
+// client certificate
+func (v *VerticaDB) IsClientTLSAuthEnabled() bool {
+	return v.Spec.ClientTLSAuth != nil
+}
+
+// GetHost returns the client address range that the method applies to
+func (m *TLSAuthMethod) GetHost() string {
+	if m.Host == "" {
+		return DefaultTLSAuthMethodHost
+	}
+	return m.Host
+}
+
+// GetClientTLSAuthMethodStatus returns the status of the TLS authentication
+// method with the given name, or nil if the operator didn't create it
+func (v *VerticaDB) GetClientTLSAuthMethodStatus(name string) *TLSAuthMethodStatus {
+	if v.Status.ClientTLSAuth == nil {
+		return nil
+	}
+	for i := range v.Status.ClientTLSAuth.Methods {
+		if v.Status.ClientTLSAuth.Methods[i].Name == name {
+			return &v.Status.ClientTLSAuth.Methods[i]
+		}
+	}
+	return nil
+}
@@ -89,6 +89,9 @@ const (
 	FetchNodeDetailsWithVclusterOpsMinVersion = "v24.3.0"
 	// Starting in v24.4.0, saving a restore point to an existing archive is supported
 	SaveRestorePointNMAOpsMinVersion = "v24.4.0"
+	// The vcluster capture_workload and replay_workload commands, used by
+	// VerticaWorkloadReplay, are only bundled with images starting in v26.1.0
+	WorkloadReplayMinVersion = "v26.1.0"
 	// starting in v24.3-4, v24.4-1, and v25.0-0 pausing sessions works a little differently
 	MinPauseSessionsVersion243 = "v24.3.0-4"
 	MinPauseSessionsVersion244 = "v24.4.0-1"
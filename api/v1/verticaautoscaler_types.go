@@ -87,14 +87,43 @@ type VerticaAutoscalerSpec struct {
 	// The scaling behavior can also be customized to meet different performance requirements. The maximum and mininum of
 	// sizes of the replica sets can be specified to limit the use of resources.
 	CustomAutoscaler *CustomAutoscalerSpec `json:"customAutoscaler,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// Controls which subclusters are removed when scaling in with the
+	// "Subcluster" granularity. When set, the candidate subclusters are ranked
+	// by their running queries and active sessions, and the least busy one is
+	// removed first. Otherwise, the last subcluster is removed first.
+	ScaleInSafety *ScaleInSafetySpec `json:"scaleInSafety,omitempty"`
+}
+
+// ScaleInSafetySpec defines how busy subclusters are handled on scale in
+type ScaleInSafetySpec struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=0
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	// How long, in seconds, to wait for the queries running in the chosen
+	// subcluster to finish before it is removed. If 0, the subcluster is
+	// removed without waiting.
+	QueryGracePeriodSeconds int32 `json:"queryGracePeriodSeconds,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=false
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	// If true, the queries still running in the chosen subcluster once the
+	// grace period has passed are cancelled before it is removed. Otherwise,
+	// they are left to the connection draining of the VerticaDB.
+	CancelQueries bool `json:"cancelQueries,omitempty"`
 }
 
 // CustomAutoscalerSpec customizes VerticaAutoscaler
 type CustomAutoscalerSpec struct {
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default:=ScaledObject
-	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:HPA","urn:alm:descriptor:com.tectonic.ui:select:ScaledObject"}
-	// The type of autoscaler. It must be one of "HPA" or "ScaledObject".
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:HPA","urn:alm:descriptor:com.tectonic.ui:select:ScaledObject","urn:alm:descriptor:com.tectonic.ui:select:Schedule"}
+	// The type of autoscaler. It must be one of "HPA", "ScaledObject" or
+	// "Schedule". With "Schedule", the size is driven only by the schedule
+	// windows and no hpa or scaledObject is created.
 	Type string `json:"type,omitempty"`
 
 	// +kubebuilder:validation:Optional
@@ -108,13 +137,204 @@ type CustomAutoscalerSpec struct {
 	// It refers to an autoscaling definition through a scaledObject.
 	// If type is "ScaledObject", this must be set.
 	ScaledObject *ScaledObjectSpec `json:"scaledObject,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// A list of time windows, each with the size the subclusters must have
+	// while the window is active. If type is "Schedule", this must be set and
+	// the operator sets the targetSize from the active window. With "HPA" or
+	// "ScaledObject", the size of the active window is a floor: metric-based
+	// scaling can still raise the size above it, but not lower it below.
+	Schedule *ScheduleSpec `json:"schedule,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// Enables predictive scaling. The operator learns the load for each hour
+	// of the week from the hpa metrics and pre-scales ahead of the expected
+	// demand. This can only be used when type is "HPA".
+	Predictive *PredictiveSpec `json:"predictive,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// Lets the operator make the scaling decision from all of the hpa metrics
+	// at once, instead of the hpa following the metric that asks for the most
+	// pods. The metrics are combined by weight, and scale in can be gated on
+	// several metrics at a time. The hpa is then pinned to the size the
+	// operator decides. This can only be used when type is "HPA".
+	ScalingPolicy *ScalingPolicySpec `json:"scalingPolicy,omitempty"`
 }
 
 const (
 	HPA          = "HPA"
 	ScaledObject = "ScaledObject"
+	Schedule     = "Schedule"
 )
 
+// ScheduleSpec defines time windows that drive the size of the subclusters
+type ScheduleSpec struct {
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The IANA time zone (e.g. America/New_York) that the cron expressions of
+	// the windows are evaluated in. If omitted, UTC is used.
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// The list of windows. If more than one window is active at a time, the
+	// largest size wins.
+	Windows []ScheduleWindow `json:"windows"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:podCount"
+	// The total pod count to use when no window is active. If omitted, the
+	// targetSize is left untouched outside of the windows. This is only used
+	// when type is "Schedule".
+	DefaultTargetSize *int32 `json:"defaultTargetSize,omitempty"`
+}
+
+// PredictiveSpec defines how the load profile is learned and used to pre-scale
+type PredictiveSpec struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=15
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	// How many minutes ahead of the expected demand to scale out. This should
+	// cover the time it takes to add the pods and rebalance the shards.
+	LeadTimeMinutes int32 `json:"leadTimeMinutes,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=2
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	// The number of weeks of history an hour needs before its forecast is
+	// used.
+	MinWeeks int32 `json:"minWeeks,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=30
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	// The weight, in percent, given to the latest week when it is averaged
+	// into the profile. A higher value adapts faster to changes in the load.
+	SmoothingPercent int32 `json:"smoothingPercent,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The IANA time zone (e.g. America/New_York) that the hours of the week
+	// are in. If omitted, UTC is used.
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// ScheduleWindow is a recurring period of time with a fixed size
+type ScheduleWindow struct {
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The name of the window. It is reported in the status when the window is
+	// active.
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// A cron expression for when the window starts (e.g. "0 8 * * 1-5").
+	Start string `json:"start"`
+
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// A cron expression for when the window ends (e.g. "0 18 * * 1-5").
+	End string `json:"end"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:podCount"
+	// The total pod count while the window is active. Exactly one of
+	// targetSize or subclusterCount must be set.
+	TargetSize int32 `json:"targetSize,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	// The number of subclusters while the window is active. This can only be
+	// used when scalingGranularity is Subcluster. The pod count is derived
+	// from the size of the template, or of the first subcluster selected by
+	// the service name if the template is not used.
+	SubclusterCount int32 `json:"subclusterCount,omitempty"`
+}
+
+// ScalingPolicySpec defines how the hpa metrics are combined into a single
+// scaling decision
+type ScalingPolicySpec struct {
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// The weight of each metric. The pod count is the current size multiplied
+	// by the weighted average of the ratio between each metric and its
+	// target. Metrics that are not listed have a weight of 1.
+	Weights []MetricWeight `json:"weights,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// The conditions that must hold before scaling in. While they don't hold,
+	// the size can only grow. If omitted, the weighted pod count is used in
+	// both directions.
+	ScaleIn *ScaleInRule `json:"scaleIn,omitempty"`
+}
+
+// MetricWeight is the weight of a single hpa metric
+type MetricWeight struct {
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The name of the metric. This is the resource name (e.g. cpu) for
+	// resource metrics and the metric name for the other types.
+	Metric string `json:"metric"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum:=0
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	// The weight of the metric relative to the others. A weight of 0 keeps
+	// the metric out of the pod count, but it can still be used to gate
+	// scale in.
+	Weight int32 `json:"weight"`
+}
+
+type ScaleInOperator string
+
+const (
+	ScaleInOperatorAnd ScaleInOperator = "And"
+	ScaleInOperatorOr  ScaleInOperator = "Or"
+)
+
+// ScaleInRule is a set of metric conditions that gate scale in
+type ScaleInRule struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=And
+	// +kubebuilder:validation:Enum:=And;Or
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:And","urn:alm:descriptor:com.tectonic.ui:select:Or"}
+	// How the conditions are combined. With "And", all of them must be met.
+	// With "Or", one of them is enough.
+	Operator ScaleInOperator `json:"operator,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// The metric conditions
+	Conditions []ScaleInCondition `json:"conditions"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=0
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	// How long, in seconds, the conditions must be met without interruption
+	// before scaling in.
+	DurationSeconds int32 `json:"durationSeconds,omitempty"`
+}
+
+// ScaleInCondition is met when a metric is at or below a threshold
+type ScaleInCondition struct {
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The name of the metric. It must be one of the hpa metrics.
+	Metric string `json:"metric"`
+
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// The condition is met when the metric is at or below this value. It
+	// must be of the same type as the target of the metric.
+	Threshold autoscalingv2.MetricTarget `json:"threshold"`
+}
+
 type HPASpec struct {
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:Minimum:=0
@@ -181,8 +401,8 @@ type ScaledObjectSpec struct {
 type ScaleTrigger struct {
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default:=""
-	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:prometheus","urn:alm:descriptor:com.tectonic.ui:select:cpu","urn:alm:descriptor:com.tectonic.ui:select:memory"}
-	// The type of metric that is being defined. It can be either cpu, memory, or prometheus.
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:prometheus","urn:alm:descriptor:com.tectonic.ui:select:cpu","urn:alm:descriptor:com.tectonic.ui:select:memory","urn:alm:descriptor:com.tectonic.ui:select:vertica"}
+	// The type of metric that is being defined. It can be either cpu, memory, prometheus or vertica.
 	// An empty string currently defaults prometheus.
 	Type TriggerType `json:"type,omitempty"`
 
@@ -201,7 +421,7 @@ type ScaleTrigger struct {
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:Utilization","urn:alm:descriptor:com.tectonic.ui:select:Value","urn:alm:descriptor:com.tectonic.ui:select:AverageValue"}
 	// Represents whether the metric type is Utilization, Value, or AverageValue.
-	// Allowed types are 'Value' or 'AverageValue' for prometheus and
+	// Allowed types are 'Value' or 'AverageValue' for prometheus/vertica and
 	// 'Utilization' or 'AverageValue' for cpu/memory. If not specified, it defaults to Value
 	// for prometheus and Utilization for cpu/memory.
 	MetricType autoscalingv2.MetricTargetType `json:"metricType,omitempty"`
@@ -217,6 +437,14 @@ type ScaleTrigger struct {
 	// The detail about the target value and container name. if type is cpu/memory
 	// this must be set.
 	Resource *CPUMemorySpec `json:"resource,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// The detail about which Vertica system table metric to sample and the
+	// threshold to scale on. If type is "vertica", this must be set. The
+	// operator queries the database directly, so no Prometheus server is
+	// needed. All of the metrics must be of this type if one of them is.
+	Vertica *VerticaMetricSpec `json:"vertica,omitempty"`
 }
 
 type TriggerType string
@@ -225,6 +453,19 @@ const (
 	CPUTriggerType        TriggerType = "cpu"
 	MemTriggerType        TriggerType = "memory"
 	PrometheusTriggerType TriggerType = "prometheus"
+	VerticaTriggerType    TriggerType = "vertica"
+)
+
+type VerticaMetricName string
+
+const (
+	// The number of requests waiting in the resource pool queues
+	VerticaMetricQueueLength VerticaMetricName = "queueLength"
+	// The number of user sessions that are running a statement
+	VerticaMetricActiveSessions VerticaMetricName = "activeSessions"
+	// The average duration, in milliseconds, of the queries that completed
+	// within the lookback window
+	VerticaMetricQueryLatency VerticaMetricName = "queryLatency"
 )
 
 type PrometheusAuthModes string
@@ -293,6 +534,42 @@ type PrometheusSpec struct {
 	UseCachedMetrics bool `json:"useCachedMetrics,omitempty"`
 }
 
+type VerticaMetricSpec struct {
+	// +kubebuilder:validation:Enum:=queueLength;activeSessions;queryLatency
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:queueLength","urn:alm:descriptor:com.tectonic.ui:select:activeSessions","urn:alm:descriptor:com.tectonic.ui:select:queryLatency"}
+	// The Vertica metric to sample. It can be one of:
+	// - queueLength: the number of requests waiting in the resource pool
+	//   queues (v_monitor.resource_queues).
+	// - activeSessions: the number of sessions running a statement
+	//   (v_monitor.sessions).
+	// - queryLatency: the average duration in milliseconds of the queries
+	//   that completed within the lookback window (v_monitor.query_requests).
+	Metric VerticaMetricName `json:"metric"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// Only count the requests queued in this resource pool. This can only be
+	// set for the queueLength metric. If omitted, all pools are counted.
+	ResourcePool string `json:"resourcePool,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=60
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	// The number of seconds of completed queries to average over for the
+	// queryLatency metric. It is ignored by the other metrics.
+	LookbackSeconds int32 `json:"lookbackSeconds,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	// The threshold value at which scale out is triggered.
+	Threshold int32 `json:"threshold"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	// This is the lower bound at which the autoscaler starts scaling in to the minimum replica count.
+	// If the metric falls below threshold but is still above this value, the current replica count remains unchanged.
+	ScaleInThreshold int32 `json:"scaleInThreshold,omitempty"`
+}
+
 type CPUMemorySpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
 	// The value to trigger scaling for.
@@ -343,6 +620,174 @@ type VerticaAutoscalerStatus struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	// Conditions for VerticaAutoscaler
 	Conditions []VerticaAutoscalerCondition `json:"conditions,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The name of the schedule window that is currently active. This is empty
+	// if no window is active or no schedule is set.
+	ActiveScheduleWindow string `json:"activeScheduleWindow,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The pod count required by the schedule at the last reconcile. When
+	// metric-based scaling is also used, this is the floor it cannot scale
+	// below.
+	ScheduledSize int32 `json:"scheduledSize,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The last value sampled for each vertica metric. This is only set when
+	// the scaledObject uses metrics of type vertica.
+	VerticaMetrics []VerticaMetricStatus `json:"verticaMetrics,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The latest forecast of the predictive policy and the decision that was
+	// made from it. This is only set when predictive scaling is enabled.
+	Forecast *PredictiveForecast `json:"forecast,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The subclusters chosen for removal at the last scale in, and why they
+	// were chosen. This is only set when scaleInSafety is set.
+	ScaleInVictims []ScaleInVictim `json:"scaleInVictims,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The inputs and the result of the last evaluation of the scaling policy.
+	// This is only set when scalingPolicy is set.
+	PolicyEvaluation *ScalingPolicyEvaluation `json:"policyEvaluation,omitempty"`
+}
+
+// ScalingPolicyEvaluation records how the scaling policy arrived at a size
+type ScalingPolicyEvaluation struct {
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// The time the policy was evaluated. The evaluation is only recorded
+	// when its result or inputs change.
+	EvaluationTime metav1.Time `json:"evaluationTime"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// The value of each metric that was used
+	Metrics []PolicyMetricEvaluation `json:"metrics,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// The weighted average of the ratio between each metric and its target,
+	// in percent
+	WeightedRatioPercent int32 `json:"weightedRatioPercent"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// True if the scale in conditions are met
+	ScaleInConditionsMet bool `json:"scaleInConditionsMet"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The time the scale in conditions started to be met. It is cleared as
+	// soon as they are no longer met.
+	ScaleInConditionsMetSince *metav1.Time `json:"scaleInConditionsMetSince,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// The pod count the policy decided on
+	DesiredSize int32 `json:"desiredSize"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// Explains the decision
+	Reason string `json:"reason"`
+}
+
+// PolicyMetricEvaluation is the value of a single metric fed to the policy
+type PolicyMetricEvaluation struct {
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// The name of the metric
+	Name string `json:"name"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// The current value of the metric, as reported by the hpa
+	Value string `json:"value"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// The weight the metric was given
+	Weight int32 `json:"weight"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// The ratio between the metric and its target, in percent
+	RatioPercent int32 `json:"ratioPercent"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// Whether the scale in condition of this metric is met. This is not set
+	// if the metric has no scale in condition.
+	ScaleInConditionMet *bool `json:"scaleInConditionMet,omitempty"`
+}
+
+// ScaleInVictim is a subcluster chosen for removal on scale in
+type ScaleInVictim struct {
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// The name of the subcluster
+	Subcluster string `json:"subcluster"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// The number of client sessions connected to the subcluster when it was
+	// last checked
+	ActiveSessions int32 `json:"activeSessions"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// The number of queries running in the subcluster when it was last
+	// checked
+	RunningQueries int32 `json:"runningQueries"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// The time the subcluster was first chosen. The query grace period starts
+	// at this time.
+	SelectedTime metav1.Time `json:"selectedTime"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// Explains why the subcluster was chosen
+	Reason string `json:"reason"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The time the subcluster was removed from the VerticaDB. This is not set
+	// while the operator waits for its queries to finish.
+	RemovedTime *metav1.Time `json:"removedTime,omitempty"`
+}
+
+// PredictiveForecast is the expected demand and what the operator did about it
+type PredictiveForecast struct {
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// The start of the hour the forecast is for
+	ForecastTime metav1.Time `json:"forecastTime"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// The pod count the load of the current hour asks for
+	CurrentDemand int32 `json:"currentDemand"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// The pod count the load profile expects to need. This is 0 if there is
+	// not enough history for the hour yet.
+	PredictedSize int32 `json:"predictedSize"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// Explains why the operator did or did not pre-scale
+	Reason string `json:"reason"`
+}
+
+// VerticaMetricStatus is the last value sampled for a vertica metric
+type VerticaMetricStatus struct {
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// The name of the metric in the scaledObject
+	Name string `json:"name"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// The value that was sampled
+	Value int64 `json:"value"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// The pod count this metric asked for when it was sampled
+	DesiredSize int32 `json:"desiredSize"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// The time the value was sampled
+	SampleTime metav1.Time `json:"sampleTime"`
 }
 
 // VerticaAutoscalerCondition defines condition for VerticaAutoscaler
@@ -541,10 +986,25 @@ func (v *VerticaAutoscaler) IsCustomAutoScalerSet() bool {
 // IsCustomMetricsEnabled returns true if the CR is set to use
 // custom metrics for scaling.
 func (v *VerticaAutoscaler) IsCustomMetricsEnabled() bool {
-	return v.IsCustomAutoScalerSet() &&
+	return v.IsCustomAutoScalerSet() && !v.IsScheduleType() && !v.IsVerticaMetricsEnabled() &&
 		(v.Spec.CustomAutoscaler.Hpa != nil || v.Spec.CustomAutoscaler.ScaledObject != nil)
 }
 
+// IsVerticaMetricsEnabled returns true if the scaledObject only uses vertica
+// metrics. The operator samples them and sets the targetSize itself, so no
+// keda scaledObject is created.
+func (v *VerticaAutoscaler) IsVerticaMetricsEnabled() bool {
+	if !v.IsScaledObjectEnabled() || len(v.Spec.CustomAutoscaler.ScaledObject.Metrics) == 0 {
+		return false
+	}
+	for i := range v.Spec.CustomAutoscaler.ScaledObject.Metrics {
+		if !v.Spec.CustomAutoscaler.ScaledObject.Metrics[i].IsVerticaMetric() {
+			return false
+		}
+	}
+	return true
+}
+
 // IsHpaEnabled returns true if custom autoscaling with hpa is set.
 func (v *VerticaAutoscaler) IsHpaEnabled() bool {
 	return v.IsCustomAutoScalerSet() && v.Spec.CustomAutoscaler.Type == HPA && v.Spec.CustomAutoscaler.Hpa != nil
@@ -560,6 +1020,30 @@ func (v *VerticaAutoscaler) IsScaledObjectEnabled() bool {
 	return v.IsCustomAutoScalerSet() && v.Spec.CustomAutoscaler.Type == ScaledObject && v.Spec.CustomAutoscaler.ScaledObject != nil
 }
 
+// IsScheduleType returns true if custom autoscaler type is Schedule.
+func (v *VerticaAutoscaler) IsScheduleType() bool {
+	return v.IsCustomAutoScalerSet() && v.Spec.CustomAutoscaler.Type == Schedule
+}
+
+// IsScheduleEnabled returns true if schedule windows are set. They are used
+// alone with the Schedule type, or as a floor for the hpa/scaledObject.
+func (v *VerticaAutoscaler) IsScheduleEnabled() bool {
+	return v.IsCustomAutoScalerSet() && v.Spec.CustomAutoscaler.Schedule != nil &&
+		len(v.Spec.CustomAutoscaler.Schedule.Windows) > 0
+}
+
+// IsPredictiveEnabled returns true if predictive scaling is set. It is only
+// supported with the hpa.
+func (v *VerticaAutoscaler) IsPredictiveEnabled() bool {
+	return v.IsHpaEnabled() && v.Spec.CustomAutoscaler.Predictive != nil
+}
+
+// IsScalingPolicyEnabled returns true if the operator makes the scaling
+// decision from the scaling policy. It is only supported with the hpa.
+func (v *VerticaAutoscaler) IsScalingPolicyEnabled() bool {
+	return v.IsHpaEnabled() && v.Spec.CustomAutoscaler.ScalingPolicy != nil
+}
+
 // IsScaledObjectType returns true if custom autoscaler type is SacledObject.
 func (v *VerticaAutoscaler) IsScaledObjectType() bool {
 	return v.IsCustomAutoScalerSet() && v.Spec.CustomAutoscaler.Type == ScaledObject
@@ -20,7 +20,9 @@ package v1
 import (
 	"fmt"
 	"slices"
+	"time"
 
+	"github.com/vertica/vertica-kubernetes/pkg/cron"
 	vmeta "github.com/vertica/vertica-kubernetes/pkg/meta"
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -134,11 +136,16 @@ func (v *VerticaAutoscaler) validateSpec() field.ErrorList {
 	allErrs = v.validateScaledObjectMetric(allErrs)
 	allErrs = v.validateHPA(allErrs)
 	allErrs = v.validatePrometheusAuthModes(allErrs)
+	allErrs = v.validateVerticaMetrics(allErrs)
 	allErrs = v.validateScaleInThreshold(allErrs)
 	allErrs = v.validateHPAReplicas(allErrs)
 	allErrs = v.validateScaledObjectReplicas(allErrs)
 	allErrs = v.validateMetricsName(allErrs)
 	allErrs = v.validatePausingScalingAnnotations(allErrs)
+	allErrs = v.validateSchedule(allErrs)
+	allErrs = v.validatePredictive(allErrs)
+	allErrs = v.validateScalingPolicy(allErrs)
+	allErrs = v.validateScaleInSafety(allErrs)
 	return allErrs
 }
 
@@ -206,13 +213,22 @@ func (v *VerticaAutoscaler) validateSubclusterTemplate(allErrs field.ErrorList)
 // validateCustomAutoscaler will check if the CustomAutoscaler field is valid
 func (v *VerticaAutoscaler) validateCustomAutoscaler(allErrs field.ErrorList) field.ErrorList {
 	pathPrefix := field.NewPath("spec").Child("customAutoscaler")
-	validTypes := []string{HPA, ScaledObject, ""}
+	validTypes := []string{HPA, ScaledObject, Schedule, ""}
 	// validate type
 	if v.Spec.CustomAutoscaler != nil && !slices.Contains(validTypes, v.Spec.CustomAutoscaler.Type) {
 		err := field.Invalid(pathPrefix.Child("type"),
 			v.Spec.CustomAutoscaler.Type,
-			fmt.Sprintf("Type must be one of '%s', '%s' or empty.",
-				HPA, ScaledObject),
+			fmt.Sprintf("Type must be one of '%s', '%s', '%s' or empty.",
+				HPA, ScaledObject, Schedule),
+		)
+		allErrs = append(allErrs, err)
+	}
+
+	// customAutoscaler.Schedule must be set if customAutoscaler.type is Schedule
+	if v.IsScheduleType() && !v.IsScheduleEnabled() {
+		err := field.Invalid(pathPrefix.Child("type"),
+			v.Spec.CustomAutoscaler.Type,
+			fmt.Sprintf("customAutoscaler.schedule must have at least one window if customAutoscaler.type is %s.", Schedule),
 		)
 		allErrs = append(allErrs, err)
 	}
@@ -327,7 +343,7 @@ func (v *VerticaAutoscaler) validateMetricsName(allErrs field.ErrorList) field.E
 
 // validateScaledObject will check if the ScaledObject field is valid
 func (v *VerticaAutoscaler) validateScaledObject(allErrs field.ErrorList) field.ErrorList {
-	validTriggers := []TriggerType{CPUTriggerType, MemTriggerType, PrometheusTriggerType, ""}
+	validTriggers := []TriggerType{CPUTriggerType, MemTriggerType, PrometheusTriggerType, VerticaTriggerType, ""}
 	prometheusMetricTypes := []autoscalingv2.MetricTargetType{autoscalingv2.ValueMetricType, autoscalingv2.AverageValueMetricType}
 	cpumemMetricTypes := []autoscalingv2.MetricTargetType{autoscalingv2.UtilizationMetricType, autoscalingv2.AverageValueMetricType}
 	pathPrefix := field.NewPath("spec").Child("customAutoscaler")
@@ -348,8 +364,8 @@ func (v *VerticaAutoscaler) validateScaledObject(allErrs field.ErrorList) field.
 			if !slices.Contains(validTriggers, metric.Type) {
 				err := field.Invalid(metricsPathPrefix.Index(i).Child("type"),
 					metric.Type,
-					fmt.Sprintf("Type must be one of '%s', '%s', '%s' or empty.",
-						CPUTriggerType, MemTriggerType, PrometheusTriggerType),
+					fmt.Sprintf("Type must be one of '%s', '%s', '%s', '%s' or empty.",
+						CPUTriggerType, MemTriggerType, PrometheusTriggerType, VerticaTriggerType),
 				)
 				allErrs = append(allErrs, err)
 			}
@@ -363,6 +379,16 @@ func (v *VerticaAutoscaler) validateScaledObject(allErrs field.ErrorList) field.
 				)
 				allErrs = append(allErrs, err)
 			}
+			// validate vertica type metric
+			if metric.Type == VerticaTriggerType && !slices.Contains(prometheusMetricTypes, metric.MetricType) {
+				err := field.Invalid(metricsPathPrefix.Index(i).Child("type"),
+					metric.MetricType,
+					fmt.Sprintf("When Type is set to %s "+
+						"metricType must be one of '%s', '%s'.",
+						VerticaTriggerType, autoscalingv2.ValueMetricType, autoscalingv2.AverageValueMetricType),
+				)
+				allErrs = append(allErrs, err)
+			}
 			// validate cpu/mem type metric
 			if (metric.Type == CPUTriggerType || metric.Type == MemTriggerType) && !slices.Contains(cpumemMetricTypes, metric.MetricType) {
 				err := field.Invalid(metricsPathPrefix.Index(i).Child("type"),
@@ -407,6 +433,56 @@ func (v *VerticaAutoscaler) validatePrometheusAuthModes(allErrs field.ErrorList)
 	return allErrs
 }
 
+// validateVerticaMetrics will check the metrics of type vertica. Since the
+// operator scales on them without keda, they cannot be mixed with other types.
+func (v *VerticaAutoscaler) validateVerticaMetrics(allErrs field.ErrorList) field.ErrorList {
+	if !v.IsScaledObjectEnabled() {
+		return allErrs
+	}
+	pathPrefix := field.NewPath("spec").Child("customAutoscaler").Child("scaledObject").Child("metrics")
+	metrics := v.Spec.CustomAutoscaler.ScaledObject.Metrics
+	verticaCount := 0
+	for i := range metrics {
+		if !metrics[i].IsVerticaMetric() {
+			continue
+		}
+		verticaCount++
+		vm := metrics[i].Vertica
+		if vm == nil {
+			// Already reported in validateScaledObjectMetric
+			continue
+		}
+		path := pathPrefix.Index(i).Child("vertica")
+		validMetrics := []VerticaMetricName{VerticaMetricQueueLength, VerticaMetricActiveSessions, VerticaMetricQueryLatency}
+		if !slices.Contains(validMetrics, vm.Metric) {
+			allErrs = append(allErrs, field.Invalid(path.Child("metric"), vm.Metric,
+				fmt.Sprintf("metric must be one of '%s', '%s' or '%s'.",
+					VerticaMetricQueueLength, VerticaMetricActiveSessions, VerticaMetricQueryLatency)))
+		}
+		if vm.ResourcePool != "" && vm.Metric != VerticaMetricQueueLength {
+			allErrs = append(allErrs, field.Invalid(path.Child("resourcePool"), vm.ResourcePool,
+				fmt.Sprintf("resourcePool can only be set for the %s metric.", VerticaMetricQueueLength)))
+		}
+		if vm.LookbackSeconds < 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("lookbackSeconds"), vm.LookbackSeconds,
+				"lookbackSeconds cannot be negative."))
+		}
+		if vm.Threshold <= 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("threshold"), vm.Threshold,
+				"threshold must be greater than 0."))
+		}
+		if vm.ScaleInThreshold < 0 || (vm.ScaleInThreshold > 0 && vm.ScaleInThreshold >= vm.Threshold) {
+			allErrs = append(allErrs, field.Invalid(path.Child("scaleInThreshold"), vm.ScaleInThreshold,
+				"scaleInThreshold must be between 0 and threshold."))
+		}
+	}
+	if verticaCount > 0 && verticaCount < len(metrics) {
+		allErrs = append(allErrs, field.Invalid(pathPrefix, verticaCount,
+			fmt.Sprintf("metrics of type %s cannot be mixed with other types.", VerticaTriggerType)))
+	}
+	return allErrs
+}
+
 // validateScaledObjectNil will check if the ScaledObject metric is nil
 func (v *VerticaAutoscaler) validateScaledObjectMetric(allErrs field.ErrorList) field.ErrorList {
 	if v.IsScaledObjectEnabled() {
@@ -424,6 +500,16 @@ func (v *VerticaAutoscaler) validateScaledObjectMetric(allErrs field.ErrorList)
 				allErrs = append(allErrs, err)
 			}
 
+			// metrics[].vertica must be set if metrics[].type is "vertica"
+			if metric.Type == VerticaTriggerType && metric.Vertica == nil {
+				err := field.Invalid(pathPrefix.Child("scaledObject").Child("metrics").Index(i).Child("type"),
+					v.Spec.CustomAutoscaler.ScaledObject.Metrics[i].MetricType,
+					fmt.Sprintf("When Type is set to %s, metrics[].vertica must be set.",
+						VerticaTriggerType),
+				)
+				allErrs = append(allErrs, err)
+			}
+
 			// metrics[].resource must be set if metrics[].type is "cpu" or "memory"
 			if (metric.Type == CPUTriggerType || metric.Type == MemTriggerType) && metric.Resource == nil {
 				err := field.Invalid(pathPrefix.Child("scaledObject").Child("metrics").Index(i).Child("type"),
@@ -795,3 +881,191 @@ func (v *VerticaAutoscaler) validatePausingScalingAnnotations(allErrs field.Erro
 	}
 	return allErrs
 }
+
+// validateSchedule will check that the schedule windows are valid
+func (v *VerticaAutoscaler) validateSchedule(allErrs field.ErrorList) field.ErrorList {
+	if !v.IsScheduleEnabled() {
+		return allErrs
+	}
+	sched := v.Spec.CustomAutoscaler.Schedule
+	pathPrefix := field.NewPath("spec").Child("customAutoscaler").Child("schedule")
+	if sched.TimeZone != "" {
+		if _, err := time.LoadLocation(sched.TimeZone); err != nil {
+			allErrs = append(allErrs, field.Invalid(pathPrefix.Child("timeZone"), sched.TimeZone,
+				fmt.Sprintf("timeZone is not a valid IANA time zone: %s", err)))
+		}
+	}
+	if sched.DefaultTargetSize != nil && *sched.DefaultTargetSize < 0 {
+		allErrs = append(allErrs, field.Invalid(pathPrefix.Child("defaultTargetSize"), *sched.DefaultTargetSize,
+			"defaultTargetSize cannot be negative"))
+	}
+	windowNames := map[string]struct{}{}
+	for i := range sched.Windows {
+		w := &sched.Windows[i]
+		path := pathPrefix.Child("windows").Index(i)
+		if _, exists := windowNames[w.Name]; exists || w.Name == "" {
+			allErrs = append(allErrs, field.Invalid(path.Child("name"), w.Name,
+				"window name must be set and unique"))
+		}
+		windowNames[w.Name] = struct{}{}
+		if _, err := cron.Parse(w.Start); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("start"), w.Start, err.Error()))
+		}
+		if _, err := cron.Parse(w.End); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("end"), w.End, err.Error()))
+		}
+		if (w.TargetSize > 0) == (w.SubclusterCount > 0) {
+			allErrs = append(allErrs, field.Invalid(path, w.Name,
+				"exactly one of targetSize or subclusterCount must be set to a positive value"))
+		}
+		if w.SubclusterCount > 0 && v.Spec.ScalingGranularity != SubclusterScalingGranularity {
+			allErrs = append(allErrs, field.Invalid(path.Child("subclusterCount"), w.SubclusterCount,
+				fmt.Sprintf("subclusterCount can only be used if scalingGranularity is %s", SubclusterScalingGranularity)))
+		}
+	}
+	return allErrs
+}
+
+// validatePredictive will check if the predictive policy is valid
+func (v *VerticaAutoscaler) validatePredictive(allErrs field.ErrorList) field.ErrorList {
+	if !v.IsCustomAutoScalerSet() || v.Spec.CustomAutoscaler.Predictive == nil {
+		return allErrs
+	}
+	pred := v.Spec.CustomAutoscaler.Predictive
+	pathPrefix := field.NewPath("spec").Child("customAutoscaler").Child("predictive")
+	if !v.IsHpaType() {
+		allErrs = append(allErrs, field.Invalid(pathPrefix, v.Spec.CustomAutoscaler.Type,
+			fmt.Sprintf("predictive can only be set when type is %s", HPA)))
+	}
+	if pred.LeadTimeMinutes < 0 || pred.LeadTimeMinutes > 60*24 {
+		allErrs = append(allErrs, field.Invalid(pathPrefix.Child("leadTimeMinutes"), pred.LeadTimeMinutes,
+			"leadTimeMinutes must be between 0 and 1440"))
+	}
+	if pred.MinWeeks < 0 {
+		allErrs = append(allErrs, field.Invalid(pathPrefix.Child("minWeeks"), pred.MinWeeks,
+			"minWeeks cannot be negative"))
+	}
+	if pred.SmoothingPercent < 0 || pred.SmoothingPercent > 100 {
+		allErrs = append(allErrs, field.Invalid(pathPrefix.Child("smoothingPercent"), pred.SmoothingPercent,
+			"smoothingPercent must be between 0 and 100"))
+	}
+	if pred.TimeZone != "" {
+		if _, err := time.LoadLocation(pred.TimeZone); err != nil {
+			allErrs = append(allErrs, field.Invalid(pathPrefix.Child("timeZone"), pred.TimeZone,
+				fmt.Sprintf("timeZone is not a valid IANA time zone: %s", err)))
+		}
+	}
+	return allErrs
+}
+
+// validateScalingPolicy will check if the scaling policy is valid
+func (v *VerticaAutoscaler) validateScalingPolicy(allErrs field.ErrorList) field.ErrorList {
+	if !v.IsCustomAutoScalerSet() || v.Spec.CustomAutoscaler.ScalingPolicy == nil {
+		return allErrs
+	}
+	policy := v.Spec.CustomAutoscaler.ScalingPolicy
+	pathPrefix := field.NewPath("spec").Child("customAutoscaler").Child("scalingPolicy")
+	if !v.IsHpaEnabled() {
+		allErrs = append(allErrs, field.Invalid(pathPrefix, v.Spec.CustomAutoscaler.Type,
+			fmt.Sprintf("scalingPolicy can only be set when type is %s", HPA)))
+		return allErrs
+	}
+	if v.HasScaleInThreshold() {
+		allErrs = append(allErrs, field.Invalid(pathPrefix, v.Spec.CustomAutoscaler.ScalingPolicy,
+			"scalingPolicy cannot be used with the scaleInThreshold of the hpa metrics. Use scalingPolicy.scaleIn instead"))
+	}
+	mMap := v.GetMetricMap()
+	weighted := map[string]struct{}{}
+	for i := range policy.Weights {
+		w := &policy.Weights[i]
+		path := pathPrefix.Child("weights").Index(i)
+		if _, found := mMap[w.Metric]; !found {
+			allErrs = append(allErrs, field.Invalid(path.Child("metric"), w.Metric,
+				"metric must be the name of one of the hpa metrics"))
+		}
+		if _, exists := weighted[w.Metric]; exists {
+			allErrs = append(allErrs, field.Invalid(path.Child("metric"), w.Metric,
+				"metric can only be weighted once"))
+		}
+		weighted[w.Metric] = struct{}{}
+		if w.Weight < 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("weight"), w.Weight,
+				"weight cannot be negative"))
+		}
+	}
+	if policy.ScaleIn == nil {
+		return allErrs
+	}
+	scaleIn := policy.ScaleIn
+	scaleInPath := pathPrefix.Child("scaleIn")
+	if scaleIn.Operator != "" && scaleIn.Operator != ScaleInOperatorAnd && scaleIn.Operator != ScaleInOperatorOr {
+		allErrs = append(allErrs, field.Invalid(scaleInPath.Child("operator"), scaleIn.Operator,
+			fmt.Sprintf("operator must be one of %s or %s", ScaleInOperatorAnd, ScaleInOperatorOr)))
+	}
+	if scaleIn.DurationSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(scaleInPath.Child("durationSeconds"), scaleIn.DurationSeconds,
+			"durationSeconds cannot be negative"))
+	}
+	if len(scaleIn.Conditions) == 0 {
+		allErrs = append(allErrs, field.Invalid(scaleInPath.Child("conditions"), scaleIn.Conditions,
+			"conditions must contain at least one element"))
+	}
+	conditioned := map[string]struct{}{}
+	for i := range scaleIn.Conditions {
+		cond := &scaleIn.Conditions[i]
+		path := scaleInPath.Child("conditions").Index(i)
+		if _, exists := conditioned[cond.Metric]; exists {
+			allErrs = append(allErrs, field.Invalid(path.Child("metric"), cond.Metric,
+				"metric can only have one condition"))
+		}
+		conditioned[cond.Metric] = struct{}{}
+		md, found := mMap[cond.Metric]
+		if !found {
+			allErrs = append(allErrs, field.Invalid(path.Child("metric"), cond.Metric,
+				"metric must be the name of one of the hpa metrics"))
+			continue
+		}
+		if !isMetricTargetValueSet(&cond.Threshold) {
+			allErrs = append(allErrs, field.Invalid(path.Child("threshold"), cond.Threshold,
+				fmt.Sprintf("threshold must set the value for its type %s", cond.Threshold.Type)))
+		}
+		if mt := GetMetricTarget(&md.Metric); mt != nil && mt.Type != cond.Threshold.Type {
+			allErrs = append(allErrs, field.Invalid(path.Child("threshold").Child("type"), cond.Threshold.Type,
+				fmt.Sprintf("threshold type %s must be of the same type as the target of the metric %s",
+					cond.Threshold.Type, mt.Type)))
+		}
+	}
+	return allErrs
+}
+
+// isMetricTargetValueSet returns true if the value that goes with the type
+// of the metric target is set
+func isMetricTargetValueSet(mt *autoscalingv2.MetricTarget) bool {
+	switch mt.Type {
+	case autoscalingv2.UtilizationMetricType:
+		return mt.AverageUtilization != nil
+	case autoscalingv2.ValueMetricType:
+		return mt.Value != nil
+	case autoscalingv2.AverageValueMetricType:
+		return mt.AverageValue != nil
+	}
+	return false
+}
+
+// validateScaleInSafety will check if the scale in safety settings are valid
+func (v *VerticaAutoscaler) validateScaleInSafety(allErrs field.ErrorList) field.ErrorList {
+	safety := v.Spec.ScaleInSafety
+	if safety == nil {
+		return allErrs
+	}
+	pathPrefix := field.NewPath("spec").Child("scaleInSafety")
+	if v.Spec.ScalingGranularity != SubclusterScalingGranularity {
+		allErrs = append(allErrs, field.Invalid(pathPrefix, v.Spec.ScalingGranularity,
+			fmt.Sprintf("scaleInSafety can only be set when scalingGranularity is %s", SubclusterScalingGranularity)))
+	}
+	if safety.QueryGracePeriodSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(pathPrefix.Child("queryGracePeriodSeconds"), safety.QueryGracePeriodSeconds,
+			"queryGracePeriodSeconds cannot be negative"))
+	}
+	return allErrs
+}
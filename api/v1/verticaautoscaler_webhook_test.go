@@ -20,6 +20,7 @@ import (
 	. "github.com/onsi/gomega"
 	vmeta "github.com/vertica/vertica-kubernetes/pkg/meta"
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 var _ = Describe("verticaautoscaler_webhook", func() {
@@ -489,4 +490,192 @@ var _ = Describe("verticaautoscaler_webhook", func() {
 		_, err = vas.ValidateCreate()
 		Expect(err).ShouldNot(Succeed())
 	})
+
+	It("should validate the schedule windows", func() {
+		vas := MakeVAS()
+		vas.Spec.CustomAutoscaler = &CustomAutoscalerSpec{
+			Type: Schedule,
+		}
+		_, err := vas.ValidateCreate()
+		Expect(err).ShouldNot(Succeed())
+		vas.Spec.CustomAutoscaler.Schedule = &ScheduleSpec{
+			TimeZone: "America/New_York",
+			Windows: []ScheduleWindow{
+				{Name: "business", Start: "0 8 * * 1-5", End: "0 18 * * 1-5", TargetSize: 6},
+			},
+		}
+		_, err = vas.ValidateCreate()
+		Expect(err).Should(Succeed())
+
+		vas.Spec.CustomAutoscaler.Schedule.TimeZone = "Not/AZone"
+		_, err = vas.ValidateCreate()
+		Expect(err).ShouldNot(Succeed())
+		vas.Spec.CustomAutoscaler.Schedule.TimeZone = ""
+		vas.Spec.CustomAutoscaler.Schedule.Windows[0].End = "0 25 * * *"
+		_, err = vas.ValidateCreate()
+		Expect(err).ShouldNot(Succeed())
+		vas.Spec.CustomAutoscaler.Schedule.Windows[0].End = "0 18 * * 1-5"
+		vas.Spec.CustomAutoscaler.Schedule.Windows[0].SubclusterCount = 2
+		_, err = vas.ValidateCreate()
+		Expect(err).ShouldNot(Succeed())
+		// subclusterCount is only allowed with the Subcluster granularity
+		vas.Spec.CustomAutoscaler.Schedule.Windows[0].TargetSize = 0
+		_, err = vas.ValidateCreate()
+		Expect(err).ShouldNot(Succeed())
+		vas.Spec.ScalingGranularity = SubclusterScalingGranularity
+		_, err = vas.ValidateCreate()
+		Expect(err).Should(Succeed())
+	})
+
+	It("should validate the vertica metrics", func() {
+		vas := MakeVASWithScaledObject()
+		vas.Spec.CustomAutoscaler.ScaledObject.Metrics = []ScaleTrigger{
+			{
+				Name:       "queue",
+				Type:       VerticaTriggerType,
+				MetricType: autoscalingv2.ValueMetricType,
+			},
+		}
+		_, err := vas.ValidateCreate()
+		Expect(err).ShouldNot(Succeed())
+		vas.Spec.CustomAutoscaler.ScaledObject.Metrics[0].Vertica = &VerticaMetricSpec{
+			Metric:       VerticaMetricQueueLength,
+			ResourcePool: "general",
+			Threshold:    5,
+		}
+		_, err = vas.ValidateCreate()
+		Expect(err).Should(Succeed())
+		Expect(vas.IsVerticaMetricsEnabled()).Should(BeTrue())
+		Expect(vas.IsCustomMetricsEnabled()).Should(BeFalse())
+
+		vas.Spec.CustomAutoscaler.ScaledObject.Metrics[0].Vertica.ScaleInThreshold = 5
+		_, err = vas.ValidateCreate()
+		Expect(err).ShouldNot(Succeed())
+		vas.Spec.CustomAutoscaler.ScaledObject.Metrics[0].Vertica.ScaleInThreshold = 2
+		vas.Spec.CustomAutoscaler.ScaledObject.Metrics[0].Vertica.Metric = VerticaMetricActiveSessions
+		// resourcePool is only allowed with queueLength
+		_, err = vas.ValidateCreate()
+		Expect(err).ShouldNot(Succeed())
+		vas.Spec.CustomAutoscaler.ScaledObject.Metrics[0].Vertica.ResourcePool = ""
+		_, err = vas.ValidateCreate()
+		Expect(err).Should(Succeed())
+
+		// vertica metrics cannot be mixed with other types
+		vas.Spec.CustomAutoscaler.ScaledObject.Metrics = append(vas.Spec.CustomAutoscaler.ScaledObject.Metrics, ScaleTrigger{
+			Name:       "cpu",
+			Type:       CPUTriggerType,
+			MetricType: autoscalingv2.UtilizationMetricType,
+			Resource:   &CPUMemorySpec{Threshold: 80},
+		})
+		_, err = vas.ValidateCreate()
+		Expect(err).ShouldNot(Succeed())
+		Expect(vas.IsVerticaMetricsEnabled()).Should(BeFalse())
+	})
+
+	It("should validate the predictive policy", func() {
+		vas := MakeVASWithMetrics()
+		vas.Spec.CustomAutoscaler.Predictive = &PredictiveSpec{
+			LeadTimeMinutes:  15,
+			MinWeeks:         2,
+			SmoothingPercent: 30,
+		}
+		_, err := vas.ValidateCreate()
+		Expect(err).Should(Succeed())
+		Expect(vas.IsPredictiveEnabled()).Should(BeTrue())
+
+		vas.Spec.CustomAutoscaler.Predictive.SmoothingPercent = 101
+		_, err = vas.ValidateCreate()
+		Expect(err).ShouldNot(Succeed())
+		vas.Spec.CustomAutoscaler.Predictive.SmoothingPercent = 30
+		vas.Spec.CustomAutoscaler.Predictive.TimeZone = "Not/AZone"
+		_, err = vas.ValidateCreate()
+		Expect(err).ShouldNot(Succeed())
+
+		// The profile is learned from the hpa metrics
+		vas = MakeVASWithScaledObject()
+		vas.Spec.CustomAutoscaler.Predictive = &PredictiveSpec{}
+		_, err = vas.ValidateCreate()
+		Expect(err).ShouldNot(Succeed())
+	})
+
+	It("should validate the scaling policy", func() {
+		vas := MakeVASWithMetrics()
+		cpu := int32(30)
+		vas.Spec.CustomAutoscaler.ScalingPolicy = &ScalingPolicySpec{
+			Weights: []MetricWeight{{Metric: "cpu", Weight: 2}},
+			ScaleIn: &ScaleInRule{
+				Operator: ScaleInOperatorAnd,
+				Conditions: []ScaleInCondition{
+					{
+						Metric: "cpu",
+						Threshold: autoscalingv2.MetricTarget{
+							Type:               autoscalingv2.UtilizationMetricType,
+							AverageUtilization: &cpu,
+						},
+					},
+				},
+				DurationSeconds: 600,
+			},
+		}
+		_, err := vas.ValidateCreate()
+		Expect(err).Should(Succeed())
+		Expect(vas.IsScalingPolicyEnabled()).Should(BeTrue())
+
+		// The metrics must be ones of the hpa
+		vas.Spec.CustomAutoscaler.ScalingPolicy.Weights[0].Metric = "memory"
+		_, err = vas.ValidateCreate()
+		Expect(err).ShouldNot(Succeed())
+		vas.Spec.CustomAutoscaler.ScalingPolicy.Weights[0].Metric = "cpu"
+		vas.Spec.CustomAutoscaler.ScalingPolicy.Weights[0].Weight = -1
+		_, err = vas.ValidateCreate()
+		Expect(err).ShouldNot(Succeed())
+		vas.Spec.CustomAutoscaler.ScalingPolicy.Weights[0].Weight = 2
+
+		// The threshold must be of the same type as the target
+		cond := &vas.Spec.CustomAutoscaler.ScalingPolicy.ScaleIn.Conditions[0]
+		cond.Threshold = autoscalingv2.MetricTarget{
+			Type:  autoscalingv2.ValueMetricType,
+			Value: resource.NewQuantity(1, resource.DecimalSI),
+		}
+		_, err = vas.ValidateCreate()
+		Expect(err).ShouldNot(Succeed())
+		cond.Threshold = autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType}
+		_, err = vas.ValidateCreate()
+		Expect(err).ShouldNot(Succeed())
+		cond.Threshold.AverageUtilization = &cpu
+
+		// It replaces the scale in threshold of the metrics
+		vas.Spec.CustomAutoscaler.Hpa.Metrics[0].ScaleInThreshold = &autoscalingv2.MetricTarget{
+			Type:               autoscalingv2.UtilizationMetricType,
+			AverageUtilization: &cpu,
+		}
+		_, err = vas.ValidateCreate()
+		Expect(err).ShouldNot(Succeed())
+
+		vas = MakeVASWithScaledObject()
+		vas.Spec.CustomAutoscaler.ScalingPolicy = &ScalingPolicySpec{}
+		_, err = vas.ValidateCreate()
+		Expect(err).ShouldNot(Succeed())
+	})
+
+	It("should validate the scale in safety settings", func() {
+		vas := MakeVAS()
+		vas.Spec.ScalingGranularity = SubclusterScalingGranularity
+		vas.Spec.ScaleInSafety = &ScaleInSafetySpec{
+			QueryGracePeriodSeconds: 60,
+			CancelQueries:           true,
+		}
+		_, err := vas.ValidateCreate()
+		Expect(err).Should(Succeed())
+
+		vas.Spec.ScaleInSafety.QueryGracePeriodSeconds = -1
+		_, err = vas.ValidateCreate()
+		Expect(err).ShouldNot(Succeed())
+		vas.Spec.ScaleInSafety.QueryGracePeriodSeconds = 0
+
+		// Pods cannot be picked individually
+		vas.Spec.ScalingGranularity = PodScalingGranularity
+		_, err = vas.ValidateCreate()
+		Expect(err).ShouldNot(Succeed())
+	})
 })
@@ -94,8 +94,10 @@ type VerticaDBSpec struct {
 	// is set for the database. If this is set, it is up the user to create this
 	// secret before deployment. The secret must have a key named password. To
 	// store this secret outside of Kubernetes, you can use a secret path
-	// reference prefix, such as gsm://. Everything after the prefix is the name
-	// of the secret in the service you are storing.
+	// reference prefix, such as gsm://, awssm://, vault:// (HashiCorp Vault
+	// KV v2, vault://<mount>/<path>) or azkv:// (Azure Key Vault,
+	// azkv://<vault>/<name>). Everything after the prefix is the name of the
+	// secret in the service you are storing.
 	PasswordSecret string `json:"passwordSecret,omitempty"`
 
 	// +kubebuilder:validation:Optional
@@ -107,7 +109,10 @@ type VerticaDBSpec struct {
 	// from the secret -- if there are multiple licenses it will pick one by
 	// selecting the first one alphabetically.  The user is responsible for
 	// installing any additional licenses or if the license was added to the
-	// secret after DB creation.
+	// secret after DB creation. To store this secret in HashiCorp Vault or
+	// Azure Key Vault, you can use the vault:// or azkv:// secret path
+	// reference prefix. The operator copies it to a Kubernetes secret that is
+	// mounted in the pods.
 	LicenseSecret string `json:"licenseSecret,omitempty"`
 
 	// +kubebuilder:validation:Optional
@@ -287,6 +292,29 @@ type VerticaDBSpec struct {
 	// The cipher suites must match the TLS version. This field will be empty when tls is not enabled and configured
 	DBTLSConfig *DBTLSConfig `json:"dbTlsConfig,omitempty"`
 
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	// +kubebuilder:validation:Optional
+	// Database configuration parameters that the operator keeps set in the
+	// database. The key is the name of the configuration parameter. A
+	// parameter is set at the database level, unless a node or subcluster is
+	// given. The operator only changes a parameter when its current value
+	// differs from the one in this map. Parameters that need a restart before
+	// the new value takes effect are listed in
+	// status.configurationParametersPendingRestart. The parameters the
+	// operator has set are recorded in
+	// status.appliedConfigurationParameters; removing a parameter from this
+	// map, or changing its scope, clears it in the database at the levels it
+	// is no longer set at. This is only supported for vclusterops deployments.
+	ConfigurationParameters map[string]ConfigurationParameter `json:"configurationParameters,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	// +kubebuilder:validation:Optional
+	// Settings for the health watchdog that runs in the node management agent
+	// (NMA). The operator keeps the watchdog parameters and policy in sync
+	// with this section. It can also cancel the runaway queries that the
+	// watchdog finds. This is only supported for vclusterops deployments.
+	HealthWatchdog *HealthWatchdogSpec `json:"healthWatchdog,omitempty"`
+
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret","urn:alm:descriptor:com.tectonic.ui:advanced"}
 	// +kubebuilder:default:=""
 	// +kubebuilder:validation:Optional
@@ -311,6 +339,23 @@ type VerticaDBSpec struct {
 	// Identifies Client-Server TLS configuration
 	ClientServerTLS *TLSConfigSpec `json:"clientServerTLS,omitempty"`
 
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	// +kubebuilder:validation:Optional
+	// Certificate authentication for database users. The operator adds the
+	// trusted client CA to the server TLS configuration and keeps the TLS
+	// authentication methods in the database in sync with this section. This
+	// is only supported for vclusterops deployments with client-server TLS
+	// enabled.
+	ClientTLSAuth *ClientTLSAuthSpec `json:"clientTLSAuth,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	// +kubebuilder:validation:Optional
+	// A policy to rotate the password of the database's superuser. When set,
+	// the operator generates a new password on the given interval, changes
+	// it in the database, stores it in a new secret and updates
+	// passwordSecret to point to that secret.
+	PasswordRotation *PasswordRotationPolicy `json:"passwordRotation,omitempty"`
+
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:hidden"
 	// +kubebuilder:validation:Optional
 	// Allows tuning of the Vertica pods readiness probe. Each of the values
@@ -956,6 +1001,129 @@ type DBTLSConfig struct {
 	CipherSuites string `json:"cipherSuites,omitempty"`
 }
 
+// ConfigurationParameter is the desired value of a database configuration
+// parameter along with the scope it is set at.
+type ConfigurationParameter struct {
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Required
+	// The value to set the configuration parameter to.
+	Value string `json:"value"`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// The name of a subcluster. When set, the parameter is set at the node
+	// level for each node in the subcluster rather than at the database
+	// level. This cannot be combined with node.
+	Subcluster string `json:"subcluster,omitempty"`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// The Vertica node name (e.g. v_vertdb_node0001). When set, the parameter
+	// is only set at the node level for this node. It must be a node of the
+	// database. This cannot be combined with subcluster.
+	Node string `json:"node,omitempty"`
+}
+
+// AppliedConfigurationParameter is a configuration parameter that the operator
+// set in the database
+type AppliedConfigurationParameter struct {
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// The name of the configuration parameter, as given in
+	// spec.configurationParameters.
+	Name string `json:"name"`
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The levels the parameter was set at. An empty level is the database
+	// level and NODE <vnode> is the node level for a single node.
+	Levels []string `json:"levels,omitempty"`
+}
+
+// HealthWatchdogSpec holds the settings of the health watchdog
+type HealthWatchdogSpec struct {
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// Health watchdog configuration parameters, such as the runtime limit of
+	// a query. The key is the name of the parameter. A parameter that is
+	// removed from this map is cleared in the watchdog.
+	Parameters map[string]string `json:"parameters,omitempty"`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// The health watchdog policy settings. The operator sets the policy
+	// whenever these settings change.
+	Policy map[string]string `json:"policy,omitempty"`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:None","urn:alm:descriptor:com.tectonic.ui:select:Report","urn:alm:descriptor:com.tectonic.ui:select:Cancel"}
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=None
+	// +kubebuilder:validation:Enum=None;Report;Cancel
+	// What the operator does with the queries that the watchdog flags when it
+	// checks the cluster health. Valid values are:
+	// - None: the operator doesn't check the watchdog.
+	// - Report: the operator periodically checks the watchdog and reports
+	// each flagged query with an event and in the operator metrics.
+	// - Cancel: like Report, but the operator also cancels every flagged
+	// query. This is only safe if the watchdog parameters limit what it flags
+	// to queries that should never run.
+	RunawayQueryAction RunawayQueryActionType `json:"runawayQueryAction,omitempty"`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=60
+	// +kubebuilder:validation:Minimum:=10
+	// The number of seconds between two checks for runaway queries. This is
+	// not used when runawayQueryAction is None.
+	CheckIntervalSeconds int `json:"checkIntervalSeconds,omitempty"`
+}
+
+type RunawayQueryActionType string
+
+const (
+	RunawayQueryActionNone   RunawayQueryActionType = "None"
+	RunawayQueryActionReport RunawayQueryActionType = "Report"
+	RunawayQueryActionCancel RunawayQueryActionType = "Cancel"
+)
+
+// ClientTLSAuthSpec declares how database users log in with a client
+// certificate
+type ClientTLSAuthSpec struct {
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:io.kubernetes:Secret"
+	// +kubebuilder:validation:Required
+	// The name of a secret with the CA certificates that sign the client
+	// certificates. The certificates must be in the ca.crt key of the secret.
+	TrustedCASecret string `json:"trustedCASecret"`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// The TLS authentication methods to create in the database. A method that
+	// is removed from this list is dropped from the database.
+	Methods []TLSAuthMethod `json:"methods,omitempty"`
+}
+
+// TLSAuthMethod is a TLS authentication method bound to users and roles
+type TLSAuthMethod struct {
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Required
+	// The name of the authentication record in the database
+	Name string `json:"name"`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:="0.0.0.0/0"
+	// The client address range, in CIDR notation, that the method applies to
+	Host string `json:"host,omitempty"`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// The users the method is granted to. An entry can be a user name or a
+	// glob pattern, such as svc-*. A pattern is matched against the
+	// VerticaUser objects of this database.
+	Users []string `json:"users,omitempty"`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// The roles the method is granted to. An entry can be a role name or a
+	// glob pattern. A pattern is matched against the VerticaRole objects of
+	// this database.
+	Roles []string `json:"roles,omitempty"`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// The priority of the method. When several methods match a client, the
+	// one with the highest priority is used. Zero keeps the database default.
+	Priority int `json:"priority,omitempty"`
+}
+
 // Used for storing TLS configuration for either httpsNMATLS or ClientServerTLS
 type TLSConfigSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret","urn:alm:descriptor:com.tectonic.ui:advanced"}
@@ -1020,8 +1188,71 @@ type TLSAutoRotate struct {
 	// When we reach the end of the list, this will determine whether to loop back to the first element of the list
 	// or to finish (giving a warning). Default is false, meaning finish auto-rotate.
 	RestartAtEnd bool `json:"restartAtEnd,omitempty"`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// When set, rotation is driven by the expiry of the certificate in use rather
+	// than by a list of secrets. Once the certificate has reached a set fraction
+	// of its lifetime, the operator issues a replacement and rotates to it. This
+	// cannot be combined with "autoRotate.secrets".
+	Issue *TLSCertIssue `json:"issue,omitempty"`
+}
+
+// TLSCertIssue describes how the operator issues replacement certificates
+// for expiry-driven auto-rotation. Exactly one of caSecret or issuerRef must be set.
+type TLSCertIssue struct {
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:number"}
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=67
+	// +kubebuilder:validation:Minimum:=1
+	// +kubebuilder:validation:Maximum:=99
+	// The percentage of the certificate's lifetime, measured from its NotBefore
+	// time, at which a replacement is issued. Default is 67, which renews a one
+	// year certificate roughly four months before it expires.
+	RenewAtLifetimePercent int `json:"renewAtLifetimePercent,omitempty"`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:io.kubernetes:Secret"}
+	// +kubebuilder:validation:Optional
+	// The name of a secret holding the CA that signs the replacement
+	// certificates. It must have the keys tls.key and tls.crt, with the key in
+	// PKCS#1 format. The CA certificate is stored as ca.crt in every secret the
+	// operator issues.
+	CASecret string `json:"caSecret,omitempty"`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// A cert-manager issuer that signs the replacement certificates. The
+	// operator creates a cert-manager Certificate for each replacement and
+	// removes it once the secret has been rotated in. The issuer must populate
+	// ca.crt in the secret it writes, as the CA and Vault issuers do.
+	IssuerRef *CertManagerIssuerRef `json:"issuerRef,omitempty"`
 }
 
+// CertManagerIssuerRef refers to a cert-manager Issuer or ClusterIssuer
+type CertManagerIssuerRef struct {
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Required
+	// The name of the issuer
+	Name string `json:"name"`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=Issuer
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	// The kind of the issuer. An Issuer must be in the same namespace as the VerticaDB.
+	Kind string `json:"kind,omitempty"`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:advanced"}
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=cert-manager.io
+	// The API group of the issuer. This only needs to be changed for external issuers.
+	Group string `json:"group,omitempty"`
+}
+
+const (
+	// The default value for autoRotate.issue.renewAtLifetimePercent
+	DefaultRenewAtLifetimePercent = 67
+	// The values and default for autoRotate.issue.issuerRef
+	IssuerKind        = "Issuer"
+	ClusterIssuerKind = "ClusterIssuer"
+	CertManagerGroup  = "cert-manager.io"
+)
+
 // VerticaDBStatus defines the observed state of VerticaDB
 type VerticaDBStatus struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=status
@@ -1088,6 +1319,43 @@ type VerticaDBStatus struct {
 	// The DB level TLS config
 	DBTLSConfig *DBTLSConfig `json:"dbTlsConfig,omitempty"`
 
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The names of the configuration parameters from
+	// spec.configurationParameters whose new value only takes effect after the
+	// database is restarted.
+	ConfigurationParametersPendingRestart []string `json:"configurationParametersPendingRestart,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The configuration parameters from spec.configurationParameters that the
+	// operator has set in the database, along with the levels they were set
+	// at. It is used to clear a parameter once it is no longer in the spec.
+	AppliedConfigurationParameters []AppliedConfigurationParameter `json:"appliedConfigurationParameters,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The state of the health watchdog as set by the operator
+	HealthWatchdog *HealthWatchdogStatus `json:"healthWatchdog,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The client certificate authentication as set in the database by the
+	// operator
+	ClientTLSAuth *ClientTLSAuthStatus `json:"clientTLSAuth,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The state of the superuser password rotation
+	PasswordRotation *PasswordRotationStatus `json:"passwordRotation,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The next time a certificate in a TLS secret of the VerticaDB crosses one
+	// of the cert-expiry-warning-days thresholds or expires. The operator
+	// checks the certificates again at that time.
+	CertExpiryNextCheckTime *metav1.Time `json:"certExpiryNextCheckTime,omitempty"`
+
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	// +optional
 	// Current retry attempt for HTTPS polling after failed cert rotation
@@ -1102,6 +1370,46 @@ type VerticaDBStatus struct {
 	// The names of secrets that have been observed by the operator.
 	// This is used to trigger a rolling restart of the pods when these resources change.
 	ObservedSecrets []string `json:"observedSecrets,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The actions the operator would take to reconcile the current spec. This
+	// is only populated while the vertica.com/plan annotation is set. In that
+	// mode, the operator computes the plan but doesn't act on it.
+	Plan *ReconcilePlan `json:"plan,omitempty"`
+}
+
+// ReconcilePlan is the ordered list of actions the operator would take
+type ReconcilePlan struct {
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// The time the plan was computed
+	GeneratedTime metav1.Time `json:"generatedTime"`
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// The generation of the VerticaDB the plan was computed for
+	ObservedGeneration int64 `json:"observedGeneration"`
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The actions, in the order they would run. An empty list means the
+	// database already matches the spec.
+	Actions []PlannedAction `json:"actions,omitempty"`
+}
+
+// PlannedAction is a single action the operator would take
+type PlannedAction struct {
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// The reconciler that would take the action, such as DBAddNode
+	Reconciler string `json:"reconciler"`
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// A description of the action
+	Description string `json:"description"`
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The subcluster the action applies to, if any
+	Subcluster string `json:"subcluster,omitempty"`
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The pods the action applies to, if any
+	Pods []string `json:"pods,omitempty"`
 }
 
 const (
@@ -1133,6 +1441,135 @@ type TLSConfigStatus struct {
 	// +optional
 	// When an auto-rotation fails, this field contains the name of the secret that failed to be applied.
 	AutoRotateFailedSecret string `json:"autoRotateFailedSecret,omitempty"`
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The NotAfter time of the certificate in use. This is only set when
+	// autoRotate.issue is used.
+	CertExpiry *metav1.Time `json:"certExpiry,omitempty"`
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The time at which the operator will issue a replacement for the
+	// certificate in use. This is only set when autoRotate.issue is used.
+	NextRotation *metav1.Time `json:"nextRotation,omitempty"`
+}
+
+// HealthWatchdogStatus is the state of the health watchdog
+type HealthWatchdogStatus struct {
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The watchdog parameters that the operator last set
+	Parameters map[string]string `json:"parameters,omitempty"`
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The watchdog policy settings that the operator last set
+	Policy map[string]string `json:"policy,omitempty"`
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The last time the operator checked for runaway queries
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The number of runaway queries that the watchdog flagged at the last
+	// check
+	RunawayQueries int `json:"runawayQueries"`
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The number of runaway queries that the operator cancelled
+	CancelledQueries int `json:"cancelledQueries"`
+}
+
+// ClientTLSAuthStatus is the client certificate authentication set in the
+// database
+type ClientTLSAuthStatus struct {
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The secret whose CA certificates were last added to the server TLS
+	// configuration
+	TrustedCASecret string `json:"trustedCASecret,omitempty"`
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// A digest of the server TLS configuration that was last set with the
+	// trusted CA certificates. It covers the client-server TLS secret and
+	// mode in use along with the certificates, so the trusted CA is added
+	// again when any of them change, such as after a certificate rotation.
+	TrustedCADigest string `json:"trustedCADigest,omitempty"`
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The TLS authentication methods that the operator created
+	Methods []TLSAuthMethodStatus `json:"methods,omitempty"`
+}
+
+// PasswordRotationPolicy defines how often the superuser password is rotated
+// and where the new passwords are stored
+type PasswordRotationPolicy struct {
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=90
+	// +kubebuilder:validation:Minimum:=1
+	// The number of days between two rotations of the superuser password.
+	IntervalDays int `json:"intervalDays,omitempty"`
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +kubebuilder:validation:Required
+	// The base name of the Kubernetes secrets that the operator creates to
+	// store the rotated passwords. Each rotation writes a new secret named
+	// <targetSecret>-<rotation number>, with the password in the key named
+	// password. Only the secrets of the last two rotations are kept. The
+	// secret set by the user in passwordSecret is never deleted. The secret
+	// in use is in status.passwordRotation.currentSecret and, while this
+	// policy is set, takes precedence over passwordSecret. Remove the policy
+	// to set the password through passwordSecret again.
+	TargetSecret string `json:"targetSecret"`
+}
+
+// PasswordRotationStatus is the state of the superuser password rotation
+type PasswordRotationStatus struct {
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The time the operator found the rotation policy set. The first
+	// rotation is due one interval after it.
+	EnabledTime *metav1.Time `json:"enabledTime,omitempty"`
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The last time the superuser password was rotated
+	LastRotationTime *metav1.Time `json:"lastRotationTime,omitempty"`
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The number of times the operator rotated the superuser password
+	Rotations int `json:"rotations"`
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The secret with the password of a rotation that is in progress. It is
+	// set before the password is changed in the database so that an
+	// interrupted rotation can be completed with either password.
+	PendingSecret string `json:"pendingSecret,omitempty"`
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The secret with the password of the last completed rotation, which is
+	// the one in effect in the database
+	CurrentSecret string `json:"currentSecret,omitempty"`
+}
+
+// TLSAuthMethodStatus is a TLS authentication method created by the operator
+type TLSAuthMethodStatus struct {
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// The name of the authentication record
+	Name string `json:"name"`
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// The client address range the method applies to
+	Host string `json:"host"`
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The priority of the method
+	Priority int `json:"priority,omitempty"`
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The users and roles, named in the spec, that the method is granted to
+	Grantees []string `json:"grantees,omitempty"`
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The users and roles, matched by a pattern in the spec, that the method
+	// is granted to
+	MatchedGrantees []string `json:"matchedGrantees,omitempty"`
 }
 
 type RestorePointInfo struct {
@@ -1204,6 +1641,20 @@ const (
 	TLSCertRollbackNeeded = "TLSCertRollbackNeeded"
 	// TLSCertRollbackInProgress indicates that user has triggered TLS rollback
 	TLSCertRollbackInProgress = "TLSCertRollbackInProgress"
+	// RebalanceShardsInProgress indicates a shard rebalance is running. The
+	// message of the condition reports how far along the rebalance is.
+	RebalanceShardsInProgress = "RebalanceShardsInProgress"
+	// CertificateExpiringSoon indicates a certificate in one of the TLS
+	// secrets is within the expiry warning threshold, or has already expired.
+	// The message lists the certificates.
+	CertificateExpiringSoon = "CertificateExpiringSoon"
+)
+
+const (
+	// Reasons used with the CertificateExpiringSoon condition
+	CertificateExpiringReason = "CertificateExpiring"
+	CertificateExpiredReason  = "CertificateExpired"
+	CertificatesValidReason   = "CertificatesValid"
 )
 
 const (
@@ -1218,6 +1669,14 @@ const (
 	RollbackAfterNMACertRotationReason = "NMACertRotationFailed"
 )
 
+const (
+	// Reasons used with the RebalanceShardsInProgress condition
+	RebalanceShardsRunningReason   = "Rebalancing"
+	RebalanceShardsCompletedReason = "Completed"
+	RebalanceShardsFailedReason    = "Failed"
+	RebalanceShardsTimedOutReason  = "TimedOut"
+)
+
 const (
 	// list of reasons for conditions' transitions
 	UnknownReason = "UnKnown"
@@ -19,7 +19,10 @@ package v1
 import (
 	"fmt"
 	"maps"
+	"net"
+	"path"
 	"reflect"
+	"regexp"
 	"slices"
 	"strings"
 
@@ -31,6 +34,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -518,6 +522,37 @@ func (v *VerticaDB) checkValidSubclusterTypeTransition(oldObj *VerticaDB, allErr
 	return allErrs
 }
 
+// validatePasswordRotation checks the superuser password rotation policy
+func (v *VerticaDB) validatePasswordRotation(allErrs field.ErrorList) field.ErrorList {
+	if v.Spec.PasswordRotation == nil {
+		return allErrs
+	}
+	pathPrefix := field.NewPath("spec").Child("passwordRotation")
+	if v.Spec.PasswordRotation.IntervalDays < 0 {
+		err := field.Invalid(pathPrefix.Child("intervalDays"), v.Spec.PasswordRotation.IntervalDays,
+			"intervalDays cannot be negative")
+		allErrs = append(allErrs, err)
+	}
+	if v.Spec.PasswordRotation.TargetSecret == "" {
+		err := field.Required(pathPrefix.Child("targetSecret"),
+			"targetSecret must be set to rotate the superuser password")
+		allErrs = append(allErrs, err)
+	} else if errs := validation.IsDNS1123Subdomain(v.GetPasswordRotationSecretName(0)); len(errs) > 0 {
+		// The rotated passwords are always stored in Kubernetes secrets
+		err := field.Invalid(pathPrefix.Child("targetSecret"), v.Spec.PasswordRotation.TargetSecret,
+			fmt.Sprintf("targetSecret must be a valid Kubernetes secret name: %s", strings.Join(errs, ", ")))
+		allErrs = append(allErrs, err)
+	}
+	// The password of a sandbox cannot be changed, so a rotation would leave
+	// the sandboxes with the old password.
+	if len(v.Spec.Sandboxes) > 0 {
+		err := field.Forbidden(pathPrefix,
+			"passwordRotation cannot be set when the vdb has sandboxes")
+		allErrs = append(allErrs, err)
+	}
+	return allErrs
+}
+
 // checkPasswordSecretUpdateInShutdownSandbox checks if password secrets are being updated in shutdown sandboxes
 func (v *VerticaDB) checkPasswordSecretUpdateWithSandbox(oldObj *VerticaDB, allErrs field.ErrorList) field.ErrorList {
 	// if vdb does not have any sandboxes, skip this check
@@ -552,6 +587,10 @@ func (v *VerticaDB) validateVerticaDBSpec() field.ErrorList {
 	allErrs = v.validateAdditionalBucketsTypes(allErrs)
 	allErrs = v.validateS3ServerSideEncryption(allErrs)
 	allErrs = v.validateAdditionalConfigParms(allErrs)
+	allErrs = v.validateConfigurationParameters(allErrs)
+	allErrs = v.validateHealthWatchdog(allErrs)
+	allErrs = v.validateClientTLSAuth(allErrs)
+	allErrs = v.validatePasswordRotation(allErrs)
 	allErrs = v.validateCustomLabels(allErrs)
 	allErrs = v.validateIncludeUIDInPathAnnotation(allErrs)
 	allErrs = v.validateEndpoint(allErrs)
@@ -862,6 +901,192 @@ func (v *VerticaDB) validateAdditionalConfigParms(allErrs field.ErrorList) field
 	return allErrs
 }
 
+// validateConfigurationParameters checks the configuration parameters that
+// the operator will set in the database
+func (v *VerticaDB) validateConfigurationParameters(allErrs field.ErrorList) field.ErrorList {
+	if len(v.Spec.ConfigurationParameters) == 0 {
+		return allErrs
+	}
+	pathPrefix := field.NewPath("spec").Child("configurationParameters")
+	if !vmeta.UseVClusterOps(v.Annotations) {
+		err := field.Forbidden(pathPrefix,
+			"configurationParameters is only supported for vclusterops deployments")
+		allErrs = append(allErrs, err)
+	}
+	scMap := v.GenSubclusterMap()
+	// configuration parameter names are case insensitive
+	seen := map[string]string{}
+	for _, name := range slices.Sorted(maps.Keys(v.Spec.ConfigurationParameters)) {
+		param := v.Spec.ConfigurationParameters[name]
+		paramPath := pathPrefix.Key(name)
+		if !isValidConfigParameterName(name) {
+			err := field.Invalid(paramPath, name,
+				"configuration parameter name must start with a letter and only contain letters, digits and underscores")
+			allErrs = append(allErrs, err)
+		}
+		if dup, ok := seen[strings.ToLower(name)]; ok {
+			err := field.Duplicate(paramPath, fmt.Sprintf("%s is the same parameter as %s", name, dup))
+			allErrs = append(allErrs, err)
+		}
+		seen[strings.ToLower(name)] = name
+		if param.Node != "" && param.Subcluster != "" {
+			err := field.Invalid(paramPath, param,
+				"only one of node or subcluster can be set")
+			allErrs = append(allErrs, err)
+		}
+		if _, ok := scMap[param.Subcluster]; param.Subcluster != "" && !ok {
+			err := field.Invalid(paramPath.Child("subcluster"), param.Subcluster,
+				"subcluster does not exist in spec.subclusters")
+			allErrs = append(allErrs, err)
+		}
+		if param.Node != "" && !v.isKnownVNodeName(param.Node) {
+			err := field.Invalid(paramPath.Child("node"), param.Node,
+				"node does not match a node of the database")
+			allErrs = append(allErrs, err)
+		}
+	}
+	return allErrs
+}
+
+// isKnownVNodeName returns true if the given name is the vnode name of a node
+// in the database. Once the nodes are in the status, the name must be one of
+// them. Before that, it must at least be a vnode name for this database.
+func (v *VerticaDB) isKnownVNodeName(name string) bool {
+	found := false
+	for i := range v.Status.Subclusters {
+		for j := range v.Status.Subclusters[i].Detail {
+			vnodeName := v.Status.Subclusters[i].Detail[j].VNodeName
+			if vnodeName == "" {
+				continue
+			}
+			found = true
+			if strings.EqualFold(vnodeName, name) {
+				return true
+			}
+		}
+	}
+	if found {
+		return false
+	}
+	r := regexp.MustCompile(fmt.Sprintf(`(?i)^v_%s_node[0-9]{4}$`, regexp.QuoteMeta(v.Spec.DBName)))
+	return r.MatchString(name)
+}
+
+// validateHealthWatchdog checks the settings of the health watchdog
+func (v *VerticaDB) validateHealthWatchdog(allErrs field.ErrorList) field.ErrorList {
+	if v.Spec.HealthWatchdog == nil {
+		return allErrs
+	}
+	pathPrefix := field.NewPath("spec").Child("healthWatchdog")
+	if !vmeta.UseVClusterOps(v.Annotations) {
+		err := field.Forbidden(pathPrefix,
+			"healthWatchdog is only supported for vclusterops deployments")
+		allErrs = append(allErrs, err)
+	}
+	for _, name := range slices.Sorted(maps.Keys(v.Spec.HealthWatchdog.Parameters)) {
+		if !isValidConfigParameterName(name) {
+			err := field.Invalid(pathPrefix.Child("parameters").Key(name), name,
+				"health watchdog parameter name must start with a letter and only contain letters, digits and underscores")
+			allErrs = append(allErrs, err)
+		}
+		if v.Spec.HealthWatchdog.Parameters[name] == "" {
+			err := field.Invalid(pathPrefix.Child("parameters").Key(name), "",
+				"health watchdog parameter value cannot be empty")
+			allErrs = append(allErrs, err)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(v.Spec.HealthWatchdog.Policy)) {
+		if name == "" {
+			err := field.Invalid(pathPrefix.Child("policy"), name,
+				"health watchdog policy setting name cannot be empty")
+			allErrs = append(allErrs, err)
+		}
+	}
+	switch v.Spec.HealthWatchdog.RunawayQueryAction {
+	case "", RunawayQueryActionNone, RunawayQueryActionReport, RunawayQueryActionCancel:
+	default:
+		err := field.Invalid(pathPrefix.Child("runawayQueryAction"), v.Spec.HealthWatchdog.RunawayQueryAction,
+			fmt.Sprintf("runawayQueryAction must be one of %s, %s or %s",
+				RunawayQueryActionNone, RunawayQueryActionReport, RunawayQueryActionCancel))
+		allErrs = append(allErrs, err)
+	}
+	if v.Spec.HealthWatchdog.CheckIntervalSeconds < 0 {
+		err := field.Invalid(pathPrefix.Child("checkIntervalSeconds"), v.Spec.HealthWatchdog.CheckIntervalSeconds,
+			"checkIntervalSeconds cannot be negative")
+		allErrs = append(allErrs, err)
+	}
+	return allErrs
+}
+
+// validateClientTLSAuth checks the trusted CA and the TLS authentication
+// methods of client certificate authentication
+func (v *VerticaDB) validateClientTLSAuth(allErrs field.ErrorList) field.ErrorList {
+	if v.Spec.ClientTLSAuth == nil {
+		return allErrs
+	}
+	pathPrefix := field.NewPath("spec").Child("clientTLSAuth")
+	if !vmeta.UseVClusterOps(v.Annotations) {
+		err := field.Forbidden(pathPrefix,
+			"clientTLSAuth is only supported for vclusterops deployments")
+		allErrs = append(allErrs, err)
+	}
+	if !v.IsClientServerTLSAuthEnabled() {
+		err := field.Forbidden(pathPrefix,
+			"clientTLSAuth requires client-server TLS to be enabled")
+		allErrs = append(allErrs, err)
+	}
+	if v.Spec.ClientTLSAuth.TrustedCASecret == "" {
+		err := field.Required(pathPrefix.Child("trustedCASecret"),
+			"trustedCASecret must name the secret with the CA certificates of the clients")
+		allErrs = append(allErrs, err)
+	}
+	names := map[string]bool{}
+	for i := range v.Spec.ClientTLSAuth.Methods {
+		m := &v.Spec.ClientTLSAuth.Methods[i]
+		methodPath := pathPrefix.Child("methods").Index(i)
+		if !isValidConfigParameterName(m.Name) {
+			err := field.Invalid(methodPath.Child("name"), m.Name,
+				"authentication method name must start with a letter and only contain letters, digits and underscores")
+			allErrs = append(allErrs, err)
+		}
+		if names[strings.ToLower(m.Name)] {
+			err := field.Duplicate(methodPath.Child("name"), m.Name)
+			allErrs = append(allErrs, err)
+		}
+		names[strings.ToLower(m.Name)] = true
+		if _, _, err := net.ParseCIDR(m.GetHost()); err != nil {
+			err := field.Invalid(methodPath.Child("host"), m.Host,
+				"host must be an address range in CIDR notation")
+			allErrs = append(allErrs, err)
+		}
+		if m.Priority < 0 {
+			err := field.Invalid(methodPath.Child("priority"), m.Priority,
+				"priority cannot be negative")
+			allErrs = append(allErrs, err)
+		}
+		allErrs = validateTLSAuthGrantees(allErrs, methodPath.Child("users"), m.Users)
+		allErrs = validateTLSAuthGrantees(allErrs, methodPath.Child("roles"), m.Roles)
+	}
+	return allErrs
+}
+
+// validateTLSAuthGrantees checks that each user or role of a TLS
+// authentication method is a name or a valid glob pattern
+func validateTLSAuthGrantees(allErrs field.ErrorList, fldPath *field.Path, grantees []string) field.ErrorList {
+	for i, grantee := range grantees {
+		if grantee == "" {
+			err := field.Invalid(fldPath.Index(i), grantee, "name cannot be empty")
+			allErrs = append(allErrs, err)
+			continue
+		}
+		if _, err := path.Match(grantee, ""); err != nil {
+			err := field.Invalid(fldPath.Index(i), grantee, "name is not a valid glob pattern")
+			allErrs = append(allErrs, err)
+		}
+	}
+	return allErrs
+}
+
 func (v *VerticaDB) validateCustomLabels(allErrs field.ErrorList) field.ErrorList {
 	for _, invalidLabel := range vmeta.ProtectedLabels {
 		_, ok := v.Spec.Labels[invalidLabel]
@@ -3240,6 +3465,10 @@ func (v *VerticaDB) validateOneTLSAutoRotateConfig(configName string) field.Erro
 
 	fldPath := field.NewPath("spec").Child(fieldName).Child("autoRotate")
 
+	if tls.AutoRotate.Issue != nil {
+		return v.validateTLSAutoIssueConfig(tls.AutoRotate, fldPath)
+	}
+
 	secrets := tls.AutoRotate.Secrets
 	interval := tls.AutoRotate.Interval
 
@@ -3272,3 +3501,46 @@ func (v *VerticaDB) validateOneTLSAutoRotateConfig(configName string) field.Erro
 
 	return allErrs
 }
+
+// validateTLSAutoIssueConfig validates autoRotate when the operator issues the
+// certificates. The secret list and interval do not apply in that mode.
+func (v *VerticaDB) validateTLSAutoIssueConfig(autoRotate *TLSAutoRotate, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	issue := autoRotate.Issue
+	issuePath := fldPath.Child("issue")
+
+	if len(autoRotate.Secrets) > 0 {
+		allErrs = append(allErrs,
+			field.Forbidden(fldPath.Child("secrets"),
+				"cannot be set together with autoRotate.issue"),
+		)
+	}
+	if issue.RenewAtLifetimePercent < 0 || issue.RenewAtLifetimePercent > 99 {
+		allErrs = append(allErrs,
+			field.Invalid(issuePath.Child("renewAtLifetimePercent"), issue.RenewAtLifetimePercent,
+				"must be between 1 and 99"),
+		)
+	}
+	if (issue.CASecret == "") == (issue.IssuerRef == nil) {
+		allErrs = append(allErrs,
+			field.Invalid(issuePath, issue,
+				"exactly one of caSecret or issuerRef must be set"),
+		)
+	}
+	if issue.IssuerRef != nil {
+		if issue.IssuerRef.Name == "" {
+			allErrs = append(allErrs,
+				field.Required(issuePath.Child("issuerRef").Child("name"),
+					"must be set to the name of a cert-manager issuer"),
+			)
+		}
+		kind := issue.IssuerRef.GetIssuerKind()
+		if kind != IssuerKind && kind != ClusterIssuerKind {
+			allErrs = append(allErrs,
+				field.NotSupported(issuePath.Child("issuerRef").Child("kind"), kind,
+					[]string{IssuerKind, ClusterIssuerKind}),
+			)
+		}
+	}
+	return allErrs
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -3038,6 +3039,42 @@ var _ = Describe("verticadb_webhook", func() {
 		Expect(allErrs).To(BeEmpty())
 	})
 
+	It("should validate autoRotate.issue", func() {
+		vdb := MakeVDBForTLS()
+		vdb.Spec.ClientServerTLS = MakeTLSWithAutoRotate(nil, 0, "")
+		vdb.Spec.ClientServerTLS.AutoRotate.Issue = &TLSCertIssue{CASecret: "ca"}
+		Expect(vdb.validateAutoRotateConfig(field.ErrorList{})).To(BeEmpty())
+
+		vdb.Spec.ClientServerTLS.AutoRotate.Secrets = []string{"secret1", "secret2"}
+		allErrs := vdb.validateAutoRotateConfig(field.ErrorList{})
+		Expect(allErrs).To(HaveLen(1))
+		Expect(allErrs[0].Error()).To(ContainSubstring("cannot be set together with autoRotate.issue"))
+
+		vdb.Spec.ClientServerTLS.AutoRotate.Secrets = nil
+		vdb.Spec.ClientServerTLS.AutoRotate.Issue.IssuerRef = &CertManagerIssuerRef{Name: "ca-issuer"}
+		allErrs = vdb.validateAutoRotateConfig(field.ErrorList{})
+		Expect(allErrs).To(HaveLen(1))
+		Expect(allErrs[0].Error()).To(ContainSubstring("exactly one of caSecret or issuerRef"))
+
+		vdb.Spec.ClientServerTLS.AutoRotate.Issue.CASecret = ""
+		vdb.Spec.ClientServerTLS.AutoRotate.Issue.IssuerRef.Kind = "Vault"
+		vdb.Spec.ClientServerTLS.AutoRotate.Issue.RenewAtLifetimePercent = 100
+		Expect(vdb.validateAutoRotateConfig(field.ErrorList{})).To(HaveLen(2))
+
+		vdb.Spec.ClientServerTLS.AutoRotate.Issue.IssuerRef.Kind = ClusterIssuerKind
+		vdb.Spec.ClientServerTLS.AutoRotate.Issue.RenewAtLifetimePercent = 80
+		Expect(vdb.validateAutoRotateConfig(field.ErrorList{})).To(BeEmpty())
+	})
+
+	It("should compute the renewal time from the certificate lifetime", func() {
+		notBefore := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		notAfter := notBefore.Add(100 * time.Hour)
+		issue := &TLSCertIssue{RenewAtLifetimePercent: 75}
+		Expect(issue.GetCertRenewalTime(notBefore, notAfter)).To(Equal(notBefore.Add(75 * time.Hour)))
+		issue.RenewAtLifetimePercent = 0
+		Expect(issue.GetCertRenewalTime(notBefore, notAfter)).To(Equal(notBefore.Add(67 * time.Hour)))
+	})
+
 	It("should not allow disabling TLS after it is enabled", func() {
 		oldVdb := MakeVDB()
 		oldVdb.Spec.HTTPSNMATLS = &TLSConfigSpec{Enabled: BoolPtr(true)}
@@ -3065,6 +3102,215 @@ var _ = Describe("verticadb_webhook", func() {
 		allErrs := newVdb.checkValidTLSEnabled(oldVdb, nil)
 		Expect(allErrs).Should(BeEmpty())
 	})
+
+	It("should validate configuration parameters", func() {
+		vdb := createVDBHelper()
+		vdb.Spec.ConfigurationParameters = map[string]ConfigurationParameter{
+			"MaxClientSessions": {Value: "100"},
+			"EnableSSL":         {Value: "1", Subcluster: vdb.Spec.Subclusters[0].Name},
+			"MaxMemorySize":     {Value: "1G", Node: "v_db_node0001"},
+		}
+		validateSpecValuesHaveErr(vdb, false)
+
+		vdb.Spec.ConfigurationParameters["maxclientsessions"] = ConfigurationParameter{Value: "50"}
+		validateSpecValuesHaveErr(vdb, true)
+		delete(vdb.Spec.ConfigurationParameters, "maxclientsessions")
+
+		vdb.Spec.ConfigurationParameters["Bad-Name"] = ConfigurationParameter{Value: "1"}
+		validateSpecValuesHaveErr(vdb, true)
+		delete(vdb.Spec.ConfigurationParameters, "Bad-Name")
+
+		vdb.Spec.ConfigurationParameters["EnableSSL"] = ConfigurationParameter{Value: "1", Subcluster: "not-there"}
+		validateSpecValuesHaveErr(vdb, true)
+
+		vdb.Spec.ConfigurationParameters["EnableSSL"] = ConfigurationParameter{Value: "1",
+			Subcluster: vdb.Spec.Subclusters[0].Name, Node: "v_db_node0001"}
+		validateSpecValuesHaveErr(vdb, true)
+		delete(vdb.Spec.ConfigurationParameters, "EnableSSL")
+		validateSpecValuesHaveErr(vdb, false)
+
+		// A node scope must be a node of this database
+		vdb.Spec.ConfigurationParameters["MaxMemorySize"] = ConfigurationParameter{Value: "1G", Node: "v_otherdb_node0001"}
+		validateSpecValuesHaveErr(vdb, true)
+		vdb.Status.Subclusters = []SubclusterStatus{
+			{Name: vdb.Spec.Subclusters[0].Name, Detail: []VerticaDBPodStatus{{VNodeName: "v_db_node0001"}}},
+		}
+		vdb.Spec.ConfigurationParameters["MaxMemorySize"] = ConfigurationParameter{Value: "1G", Node: "v_db_node0002"}
+		validateSpecValuesHaveErr(vdb, true)
+		vdb.Spec.ConfigurationParameters["MaxMemorySize"] = ConfigurationParameter{Value: "1G", Node: "V_DB_NODE0001"}
+		validateSpecValuesHaveErr(vdb, false)
+
+		vdb.Annotations[vmeta.VClusterOpsAnnotation] = vmeta.VClusterOpsAnnotationFalse
+		Expect(vdb.validateConfigurationParameters(field.ErrorList{})).ShouldNot(BeEmpty())
+	})
+
+	It("should validate the health watchdog settings", func() {
+		vdb := createVDBHelper()
+		vdb.Spec.HealthWatchdog = &HealthWatchdogSpec{
+			Parameters:           map[string]string{"QueryRuntimeLimit": "600"},
+			Policy:               map[string]string{"cancel": "true"},
+			RunawayQueryAction:   RunawayQueryActionCancel,
+			CheckIntervalSeconds: 30,
+		}
+		validateSpecValuesHaveErr(vdb, false)
+
+		vdb.Spec.HealthWatchdog.RunawayQueryAction = "Kill"
+		validateSpecValuesHaveErr(vdb, true)
+		vdb.Spec.HealthWatchdog.RunawayQueryAction = RunawayQueryActionReport
+		validateSpecValuesHaveErr(vdb, false)
+
+		vdb.Spec.HealthWatchdog.Parameters["Bad-Name"] = "1"
+		validateSpecValuesHaveErr(vdb, true)
+		delete(vdb.Spec.HealthWatchdog.Parameters, "Bad-Name")
+
+		vdb.Spec.HealthWatchdog.Parameters["QueryRuntimeLimit"] = ""
+		validateSpecValuesHaveErr(vdb, true)
+		vdb.Spec.HealthWatchdog.Parameters["QueryRuntimeLimit"] = "600"
+
+		vdb.Spec.HealthWatchdog.CheckIntervalSeconds = -1
+		validateSpecValuesHaveErr(vdb, true)
+		vdb.Spec.HealthWatchdog.CheckIntervalSeconds = 0
+		validateSpecValuesHaveErr(vdb, false)
+
+		vdb.Annotations[vmeta.VClusterOpsAnnotation] = vmeta.VClusterOpsAnnotationFalse
+		Expect(vdb.validateHealthWatchdog(field.ErrorList{})).ShouldNot(BeEmpty())
+	})
+
+	It("should validate the password rotation policy", func() {
+		vdb := createVDBHelper()
+		vdb.Spec.PasswordRotation = &PasswordRotationPolicy{
+			IntervalDays: 90,
+			TargetSecret: "su-passwd",
+		}
+		validateSpecValuesHaveErr(vdb, false)
+
+		vdb.Spec.PasswordRotation.IntervalDays = -1
+		validateSpecValuesHaveErr(vdb, true)
+		vdb.Spec.PasswordRotation.IntervalDays = 0
+		validateSpecValuesHaveErr(vdb, false)
+
+		vdb.Spec.PasswordRotation.TargetSecret = ""
+		validateSpecValuesHaveErr(vdb, true)
+		vdb.Spec.PasswordRotation.TargetSecret = "gsm://projects/123/secrets/su"
+		validateSpecValuesHaveErr(vdb, true)
+		vdb.Spec.PasswordRotation.TargetSecret = "su-passwd"
+
+		vdb.Spec.Sandboxes = []Sandbox{{Name: "sand", Subclusters: []SandboxSubcluster{{Name: "sc1"}}}}
+		Expect(vdb.validatePasswordRotation(field.ErrorList{})).ShouldNot(BeEmpty())
+	})
+
+	It("should compute when the next password rotation is due", func() {
+		vdb := MakeVDB()
+		now := time.Now()
+		Expect(vdb.GetPasswordRotationNextIn(now)).Should(BeZero())
+
+		vdb.Spec.PasswordRotation = &PasswordRotationPolicy{TargetSecret: "su-passwd"}
+		Expect(vdb.GetPasswordRotationInterval()).Should(Equal(90 * 24 * time.Hour))
+		Expect(vdb.GetPasswordRotationNextIn(now)).Should(BeZero())
+		Expect(vdb.GetPasswordRotationSecretName(3)).Should(Equal("su-passwd-3"))
+
+		vdb.Status.PasswordRotation = &PasswordRotationStatus{LastRotationTime: &metav1.Time{Time: now.Add(-24 * time.Hour)}}
+		Expect(vdb.GetPasswordRotationNextIn(now)).Should(Equal(89 * 24 * time.Hour))
+
+		vdb.Spec.PasswordRotation.IntervalDays = 1
+		Expect(vdb.GetPasswordRotationNextIn(now)).Should(BeZero())
+		Expect(vdb.IsPasswordRotationPending()).Should(BeFalse())
+		vdb.Status.PasswordRotation.PendingSecret = "su-passwd-1"
+		Expect(vdb.IsPasswordRotationPending()).Should(BeTrue())
+	})
+
+	It("should count the first password rotation interval from when the policy was enabled", func() {
+		vdb := MakeVDB()
+		now := time.Now()
+		vdb.Spec.PasswordRotation = &PasswordRotationPolicy{TargetSecret: "su-passwd", IntervalDays: 10}
+		vdb.Status.PasswordRotation = &PasswordRotationStatus{EnabledTime: &metav1.Time{Time: now.Add(-24 * time.Hour)}}
+		Expect(vdb.GetPasswordRotationNextIn(now)).Should(Equal(9 * 24 * time.Hour))
+
+		vdb.Status.PasswordRotation.EnabledTime = &metav1.Time{Time: now.Add(-11 * 24 * time.Hour)}
+		Expect(vdb.GetPasswordRotationNextIn(now)).Should(BeZero())
+
+		// The last rotation takes over once there is one
+		vdb.Status.PasswordRotation.LastRotationTime = &metav1.Time{Time: now.Add(-2 * 24 * time.Hour)}
+		Expect(vdb.GetPasswordRotationNextIn(now)).Should(Equal(8 * 24 * time.Hour))
+	})
+
+	It("should prefer the secret of the last password rotation", func() {
+		vdb := MakeVDB()
+		vdb.Spec.PasswordSecret = "su-passwd"
+		statusSecret := "su-passwd"
+		vdb.Status.PasswordSecret = &statusSecret
+		vdb.Status.PasswordRotation = &PasswordRotationStatus{CurrentSecret: "su-rotated-2"}
+		// Ignored until the policy is set
+		Expect(vdb.GetPasswordSecret()).Should(Equal("su-passwd"))
+		Expect(vdb.GetDesiredPasswordSecret()).Should(Equal("su-passwd"))
+
+		vdb.Spec.PasswordRotation = &PasswordRotationPolicy{TargetSecret: "su-rotated"}
+		Expect(vdb.GetPasswordSecret()).Should(Equal("su-rotated-2"))
+		Expect(vdb.GetDesiredPasswordSecret()).Should(Equal("su-rotated-2"))
+	})
+
+	It("should compute when the next health watchdog check is due", func() {
+		vdb := MakeVDB()
+		now := time.Now()
+		Expect(vdb.GetHealthWatchdogNextCheckIn(now)).Should(BeZero())
+
+		vdb.Spec.HealthWatchdog = &HealthWatchdogSpec{}
+		Expect(vdb.GetRunawayQueryAction()).Should(Equal(RunawayQueryActionNone))
+		vdb.Status.HealthWatchdog = &HealthWatchdogStatus{LastCheckTime: &metav1.Time{Time: now}}
+		Expect(vdb.GetHealthWatchdogNextCheckIn(now)).Should(BeZero())
+
+		vdb.Status.HealthWatchdog = nil
+		vdb.Spec.HealthWatchdog.RunawayQueryAction = RunawayQueryActionReport
+		Expect(vdb.IsHealthWatchdogCheckEnabled()).Should(BeTrue())
+		Expect(vdb.IsHealthWatchdogCancelEnabled()).Should(BeFalse())
+		Expect(vdb.GetHealthWatchdogCheckInterval()).Should(Equal(time.Minute))
+		Expect(vdb.GetHealthWatchdogNextCheckIn(now)).Should(BeZero())
+
+		vdb.Status.HealthWatchdog = &HealthWatchdogStatus{LastCheckTime: &metav1.Time{Time: now.Add(-20 * time.Second)}}
+		Expect(vdb.GetHealthWatchdogNextCheckIn(now)).Should(Equal(40 * time.Second))
+
+		vdb.Spec.HealthWatchdog.CheckIntervalSeconds = 10
+		Expect(vdb.GetHealthWatchdogNextCheckIn(now)).Should(BeZero())
+	})
+
+	It("should validate client certificate authentication", func() {
+		vdb := createVDBHelper()
+		vdb.Spec.ClientServerTLS = &TLSConfigSpec{Enabled: BoolPtr(true)}
+		vdb.Spec.ClientTLSAuth = &ClientTLSAuthSpec{
+			TrustedCASecret: "client-ca",
+			Methods: []TLSAuthMethod{
+				{Name: "svc_tls", Host: "10.0.0.0/8", Users: []string{"svc-*", "etl"}},
+				{Name: "app_tls", Roles: []string{"app_role"}, Priority: 5},
+			},
+		}
+		validateSpecValuesHaveErr(vdb, false)
+		Expect(vdb.Spec.ClientTLSAuth.Methods[1].GetHost()).Should(Equal(DefaultTLSAuthMethodHost))
+
+		vdb.Spec.ClientTLSAuth.Methods[0].Host = "10.0.0.1"
+		validateSpecValuesHaveErr(vdb, true)
+		vdb.Spec.ClientTLSAuth.Methods[0].Host = "10.0.0.0/8"
+
+		vdb.Spec.ClientTLSAuth.Methods[1].Name = "SVC_TLS"
+		validateSpecValuesHaveErr(vdb, true)
+		vdb.Spec.ClientTLSAuth.Methods[1].Name = "app-tls"
+		validateSpecValuesHaveErr(vdb, true)
+		vdb.Spec.ClientTLSAuth.Methods[1].Name = "app_tls"
+
+		vdb.Spec.ClientTLSAuth.Methods[0].Users = []string{"svc-[a"}
+		validateSpecValuesHaveErr(vdb, true)
+		vdb.Spec.ClientTLSAuth.Methods[0].Users = []string{"svc-*"}
+
+		vdb.Spec.ClientTLSAuth.Methods[1].Priority = -1
+		validateSpecValuesHaveErr(vdb, true)
+		vdb.Spec.ClientTLSAuth.Methods[1].Priority = 0
+
+		vdb.Spec.ClientTLSAuth.TrustedCASecret = ""
+		validateSpecValuesHaveErr(vdb, true)
+		vdb.Spec.ClientTLSAuth.TrustedCASecret = "client-ca"
+
+		vdb.Spec.ClientServerTLS.Enabled = BoolPtr(false)
+		validateSpecValuesHaveErr(vdb, true)
+	})
 })
 
 func createVDBHelper() *VerticaDB {
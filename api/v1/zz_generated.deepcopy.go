@@ -57,6 +57,26 @@ func (in *Affinity) DeepCopy() *Affinity {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppliedConfigurationParameter) DeepCopyInto(out *AppliedConfigurationParameter) {
+	*out = *in
+	if in.Levels != nil {
+		in, out := &in.Levels, &out.Levels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedConfigurationParameter.
+func (in *AppliedConfigurationParameter) DeepCopy() *AppliedConfigurationParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(AppliedConfigurationParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CPUMemorySpec) DeepCopyInto(out *CPUMemorySpec) {
 	*out = *in
@@ -72,6 +92,65 @@ func (in *CPUMemorySpec) DeepCopy() *CPUMemorySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerIssuerRef) DeepCopyInto(out *CertManagerIssuerRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerIssuerRef.
+func (in *CertManagerIssuerRef) DeepCopy() *CertManagerIssuerRef {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerIssuerRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientTLSAuthSpec) DeepCopyInto(out *ClientTLSAuthSpec) {
+	*out = *in
+	if in.Methods != nil {
+		in, out := &in.Methods, &out.Methods
+		*out = make([]TLSAuthMethod, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientTLSAuthSpec.
+func (in *ClientTLSAuthSpec) DeepCopy() *ClientTLSAuthSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientTLSAuthSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientTLSAuthStatus) DeepCopyInto(out *ClientTLSAuthStatus) {
+	*out = *in
+	if in.Methods != nil {
+		in, out := &in.Methods, &out.Methods
+		*out = make([]TLSAuthMethodStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientTLSAuthStatus.
+func (in *ClientTLSAuthStatus) DeepCopy() *ClientTLSAuthStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientTLSAuthStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommunalStorage) DeepCopyInto(out *CommunalStorage) {
 	*out = *in
@@ -94,6 +173,21 @@ func (in *CommunalStorage) DeepCopy() *CommunalStorage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigurationParameter) DeepCopyInto(out *ConfigurationParameter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigurationParameter.
+func (in *ConfigurationParameter) DeepCopy() *ConfigurationParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigurationParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CustomAutoscalerSpec) DeepCopyInto(out *CustomAutoscalerSpec) {
 	*out = *in
@@ -107,6 +201,21 @@ func (in *CustomAutoscalerSpec) DeepCopyInto(out *CustomAutoscalerSpec) {
 		*out = new(ScaledObjectSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(ScheduleSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Predictive != nil {
+		in, out := &in.Predictive, &out.Predictive
+		*out = new(PredictiveSpec)
+		**out = **in
+	}
+	if in.ScalingPolicy != nil {
+		in, out := &in.ScalingPolicy, &out.ScalingPolicy
+		*out = new(ScalingPolicySpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomAutoscalerSpec.
@@ -134,6 +243,68 @@ func (in *DBTLSConfig) DeepCopy() *DBTLSConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthWatchdogSpec) DeepCopyInto(out *HealthWatchdogSpec) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthWatchdogSpec.
+func (in *HealthWatchdogSpec) DeepCopy() *HealthWatchdogSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthWatchdogSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthWatchdogStatus) DeepCopyInto(out *HealthWatchdogStatus) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthWatchdogStatus.
+func (in *HealthWatchdogStatus) DeepCopy() *HealthWatchdogStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthWatchdogStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HPASpec) DeepCopyInto(out *HPASpec) {
 	*out = *in
@@ -218,6 +389,130 @@ func (in *MetricDefinition) DeepCopy() *MetricDefinition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricWeight) DeepCopyInto(out *MetricWeight) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricWeight.
+func (in *MetricWeight) DeepCopy() *MetricWeight {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricWeight)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordRotationPolicy) DeepCopyInto(out *PasswordRotationPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordRotationPolicy.
+func (in *PasswordRotationPolicy) DeepCopy() *PasswordRotationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordRotationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordRotationStatus) DeepCopyInto(out *PasswordRotationStatus) {
+	*out = *in
+	if in.EnabledTime != nil {
+		in, out := &in.EnabledTime, &out.EnabledTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastRotationTime != nil {
+		in, out := &in.LastRotationTime, &out.LastRotationTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordRotationStatus.
+func (in *PasswordRotationStatus) DeepCopy() *PasswordRotationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordRotationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlannedAction) DeepCopyInto(out *PlannedAction) {
+	*out = *in
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlannedAction.
+func (in *PlannedAction) DeepCopy() *PlannedAction {
+	if in == nil {
+		return nil
+	}
+	out := new(PlannedAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyMetricEvaluation) DeepCopyInto(out *PolicyMetricEvaluation) {
+	*out = *in
+	if in.ScaleInConditionMet != nil {
+		in, out := &in.ScaleInConditionMet, &out.ScaleInConditionMet
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyMetricEvaluation.
+func (in *PolicyMetricEvaluation) DeepCopy() *PolicyMetricEvaluation {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyMetricEvaluation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PredictiveForecast) DeepCopyInto(out *PredictiveForecast) {
+	*out = *in
+	in.ForecastTime.DeepCopyInto(&out.ForecastTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PredictiveForecast.
+func (in *PredictiveForecast) DeepCopy() *PredictiveForecast {
+	if in == nil {
+		return nil
+	}
+	out := new(PredictiveForecast)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PredictiveSpec) DeepCopyInto(out *PredictiveSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PredictiveSpec.
+func (in *PredictiveSpec) DeepCopy() *PredictiveSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PredictiveSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PrometheusSpec) DeepCopyInto(out *PrometheusSpec) {
 	*out = *in
@@ -273,6 +568,29 @@ func (in *ProxySubclusterConfig) DeepCopy() *ProxySubclusterConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconcilePlan) DeepCopyInto(out *ReconcilePlan) {
+	*out = *in
+	in.GeneratedTime.DeepCopyInto(&out.GeneratedTime)
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]PlannedAction, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconcilePlan.
+func (in *ReconcilePlan) DeepCopy() *ReconcilePlan {
+	if in == nil {
+		return nil
+	}
+	out := new(ReconcilePlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RestorePointInfo) DeepCopyInto(out *RestorePointInfo) {
 	*out = *in
@@ -374,6 +692,79 @@ func (in *SandboxUpgradeState) DeepCopy() *SandboxUpgradeState {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleInCondition) DeepCopyInto(out *ScaleInCondition) {
+	*out = *in
+	in.Threshold.DeepCopyInto(&out.Threshold)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleInCondition.
+func (in *ScaleInCondition) DeepCopy() *ScaleInCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleInCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleInRule) DeepCopyInto(out *ScaleInRule) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ScaleInCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleInRule.
+func (in *ScaleInRule) DeepCopy() *ScaleInRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleInRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleInSafetySpec) DeepCopyInto(out *ScaleInSafetySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleInSafetySpec.
+func (in *ScaleInSafetySpec) DeepCopy() *ScaleInSafetySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleInSafetySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleInVictim) DeepCopyInto(out *ScaleInVictim) {
+	*out = *in
+	in.SelectedTime.DeepCopyInto(&out.SelectedTime)
+	if in.RemovedTime != nil {
+		in, out := &in.RemovedTime, &out.RemovedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleInVictim.
+func (in *ScaleInVictim) DeepCopy() *ScaleInVictim {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleInVictim)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScaleTrigger) DeepCopyInto(out *ScaleTrigger) {
 	*out = *in
@@ -387,6 +778,11 @@ func (in *ScaleTrigger) DeepCopyInto(out *ScaleTrigger) {
 		*out = new(CPUMemorySpec)
 		**out = **in
 	}
+	if in.Vertica != nil {
+		in, out := &in.Vertica, &out.Vertica
+		*out = new(VerticaMetricSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleTrigger.
@@ -446,6 +842,98 @@ func (in *ScaledObjectSpec) DeepCopy() *ScaledObjectSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScalingPolicyEvaluation) DeepCopyInto(out *ScalingPolicyEvaluation) {
+	*out = *in
+	in.EvaluationTime.DeepCopyInto(&out.EvaluationTime)
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]PolicyMetricEvaluation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ScaleInConditionsMetSince != nil {
+		in, out := &in.ScaleInConditionsMetSince, &out.ScaleInConditionsMetSince
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScalingPolicyEvaluation.
+func (in *ScalingPolicyEvaluation) DeepCopy() *ScalingPolicyEvaluation {
+	if in == nil {
+		return nil
+	}
+	out := new(ScalingPolicyEvaluation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScalingPolicySpec) DeepCopyInto(out *ScalingPolicySpec) {
+	*out = *in
+	if in.Weights != nil {
+		in, out := &in.Weights, &out.Weights
+		*out = make([]MetricWeight, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScaleIn != nil {
+		in, out := &in.ScaleIn, &out.ScaleIn
+		*out = new(ScaleInRule)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScalingPolicySpec.
+func (in *ScalingPolicySpec) DeepCopy() *ScalingPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ScalingPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduleSpec) DeepCopyInto(out *ScheduleSpec) {
+	*out = *in
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]ScheduleWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.DefaultTargetSize != nil {
+		in, out := &in.DefaultTargetSize, &out.DefaultTargetSize
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleSpec.
+func (in *ScheduleSpec) DeepCopy() *ScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduleWindow) DeepCopyInto(out *ScheduleWindow) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleWindow.
+func (in *ScheduleWindow) DeepCopy() *ScheduleWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduleWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Subcluster) DeepCopyInto(out *Subcluster) {
 	*out = *in
@@ -557,6 +1045,56 @@ func (in *SubclusterStatus) DeepCopy() *SubclusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSAuthMethod) DeepCopyInto(out *TLSAuthMethod) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSAuthMethod.
+func (in *TLSAuthMethod) DeepCopy() *TLSAuthMethod {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSAuthMethod)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSAuthMethodStatus) DeepCopyInto(out *TLSAuthMethodStatus) {
+	*out = *in
+	if in.Grantees != nil {
+		in, out := &in.Grantees, &out.Grantees
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MatchedGrantees != nil {
+		in, out := &in.MatchedGrantees, &out.MatchedGrantees
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSAuthMethodStatus.
+func (in *TLSAuthMethodStatus) DeepCopy() *TLSAuthMethodStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSAuthMethodStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLSAutoRotate) DeepCopyInto(out *TLSAutoRotate) {
 	*out = *in
@@ -565,6 +1103,11 @@ func (in *TLSAutoRotate) DeepCopyInto(out *TLSAutoRotate) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Issue != nil {
+		in, out := &in.Issue, &out.Issue
+		*out = new(TLSCertIssue)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSAutoRotate.
@@ -577,6 +1120,26 @@ func (in *TLSAutoRotate) DeepCopy() *TLSAutoRotate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSCertIssue) DeepCopyInto(out *TLSCertIssue) {
+	*out = *in
+	if in.IssuerRef != nil {
+		in, out := &in.IssuerRef, &out.IssuerRef
+		*out = new(CertManagerIssuerRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSCertIssue.
+func (in *TLSCertIssue) DeepCopy() *TLSCertIssue {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSCertIssue)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLSConfigSpec) DeepCopyInto(out *TLSConfigSpec) {
 	*out = *in
@@ -611,6 +1174,14 @@ func (in *TLSConfigStatus) DeepCopyInto(out *TLSConfigStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.CertExpiry != nil {
+		in, out := &in.CertExpiry, &out.CertExpiry
+		*out = (*in).DeepCopy()
+	}
+	if in.NextRotation != nil {
+		in, out := &in.NextRotation, &out.NextRotation
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSConfigStatus.
@@ -707,6 +1278,11 @@ func (in *VerticaAutoscalerSpec) DeepCopyInto(out *VerticaAutoscalerSpec) {
 		*out = new(CustomAutoscalerSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ScaleInSafety != nil {
+		in, out := &in.ScaleInSafety, &out.ScaleInSafety
+		*out = new(ScaleInSafetySpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerticaAutoscalerSpec.
@@ -729,6 +1305,30 @@ func (in *VerticaAutoscalerStatus) DeepCopyInto(out *VerticaAutoscalerStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.VerticaMetrics != nil {
+		in, out := &in.VerticaMetrics, &out.VerticaMetrics
+		*out = make([]VerticaMetricStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Forecast != nil {
+		in, out := &in.Forecast, &out.Forecast
+		*out = new(PredictiveForecast)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScaleInVictims != nil {
+		in, out := &in.ScaleInVictims, &out.ScaleInVictims
+		*out = make([]ScaleInVictim, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PolicyEvaluation != nil {
+		in, out := &in.PolicyEvaluation, &out.PolicyEvaluation
+		*out = new(ScalingPolicyEvaluation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerticaAutoscalerStatus.
@@ -914,6 +1514,18 @@ func (in *VerticaDBSpec) DeepCopyInto(out *VerticaDBSpec) {
 		*out = new(DBTLSConfig)
 		**out = **in
 	}
+	if in.ConfigurationParameters != nil {
+		in, out := &in.ConfigurationParameters, &out.ConfigurationParameters
+		*out = make(map[string]ConfigurationParameter, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.HealthWatchdog != nil {
+		in, out := &in.HealthWatchdog, &out.HealthWatchdog
+		*out = new(HealthWatchdogSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.HTTPSNMATLS != nil {
 		in, out := &in.HTTPSNMATLS, &out.HTTPSNMATLS
 		*out = new(TLSConfigSpec)
@@ -924,6 +1536,16 @@ func (in *VerticaDBSpec) DeepCopyInto(out *VerticaDBSpec) {
 		*out = new(TLSConfigSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ClientTLSAuth != nil {
+		in, out := &in.ClientTLSAuth, &out.ClientTLSAuth
+		*out = new(ClientTLSAuthSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PasswordRotation != nil {
+		in, out := &in.PasswordRotation, &out.PasswordRotation
+		*out = new(PasswordRotationPolicy)
+		**out = **in
+	}
 	if in.ReadinessProbeOverride != nil {
 		in, out := &in.ReadinessProbeOverride, &out.ReadinessProbeOverride
 		*out = new(corev1.Probe)
@@ -1030,6 +1652,37 @@ func (in *VerticaDBStatus) DeepCopyInto(out *VerticaDBStatus) {
 		*out = new(DBTLSConfig)
 		**out = **in
 	}
+	if in.ConfigurationParametersPendingRestart != nil {
+		in, out := &in.ConfigurationParametersPendingRestart, &out.ConfigurationParametersPendingRestart
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AppliedConfigurationParameters != nil {
+		in, out := &in.AppliedConfigurationParameters, &out.AppliedConfigurationParameters
+		*out = make([]AppliedConfigurationParameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HealthWatchdog != nil {
+		in, out := &in.HealthWatchdog, &out.HealthWatchdog
+		*out = new(HealthWatchdogStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientTLSAuth != nil {
+		in, out := &in.ClientTLSAuth, &out.ClientTLSAuth
+		*out = new(ClientTLSAuthStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PasswordRotation != nil {
+		in, out := &in.PasswordRotation, &out.PasswordRotation
+		*out = new(PasswordRotationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CertExpiryNextCheckTime != nil {
+		in, out := &in.CertExpiryNextCheckTime, &out.CertExpiryNextCheckTime
+		*out = (*in).DeepCopy()
+	}
 	if in.ObservedConfigMaps != nil {
 		in, out := &in.ObservedConfigMaps, &out.ObservedConfigMaps
 		*out = make([]string, len(*in))
@@ -1040,6 +1693,11 @@ func (in *VerticaDBStatus) DeepCopyInto(out *VerticaDBStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(ReconcilePlan)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerticaDBStatus.
@@ -1051,3 +1709,34 @@ func (in *VerticaDBStatus) DeepCopy() *VerticaDBStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerticaMetricSpec) DeepCopyInto(out *VerticaMetricSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerticaMetricSpec.
+func (in *VerticaMetricSpec) DeepCopy() *VerticaMetricSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VerticaMetricSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VerticaMetricStatus) DeepCopyInto(out *VerticaMetricStatus) {
+	*out = *in
+	in.SampleTime.DeepCopyInto(&out.SampleTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VerticaMetricStatus.
+func (in *VerticaMetricStatus) DeepCopy() *VerticaMetricStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VerticaMetricStatus)
+	in.DeepCopyInto(out)
+	return out
+}
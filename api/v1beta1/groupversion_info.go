@@ -27,12 +27,19 @@ const (
 	Group   = "vertica.com"
 	Version = "v1beta1"
 
-	VerticaDBKind          = "VerticaDB"
-	VerticaAutoscalerKind  = "VerticaAutoscaler"
-	EventTriggerKind       = "EventTrigger"
-	RestorePointsQueryKind = "VerticaRestorePointsQuery"
-	VerticaScrutinizeKind  = "VerticaScrutinize"
-	VerticaReplicatorKind  = "VerticaReplicator"
+	VerticaDBKind             = "VerticaDB"
+	VerticaAutoscalerKind     = "VerticaAutoscaler"
+	EventTriggerKind          = "EventTrigger"
+	RestorePointsQueryKind    = "VerticaRestorePointsQuery"
+	VerticaScrutinizeKind     = "VerticaScrutinize"
+	VerticaReplicatorKind     = "VerticaReplicator"
+	RestorePointScheduleKind  = "VerticaRestorePointSchedule"
+	VerticaUserKind           = "VerticaUser"
+	VerticaRoleKind           = "VerticaRole"
+	VerticaResourcePoolKind   = "VerticaResourcePool"
+	VerticaBackupKind         = "VerticaBackup"
+	VerticaHealthCheckKind    = "VerticaHealthCheck"
+	VerticaWorkloadReplayKind = "VerticaWorkloadReplay"
 )
 
 var (
@@ -46,10 +53,17 @@ var (
 	AddToScheme = SchemeBuilder.AddToScheme
 
 	// All supported group/kind by this operator
-	GkVDB  = schema.GroupKind{Group: Group, Kind: VerticaDBKind}
-	GkVAS  = schema.GroupKind{Group: Group, Kind: VerticaAutoscalerKind}
-	GkET   = schema.GroupKind{Group: Group, Kind: EventTriggerKind}
-	GkVRPQ = schema.GroupKind{Group: Group, Kind: RestorePointsQueryKind}
-	GkVSCR = schema.GroupKind{Group: Group, Kind: VerticaScrutinizeKind}
-	GkVR   = schema.GroupKind{Group: Group, Kind: VerticaReplicatorKind}
+	GkVDB     = schema.GroupKind{Group: Group, Kind: VerticaDBKind}
+	GkVAS     = schema.GroupKind{Group: Group, Kind: VerticaAutoscalerKind}
+	GkET      = schema.GroupKind{Group: Group, Kind: EventTriggerKind}
+	GkVRPQ    = schema.GroupKind{Group: Group, Kind: RestorePointsQueryKind}
+	GkVSCR    = schema.GroupKind{Group: Group, Kind: VerticaScrutinizeKind}
+	GkVR      = schema.GroupKind{Group: Group, Kind: VerticaReplicatorKind}
+	GkVRPS    = schema.GroupKind{Group: Group, Kind: RestorePointScheduleKind}
+	GkVUSR    = schema.GroupKind{Group: Group, Kind: VerticaUserKind}
+	GkVROLE   = schema.GroupKind{Group: Group, Kind: VerticaRoleKind}
+	GkVRPOOL  = schema.GroupKind{Group: Group, Kind: VerticaResourcePoolKind}
+	GkVBACKUP = schema.GroupKind{Group: Group, Kind: VerticaBackupKind}
+	GkVHC     = schema.GroupKind{Group: Group, Kind: VerticaHealthCheckKind}
+	GkVWR     = schema.GroupKind{Group: Group, Kind: VerticaWorkloadReplayKind}
 )
@@ -21,9 +21,11 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	v1 "github.com/vertica/vertica-kubernetes/api/v1"
+	vmeta "github.com/vertica/vertica-kubernetes/pkg/meta"
 
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
@@ -36,6 +38,14 @@ const (
 	ReasonSucceeded = "Succeeded"
 )
 
+// ResourcePoolMemorySizeRegex matches the memory size of a resource pool. It
+// is either an amount with a unit or a percentage.
+var ResourcePoolMemorySizeRegex = regexp.MustCompile(`^([0-9]+)([KMGT%])$`)
+
+// The units that a resource pool memory size can be given in, from smallest
+// to largest. Each is 1024 times the one before it.
+var resourcePoolMemoryUnits = []string{"K", "M", "G", "T"}
+
 // Affinity is used instead of corev1.Affinity and behaves the same.
 // This structure is used in some CRs fields to define the "Affinity".
 // corev1.Affinity is composed of 3 fields and for each of them,
@@ -114,6 +124,30 @@ func GenerateLogAgeTime(hourOffset time.Duration, timeZone string) string {
 	return timeOffsetFormatted
 }
 
+// GetLogAgeWindow returns the time window, in UTC, that scrutinize collects
+// logs for. It is derived from the log-age annotations the same way the
+// webhook validates them: log-age-hours wins, otherwise the oldest time
+// defaults to 24 hours ago and the newest time defaults to now.
+func (vscr *VerticaScrutinize) GetLogAgeWindow(now time.Time) (oldest, newest time.Time) {
+	newest = now.UTC()
+	oldest = newest.Add(-24 * time.Hour)
+	if hours := vmeta.GetScrutinizeLogAgeHours(vscr.Annotations); hours > 0 {
+		oldest = newest.Add(-time.Duration(hours) * time.Hour)
+		return oldest, newest
+	}
+	if t := vmeta.GetScrutinizeLogAgeOldestTime(vscr.Annotations); t != "" {
+		if parsed, err := parseLogAgeTime(t); err == nil {
+			oldest = parsed
+		}
+	}
+	if t := vmeta.GetScrutinizeLogAgeNewestTime(vscr.Annotations); t != "" {
+		if parsed, err := parseLogAgeTime(t); err == nil {
+			newest = parsed
+		}
+	}
+	return oldest, newest
+}
+
 // FindStatusCondition finds the conditionType in conditions.
 func (vscr *VerticaScrutinize) FindStatusCondition(conditionType string) *metav1.Condition {
 	return meta.FindStatusCondition(vscr.Status.Conditions, conditionType)
@@ -144,6 +178,11 @@ func (vrpq *VerticaRestorePointsQuery) IsStatusConditionPresent(statusCondition
 	return meta.FindStatusCondition(vrpq.Status.Conditions, statusCondition) != nil
 }
 
+// FindStatusCondition finds the conditionType in conditions.
+func (vrpq *VerticaRestorePointsQuery) FindStatusCondition(conditionType string) *metav1.Condition {
+	return meta.FindStatusCondition(vrpq.Status.Conditions, conditionType)
+}
+
 // FindStatusCondition finds the conditionType in conditions.
 func (vrep *VerticaReplicator) FindStatusCondition(conditionType string) *metav1.Condition {
 	return meta.FindStatusCondition(vrep.Status.Conditions, conditionType)
@@ -161,6 +200,232 @@ func (vrep *VerticaReplicator) IsStatusConditionPresent(statusCondition string)
 	return meta.FindStatusCondition(vrep.Status.Conditions, statusCondition) != nil
 }
 
+func (vrps *VerticaRestorePointSchedule) ExtractNamespacedName() types.NamespacedName {
+	return types.NamespacedName{
+		Name:      vrps.ObjectMeta.Name,
+		Namespace: vrps.ObjectMeta.Namespace,
+	}
+}
+
+// FindStatusCondition finds the conditionType in conditions.
+func (vrps *VerticaRestorePointSchedule) FindStatusCondition(conditionType string) *metav1.Condition {
+	return meta.FindStatusCondition(vrps.Status.Conditions, conditionType)
+}
+
+func (vrps *VerticaRestorePointSchedule) IsStatusConditionTrue(statusCondition string) bool {
+	return meta.IsStatusConditionTrue(vrps.Status.Conditions, statusCondition)
+}
+
+func (vrps *VerticaRestorePointSchedule) IsStatusConditionFalse(statusCondition string) bool {
+	return meta.IsStatusConditionFalse(vrps.Status.Conditions, statusCondition)
+}
+
+// SetStatusConditions will set the state and the given conditions in the
+// status
+func (vrps *VerticaRestorePointSchedule) SetStatusConditions(state string, conditions []*metav1.Condition) {
+	vrps.Status.State = state
+	for _, condition := range conditions {
+		meta.SetStatusCondition(&vrps.Status.Conditions, *condition)
+	}
+}
+
+func (vusr *VerticaUser) ExtractNamespacedName() types.NamespacedName {
+	return types.NamespacedName{
+		Name:      vusr.ObjectMeta.Name,
+		Namespace: vusr.ObjectMeta.Namespace,
+	}
+}
+
+// FindStatusCondition finds the conditionType in conditions.
+func (vusr *VerticaUser) FindStatusCondition(conditionType string) *metav1.Condition {
+	return meta.FindStatusCondition(vusr.Status.Conditions, conditionType)
+}
+
+func (vusr *VerticaUser) IsStatusConditionTrue(statusCondition string) bool {
+	return meta.IsStatusConditionTrue(vusr.Status.Conditions, statusCondition)
+}
+
+func (vusr *VerticaUser) IsStatusConditionFalse(statusCondition string) bool {
+	return meta.IsStatusConditionFalse(vusr.Status.Conditions, statusCondition)
+}
+
+// SetStatusConditions will set the state and the given conditions in the
+// status
+func (vusr *VerticaUser) SetStatusConditions(state string, conditions []*metav1.Condition) {
+	vusr.Status.State = state
+	for _, condition := range conditions {
+		meta.SetStatusCondition(&vusr.Status.Conditions, *condition)
+	}
+}
+
+// GetUserName returns the name of the user in the database. It defaults to
+// the name of the object.
+func (vusr *VerticaUser) GetUserName() string {
+	if vusr.Spec.UserName != "" {
+		return vusr.Spec.UserName
+	}
+	return vusr.Name
+}
+
+func (vrole *VerticaRole) ExtractNamespacedName() types.NamespacedName {
+	return types.NamespacedName{
+		Name:      vrole.ObjectMeta.Name,
+		Namespace: vrole.ObjectMeta.Namespace,
+	}
+}
+
+// FindStatusCondition finds the conditionType in conditions.
+func (vrole *VerticaRole) FindStatusCondition(conditionType string) *metav1.Condition {
+	return meta.FindStatusCondition(vrole.Status.Conditions, conditionType)
+}
+
+func (vrole *VerticaRole) IsStatusConditionTrue(statusCondition string) bool {
+	return meta.IsStatusConditionTrue(vrole.Status.Conditions, statusCondition)
+}
+
+func (vrole *VerticaRole) IsStatusConditionFalse(statusCondition string) bool {
+	return meta.IsStatusConditionFalse(vrole.Status.Conditions, statusCondition)
+}
+
+// SetStatusConditions will set the state and the given conditions in the
+// status
+func (vrole *VerticaRole) SetStatusConditions(state string, conditions []*metav1.Condition) {
+	vrole.Status.State = state
+	for _, condition := range conditions {
+		meta.SetStatusCondition(&vrole.Status.Conditions, *condition)
+	}
+}
+
+// GetRoleName returns the name of the role in the database. It defaults to
+// the name of the object.
+func (vrole *VerticaRole) GetRoleName() string {
+	if vrole.Spec.RoleName != "" {
+		return vrole.Spec.RoleName
+	}
+	return vrole.Name
+}
+
+func (vrpool *VerticaResourcePool) ExtractNamespacedName() types.NamespacedName {
+	return types.NamespacedName{
+		Name:      vrpool.ObjectMeta.Name,
+		Namespace: vrpool.ObjectMeta.Namespace,
+	}
+}
+
+// FindStatusCondition finds the conditionType in conditions.
+func (vrpool *VerticaResourcePool) FindStatusCondition(conditionType string) *metav1.Condition {
+	return meta.FindStatusCondition(vrpool.Status.Conditions, conditionType)
+}
+
+func (vrpool *VerticaResourcePool) IsStatusConditionTrue(statusCondition string) bool {
+	return meta.IsStatusConditionTrue(vrpool.Status.Conditions, statusCondition)
+}
+
+func (vrpool *VerticaResourcePool) IsStatusConditionFalse(statusCondition string) bool {
+	return meta.IsStatusConditionFalse(vrpool.Status.Conditions, statusCondition)
+}
+
+// SetStatusConditions will set the state and the given conditions in the
+// status
+func (vrpool *VerticaResourcePool) SetStatusConditions(state string, conditions []*metav1.Condition) {
+	vrpool.Status.State = state
+	for _, condition := range conditions {
+		meta.SetStatusCondition(&vrpool.Status.Conditions, *condition)
+	}
+}
+
+// GetPoolName returns the name of the resource pool in the database. It
+// defaults to the name of the object.
+func (vrpool *VerticaResourcePool) GetPoolName() string {
+	if vrpool.Spec.PoolName != "" {
+		return vrpool.Spec.PoolName
+	}
+	return vrpool.Name
+}
+
+// ResourcePoolSettings are the settings of a resource pool that depend on the
+// size of the subcluster it is bound to.
+type ResourcePoolSettings struct {
+	MemorySize         string
+	MaxConcurrency     *int32
+	PlannedConcurrency *int32
+}
+
+// GetScaledSettings returns the pool settings for a subcluster with the given
+// number of pods. If there are no scaling rules, the settings from the spec
+// are returned as is.
+func (vrpool *VerticaResourcePool) GetScaledSettings(scSize int32) (ResourcePoolSettings, error) {
+	settings := ResourcePoolSettings{
+		MemorySize:         vrpool.Spec.MemorySize,
+		MaxConcurrency:     vrpool.Spec.MaxConcurrency,
+		PlannedConcurrency: vrpool.Spec.PlannedConcurrency,
+	}
+	scaling := vrpool.Spec.Scaling
+	if scaling == nil || vrpool.Spec.Subcluster == "" {
+		return settings, nil
+	}
+	podDelta := int64(scSize - scaling.BaseSize)
+	if settings.MaxConcurrency != nil {
+		v := int32(scaleResourcePoolValue(int64(*settings.MaxConcurrency), scaling.MaxConcurrencyPercentPerPod, podDelta))
+		settings.MaxConcurrency = &v
+	}
+	if settings.PlannedConcurrency != nil {
+		v := int32(scaleResourcePoolValue(int64(*settings.PlannedConcurrency), scaling.PlannedConcurrencyPercentPerPod, podDelta))
+		settings.PlannedConcurrency = &v
+	}
+	if settings.MemorySize != "" && scaling.MemorySizePercentPerPod != 0 {
+		kb, err := parseResourcePoolMemorySizeKB(settings.MemorySize)
+		if err != nil {
+			return settings, err
+		}
+		settings.MemorySize = formatResourcePoolMemorySizeKB(
+			scaleResourcePoolValue(kb, scaling.MemorySizePercentPerPod, podDelta))
+	}
+	return settings, nil
+}
+
+// scaleResourcePoolValue will grow the base value by pct percent for each pod
+// in podDelta. The result is never less than 1.
+func scaleResourcePoolValue(base int64, pct int32, podDelta int64) int64 {
+	const percent = 100
+	v := base + base*int64(pct)*podDelta/percent
+	if v < 1 {
+		return 1
+	}
+	return v
+}
+
+// parseResourcePoolMemorySizeKB returns the memory size in kilobytes. It
+// fails if the memory size is a percentage.
+func parseResourcePoolMemorySizeKB(memSize string) (int64, error) {
+	m := ResourcePoolMemorySizeRegex.FindStringSubmatch(memSize)
+	if m == nil || m[2] == "%" {
+		return 0, fmt.Errorf("memory size %q must be an amount with a unit of K, M, G or T", memSize)
+	}
+	v, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	for _, unit := range resourcePoolMemoryUnits {
+		if unit == m[2] {
+			break
+		}
+		v *= 1024
+	}
+	return v, nil
+}
+
+// formatResourcePoolMemorySizeKB will format the kilobytes using the largest
+// unit that represents it exactly.
+func formatResourcePoolMemorySizeKB(kb int64) string {
+	unit := 0
+	for unit < len(resourcePoolMemoryUnits)-1 && kb%1024 == 0 {
+		kb /= 1024
+		unit++
+	}
+	return fmt.Sprintf("%d%s", kb, resourcePoolMemoryUnits[unit])
+}
+
 // GetHPAMetrics extract an return hpa metrics from MetricDefinition struct.
 func (v *VerticaAutoscaler) GetHPAMetrics() []autoscalingv2.MetricSpec {
 	metrics := make([]autoscalingv2.MetricSpec, len(v.Spec.CustomAutoscaler.Hpa.Metrics))
@@ -340,6 +605,163 @@ func (v *VerticaAutoscaler) GetMetricMap() map[string]*MetricDefinition {
 	return mMap
 }
 
+func (vbackup *VerticaBackup) ExtractNamespacedName() types.NamespacedName {
+	return types.NamespacedName{
+		Name:      vbackup.ObjectMeta.Name,
+		Namespace: vbackup.ObjectMeta.Namespace,
+	}
+}
+
+// FindStatusCondition finds the conditionType in conditions.
+func (vbackup *VerticaBackup) FindStatusCondition(conditionType string) *metav1.Condition {
+	return meta.FindStatusCondition(vbackup.Status.Conditions, conditionType)
+}
+
+func (vbackup *VerticaBackup) IsStatusConditionTrue(statusCondition string) bool {
+	return meta.IsStatusConditionTrue(vbackup.Status.Conditions, statusCondition)
+}
+
+func (vbackup *VerticaBackup) IsStatusConditionFalse(statusCondition string) bool {
+	return meta.IsStatusConditionFalse(vbackup.Status.Conditions, statusCondition)
+}
+
+// SetStatusConditions will set the state and the given conditions in the
+// status
+func (vbackup *VerticaBackup) SetStatusConditions(state string, conditions []*metav1.Condition) {
+	vbackup.Status.State = state
+	for _, condition := range conditions {
+		meta.SetStatusCondition(&vbackup.Status.Conditions, *condition)
+	}
+}
+
+// GetSnapshotName returns the name of the snapshot to save or restore. For a
+// backup, it defaults to the name of the object.
+func (vbackup *VerticaBackup) GetSnapshotName() string {
+	if vbackup.Spec.SnapshotName != "" {
+		return vbackup.Spec.SnapshotName
+	}
+	return vbackup.Name
+}
+
+// IsRestore returns true if the VerticaBackup restores a snapshot
+func (vbackup *VerticaBackup) IsRestore() bool {
+	return vbackup.Spec.Operation == BackupOperationRestore
+}
+
+func (vhc *VerticaHealthCheck) ExtractNamespacedName() types.NamespacedName {
+	return types.NamespacedName{
+		Name:      vhc.ObjectMeta.Name,
+		Namespace: vhc.ObjectMeta.Namespace,
+	}
+}
+
+// FindStatusCondition finds the conditionType in conditions.
+func (vhc *VerticaHealthCheck) FindStatusCondition(conditionType string) *metav1.Condition {
+	return meta.FindStatusCondition(vhc.Status.Conditions, conditionType)
+}
+
+func (vhc *VerticaHealthCheck) IsStatusConditionTrue(statusCondition string) bool {
+	return meta.IsStatusConditionTrue(vhc.Status.Conditions, statusCondition)
+}
+
+func (vhc *VerticaHealthCheck) IsStatusConditionFalse(statusCondition string) bool {
+	return meta.IsStatusConditionFalse(vhc.Status.Conditions, statusCondition)
+}
+
+// SetStatusConditions will set the state and the given conditions in the
+// status
+func (vhc *VerticaHealthCheck) SetStatusConditions(state string, conditions []*metav1.Condition) {
+	vhc.Status.State = state
+	for _, condition := range conditions {
+		meta.SetStatusCondition(&vhc.Status.Conditions, *condition)
+	}
+}
+
+// GetInterval returns the time between two health checks
+func (vhc *VerticaHealthCheck) GetInterval() time.Duration {
+	const defaultIntervalSeconds = 300
+	if vhc.Spec.IntervalSeconds <= 0 {
+		return defaultIntervalSeconds * time.Second
+	}
+	return time.Duration(vhc.Spec.IntervalSeconds) * time.Second
+}
+
+// GetLockWaitThreshold returns the duration above which lock waits are
+// reported
+func (vhc *VerticaHealthCheck) GetLockWaitThreshold() time.Duration {
+	const defaultLockWaitThresholdSeconds = 5
+	if vhc.Spec.LockWaitThresholdSeconds <= 0 {
+		return defaultLockWaitThresholdSeconds * time.Second
+	}
+	return time.Duration(vhc.Spec.LockWaitThresholdSeconds) * time.Second
+}
+
+// GetSlowEventThreshold returns the duration above which mutex events are
+// reported
+func (vhc *VerticaHealthCheck) GetSlowEventThreshold() time.Duration {
+	const defaultSlowEventThresholdMilliseconds = 1000
+	if vhc.Spec.SlowEventThresholdMilliseconds <= 0 {
+		return defaultSlowEventThresholdMilliseconds * time.Millisecond
+	}
+	return time.Duration(vhc.Spec.SlowEventThresholdMilliseconds) * time.Millisecond
+}
+
+func (vwr *VerticaWorkloadReplay) ExtractNamespacedName() types.NamespacedName {
+	return types.NamespacedName{
+		Name:      vwr.ObjectMeta.Name,
+		Namespace: vwr.ObjectMeta.Namespace,
+	}
+}
+
+// FindStatusCondition finds the conditionType in conditions.
+func (vwr *VerticaWorkloadReplay) FindStatusCondition(conditionType string) *metav1.Condition {
+	return meta.FindStatusCondition(vwr.Status.Conditions, conditionType)
+}
+
+func (vwr *VerticaWorkloadReplay) IsStatusConditionTrue(statusCondition string) bool {
+	return meta.IsStatusConditionTrue(vwr.Status.Conditions, statusCondition)
+}
+
+func (vwr *VerticaWorkloadReplay) IsStatusConditionFalse(statusCondition string) bool {
+	return meta.IsStatusConditionFalse(vwr.Status.Conditions, statusCondition)
+}
+
+// SetStatusConditions will set the state and the given conditions in the
+// status
+func (vwr *VerticaWorkloadReplay) SetStatusConditions(state string, conditions []*metav1.Condition) {
+	vwr.Status.State = state
+	for _, condition := range conditions {
+		meta.SetStatusCondition(&vwr.Status.Conditions, *condition)
+	}
+}
+
+// GetRegressionThresholdPercent returns how much slower, in percent, a
+// replayed query must be to count as a regression
+func (vwr *VerticaWorkloadReplay) GetRegressionThresholdPercent() int {
+	const defaultRegressionThresholdPercent = 20
+	if vwr.Spec.RegressionThresholdPercent <= 0 {
+		return defaultRegressionThresholdPercent
+	}
+	return vwr.Spec.RegressionThresholdPercent
+}
+
+// GetCaptureObjectKey returns the key, relative to spec.path, of the captured
+// workload in the object store
+func (vwr *VerticaWorkloadReplay) GetCaptureObjectKey() string {
+	return fmt.Sprintf("%s/capture.csv", vwr.Name)
+}
+
+// GetReportObjectKey returns the key, relative to spec.path, of the replay
+// report in the object store
+func (vwr *VerticaWorkloadReplay) GetReportObjectKey() string {
+	return fmt.Sprintf("%s/replay-report.csv", vwr.Name)
+}
+
+// GetObjectPath returns the full s3:// path of a key in spec.path
+func (vwr *VerticaWorkloadReplay) GetObjectPath(key string) string {
+	return fmt.Sprintf("%s/%s", strings.TrimSuffix(vwr.Spec.Path, "/"), key)
+}
+
 func MakeSampleVrpqName() types.NamespacedName {
 	return types.NamespacedName{Name: "vrpq-sample", Namespace: "default"}
 }
@@ -368,6 +790,203 @@ func MakeVrpq() *VerticaRestorePointsQuery {
 	return vrpq
 }
 
+func MakeSampleVrpsName() types.NamespacedName {
+	return types.NamespacedName{Name: "vrps-sample", Namespace: "default"}
+}
+
+// MakeVrps will make a VerticaRestorePointSchedule for test purposes
+func MakeVrps() *VerticaRestorePointSchedule {
+	VDBNm := v1.MakeVDBName()
+	nm := MakeSampleVrpsName()
+	return &VerticaRestorePointSchedule{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: GroupVersion.String(),
+			Kind:       RestorePointScheduleKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nm.Name,
+			Namespace: nm.Namespace,
+			UID:       "zxcvbn-ghi-lkm-rps",
+		},
+		Spec: VerticaRestorePointScheduleSpec{
+			VerticaDBName:    VDBNm.Name,
+			Schedule:         "0 0 * * *",
+			ArchiveName:      archiveNm,
+			NumRestorePoints: 7,
+		},
+	}
+}
+
+func MakeSampleVusrName() types.NamespacedName {
+	return types.NamespacedName{Name: "vusr-sample", Namespace: "default"}
+}
+
+// MakeVusr will make a VerticaUser for test purposes
+func MakeVusr() *VerticaUser {
+	VDBNm := v1.MakeVDBName()
+	nm := MakeSampleVusrName()
+	return &VerticaUser{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: GroupVersion.String(),
+			Kind:       VerticaUserKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nm.Name,
+			Namespace: nm.Namespace,
+			UID:       "zxcvbn-ghi-lkm-usr",
+		},
+		Spec: VerticaUserSpec{
+			VerticaDBName: VDBNm.Name,
+			UserName:      "app_user",
+			Roles:         []string{"app_role"},
+			Grants: []VerticaGrant{
+				{Privileges: []string{"USAGE"}, ObjectType: GrantObjectTypeSchema, ObjectName: "store"},
+			},
+		},
+	}
+}
+
+func MakeSampleVroleName() types.NamespacedName {
+	return types.NamespacedName{Name: "vrole-sample", Namespace: "default"}
+}
+
+// MakeVrole will make a VerticaRole for test purposes
+func MakeVrole() *VerticaRole {
+	VDBNm := v1.MakeVDBName()
+	nm := MakeSampleVroleName()
+	return &VerticaRole{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: GroupVersion.String(),
+			Kind:       VerticaRoleKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nm.Name,
+			Namespace: nm.Namespace,
+			UID:       "zxcvbn-ghi-lkm-role",
+		},
+		Spec: VerticaRoleSpec{
+			VerticaDBName: VDBNm.Name,
+			RoleName:      "app_role",
+			Grants: []VerticaGrant{
+				{Privileges: []string{"SELECT", "INSERT"}, ObjectType: GrantObjectTypeAllTablesInSchema, ObjectName: "store"},
+			},
+		},
+	}
+}
+
+func MakeSampleVrpoolName() types.NamespacedName {
+	return types.NamespacedName{Name: "vrpool-sample", Namespace: "default"}
+}
+
+// MakeVrpool will make a VerticaResourcePool for test purposes
+func MakeVrpool() *VerticaResourcePool {
+	VDBNm := v1.MakeVDBName()
+	nm := MakeSampleVrpoolName()
+	maxConcurrency := int32(4)
+	return &VerticaResourcePool{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: GroupVersion.String(),
+			Kind:       VerticaResourcePoolKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nm.Name,
+			Namespace: nm.Namespace,
+			UID:       "zxcvbn-ghi-lkm-pool",
+		},
+		Spec: VerticaResourcePoolSpec{
+			VerticaDBName:  VDBNm.Name,
+			PoolName:       "etl_pool",
+			Subcluster:     v1.MakeVDB().Spec.Subclusters[0].Name,
+			MemorySize:     "4G",
+			MaxConcurrency: &maxConcurrency,
+		},
+	}
+}
+
+func MakeSampleVbackupName() types.NamespacedName {
+	return types.NamespacedName{Name: "vbackup-sample", Namespace: "default"}
+}
+
+// MakeVbackup will make a VerticaBackup for test purposes
+func MakeVbackup() *VerticaBackup {
+	VDBNm := v1.MakeVDBName()
+	nm := MakeSampleVbackupName()
+	return &VerticaBackup{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: GroupVersion.String(),
+			Kind:       VerticaBackupKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nm.Name,
+			Namespace: nm.Namespace,
+			UID:       "zxcvbn-ghi-lkm-backup",
+		},
+		Spec: VerticaBackupSpec{
+			VerticaDBName: VDBNm.Name,
+			Operation:     BackupOperationBackup,
+			Path:          "s3://backups/vertdb",
+			Endpoint:      "http://minio:9000",
+			Region:        "us-east-1",
+		},
+	}
+}
+
+func MakeSampleVhcName() types.NamespacedName {
+	return types.NamespacedName{Name: "vhc-sample", Namespace: "default"}
+}
+
+// MakeVhc will make a VerticaHealthCheck for test purposes
+func MakeVhc() *VerticaHealthCheck {
+	VDBNm := v1.MakeVDBName()
+	nm := MakeSampleVhcName()
+	return &VerticaHealthCheck{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: GroupVersion.String(),
+			Kind:       VerticaHealthCheckKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nm.Name,
+			Namespace: nm.Namespace,
+			UID:       "zxcvbn-ghi-lkm-health",
+		},
+		Spec: VerticaHealthCheckSpec{
+			VerticaDBName:                  VDBNm.Name,
+			IntervalSeconds:                300,
+			LockWaitThresholdSeconds:       5,
+			SlowEventThresholdMilliseconds: 1000,
+		},
+	}
+}
+
+func MakeSampleVwrName() types.NamespacedName {
+	return types.NamespacedName{Name: "vwr-sample", Namespace: "default"}
+}
+
+// MakeVwr will make a VerticaWorkloadReplay for test purposes
+func MakeVwr() *VerticaWorkloadReplay {
+	VDBNm := v1.MakeVDBName()
+	nm := MakeSampleVwrName()
+	start := time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC)
+	return &VerticaWorkloadReplay{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: GroupVersion.String(),
+			Kind:       VerticaWorkloadReplayKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nm.Name,
+			Namespace: nm.Namespace,
+			UID:       "zxcvbn-ghi-lkm-replay",
+		},
+		Spec: VerticaWorkloadReplaySpec{
+			VerticaDBName:    VDBNm.Name,
+			Sandbox:          "sand",
+			CaptureStartTime: metav1.NewTime(start),
+			CaptureEndTime:   metav1.NewTime(start.Add(time.Hour)),
+			Path:             "s3://bucket/replays",
+		},
+	}
+}
+
 func MakeSampleVrepName() types.NamespacedName {
 	return types.NamespacedName{Name: "vrep-sample", Namespace: "default"}
 }
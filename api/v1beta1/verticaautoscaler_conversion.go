@@ -66,6 +66,9 @@ func convertVasToSpec(src *VerticaAutoscalerSpec) v1.VerticaAutoscalerSpec {
 		TargetSize:         src.TargetSize,
 		CustomAutoscaler:   convertVasToCustomAutoscaler(src.CustomAutoscaler),
 	}
+	if src.ScaleInSafety != nil {
+		dst.ScaleInSafety = (*v1.ScaleInSafetySpec)(src.ScaleInSafety)
+	}
 	return dst
 }
 
@@ -90,6 +93,18 @@ func convertVasFromSpec(src *v1.VerticaAutoscaler) VerticaAutoscalerSpec {
 		if srcSpec.CustomAutoscaler.ScaledObject != nil {
 			dst.CustomAutoscaler.ScaledObject = convertVasFromScaledObjectSpec(srcSpec.CustomAutoscaler.ScaledObject)
 		}
+		if srcSpec.CustomAutoscaler.Schedule != nil {
+			dst.CustomAutoscaler.Schedule = convertVasFromScheduleSpec(srcSpec.CustomAutoscaler.Schedule)
+		}
+		if srcSpec.CustomAutoscaler.Predictive != nil {
+			dst.CustomAutoscaler.Predictive = (*PredictiveSpec)(srcSpec.CustomAutoscaler.Predictive)
+		}
+		if srcSpec.CustomAutoscaler.ScalingPolicy != nil {
+			dst.CustomAutoscaler.ScalingPolicy = convertVasFromScalingPolicySpec(srcSpec.CustomAutoscaler.ScalingPolicy)
+		}
+	}
+	if srcSpec.ScaleInSafety != nil {
+		dst.ScaleInSafety = (*ScaleInSafetySpec)(srcSpec.ScaleInSafety)
 	}
 	return dst
 }
@@ -146,6 +161,50 @@ func convertVasFromScaledObjectSpec(src *v1.ScaledObjectSpec) *ScaledObjectSpec
 				Threshold: srcMetric.Resource.Threshold,
 			}
 		}
+		if srcMetric.Vertica != nil {
+			dst.Metrics[i].Vertica = &VerticaMetricSpec{
+				Metric:           VerticaMetricName(srcMetric.Vertica.Metric),
+				ResourcePool:     srcMetric.Vertica.ResourcePool,
+				LookbackSeconds:  srcMetric.Vertica.LookbackSeconds,
+				Threshold:        srcMetric.Vertica.Threshold,
+				ScaleInThreshold: srcMetric.Vertica.ScaleInThreshold,
+			}
+		}
+	}
+	return dst
+}
+
+// convertVasFromScheduleSpec will convert from a v1 ScheduleSpec to a v1beta1 version
+func convertVasFromScheduleSpec(src *v1.ScheduleSpec) *ScheduleSpec {
+	dst := &ScheduleSpec{
+		TimeZone:          src.TimeZone,
+		Windows:           make([]ScheduleWindow, len(src.Windows)),
+		DefaultTargetSize: src.DefaultTargetSize,
+	}
+	for i := range src.Windows {
+		dst.Windows[i] = ScheduleWindow(src.Windows[i])
+	}
+	return dst
+}
+
+// convertVasFromScalingPolicySpec will convert from a v1 ScalingPolicySpec to a v1beta1 version
+func convertVasFromScalingPolicySpec(src *v1.ScalingPolicySpec) *ScalingPolicySpec {
+	dst := &ScalingPolicySpec{}
+	if src.Weights != nil {
+		dst.Weights = make([]MetricWeight, len(src.Weights))
+		for i := range src.Weights {
+			dst.Weights[i] = MetricWeight(src.Weights[i])
+		}
+	}
+	if src.ScaleIn != nil {
+		dst.ScaleIn = &ScaleInRule{
+			Operator:        ScaleInOperator(src.ScaleIn.Operator),
+			Conditions:      make([]ScaleInCondition, len(src.ScaleIn.Conditions)),
+			DurationSeconds: src.ScaleIn.DurationSeconds,
+		}
+		for i := range src.ScaleIn.Conditions {
+			dst.ScaleIn.Conditions[i] = ScaleInCondition(src.ScaleIn.Conditions[i])
+		}
 	}
 	return dst
 }
@@ -153,10 +212,12 @@ func convertVasFromScaledObjectSpec(src *v1.ScaledObjectSpec) *ScaledObjectSpec
 // convertToVasStatus will convert to a v1 VerticaAutoscalerStatus from a v1beta1 version
 func convertToVasStatus(src *VerticaAutoscalerStatus) v1.VerticaAutoscalerStatus {
 	dst := v1.VerticaAutoscalerStatus{
-		ScalingCount: src.ScalingCount,
-		CurrentSize:  src.CurrentSize,
-		Selector:     src.Selector,
-		Conditions:   make([]v1.VerticaAutoscalerCondition, len(src.Conditions)),
+		ScalingCount:         src.ScalingCount,
+		CurrentSize:          src.CurrentSize,
+		Selector:             src.Selector,
+		ActiveScheduleWindow: src.ActiveScheduleWindow,
+		ScheduledSize:        src.ScheduledSize,
+		Conditions:           make([]v1.VerticaAutoscalerCondition, len(src.Conditions)),
 	}
 	for i := range src.Conditions {
 		srcMetric := &src.Conditions[i]
@@ -166,6 +227,37 @@ func convertToVasStatus(src *VerticaAutoscalerStatus) v1.VerticaAutoscalerStatus
 			LastTransitionTime: srcMetric.LastTransitionTime,
 		}
 	}
+	if src.VerticaMetrics != nil {
+		dst.VerticaMetrics = make([]v1.VerticaMetricStatus, len(src.VerticaMetrics))
+		for i := range src.VerticaMetrics {
+			dst.VerticaMetrics[i] = v1.VerticaMetricStatus(src.VerticaMetrics[i])
+		}
+	}
+	if src.Forecast != nil {
+		dst.Forecast = (*v1.PredictiveForecast)(src.Forecast)
+	}
+	if src.ScaleInVictims != nil {
+		dst.ScaleInVictims = make([]v1.ScaleInVictim, len(src.ScaleInVictims))
+		for i := range src.ScaleInVictims {
+			dst.ScaleInVictims[i] = v1.ScaleInVictim(src.ScaleInVictims[i])
+		}
+	}
+	if src.PolicyEvaluation != nil {
+		dst.PolicyEvaluation = &v1.ScalingPolicyEvaluation{
+			EvaluationTime:            src.PolicyEvaluation.EvaluationTime,
+			WeightedRatioPercent:      src.PolicyEvaluation.WeightedRatioPercent,
+			ScaleInConditionsMet:      src.PolicyEvaluation.ScaleInConditionsMet,
+			ScaleInConditionsMetSince: src.PolicyEvaluation.ScaleInConditionsMetSince,
+			DesiredSize:               src.PolicyEvaluation.DesiredSize,
+			Reason:                    src.PolicyEvaluation.Reason,
+		}
+		if src.PolicyEvaluation.Metrics != nil {
+			dst.PolicyEvaluation.Metrics = make([]v1.PolicyMetricEvaluation, len(src.PolicyEvaluation.Metrics))
+			for i := range src.PolicyEvaluation.Metrics {
+				dst.PolicyEvaluation.Metrics[i] = v1.PolicyMetricEvaluation(src.PolicyEvaluation.Metrics[i])
+			}
+		}
+	}
 	return dst
 }
 
@@ -183,6 +275,50 @@ func convertVasToCustomAutoscaler(src *CustomAutoscalerSpec) *v1.CustomAutoscale
 	if src.ScaledObject != nil {
 		dst.ScaledObject = convertVasToScaledObjectSpec(src.ScaledObject)
 	}
+	if src.Schedule != nil {
+		dst.Schedule = convertVasToScheduleSpec(src.Schedule)
+	}
+	if src.Predictive != nil {
+		dst.Predictive = (*v1.PredictiveSpec)(src.Predictive)
+	}
+	if src.ScalingPolicy != nil {
+		dst.ScalingPolicy = convertVasToScalingPolicySpec(src.ScalingPolicy)
+	}
+	return dst
+}
+
+// convertVasToScheduleSpec will convert a v1beta1 ScheduleSpec to v1 version
+func convertVasToScheduleSpec(src *ScheduleSpec) *v1.ScheduleSpec {
+	dst := &v1.ScheduleSpec{
+		TimeZone:          src.TimeZone,
+		Windows:           make([]v1.ScheduleWindow, len(src.Windows)),
+		DefaultTargetSize: src.DefaultTargetSize,
+	}
+	for i := range src.Windows {
+		dst.Windows[i] = v1.ScheduleWindow(src.Windows[i])
+	}
+	return dst
+}
+
+// convertVasToScalingPolicySpec will convert a v1beta1 ScalingPolicySpec to v1 version
+func convertVasToScalingPolicySpec(src *ScalingPolicySpec) *v1.ScalingPolicySpec {
+	dst := &v1.ScalingPolicySpec{}
+	if src.Weights != nil {
+		dst.Weights = make([]v1.MetricWeight, len(src.Weights))
+		for i := range src.Weights {
+			dst.Weights[i] = v1.MetricWeight(src.Weights[i])
+		}
+	}
+	if src.ScaleIn != nil {
+		dst.ScaleIn = &v1.ScaleInRule{
+			Operator:        v1.ScaleInOperator(src.ScaleIn.Operator),
+			Conditions:      make([]v1.ScaleInCondition, len(src.ScaleIn.Conditions)),
+			DurationSeconds: src.ScaleIn.DurationSeconds,
+		}
+		for i := range src.ScaleIn.Conditions {
+			dst.ScaleIn.Conditions[i] = v1.ScaleInCondition(src.ScaleIn.Conditions[i])
+		}
+	}
 	return dst
 }
 
@@ -238,6 +374,15 @@ func convertVasToScaledObjectSpec(src *ScaledObjectSpec) *v1.ScaledObjectSpec {
 				Threshold: srcMetric.Resource.Threshold,
 			}
 		}
+		if srcMetric.Vertica != nil {
+			dst.Metrics[i].Vertica = &v1.VerticaMetricSpec{
+				Metric:           v1.VerticaMetricName(srcMetric.Vertica.Metric),
+				ResourcePool:     srcMetric.Vertica.ResourcePool,
+				LookbackSeconds:  srcMetric.Vertica.LookbackSeconds,
+				Threshold:        srcMetric.Vertica.Threshold,
+				ScaleInThreshold: srcMetric.Vertica.ScaleInThreshold,
+			}
+		}
 	}
 	return dst
 }
@@ -245,10 +390,12 @@ func convertVasToScaledObjectSpec(src *ScaledObjectSpec) *v1.ScaledObjectSpec {
 // convertVasFromStatus will convert from a v1 VerticaAutoscalerStatus to a v1beta1 version
 func convertVasFromStatus(src *v1.VerticaAutoscalerStatus) VerticaAutoscalerStatus {
 	dst := VerticaAutoscalerStatus{
-		ScalingCount: src.ScalingCount,
-		CurrentSize:  src.CurrentSize,
-		Selector:     src.Selector,
-		Conditions:   make([]VerticaAutoscalerCondition, len(src.Conditions)),
+		ScalingCount:         src.ScalingCount,
+		CurrentSize:          src.CurrentSize,
+		Selector:             src.Selector,
+		ActiveScheduleWindow: src.ActiveScheduleWindow,
+		ScheduledSize:        src.ScheduledSize,
+		Conditions:           make([]VerticaAutoscalerCondition, len(src.Conditions)),
 	}
 	for i := range src.Conditions {
 		srcMetric := &src.Conditions[i]
@@ -258,6 +405,37 @@ func convertVasFromStatus(src *v1.VerticaAutoscalerStatus) VerticaAutoscalerStat
 			LastTransitionTime: srcMetric.LastTransitionTime,
 		}
 	}
+	if src.VerticaMetrics != nil {
+		dst.VerticaMetrics = make([]VerticaMetricStatus, len(src.VerticaMetrics))
+		for i := range src.VerticaMetrics {
+			dst.VerticaMetrics[i] = VerticaMetricStatus(src.VerticaMetrics[i])
+		}
+	}
+	if src.Forecast != nil {
+		dst.Forecast = (*PredictiveForecast)(src.Forecast)
+	}
+	if src.ScaleInVictims != nil {
+		dst.ScaleInVictims = make([]ScaleInVictim, len(src.ScaleInVictims))
+		for i := range src.ScaleInVictims {
+			dst.ScaleInVictims[i] = ScaleInVictim(src.ScaleInVictims[i])
+		}
+	}
+	if src.PolicyEvaluation != nil {
+		dst.PolicyEvaluation = &ScalingPolicyEvaluation{
+			EvaluationTime:            src.PolicyEvaluation.EvaluationTime,
+			WeightedRatioPercent:      src.PolicyEvaluation.WeightedRatioPercent,
+			ScaleInConditionsMet:      src.PolicyEvaluation.ScaleInConditionsMet,
+			ScaleInConditionsMetSince: src.PolicyEvaluation.ScaleInConditionsMetSince,
+			DesiredSize:               src.PolicyEvaluation.DesiredSize,
+			Reason:                    src.PolicyEvaluation.Reason,
+		}
+		if src.PolicyEvaluation.Metrics != nil {
+			dst.PolicyEvaluation.Metrics = make([]PolicyMetricEvaluation, len(src.PolicyEvaluation.Metrics))
+			for i := range src.PolicyEvaluation.Metrics {
+				dst.PolicyEvaluation.Metrics[i] = PolicyMetricEvaluation(src.PolicyEvaluation.Metrics[i])
+			}
+		}
+	}
 	return dst
 }
 
@@ -150,4 +150,34 @@ var _ = Describe("verticaautoscaler_conversion", func() {
 		Ω(v1beta1VAS.ConvertFrom(&v1VAS)).Should(Succeed())
 		Ω(v1beta1VAS.Spec.CustomAutoscaler.ScaledObject.Metrics[0].Prometheus.UseCachedMetrics).Should(BeFalse())
 	})
+
+	It("should convert ScheduleSpec between v1beta1 and v1", func() {
+		defaultSize := int32(3)
+		v1beta1VAS := MakeVAS()
+		v1beta1VAS.Spec.CustomAutoscaler = &CustomAutoscalerSpec{
+			Type: Schedule,
+			Schedule: &ScheduleSpec{
+				TimeZone:          "UTC",
+				DefaultTargetSize: &defaultSize,
+				Windows: []ScheduleWindow{
+					{Name: "business", Start: "0 8 * * 1-5", End: "0 18 * * 1-5", SubclusterCount: 2},
+				},
+			},
+		}
+		v1beta1VAS.Status.ActiveScheduleWindow = "business"
+		v1beta1VAS.Status.ScheduledSize = 6
+		v1VAS := v1.VerticaAutoscaler{}
+
+		Ω(v1beta1VAS.ConvertTo(&v1VAS)).Should(Succeed())
+		Ω(v1VAS.Spec.CustomAutoscaler.Type).Should(Equal(v1.Schedule))
+		Ω(*v1VAS.Spec.CustomAutoscaler.Schedule.DefaultTargetSize).Should(Equal(defaultSize))
+		Ω(v1VAS.Spec.CustomAutoscaler.Schedule.Windows[0].SubclusterCount).Should(Equal(int32(2)))
+		Ω(v1VAS.Status.ActiveScheduleWindow).Should(Equal("business"))
+		Ω(v1VAS.Status.ScheduledSize).Should(Equal(int32(6)))
+
+		v1VAS.Spec.CustomAutoscaler.Schedule.Windows[0].End = "0 20 * * 1-5"
+		Ω(v1beta1VAS.ConvertFrom(&v1VAS)).Should(Succeed())
+		Ω(v1beta1VAS.Spec.CustomAutoscaler.Schedule.Windows[0].End).Should(Equal("0 20 * * 1-5"))
+		Ω(v1beta1VAS.Spec.CustomAutoscaler.Schedule.TimeZone).Should(Equal("UTC"))
+	})
 })
@@ -0,0 +1,197 @@
+/*
+Copyright [2021-2024] Open Text.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// BackupOperationBackup takes a snapshot of the database and uploads it
+	BackupOperationBackup = "Backup"
+	// BackupOperationRestore copies a snapshot back into the database pods
+	BackupOperationRestore = "Restore"
+)
+
+// VerticaBackupSpec defines the desired state of VerticaBackup
+type VerticaBackupSpec struct {
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The name of the VerticaDB CR to back up or restore. The VerticaDB object
+	// must exist in the same namespace as this object and must be an
+	// Enterprise mode database.
+	VerticaDBName string `json:"verticaDBName"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=Backup
+	// +kubebuilder:validation:Enum=Backup;Restore
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:select:Backup","urn:alm:descriptor:com.tectonic.ui:select:Restore"
+	// The operation to run. Backup snapshots the catalog and data of every
+	// pod and uploads it. Restore copies a snapshot back into the pods. A
+	// restore requires the database to be stopped and autoRestartVertica to
+	// be false in the VerticaDB.
+	Operation string `json:"operation,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The S3 compatible location where snapshots are stored. It must start
+	// with s3://. Files are stored once by content hash and shared across all
+	// snapshots in this location, so each backup only uploads the files that
+	// changed since the previous one. Each VerticaDB should have its own path.
+	Path string `json:"path"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The URL of the object store endpoint, such as https://minio:9000. If
+	// omitted, the AWS endpoint for the region is used.
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:="us-east-1"
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The region of the bucket
+	Region string `json:"region,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:io.kubernetes:Secret"
+	// The name of a secret that contains the credentials to access the object
+	// store. It must have the keys accesskey and secretkey. If omitted, the
+	// default AWS credential chain of the operator is used.
+	CredentialSecret string `json:"credentialSecret,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The name of the snapshot. For a backup, it defaults to the name of this
+	// object. For a restore, it is required and must name an existing
+	// snapshot in the path.
+	SnapshotName string `json:"snapshotName,omitempty"`
+}
+
+// VerticaBackupStatus defines the observed state of VerticaBackup
+type VerticaBackupStatus struct {
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// Conditions for VerticaBackup
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// Status message for the backup or restore
+	State string `json:"state,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The name of the snapshot that was saved or restored
+	SnapshotName string `json:"snapshotName,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The time the operation started
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The time the operation completed
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The number of files in the snapshot
+	TotalFiles int `json:"totalFiles"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The number of files that had to be copied. Files that were already in
+	// the object store, or already in the pod for a restore, are skipped.
+	TransferredFiles int `json:"transferredFiles"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The number of bytes that were copied
+	TransferredBytes int64 `json:"transferredBytes"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// Per pod details of the operation. Every pod is listed when the
+	// operation starts and the pods are processed one at a time, so this shows
+	// how far along the operation is.
+	Nodes []VerticaBackupNodeStatus `json:"nodes,omitempty"`
+}
+
+// VerticaBackupNodeStatus has the details of the backup or restore of one pod
+type VerticaBackupNodeStatus struct {
+	// The name of the pod
+	PodName string `json:"podName"`
+	// The vertica node name of the pod
+	VNodeName string `json:"vnodeName,omitempty"`
+	// The state of the operation for this pod. It is Pending until the files
+	// of the pod have been copied, then Complete.
+	State string `json:"state"`
+	// The number of files in the snapshot of this pod
+	TotalFiles int `json:"totalFiles"`
+	// The number of files that had to be copied
+	TransferredFiles int `json:"transferredFiles"`
+	// The number of bytes that were copied
+	TransferredBytes int64 `json:"transferredBytes"`
+}
+
+const (
+	// BackupNodePending is the state of a pod that hasn't been processed yet
+	BackupNodePending = "Pending"
+	// BackupNodeComplete is the state of a pod whose files have been copied
+	BackupNodeComplete = "Complete"
+)
+
+const (
+	// BackupReady indicates whether the referenced VerticaDB supports
+	// VerticaBackup
+	BackupReady = "BackupReady"
+	// BackupComplete indicates the operation has finished. It is true if it
+	// succeeded and false if it failed.
+	BackupComplete = "BackupComplete"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories=vertica,shortName=vbackup
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="VerticaDB",type="string",JSONPath=".spec.verticaDBName"
+// +kubebuilder:printcolumn:name="Operation",type="string",JSONPath=".spec.operation"
+// +kubebuilder:printcolumn:name="Snapshot",type="string",JSONPath=".status.snapshotName"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Transferred",type="integer",JSONPath=".status.transferredFiles"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +operator-sdk:csv:customresourcedefinitions:resources={{VerticaDB,vertica.com/v1,""}}
+
+// VerticaBackup is the Schema for the verticabackups API
+type VerticaBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VerticaBackupSpec   `json:"spec,omitempty"`
+	Status VerticaBackupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VerticaBackupList contains a list of VerticaBackup
+type VerticaBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VerticaBackup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VerticaBackup{}, &VerticaBackupList{})
+}
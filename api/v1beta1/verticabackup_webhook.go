@@ -0,0 +1,134 @@
+/*
+Copyright [2021-2024] Open Text.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const s3Prefix = "s3://"
+
+// snapshotNameRegex restricts snapshot names to characters that are safe in
+// an object key and a file name
+var snapshotNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// log is for logging in this package.
+var verticabackuplog = logf.Log.WithName("verticabackup-resource")
+
+func (vbackup *VerticaBackup) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(vbackup).
+		Complete()
+}
+
+var _ webhook.Defaulter = &VerticaBackup{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (vbackup *VerticaBackup) Default() {
+	verticabackuplog.Info("default", "name", vbackup.Name)
+}
+
+var _ webhook.Validator = &VerticaBackup{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (vbackup *VerticaBackup) ValidateCreate() (admission.Warnings, error) {
+	verticabackuplog.Info("validate create", "name", vbackup.Name)
+
+	allErrs := vbackup.validateVbackupSpec()
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+	return nil, apierrors.NewInvalid(GkVBACKUP, vbackup.Name, allErrs)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (vbackup *VerticaBackup) ValidateUpdate(oldObj runtime.Object) (admission.Warnings, error) {
+	verticabackuplog.Info("validate update", "name", vbackup.Name)
+
+	allErrs := vbackup.validateVbackupSpec()
+	old := oldObj.(*VerticaBackup)
+	allErrs = vbackup.validateImmutableFields(old, allErrs)
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+	return nil, apierrors.NewInvalid(GkVBACKUP, vbackup.Name, allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (vbackup *VerticaBackup) ValidateDelete() (admission.Warnings, error) {
+	verticabackuplog.Info("validate delete", "name", vbackup.Name)
+	return nil, nil
+}
+
+// validateVbackupSpec will validate the current VerticaBackup to see if it is valid
+func (vbackup *VerticaBackup) validateVbackupSpec() field.ErrorList {
+	allErrs := vbackup.validatePath(field.ErrorList{})
+	allErrs = vbackup.validateSnapshotName(allErrs)
+	return allErrs
+}
+
+// validatePath will make sure the path points to a bucket in an S3
+// compatible object store
+func (vbackup *VerticaBackup) validatePath(allErrs field.ErrorList) field.ErrorList {
+	pathPrefix := field.NewPath("spec").Child("path")
+	if !strings.HasPrefix(vbackup.Spec.Path, s3Prefix) {
+		return append(allErrs, field.Invalid(pathPrefix, vbackup.Spec.Path,
+			"path must start with s3://"))
+	}
+	if strings.Trim(strings.TrimPrefix(vbackup.Spec.Path, s3Prefix), "/") == "" {
+		allErrs = append(allErrs, field.Invalid(pathPrefix, vbackup.Spec.Path,
+			"path must include a bucket name"))
+	}
+	return allErrs
+}
+
+// validateSnapshotName will check the snapshot name. It is required for a
+// restore.
+func (vbackup *VerticaBackup) validateSnapshotName(allErrs field.ErrorList) field.ErrorList {
+	pathPrefix := field.NewPath("spec").Child("snapshotName")
+	if vbackup.IsRestore() && vbackup.Spec.SnapshotName == "" {
+		return append(allErrs, field.Required(pathPrefix,
+			"snapshotName must be set to restore a snapshot"))
+	}
+	if !snapshotNameRegex.MatchString(vbackup.GetSnapshotName()) {
+		allErrs = append(allErrs, field.Invalid(pathPrefix, vbackup.GetSnapshotName(),
+			"snapshotName can only contain letters, digits, '_', '.' and '-'"))
+	}
+	return allErrs
+}
+
+// validateImmutableFields will prevent any change to the spec. A
+// VerticaBackup runs once, so a new object must be created for another
+// backup or restore.
+func (vbackup *VerticaBackup) validateImmutableFields(old *VerticaBackup,
+	allErrs field.ErrorList) field.ErrorList {
+	if !reflect.DeepEqual(vbackup.Spec, old.Spec) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"),
+			"spec cannot change after creation. Create a new VerticaBackup instead"))
+	}
+	return allErrs
+}
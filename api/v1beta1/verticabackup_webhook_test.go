@@ -0,0 +1,69 @@
+/*
+Copyright [2021-2024] Open Text.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("verticabackup_webhook", func() {
+	It("should succeed with default fields", func() {
+		vbackup := MakeVbackup()
+		_, err := vbackup.ValidateCreate()
+		Expect(err).Should(Succeed())
+		_, err = vbackup.ValidateUpdate(vbackup)
+		Expect(err).Should(Succeed())
+	})
+
+	It("should fail if the path is not an s3 path", func() {
+		vbackup := MakeVbackup()
+		vbackup.Spec.Path = "gs://bucket/db"
+		_, err := vbackup.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("path must start with s3://"))
+
+		vbackup.Spec.Path = "s3:///"
+		_, err = vbackup.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("path must include a bucket name"))
+	})
+
+	It("should require a snapshot name for a restore", func() {
+		vbackup := MakeVbackup()
+		vbackup.Spec.Operation = BackupOperationRestore
+		_, err := vbackup.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("snapshotName must be set to restore a snapshot"))
+
+		vbackup.Spec.SnapshotName = "nightly"
+		_, err = vbackup.ValidateCreate()
+		Expect(err).Should(Succeed())
+	})
+
+	It("should fail for an invalid snapshot name", func() {
+		vbackup := MakeVbackup()
+		vbackup.Spec.SnapshotName = "../nightly"
+		_, err := vbackup.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("snapshotName can only contain"))
+	})
+
+	It("should not allow the spec to change", func() {
+		oldVbackup := MakeVbackup()
+		vbackup := MakeVbackup()
+		vbackup.Spec.SnapshotName = "other"
+		_, err := vbackup.ValidateUpdate(oldVbackup)
+		Expect(err.Error()).To(ContainSubstring("spec cannot change after creation"))
+	})
+})
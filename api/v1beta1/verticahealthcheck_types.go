@@ -0,0 +1,188 @@
+/*
+Copyright [2021-2024] Open Text.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// HealthFindingLockWait is a series of lock waits on a node that lasted
+	// longer than the lock wait threshold
+	HealthFindingLockWait = "LockWait"
+	// HealthFindingSlowEvent is a mutex event that was slower than the slow
+	// event threshold
+	HealthFindingSlowEvent = "SlowEvent"
+	// HealthFindingMissingRelease is a lock that was granted but never released
+	HealthFindingMissingRelease = "MissingLockRelease"
+)
+
+// VerticaHealthCheckSpec defines the desired state of VerticaHealthCheck
+type VerticaHealthCheckSpec struct {
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The name of the VerticaDB CR to monitor. The VerticaDB object must exist
+	// in the same namespace as this object and must be deployed with
+	// vclusterops.
+	VerticaDBName string `json:"verticaDBName"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=300
+	// +kubebuilder:validation:Minimum:=30
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	// The number of seconds between two health checks. Each check analyzes
+	// the events that happened since the previous one.
+	IntervalSeconds int `json:"intervalSeconds,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=5
+	// +kubebuilder:validation:Minimum:=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	// A series of lock waits on a node that lasts longer than this number of
+	// seconds is reported as a finding. This is also the threshold used to
+	// look for locks that were never released.
+	LockWaitThresholdSeconds int `json:"lockWaitThresholdSeconds,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=1000
+	// +kubebuilder:validation:Minimum:=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	// A mutex event that takes longer than this number of milliseconds is
+	// reported as a finding.
+	SlowEventThresholdMilliseconds int `json:"slowEventThresholdMilliseconds,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	// If true, no health checks are run until this is set back to false.
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// VerticaHealthCheckStatus defines the observed state of VerticaHealthCheck
+type VerticaHealthCheckStatus struct {
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// Conditions for VerticaHealthCheck
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// Status message for the health check
+	State string `json:"state,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The last time a health check was run
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The next time a health check is going to be run
+	NextCheckTime *metav1.Time `json:"nextCheckTime,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The number of lock wait events, over the threshold, found by the last
+	// health check
+	LockWaitEvents int `json:"lockWaitEvents"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The longest series of lock waits, in milliseconds, found by the last
+	// health check
+	MaxLockWaitMilliseconds int64 `json:"maxLockWaitMilliseconds"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The number of slow mutex events found by the last health check
+	SlowEvents int `json:"slowEvents"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The duration, in milliseconds, of the slowest mutex event found by the
+	// last health check
+	MaxSlowEventMilliseconds int64 `json:"maxSlowEventMilliseconds"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The number of locks that were not released found by the last health
+	// check
+	MissingLockReleases int `json:"missingLockReleases"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The most significant findings of the last health check, longest first
+	Findings []VerticaHealthCheckFinding `json:"findings,omitempty"`
+}
+
+// VerticaHealthCheckFinding is one problem found by a health check
+type VerticaHealthCheckFinding struct {
+	// The kind of finding. One of LockWait, SlowEvent or MissingLockRelease.
+	Type string `json:"type"`
+	// The vertica node the finding was seen on
+	NodeName string `json:"nodeName,omitempty"`
+	// The time the event started
+	Time string `json:"time,omitempty"`
+	// How long the event lasted, in milliseconds
+	DurationMilliseconds int64 `json:"durationMilliseconds"`
+	// A description of the event, such as the lock or mutex involved
+	Description string `json:"description,omitempty"`
+	// The session that caused the event, if known
+	SessionID string `json:"sessionID,omitempty"`
+	// The transaction that caused the event, if known
+	TransactionID string `json:"transactionID,omitempty"`
+}
+
+const (
+	// HealthCheckReady indicates whether the referenced VerticaDB supports
+	// health checks and the checks are active
+	HealthCheckReady = "HealthCheckReady"
+	// Healthy reflects the outcome of the most recent health check. It is
+	// false if findings over the thresholds were found or if the check
+	// itself failed.
+	Healthy = "Healthy"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories=vertica,shortName=vhc
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="VerticaDB",type="string",JSONPath=".spec.verticaDBName"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="LockWaits",type="integer",JSONPath=".status.lockWaitEvents"
+// +kubebuilder:printcolumn:name="SlowEvents",type="integer",JSONPath=".status.slowEvents"
+// +kubebuilder:printcolumn:name="LastCheck",type="date",JSONPath=".status.lastCheckTime"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +operator-sdk:csv:customresourcedefinitions:resources={{VerticaDB,vertica.com/v1,""}}
+
+// VerticaHealthCheck is the Schema for the verticahealthchecks API
+type VerticaHealthCheck struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VerticaHealthCheckSpec   `json:"spec,omitempty"`
+	Status VerticaHealthCheckStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VerticaHealthCheckList contains a list of VerticaHealthCheck
+type VerticaHealthCheckList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VerticaHealthCheck `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VerticaHealthCheck{}, &VerticaHealthCheckList{})
+}
@@ -0,0 +1,124 @@
+/*
+Copyright [2021-2024] Open Text.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// MinHealthCheckIntervalSeconds is the shortest interval allowed between two
+// health checks. Each check queries the data collector tables on every up
+// node, so we don't want it to run back to back.
+const MinHealthCheckIntervalSeconds = 30
+
+// log is for logging in this package.
+var verticahealthchecklog = logf.Log.WithName("verticahealthcheck-resource")
+
+func (vhc *VerticaHealthCheck) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(vhc).
+		Complete()
+}
+
+var _ webhook.Defaulter = &VerticaHealthCheck{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (vhc *VerticaHealthCheck) Default() {
+	verticahealthchecklog.Info("default", "name", vhc.Name)
+}
+
+var _ webhook.Validator = &VerticaHealthCheck{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (vhc *VerticaHealthCheck) ValidateCreate() (admission.Warnings, error) {
+	verticahealthchecklog.Info("validate create", "name", vhc.Name)
+
+	allErrs := vhc.validateVhcSpec()
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+	return nil, apierrors.NewInvalid(GkVHC, vhc.Name, allErrs)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (vhc *VerticaHealthCheck) ValidateUpdate(oldObj runtime.Object) (admission.Warnings, error) {
+	verticahealthchecklog.Info("validate update", "name", vhc.Name)
+
+	allErrs := vhc.validateVhcSpec()
+	old := oldObj.(*VerticaHealthCheck)
+	allErrs = vhc.validateImmutableFields(old, allErrs)
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+	return nil, apierrors.NewInvalid(GkVHC, vhc.Name, allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (vhc *VerticaHealthCheck) ValidateDelete() (admission.Warnings, error) {
+	verticahealthchecklog.Info("validate delete", "name", vhc.Name)
+	return nil, nil
+}
+
+// validateVhcSpec will validate the current VerticaHealthCheck to see if it is valid
+func (vhc *VerticaHealthCheck) validateVhcSpec() field.ErrorList {
+	allErrs := vhc.validateInterval(field.ErrorList{})
+	allErrs = vhc.validateThresholds(allErrs)
+	return allErrs
+}
+
+// validateInterval will make sure the checks are not run too often
+func (vhc *VerticaHealthCheck) validateInterval(allErrs field.ErrorList) field.ErrorList {
+	if vhc.Spec.IntervalSeconds != 0 && vhc.Spec.IntervalSeconds < MinHealthCheckIntervalSeconds {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("intervalSeconds"),
+			vhc.Spec.IntervalSeconds,
+			fmt.Sprintf("intervalSeconds must be at least %d", MinHealthCheckIntervalSeconds)))
+	}
+	return allErrs
+}
+
+// validateThresholds will check that the thresholds are not negative. A
+// value of 0 means the default is used.
+func (vhc *VerticaHealthCheck) validateThresholds(allErrs field.ErrorList) field.ErrorList {
+	pathPrefix := field.NewPath("spec")
+	if vhc.Spec.LockWaitThresholdSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(pathPrefix.Child("lockWaitThresholdSeconds"),
+			vhc.Spec.LockWaitThresholdSeconds, "lockWaitThresholdSeconds cannot be negative"))
+	}
+	if vhc.Spec.SlowEventThresholdMilliseconds < 0 {
+		allErrs = append(allErrs, field.Invalid(pathPrefix.Child("slowEventThresholdMilliseconds"),
+			vhc.Spec.SlowEventThresholdMilliseconds, "slowEventThresholdMilliseconds cannot be negative"))
+	}
+	return allErrs
+}
+
+// validateImmutableFields will prevent changing the VerticaDB a health check refers to
+func (vhc *VerticaHealthCheck) validateImmutableFields(old *VerticaHealthCheck,
+	allErrs field.ErrorList) field.ErrorList {
+	if vhc.Spec.VerticaDBName != old.Spec.VerticaDBName {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("verticaDBName"),
+			vhc.Spec.VerticaDBName, "verticaDBName cannot change after creation"))
+	}
+	return allErrs
+}
@@ -0,0 +1,71 @@
+/*
+Copyright [2021-2024] Open Text.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("verticahealthcheck_webhook", func() {
+	It("should succeed with default fields", func() {
+		vhc := MakeVhc()
+		_, err := vhc.ValidateCreate()
+		Expect(err).Should(Succeed())
+		_, err = vhc.ValidateUpdate(vhc)
+		Expect(err).Should(Succeed())
+	})
+
+	It("should fail if the interval is too short", func() {
+		vhc := MakeVhc()
+		vhc.Spec.IntervalSeconds = 10
+		_, err := vhc.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("intervalSeconds must be at least 30"))
+	})
+
+	It("should fail if a threshold is negative", func() {
+		vhc := MakeVhc()
+		vhc.Spec.LockWaitThresholdSeconds = -1
+		_, err := vhc.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("lockWaitThresholdSeconds cannot be negative"))
+
+		vhc = MakeVhc()
+		vhc.Spec.SlowEventThresholdMilliseconds = -1
+		_, err = vhc.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("slowEventThresholdMilliseconds cannot be negative"))
+	})
+
+	It("should not allow the VerticaDB name to change", func() {
+		oldVhc := MakeVhc()
+		vhc := MakeVhc()
+		vhc.Spec.VerticaDBName = "other-db"
+		_, err := vhc.ValidateUpdate(oldVhc)
+		Expect(err.Error()).To(ContainSubstring("verticaDBName cannot change after creation"))
+	})
+
+	It("should fall back to the defaults when the spec is not set", func() {
+		vhc := MakeVhc()
+		vhc.Spec.IntervalSeconds = 0
+		vhc.Spec.LockWaitThresholdSeconds = 0
+		vhc.Spec.SlowEventThresholdMilliseconds = 0
+		Expect(vhc.GetInterval()).Should(Equal(5 * time.Minute))
+		Expect(vhc.GetLockWaitThreshold()).Should(Equal(5 * time.Second))
+		Expect(vhc.GetSlowEventThreshold()).Should(Equal(time.Second))
+	})
+})
@@ -0,0 +1,186 @@
+/*
+Copyright [2021-2024] Open Text.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VerticaResourcePoolSpec defines the desired state of VerticaResourcePool
+type VerticaResourcePoolSpec struct {
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The name of the VerticaDB CR that the resource pool is created in. The
+	// VerticaDB object must exist in the same namespace as this object.
+	VerticaDBName string `json:"verticaDBName"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The name of the resource pool in the database. If omitted, the name of
+	// this object is used. This cannot change after creation.
+	PoolName string `json:"poolName,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The name of a subcluster, from spec.subclusters in the VerticaDB, that
+	// the pool is created for. The pool then only exists on the nodes of that
+	// subcluster. If omitted, the pool is global. This cannot change after
+	// creation.
+	Subcluster string `json:"subcluster,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The amount of memory, per node, that is reserved for the pool. It is an
+	// integer followed by a unit of K, M, G or T (e.g. 4G), or a percentage of
+	// the node's memory (e.g. 20%). If omitted, the database default is used.
+	MemorySize string `json:"memorySize,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=0
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	// The maximum number of queries that can run concurrently in the pool. If
+	// omitted, there is no limit.
+	MaxConcurrency *int32 `json:"maxConcurrency,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	// The number of queries that the pool is expected to run concurrently. It
+	// controls how much memory each query is given at startup. If omitted, it
+	// is computed automatically by the database.
+	PlannedConcurrency *int32 `json:"plannedConcurrency,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=-100
+	// +kubebuilder:validation:Maximum:=100
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	// The priority of queries in this pool when they compete for resources
+	// with other pools. It ranges from -100 to 100.
+	Priority *int32 `json:"priority,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=0
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	// The number of seconds a query waits for resources before it is
+	// rejected. If omitted, queries wait indefinitely.
+	QueueTimeout *int32 `json:"queueTimeout,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// Rules that change the size of the pool as the subcluster it is bound to
+	// changes size, such as when the VerticaAutoscaler adds pods. This can
+	// only be set when spec.subcluster is set.
+	Scaling *VerticaResourcePoolScaling `json:"scaling,omitempty"`
+}
+
+// VerticaResourcePoolScaling describes how the pool settings follow the size
+// of the subcluster. The values in the spec are for a subcluster of baseSize
+// pods. For every pod above (or below) that, each setting grows (or shrinks)
+// by the given percentage of its base value.
+type VerticaResourcePoolScaling struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum:=1
+	// The subcluster size that the settings in the spec are given for
+	BaseSize int32 `json:"baseSize"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=0
+	// The percentage of spec.memorySize to add for each pod above baseSize.
+	// It can only be used when spec.memorySize has a unit rather than a
+	// percentage.
+	MemorySizePercentPerPod int32 `json:"memorySizePercentPerPod,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=0
+	// The percentage of spec.maxConcurrency to add for each pod above baseSize
+	MaxConcurrencyPercentPerPod int32 `json:"maxConcurrencyPercentPerPod,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=0
+	// The percentage of spec.plannedConcurrency to add for each pod above
+	// baseSize
+	PlannedConcurrencyPercentPerPod int32 `json:"plannedConcurrencyPercentPerPod,omitempty"`
+}
+
+// VerticaResourcePoolStatus defines the observed state of VerticaResourcePool
+type VerticaResourcePoolStatus struct {
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// Conditions for VerticaResourcePool
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// Status message for the resource pool
+	State string `json:"state,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The size of the subcluster the pool settings were last computed for
+	SubclusterSize int32 `json:"subclusterSize,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The memory size that was last applied to the pool
+	MemorySize string `json:"memorySize,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The max concurrency that was last applied to the pool
+	MaxConcurrency *int32 `json:"maxConcurrency,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The planned concurrency that was last applied to the pool
+	PlannedConcurrency *int32 `json:"plannedConcurrency,omitempty"`
+}
+
+const (
+	// ResourcePoolReady indicates whether the pool exists in the database and
+	// matches the spec
+	ResourcePoolReady = "ResourcePoolReady"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories=vertica,shortName=vrpool
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="VerticaDB",type="string",JSONPath=".spec.verticaDBName"
+// +kubebuilder:printcolumn:name="Subcluster",type="string",JSONPath=".spec.subcluster"
+// +kubebuilder:printcolumn:name="MemorySize",type="string",JSONPath=".status.memorySize"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +operator-sdk:csv:customresourcedefinitions:resources={{VerticaDB,vertica.com/v1,""}}
+
+// VerticaResourcePool is the Schema for the verticaresourcepools API
+type VerticaResourcePool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VerticaResourcePoolSpec   `json:"spec,omitempty"`
+	Status VerticaResourcePoolStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VerticaResourcePoolList contains a list of VerticaResourcePool
+type VerticaResourcePoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VerticaResourcePool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VerticaResourcePool{}, &VerticaResourcePoolList{})
+}
@@ -0,0 +1,163 @@
+/*
+Copyright [2021-2024] Open Text.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"strconv"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// builtinResourcePools are the pools that vertica creates. They cannot be
+// created or dropped, so the operator cannot manage them.
+var builtinResourcePools = []string{"general", "sysquery", "recovery", "dbd", "jvm", "blobdata", "metadata", "refresh", "tm"}
+
+// log is for logging in this package.
+var verticaresourcepoollog = logf.Log.WithName("verticaresourcepool-resource")
+
+func (vrpool *VerticaResourcePool) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(vrpool).
+		Complete()
+}
+
+var _ webhook.Defaulter = &VerticaResourcePool{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (vrpool *VerticaResourcePool) Default() {
+	verticaresourcepoollog.Info("default", "name", vrpool.Name)
+}
+
+var _ webhook.Validator = &VerticaResourcePool{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (vrpool *VerticaResourcePool) ValidateCreate() (admission.Warnings, error) {
+	verticaresourcepoollog.Info("validate create", "name", vrpool.Name)
+
+	allErrs := vrpool.validateVrpoolSpec()
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+	return nil, apierrors.NewInvalid(GkVRPOOL, vrpool.Name, allErrs)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (vrpool *VerticaResourcePool) ValidateUpdate(oldObj runtime.Object) (admission.Warnings, error) {
+	verticaresourcepoollog.Info("validate update", "name", vrpool.Name)
+
+	allErrs := vrpool.validateVrpoolSpec()
+	old := oldObj.(*VerticaResourcePool)
+	allErrs = vrpool.validateImmutableFields(old, allErrs)
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+	return nil, apierrors.NewInvalid(GkVRPOOL, vrpool.Name, allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (vrpool *VerticaResourcePool) ValidateDelete() (admission.Warnings, error) {
+	verticaresourcepoollog.Info("validate delete", "name", vrpool.Name)
+	return nil, nil
+}
+
+// validateVrpoolSpec will validate the current VerticaResourcePool to see if it is valid
+func (vrpool *VerticaResourcePool) validateVrpoolSpec() field.ErrorList {
+	allErrs := vrpool.validatePoolName(field.ErrorList{})
+	allErrs = vrpool.validateMemorySize(allErrs)
+	allErrs = vrpool.validateScaling(allErrs)
+	return allErrs
+}
+
+// validatePoolName will make sure the pool name is valid and isn't one of
+// the built-in pools
+func (vrpool *VerticaResourcePool) validatePoolName(allErrs field.ErrorList) field.ErrorList {
+	pathPrefix := field.NewPath("spec").Child("poolName")
+	allErrs = validateIdentifier(vrpool.GetPoolName(), pathPrefix, allErrs)
+	for _, builtin := range builtinResourcePools {
+		if strings.EqualFold(vrpool.GetPoolName(), builtin) {
+			allErrs = append(allErrs, field.Invalid(pathPrefix, vrpool.GetPoolName(),
+				"poolName cannot be the name of a built-in resource pool"))
+		}
+	}
+	return allErrs
+}
+
+// validateMemorySize will check the format of the memory size
+func (vrpool *VerticaResourcePool) validateMemorySize(allErrs field.ErrorList) field.ErrorList {
+	if vrpool.Spec.MemorySize == "" {
+		return allErrs
+	}
+	pathPrefix := field.NewPath("spec").Child("memorySize")
+	m := ResourcePoolMemorySizeRegex.FindStringSubmatch(vrpool.Spec.MemorySize)
+	if m == nil {
+		return append(allErrs, field.Invalid(pathPrefix, vrpool.Spec.MemorySize,
+			"memorySize must be an integer followed by K, M, G, T or %"))
+	}
+	if m[2] == "%" {
+		const maxPercent = 100
+		if pct, err := strconv.Atoi(m[1]); err != nil || pct > maxPercent {
+			allErrs = append(allErrs, field.Invalid(pathPrefix, vrpool.Spec.MemorySize,
+				"memorySize cannot be more than 100%"))
+		}
+	}
+	return allErrs
+}
+
+// validateScaling will check that the scaling rules can be applied
+func (vrpool *VerticaResourcePool) validateScaling(allErrs field.ErrorList) field.ErrorList {
+	if vrpool.Spec.Scaling == nil {
+		return allErrs
+	}
+	pathPrefix := field.NewPath("spec").Child("scaling")
+	if vrpool.Spec.Subcluster == "" {
+		allErrs = append(allErrs, field.Invalid(pathPrefix, vrpool.Spec.Scaling,
+			"scaling can only be set when the pool is bound to a subcluster"))
+	}
+	if vrpool.Spec.Scaling.MemorySizePercentPerPod != 0 && strings.HasSuffix(vrpool.Spec.MemorySize, "%") {
+		allErrs = append(allErrs, field.Invalid(pathPrefix.Child("memorySizePercentPerPod"),
+			vrpool.Spec.Scaling.MemorySizePercentPerPod,
+			"memorySizePercentPerPod cannot be used when memorySize is a percentage"))
+	}
+	return allErrs
+}
+
+// validateImmutableFields will prevent changing the VerticaDB, the pool name
+// or the subcluster after creation
+func (vrpool *VerticaResourcePool) validateImmutableFields(old *VerticaResourcePool,
+	allErrs field.ErrorList) field.ErrorList {
+	pathPrefix := field.NewPath("spec")
+	if vrpool.Spec.VerticaDBName != old.Spec.VerticaDBName {
+		allErrs = append(allErrs, field.Invalid(pathPrefix.Child("verticaDBName"),
+			vrpool.Spec.VerticaDBName, "verticaDBName cannot change after creation"))
+	}
+	if vrpool.GetPoolName() != old.GetPoolName() {
+		allErrs = append(allErrs, field.Invalid(pathPrefix.Child("poolName"),
+			vrpool.Spec.PoolName, "poolName cannot change after creation"))
+	}
+	if vrpool.Spec.Subcluster != old.Spec.Subcluster {
+		allErrs = append(allErrs, field.Invalid(pathPrefix.Child("subcluster"),
+			vrpool.Spec.Subcluster, "subcluster cannot change after creation"))
+	}
+	return allErrs
+}
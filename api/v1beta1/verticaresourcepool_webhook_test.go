@@ -0,0 +1,108 @@
+/*
+Copyright [2021-2024] Open Text.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("verticaresourcepool_webhook", func() {
+	It("should succeed with default fields", func() {
+		vrpool := MakeVrpool()
+		_, err := vrpool.ValidateCreate()
+		Expect(err).Should(Succeed())
+		_, err = vrpool.ValidateUpdate(vrpool)
+		Expect(err).Should(Succeed())
+	})
+
+	It("should fail for a built-in pool", func() {
+		vrpool := MakeVrpool()
+		vrpool.Spec.PoolName = "GENERAL"
+		_, err := vrpool.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("poolName cannot be the name of a built-in resource pool"))
+	})
+
+	It("should fail if the memory size is invalid", func() {
+		vrpool := MakeVrpool()
+		vrpool.Spec.MemorySize = "4GB"
+		_, err := vrpool.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("memorySize must be an integer followed by"))
+
+		vrpool.Spec.MemorySize = "150%"
+		_, err = vrpool.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("memorySize cannot be more than 100%"))
+
+		vrpool.Spec.MemorySize = "25%"
+		_, err = vrpool.ValidateCreate()
+		Expect(err).Should(Succeed())
+	})
+
+	It("should only allow scaling for a pool bound to a subcluster", func() {
+		vrpool := MakeVrpool()
+		vrpool.Spec.Subcluster = ""
+		vrpool.Spec.Scaling = &VerticaResourcePoolScaling{BaseSize: 3, MaxConcurrencyPercentPerPod: 50}
+		_, err := vrpool.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("scaling can only be set when the pool is bound to a subcluster"))
+
+		vrpool = MakeVrpool()
+		vrpool.Spec.MemorySize = "20%"
+		vrpool.Spec.Scaling = &VerticaResourcePoolScaling{BaseSize: 3, MemorySizePercentPerPod: 50}
+		_, err = vrpool.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("memorySizePercentPerPod cannot be used when memorySize is a percentage"))
+	})
+
+	It("should not allow the subcluster to change", func() {
+		oldVrpool := MakeVrpool()
+		vrpool := MakeVrpool()
+		vrpool.Spec.Subcluster = "other"
+		_, err := vrpool.ValidateUpdate(oldVrpool)
+		Expect(err.Error()).To(ContainSubstring("subcluster cannot change after creation"))
+	})
+
+	It("should scale the pool settings with the subcluster size", func() {
+		vrpool := MakeVrpool()
+		planned := int32(2)
+		vrpool.Spec.PlannedConcurrency = &planned
+		vrpool.Spec.Scaling = &VerticaResourcePoolScaling{
+			BaseSize:                        3,
+			MemorySizePercentPerPod:         50,
+			MaxConcurrencyPercentPerPod:     25,
+			PlannedConcurrencyPercentPerPod: 0,
+		}
+		settings, err := vrpool.GetScaledSettings(3)
+		Expect(err).Should(Succeed())
+		Expect(settings.MemorySize).Should(Equal("4G"))
+		Expect(*settings.MaxConcurrency).Should(Equal(int32(4)))
+
+		settings, err = vrpool.GetScaledSettings(4)
+		Expect(err).Should(Succeed())
+		Expect(settings.MemorySize).Should(Equal("6G"))
+		Expect(*settings.MaxConcurrency).Should(Equal(int32(5)))
+		Expect(*settings.PlannedConcurrency).Should(Equal(int32(2)))
+
+		settings, err = vrpool.GetScaledSettings(2)
+		Expect(err).Should(Succeed())
+		Expect(settings.MemorySize).Should(Equal("2G"))
+		Expect(*settings.MaxConcurrency).Should(Equal(int32(3)))
+
+		vrpool.Spec.MemorySize = "3G"
+		settings, err = vrpool.GetScaledSettings(4)
+		Expect(err).Should(Succeed())
+		Expect(settings.MemorySize).Should(Equal("4608M"))
+	})
+})
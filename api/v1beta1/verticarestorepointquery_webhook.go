@@ -76,6 +76,45 @@ func (vrpq *VerticaRestorePointsQuery) ValidateDelete() (admission.Warnings, err
 // validateSpec will validate the current VerticaRestorePointsQuery to see if it is valid
 func (vrpq *VerticaRestorePointsQuery) validateVrpqSpec() field.ErrorList {
 	allErrs := vrpq.validateTimeStamp(field.ErrorList{})
+	allErrs = vrpq.validateReviveCopy(allErrs)
+	return allErrs
+}
+
+// validateReviveCopy will check the revive copy options, if set. It makes sure the
+// restore point is selected in exactly one way and that the new VerticaDB
+// doesn't clash with the source.
+func (vrpq *VerticaRestorePointsQuery) validateReviveCopy(allErrs field.ErrorList) field.ErrorList {
+	restore := vrpq.Spec.ReviveCopy
+	if restore == nil {
+		return allErrs
+	}
+	pathPrefix := field.NewPath("spec").Child("reviveCopy")
+	if restore.TargetVerticaDBName == "" {
+		err := field.Required(pathPrefix.Child("targetVerticaDBName"),
+			"targetVerticaDBName must be set to revive a copy at a restore point")
+		allErrs = append(allErrs, err)
+	} else if restore.TargetVerticaDBName == vrpq.Spec.VerticaDBName {
+		err := field.Invalid(pathPrefix.Child("targetVerticaDBName"), restore.TargetVerticaDBName,
+			"targetVerticaDBName must differ from verticaDBName")
+		allErrs = append(allErrs, err)
+	}
+	if restore.CommunalPath == "" {
+		err := field.Required(pathPrefix.Child("communalPath"),
+			"communalPath must be set to a location that holds a copy of the source communal data")
+		allErrs = append(allErrs, err)
+	}
+	if (restore.ID == "") == (restore.Timestamp == "") {
+		err := field.Invalid(pathPrefix, restore,
+			"exactly one of id or timestamp must be set to select a restore point")
+		allErrs = append(allErrs, err)
+	}
+	if restore.Timestamp != "" {
+		options := vops.ShowRestorePointFilterOptions{EndTimestamp: restore.Timestamp}
+		if timestampErr := options.ValidateAndStandardizeTimestampsIfAny(); timestampErr != nil {
+			err := field.Invalid(pathPrefix.Child("timestamp"), restore.Timestamp, timestampErr.Error())
+			allErrs = append(allErrs, err)
+		}
+	}
 	return allErrs
 }
 
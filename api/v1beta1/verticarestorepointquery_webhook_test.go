@@ -61,4 +61,39 @@ var _ = Describe("verticarestorepointsquery_webhook", func() {
 		_, err := vrpq.ValidateCreate()
 		Expect(err.Error()).To(ContainSubstring("start timestamp must be before end timestamp"))
 	})
+
+	It("should validate the restore options", func() {
+		vrpq := MakeVrpq()
+		vrpq.Spec.ReviveCopy = &VerticaRestorePointQueryReviveCopyOptions{
+			TargetVerticaDBName: "restored-db",
+			Timestamp:           "2006-01-02",
+		}
+		_, err := vrpq.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("communalPath must be set"))
+
+		vrpq.Spec.ReviveCopy.CommunalPath = "s3://copy/db"
+		_, err = vrpq.ValidateCreate()
+		Expect(err).Should(Succeed())
+
+		vrpq.Spec.ReviveCopy.ID = "abc"
+		_, err = vrpq.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("exactly one of id or timestamp must be set"))
+
+		vrpq.Spec.ReviveCopy.Timestamp = ""
+		vrpq.Spec.ReviveCopy.TargetVerticaDBName = vrpq.Spec.VerticaDBName
+		_, err = vrpq.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("targetVerticaDBName must differ from verticaDBName"))
+
+		vrpq.Spec.ReviveCopy.TargetVerticaDBName = ""
+		_, err = vrpq.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("targetVerticaDBName must be set"))
+
+		vrpq.Spec.ReviveCopy = &VerticaRestorePointQueryReviveCopyOptions{
+			TargetVerticaDBName: "restored-db",
+			Timestamp:           "yesterday",
+			CommunalPath:        "s3://copy/db",
+		}
+		_, err = vrpq.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("end timestamp \"yesterday\" is invalid"))
+	})
 })
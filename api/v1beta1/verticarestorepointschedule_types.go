@@ -0,0 +1,135 @@
+/*
+Copyright [2021-2024] Open Text.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VerticaRestorePointScheduleSpec defines the desired state of VerticaRestorePointSchedule
+type VerticaRestorePointScheduleSpec struct {
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The name of the VerticaDB CR that restore points are saved for. The
+	// VerticaDB object must exist in the same namespace as this object.
+	VerticaDBName string `json:"verticaDBName"`
+
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// A cron expression that controls when a restore point is saved. It uses
+	// the standard 5 field format (minute, hour, day of month, month, day of
+	// week) and is evaluated in UTC. Macros such as @daily or @hourly are also
+	// accepted.
+	Schedule string `json:"schedule"`
+
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// Name of the archive that restore points are saved to. The operator
+	// creates the archive if it doesn't already exist.
+	ArchiveName string `json:"archiveName"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=0
+	// +kubebuilder:validation:Minimum:=0
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	// The number of restore points to retain in the archive. When a new
+	// restore point is saved and the archive is full, the oldest restore
+	// point is removed. A value of 0 means there is no limit.
+	NumRestorePoints int `json:"numRestorePoints,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
+	// If true, no new restore points are saved until this is set back to
+	// false. Runs missed while suspended are not caught up.
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// VerticaRestorePointScheduleStatus defines the observed state of VerticaRestorePointSchedule
+type VerticaRestorePointScheduleStatus struct {
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// Conditions for VerticaRestorePointSchedule
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// Status message for the schedule
+	State string `json:"state,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The last time a restore point was attempted by the schedule
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The last time a restore point was saved successfully
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The last time a restore point failed to be saved
+	LastFailedTime *metav1.Time `json:"lastFailedTime,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The next time a restore point is going to be saved
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+}
+
+const (
+	// ScheduleReady indicates whether the referenced VerticaDB supports
+	// scheduled restore points and the schedule is active
+	ScheduleReady = "ScheduleReady"
+	// SavingRestorePoint indicates a scheduled restore point is in progress
+	SavingRestorePoint = "SavingRestorePoint"
+	// LastRestorePointSaved reflects the outcome of the most recent scheduled
+	// restore point. It is true if it succeeded and false if it failed.
+	LastRestorePointSaved = "LastRestorePointSaved"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories=vertica,shortName=vrps
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="VerticaDB",type="string",JSONPath=".spec.verticaDBName"
+// +kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".spec.schedule"
+// +kubebuilder:printcolumn:name="Archive",type="string",JSONPath=".spec.archiveName"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="LastSuccess",type="date",JSONPath=".status.lastSuccessfulTime"
+// +kubebuilder:printcolumn:name="NextRun",type="string",JSONPath=".status.nextScheduleTime"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +operator-sdk:csv:customresourcedefinitions:resources={{VerticaDB,vertica.com/v1,""}}
+
+// VerticaRestorePointSchedule is the Schema for the verticarestorepointschedules API
+type VerticaRestorePointSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VerticaRestorePointScheduleSpec   `json:"spec,omitempty"`
+	Status VerticaRestorePointScheduleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VerticaRestorePointScheduleList contains a list of VerticaRestorePointSchedule
+type VerticaRestorePointScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VerticaRestorePointSchedule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VerticaRestorePointSchedule{}, &VerticaRestorePointScheduleList{})
+}
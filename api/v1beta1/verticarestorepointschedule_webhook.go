@@ -0,0 +1,111 @@
+/*
+Copyright [2021-2024] Open Text.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"github.com/vertica/vertica-kubernetes/pkg/cron"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging in this package.
+var verticarestorepointschedulelog = logf.Log.WithName("verticarestorepointschedule-resource")
+
+func (vrps *VerticaRestorePointSchedule) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(vrps).
+		Complete()
+}
+
+var _ webhook.Defaulter = &VerticaRestorePointSchedule{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (vrps *VerticaRestorePointSchedule) Default() {
+	verticarestorepointschedulelog.Info("default", "name", vrps.Name)
+}
+
+var _ webhook.Validator = &VerticaRestorePointSchedule{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (vrps *VerticaRestorePointSchedule) ValidateCreate() (admission.Warnings, error) {
+	verticarestorepointschedulelog.Info("validate create", "name", vrps.Name)
+
+	allErrs := vrps.validateVrpsSpec()
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+	return nil, apierrors.NewInvalid(GkVRPS, vrps.Name, allErrs)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (vrps *VerticaRestorePointSchedule) ValidateUpdate(oldObj runtime.Object) (admission.Warnings, error) {
+	verticarestorepointschedulelog.Info("validate update", "name", vrps.Name)
+
+	allErrs := vrps.validateVrpsSpec()
+	old := oldObj.(*VerticaRestorePointSchedule)
+	allErrs = vrps.validateImmutableFields(old, allErrs)
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+	return nil, apierrors.NewInvalid(GkVRPS, vrps.Name, allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (vrps *VerticaRestorePointSchedule) ValidateDelete() (admission.Warnings, error) {
+	verticarestorepointschedulelog.Info("validate delete", "name", vrps.Name)
+	return nil, nil
+}
+
+// validateVrpsSpec will validate the current VerticaRestorePointSchedule to see if it is valid
+func (vrps *VerticaRestorePointSchedule) validateVrpsSpec() field.ErrorList {
+	allErrs := vrps.validateSchedule(field.ErrorList{})
+	allErrs = vrps.validateArchiveName(allErrs)
+	return allErrs
+}
+
+// validateSchedule will check that the cron expression can be parsed
+func (vrps *VerticaRestorePointSchedule) validateSchedule(allErrs field.ErrorList) field.ErrorList {
+	if _, err := cron.Parse(vrps.Spec.Schedule); err != nil {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("schedule"),
+			vrps.Spec.Schedule, err.Error()))
+	}
+	return allErrs
+}
+
+// validateArchiveName will make sure an archive name is given
+func (vrps *VerticaRestorePointSchedule) validateArchiveName(allErrs field.ErrorList) field.ErrorList {
+	if vrps.Spec.ArchiveName == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec").Child("archiveName"),
+			"archiveName must be set"))
+	}
+	return allErrs
+}
+
+// validateImmutableFields will prevent changing the VerticaDB a schedule refers to
+func (vrps *VerticaRestorePointSchedule) validateImmutableFields(old *VerticaRestorePointSchedule,
+	allErrs field.ErrorList) field.ErrorList {
+	if vrps.Spec.VerticaDBName != old.Spec.VerticaDBName {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("verticaDBName"),
+			vrps.Spec.VerticaDBName, "verticaDBName cannot change after creation"))
+	}
+	return allErrs
+}
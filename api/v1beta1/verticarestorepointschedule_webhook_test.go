@@ -0,0 +1,65 @@
+/*
+Copyright [2021-2024] Open Text.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("verticarestorepointschedule_webhook", func() {
+	It("should succeed with default fields", func() {
+		vrps := MakeVrps()
+		_, err := vrps.ValidateCreate()
+		Expect(err).Should(Succeed())
+		_, err = vrps.ValidateUpdate(vrps)
+		Expect(err).Should(Succeed())
+	})
+
+	It("should accept cron macros", func() {
+		vrps := MakeVrps()
+		vrps.Spec.Schedule = "@hourly"
+		_, err := vrps.ValidateCreate()
+		Expect(err).Should(Succeed())
+	})
+
+	It("should fail if the schedule is invalid", func() {
+		vrps := MakeVrps()
+		vrps.Spec.Schedule = "0 25 * * *"
+		_, err := vrps.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("invalid hour field"))
+
+		vrps.Spec.Schedule = ""
+		_, err = vrps.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("must have 5 fields"))
+	})
+
+	It("should fail if archive name is missing", func() {
+		vrps := MakeVrps()
+		vrps.Spec.ArchiveName = ""
+		_, err := vrps.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("archiveName must be set"))
+	})
+
+	It("should not allow the VerticaDB name to change", func() {
+		oldVrps := MakeVrps()
+		vrps := MakeVrps()
+		vrps.Spec.VerticaDBName = "other-db"
+		_, err := vrps.ValidateUpdate(oldVrps)
+		Expect(err.Error()).To(ContainSubstring("verticaDBName cannot change after creation"))
+	})
+})
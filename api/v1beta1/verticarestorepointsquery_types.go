@@ -35,6 +35,64 @@ type VerticaRestorePointsQuerySpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
 	// Optional parameter that will limit the query to only restore points satisfying provided filter options
 	FilterOptions *VerticaRestorePointQueryFilterOptions `json:"filterOptions,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced"
+	// Optional parameter that, when set, will revive a copy of the database
+	// into a new VerticaDB at one of the restore points found by the query,
+	// once the query completes. The copy of the communal data must be made
+	// beforehand; see reviveCopy.communalPath.
+	ReviveCopy *VerticaRestorePointQueryReviveCopyOptions `json:"reviveCopy,omitempty"`
+}
+
+// VerticaRestorePointQueryReviveCopyOptions defines how to select a restore point
+// from the query results and the VerticaDB that revives a copy of the database
+// at that restore point
+type VerticaRestorePointQueryReviveCopyOptions struct {
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The name of the new VerticaDB that the restore point is revived into.
+	// It is created in the same namespace as this object and must not
+	// already exist. Its spec is cloned from the source VerticaDB.
+	TargetVerticaDBName string `json:"targetVerticaDBName"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The ID of the restore point to restore. Specify either id or timestamp,
+	// but not both.
+	ID string `json:"id,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// Selects the most recent restore point created at or before this
+	// timestamp. It accepts the same formats as the filter timestamps and is
+	// interpreted in UTC. Specify either id or timestamp, but not both.
+	Timestamp string `json:"timestamp,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// Only consider restore points from this archive. If omitted, the archive
+	// from filterOptions is used, if set.
+	ArchiveName string `json:"archiveName,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The communal path of the new VerticaDB. All other communal settings
+	// (endpoint, credentials, region, etc.) are taken from the source
+	// VerticaDB. It cannot be the communal path of the source VerticaDB.
+	//
+	// Prerequisite: the operator does not copy communal data. Before setting
+	// this, copy the source VerticaDB's communal path to this location (for
+	// example with a bucket sync) so that it includes the archive and the
+	// restore point. The new VerticaDB revives from this copy, and the revive
+	// fails if the copy is missing or incomplete.
+	CommunalPath string `json:"communalPath"`
 }
 
 // VerticaRestorePointQueryFilterOptions defines the filter options to use while listing restore points
@@ -81,6 +139,17 @@ type VerticaRestorePointsQueryStatus struct {
 	// This contains the result of the restore points query. Check the QueryComplete
 	// status condition to know when this has been populated by the operator.
 	RestorePoints []vclusterops.RestorePoint `json:"restorePoints"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// The restore point that was selected when spec.reviveCopy is set
+	RevivedRestorePoint *vclusterops.RestorePoint `json:"revivedRestorePoint,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// The name of the VerticaDB that was created to revive the copy into when
+	// spec.reviveCopy is set
+	RevivedVerticaDBName string `json:"revivedVerticaDBName,omitempty"`
 }
 
 const (
@@ -90,6 +159,9 @@ const (
 	QueryComplete = "QueryComplete"
 	// QueryReady indicates whether the operator is ready to start querying
 	QueryReady = "QueryReady"
+	// ReviveCopyComplete indicates the revive requested through spec.reviveCopy
+	// has been handled. It is true if the new VerticaDB was created.
+	ReviveCopyComplete = "ReviveCopyComplete"
 )
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,106 @@
+/*
+Copyright [2021-2024] Open Text.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VerticaRoleSpec defines the desired state of VerticaRole
+type VerticaRoleSpec struct {
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The name of the VerticaDB CR that the role is created in. The VerticaDB
+	// object must exist in the same namespace as this object.
+	VerticaDBName string `json:"verticaDBName"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The name of the role in the database. If omitted, the name of this
+	// object is used. This cannot change after creation.
+	RoleName string `json:"roleName,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// A list of other roles to grant to this role, which builds a role
+	// hierarchy. Roles that are removed from this list are revoked.
+	Roles []string `json:"roles,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// A list of privileges to grant to the role on database objects. Grants
+	// that are removed from this list are revoked from the role.
+	Grants []VerticaGrant `json:"grants,omitempty"`
+}
+
+// VerticaRoleStatus defines the observed state of VerticaRole
+type VerticaRoleStatus struct {
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// Conditions for VerticaRole
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// Status message for the role
+	State string `json:"state,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The roles that the operator has granted to this role
+	GrantedRoles []string `json:"grantedRoles,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The object privileges that the operator has granted to this role
+	AppliedGrants []VerticaGrant `json:"appliedGrants,omitempty"`
+}
+
+const (
+	// RoleReady indicates whether the role exists in the database and matches
+	// the spec
+	RoleReady = "RoleReady"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories=vertica,shortName=vrole
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="VerticaDB",type="string",JSONPath=".spec.verticaDBName"
+// +kubebuilder:printcolumn:name="Role",type="string",JSONPath=".spec.roleName"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +operator-sdk:csv:customresourcedefinitions:resources={{VerticaDB,vertica.com/v1,""}}
+
+// VerticaRole is the Schema for the verticaroles API
+type VerticaRole struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VerticaRoleSpec   `json:"spec,omitempty"`
+	Status VerticaRoleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VerticaRoleList contains a list of VerticaRole
+type VerticaRoleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VerticaRole `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VerticaRole{}, &VerticaRoleList{})
+}
@@ -0,0 +1,104 @@
+/*
+Copyright [2021-2024] Open Text.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging in this package.
+var verticarolelog = logf.Log.WithName("verticarole-resource")
+
+func (vrole *VerticaRole) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(vrole).
+		Complete()
+}
+
+var _ webhook.Defaulter = &VerticaRole{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (vrole *VerticaRole) Default() {
+	verticarolelog.Info("default", "name", vrole.Name)
+}
+
+var _ webhook.Validator = &VerticaRole{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (vrole *VerticaRole) ValidateCreate() (admission.Warnings, error) {
+	verticarolelog.Info("validate create", "name", vrole.Name)
+
+	allErrs := vrole.validateVroleSpec()
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+	return nil, apierrors.NewInvalid(GkVROLE, vrole.Name, allErrs)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (vrole *VerticaRole) ValidateUpdate(oldObj runtime.Object) (admission.Warnings, error) {
+	verticarolelog.Info("validate update", "name", vrole.Name)
+
+	allErrs := vrole.validateVroleSpec()
+	old := oldObj.(*VerticaRole)
+	allErrs = vrole.validateImmutableFields(old, allErrs)
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+	return nil, apierrors.NewInvalid(GkVROLE, vrole.Name, allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (vrole *VerticaRole) ValidateDelete() (admission.Warnings, error) {
+	verticarolelog.Info("validate delete", "name", vrole.Name)
+	return nil, nil
+}
+
+// validateVroleSpec will validate the current VerticaRole to see if it is valid
+func (vrole *VerticaRole) validateVroleSpec() field.ErrorList {
+	pathPrefix := field.NewPath("spec")
+	allErrs := validateIdentifier(vrole.GetRoleName(), pathPrefix.Child("roleName"), field.ErrorList{})
+	allErrs = validateRoleList(vrole.Spec.Roles, pathPrefix.Child("roles"), allErrs)
+	for i := range vrole.Spec.Roles {
+		if vrole.Spec.Roles[i] == vrole.GetRoleName() {
+			allErrs = append(allErrs, field.Invalid(pathPrefix.Child("roles").Index(i), vrole.Spec.Roles[i],
+				"a role cannot be granted to itself"))
+		}
+	}
+	allErrs = validateGrants(vrole.Spec.Grants, pathPrefix.Child("grants"), allErrs)
+	return allErrs
+}
+
+// validateImmutableFields will prevent changing the VerticaDB or the name of
+// the role after creation
+func (vrole *VerticaRole) validateImmutableFields(old *VerticaRole, allErrs field.ErrorList) field.ErrorList {
+	if vrole.Spec.VerticaDBName != old.Spec.VerticaDBName {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("verticaDBName"),
+			vrole.Spec.VerticaDBName, "verticaDBName cannot change after creation"))
+	}
+	if vrole.GetRoleName() != old.GetRoleName() {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("roleName"),
+			vrole.Spec.RoleName, "roleName cannot change after creation"))
+	}
+	return allErrs
+}
@@ -0,0 +1,54 @@
+/*
+Copyright [2021-2024] Open Text.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("verticarole_webhook", func() {
+	It("should succeed with default fields", func() {
+		vrole := MakeVrole()
+		_, err := vrole.ValidateCreate()
+		Expect(err).Should(Succeed())
+		_, err = vrole.ValidateUpdate(vrole)
+		Expect(err).Should(Succeed())
+	})
+
+	It("should fail if the role is granted to itself", func() {
+		vrole := MakeVrole()
+		vrole.Spec.Roles = []string{"reader", vrole.GetRoleName()}
+		_, err := vrole.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("a role cannot be granted to itself"))
+	})
+
+	It("should fail if a grant is missing privileges", func() {
+		vrole := MakeVrole()
+		vrole.Spec.Grants[0].Privileges = nil
+		_, err := vrole.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("at least one privilege must be given"))
+	})
+
+	It("should not allow the role name to change", func() {
+		oldVrole := MakeVrole()
+		vrole := MakeVrole()
+		vrole.Spec.RoleName = ""
+		_, err := vrole.ValidateUpdate(oldVrole)
+		Expect(err.Error()).To(ContainSubstring("roleName cannot change after creation"))
+	})
+})
@@ -108,6 +108,13 @@ type VerticaScrutinizeStatus struct {
 	// The name of the scrutinize tarball that was created.
 	TarballName string `json:"tarballName"`
 
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The name of the cluster health analysis report that was created next to
+	// the tarball. It is only set when the analysis is enabled through the
+	// vertica.com/scrutinize-analysis annotation and it ran successfully.
+	AnalysisReportName string `json:"analysisReportName,omitempty"`
+
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	// +optional
 	// Status message for scrutinize
@@ -123,6 +130,9 @@ const (
 	ScrutinizePodCreated = "ScrutinizePodCreated"
 	// ScrutinizeCollectionFinished indicates whether scrutinize collection is done
 	ScrutinizeCollectionFinished = "ScrutinizeCollectionFinished"
+	// ScrutinizeAnalysisFinished indicates whether the slow event and lock
+	// analysis, that runs after the scrutinize collection, is done
+	ScrutinizeAnalysisFinished = "ScrutinizeAnalysisFinished"
 	// ScrutinizeReady indicates that there is a VerticaDB ready for scrutinize, meaning
 	// the server version supports vclusterops and vclusterops is enabled
 	ScrutinizeReady = "ScrutinizeReady"
@@ -133,6 +143,7 @@ const (
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
 // +kubebuilder:printcolumn:name="Pod",type="string",JSONPath=".status.podName"
+// +kubebuilder:printcolumn:name="Report",type="string",JSONPath=".status.analysisReportName",priority=1
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +operator-sdk:csv:customresourcedefinitions:resources={{Pod,v1,""},{VerticaDB,vertica.com/v1,""}}
 
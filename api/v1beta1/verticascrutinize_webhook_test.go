@@ -115,4 +115,23 @@ var _ = Describe("verticascrutinize_webhook", func() {
 		Expect(err.Error()).To(ContainSubstring("should be formatted as: YYYY-MM-DD HH [+/-XX]"))
 	})
 
+	It("should derive the log age window from the annotations", func() {
+		vscr := MakeVscr()
+		now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+		oldest, newest := vscr.GetLogAgeWindow(now)
+		Expect(oldest).Should(Equal(now.Add(-24 * time.Hour)))
+		Expect(newest).Should(Equal(now))
+
+		vscr.Annotations[vmeta.ScrutinizeLogAgeHours] = "8"
+		oldest, newest = vscr.GetLogAgeWindow(now)
+		Expect(oldest).Should(Equal(now.Add(-8 * time.Hour)))
+		Expect(newest).Should(Equal(now))
+
+		vscr.Annotations[vmeta.ScrutinizeLogAgeHours] = "0"
+		vscr.Annotations[vmeta.ScrutinizeLogAgeOldestTime] = "2024-03-09 10 -05"
+		vscr.Annotations[vmeta.ScrutinizeLogAgeNewestTime] = "2024-03-10 02"
+		oldest, newest = vscr.GetLogAgeWindow(now)
+		Expect(oldest).Should(Equal(time.Date(2024, 3, 9, 15, 0, 0, 0, time.UTC)))
+		Expect(newest).Should(Equal(time.Date(2024, 3, 10, 2, 0, 0, 0, time.UTC)))
+	})
 })
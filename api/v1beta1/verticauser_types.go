@@ -0,0 +1,170 @@
+/*
+Copyright [2021-2024] Open Text.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VerticaUserSpec defines the desired state of VerticaUser
+type VerticaUserSpec struct {
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The name of the VerticaDB CR that the user is created in. The VerticaDB
+	// object must exist in the same namespace as this object.
+	VerticaDBName string `json:"verticaDBName"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The name of the user in the database. If omitted, the name of this
+	// object is used. This cannot change after creation.
+	UserName string `json:"userName,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:io.kubernetes:Secret"
+	// The name of a secret that contains the password of the user. The
+	// password must be stored in the key 'password'. The secret can be stored
+	// in a secret store by prefixing the name with the store type (e.g.
+	// gsm:// or awssm://). If omitted, the user is created without a
+	// password. The password is changed in the database when the secret
+	// name changes.
+	PasswordSecret string `json:"passwordSecret,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// A list of roles to grant to the user. All of the roles are also set as
+	// default roles so they are enabled when the user logs in. Roles that are
+	// removed from this list are revoked from the user.
+	Roles []string `json:"roles,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	// A list of privileges to grant to the user directly on database objects.
+	// Grants that are removed from this list are revoked from the user.
+	Grants []VerticaGrant `json:"grants,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The name of the resource pool the user's queries run in. If omitted,
+	// the user is assigned the general pool.
+	ResourcePool string `json:"resourcePool,omitempty"`
+}
+
+// VerticaGrant describes a set of privileges granted on a database object
+type VerticaGrant struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems:=1
+	// The privileges to grant, such as SELECT, INSERT or USAGE. Use ALL to
+	// grant every privilege that applies to the object type.
+	Privileges []string `json:"privileges"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum:=DATABASE;SCHEMA;TABLE;VIEW;SEQUENCE;ALL TABLES IN SCHEMA;RESOURCE POOL;STORAGE LOCATION
+	// The type of the object the privileges are granted on.
+	ObjectType string `json:"objectType"`
+
+	// +kubebuilder:validation:Required
+	// The name of the object. Tables, views and sequences can be qualified
+	// with their schema, such as 'store.orders'.
+	ObjectName string `json:"objectName"`
+}
+
+const (
+	GrantObjectTypeDatabase          = "DATABASE"
+	GrantObjectTypeSchema            = "SCHEMA"
+	GrantObjectTypeTable             = "TABLE"
+	GrantObjectTypeView              = "VIEW"
+	GrantObjectTypeSequence          = "SEQUENCE"
+	GrantObjectTypeAllTablesInSchema = "ALL TABLES IN SCHEMA"
+	GrantObjectTypeResourcePool      = "RESOURCE POOL"
+	GrantObjectTypeStorageLocation   = "STORAGE LOCATION"
+)
+
+// VerticaUserStatus defines the observed state of VerticaUser
+type VerticaUserStatus struct {
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// Conditions for VerticaUser
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// Status message for the user
+	State string `json:"state,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The roles that the operator has granted to the user
+	GrantedRoles []string `json:"grantedRoles,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The object privileges that the operator has granted to the user
+	AppliedGrants []VerticaGrant `json:"appliedGrants,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The name of the password secret that was last applied to the user. It
+	// is used to detect when the password needs to change.
+	PasswordSecret *string `json:"passwordSecret,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// A salted digest of the password that was last applied to the user. It
+	// is used to detect when the contents of the password secret change.
+	PasswordDigest string `json:"passwordDigest,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The resource pool that was last assigned to the user
+	ResourcePool string `json:"resourcePool,omitempty"`
+}
+
+const (
+	// UserReady indicates whether the user exists in the database and matches
+	// the spec
+	UserReady = "UserReady"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories=vertica,shortName=vusr
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="VerticaDB",type="string",JSONPath=".spec.verticaDBName"
+// +kubebuilder:printcolumn:name="User",type="string",JSONPath=".spec.userName"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +operator-sdk:csv:customresourcedefinitions:resources={{VerticaDB,vertica.com/v1,""},{Secret,v1,""}}
+
+// VerticaUser is the Schema for the verticausers API
+type VerticaUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VerticaUserSpec   `json:"spec,omitempty"`
+	Status VerticaUserStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VerticaUserList contains a list of VerticaUser
+type VerticaUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VerticaUser `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VerticaUser{}, &VerticaUserList{})
+}
@@ -0,0 +1,164 @@
+/*
+Copyright [2021-2024] Open Text.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"regexp"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	// The maximum length of an identifier in vertica
+	maxIdentifierLength = 128
+)
+
+// privilegeRegex restricts privileges to keywords, such as SELECT or ALL
+// PRIVILEGES, so that they cannot be used to inject SQL.
+var privilegeRegex = regexp.MustCompile(`^[A-Za-z]+( [A-Za-z]+)*$`)
+
+// log is for logging in this package.
+var verticauserlog = logf.Log.WithName("verticauser-resource")
+
+func (vusr *VerticaUser) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(vusr).
+		Complete()
+}
+
+var _ webhook.Defaulter = &VerticaUser{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (vusr *VerticaUser) Default() {
+	verticauserlog.Info("default", "name", vusr.Name)
+}
+
+var _ webhook.Validator = &VerticaUser{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (vusr *VerticaUser) ValidateCreate() (admission.Warnings, error) {
+	verticauserlog.Info("validate create", "name", vusr.Name)
+
+	allErrs := vusr.validateVusrSpec()
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+	return nil, apierrors.NewInvalid(GkVUSR, vusr.Name, allErrs)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (vusr *VerticaUser) ValidateUpdate(oldObj runtime.Object) (admission.Warnings, error) {
+	verticauserlog.Info("validate update", "name", vusr.Name)
+
+	allErrs := vusr.validateVusrSpec()
+	old := oldObj.(*VerticaUser)
+	allErrs = vusr.validateImmutableFields(old, allErrs)
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+	return nil, apierrors.NewInvalid(GkVUSR, vusr.Name, allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (vusr *VerticaUser) ValidateDelete() (admission.Warnings, error) {
+	verticauserlog.Info("validate delete", "name", vusr.Name)
+	return nil, nil
+}
+
+// validateVusrSpec will validate the current VerticaUser to see if it is valid
+func (vusr *VerticaUser) validateVusrSpec() field.ErrorList {
+	pathPrefix := field.NewPath("spec")
+	allErrs := validateIdentifier(vusr.GetUserName(), pathPrefix.Child("userName"), field.ErrorList{})
+	allErrs = validateRoleList(vusr.Spec.Roles, pathPrefix.Child("roles"), allErrs)
+	allErrs = validateGrants(vusr.Spec.Grants, pathPrefix.Child("grants"), allErrs)
+	if vusr.Spec.ResourcePool != "" {
+		allErrs = validateIdentifier(vusr.Spec.ResourcePool, pathPrefix.Child("resourcePool"), allErrs)
+	}
+	return allErrs
+}
+
+// validateImmutableFields will prevent changing the VerticaDB or the name of
+// the user after creation
+func (vusr *VerticaUser) validateImmutableFields(old *VerticaUser, allErrs field.ErrorList) field.ErrorList {
+	if vusr.Spec.VerticaDBName != old.Spec.VerticaDBName {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("verticaDBName"),
+			vusr.Spec.VerticaDBName, "verticaDBName cannot change after creation"))
+	}
+	if vusr.GetUserName() != old.GetUserName() {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("userName"),
+			vusr.Spec.UserName, "userName cannot change after creation"))
+	}
+	return allErrs
+}
+
+// validateIdentifier will check that a database identifier is set and isn't
+// longer than vertica allows
+func validateIdentifier(id string, fieldPath *field.Path, allErrs field.ErrorList) field.ErrorList {
+	if id == "" {
+		return append(allErrs, field.Required(fieldPath, "name cannot be empty"))
+	}
+	if len(id) > maxIdentifierLength {
+		allErrs = append(allErrs, field.Invalid(fieldPath, id,
+			fmt.Sprintf("name cannot be longer than %d characters", maxIdentifierLength)))
+	}
+	return allErrs
+}
+
+// validateRoleList will check the names of the roles to grant
+func validateRoleList(roles []string, fieldPath *field.Path, allErrs field.ErrorList) field.ErrorList {
+	for i := range roles {
+		allErrs = validateIdentifier(roles[i], fieldPath.Index(i), allErrs)
+	}
+	return allErrs
+}
+
+// validateGrants will check the privileges and object of each grant
+func validateGrants(grants []VerticaGrant, fieldPath *field.Path, allErrs field.ErrorList) field.ErrorList {
+	for i := range grants {
+		p := fieldPath.Index(i)
+		if len(grants[i].Privileges) == 0 {
+			allErrs = append(allErrs, field.Required(p.Child("privileges"), "at least one privilege must be given"))
+		}
+		for j, priv := range grants[i].Privileges {
+			if !privilegeRegex.MatchString(priv) {
+				allErrs = append(allErrs, field.Invalid(p.Child("privileges").Index(j), priv,
+					"privilege must only contain letters and single spaces"))
+			}
+		}
+		switch grants[i].ObjectType {
+		case GrantObjectTypeDatabase, GrantObjectTypeSchema, GrantObjectTypeTable, GrantObjectTypeView,
+			GrantObjectTypeSequence, GrantObjectTypeAllTablesInSchema, GrantObjectTypeResourcePool,
+			GrantObjectTypeStorageLocation:
+		default:
+			allErrs = append(allErrs, field.NotSupported(p.Child("objectType"), grants[i].ObjectType,
+				[]string{GrantObjectTypeDatabase, GrantObjectTypeSchema, GrantObjectTypeTable, GrantObjectTypeView,
+					GrantObjectTypeSequence, GrantObjectTypeAllTablesInSchema, GrantObjectTypeResourcePool,
+					GrantObjectTypeStorageLocation}))
+		}
+		if grants[i].ObjectName == "" {
+			allErrs = append(allErrs, field.Required(p.Child("objectName"), "objectName must be set"))
+		}
+	}
+	return allErrs
+}
@@ -0,0 +1,92 @@
+/*
+Copyright [2021-2024] Open Text.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("verticauser_webhook", func() {
+	It("should succeed with default fields", func() {
+		vusr := MakeVusr()
+		_, err := vusr.ValidateCreate()
+		Expect(err).Should(Succeed())
+		_, err = vusr.ValidateUpdate(vusr)
+		Expect(err).Should(Succeed())
+	})
+
+	It("should default the user name to the object name", func() {
+		vusr := MakeVusr()
+		vusr.Spec.UserName = ""
+		Expect(vusr.GetUserName()).Should(Equal(vusr.Name))
+		_, err := vusr.ValidateCreate()
+		Expect(err).Should(Succeed())
+	})
+
+	It("should fail if the user name is too long", func() {
+		vusr := MakeVusr()
+		vusr.Spec.UserName = strings.Repeat("u", maxIdentifierLength+1)
+		_, err := vusr.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("name cannot be longer than"))
+	})
+
+	It("should fail if a role name is empty", func() {
+		vusr := MakeVusr()
+		vusr.Spec.Roles = append(vusr.Spec.Roles, "")
+		_, err := vusr.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("spec.roles[1]"))
+	})
+
+	It("should fail if a privilege is not a keyword", func() {
+		vusr := MakeVusr()
+		vusr.Spec.Grants[0].Privileges = []string{"SELECT; DROP TABLE t"}
+		_, err := vusr.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("privilege must only contain letters"))
+
+		vusr.Spec.Grants[0].Privileges = []string{"ALL PRIVILEGES"}
+		_, err = vusr.ValidateCreate()
+		Expect(err).Should(Succeed())
+	})
+
+	It("should fail if the grant object is invalid", func() {
+		vusr := MakeVusr()
+		vusr.Spec.Grants[0].ObjectType = "FUNCTION"
+		_, err := vusr.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("spec.grants[0].objectType"))
+
+		vusr = MakeVusr()
+		vusr.Spec.Grants[0].ObjectName = ""
+		_, err = vusr.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("objectName must be set"))
+	})
+
+	It("should not allow the VerticaDB name or user name to change", func() {
+		oldVusr := MakeVusr()
+		vusr := MakeVusr()
+		vusr.Spec.VerticaDBName = "other-db"
+		_, err := vusr.ValidateUpdate(oldVusr)
+		Expect(err.Error()).To(ContainSubstring("verticaDBName cannot change after creation"))
+
+		vusr = MakeVusr()
+		vusr.Spec.UserName = "other_user"
+		_, err = vusr.ValidateUpdate(oldVusr)
+		Expect(err.Error()).To(ContainSubstring("userName cannot change after creation"))
+	})
+})
@@ -0,0 +1,222 @@
+/*
+Copyright [2021-2024] Open Text.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VerticaWorkloadReplaySpec defines the desired state of VerticaWorkloadReplay
+type VerticaWorkloadReplaySpec struct {
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The name of the VerticaDB CR to capture the workload from. The VerticaDB
+	// object must exist in the same namespace as this object and must be
+	// deployed with vclusterops.
+	VerticaDBName string `json:"verticaDBName"`
+
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The name of the sandbox, from spec.sandboxes of the VerticaDB, to replay
+	// the workload in. This is typically a sandbox that runs the image you
+	// intend to upgrade to.
+	Sandbox string `json:"sandbox"`
+
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The start of the time window to capture from the main cluster
+	CaptureStartTime metav1.Time `json:"captureStartTime"`
+
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The end of the time window to capture from the main cluster. If it is in
+	// the future, the capture waits until this time has passed.
+	CaptureEndTime metav1.Time `json:"captureEndTime"`
+
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The S3 compatible location where the captured workload and the replay
+	// report are stored. It must start with s3://. Both files are written in a
+	// folder named after this object.
+	Path string `json:"path"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The URL of the object store endpoint, such as https://minio:9000. If
+	// omitted, the AWS endpoint for the region is used.
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:="us-east-1"
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	// The region of the bucket
+	Region string `json:"region,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:io.kubernetes:Secret"
+	// The name of a secret that contains the credentials to access the object
+	// store. It must have the keys accesskey and secretkey. If omitted, the
+	// default AWS credential chain of the operator is used.
+	CredentialSecret string `json:"credentialSecret,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=20
+	// +kubebuilder:validation:Minimum:=0
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number"
+	// A replayed query counts as a regression if it took this many percent
+	// longer than the original one. Queries that were slower by less than 10
+	// milliseconds are never counted, to ignore the noise on short queries.
+	RegressionThresholdPercent int `json:"regressionThresholdPercent,omitempty"`
+}
+
+// VerticaWorkloadReplayStatus defines the observed state of VerticaWorkloadReplay
+type VerticaWorkloadReplayStatus struct {
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// Conditions for VerticaWorkloadReplay
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// Status message for the capture and replay
+	State string `json:"state,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The time the capture started
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The time the replay completed
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The ID the replay was started with. The NMA uses it to identify the
+	// replay, so it can be used to cancel a replay that is running.
+	ReplayJobID int64 `json:"replayJobID,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The location of the captured workload in the object store
+	CapturePath string `json:"capturePath,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The location of the replay report in the object store. The report
+	// compares the duration of each query in the capture and in the replay.
+	ReportPath string `json:"reportPath,omitempty"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The number of queries in the captured workload
+	CapturedQueries int `json:"capturedQueries"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The number of queries that were replayed
+	ReplayedQueries int `json:"replayedQueries"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The sum of the duration of the queries, in milliseconds, when they were
+	// captured
+	OriginalDurationMilliseconds int64 `json:"originalDurationMilliseconds"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The sum of the duration of the queries, in milliseconds, when they were
+	// replayed
+	ReplayDurationMilliseconds int64 `json:"replayDurationMilliseconds"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The number of queries that were slower than the regression threshold
+	// when replayed
+	Regressions int `json:"regressions"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The number of queries that failed when replayed
+	Errors int `json:"errors"`
+
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	// The queries with the largest regressions, and those that failed, worst
+	// first
+	TopRegressions []VerticaWorkloadReplayRegression `json:"topRegressions,omitempty"`
+}
+
+// VerticaWorkloadReplayRegression is a query that was slower, or failed, when
+// it was replayed
+type VerticaWorkloadReplayRegression struct {
+	// The text of the query. Long queries are truncated.
+	Request string `json:"request"`
+	// The duration of the query, in milliseconds, when it was captured
+	OriginalDurationMilliseconds int64 `json:"originalDurationMilliseconds"`
+	// The duration of the query, in milliseconds, when it was replayed
+	ReplayDurationMilliseconds int64 `json:"replayDurationMilliseconds"`
+	// The error returned by the replayed query, if any
+	Error string `json:"error,omitempty"`
+}
+
+const (
+	// WorkloadReplayReady indicates whether the referenced VerticaDB and
+	// sandbox support workload replay
+	WorkloadReplayReady = "WorkloadReplayReady"
+	// WorkloadCaptured indicates the workload was captured and saved in the
+	// object store
+	WorkloadCaptured = "WorkloadCaptured"
+	// WorkloadReplayComplete indicates the capture and replay have finished.
+	// It is true if they succeeded and false if either failed.
+	WorkloadReplayComplete = "WorkloadReplayComplete"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:categories=vertica,shortName=vwr
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="VerticaDB",type="string",JSONPath=".spec.verticaDBName"
+// +kubebuilder:printcolumn:name="Sandbox",type="string",JSONPath=".spec.sandbox"
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Queries",type="integer",JSONPath=".status.replayedQueries"
+// +kubebuilder:printcolumn:name="Regressions",type="integer",JSONPath=".status.regressions"
+// +kubebuilder:printcolumn:name="Errors",type="integer",JSONPath=".status.errors"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +operator-sdk:csv:customresourcedefinitions:resources={{VerticaDB,vertica.com/v1,""}}
+
+// VerticaWorkloadReplay is the Schema for the verticaworkloadreplays API
+// The VerticaDB must run an image of v26.1.0 or newer, as older images ship a
+// vcluster without the capture_workload and replay_workload commands.
+type VerticaWorkloadReplay struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VerticaWorkloadReplaySpec   `json:"spec,omitempty"`
+	Status VerticaWorkloadReplayStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VerticaWorkloadReplayList contains a list of VerticaWorkloadReplay
+type VerticaWorkloadReplayList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VerticaWorkloadReplay `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VerticaWorkloadReplay{}, &VerticaWorkloadReplayList{})
+}
@@ -0,0 +1,142 @@
+/*
+Copyright [2021-2024] Open Text.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging in this package.
+var verticaworkloadreplaylog = logf.Log.WithName("verticaworkloadreplay-resource")
+
+func (vwr *VerticaWorkloadReplay) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(vwr).
+		Complete()
+}
+
+var _ webhook.Defaulter = &VerticaWorkloadReplay{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (vwr *VerticaWorkloadReplay) Default() {
+	verticaworkloadreplaylog.Info("default", "name", vwr.Name)
+}
+
+var _ webhook.Validator = &VerticaWorkloadReplay{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (vwr *VerticaWorkloadReplay) ValidateCreate() (admission.Warnings, error) {
+	verticaworkloadreplaylog.Info("validate create", "name", vwr.Name)
+
+	allErrs := vwr.validateVwrSpec()
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+	return nil, apierrors.NewInvalid(GkVWR, vwr.Name, allErrs)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (vwr *VerticaWorkloadReplay) ValidateUpdate(oldObj runtime.Object) (admission.Warnings, error) {
+	verticaworkloadreplaylog.Info("validate update", "name", vwr.Name)
+
+	allErrs := vwr.validateVwrSpec()
+	old := oldObj.(*VerticaWorkloadReplay)
+	allErrs = vwr.validateImmutableFields(old, allErrs)
+	if len(allErrs) == 0 {
+		return nil, nil
+	}
+	return nil, apierrors.NewInvalid(GkVWR, vwr.Name, allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (vwr *VerticaWorkloadReplay) ValidateDelete() (admission.Warnings, error) {
+	verticaworkloadreplaylog.Info("validate delete", "name", vwr.Name)
+	return nil, nil
+}
+
+// validateVwrSpec will validate the current VerticaWorkloadReplay to see if it is valid
+func (vwr *VerticaWorkloadReplay) validateVwrSpec() field.ErrorList {
+	allErrs := vwr.validateSandbox(field.ErrorList{})
+	allErrs = vwr.validateCaptureWindow(allErrs)
+	allErrs = vwr.validatePath(allErrs)
+	allErrs = vwr.validateRegressionThreshold(allErrs)
+	return allErrs
+}
+
+// validateSandbox will make sure a sandbox to replay in was given
+func (vwr *VerticaWorkloadReplay) validateSandbox(allErrs field.ErrorList) field.ErrorList {
+	if vwr.Spec.Sandbox == "" {
+		allErrs = append(allErrs, field.Required(field.NewPath("spec").Child("sandbox"),
+			"sandbox must be set to the sandbox the workload is replayed in"))
+	}
+	return allErrs
+}
+
+// validateCaptureWindow will make sure the capture window ends after it starts
+func (vwr *VerticaWorkloadReplay) validateCaptureWindow(allErrs field.ErrorList) field.ErrorList {
+	if !vwr.Spec.CaptureEndTime.After(vwr.Spec.CaptureStartTime.Time) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("captureEndTime"),
+			vwr.Spec.CaptureEndTime, "captureEndTime must be after captureStartTime"))
+	}
+	return allErrs
+}
+
+// validatePath will make sure the path points to a bucket in an S3
+// compatible object store
+func (vwr *VerticaWorkloadReplay) validatePath(allErrs field.ErrorList) field.ErrorList {
+	pathPrefix := field.NewPath("spec").Child("path")
+	if !strings.HasPrefix(vwr.Spec.Path, s3Prefix) {
+		return append(allErrs, field.Invalid(pathPrefix, vwr.Spec.Path,
+			"path must start with s3://"))
+	}
+	if strings.Trim(strings.TrimPrefix(vwr.Spec.Path, s3Prefix), "/") == "" {
+		allErrs = append(allErrs, field.Invalid(pathPrefix, vwr.Spec.Path,
+			"path must include a bucket name"))
+	}
+	return allErrs
+}
+
+// validateRegressionThreshold will check that the threshold is not negative.
+// A value of 0 means the default is used.
+func (vwr *VerticaWorkloadReplay) validateRegressionThreshold(allErrs field.ErrorList) field.ErrorList {
+	if vwr.Spec.RegressionThresholdPercent < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec").Child("regressionThresholdPercent"),
+			vwr.Spec.RegressionThresholdPercent, "regressionThresholdPercent cannot be negative"))
+	}
+	return allErrs
+}
+
+// validateImmutableFields will prevent any change to the spec. A
+// VerticaWorkloadReplay runs once, so a new object must be created for
+// another capture and replay.
+func (vwr *VerticaWorkloadReplay) validateImmutableFields(old *VerticaWorkloadReplay,
+	allErrs field.ErrorList) field.ErrorList {
+	if !reflect.DeepEqual(vwr.Spec, old.Spec) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"),
+			"spec cannot change after creation. Create a new VerticaWorkloadReplay instead"))
+	}
+	return allErrs
+}
@@ -0,0 +1,80 @@
+/*
+Copyright [2021-2024] Open Text.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("verticaworkloadreplay_webhook", func() {
+	It("should succeed with default fields", func() {
+		vwr := MakeVwr()
+		_, err := vwr.ValidateCreate()
+		Expect(err).Should(Succeed())
+		_, err = vwr.ValidateUpdate(vwr)
+		Expect(err).Should(Succeed())
+	})
+
+	It("should require a sandbox", func() {
+		vwr := MakeVwr()
+		vwr.Spec.Sandbox = ""
+		_, err := vwr.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("sandbox must be set"))
+	})
+
+	It("should fail if the capture window ends before it starts", func() {
+		vwr := MakeVwr()
+		vwr.Spec.CaptureEndTime = vwr.Spec.CaptureStartTime
+		_, err := vwr.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("captureEndTime must be after captureStartTime"))
+	})
+
+	It("should fail if the path is not an s3 bucket", func() {
+		vwr := MakeVwr()
+		vwr.Spec.Path = "/data/replays"
+		_, err := vwr.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("path must start with s3://"))
+
+		vwr.Spec.Path = "s3://"
+		_, err = vwr.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("path must include a bucket name"))
+	})
+
+	It("should fail if the regression threshold is negative", func() {
+		vwr := MakeVwr()
+		vwr.Spec.RegressionThresholdPercent = -5
+		_, err := vwr.ValidateCreate()
+		Expect(err.Error()).To(ContainSubstring("regressionThresholdPercent cannot be negative"))
+	})
+
+	It("should not allow the spec to change", func() {
+		oldVwr := MakeVwr()
+		vwr := MakeVwr()
+		vwr.Spec.Sandbox = "other"
+		_, err := vwr.ValidateUpdate(oldVwr)
+		Expect(err.Error()).To(ContainSubstring("spec cannot change after creation"))
+	})
+
+	It("should build the object paths from the spec", func() {
+		vwr := MakeVwr()
+		vwr.Spec.Path = "s3://bucket/replays/"
+		Expect(vwr.GetObjectPath(vwr.GetCaptureObjectKey())).Should(Equal("s3://bucket/replays/vwr-sample/capture.csv"))
+		Expect(vwr.GetObjectPath(vwr.GetReportObjectKey())).Should(Equal("s3://bucket/replays/vwr-sample/replay-report.csv"))
+		Expect(vwr.GetRegressionThresholdPercent()).Should(Equal(20))
+	})
+})
@@ -21,6 +21,7 @@ import (
 	"crypto/x509"
 	"log"
 	"os"
+	"path/filepath"
 	"time"
 
 	// Allows us to pull in things generated from `go generate`
@@ -53,11 +54,19 @@ import (
 	"github.com/vertica/vertica-kubernetes/pkg/controllers/et"
 	"github.com/vertica/vertica-kubernetes/pkg/controllers/sandbox"
 	"github.com/vertica/vertica-kubernetes/pkg/controllers/vas"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers/vbackup"
 	"github.com/vertica/vertica-kubernetes/pkg/controllers/vdb"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers/vhc"
 	"github.com/vertica/vertica-kubernetes/pkg/controllers/vrep"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers/vrole"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers/vrpool"
 	"github.com/vertica/vertica-kubernetes/pkg/controllers/vrpq"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers/vrps"
 	"github.com/vertica/vertica-kubernetes/pkg/controllers/vscr"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers/vusr"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers/vwr"
 	vmeta "github.com/vertica/vertica-kubernetes/pkg/meta"
+	"github.com/vertica/vertica-kubernetes/pkg/metrics"
 	"github.com/vertica/vertica-kubernetes/pkg/opcfg"
 	"github.com/vertica/vertica-kubernetes/pkg/security"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -68,6 +77,8 @@ import (
 
 const (
 	CertDir = "/tmp/k8s-webhook-server/serving-certs"
+	// How often the expiry of the webhook cert is refreshed
+	WebhookCertExpiryInterval = time.Hour
 )
 
 //go:generate sh -c "printf %s $(git rev-parse HEAD) > git-commit.go-generate.txt"
@@ -123,10 +134,11 @@ func addReconcilersToManager(mgr manager.Manager, restCfg *rest.Config) {
 	}
 
 	if err := (&vas.VerticaAutoscalerReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-		EVRec:  mgr.GetEventRecorderFor(vmeta.OperatorName),
-		Log:    ctrl.Log.WithName("controllers").WithName("VerticaAutoscaler"),
+		Client:       mgr.GetClient(),
+		Scheme:       mgr.GetScheme(),
+		EVRec:        mgr.GetEventRecorderFor(vmeta.OperatorName),
+		Log:          ctrl.Log.WithName("controllers").WithName("VerticaAutoscaler"),
+		CacheManager: cacheManager,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "VerticaAutoscaler")
 		os.Exit(1)
@@ -188,6 +200,90 @@ func addReconcilersToManager(mgr manager.Manager, restCfg *rest.Config) {
 		setupLog.Error(err, "unable to create controller", "controller", "VerticaReplicator")
 		os.Exit(1)
 	}
+	if err := (&vrps.VerticaRestorePointScheduleReconciler{
+		Client:       mgr.GetClient(),
+		Scheme:       mgr.GetScheme(),
+		Cfg:          restCfg,
+		EVRec:        mgr.GetEventRecorderFor(vmeta.OperatorName),
+		Log:          ctrl.Log.WithName("controllers").WithName("VerticaRestorePointSchedule"),
+		Concurrency:  opcfg.GetVerticaRestorePointScheduleConcurrency(),
+		CacheManager: cacheManager,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "VerticaRestorePointSchedule")
+		os.Exit(1)
+	}
+	if err := (&vusr.VerticaUserReconciler{
+		Client:       mgr.GetClient(),
+		Scheme:       mgr.GetScheme(),
+		Cfg:          restCfg,
+		EVRec:        mgr.GetEventRecorderFor(vmeta.OperatorName),
+		Log:          ctrl.Log.WithName("controllers").WithName("VerticaUser"),
+		Concurrency:  opcfg.GetVerticaUserConcurrency(),
+		CacheManager: cacheManager,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "VerticaUser")
+		os.Exit(1)
+	}
+	if err := (&vrole.VerticaRoleReconciler{
+		Client:       mgr.GetClient(),
+		Scheme:       mgr.GetScheme(),
+		Cfg:          restCfg,
+		EVRec:        mgr.GetEventRecorderFor(vmeta.OperatorName),
+		Log:          ctrl.Log.WithName("controllers").WithName("VerticaRole"),
+		Concurrency:  opcfg.GetVerticaRoleConcurrency(),
+		CacheManager: cacheManager,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "VerticaRole")
+		os.Exit(1)
+	}
+	if err := (&vrpool.VerticaResourcePoolReconciler{
+		Client:       mgr.GetClient(),
+		Scheme:       mgr.GetScheme(),
+		Cfg:          restCfg,
+		EVRec:        mgr.GetEventRecorderFor(vmeta.OperatorName),
+		Log:          ctrl.Log.WithName("controllers").WithName("VerticaResourcePool"),
+		Concurrency:  opcfg.GetVerticaResourcePoolConcurrency(),
+		CacheManager: cacheManager,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "VerticaResourcePool")
+		os.Exit(1)
+	}
+	if err := (&vbackup.VerticaBackupReconciler{
+		Client:       mgr.GetClient(),
+		Scheme:       mgr.GetScheme(),
+		Cfg:          restCfg,
+		EVRec:        mgr.GetEventRecorderFor(vmeta.OperatorName),
+		Log:          ctrl.Log.WithName("controllers").WithName("VerticaBackup"),
+		Concurrency:  opcfg.GetVerticaBackupConcurrency(),
+		CacheManager: cacheManager,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "VerticaBackup")
+		os.Exit(1)
+	}
+	if err := (&vhc.VerticaHealthCheckReconciler{
+		Client:       mgr.GetClient(),
+		Scheme:       mgr.GetScheme(),
+		Cfg:          restCfg,
+		EVRec:        mgr.GetEventRecorderFor(vmeta.OperatorName),
+		Log:          ctrl.Log.WithName("controllers").WithName("VerticaHealthCheck"),
+		Concurrency:  opcfg.GetVerticaHealthCheckConcurrency(),
+		CacheManager: cacheManager,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "VerticaHealthCheck")
+		os.Exit(1)
+	}
+	if err := (&vwr.VerticaWorkloadReplayReconciler{
+		Client:       mgr.GetClient(),
+		Scheme:       mgr.GetScheme(),
+		Cfg:          restCfg,
+		EVRec:        mgr.GetEventRecorderFor(vmeta.OperatorName),
+		Log:          ctrl.Log.WithName("controllers").WithName("VerticaWorkloadReplay"),
+		Concurrency:  opcfg.GetVerticaWorkloadReplayConcurrency(),
+		CacheManager: cacheManager,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "VerticaWorkloadReplay")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 }
 
@@ -217,6 +313,34 @@ func addWebhooksToManager(mgr manager.Manager) {
 		setupLog.Error(err, "unable to create webhook", "webhook", "VerticaReplicator", "version", vapiB1.Version)
 		os.Exit(1)
 	}
+	if err := (&vapiB1.VerticaRestorePointSchedule{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "VerticaRestorePointSchedule", "version", vapiB1.Version)
+		os.Exit(1)
+	}
+	if err := (&vapiB1.VerticaUser{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "VerticaUser", "version", vapiB1.Version)
+		os.Exit(1)
+	}
+	if err := (&vapiB1.VerticaRole{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "VerticaRole", "version", vapiB1.Version)
+		os.Exit(1)
+	}
+	if err := (&vapiB1.VerticaResourcePool{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "VerticaResourcePool", "version", vapiB1.Version)
+		os.Exit(1)
+	}
+	if err := (&vapiB1.VerticaBackup{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "VerticaBackup", "version", vapiB1.Version)
+		os.Exit(1)
+	}
+	if err := (&vapiB1.VerticaHealthCheck{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "VerticaHealthCheck", "version", vapiB1.Version)
+		os.Exit(1)
+	}
+	if err := (&vapiB1.VerticaWorkloadReplay{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "VerticaWorkloadReplay", "version", vapiB1.Version)
+		os.Exit(1)
+	}
 }
 
 // setupWebhook will setup the webhook in the manager if enabled
@@ -245,12 +369,42 @@ func setupWebhook(ctx context.Context, mgr manager.Manager, restCfg *rest.Config
 			}
 		}
 		addWebhooksToManager(mgr)
+		if err := mgr.Add(manager.RunnableFunc(watchWebhookCertExpiry)); err != nil {
+			return err
+		}
 	} else {
 		setupLog.Info("webhook setup is because webhook is not enabled")
 	}
 	return nil
 }
 
+// watchWebhookCertExpiry exports the time left before the webhook cert
+// expires. The cert is read from the cert directory so that this works however
+// the cert was provided. It runs until the context is cancelled.
+func watchWebhookCertExpiry(ctx context.Context) error {
+	ns := opcfg.GetOperatorNamespace()
+	ticker := time.NewTicker(WebhookCertExpiryInterval)
+	defer ticker.Stop()
+	for {
+		certPEM, err := os.ReadFile(filepath.Join(CertDir, "tls.crt"))
+		if err == nil {
+			var cert *x509.Certificate
+			cert, err = security.DecodeCertificate(certPEM)
+			if err == nil {
+				metrics.WebhookCertificateExpirySeconds.WithLabelValues(ns).Set(time.Until(cert.NotAfter).Seconds())
+			}
+		}
+		if err != nil {
+			setupLog.Info("unable to read the webhook cert to check its expiry", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
 // getReadinessProbeCallack returns the check to use for the readiness probe
 func getReadinessProbeCallback(mgr ctrl.Manager) healthz.Checker {
 	// If the webhook is enabled, we use a checker that tests if the webhook is
@@ -376,11 +530,18 @@ func main() {
 		EventBroadcaster:        multibroadcaster,
 		Controller: config.Controller{
 			GroupKindConcurrency: map[string]int{
-				vapiB1.GkVDB.String():  opcfg.GetVerticaDBConcurrency(),
-				vapiB1.GkVAS.String():  opcfg.GetVerticaAutoscalerConcurrency(),
-				vapiB1.GkET.String():   opcfg.GetEventTriggerConcurrency(),
-				vapiB1.GkVRPQ.String(): opcfg.GetVerticaRestorePointsQueryConcurrency(),
-				vapiB1.GkVSCR.String(): opcfg.GetVerticaScrutinizeConcurrency(),
+				vapiB1.GkVDB.String():     opcfg.GetVerticaDBConcurrency(),
+				vapiB1.GkVAS.String():     opcfg.GetVerticaAutoscalerConcurrency(),
+				vapiB1.GkET.String():      opcfg.GetEventTriggerConcurrency(),
+				vapiB1.GkVRPQ.String():    opcfg.GetVerticaRestorePointsQueryConcurrency(),
+				vapiB1.GkVSCR.String():    opcfg.GetVerticaScrutinizeConcurrency(),
+				vapiB1.GkVRPS.String():    opcfg.GetVerticaRestorePointScheduleConcurrency(),
+				vapiB1.GkVUSR.String():    opcfg.GetVerticaUserConcurrency(),
+				vapiB1.GkVROLE.String():   opcfg.GetVerticaRoleConcurrency(),
+				vapiB1.GkVRPOOL.String():  opcfg.GetVerticaResourcePoolConcurrency(),
+				vapiB1.GkVBACKUP.String(): opcfg.GetVerticaBackupConcurrency(),
+				vapiB1.GkVHC.String():     opcfg.GetVerticaHealthCheckConcurrency(),
+				vapiB1.GkVWR.String():     opcfg.GetVerticaWorkloadReplayConcurrency(),
 			},
 		},
 	})
@@ -160,6 +160,30 @@ const (
 	transactionIDKey       = "transactionID"
 )
 
+// Flags for the multi-database fleet view
+const (
+	kubeconfigFlag    = "kubeconfig"
+	kubeContextFlag   = "context"
+	namespaceFlag     = "namespace"
+	formatFlag        = "format"
+	skipNodeStateFlag = "skip-node-state"
+)
+
+// Flags for workload capture/replay and the health watchdog
+const (
+	workloadFileFlag      = "workload-file"
+	replayResultsFileFlag = "replay-results-file"
+	startTimestampFlag    = "start-timestamp"
+	endTimestampFlag      = "end-timestamp"
+	jobIDFlag             = "job-id"
+	watchdogActionFlag    = "action"
+	parameterNameFlag     = "parameter"
+	parameterValueFlag    = "value"
+	clearParameterFlag    = "clear"
+	policySettingsFlag    = "policy"
+	sessionFlag           = "session"
+)
+
 // flags to viper key map
 var flagKeyMap = map[string]string{
 	dbNameFlag:                      dbNameKey,
@@ -257,14 +281,24 @@ const (
 	scrutinizeSubCmd        = "scrutinize"
 	showRestorePointsSubCmd = "show_restore_points"
 	installPkgSubCmd        = "install_packages"
+	fleetStatusSubCmd       = "fleet_status"
+	captureWorkloadSubCmd   = "capture_workload"
+	replayWorkloadSubCmd    = "replay_workload"
+	cancelWorkloadSubCmd    = "cancel_workload"
+	healthWatchdogCmd       = "health_watchdog"
+	watchdogGetSubCmd       = "get"
+	watchdogSetSubCmd       = "set"
+	watchdogCancelSubCmd    = "cancel_query"
 	// hidden Cmds (for internal testing only)
-	promoteSandboxSubCmd    = "promote_sandbox"
-	createArchiveCmd        = "create_archive"
-	saveRestorePointsSubCmd = "save_restore_point"
-	getDrainingStatusSubCmd = "get_draining_status"
-	upgradeLicenseCmd       = "upgrade_license"
-	checkConnectionSubCmd   = "check"
-	clusterHealth           = "cluster_health"
+	promoteSandboxSubCmd     = "promote_sandbox"
+	createArchiveCmd         = "create_archive"
+	saveRestorePointsSubCmd  = "save_restore_point"
+	removeRestorePointSubCmd = "remove_restore_point"
+	dropArchiveSubCmd        = "drop_archive"
+	getDrainingStatusSubCmd  = "get_draining_status"
+	upgradeLicenseCmd        = "upgrade_license"
+	checkConnectionSubCmd    = "check"
+	clusterHealth            = "cluster_health"
 )
 
 // cmdGlobals holds global variables shared by multiple
@@ -645,11 +679,18 @@ func constructCmds() []*cobra.Command {
 		makeCmdReplication(),
 		makeCmdGetReplicationStatus(),
 		makeCmdConnection(),
+		makeCmdFleetStatus(),
+		makeCmdCaptureWorkload(),
+		makeCmdReplayWorkload(),
+		makeCmdCancelWorkload(),
+		makeCmdHealthWatchdog(),
 		// hidden cmds (for internal testing only)
 		makeCmdGetDrainingStatus(),
 		makeCmdPromoteSandbox(),
 		makeCmdCreateArchive(),
 		makeCmdSaveRestorePoint(),
+		makeCmdRemoveRestorePoint(),
+		makeCmdDropArchive(),
 		makeCmdUpgradeLicense(),
 		makeCmdClusterHealth(),
 	}
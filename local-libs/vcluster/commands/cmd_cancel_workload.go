@@ -0,0 +1,146 @@
+/*
+ (c) Copyright [2023-2025] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/json"
+
+	"github.com/spf13/cobra"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdCancelWorkload
+ *
+ * Implements ClusterCommand interface
+ */
+type CmdCancelWorkload struct {
+	cancelOptions *vclusterops.VWorkloadCancelOptions
+	CmdBase
+}
+
+// workloadCancelResult is the JSON output of cancel_workload
+type workloadCancelResult struct {
+	JobID   int64  `json:"job_id"`
+	Sandbox string `json:"sandbox"`
+}
+
+func makeCmdCancelWorkload() *cobra.Command {
+	newCmd := &CmdCancelWorkload{}
+	opt := vclusterops.VWorkloadCancelOptionsFactory()
+	newCmd.cancelOptions = &opt
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		cancelWorkloadSubCmd,
+		"Cancel a workload replay.",
+		`Cancels a workload replay, started with the replay_workload command, that
+is running in a sandbox.
+
+Examples:
+  # Cancel the replay job 1001 in sandbox sand1 with user input
+  vcluster cancel_workload --db-name test_db \
+    --hosts 10.20.30.40,10.20.30.41,10.20.30.42 \
+    --sandbox sand1 --job-id 1001 --password "PASSWORD"
+
+  # Cancel the replay job 1001 in sandbox sand1 with config file
+  vcluster cancel_workload --sandbox sand1 --job-id 1001 \
+    --config /opt/vertica/config/vertica_cluster.yaml \
+    --password "PASSWORD"
+`,
+		[]string{dbNameFlag, configFlag, passwordFlag, dbUserFlag, hostsFlag, ipv6Flag, outputFileFlag},
+	)
+
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
+	markFlagsRequired(cmd, sandboxFlag, jobIDFlag)
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdCancelWorkload) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&c.cancelOptions.Sandbox,
+		sandboxFlag,
+		"",
+		"The name of the sandbox the workload is replayed in.",
+	)
+	cmd.Flags().Int64Var(
+		&c.cancelOptions.JobID,
+		jobIDFlag,
+		0,
+		"The ID of the replay job to cancel.",
+	)
+}
+
+func (c *CmdCancelWorkload) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogMaskedArgParse(c.argv)
+
+	// for some options, we do not want to use their default values,
+	// if they are not provided in cli,
+	// reset the value of those options to nil
+	c.ResetUserInputOptions(&c.cancelOptions.DatabaseOptions)
+	return c.validateParse(logger)
+}
+
+func (c *CmdCancelWorkload) validateParse(logger vlog.Printer) error {
+	logger.Info("Called validateParse()")
+
+	if !c.usePassword() {
+		err := c.getCertFilesFromCertPaths(&c.cancelOptions.DatabaseOptions)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := c.ValidateParseBaseOptions(&c.cancelOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+	return c.setDBPassword(&c.cancelOptions.DatabaseOptions)
+}
+
+func (c *CmdCancelWorkload) Run(vcc vclusterops.ClusterCommands) error {
+	vcc.LogInfo("Called method Run()")
+
+	options := c.cancelOptions
+	err := vcc.VWorkloadCancel(options)
+	if err != nil {
+		vcc.LogError(err, "failed to cancel workload", "sandbox", options.Sandbox, "jobID", options.JobID)
+		return err
+	}
+
+	bytes, err := json.MarshalIndent(workloadCancelResult{
+		JobID:   options.JobID,
+		Sandbox: options.Sandbox,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	bytes = append(bytes, '\n')
+	c.writeCmdOutputToFile(globals.file, bytes, vcc.GetLog())
+
+	vcc.DisplayInfo("Successfully cancelled workload replay job %d in sandbox %s", options.JobID, options.Sandbox)
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance to the one in CmdCancelWorkload
+func (c *CmdCancelWorkload) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.cancelOptions.DatabaseOptions = *opt
+}
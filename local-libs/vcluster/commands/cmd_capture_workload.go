@@ -0,0 +1,170 @@
+/*
+ (c) Copyright [2023-2025] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/json"
+
+	"github.com/spf13/cobra"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdCaptureWorkload
+ *
+ * Implements ClusterCommand interface
+ */
+type CmdCaptureWorkload struct {
+	captureOptions *vclusterops.VWorkloadCaptureOptions
+	CmdBase
+}
+
+// workloadCaptureResult is the JSON output of capture_workload
+type workloadCaptureResult struct {
+	WorkloadFile   string `json:"workload_file"`
+	StartTimestamp string `json:"start_timestamp"`
+	EndTimestamp   string `json:"end_timestamp"`
+}
+
+func makeCmdCaptureWorkload() *cobra.Command {
+	newCmd := &CmdCaptureWorkload{}
+	opt := vclusterops.VWorkloadCaptureOptionsFactory()
+	newCmd.captureOptions = &opt
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		captureWorkloadSubCmd,
+		"Capture the queries run against a database over a time window.",
+		`Captures the queries that were run against the database between a
+start and an end timestamp and saves them to a CSV file. The file can then be
+replayed against a sandbox with the replay_workload command.
+
+Timestamps must be in the format 'YYYY-MM-DD HH:MM:SS[.ffffff]-TZ', where TZ is
+the UTC hour offset.
+
+Examples:
+  # Capture one hour of workload with user input
+  vcluster capture_workload --db-name test_db \
+    --hosts 10.20.30.40,10.20.30.41,10.20.30.42 \
+    --start-timestamp "2025-01-10 09:00:00-05" \
+    --end-timestamp "2025-01-10 10:00:00-05" \
+    --workload-file /tmp/workload.csv --password "PASSWORD"
+
+  # Capture one hour of workload with config file
+  vcluster capture_workload \
+    --start-timestamp "2025-01-10 09:00:00-05" \
+    --end-timestamp "2025-01-10 10:00:00-05" \
+    --workload-file /tmp/workload.csv \
+    --config /opt/vertica/config/vertica_cluster.yaml \
+    --password "PASSWORD"
+`,
+		[]string{dbNameFlag, configFlag, passwordFlag, dbUserFlag, hostsFlag, ipv6Flag, outputFileFlag},
+	)
+
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
+	markFlagsRequired(cmd, workloadFileFlag, startTimestampFlag, endTimestampFlag)
+	markFlagsFileName(cmd, map[string][]string{workloadFileFlag: {"csv"}})
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdCaptureWorkload) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&c.captureOptions.WorkloadFileLocation,
+		workloadFileFlag,
+		"",
+		"The path of the CSV file the captured workload is saved to. It must not exist yet.",
+	)
+	cmd.Flags().StringVar(
+		&c.captureOptions.StartTimestamp,
+		startTimestampFlag,
+		"",
+		"The start of the time window to capture.",
+	)
+	cmd.Flags().StringVar(
+		&c.captureOptions.EndTimestamp,
+		endTimestampFlag,
+		"",
+		"The end of the time window to capture.",
+	)
+}
+
+func (c *CmdCaptureWorkload) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogMaskedArgParse(c.argv)
+
+	// for some options, we do not want to use their default values,
+	// if they are not provided in cli,
+	// reset the value of those options to nil
+	c.ResetUserInputOptions(&c.captureOptions.DatabaseOptions)
+	return c.validateParse(logger)
+}
+
+func (c *CmdCaptureWorkload) validateParse(logger vlog.Printer) error {
+	logger.Info("Called validateParse()")
+
+	if !c.usePassword() {
+		err := c.getCertFilesFromCertPaths(&c.captureOptions.DatabaseOptions)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := c.ValidateParseBaseOptions(&c.captureOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+	err = c.setDBPassword(&c.captureOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+	// the operator passes a password secret stored outside of k8s through
+	// environment variables rather than a mounted file
+	return dbPasswordLookupFromSecretStore(logger, secretStoreRetrieverStruct{}, &c.captureOptions.DatabaseOptions)
+}
+
+func (c *CmdCaptureWorkload) Run(vcc vclusterops.ClusterCommands) error {
+	vcc.LogInfo("Called method Run()")
+
+	options := c.captureOptions
+	err := vcc.VWorkloadCapture(options)
+	if err != nil {
+		vcc.LogError(err, "failed to capture workload", "workloadFile", options.WorkloadFileLocation)
+		return err
+	}
+
+	bytes, err := json.MarshalIndent(workloadCaptureResult{
+		WorkloadFile:   options.WorkloadFileLocation,
+		StartTimestamp: options.StartTimestamp,
+		EndTimestamp:   options.EndTimestamp,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	bytes = append(bytes, '\n')
+	c.writeCmdOutputToFile(globals.file, bytes, vcc.GetLog())
+
+	vcc.DisplayInfo("Successfully captured workload to %s", options.WorkloadFileLocation)
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance to the one in CmdCaptureWorkload
+func (c *CmdCaptureWorkload) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.captureOptions.DatabaseOptions = *opt
+}
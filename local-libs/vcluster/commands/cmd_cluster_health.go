@@ -52,7 +52,7 @@ Examples:
   # Check the cluster health
   vcluster cluster_health --start-time <start_time> --end-time <end_time>
 `,
-		[]string{dbNameFlag, configFlag, hostsFlag, ipv6Flag, passwordFlag, outputFileFlag},
+		[]string{dbNameFlag, configFlag, hostsFlag, ipv6Flag, passwordFlag, dbUserFlag, outputFileFlag},
 	)
 
 	// local flags
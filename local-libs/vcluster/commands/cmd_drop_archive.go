@@ -0,0 +1,163 @@
+/*
+ (c) Copyright [2023-2025] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdDropArchive
+ *
+ * Parses arguments to drop_archive and calls
+ * the high-level function for drop_archive.
+ *
+ * Implements ClusterCommand interface
+ */
+
+type CmdDropArchive struct {
+	CmdBase
+	dropArchiveOptions *vclusterops.VDropArchiveOptions
+}
+
+func makeCmdDropArchive() *cobra.Command {
+	newCmd := &CmdDropArchive{}
+	opt := vclusterops.VDropArchiveFactory()
+	newCmd.dropArchiveOptions = &opt
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		dropArchiveSubCmd,
+		"Drop an archive and all of its restore points.",
+		`Drop an archive and all of its restore points.
+
+Examples:
+  # Drop an archive with user input
+  vcluster drop_archive --db-name test_db \
+	--archive-name ARCHIVE_ONE \
+	--password "PASSWORD"
+
+  # Drop an archive for a sandbox
+  vcluster drop_archive --db-name test_db \
+	--archive-name ARCHIVE_ONE --sandbox SANDBOX_ONE \
+	--password "PASSWORD"
+
+`,
+		[]string{dbNameFlag, hostsFlag, passwordFlag,
+			ipv6Flag, configFlag, eonModeFlag},
+	)
+
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
+	// require db-name and archive-name
+	markFlagsRequired(cmd, dbNameFlag, archiveNameFlag)
+
+	// hide this subcommand
+	cmd.Hidden = true
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdDropArchive) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&c.dropArchiveOptions.ArchiveName,
+		archiveNameFlag,
+		"",
+		"Collection of restore points that belong to a certain archive.",
+	)
+	cmd.Flags().StringVar(
+		&c.dropArchiveOptions.Sandbox,
+		sandboxFlag,
+		"",
+		"The name of target sandbox",
+	)
+}
+
+func (c *CmdDropArchive) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogMaskedArgParse(c.argv)
+
+	// for some options, we do not want to use their default values,
+	// if they are not provided in cli,
+	// reset the value of those options to nil
+	c.ResetUserInputOptions(&c.dropArchiveOptions.DatabaseOptions)
+
+	// drop_archive only works for an Eon db so we assume the user always runs this subcommand
+	// on an Eon db. When Eon mode cannot be found in config file, we set its value to true.
+	if !viper.IsSet(eonModeKey) {
+		c.dropArchiveOptions.IsEon = true
+	}
+
+	return c.validateParse(logger)
+}
+
+// all validations of the arguments should go in here
+func (c *CmdDropArchive) validateParse(logger vlog.Printer) error {
+	logger.Info("Called validateParse()")
+
+	err := c.ValidateParseBaseOptions(&c.dropArchiveOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	if !c.usePassword() {
+		err = c.getCertFilesFromCertPaths(&c.dropArchiveOptions.DatabaseOptions)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = c.setConfigParam(&c.dropArchiveOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	err = c.setDBPassword(&c.dropArchiveOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *CmdDropArchive) Analyze(logger vlog.Printer) error {
+	logger.Info("Called method Analyze()")
+	return nil
+}
+
+func (c *CmdDropArchive) Run(vcc vclusterops.ClusterCommands) error {
+	vcc.LogInfo("Called method Run()")
+
+	options := c.dropArchiveOptions
+
+	err := vcc.VDropArchive(options)
+	if err != nil {
+		vcc.LogError(err, "failed to drop archive", "DBName", options.DBName)
+		return err
+	}
+
+	vcc.DisplayInfo("Successfully dropped archive %s in database %s", options.ArchiveName, options.DBName)
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance to the one in CmdDropArchive
+func (c *CmdDropArchive) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.dropArchiveOptions.DatabaseOptions = *opt
+}
@@ -0,0 +1,582 @@
+/*
+ (c) Copyright [2023-2025] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+	vmeta "github.com/vertica/vertica-kubernetes/pkg/meta"
+	"github.com/vertica/vertica-kubernetes/pkg/secrets"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// The output formats supported by fleet_status
+const (
+	fleetFormatTable = "table"
+	fleetFormatJSON  = "json"
+	fleetFormatYAML  = "yaml"
+)
+
+// Node state reported for a database whose nodes could not be reached
+const fleetNodeStateUnreachable = "UNREACHABLE"
+
+// verticaDBGVR is the group/version/resource of the VerticaDB custom resource
+var verticaDBGVR = schema.GroupVersionResource{
+	Group:    "vertica.com",
+	Version:  "v1",
+	Resource: "verticadbs",
+}
+
+/* CmdFleetStatus
+ *
+ * Lists every VerticaDB visible through a kubeconfig and
+ * summarizes its status.
+ *
+ * Implements ClusterCommand interface
+ */
+type CmdFleetStatus struct {
+	kubeconfig    string
+	kubeContext   string
+	namespace     string
+	format        string
+	skipNodeState bool
+	// template used for the node state requests. It holds the TLS
+	// settings given on the command line.
+	dbOptions vclusterops.DatabaseOptions
+	CmdBase
+}
+
+// fleetVerticaDB is the subset of the VerticaDB custom resource that is
+// needed to build the fleet view. We decode into our own struct rather than
+// the operator API so that vcluster does not depend on the operator's
+// controller libraries.
+type fleetVerticaDB struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		DBName         string `json:"dbName"`
+		PasswordSecret string `json:"passwordSecret,omitempty"`
+	} `json:"spec,omitempty"`
+	Status struct {
+		AddedToDBCount int32 `json:"addedToDBCount"`
+		UpNodeCount    int32 `json:"upNodeCount"`
+		Subclusters    []struct {
+			Name           string `json:"name"`
+			Type           string `json:"type"`
+			AddedToDBCount int32  `json:"addedToDBCount"`
+			UpNodeCount    int32  `json:"upNodeCount"`
+			Shutdown       bool   `json:"shutdown"`
+		} `json:"subclusters,omitempty"`
+		UpgradeStatus string `json:"upgradeStatus"`
+		Sandboxes     []struct {
+			Name         string   `json:"name"`
+			Subclusters  []string `json:"subclusters"`
+			UpgradeState struct {
+				UpgradeInProgress bool   `json:"upgradeInProgress"`
+				UpgradeStatus     string `json:"upgradeStatus"`
+			} `json:"upgradeState,omitempty"`
+		} `json:"sandboxes,omitempty"`
+		RestorePoint *FleetRestorePoint `json:"restorePoint"`
+		TLSConfigs   []struct {
+			Name   string `json:"name"`
+			Secret string `json:"secret"`
+			Mode   string `json:"mode"`
+		} `json:"tlsConfigs,omitempty"`
+		PasswordSecret *string `json:"passwordSecret,omitempty"`
+	} `json:"status,omitempty"`
+}
+
+// FleetDBStatus is the summary of one VerticaDB in the fleet view
+type FleetDBStatus struct {
+	Namespace      string                  `json:"namespace" yaml:"namespace"`
+	Name           string                  `json:"name" yaml:"name"`
+	DBName         string                  `json:"dbName" yaml:"dbName"`
+	AddedToDBCount int32                   `json:"addedToDBCount" yaml:"addedToDBCount"`
+	UpNodeCount    int32                   `json:"upNodeCount" yaml:"upNodeCount"`
+	Subclusters    []FleetSubclusterStatus `json:"subclusters,omitempty" yaml:"subclusters,omitempty"`
+	UpgradeStatus  string                  `json:"upgradeStatus,omitempty" yaml:"upgradeStatus,omitempty"`
+	Sandboxes      []FleetSandboxStatus    `json:"sandboxes,omitempty" yaml:"sandboxes,omitempty"`
+	TLSConfigs     []FleetTLSConfigStatus  `json:"tlsConfigs,omitempty" yaml:"tlsConfigs,omitempty"`
+	RestorePoint   *FleetRestorePoint      `json:"restorePoint,omitempty" yaml:"restorePoint,omitempty"`
+	Nodes          []FleetNodeState        `json:"nodes,omitempty" yaml:"nodes,omitempty"`
+	NodeStateError string                  `json:"nodeStateError,omitempty" yaml:"nodeStateError,omitempty"`
+	// not part of the output; used to fetch the node states
+	passwordSecret string
+	annotations    map[string]string
+}
+
+// FleetSubclusterStatus holds the node counts of a single subcluster
+type FleetSubclusterStatus struct {
+	Name           string `json:"name" yaml:"name"`
+	Type           string `json:"type" yaml:"type"`
+	AddedToDBCount int32  `json:"addedToDBCount" yaml:"addedToDBCount"`
+	UpNodeCount    int32  `json:"upNodeCount" yaml:"upNodeCount"`
+	Shutdown       bool   `json:"shutdown,omitempty" yaml:"shutdown,omitempty"`
+}
+
+// FleetSandboxStatus holds the state of a sandbox
+type FleetSandboxStatus struct {
+	Name          string   `json:"name" yaml:"name"`
+	Subclusters   []string `json:"subclusters" yaml:"subclusters"`
+	UpgradeStatus string   `json:"upgradeStatus,omitempty" yaml:"upgradeStatus,omitempty"`
+}
+
+// FleetTLSConfigStatus holds the state of one TLS config
+type FleetTLSConfigStatus struct {
+	Name   string `json:"name" yaml:"name"`
+	Secret string `json:"secret" yaml:"secret"`
+	Mode   string `json:"mode" yaml:"mode"`
+}
+
+// FleetRestorePoint is the last restore point saved for a database
+type FleetRestorePoint struct {
+	Archive        string `json:"archive" yaml:"archive"`
+	StartTimestamp string `json:"startTimestamp" yaml:"startTimestamp"`
+	EndTimestamp   string `json:"endTimestamp" yaml:"endTimestamp"`
+}
+
+// FleetNodeState is the state of a node returned by VFetchNodeState
+type FleetNodeState struct {
+	Name       string `json:"name" yaml:"name"`
+	Address    string `json:"address" yaml:"address"`
+	State      string `json:"state" yaml:"state"`
+	Subcluster string `json:"subcluster,omitempty" yaml:"subcluster,omitempty"`
+	Sandbox    string `json:"sandbox,omitempty" yaml:"sandbox,omitempty"`
+	Version    string `json:"version,omitempty" yaml:"version,omitempty"`
+}
+
+func makeCmdFleetStatus() *cobra.Command {
+	newCmd := &CmdFleetStatus{}
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		fleetStatusSubCmd,
+		"Summarizes the status of every VerticaDB visible through a kubeconfig.",
+		`Lists every VerticaDB custom resource that the kubeconfig can see and
+summarizes its status:
+- Number of nodes that are added to the database and up, per subcluster
+- Upgrade status
+- Sandboxes
+- TLS config status
+- Last restore point
+
+For each database whose pods can be reached, the command also fetches the
+state of every node. Use --skip-node-state to only report what is stored in
+the VerticaDB status.
+
+Examples:
+  # Show every VerticaDB in all namespaces as a table
+  vcluster fleet_status
+
+  # Show the VerticaDBs of one namespace in JSON
+  vcluster fleet_status --namespace prod --format json
+
+  # Use a specific kubeconfig and context, without contacting the nodes
+  vcluster fleet_status --kubeconfig /path/to/kubeconfig --context prod-cluster \
+    --skip-node-state --format yaml
+`,
+		[]string{outputFileFlag},
+	)
+
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdFleetStatus) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&c.kubeconfig,
+		kubeconfigFlag,
+		"",
+		"Path to the kubeconfig file. If omitted, the KUBECONFIG environment variable, "+
+			"$HOME/.kube/config or the in-cluster config is used.",
+	)
+	cmd.Flags().StringVar(
+		&c.kubeContext,
+		kubeContextFlag,
+		"",
+		"The kubeconfig context to use. If omitted, the current context is used.",
+	)
+	cmd.Flags().StringVar(
+		&c.namespace,
+		namespaceFlag,
+		"",
+		"Only list the VerticaDBs in this namespace. If omitted, all namespaces are listed.",
+	)
+	cmd.Flags().StringVar(
+		&c.format,
+		formatFlag,
+		fleetFormatTable,
+		fmt.Sprintf("The output format. One of: %s, %s, %s.", fleetFormatTable, fleetFormatJSON, fleetFormatYAML),
+	)
+	cmd.Flags().BoolVar(
+		&c.skipNodeState,
+		skipNodeStateFlag,
+		false,
+		"Whether to skip fetching the node states from the databases",
+	)
+}
+
+func (c *CmdFleetStatus) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogMaskedArgParse(c.argv)
+
+	return c.validateParse(logger)
+}
+
+func (c *CmdFleetStatus) validateParse(logger vlog.Printer) error {
+	logger.Info("Called validateParse()", "command", fleetStatusSubCmd)
+	switch c.format {
+	case fleetFormatTable, fleetFormatJSON, fleetFormatYAML:
+	default:
+		return fmt.Errorf("invalid format %q, must be one of %s, %s or %s",
+			c.format, fleetFormatTable, fleetFormatJSON, fleetFormatYAML)
+	}
+	return c.getCertFilesFromCertPaths(&c.dbOptions)
+}
+
+func (c *CmdFleetStatus) Run(vcc vclusterops.ClusterCommands) error {
+	vcc.V(1).Info("Called method Run()")
+	ctx := context.Background()
+
+	restCfg, err := c.getRestConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load the kubeconfig: %w", err)
+	}
+	dynClient, err := dynamic.NewForConfig(restCfg)
+	if err != nil {
+		return fmt.Errorf("failed to create the kubernetes client: %w", err)
+	}
+	fleet, err := listFleetDBs(ctx, dynClient, c.namespace)
+	if err != nil {
+		vcc.LogError(err, "failed to list the VerticaDBs")
+		return err
+	}
+
+	if !c.skipNodeState {
+		clientset, err := kubernetes.NewForConfig(restCfg)
+		if err != nil {
+			return fmt.Errorf("failed to create the kubernetes client: %w", err)
+		}
+		logger := vcc.GetLog()
+		fetcher := secrets.MultiSourceSecretFetcher{
+			Log:       &logger,
+			K8sClient: &secrets.StandardK8sClient{Clientset: clientset, Config: restCfg},
+		}
+		for i := range fleet {
+			c.fetchNodeState(ctx, vcc, clientset, &fetcher, &fleet[i])
+		}
+	}
+
+	out, err := formatFleetStatus(fleet, c.format)
+	if err != nil {
+		return err
+	}
+	c.writeCmdOutputToFile(globals.file, out, vcc.GetLog())
+	vcc.DisplayInfo("Successfully listed %d VerticaDB(s)", len(fleet))
+	return nil
+}
+
+// SetDatabaseOptions is a no-op. The database options are taken from each VerticaDB.
+func (c *CmdFleetStatus) SetDatabaseOptions(_ *vclusterops.DatabaseOptions) {
+}
+
+// getRestConfig builds the rest config from the kubeconfig, following the
+// same precedence rules as kubectl
+func (c *CmdFleetStatus) getRestConfig() (*rest.Config, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if c.kubeconfig != "" {
+		loadingRules.ExplicitPath = c.kubeconfig
+	}
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: c.kubeContext}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+}
+
+// fetchNodeState calls VFetchNodeState against the running pods of a
+// VerticaDB. Any failure is recorded in the fleet entry rather than
+// returned, so that one unreachable database doesn't hide the others.
+func (c *CmdFleetStatus) fetchNodeState(ctx context.Context, vcc vclusterops.ClusterCommands,
+	clientset kubernetes.Interface, fetcher *secrets.MultiSourceSecretFetcher, db *FleetDBStatus) {
+	hosts, err := getFleetDBHosts(ctx, clientset, db)
+	if err != nil {
+		db.NodeStateError = err.Error()
+		return
+	}
+	if len(hosts) == 0 {
+		db.NodeStateError = "no running pods found"
+		return
+	}
+
+	opts := vclusterops.VFetchNodeStateOptionsFactory()
+	opts.DatabaseOptions.DBName = db.DBName
+	opts.DatabaseOptions.RawHosts = hosts
+	opts.DatabaseOptions.Key = c.dbOptions.Key
+	opts.DatabaseOptions.Cert = c.dbOptions.Cert
+	opts.DatabaseOptions.CaCert = c.dbOptions.CaCert
+	opts.SkipDownDatabase = true
+	opts.UserName = db.superuser()
+	opts.Password = new(string)
+	if db.passwordSecret != "" {
+		pwd, err := fetchFleetDBPassword(ctx, fetcher, db)
+		if err != nil {
+			db.NodeStateError = err.Error()
+			return
+		}
+		*opts.Password = pwd
+	}
+
+	nodeStates, err := vcc.VFetchNodeState(&opts)
+	if err != nil {
+		vcc.LogInfo("failed to fetch the node states", "namespace", db.Namespace, "name", db.Name, "error", err)
+		db.NodeStateError = fmt.Sprintf("%s: %s", fleetNodeStateUnreachable, err)
+		return
+	}
+	for i := range nodeStates {
+		db.Nodes = append(db.Nodes, FleetNodeState{
+			Name:       nodeStates[i].Name,
+			Address:    nodeStates[i].Address,
+			State:      nodeStates[i].State,
+			Subcluster: nodeStates[i].Subcluster,
+			Sandbox:    nodeStates[i].Sandbox,
+			Version:    nodeStates[i].Version,
+		})
+	}
+}
+
+// listFleetDBs returns the summary of every VerticaDB in the namespace. An
+// empty namespace lists the VerticaDBs of all namespaces.
+func listFleetDBs(ctx context.Context, dynClient dynamic.Interface, namespace string) ([]FleetDBStatus, error) {
+	list, err := dynClient.Resource(verticaDBGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list VerticaDBs: %w", err)
+	}
+	fleet := make([]FleetDBStatus, 0, len(list.Items))
+	for i := range list.Items {
+		db, err := buildFleetDBStatus(&list.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		fleet = append(fleet, db)
+	}
+	sort.Slice(fleet, func(i, j int) bool {
+		if fleet[i].Namespace != fleet[j].Namespace {
+			return fleet[i].Namespace < fleet[j].Namespace
+		}
+		return fleet[i].Name < fleet[j].Name
+	})
+	return fleet, nil
+}
+
+// buildFleetDBStatus converts a VerticaDB object into its fleet summary
+func buildFleetDBStatus(obj *unstructured.Unstructured) (FleetDBStatus, error) {
+	vdb := fleetVerticaDB{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &vdb); err != nil {
+		return FleetDBStatus{}, fmt.Errorf("failed to decode VerticaDB %s/%s: %w", obj.GetNamespace(), obj.GetName(), err)
+	}
+	db := FleetDBStatus{
+		Namespace:      vdb.Namespace,
+		Name:           vdb.Name,
+		DBName:         vdb.Spec.DBName,
+		AddedToDBCount: vdb.Status.AddedToDBCount,
+		UpNodeCount:    vdb.Status.UpNodeCount,
+		UpgradeStatus:  vdb.Status.UpgradeStatus,
+		RestorePoint:   vdb.Status.RestorePoint,
+		passwordSecret: vdb.Spec.PasswordSecret,
+		annotations:    vdb.Annotations,
+	}
+	// status holds the current password secret when it has been rotated
+	if vdb.Status.PasswordSecret != nil {
+		db.passwordSecret = *vdb.Status.PasswordSecret
+	}
+	for i := range vdb.Status.Subclusters {
+		sc := &vdb.Status.Subclusters[i]
+		db.Subclusters = append(db.Subclusters, FleetSubclusterStatus{
+			Name:           sc.Name,
+			Type:           sc.Type,
+			AddedToDBCount: sc.AddedToDBCount,
+			UpNodeCount:    sc.UpNodeCount,
+			Shutdown:       sc.Shutdown,
+		})
+	}
+	for i := range vdb.Status.Sandboxes {
+		sb := &vdb.Status.Sandboxes[i]
+		db.Sandboxes = append(db.Sandboxes, FleetSandboxStatus{
+			Name:          sb.Name,
+			Subclusters:   sb.Subclusters,
+			UpgradeStatus: sb.UpgradeState.UpgradeStatus,
+		})
+	}
+	for i := range vdb.Status.TLSConfigs {
+		tls := &vdb.Status.TLSConfigs[i]
+		db.TLSConfigs = append(db.TLSConfigs, FleetTLSConfigStatus{
+			Name:   tls.Name,
+			Secret: tls.Secret,
+			Mode:   tls.Mode,
+		})
+	}
+	return db, nil
+}
+
+// superuser returns the name of the database superuser
+func (f *FleetDBStatus) superuser() string {
+	return vmeta.GetSuperuserName(f.annotations)
+}
+
+// getFleetDBHosts returns the IPs of the running pods of a VerticaDB
+func getFleetDBHosts(ctx context.Context, clientset kubernetes.Interface, db *FleetDBStatus) ([]string, error) {
+	pods, err := clientset.CoreV1().Pods(db.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", vmeta.VDBInstanceLabel, db.Name),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the pods: %w", err)
+	}
+	hosts := []string{}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase == corev1.PodRunning && pod.Status.PodIP != "" {
+			hosts = append(hosts, pod.Status.PodIP)
+		}
+	}
+	return hosts, nil
+}
+
+// fetchFleetDBPassword reads the superuser password of a VerticaDB
+func fetchFleetDBPassword(ctx context.Context, fetcher *secrets.MultiSourceSecretFetcher, db *FleetDBStatus) (string, error) {
+	const passwordKey = "password"
+	data, err := fetcher.Fetch(ctx, types.NamespacedName{Namespace: db.Namespace, Name: db.passwordSecret})
+	if err != nil {
+		return "", fmt.Errorf("failed to read the password secret: %w", err)
+	}
+	pwd, ok := data[passwordKey]
+	if !ok {
+		return "", fmt.Errorf("password not found, secret must have a key with name %q", passwordKey)
+	}
+	return string(pwd), nil
+}
+
+// formatFleetStatus renders the fleet in the given output format
+func formatFleetStatus(fleet []FleetDBStatus, format string) ([]byte, error) {
+	switch format {
+	case fleetFormatJSON:
+		out, err := json.MarshalIndent(fleet, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal the fleet status: %w", err)
+		}
+		return append(out, '\n'), nil
+	case fleetFormatYAML:
+		out, err := yaml.Marshal(fleet)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal the fleet status: %w", err)
+		}
+		return out, nil
+	default:
+		return formatFleetStatusTable(fleet), nil
+	}
+}
+
+// formatFleetStatusTable renders the fleet as a table with one row per VerticaDB
+func formatFleetStatusTable(fleet []FleetDBStatus) []byte {
+	var buf bytes.Buffer
+	const padding = 2
+	w := tabwriter.NewWriter(&buf, 0, 0, padding, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tNAME\tDATABASE\tUP/ADDED\tSUBCLUSTERS\tUPGRADE\tSANDBOXES\tTLS\tRESTORE POINT\tNODES")
+	for i := range fleet {
+		db := &fleet[i]
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d/%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			db.Namespace, db.Name, orNone(db.DBName), db.UpNodeCount, db.AddedToDBCount,
+			db.subclustersColumn(), orNone(db.UpgradeStatus), db.sandboxesColumn(),
+			db.tlsColumn(), db.restorePointColumn(), db.nodesColumn())
+	}
+	w.Flush()
+	return buf.Bytes()
+}
+
+func (f *FleetDBStatus) subclustersColumn() string {
+	cols := []string{}
+	for i := range f.Subclusters {
+		sc := &f.Subclusters[i]
+		cols = append(cols, fmt.Sprintf("%s(%d/%d)", sc.Name, sc.UpNodeCount, sc.AddedToDBCount))
+	}
+	return orNone(strings.Join(cols, ","))
+}
+
+func (f *FleetDBStatus) sandboxesColumn() string {
+	cols := []string{}
+	for i := range f.Sandboxes {
+		cols = append(cols, f.Sandboxes[i].Name)
+	}
+	return orNone(strings.Join(cols, ","))
+}
+
+func (f *FleetDBStatus) tlsColumn() string {
+	cols := []string{}
+	for i := range f.TLSConfigs {
+		cols = append(cols, fmt.Sprintf("%s=%s", f.TLSConfigs[i].Name, orNone(f.TLSConfigs[i].Mode)))
+	}
+	return orNone(strings.Join(cols, ","))
+}
+
+func (f *FleetDBStatus) restorePointColumn() string {
+	if f.RestorePoint == nil || f.RestorePoint.Archive == "" {
+		return orNone("")
+	}
+	return fmt.Sprintf("%s@%s", f.RestorePoint.Archive, f.RestorePoint.EndTimestamp)
+}
+
+func (f *FleetDBStatus) nodesColumn() string {
+	if f.NodeStateError != "" {
+		return fleetNodeStateUnreachable
+	}
+	if len(f.Nodes) == 0 {
+		return orNone("")
+	}
+	up := 0
+	for i := range f.Nodes {
+		if f.Nodes[i].State == util.NodeUpState {
+			up++
+		}
+	}
+	return fmt.Sprintf("%d/%d UP", up, len(f.Nodes))
+}
+
+// orNone returns "<none>" for an empty table cell
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
@@ -0,0 +1,33 @@
+/*
+ (c) Copyright [2023-2025] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func makeCmdHealthWatchdog() *cobra.Command {
+	cmd := makeSimpleCobraCmd(
+		healthWatchdogCmd,
+		"Manages the database health watchdog.",
+		`Checks the cluster health, configures the health watchdog parameters and
+policy, or cancels the queries the health watchdog reports.`)
+
+	cmd.AddCommand(makeCmdHealthWatchdogGet())
+	cmd.AddCommand(makeCmdHealthWatchdogSet())
+	cmd.AddCommand(makeCmdHealthWatchdogCancelQuery())
+	return cmd
+}
@@ -0,0 +1,172 @@
+/*
+ (c) Copyright [2023-2025] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdHealthWatchdogCancelQuery
+ *
+ * Implements ClusterCommand interface
+ */
+type CmdHealthWatchdogCancelQuery struct {
+	cancelOptions *vclusterops.VHealthWatchdogCancelQueryOptions
+	sessions      []string
+	CmdBase
+}
+
+func makeCmdHealthWatchdogCancelQuery() *cobra.Command {
+	newCmd := &CmdHealthWatchdogCancelQuery{}
+	opt := vclusterops.VHealthWatchdogCancelQueryOptionsFactory()
+	newCmd.cancelOptions = &opt
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		watchdogCancelSubCmd,
+		"Cancel sessions or statements through the health watchdog.",
+		`Cancels sessions, or single statements within them, through the health
+watchdog and displays the result of each cancellation as JSON.
+
+Each --session is given as SESSION_ID, to cancel the whole session, or as
+SESSION_ID=STATEMENT_ID, to only cancel one of its statements.
+
+Examples:
+  # Cancel a session with user input
+  vcluster health_watchdog cancel_query --db-name test_db \
+    --hosts 10.20.30.40,10.20.30.41,10.20.30.42 \
+    --session v_test_db_node0001-12345:0x1a2b \
+    --password "PASSWORD"
+
+  # Cancel a statement and a session with config file
+  vcluster health_watchdog cancel_query \
+    --session v_test_db_node0001-12345:0x1a2b=3 \
+    --session v_test_db_node0002-67890:0x3c4d \
+    --config /opt/vertica/config/vertica_cluster.yaml \
+    --password "PASSWORD"
+`,
+		[]string{dbNameFlag, configFlag, passwordFlag, dbUserFlag, hostsFlag, ipv6Flag, outputFileFlag},
+	)
+
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
+	markFlagsRequired(cmd, sessionFlag)
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdHealthWatchdogCancelQuery) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(
+		&c.sessions,
+		sessionFlag,
+		[]string{},
+		"The session to cancel, as SESSION_ID or SESSION_ID=STATEMENT_ID. Can be repeated.",
+	)
+}
+
+func (c *CmdHealthWatchdogCancelQuery) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogMaskedArgParse(c.argv)
+
+	// for some options, we do not want to use their default values,
+	// if they are not provided in cli,
+	// reset the value of those options to nil
+	c.ResetUserInputOptions(&c.cancelOptions.DatabaseOptions)
+	return c.validateParse(logger)
+}
+
+func (c *CmdHealthWatchdogCancelQuery) validateParse(logger vlog.Printer) error {
+	logger.Info("Called validateParse()")
+
+	sessions, err := parseWatchdogSessions(c.sessions)
+	if err != nil {
+		return err
+	}
+	c.cancelOptions.Sessions = sessions
+
+	if !c.usePassword() {
+		err = c.getCertFilesFromCertPaths(&c.cancelOptions.DatabaseOptions)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = c.ValidateParseBaseOptions(&c.cancelOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+	return c.setDBPassword(&c.cancelOptions.DatabaseOptions)
+}
+
+// parseWatchdogSessions converts the --session values into the sessions to cancel.
+// Session IDs contain colons, so the statement ID is separated with '='.
+func parseWatchdogSessions(values []string) ([]vclusterops.HealthWatchdogCancelQueryOptions, error) {
+	sessions := make([]vclusterops.HealthWatchdogCancelQueryOptions, 0, len(values))
+	for _, v := range values {
+		sessionID, stmtID, hasStmt := strings.Cut(v, "=")
+		if sessionID == "" {
+			return nil, fmt.Errorf("invalid --%s %q: the session ID is empty", sessionFlag, v)
+		}
+		session := vclusterops.HealthWatchdogCancelQueryOptions{SessionID: sessionID}
+		if hasStmt {
+			id, err := strconv.ParseInt(stmtID, 10, 64)
+			if err != nil || id <= 0 {
+				return nil, fmt.Errorf("invalid --%s %q: the statement ID must be a positive integer", sessionFlag, v)
+			}
+			session.StatementID = id
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+func (c *CmdHealthWatchdogCancelQuery) Run(vcc vclusterops.ClusterCommands) error {
+	vcc.LogInfo("Called method Run()")
+
+	options := c.cancelOptions
+	responses, err := vcc.VHealthWatchdogCancelQuery(options)
+	if err != nil {
+		vcc.LogError(err, "failed to cancel queries through the health watchdog")
+		return err
+	}
+
+	if responses == nil {
+		responses = []vclusterops.HealthWatchdogCancelQueryResponse{}
+	}
+	bytes, err := json.MarshalIndent(responses, "", "  ")
+	if err != nil {
+		return err
+	}
+	bytes = append(bytes, '\n')
+	c.writeCmdOutputToFile(globals.file, bytes, vcc.GetLog())
+
+	vcc.DisplayInfo("Successfully sent %d cancellation request(s) to the health watchdog", len(options.Sessions))
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance to the one in CmdHealthWatchdogCancelQuery
+func (c *CmdHealthWatchdogCancelQuery) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.cancelOptions.DatabaseOptions = *opt
+}
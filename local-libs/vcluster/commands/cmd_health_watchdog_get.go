@@ -0,0 +1,143 @@
+/*
+ (c) Copyright [2023-2025] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/json"
+
+	"github.com/spf13/cobra"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// The health watchdog get action that checks the cluster health
+const checkClusterHealthAction = "check_cluster_health"
+
+/* CmdHealthWatchdogGet
+ *
+ * Implements ClusterCommand interface
+ */
+type CmdHealthWatchdogGet struct {
+	getOptions *vclusterops.VHealthWatchdogGetOptions
+	CmdBase
+}
+
+func makeCmdHealthWatchdogGet() *cobra.Command {
+	newCmd := &CmdHealthWatchdogGet{}
+	opt := vclusterops.VHealthWatchdogGetValueOptionsFactory()
+	newCmd.getOptions = &opt
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		watchdogGetSubCmd,
+		"Get values from the health watchdog.",
+		`Gets values from the health watchdog of each up host and displays them as
+JSON. By default, it checks the cluster health, which is only sent to one
+initiator host.
+
+Examples:
+  # Check the cluster health with user input
+  vcluster health_watchdog get --db-name test_db \
+    --hosts 10.20.30.40,10.20.30.41,10.20.30.42 \
+    --password "PASSWORD"
+
+  # Check the cluster health with config file and save the result to a file
+  vcluster health_watchdog get --output-file /tmp/cluster_health.json \
+    --config /opt/vertica/config/vertica_cluster.yaml \
+    --password "PASSWORD"
+`,
+		[]string{dbNameFlag, configFlag, passwordFlag, dbUserFlag, hostsFlag, ipv6Flag, outputFileFlag},
+	)
+
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdHealthWatchdogGet) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&c.getOptions.Action,
+		watchdogActionFlag,
+		checkClusterHealthAction,
+		"The health watchdog action to run.",
+	)
+	cmd.Flags().StringVar(
+		&c.getOptions.ParameterName,
+		parameterNameFlag,
+		"",
+		"The name of the health watchdog parameter to get, for the actions that need one.",
+	)
+}
+
+func (c *CmdHealthWatchdogGet) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogMaskedArgParse(c.argv)
+
+	// for some options, we do not want to use their default values,
+	// if they are not provided in cli,
+	// reset the value of those options to nil
+	c.ResetUserInputOptions(&c.getOptions.DatabaseOptions)
+	return c.validateParse(logger)
+}
+
+func (c *CmdHealthWatchdogGet) validateParse(logger vlog.Printer) error {
+	logger.Info("Called validateParse()")
+
+	if !c.usePassword() {
+		err := c.getCertFilesFromCertPaths(&c.getOptions.DatabaseOptions)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := c.ValidateParseBaseOptions(&c.getOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+	return c.setDBPassword(&c.getOptions.DatabaseOptions)
+}
+
+func (c *CmdHealthWatchdogGet) Run(vcc vclusterops.ClusterCommands) error {
+	vcc.LogInfo("Called method Run()")
+
+	options := c.getOptions
+	hostValues, err := vcc.VHealthWatchdogGet(options)
+	if err != nil {
+		vcc.LogError(err, "failed to get health watchdog values", "action", options.Action)
+		return err
+	}
+
+	result := []vclusterops.HealthWatchdogHostValues{}
+	if hostValues != nil {
+		result = *hostValues
+	}
+	bytes, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	bytes = append(bytes, '\n')
+	c.writeCmdOutputToFile(globals.file, bytes, vcc.GetLog())
+
+	vcc.DisplayInfo("Successfully ran health watchdog action %s", options.Action)
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance to the one in CmdHealthWatchdogGet
+func (c *CmdHealthWatchdogGet) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.getOptions.DatabaseOptions = *opt
+}
@@ -0,0 +1,203 @@
+/*
+ (c) Copyright [2023-2025] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// The health watchdog set actions
+const (
+	setConfigParameterAction   = "set_config_parameter"
+	clearConfigParameterAction = "clear_config_parameter"
+	setWatchdogPolicyAction    = "set_health_watchdog_policy"
+)
+
+/* CmdHealthWatchdogSet
+ *
+ * Implements ClusterCommand interface
+ */
+type CmdHealthWatchdogSet struct {
+	setOptions     *vclusterops.VHealthWatchdogSetOptions
+	clearParameter bool
+	CmdBase
+}
+
+// healthWatchdogSetResult is the JSON output of health_watchdog set
+type healthWatchdogSetResult struct {
+	Action         string            `json:"action"`
+	ParameterName  string            `json:"parameter_name,omitempty"`
+	Value          string            `json:"value,omitempty"`
+	PolicySettings map[string]string `json:"policy_settings,omitempty"`
+}
+
+func makeCmdHealthWatchdogSet() *cobra.Command {
+	newCmd := &CmdHealthWatchdogSet{}
+	opt := vclusterops.VHealthWatchdogSetOptionsFactory()
+	newCmd.setOptions = &opt
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		watchdogSetSubCmd,
+		"Configure the health watchdog.",
+		`Sets or clears a health watchdog parameter, or sets the health watchdog
+policy, on each up host. Exactly one of --parameter or --policy must be given.
+
+Examples:
+  # Set a health watchdog parameter with user input
+  vcluster health_watchdog set --db-name test_db \
+    --hosts 10.20.30.40,10.20.30.41,10.20.30.42 \
+    --parameter HealthWatchdogMaxQueryDuration --value 600 \
+    --password "PASSWORD"
+
+  # Clear a health watchdog parameter with config file
+  vcluster health_watchdog set --parameter HealthWatchdogMaxQueryDuration --clear \
+    --config /opt/vertica/config/vertica_cluster.yaml \
+    --password "PASSWORD"
+
+  # Set the health watchdog policy with config file
+  vcluster health_watchdog set --policy cancel_runaway_queries=true,interval=60 \
+    --config /opt/vertica/config/vertica_cluster.yaml \
+    --password "PASSWORD"
+`,
+		[]string{dbNameFlag, configFlag, passwordFlag, dbUserFlag, hostsFlag, ipv6Flag, outputFileFlag},
+	)
+
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
+	markFlagsOneRequired(cmd, []string{parameterNameFlag, policySettingsFlag})
+	cmd.MarkFlagsMutuallyExclusive(parameterNameFlag, policySettingsFlag)
+	cmd.MarkFlagsMutuallyExclusive(parameterValueFlag, clearParameterFlag)
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdHealthWatchdogSet) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&c.setOptions.ParameterName,
+		parameterNameFlag,
+		"",
+		"The name of the health watchdog parameter to set or clear.",
+	)
+	cmd.Flags().StringVar(
+		&c.setOptions.Value,
+		parameterValueFlag,
+		"",
+		"The value to set the health watchdog parameter to.",
+	)
+	cmd.Flags().BoolVar(
+		&c.clearParameter,
+		clearParameterFlag,
+		false,
+		"Clear the health watchdog parameter instead of setting it.",
+	)
+	cmd.Flags().StringToStringVar(
+		&c.setOptions.PolicySettings,
+		policySettingsFlag,
+		map[string]string{},
+		"Comma-separated list of key=value pairs that make up the health watchdog policy.",
+	)
+}
+
+func (c *CmdHealthWatchdogSet) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogMaskedArgParse(c.argv)
+
+	// for some options, we do not want to use their default values,
+	// if they are not provided in cli,
+	// reset the value of those options to nil
+	c.ResetUserInputOptions(&c.setOptions.DatabaseOptions)
+	return c.validateParse(logger)
+}
+
+func (c *CmdHealthWatchdogSet) validateParse(logger vlog.Printer) error {
+	logger.Info("Called validateParse()")
+
+	err := c.setAction()
+	if err != nil {
+		return err
+	}
+
+	if !c.usePassword() {
+		err = c.getCertFilesFromCertPaths(&c.setOptions.DatabaseOptions)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = c.ValidateParseBaseOptions(&c.setOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+	return c.setDBPassword(&c.setOptions.DatabaseOptions)
+}
+
+// setAction derives the health watchdog action from the flags that were given
+func (c *CmdHealthWatchdogSet) setAction() error {
+	switch {
+	case len(c.setOptions.PolicySettings) > 0:
+		c.setOptions.Action = setWatchdogPolicyAction
+	case c.clearParameter:
+		c.setOptions.Action = clearConfigParameterAction
+	case c.setOptions.ParameterName != "":
+		if c.setOptions.Value == "" {
+			return fmt.Errorf("--%s or --%s must be given with --%s",
+				parameterValueFlag, clearParameterFlag, parameterNameFlag)
+		}
+		c.setOptions.Action = setConfigParameterAction
+	default:
+		return fmt.Errorf("one of --%s or --%s must be given", parameterNameFlag, policySettingsFlag)
+	}
+	return nil
+}
+
+func (c *CmdHealthWatchdogSet) Run(vcc vclusterops.ClusterCommands) error {
+	vcc.LogInfo("Called method Run()")
+
+	options := c.setOptions
+	err := vcc.VHealthWatchdogSet(options)
+	if err != nil {
+		vcc.LogError(err, "failed to configure the health watchdog", "action", options.Action)
+		return err
+	}
+
+	bytes, err := json.MarshalIndent(healthWatchdogSetResult{
+		Action:         options.Action,
+		ParameterName:  options.ParameterName,
+		Value:          options.Value,
+		PolicySettings: options.PolicySettings,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	bytes = append(bytes, '\n')
+	c.writeCmdOutputToFile(globals.file, bytes, vcc.GetLog())
+
+	vcc.DisplayInfo("Successfully ran health watchdog action %s", options.Action)
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance to the one in CmdHealthWatchdogSet
+func (c *CmdHealthWatchdogSet) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.setOptions.DatabaseOptions = *opt
+}
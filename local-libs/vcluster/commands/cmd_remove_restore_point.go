@@ -0,0 +1,168 @@
+/*
+ (c) Copyright [2023-2025] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdRemoveRestorePoint
+ *
+ * Parses arguments to remove_restore_point and calls
+ * the high-level function for remove_restore_point.
+ *
+ * Implements ClusterCommand interface
+ */
+
+type CmdRemoveRestorePoint struct {
+	CmdBase
+	removeRestorePointOptions *vclusterops.VRemoveRestorePointOptions
+}
+
+func makeCmdRemoveRestorePoint() *cobra.Command {
+	newCmd := &CmdRemoveRestorePoint{}
+	opt := vclusterops.VRemoveRestorePointFactory()
+	newCmd.removeRestorePointOptions = &opt
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		removeRestorePointSubCmd,
+		"Remove a restore point from a given archive.",
+		`Remove a restore point from a given archive. The restore point is
+identified by its index in the archive. Index 1 is the most recent restore
+point. Removing a restore point shifts the indexes of the older ones down by
+one.
+
+Examples:
+  # Remove the oldest of three restore points by its index
+  vcluster remove_restore_point --db-name test_db \
+	--archive-name ARCHIVE_ONE --restore-point-index 3 \
+	--password "PASSWORD"
+
+`,
+		[]string{dbNameFlag, hostsFlag, passwordFlag,
+			ipv6Flag, configFlag, eonModeFlag},
+	)
+
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
+	// require db-name, archive-name and restore-point-index
+	markFlagsRequired(cmd, dbNameFlag, archiveNameFlag, "restore-point-index")
+
+	// hide this subcommand
+	cmd.Hidden = true
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdRemoveRestorePoint) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&c.removeRestorePointOptions.ArchiveName,
+		archiveNameFlag,
+		"",
+		"Collection of restore points that belong to a certain archive.",
+	)
+	cmd.Flags().IntVar(
+		&c.removeRestorePointOptions.Index,
+		"restore-point-index",
+		0,
+		"The index of the restore point to remove. Restore point indexes are one-indexed.",
+	)
+	cmd.Flags().StringVar(
+		&c.removeRestorePointOptions.Sandbox,
+		sandboxFlag,
+		"",
+		"The name of target sandbox",
+	)
+}
+
+func (c *CmdRemoveRestorePoint) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogMaskedArgParse(c.argv)
+
+	// for some options, we do not want to use their default values,
+	// if they are not provided in cli,
+	// reset the value of those options to nil
+	c.ResetUserInputOptions(&c.removeRestorePointOptions.DatabaseOptions)
+
+	// remove_restore_point only works for an Eon db so we assume the user always runs this subcommand
+	// on an Eon db. When Eon mode cannot be found in config file, we set its value to true.
+	if !viper.IsSet(eonModeKey) {
+		c.removeRestorePointOptions.IsEon = true
+	}
+
+	return c.validateParse(logger)
+}
+
+// all validations of the arguments should go in here
+func (c *CmdRemoveRestorePoint) validateParse(logger vlog.Printer) error {
+	logger.Info("Called validateParse()")
+
+	err := c.ValidateParseBaseOptions(&c.removeRestorePointOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	if !c.usePassword() {
+		err = c.getCertFilesFromCertPaths(&c.removeRestorePointOptions.DatabaseOptions)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = c.setConfigParam(&c.removeRestorePointOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	err = c.setDBPassword(&c.removeRestorePointOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *CmdRemoveRestorePoint) Analyze(logger vlog.Printer) error {
+	logger.Info("Called method Analyze()")
+	return nil
+}
+
+func (c *CmdRemoveRestorePoint) Run(vcc vclusterops.ClusterCommands) error {
+	vcc.LogInfo("Called method Run()")
+
+	options := c.removeRestorePointOptions
+
+	err := vcc.VRemoveRestorePoint(options)
+	if err != nil {
+		vcc.LogError(err, "failed to remove restore point", "DBName", options.DBName)
+		return err
+	}
+
+	vcc.DisplayInfo("Successfully removed restore point from archive %s in database %s",
+		options.ArchiveName, options.DBName)
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance to the one in CmdRemoveRestorePoint
+func (c *CmdRemoveRestorePoint) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.removeRestorePointOptions.DatabaseOptions = *opt
+}
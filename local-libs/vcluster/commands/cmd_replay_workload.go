@@ -0,0 +1,195 @@
+/*
+ (c) Copyright [2023-2025] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+/* CmdReplayWorkload
+ *
+ * Implements ClusterCommand interface
+ */
+type CmdReplayWorkload struct {
+	replayOptions *vclusterops.VWorkloadReplayOptions
+	CmdBase
+}
+
+// workloadReplayResult is the JSON output of replay_workload
+type workloadReplayResult struct {
+	JobID             int64  `json:"job_id"`
+	Sandbox           string `json:"sandbox"`
+	WorkloadFile      string `json:"workload_file"`
+	ReplayResultsFile string `json:"replay_results_file"`
+}
+
+func makeCmdReplayWorkload() *cobra.Command {
+	newCmd := &CmdReplayWorkload{}
+	opt := vclusterops.VWorkloadReplayOptionsFactory()
+	newCmd.replayOptions = &opt
+
+	cmd := makeBasicCobraCmd(
+		newCmd,
+		replayWorkloadSubCmd,
+		"Replay a captured workload against a sandbox.",
+		`Replays a workload, captured with the capture_workload command, against
+the subclusters of a sandbox and saves a report that compares the replayed
+queries with the original ones.
+
+The replay is identified by a job ID. If --job-id is not given, one is
+generated and displayed so that the replay can be stopped with the
+cancel_workload command. Interrupting the command also stops the replay; the
+report is still saved for the queries that ran.
+
+Examples:
+  # Replay a workload in sandbox sand1 with user input
+  vcluster replay_workload --db-name test_db \
+    --hosts 10.20.30.40,10.20.30.41,10.20.30.42 \
+    --sandbox sand1 --workload-file /tmp/workload.csv \
+    --replay-results-file /tmp/replay.csv --password "PASSWORD"
+
+  # Replay a workload in sandbox sand1 with config file
+  vcluster replay_workload --sandbox sand1 --job-id 1001 \
+    --workload-file /tmp/workload.csv --replay-results-file /tmp/replay.csv \
+    --config /opt/vertica/config/vertica_cluster.yaml \
+    --password "PASSWORD"
+`,
+		[]string{dbNameFlag, configFlag, passwordFlag, dbUserFlag, hostsFlag, ipv6Flag, outputFileFlag},
+	)
+
+	// local flags
+	newCmd.setLocalFlags(cmd)
+
+	markFlagsRequired(cmd, sandboxFlag, workloadFileFlag, replayResultsFileFlag)
+	markFlagsFileName(cmd, map[string][]string{
+		workloadFileFlag:      {"csv"},
+		replayResultsFileFlag: {"csv"},
+	})
+
+	return cmd
+}
+
+// setLocalFlags will set the local flags the command has
+func (c *CmdReplayWorkload) setLocalFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&c.replayOptions.Sandbox,
+		sandboxFlag,
+		"",
+		"The name of the sandbox to replay the workload in.",
+	)
+	cmd.Flags().StringVar(
+		&c.replayOptions.WorkloadFileLocation,
+		workloadFileFlag,
+		"",
+		"The absolute path of the CSV file produced by capture_workload.",
+	)
+	cmd.Flags().StringVar(
+		&c.replayOptions.ReplayResultsFileLocation,
+		replayResultsFileFlag,
+		"",
+		"The absolute path of the CSV file the replay report is saved to. It must not exist yet.",
+	)
+	cmd.Flags().Int64Var(
+		&c.replayOptions.JobID,
+		jobIDFlag,
+		0,
+		"The ID of the replay job. A new one is generated if not given.",
+	)
+}
+
+func (c *CmdReplayWorkload) Parse(inputArgv []string, logger vlog.Printer) error {
+	c.argv = inputArgv
+	logger.LogMaskedArgParse(c.argv)
+
+	// for some options, we do not want to use their default values,
+	// if they are not provided in cli,
+	// reset the value of those options to nil
+	c.ResetUserInputOptions(&c.replayOptions.DatabaseOptions)
+	return c.validateParse(logger)
+}
+
+func (c *CmdReplayWorkload) validateParse(logger vlog.Printer) error {
+	logger.Info("Called validateParse()")
+
+	if c.replayOptions.JobID == 0 {
+		c.replayOptions.JobID = time.Now().Unix()
+	}
+
+	if !c.usePassword() {
+		err := c.getCertFilesFromCertPaths(&c.replayOptions.DatabaseOptions)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := c.ValidateParseBaseOptions(&c.replayOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+	err = c.setDBPassword(&c.replayOptions.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+	// the operator passes a password secret stored outside of k8s through
+	// environment variables rather than a mounted file
+	return dbPasswordLookupFromSecretStore(logger, secretStoreRetrieverStruct{}, &c.replayOptions.DatabaseOptions)
+}
+
+func (c *CmdReplayWorkload) Run(vcc vclusterops.ClusterCommands) error {
+	vcc.LogInfo("Called method Run()")
+
+	options := c.replayOptions
+	vcc.DisplayInfo("Replaying workload in sandbox %s with job ID %d", options.Sandbox, options.JobID)
+
+	// stop the replay, and still save its report, if the user interrupts the command
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := vcc.VWorkloadReplay(ctx, options)
+	if err != nil {
+		vcc.LogError(err, "failed to replay workload", "sandbox", options.Sandbox, "jobID", options.JobID)
+		return err
+	}
+
+	bytes, err := json.MarshalIndent(workloadReplayResult{
+		JobID:             options.JobID,
+		Sandbox:           options.Sandbox,
+		WorkloadFile:      options.WorkloadFileLocation,
+		ReplayResultsFile: options.ReplayResultsFileLocation,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	bytes = append(bytes, '\n')
+	c.writeCmdOutputToFile(globals.file, bytes, vcc.GetLog())
+
+	vcc.DisplayInfo("Successfully replayed workload, report saved to %s", options.ReplayResultsFileLocation)
+	return nil
+}
+
+// SetDatabaseOptions will assign a vclusterops.DatabaseOptions instance to the one in CmdReplayWorkload
+func (c *CmdReplayWorkload) SetDatabaseOptions(opt *vclusterops.DatabaseOptions) {
+	c.replayOptions.DatabaseOptions = *opt
+}
@@ -0,0 +1,165 @@
+/*
+ (c) Copyright [2023-2025] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+)
+
+func makeFleetVDB(namespace, name string, status map[string]any) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "vertica.com/v1",
+		"kind":       "VerticaDB",
+		"metadata": map[string]any{
+			"namespace": namespace,
+			"name":      name,
+		},
+		"spec": map[string]any{
+			"dbName":         "vertdb",
+			"passwordSecret": "su-pwd",
+		},
+		"status": status,
+	}}
+}
+
+func TestListFleetDBs(t *testing.T) {
+	vdb1 := makeFleetVDB("prod", "vdb-b", map[string]any{
+		"addedToDBCount": int64(3),
+		"upNodeCount":    int64(2),
+		"upgradeStatus":  "Checking if new version is compatible",
+		"subclusters": []any{
+			map[string]any{"name": "sc1", "type": "primary", "addedToDBCount": int64(3), "upNodeCount": int64(2), "detail": []any{}},
+		},
+		"sandboxes": []any{
+			map[string]any{"name": "sand1", "subclusters": []any{"sc2"}},
+		},
+		"tlsConfigs": []any{
+			map[string]any{"name": "https", "secret": "https-cert", "mode": "try_verify"},
+		},
+		"restorePoint":   map[string]any{"archive": "nightly", "startTimestamp": "2025-01-01", "endTimestamp": "2025-01-02"},
+		"passwordSecret": "su-pwd-rotated",
+	})
+	vdb2 := makeFleetVDB("dev", "vdb-a", map[string]any{})
+	vdb3 := makeFleetVDB("prod", "vdb-a", map[string]any{})
+
+	dynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{verticaDBGVR: "VerticaDBList"}, vdb1, vdb2, vdb3)
+
+	fleet, err := listFleetDBs(context.Background(), dynClient, "")
+	assert.NoError(t, err)
+	assert.Len(t, fleet, 3)
+	// sorted by namespace then name
+	assert.Equal(t, "dev", fleet[0].Namespace)
+	assert.Equal(t, "vdb-a", fleet[1].Name)
+	assert.Equal(t, "vdb-b", fleet[2].Name)
+
+	db := fleet[2]
+	assert.Equal(t, "vertdb", db.DBName)
+	assert.Equal(t, int32(3), db.AddedToDBCount)
+	assert.Equal(t, int32(2), db.UpNodeCount)
+	assert.Equal(t, []FleetSubclusterStatus{{Name: "sc1", Type: "primary", AddedToDBCount: 3, UpNodeCount: 2}}, db.Subclusters)
+	assert.Equal(t, []FleetSandboxStatus{{Name: "sand1", Subclusters: []string{"sc2"}}}, db.Sandboxes)
+	assert.Equal(t, []FleetTLSConfigStatus{{Name: "https", Secret: "https-cert", Mode: "try_verify"}}, db.TLSConfigs)
+	assert.Equal(t, &FleetRestorePoint{Archive: "nightly", StartTimestamp: "2025-01-01", EndTimestamp: "2025-01-02"},
+		db.RestorePoint)
+	// the password secret in the status takes precedence over the spec
+	assert.Equal(t, "su-pwd-rotated", db.passwordSecret)
+	assert.Equal(t, "dbadmin", db.superuser())
+
+	fleet, err = listFleetDBs(context.Background(), dynClient, "prod")
+	assert.NoError(t, err)
+	assert.Len(t, fleet, 2)
+}
+
+func TestGetFleetDBHosts(t *testing.T) {
+	makePod := func(name, ip string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "prod",
+				Name:      name,
+				Labels:    map[string]string{"app.kubernetes.io/instance": "vdb"},
+			},
+			Status: corev1.PodStatus{Phase: phase, PodIP: ip},
+		}
+	}
+	other := makePod("other-0", "10.0.0.9", corev1.PodRunning)
+	other.Labels["app.kubernetes.io/instance"] = "other"
+	clientset := kubefake.NewSimpleClientset(
+		makePod("vdb-sc1-0", "10.0.0.1", corev1.PodRunning),
+		makePod("vdb-sc1-1", "10.0.0.2", corev1.PodPending),
+		makePod("vdb-sc1-2", "", corev1.PodRunning),
+		other,
+	)
+	hosts, err := getFleetDBHosts(context.Background(), clientset, &FleetDBStatus{Namespace: "prod", Name: "vdb"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1"}, hosts)
+}
+
+func TestFormatFleetStatus(t *testing.T) {
+	fleet := []FleetDBStatus{
+		{
+			Namespace:      "prod",
+			Name:           "vdb",
+			DBName:         "vertdb",
+			AddedToDBCount: 3,
+			UpNodeCount:    3,
+			Subclusters:    []FleetSubclusterStatus{{Name: "sc1", AddedToDBCount: 3, UpNodeCount: 3}},
+			RestorePoint:   &FleetRestorePoint{Archive: "nightly", EndTimestamp: "2025-01-02"},
+			Nodes: []FleetNodeState{
+				{Name: "v_vertdb_node0001", State: "UP"},
+				{Name: "v_vertdb_node0002", State: "DOWN"},
+			},
+		},
+		{
+			Namespace:      "dev",
+			Name:           "vdb",
+			NodeStateError: "no running pods found",
+		},
+	}
+
+	out, err := formatFleetStatus(fleet, fleetFormatTable)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	assert.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "UP/ADDED")
+	assert.Contains(t, lines[1], "sc1(3/3)")
+	assert.Contains(t, lines[1], "nightly@2025-01-02")
+	assert.Contains(t, lines[1], "1/2 UP")
+	assert.Contains(t, lines[2], fleetNodeStateUnreachable)
+
+	out, err = formatFleetStatus(fleet, fleetFormatJSON)
+	assert.NoError(t, err)
+	var decoded []FleetDBStatus
+	assert.NoError(t, json.Unmarshal(out, &decoded))
+	assert.Equal(t, "vertdb", decoded[0].DBName)
+	assert.NotContains(t, string(out), "passwordSecret")
+
+	out, err = formatFleetStatus(fleet, fleetFormatYAML)
+	assert.NoError(t, err)
+	assert.Contains(t, string(out), "nodeStateError: no running pods found")
+}
@@ -0,0 +1,64 @@
+/*
+ (c) Copyright [2023-2025] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops"
+)
+
+func TestParseWatchdogSessions(t *testing.T) {
+	sessions, err := parseWatchdogSessions([]string{
+		"v_db_node0001-12345:0x1a2b",
+		"v_db_node0002-67890:0x3c4d=3",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []vclusterops.HealthWatchdogCancelQueryOptions{
+		{SessionID: "v_db_node0001-12345:0x1a2b"},
+		{SessionID: "v_db_node0002-67890:0x3c4d", StatementID: 3},
+	}, sessions)
+
+	_, err = parseWatchdogSessions([]string{"=3"})
+	assert.ErrorContains(t, err, "the session ID is empty")
+
+	_, err = parseWatchdogSessions([]string{"v_db_node0001-12345:0x1a2b=abc"})
+	assert.ErrorContains(t, err, "the statement ID must be a positive integer")
+}
+
+func TestHealthWatchdogSetAction(t *testing.T) {
+	opt := vclusterops.VHealthWatchdogSetOptionsFactory()
+	c := CmdHealthWatchdogSet{setOptions: &opt}
+
+	opt.ParameterName = "p1"
+	assert.ErrorContains(t, c.setAction(), "--value or --clear must be given with --parameter")
+
+	opt.Value = "10"
+	assert.NoError(t, c.setAction())
+	assert.Equal(t, setConfigParameterAction, opt.Action)
+
+	opt.Value = ""
+	c.clearParameter = true
+	assert.NoError(t, c.setAction())
+	assert.Equal(t, clearConfigParameterAction, opt.Action)
+
+	opt.ParameterName = ""
+	c.clearParameter = false
+	opt.PolicySettings = map[string]string{"k": "v"}
+	assert.NoError(t, c.setAction())
+	assert.Equal(t, setWatchdogPolicyAction, opt.Action)
+}
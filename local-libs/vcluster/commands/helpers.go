@@ -55,6 +55,33 @@ func isK8sEnvironment() bool {
 	return portSet && port != ""
 }
 
+// dbPasswordLookupFromSecretStore reads the database password from the secret
+// named by the password secret environment variables. The operator sets them
+// when the password is kept in a secret store outside of k8s. This is a no-op
+// if we are not on k8s or the password was given through a flag.
+func dbPasswordLookupFromSecretStore(logger vlog.Printer, retriever secretRetriever, opt *vclusterops.DatabaseOptions) error {
+	if !isK8sEnvironment() || opt.Password != nil {
+		return nil
+	}
+	secret, err := lookupAndCheckSecretEnvVars(passwordSecretNameEnvVar, passwordSecretNamespaceEnvVar)
+	if secret == nil || err != nil {
+		return err
+	}
+	pwdData, err := retriever.RetrieveSecret(logger, secret.Namespace, secret.Name)
+	if err != nil {
+		return err
+	}
+	const passwordKey = "password"
+	pwd, ok := pwdData[passwordKey]
+	if !ok {
+		return fmt.Errorf("password not found, secret must have a key with name %q", passwordKey)
+	}
+	opt.Password = new(string)
+	*opt.Password = string(pwd)
+	logger.Info("Successfully read database password from secret store", "secretName", secret.Name)
+	return nil
+}
+
 // this function validates that the connection file path is absolute and ends with yaml or yml
 func validateYamlFilePath(connFile string, logger vlog.Printer) error {
 	if !filepath.IsAbs(connFile) {
@@ -270,3 +270,34 @@ func TestDBPassswdLookupFromK8sSecret(t *testing.T) {
 	err = c.dbPassswdLookupFromSecretStore(vlog.Printer{})
 	assert.NoError(t, err)
 }
+
+func TestDBPasswordLookupForWorkloadCommands(t *testing.T) {
+	const randomBytes = "123"
+	t.Setenv("KUBERNETES_PORT", randomBytes)
+	t.Setenv(passwordSecretNamespaceEnvVar, randomBytes)
+	t.Setenv(passwordSecretNameEnvVar, randomBytes)
+
+	opt := vclusterops.DatabaseOptions{}
+	err := dbPasswordLookupFromSecretStore(vlog.Printer{}, TestPasswordSecretRetriever{
+		success:     true,
+		password:    "passwd",
+		passwordKey: "password",
+	}, &opt)
+	assert.NoError(t, err)
+	assert.Equal(t, "passwd", *opt.Password)
+
+	// a password given through a flag takes precedence
+	flagPasswd := "flag-passwd"
+	opt = vclusterops.DatabaseOptions{Password: &flagPasswd}
+	err = dbPasswordLookupFromSecretStore(vlog.Printer{}, TestPasswordSecretRetriever{success: false}, &opt)
+	assert.NoError(t, err)
+	assert.Equal(t, flagPasswd, *opt.Password)
+
+	opt = vclusterops.DatabaseOptions{}
+	err = dbPasswordLookupFromSecretStore(vlog.Printer{}, TestPasswordSecretRetriever{
+		success:     true,
+		password:    "passwd",
+		passwordKey: "wrong-key",
+	}, &opt)
+	assert.Error(t, err)
+}
@@ -202,3 +202,29 @@ func TestStartNode(t *testing.T) {
 	err = simulateVClusterCli("vcluster start_node --start node1=host1 --start-hosts host1")
 	assert.ErrorContains(t, err, "[start start-hosts] were all set")
 }
+
+func TestWorkloadCommands(t *testing.T) {
+	err := simulateVClusterCli("vcluster capture_workload")
+	assert.ErrorContains(t, err, `required flag(s) "end-timestamp", "start-timestamp", "workload-file" not set`)
+
+	err = simulateVClusterCli("vcluster replay_workload --workload-file /tmp/workload.csv")
+	assert.ErrorContains(t, err, `required flag(s) "replay-results-file", "sandbox" not set`)
+
+	err = simulateVClusterCli("vcluster cancel_workload --sandbox sand1")
+	assert.ErrorContains(t, err, `required flag(s) "job-id" not set`)
+}
+
+func TestHealthWatchdogCommands(t *testing.T) {
+	// vcluster health_watchdog should succeed and show help message
+	err := simulateVClusterCli("vcluster health_watchdog")
+	assert.NoError(t, err)
+
+	err = simulateVClusterCli("vcluster health_watchdog set")
+	assert.ErrorContains(t, err, "at least one of the flags in the group [parameter policy] is required")
+
+	err = simulateVClusterCli("vcluster health_watchdog set --parameter p1 --policy k=v")
+	assert.ErrorContains(t, err, "[parameter policy] were all set")
+
+	err = simulateVClusterCli("vcluster health_watchdog cancel_query")
+	assert.ErrorContains(t, err, `required flag(s) "session" not set`)
+}
@@ -612,6 +612,8 @@ type ClusterCommands interface {
 	VScrutinize(options *VScrutinizeOptions) error
 	VShowRestorePoints(options *VShowRestorePointsOptions) (restorePoints []RestorePoint, err error)
 	VSaveRestorePoint(options *VSaveRestorePointOptions) (err error)
+	VRemoveRestorePoint(options *VRemoveRestorePointOptions) error
+	VDropArchive(options *VDropArchiveOptions) error
 	VStartDatabase(options *VStartDatabaseOptions) (vdbPtr *VCoordinationDatabase, err error)
 	VStartNodes(options *VStartNodesOptions) error
 	VStartSubcluster(startScOpt *VStartScOptions) (VCoordinationDatabase, error)
@@ -624,6 +626,9 @@ type ClusterCommands interface {
 	VWorkloadReplay(ctx context.Context, options *VWorkloadReplayOptions) error
 	VWorkloadCapture(options *VWorkloadCaptureOptions) error
 	VWorkloadCancel(options *VWorkloadCancelOptions) error
+	VHealthWatchdogGet(options *VHealthWatchdogGetOptions) (*[]HealthWatchdogHostValues, error)
+	VHealthWatchdogSet(options *VHealthWatchdogSetOptions) error
+	VHealthWatchdogCancelQuery(options *VHealthWatchdogCancelQueryOptions) ([]HealthWatchdogCancelQueryResponse, error)
 }
 
 type VClusterCommandsLogger struct {
@@ -54,6 +54,9 @@ const (
 	RotateNMACertsCmd
 	RotateVerticaCertsCmd
 	SetTLSConfigCmd
+	SetClientTLSAuthCmd
+	RemoveRestorePointCmd
+	DropArchiveCmd
 )
 
 var cmdStringMap = map[CmdType]string{
@@ -104,6 +107,9 @@ var cmdStringMap = map[CmdType]string{
 	RotateNMACertsCmd:            "rotate_nma_certs",
 	RotateVerticaCertsCmd:        "rotate_vertica_certs",
 	SetTLSConfigCmd:              "set_tls_config",
+	SetClientTLSAuthCmd:          "set_client_tls_auth",
+	RemoveRestorePointCmd:        "remove_restore_point",
+	DropArchiveCmd:               "drop_archive",
 }
 
 func (cmd CmdType) CmdString() string {
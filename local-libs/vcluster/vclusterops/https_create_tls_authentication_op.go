@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/vertica/vcluster/vclusterops/util"
 )
@@ -29,6 +30,10 @@ type httpsCreateTLSAuthOp struct {
 	authName  string
 	authHosts string
 	isLocal   bool
+	// priority of the method, only sent when non-zero
+	priority int
+	// skip the error returned when the method already exists
+	skipIfExists bool
 }
 
 func makeHTTPSCreateTLSAuthOp(hosts []string, useHTTPPassword bool, userName string, httpsPassword *string,
@@ -72,6 +77,9 @@ func (op *httpsCreateTLSAuthOp) setupClusterHTTPRequest(hosts []string) error {
 			"host":    op.authHosts,
 			"isLocal": strconv.FormatBool(op.isLocal),
 		}
+		if op.priority != 0 {
+			httpRequest.QueryParams["priority"] = strconv.Itoa(op.priority)
+		}
 		if op.useHTTPPassword {
 			httpRequest.Password = op.httpsPassword
 			httpRequest.Username = op.userName
@@ -104,6 +112,10 @@ func (op *httpsCreateTLSAuthOp) processResult(_ *opEngineExecContext) error {
 	for host, result := range op.clusterHTTPRequest.ResultCollection {
 		op.logResponse(host, result)
 		err := result.getError(host, op.name)
+		if err != nil && op.skipIfExists && strings.Contains(err.Error(), "already exists") {
+			op.logger.Info("authentication method already exists, skipping", "authName", op.authName)
+			return nil
+		}
 		if err != nil {
 			allErrs = errors.Join(allErrs, err)
 			continue
@@ -0,0 +1,119 @@
+/*
+ (c) Copyright [2023-2025] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+type httpsDropArchiveOp struct {
+	opBase
+	opHTTPSBase
+	ArchiveName string
+}
+
+// makeHTTPSDropArchiveOp will make an op that call vertica-http service to drop
+// an archive, and all of its restore points, from the database. It is the
+// DELETE counterpart of the endpoint that httpsCreateArchiveOp posts to.
+func makeHTTPSDropArchiveOp(hosts []string, useHTTPPassword bool, userName string,
+	httpsPassword *string, archiveName string,
+) (httpsDropArchiveOp, error) {
+	op := httpsDropArchiveOp{}
+	op.name = "HTTPSDropArchiveOp"
+	op.description = "Drop archive from database"
+	op.hosts = hosts
+	op.useHTTPPassword = useHTTPPassword
+	if useHTTPPassword {
+		err := util.ValidateUsernameAndPassword(op.name, useHTTPPassword, userName)
+		if err != nil {
+			return op, err
+		}
+		op.userName = userName
+		op.httpsPassword = httpsPassword
+	}
+	op.ArchiveName = archiveName
+	return op, nil
+}
+
+func (op *httpsDropArchiveOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = DeleteMethod
+		httpRequest.buildHTTPSEndpoint(util.ArchiveEndpoint + "/" + op.ArchiveName)
+		if op.useHTTPPassword {
+			httpRequest.Password = op.httpsPassword
+			httpRequest.Username = op.userName
+		}
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *httpsDropArchiveOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *httpsDropArchiveOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *httpsDropArchiveOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+
+	// should only send request to one host as dropping an archive is a cluster-wide op
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+
+		if result.isUnauthorizedRequest() {
+			return fmt.Errorf("[%s] wrong password/certificate for https service on host %s",
+				op.name, host)
+		}
+
+		if !result.isPassing() {
+			allErrs = errors.Join(allErrs, result.err)
+			// not break here because we want to log all the failed nodes
+			continue
+		}
+
+		/* decode the json-format response
+			The successful response object will be a dictionary like below:
+			{
+		  		"detail": ""
+			}
+
+		*/
+		_, err := op.parseAndCheckMapResponse(host, result.content)
+		if err != nil {
+			return fmt.Errorf(`[%s] fail to parse result on host %s, details: %w`, op.name, host, err)
+		}
+		return nil
+	}
+	return allErrs
+}
+
+func (op *httpsDropArchiveOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
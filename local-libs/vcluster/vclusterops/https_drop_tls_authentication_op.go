@@ -0,0 +1,117 @@
+/*
+ (c) Copyright [2023-2025] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+)
+
+type httpsDropTLSAuthOp struct {
+	opBase
+	opHTTPSBase
+	authName string
+	// skip the error returned when the method does not exist
+	skipIfMissing bool
+}
+
+func makeHTTPSDropTLSAuthOp(hosts []string, useHTTPPassword bool, userName string, httpsPassword *string,
+	authName string) (httpsDropTLSAuthOp, error) {
+	op := httpsDropTLSAuthOp{}
+	op.name = "HTTPSDropTLSAuthOp"
+	op.description = "Drop TLS Authentication method"
+	op.authName = authName
+	// this op is a cluster-wide op, should be sent to only one host
+	op.hosts = hosts
+	op.useHTTPPassword = useHTTPPassword
+	if useHTTPPassword {
+		err := util.ValidateUsernameAndPassword(op.name, useHTTPPassword, userName)
+		if err != nil {
+			return op, err
+		}
+		op.userName = userName
+		op.httpsPassword = httpsPassword
+	}
+	return op, nil
+}
+
+func (op *httpsDropTLSAuthOp) setupClusterHTTPRequest(hosts []string) error {
+	for _, host := range hosts {
+		httpRequest := hostHTTPRequest{}
+		httpRequest.Method = DeleteMethod
+		httpRequest.buildHTTPSEndpoint(util.TLSAuthEndpoint + op.authName)
+		if op.useHTTPPassword {
+			httpRequest.Password = op.httpsPassword
+			httpRequest.Username = op.userName
+		}
+		op.clusterHTTPRequest.RequestCollection[host] = httpRequest
+	}
+
+	return nil
+}
+
+func (op *httpsDropTLSAuthOp) prepare(execContext *opEngineExecContext) error {
+	execContext.dispatcher.setup(op.hosts)
+
+	return op.setupClusterHTTPRequest(op.hosts)
+}
+
+func (op *httpsDropTLSAuthOp) execute(execContext *opEngineExecContext) error {
+	if err := op.runExecute(execContext); err != nil {
+		return err
+	}
+
+	return op.processResult(execContext)
+}
+
+func (op *httpsDropTLSAuthOp) processResult(_ *opEngineExecContext) error {
+	var allErrs error
+
+	// should only send request to one host as dropping authentication method is a cluster-wide op
+	for host, result := range op.clusterHTTPRequest.ResultCollection {
+		op.logResponse(host, result)
+		err := result.getError(host, op.name)
+		if err != nil && op.skipIfMissing && strings.Contains(err.Error(), "does not exist") {
+			op.logger.Info("authentication method does not exist, skipping", "authName", op.authName)
+			return nil
+		}
+		if err != nil {
+			allErrs = errors.Join(allErrs, err)
+			continue
+		}
+
+		// Example successful response object:
+		/*
+			{
+			  "detail": ""
+			}
+		*/
+		_, err = op.parseAndCheckMapResponse(host, result.content)
+		if err != nil {
+			return fmt.Errorf(`[%s] fail to parse result on host %s, details: %w`, op.name, host, err)
+		}
+		return nil
+	}
+
+	return allErrs
+}
+
+func (op *httpsDropTLSAuthOp) finalize(_ *opEngineExecContext) error {
+	return nil
+}
@@ -18,6 +18,7 @@ package vclusterops
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/vertica/vcluster/vclusterops/util"
 )
@@ -27,6 +28,9 @@ type httpsGrantTLSAuthOp struct {
 	opHTTPSBase
 	authName string
 	grantee  string
+	revoke   bool
+	// skip the error returned when the method of a revoke does not exist
+	skipIfMissing bool
 }
 
 func makeHTTPSGrantTLSAuthOp(hosts []string, useHTTPPassword bool, userName string, httpsPassword *string,
@@ -50,11 +54,29 @@ func makeHTTPSGrantTLSAuthOp(hosts []string, useHTTPPassword bool, userName stri
 	return op, nil
 }
 
+// makeHTTPSRevokeTLSAuthOp will revoke a TLS Authentication method from a
+// user or role
+func makeHTTPSRevokeTLSAuthOp(hosts []string, useHTTPPassword bool, userName string, httpsPassword *string,
+	authName, grantee string) (httpsGrantTLSAuthOp, error) {
+	op, err := makeHTTPSGrantTLSAuthOp(hosts, useHTTPPassword, userName, httpsPassword, authName, grantee)
+	if err != nil {
+		return op, err
+	}
+	op.name = "HTTPSRevokeTLSAuthOp"
+	op.description = "Revoke TLS Authentication method from users"
+	op.revoke = true
+	return op, nil
+}
+
 func (op *httpsGrantTLSAuthOp) setupClusterHTTPRequest(hosts []string) error {
 	for _, host := range hosts {
 		httpRequest := hostHTTPRequest{}
 		httpRequest.Method = PostMethod
-		httpRequest.buildHTTPSEndpoint(util.TLSAuthEndpoint + op.authName + "/grant")
+		action := "/grant"
+		if op.revoke {
+			action = "/revoke"
+		}
+		httpRequest.buildHTTPSEndpoint(util.TLSAuthEndpoint + op.authName + action)
 		// the grantee usually is 'public'
 		httpRequest.QueryParams = map[string]string{"grantee": op.grantee}
 		if op.useHTTPPassword {
@@ -84,10 +106,15 @@ func (op *httpsGrantTLSAuthOp) execute(execContext *opEngineExecContext) error {
 func (op *httpsGrantTLSAuthOp) processResult(_ *opEngineExecContext) error {
 	var allErrs error
 
-	// should only send request to one host as grant authentication method is a cluster-wide op
+	// should only send request to one host as grant/revoke authentication method is a cluster-wide op
 	for host, result := range op.clusterHTTPRequest.ResultCollection {
 		op.logResponse(host, result)
 		err := result.getError(host, op.name)
+		if err != nil && op.skipIfMissing && strings.Contains(err.Error(), "does not exist") {
+			op.logger.Info("authentication method does not exist, skipping revoke", "authName", op.authName,
+				"grantee", op.grantee)
+			return nil
+		}
 		if err != nil {
 			allErrs = errors.Join(allErrs, err)
 			continue
@@ -0,0 +1,177 @@
+/*
+ (c) Copyright [2023-2025] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+
+	"github.com/vertica/vcluster/vclusterops/util"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+type VRemoveRestorePointOptions struct {
+	DatabaseOptions
+	ArchiveName string
+
+	// The (1-based) index of the restore point to remove. Index 1 is the
+	// most recent restore point in the archive. Removing a restore point
+	// shifts the indexes of the older ones down by one.
+	Index int
+
+	// the name of the sandbox to target, if left empty the main cluster is assumed
+	Sandbox string
+}
+
+func VRemoveRestorePointFactory() VRemoveRestorePointOptions {
+	options := VRemoveRestorePointOptions{}
+	// set default values to the params
+	options.setDefaultValues()
+	return options
+}
+
+func (options *VRemoveRestorePointOptions) validateEonOptions(_ vlog.Printer) error {
+	if !options.IsEon {
+		return fmt.Errorf("remove restore point is only supported in Eon mode")
+	}
+	return nil
+}
+
+func (options *VRemoveRestorePointOptions) validateRequiredOptions(logger vlog.Printer) error {
+	err := options.validateEonOptions(logger)
+	if err != nil {
+		return err
+	}
+	err = options.validateBaseOptions(RemoveRestorePointCmd, logger)
+	if err != nil {
+		return err
+	}
+	if options.ArchiveName == "" {
+		return fmt.Errorf("must specify an archive name")
+	}
+	err = util.ValidateArchiveName(options.ArchiveName)
+	if err != nil {
+		return err
+	}
+	if options.Index <= 0 {
+		return fmt.Errorf("restore point index must be greater than 0")
+	}
+	return nil
+}
+
+func (options *VRemoveRestorePointOptions) validateExtraOptions() error {
+	if options.Sandbox != "" {
+		return util.ValidateSandboxName(options.Sandbox)
+	}
+	return nil
+}
+
+func (options *VRemoveRestorePointOptions) validateParseOptions(logger vlog.Printer) error {
+	// batch 1: validate required parameters
+	err := options.validateRequiredOptions(logger)
+	if err != nil {
+		return err
+	}
+
+	// batch 2: validate all other params
+	return options.validateExtraOptions()
+}
+
+// analyzeOptions will modify some options based on what is chosen
+func (options *VRemoveRestorePointOptions) analyzeOptions() (err error) {
+	// we analyze host names when it is set in user input, otherwise we use hosts in yaml config
+	if len(options.RawHosts) > 0 {
+		// resolve RawHosts to be IP addresses
+		hostAddresses, err := util.ResolveRawHostsToAddresses(options.RawHosts, options.IPv6)
+		if err != nil {
+			return err
+		}
+		options.Hosts = hostAddresses
+	}
+	return nil
+}
+
+func (options *VRemoveRestorePointOptions) validateAnalyzeOptions(logger vlog.Printer) error {
+	if err := options.validateParseOptions(logger); err != nil {
+		return err
+	}
+	if err := options.validateUserName(logger); err != nil {
+		return err
+	}
+	if err := options.setUsePassword(logger); err != nil {
+		return err
+	}
+	return options.analyzeOptions()
+}
+
+// VRemoveRestorePoint removes a single restore point from an archive
+func (vcc VClusterCommands) VRemoveRestorePoint(options *VRemoveRestorePointOptions) (err error) {
+	/*
+	 *   - Produce Instructions
+	 *   - Create a VClusterOpEngine
+	 *   - Give the instructions to the VClusterOpEngine to run
+	 */
+
+	// validate and analyze options
+	err = options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return err
+	}
+
+	// produce remove restore point instructions
+	instructions, err := vcc.produceRemoveRestorePointInstructions(options)
+	if err != nil {
+		return fmt.Errorf("fail to produce instructions, %w", err)
+	}
+
+	// create a VClusterOpEngine, and add certs to the engine
+	clusterOpEngine := makeClusterOpEngine(instructions, options)
+
+	// give the instructions to the VClusterOpEngine to run
+	runError := clusterOpEngine.run(vcc.Log)
+	if runError != nil {
+		return fmt.Errorf("fail to remove restore point: %w", runError)
+	}
+	return nil
+}
+
+// The generated instructions will later perform the following operations necessary
+// for a successful remove_restore_point:
+//   - Retrieve VDB from HTTP endpoints
+//   - Run remove restore point on the target node
+func (vcc VClusterCommands) produceRemoveRestorePointInstructions(options *VRemoveRestorePointOptions) ([]clusterOp, error) {
+	var instructions []clusterOp
+	vdb := makeVCoordinationDatabase()
+
+	err := vcc.getVDBFromRunningDBIncludeSandbox(&vdb, &options.DatabaseOptions, util.MainClusterSandbox)
+	if err != nil {
+		return instructions, err
+	}
+
+	// get up hosts
+	hosts := options.Hosts
+	// Trim host list
+	hosts = vdb.filterUpHostListBySandbox(hosts, options.Sandbox)
+	bootstrapHost := []string{getInitiator(hosts)}
+
+	httpsRemoveRestorePointOp, err := makeHTTPSRemoveRestorePointOp(bootstrapHost, options.usePassword,
+		options.UserName, options.Password, options.ArchiveName, options.Index)
+	if err != nil {
+		return instructions, err
+	}
+	instructions = append(instructions,
+		&httpsRemoveRestorePointOp)
+	return instructions, nil
+}
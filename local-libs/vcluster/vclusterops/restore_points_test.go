@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
 )
 
 const defaultStartTime = " 00:00:00"
@@ -87,3 +88,82 @@ func TestShowRestorePointFilterOptions_ValidateAndStandardizeTimestampsIfAny(t *
 	err = filterOptions.ValidateAndStandardizeTimestampsIfAny()
 	assert.EqualError(t, err, "start timestamp must be before end timestamp")
 }
+
+func TestRemoveRestorePointOptions(t *testing.T) {
+	logger := vlog.Printer{}
+	options := VRemoveRestorePointFactory()
+	options.DBName = "test_db"
+	options.RawHosts = []string{"vnode1"}
+	options.IsEon = true
+	options.ArchiveName = "db"
+
+	// Negative - no index
+	err := options.validateRequiredOptions(logger)
+	assert.ErrorContains(t, err, "restore point index must be greater than 0")
+
+	// Positive - index set
+	options.Index = 1
+	err = options.validateRequiredOptions(logger)
+	assert.NoError(t, err)
+
+	// Negative - negative index
+	options.Index = -1
+	err = options.validateRequiredOptions(logger)
+	assert.ErrorContains(t, err, "restore point index must be greater than 0")
+
+	// Negative - missing archive name
+	options.Index = 1
+	options.ArchiveName = ""
+	err = options.validateRequiredOptions(logger)
+	assert.ErrorContains(t, err, "must specify an archive name")
+
+	// Negative - Enterprise database
+	options.ArchiveName = "db"
+	options.IsEon = false
+	err = options.validateRequiredOptions(logger)
+	assert.ErrorContains(t, err, "only supported in Eon mode")
+}
+
+func TestDropArchiveOptions(t *testing.T) {
+	logger := vlog.Printer{}
+	options := VDropArchiveFactory()
+	options.DBName = "test_db"
+	options.RawHosts = []string{"vnode1"}
+	options.IsEon = true
+
+	err := options.validateRequiredOptions(logger)
+	assert.ErrorContains(t, err, "must specify an archive name")
+
+	options.ArchiveName = "db"
+	err = options.validateRequiredOptions(logger)
+	assert.NoError(t, err)
+
+	options.Sandbox = "bad sandbox"
+	err = options.validateExtraOptions()
+	assert.Error(t, err)
+}
+
+func TestRestorePointHTTPSRequests(t *testing.T) {
+	hosts := []string{"vnode1"}
+	dropOp, err := makeHTTPSDropArchiveOp(hosts, false, "", nil, "db")
+	assert.NoError(t, err)
+	removeOp, err := makeHTTPSRemoveRestorePointOp(hosts, false, "", nil, "db", 3)
+	assert.NoError(t, err)
+
+	// run through prepare() phase only
+	dropOp.skipExecute = true
+	removeOp.skipExecute = true
+	instructions := []clusterOp{&dropOp, &removeOp}
+	var options DatabaseOptions
+	clusterOpEngine := makeClusterOpEngine(instructions, &options)
+	err = clusterOpEngine.run(vlog.Printer{})
+	assert.NoError(t, err)
+
+	httpRequest := dropOp.clusterHTTPRequest.RequestCollection[hosts[0]]
+	assert.Equal(t, "v1/archives/db", httpRequest.Endpoint)
+	assert.Equal(t, DeleteMethod, httpRequest.Method)
+
+	httpRequest = removeOp.clusterHTTPRequest.RequestCollection[hosts[0]]
+	assert.Equal(t, "v1/archives/db/restore-points/3", httpRequest.Endpoint)
+	assert.Equal(t, DeleteMethod, httpRequest.Method)
+}
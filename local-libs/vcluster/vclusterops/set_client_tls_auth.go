@@ -0,0 +1,242 @@
+/*
+ (c) Copyright [2023-2025] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+// ClientTLSAuthMethod is a TLS authentication method that lets clients log
+// in with a certificate
+type ClientTLSAuthMethod struct {
+	// Name of the authentication record
+	Name string
+	// The client address range, in CIDR notation, that the method applies to
+	Host string
+	// Priority of the method. Zero keeps the database default.
+	Priority int
+}
+
+// ClientTLSAuthGrant pairs a TLS authentication method with a user or role
+type ClientTLSAuthGrant struct {
+	Method  string
+	Grantee string
+}
+
+type VSetClientTLSAuthOptions struct {
+	DatabaseOptions
+	// The secret that the server TLS configuration is set from. Along with
+	// the server certificate and key, its CA certificates include the ones
+	// that sign client certificates. Leave the config map empty to keep the
+	// server TLS configuration as it is.
+	TrustedCAConfig TLSConfig
+	// Authentication methods to drop. They are dropped before any method is
+	// created, so a method can be recreated with new settings.
+	DropMethods []string
+	// Authentication methods to create
+	CreateMethods []ClientTLSAuthMethod
+	// Users or roles to revoke a method from
+	Revokes []ClientTLSAuthGrant
+	// Users or roles to grant a method to
+	Grants []ClientTLSAuthGrant
+}
+
+func VSetClientTLSAuthOptionsFactory() VSetClientTLSAuthOptions {
+	options := VSetClientTLSAuthOptions{}
+	options.setDefaultValues()
+	options.TrustedCAConfig = TLSConfig{
+		ConfigMap:  make(map[string]string),
+		ConfigType: ServerTLSKeyPrefix,
+	}
+
+	return options
+}
+
+// validateTrustedCAConfig makes sure the trusted CA configuration is a valid
+// server TLS configuration that names the key of the CA certificates
+func (options *VSetClientTLSAuthOptions) validateTrustedCAConfig(logger vlog.Printer) error {
+	cfg := &options.TrustedCAConfig
+	if !cfg.hasConfigParam() {
+		return nil
+	}
+	if cfg.ConfigMap[TLSSecretManagerKeyCACertDataKey] == "" {
+		return fmt.Errorf("the %s key must exist with a non-empty value", TLSSecretManagerKeyCACertDataKey)
+	}
+	return cfg.validate(logger)
+}
+
+// validateAuthChanges makes sure the authentication changes are complete
+func (options *VSetClientTLSAuthOptions) validateAuthChanges() error {
+	if !options.TrustedCAConfig.hasConfigParam() && len(options.DropMethods) == 0 &&
+		len(options.CreateMethods) == 0 && len(options.Revokes) == 0 && len(options.Grants) == 0 {
+		return fmt.Errorf("missing client TLS authentication changes: specify a trusted CA or at least one method change")
+	}
+	for _, name := range options.DropMethods {
+		if name == "" {
+			return fmt.Errorf("the name of an authentication method to drop cannot be empty")
+		}
+	}
+	for i := range options.CreateMethods {
+		m := &options.CreateMethods[i]
+		if m.Name == "" {
+			return fmt.Errorf("the name of an authentication method to create cannot be empty")
+		}
+		if _, _, err := net.ParseCIDR(m.Host); err != nil {
+			return fmt.Errorf("host %q of authentication method %s is not a valid CIDR: %w", m.Host, m.Name, err)
+		}
+	}
+	for _, g := range append(options.Revokes, options.Grants...) {
+		if g.Method == "" || g.Grantee == "" {
+			return fmt.Errorf("an authentication method grant needs both a method and a grantee")
+		}
+	}
+	return nil
+}
+
+func (options *VSetClientTLSAuthOptions) analyzeOptions() (err error) {
+	return options.resolveToIPAndNormalizePaths()
+}
+
+func (options *VSetClientTLSAuthOptions) validateParseOptions(logger vlog.Printer) error {
+	// validate base options
+	err := options.validateBaseOptions(SetClientTLSAuthCmd, logger)
+	if err != nil {
+		return err
+	}
+
+	if err := options.validateTrustedCAConfig(logger); err != nil {
+		return err
+	}
+
+	return options.validateAuthChanges()
+}
+
+func (options *VSetClientTLSAuthOptions) validateAnalyzeOptions(log vlog.Printer) error {
+	if err := options.validateParseOptions(log); err != nil {
+		return err
+	}
+
+	if err := options.analyzeOptions(); err != nil {
+		return err
+	}
+
+	if err := options.setUsePassword(log); err != nil {
+		return err
+	}
+
+	return options.validateUserName(log)
+}
+
+// VSetClientTLSAuth sets up certificate authentication for database users. It
+// sets the CA certificates trusted to sign client certificates, and creates,
+// drops, grants and revokes TLS authentication methods.
+func (vcc VClusterCommands) VSetClientTLSAuth(options *VSetClientTLSAuthOptions) error {
+	// validate and analyze all options
+	err := options.validateAnalyzeOptions(vcc.Log)
+	if err != nil {
+		return err
+	}
+
+	instructions, err := vcc.produceSetClientTLSAuthInstructions(options)
+	if err != nil {
+		return err
+	}
+
+	clusterOpEngine := makeClusterOpEngine(instructions, options)
+
+	// Give the instructions to the VClusterOpEngine to run
+	runError := clusterOpEngine.run(vcc.Log)
+	if runError != nil {
+		return fmt.Errorf("fail to set client tls authentication: %w", runError)
+	}
+
+	return nil
+}
+
+// The generated instructions will later perform the following operations:
+//   - Check NMA connectivity
+//   - Set the server TLS config with the trusted CA certificates
+//   - Revoke, then drop, the authentication methods that go away
+//   - Create the new authentication methods, then grant them
+//
+// A method that is already dropped or created, or a grant that is already
+// revoked, is skipped. This lets a change that failed partway through be
+// applied again.
+func (vcc VClusterCommands) produceSetClientTLSAuthInstructions(options *VSetClientTLSAuthOptions) ([]clusterOp, error) {
+	var instructions []clusterOp
+	initiator := []string{getInitiator(options.Hosts)}
+	nmaHealthOp := makeNMAHealthOp(initiator)
+	instructions = append(instructions, &nmaHealthOp)
+
+	if options.TrustedCAConfig.hasConfigParam() {
+		nmaSetServerTLSOp, err := makeNMASetTLSOp(&options.DatabaseOptions,
+			string(options.TrustedCAConfig.ConfigType),
+			false, // grantAuth
+			true,  // syncCatalog
+			options.TrustedCAConfig.CacheDuration,
+			options.TrustedCAConfig.ConfigMap)
+		if err != nil {
+			return instructions, err
+		}
+		instructions = append(instructions, &nmaSetServerTLSOp)
+	}
+
+	for _, g := range options.Revokes {
+		revokeOp, err := makeHTTPSRevokeTLSAuthOp(initiator, options.usePassword, options.UserName,
+			options.Password, g.Method, g.Grantee)
+		if err != nil {
+			return instructions, err
+		}
+		revokeOp.skipIfMissing = true
+		instructions = append(instructions, &revokeOp)
+	}
+
+	for _, name := range options.DropMethods {
+		dropOp, err := makeHTTPSDropTLSAuthOp(initiator, options.usePassword, options.UserName,
+			options.Password, name)
+		if err != nil {
+			return instructions, err
+		}
+		dropOp.skipIfMissing = true
+		instructions = append(instructions, &dropOp)
+	}
+
+	for i := range options.CreateMethods {
+		m := &options.CreateMethods[i]
+		createOp, err := makeHTTPSCreateTLSAuthOp(initiator, options.usePassword, options.UserName,
+			options.Password, m.Name, m.Host)
+		if err != nil {
+			return instructions, err
+		}
+		createOp.priority = m.Priority
+		createOp.skipIfExists = true
+		instructions = append(instructions, &createOp)
+	}
+
+	for _, g := range options.Grants {
+		grantOp, err := makeHTTPSGrantTLSAuthOp(initiator, options.usePassword, options.UserName,
+			options.Password, g.Method, g.Grantee)
+		if err != nil {
+			return instructions, err
+		}
+		instructions = append(instructions, &grantOp)
+	}
+
+	return instructions, nil
+}
@@ -0,0 +1,74 @@
+/*
+ (c) Copyright [2023-2025] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vclusterops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vertica/vcluster/vclusterops/vlog"
+)
+
+func TestVSetClientTLSAuth_validateParseOptions(t *testing.T) {
+	logger := vlog.Printer{}
+
+	opt := VSetClientTLSAuthOptionsFactory()
+	opt.RawHosts = append(opt.RawHosts, "set-client-tls-auth-test-raw-host")
+	opt.DBName = "set_client_tls_auth_test_dbname"
+
+	// negative: nothing to change
+	err := opt.validateParseOptions(logger)
+	assert.Error(t, err)
+
+	// negative: trusted CA without the CA key
+	opt.TrustedCAConfig.SetConfigMap(map[string]string{
+		"SecretManager": "kubernetes",
+		"SecretName":    "server-client-ca",
+		"Namespace":     "default",
+		"TLSMode":       "try_verify",
+	})
+	err = opt.validateParseOptions(logger)
+	assert.Error(t, err)
+
+	// negative: trusted CA without the server certificate
+	opt.TrustedCAConfig.ConfigMap["CADataKey"] = "ca.crt"
+	err = opt.validateParseOptions(logger)
+	assert.Error(t, err)
+
+	// positive: trusted CA only
+	opt.TrustedCAConfig.ConfigMap["CertDataKey"] = "tls.crt"
+	opt.TrustedCAConfig.ConfigMap["KeyDataKey"] = "tls.key"
+	err = opt.validateParseOptions(logger)
+	assert.NoError(t, err)
+
+	// negative: host is not a CIDR
+	opt.CreateMethods = []ClientTLSAuthMethod{{Name: "svc_tls", Host: "10.0.0.1"}}
+	err = opt.validateParseOptions(logger)
+	assert.Error(t, err)
+
+	// negative: grant without a grantee
+	opt.CreateMethods[0].Host = "10.0.0.0/8"
+	opt.Grants = []ClientTLSAuthGrant{{Method: "svc_tls"}}
+	err = opt.validateParseOptions(logger)
+	assert.Error(t, err)
+
+	// positive
+	opt.Grants[0].Grantee = "etl"
+	opt.DropMethods = []string{"old_tls"}
+	opt.Revokes = []ClientTLSAuthGrant{{Method: "old_tls", Grantee: "etl"}}
+	err = opt.validateParseOptions(logger)
+	assert.NoError(t, err)
+}
@@ -68,6 +68,7 @@ const (
 	NodesEndpoint         = "nodes/"
 	DropEndpoint          = "/drop"
 	ArchiveEndpoint       = "archives"
+	RestorePointsEndpoint = "/restore-points/"
 	LicenseEndpoint       = "license"
 	TLSAuthEndpoint       = "authentication/tls/"
 	TLSBootstrapEndpoint  = "authentication/client"
@@ -0,0 +1,395 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/vertica/vertica-kubernetes/pkg/cmds"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// stagePrefix is the prefix of the directory that the hard link snapshot of a
+// pod is staged in. It is created next to the directory being backed up so
+// that it is on the same filesystem.
+const stagePrefix = ".vbackup-"
+
+// catalogDirPattern matches the catalog directory of a node, such as
+// v_vertdb_node0001_catalog. Vertica appends to some of the files in it, like
+// the transaction logs and vertica.log, so they are copied when staged.
+const catalogDirPattern = "*_catalog"
+
+// Pod is a pod whose directories get backed up or restored
+type Pod struct {
+	Name          types.NamespacedName
+	VNodeName     string
+	ContainerName string
+}
+
+// NodeStats has the progress of the backup or restore of one pod
+type NodeStats struct {
+	Pod              Pod
+	TotalFiles       int
+	TransferredFiles int
+	TransferredBytes int64
+}
+
+// fileInfo is a file found in a pod along with its hash and size
+type fileInfo struct {
+	Path   string
+	SHA256 string
+	Size   int64
+}
+
+// Backuper takes an incremental snapshot of the catalog and data directories
+// of the pods and stores it in the object store.
+//
+// The pods are not quiesced. A snapshot is taken by staging the directories
+// in every pod before any data is copied out, which gives a crash consistent
+// copy: restoring it is like restarting after every node lost power at about
+// the same time. Vertica's data files are never modified in place, so they are
+// staged as hard links that keep the content they had when the snapshot was
+// taken. The catalog is modified in place, so its files are staged as copies.
+type Backuper struct {
+	Log      logr.Logger
+	PRunner  cmds.PodRunner
+	Store    Store
+	Snapshot string
+	VDBName  string
+	DBName   string
+	// Dirs are the directories in each pod that are backed up
+	Dirs []string
+}
+
+// Stage creates the snapshot of the directories in the pod. Everything is hard
+// linked, then the links to catalog files are replaced with copies so that
+// later appends to the catalog don't change the snapshot. This should be
+// called for all of the pods before Upload is called for any of them, so that
+// the snapshots are taken as close together as possible.
+func (b *Backuper) Stage(ctx context.Context, pod *Pod) error {
+	for _, dir := range uniqueDirs(b.Dirs) {
+		cmd := []string{
+			"sh", "-c", `rm -rf "$2" && mkdir -p "$(dirname "$2")" && cp -al "$1" "$2" && ` +
+				`find "$2" -type f -path "*/$3/*" -exec sh -c ` +
+				`'for f; do cp -p "$f" "$f.vbackup-copy" && mv -f "$f.vbackup-copy" "$f" || exit 1; done' sh {} +`,
+			"sh", dir, stageDir(dir, b.Snapshot), catalogDirPattern,
+		}
+		if _, stderr, err := b.PRunner.ExecInPod(ctx, pod.Name, pod.ContainerName, cmd...); err != nil {
+			return fmt.Errorf("failed to snapshot directory %s in pod %s: %s: %w", dir, pod.Name.Name, stderr, err)
+		}
+	}
+	return nil
+}
+
+// Upload copies the files in the staged snapshot that aren't already in the
+// object store and then writes the manifest for the pod. The staged snapshot
+// is removed afterwards.
+func (b *Backuper) Upload(ctx context.Context, pod *Pod) (*NodeStats, error) {
+	stats := &NodeStats{Pod: *pod}
+	manifest := Manifest{
+		Snapshot:  b.Snapshot,
+		VDBName:   b.VDBName,
+		DBName:    b.DBName,
+		PodName:   pod.Name.Name,
+		VNodeName: pod.VNodeName,
+		CreatedAt: time.Now().UTC(),
+	}
+	for _, dir := range uniqueDirs(b.Dirs) {
+		stage := stageDir(dir, b.Snapshot)
+		files, err := listFiles(ctx, b.PRunner, pod, stage)
+		if err != nil {
+			return stats, err
+		}
+		for i := range files {
+			stats.TotalFiles++
+			manifest.Files = append(manifest.Files, ManifestFile{
+				Dir:    dir,
+				Path:   files[i].Path,
+				SHA256: files[i].SHA256,
+				Size:   files[i].Size,
+			})
+			key := ObjectKey(files[i].SHA256)
+			exists, err := b.Store.Exists(ctx, key)
+			if err != nil {
+				return stats, err
+			}
+			if exists {
+				continue
+			}
+			if err := b.uploadFile(ctx, pod, filepath.Join(stage, files[i].Path), files[i].SHA256); err != nil {
+				return stats, err
+			}
+			stats.TransferredFiles++
+			stats.TransferredBytes += files[i].Size
+		}
+	}
+	content, err := json.Marshal(&manifest)
+	if err != nil {
+		return stats, fmt.Errorf("failed to marshal the manifest: %w", err)
+	}
+	if err := b.Store.Put(ctx, ManifestKey(b.Snapshot, pod.Name.Name), bytes.NewReader(content)); err != nil {
+		return stats, err
+	}
+	b.Log.Info("Uploaded snapshot of pod", "pod", pod.Name, "totalFiles", stats.TotalFiles,
+		"transferredFiles", stats.TransferredFiles, "transferredBytes", stats.TransferredBytes)
+	return stats, b.Cleanup(ctx, pod)
+}
+
+// Cleanup removes the staged snapshot from the pod
+func (b *Backuper) Cleanup(ctx context.Context, pod *Pod) error {
+	for _, dir := range uniqueDirs(b.Dirs) {
+		stage := filepath.Dir(stageDir(dir, b.Snapshot))
+		if _, stderr, err := b.PRunner.ExecInPod(ctx, pod.Name, pod.ContainerName, "rm", "-rf", stage); err != nil {
+			return fmt.Errorf("failed to remove the staged snapshot %s in pod %s: %s: %w", stage, pod.Name.Name, stderr, err)
+		}
+	}
+	return nil
+}
+
+// uploadFile streams the content of a file in the pod to the object store. The
+// object is keyed by the hash the file had when it was listed. The streamed
+// bytes are hashed as well, and if they don't match, the object is removed so
+// that later backups don't reuse it.
+func (b *Backuper) uploadFile(ctx context.Context, pod *Pod, file, sha string) error {
+	key := ObjectKey(sha)
+	pr, pw := io.Pipe()
+	errCh := make(chan error, 1)
+	go func() {
+		stderr, err := b.PRunner.StreamFromPod(ctx, pod.Name, pod.ContainerName, pw, "cat", "--", file)
+		if err != nil {
+			err = fmt.Errorf("failed to read %s in pod %s: %s: %w", file, pod.Name.Name, stderr, err)
+		}
+		pw.CloseWithError(err)
+		errCh <- err
+	}()
+	hasher := sha256.New()
+	putErr := b.Store.Put(ctx, key, io.TeeReader(pr, hasher))
+	// Unblock the reader in the pod if the upload stopped early
+	pr.CloseWithError(putErr)
+	if err := <-errCh; err != nil {
+		return err
+	}
+	if putErr != nil {
+		return putErr
+	}
+	if uploaded := hex.EncodeToString(hasher.Sum(nil)); uploaded != sha {
+		if err := b.Store.Delete(ctx, key); err != nil {
+			b.Log.Info("failed to remove an object with unexpected content", "key", key, "err", err)
+		}
+		return fmt.Errorf("the content of %s in pod %s changed while it was backed up: expected sha256 %s, uploaded %s",
+			file, pod.Name.Name, sha, uploaded)
+	}
+	return nil
+}
+
+// Restorer copies a snapshot from the object store back into the pods. Only
+// the files that differ from what is in the pod are transferred, and files
+// that aren't part of the snapshot are removed.
+type Restorer struct {
+	Log      logr.Logger
+	PRunner  cmds.PodRunner
+	Store    Store
+	Snapshot string
+}
+
+// Restore brings the directories in the pod back to the state they had in
+// the snapshot. The database must not be running in the pod.
+func (r *Restorer) Restore(ctx context.Context, pod *Pod) (*NodeStats, error) {
+	stats := &NodeStats{Pod: *pod}
+	manifest, err := r.readManifest(ctx, pod)
+	if err != nil {
+		return stats, err
+	}
+	filesByDir := map[string][]ManifestFile{}
+	for i := range manifest.Files {
+		filesByDir[manifest.Files[i].Dir] = append(filesByDir[manifest.Files[i].Dir], manifest.Files[i])
+	}
+	dirs := make([]string, 0, len(filesByDir))
+	for dir := range filesByDir {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		if err := r.restoreDir(ctx, pod, dir, filesByDir[dir], stats); err != nil {
+			return stats, err
+		}
+	}
+	r.Log.Info("Restored snapshot in pod", "pod", pod.Name, "totalFiles", stats.TotalFiles,
+		"transferredFiles", stats.TransferredFiles, "transferredBytes", stats.TransferredBytes)
+	return stats, nil
+}
+
+// restoreDir restores a single directory of the pod
+func (r *Restorer) restoreDir(ctx context.Context, pod *Pod, dir string, files []ManifestFile, stats *NodeStats) error {
+	current, err := listFiles(ctx, r.PRunner, pod, dir)
+	if err != nil {
+		return err
+	}
+	currentHashes := make(map[string]string, len(current))
+	for i := range current {
+		currentHashes[current[i].Path] = current[i].SHA256
+	}
+	wanted := make(map[string]bool, len(files))
+	for i := range files {
+		stats.TotalFiles++
+		wanted[files[i].Path] = true
+		if currentHashes[files[i].Path] == files[i].SHA256 {
+			continue
+		}
+		if err := r.downloadFile(ctx, pod, filepath.Join(dir, files[i].Path), ObjectKey(files[i].SHA256)); err != nil {
+			return err
+		}
+		stats.TransferredFiles++
+		stats.TransferredBytes += files[i].Size
+	}
+	var extra []string
+	for i := range current {
+		if !wanted[current[i].Path] {
+			extra = append(extra, filepath.Join(dir, current[i].Path))
+		}
+	}
+	return removeFiles(ctx, r.PRunner, pod, extra)
+}
+
+// readManifest fetches the manifest of the pod from the object store
+func (r *Restorer) readManifest(ctx context.Context, pod *Pod) (*Manifest, error) {
+	rdr, err := r.Store.Get(ctx, ManifestKey(r.Snapshot, pod.Name.Name))
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Close()
+	manifest := &Manifest{}
+	if err := json.NewDecoder(rdr).Decode(manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse the manifest of pod %s: %w", pod.Name.Name, err)
+	}
+	return manifest, nil
+}
+
+// downloadFile streams an object from the object store into a file in the pod
+func (r *Restorer) downloadFile(ctx context.Context, pod *Pod, file, key string) error {
+	rdr, err := r.Store.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer rdr.Close()
+	cmd := []string{"sh", "-c", `mkdir -p "$(dirname "$1")" && cat > "$1"`, "sh", file}
+	if _, stderr, err := r.PRunner.StreamToPod(ctx, pod.Name, pod.ContainerName, rdr, cmd...); err != nil {
+		return fmt.Errorf("failed to write %s in pod %s: %s: %w", file, pod.Name.Name, stderr, err)
+	}
+	return nil
+}
+
+// removeFilesBatchSize is the most files that are removed with one command
+const removeFilesBatchSize = 100
+
+// removeFiles deletes the given files in the pod
+func removeFiles(ctx context.Context, prunner cmds.PodRunner, pod *Pod, files []string) error {
+	for start := 0; start < len(files); start += removeFilesBatchSize {
+		end := min(start+removeFilesBatchSize, len(files))
+		cmd := append([]string{"rm", "-f", "--"}, files[start:end]...)
+		if _, stderr, err := prunner.ExecInPod(ctx, pod.Name, pod.ContainerName, cmd...); err != nil {
+			return fmt.Errorf("failed to remove files in pod %s: %s: %w", pod.Name.Name, stderr, err)
+		}
+	}
+	return nil
+}
+
+// listFiles returns all of the files under dir in the pod along with their
+// hash and size. The paths are relative to dir. A missing dir has no files.
+func listFiles(ctx context.Context, prunner cmds.PodRunner, pod *Pod, dir string) ([]fileInfo, error) {
+	cmd := []string{
+		"sh", "-c", `[ -d "$1" ] || exit 0; cd "$1" && find . -type f -exec stat -c '%s %n' {} + && ` +
+			`echo --- && find . -type f -exec sha256sum {} +`,
+		"sh", dir,
+	}
+	stdout, stderr, err := prunner.ExecInPod(ctx, pod.Name, pod.ContainerName, cmd...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the files in %s in pod %s: %s: %w", dir, pod.Name.Name, stderr, err)
+	}
+	return parseFileList(stdout)
+}
+
+// parseFileList parses the output of the command run by listFiles. It has
+// lines of "<size> <path>", then a "---" line, then lines of "<sha256>  <path>".
+func parseFileList(stdout string) ([]fileInfo, error) {
+	sizeOut, hashOut, _ := strings.Cut(stdout, "---\n")
+	sizes := map[string]int64{}
+	for _, line := range strings.Split(sizeOut, "\n") {
+		if line == "" {
+			continue
+		}
+		sizeStr, p, ok := strings.Cut(line, " ")
+		if !ok {
+			return nil, fmt.Errorf("unexpected size line %q", line)
+		}
+		size, err := strconv.ParseInt(sizeStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the size in line %q: %w", line, err)
+		}
+		sizes[cleanPath(p)] = size
+	}
+	files := []fileInfo{}
+	for _, line := range strings.Split(hashOut, "\n") {
+		if line == "" {
+			continue
+		}
+		sha, p, ok := strings.Cut(line, "  ")
+		if !ok {
+			return nil, fmt.Errorf("unexpected checksum line %q", line)
+		}
+		p = cleanPath(p)
+		files = append(files, fileInfo{Path: p, SHA256: sha, Size: sizes[p]})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// cleanPath strips the leading ./ that find puts on each path
+func cleanPath(p string) string {
+	return strings.TrimPrefix(p, "./")
+}
+
+// stageDir returns the directory that the snapshot of dir is staged in
+func stageDir(dir, snapshot string) string {
+	dir = filepath.Clean(dir)
+	return filepath.Join(filepath.Dir(dir), stagePrefix+snapshot, filepath.Base(dir))
+}
+
+// uniqueDirs returns dirs without duplicates, keeping the order
+func uniqueDirs(dirs []string) []string {
+	seen := map[string]bool{}
+	res := []string{}
+	for _, d := range dirs {
+		d = filepath.Clean(d)
+		if !seen[d] {
+			seen[d] = true
+			res = append(res, d)
+		}
+	}
+	return res
+}
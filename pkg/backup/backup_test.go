@@ -0,0 +1,158 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/vertica/vertica-kubernetes/pkg/cmds"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	testDir      = "/data/vertdb"
+	testSnapshot = "snap1"
+)
+
+var _ = Describe("backup", func() {
+	ctx := context.Background()
+	pod := &Pod{
+		Name:          types.NamespacedName{Namespace: "default", Name: "vertdb-sc1-0"},
+		VNodeName:     "v_vertdb_node0001",
+		ContainerName: "server",
+	}
+
+	// makeFileList builds the output of the command that listFiles runs
+	makeFileList := func(files map[string]string) string {
+		sizes, hashes := "", ""
+		for p, content := range files {
+			sizes += fmt.Sprintf("%d ./%s\n", len(content), p)
+			hashes += fmt.Sprintf("%s  ./%s\n", HashContent([]byte(content)), p)
+		}
+		return sizes + "---\n" + hashes
+	}
+
+	It("should parse the file list", func() {
+		files, err := parseFileList("5 ./b/file 2\n3 ./a\n---\nhash2  ./b/file 2\nhash1  ./a\n")
+		Expect(err).Should(Succeed())
+		Expect(files).Should(Equal([]fileInfo{
+			{Path: "a", SHA256: "hash1", Size: 3},
+			{Path: "b/file 2", SHA256: "hash2", Size: 5},
+		}))
+		files, err = parseFileList("")
+		Expect(err).Should(Succeed())
+		Expect(files).Should(BeEmpty())
+		_, err = parseFileList("abc ./a\n---\n")
+		Expect(err).ShouldNot(Succeed())
+	})
+
+	It("should stage next to the backed up directory", func() {
+		Expect(stageDir("/data/vertdb/", "s1")).Should(Equal("/data/.vbackup-s1/vertdb"))
+		Expect(uniqueDirs([]string{"/data/vertdb", "/data/vertdb/", "/catalog/vertdb"})).Should(
+			Equal([]string{"/data/vertdb", "/catalog/vertdb"}))
+	})
+
+	It("should only upload files that aren't already stored", func() {
+		store := MakeMemStore()
+		Expect(store.Put(ctx, ObjectKey(HashContent([]byte("old"))), strings.NewReader("old"))).Should(Succeed())
+		fpr := &cmds.FakePodRunner{Results: cmds.CmdResults{
+			pod.Name: []cmds.CmdResult{
+				{}, // stage
+				{Stdout: makeFileList(map[string]string{"v1.ros": "old", "v2.ros": "new"})},
+				{Stdout: "new"}, // cat of v2.ros
+				{},              // cleanup
+			},
+		}}
+		b := Backuper{Log: logger, PRunner: fpr, Store: store, Snapshot: testSnapshot,
+			VDBName: "v", DBName: "vertdb", Dirs: []string{testDir, testDir}}
+		Expect(b.Stage(ctx, pod)).Should(Succeed())
+		stats, err := b.Upload(ctx, pod)
+		Expect(err).Should(Succeed())
+		Expect(stats.TotalFiles).Should(Equal(2))
+		Expect(stats.TransferredFiles).Should(Equal(1))
+		Expect(stats.TransferredBytes).Should(Equal(int64(3)))
+		Expect(string(store.Objects[ObjectKey(HashContent([]byte("new")))])).Should(Equal("new"))
+		Expect(fpr.FindCommands("cp -al")).Should(HaveLen(1))
+		Expect(fpr.FindCommands("cat", "--", "/data/.vbackup-snap1/vertdb/v2.ros")).Should(HaveLen(1))
+		Expect(fpr.FindCommands("rm", "-rf", "/data/.vbackup-snap1")).Should(HaveLen(1))
+
+		manifest := Manifest{}
+		Expect(json.Unmarshal(store.Objects[ManifestKey(testSnapshot, pod.Name.Name)], &manifest)).Should(Succeed())
+		Expect(manifest.VNodeName).Should(Equal(pod.VNodeName))
+		Expect(manifest.Files).Should(HaveLen(2))
+		Expect(manifest.Files[0].Dir).Should(Equal(testDir))
+	})
+
+	It("should stage catalog files as copies and reject content that changed during upload", func() {
+		store := MakeMemStore()
+		fpr := &cmds.FakePodRunner{Results: cmds.CmdResults{
+			pod.Name: []cmds.CmdResult{
+				{}, // stage
+				{Stdout: makeFileList(map[string]string{"v_vertdb_node0001_catalog/Txnlogs/txn.log": "v1"})},
+				{Stdout: "v1v2"}, // cat returns content that was appended to
+			},
+		}}
+		b := Backuper{Log: logger, PRunner: fpr, Store: store, Snapshot: testSnapshot,
+			VDBName: "v", DBName: "vertdb", Dirs: []string{testDir}}
+		Expect(b.Stage(ctx, pod)).Should(Succeed())
+		h := fpr.FindCommands("cp -al")
+		Expect(h).Should(HaveLen(1))
+		Expect(h[0].Command).Should(ContainElement(catalogDirPattern))
+		_, err := b.Upload(ctx, pod)
+		Expect(err).ShouldNot(Succeed())
+		Expect(err.Error()).Should(ContainSubstring("changed while it was backed up"))
+		Expect(store.Objects).ShouldNot(HaveKey(ObjectKey(HashContent([]byte("v1")))))
+		Expect(store.Objects).ShouldNot(HaveKey(ManifestKey(testSnapshot, pod.Name.Name)))
+	})
+
+	It("should restore changed files and remove extra ones", func() {
+		store := MakeMemStore()
+		manifest := Manifest{Snapshot: testSnapshot, PodName: pod.Name.Name, Files: []ManifestFile{
+			{Dir: testDir, Path: "same", SHA256: HashContent([]byte("same")), Size: 4},
+			{Dir: testDir, Path: "changed", SHA256: HashContent([]byte("v1")), Size: 2},
+		}}
+		content, err := json.Marshal(&manifest)
+		Expect(err).Should(Succeed())
+		Expect(store.Put(ctx, ManifestKey(testSnapshot, pod.Name.Name), strings.NewReader(string(content)))).Should(Succeed())
+		Expect(store.Put(ctx, ObjectKey(HashContent([]byte("v1"))), strings.NewReader("v1"))).Should(Succeed())
+		fpr := &cmds.FakePodRunner{Results: cmds.CmdResults{
+			pod.Name: []cmds.CmdResult{
+				{Stdout: makeFileList(map[string]string{"same": "same", "changed": "v2", "extra": "x"})},
+			},
+		}}
+		r := Restorer{Log: logger, PRunner: fpr, Store: store, Snapshot: testSnapshot}
+		stats, err := r.Restore(ctx, pod)
+		Expect(err).Should(Succeed())
+		Expect(stats.TotalFiles).Should(Equal(2))
+		Expect(stats.TransferredFiles).Should(Equal(1))
+		h := fpr.FindCommands("cat >")
+		Expect(h).Should(HaveLen(1))
+		Expect(h[0].Command).Should(ContainElement("/data/vertdb/changed"))
+		Expect(h[0].Stdin).Should(Equal("v1"))
+		Expect(fpr.FindCommands("rm", "-f", "--", "/data/vertdb/extra")).Should(HaveLen(1))
+	})
+
+	It("should fail the restore if the manifest is missing", func() {
+		r := Restorer{Log: logger, PRunner: &cmds.FakePodRunner{}, Store: MakeMemStore(), Snapshot: testSnapshot}
+		_, err := r.Restore(ctx, pod)
+		Expect(err).ShouldNot(Succeed())
+	})
+})
@@ -0,0 +1,77 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// MemStore is a Store that keeps everything in memory. It is meant for tests.
+type MemStore struct {
+	mu      sync.Mutex
+	Objects map[string][]byte
+	// PutCount is the number of times Put was called
+	PutCount int
+}
+
+// MakeMemStore returns an empty MemStore
+func MakeMemStore() *MemStore {
+	return &MemStore{Objects: map[string][]byte{}}
+}
+
+// Exists returns true if the key was stored
+func (m *MemStore) Exists(_ context.Context, key string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.Objects[key]
+	return ok, nil
+}
+
+// Put saves the content of the reader at the key
+func (m *MemStore) Put(_ context.Context, key string, body io.Reader) error {
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Objects[key] = content
+	m.PutCount++
+	return nil
+}
+
+// Get returns the content stored at the key
+func (m *MemStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	content, ok := m.Objects[key]
+	if !ok {
+		return nil, fmt.Errorf("object %s not found", key)
+	}
+	return io.NopCloser(bytes.NewReader(content)), nil
+}
+
+// Delete removes the content stored at the key
+func (m *MemStore) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.Objects, key)
+	return nil
+}
@@ -0,0 +1,72 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package backup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"time"
+)
+
+const (
+	objectsDir   = "objects"
+	snapshotsDir = "snapshots"
+)
+
+// Manifest describes the files that make up the snapshot of one pod. The file
+// content is stored separately, keyed by its hash, so that files that didn't
+// change since an earlier snapshot are only stored once.
+type Manifest struct {
+	Snapshot  string         `json:"snapshot"`
+	VDBName   string         `json:"vdbName"`
+	DBName    string         `json:"dbName"`
+	PodName   string         `json:"podName"`
+	VNodeName string         `json:"vnodeName"`
+	CreatedAt time.Time      `json:"createdAt"`
+	Files     []ManifestFile `json:"files"`
+}
+
+// ManifestFile is a single file in the snapshot
+type ManifestFile struct {
+	// Dir is the directory in the pod that the file was taken from
+	Dir string `json:"dir"`
+	// Path is the location of the file relative to Dir
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// ObjectKey returns the key the content of a file is stored at
+func ObjectKey(sha string) string {
+	if len(sha) < 2 {
+		return path.Join(objectsDir, sha)
+	}
+	return path.Join(objectsDir, sha[:2], sha)
+}
+
+// ManifestKey returns the key the manifest of a pod is stored at
+func ManifestKey(snapshot, podName string) string {
+	return path.Join(snapshotsDir, snapshot, fmt.Sprintf("%s.json", podName))
+}
+
+// HashContent returns the sha256 of the content in the same format as the
+// sha256sum utility.
+func HashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
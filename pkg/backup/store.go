@@ -0,0 +1,168 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+const s3Prefix = "s3://"
+
+// Store is the object store that snapshots are written to. Keys are relative
+// to the location of the backup.
+type Store interface {
+	// Exists returns true if an object with the given key exists
+	Exists(ctx context.Context, key string) (bool, error)
+	// Put writes the object, reading its content from the reader
+	Put(ctx context.Context, key string, body io.Reader) error
+	// Get returns a reader for the content of the object. The caller must
+	// close it.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object. It is not an error if it doesn't exist.
+	Delete(ctx context.Context, key string) error
+}
+
+// S3Store is a Store that uses an S3 compatible object store
+type S3Store struct {
+	Bucket   string
+	Prefix   string
+	Client   s3iface.S3API
+	Uploader *s3manager.Uploader
+}
+
+// S3Config has the settings to connect to the object store
+type S3Config struct {
+	// Path is the location of the backup, such as s3://bucket/prefix
+	Path      string
+	Endpoint  string
+	Region    string
+	AccessKey string
+	SecretKey string
+}
+
+// MakeS3Store will build an S3Store from the given config. When no access
+// key is given, the default AWS credential chain is used.
+func MakeS3Store(cfg *S3Config) (*S3Store, error) {
+	bucket, prefix, err := splitS3Path(cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+	awsCfg := aws.NewConfig().WithRegion(cfg.Region)
+	if cfg.Endpoint != "" {
+		// Path style addressing is what S3 compatible stores, like MinIO,
+		// support best.
+		awsCfg = awsCfg.WithEndpoint(cfg.Endpoint).WithS3ForcePathStyle(true)
+	}
+	if cfg.AccessKey != "" {
+		awsCfg = awsCfg.WithCredentials(credentials.NewStaticCredentials(cfg.AccessKey, cfg.SecretKey, ""))
+	}
+	sess, err := session.NewSession(awsCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the S3 session: %w", err)
+	}
+	client := s3.New(sess)
+	return &S3Store{
+		Bucket:   bucket,
+		Prefix:   prefix,
+		Client:   client,
+		Uploader: s3manager.NewUploaderWithClient(client),
+	}, nil
+}
+
+// splitS3Path will return the bucket and the prefix of an s3:// path
+func splitS3Path(p string) (bucket, prefix string, err error) {
+	if !strings.HasPrefix(p, s3Prefix) {
+		return "", "", fmt.Errorf("path %q must start with %s", p, s3Prefix)
+	}
+	bucket, prefix, _ = strings.Cut(strings.TrimPrefix(p, s3Prefix), "/")
+	if bucket == "" {
+		return "", "", fmt.Errorf("path %q must include a bucket name", p)
+	}
+	return bucket, strings.Trim(prefix, "/"), nil
+}
+
+// objectKey returns the full key of an object in the bucket
+func (s *S3Store) objectKey(key string) string {
+	return path.Join(s.Prefix, key)
+}
+
+// Exists returns true if an object with the given key exists
+func (s *S3Store) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := s.Client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(s.objectKey(key)),
+	})
+	if err == nil {
+		return true, nil
+	}
+	var reqErr awserr.RequestFailure
+	if errors.As(err, &reqErr) && reqErr.StatusCode() == http.StatusNotFound {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to check if object %s exists: %w", key, err)
+}
+
+// Put writes the object, reading its content from the reader. The content is
+// uploaded in parts so that it doesn't have to be held in memory.
+func (s *S3Store) Put(ctx context.Context, key string, body io.Reader) error {
+	_, err := s.Uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(s.objectKey(key)),
+		Body:   body,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload object %s: %w", key, err)
+	}
+	return nil
+}
+
+// Get returns a reader for the content of the object
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := s.Client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(s.objectKey(key)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to download object %s: %w", key, err)
+	}
+	return out.Body, nil
+}
+
+// Delete removes the object. It is not an error if it doesn't exist.
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	_, err := s.Client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(s.objectKey(key)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete object %s: %w", key, err)
+	}
+	return nil
+}
@@ -35,6 +35,7 @@ import (
 	"gopkg.in/yaml.v2"
 	appsv1 "k8s.io/api/apps/v1"
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -119,6 +120,14 @@ const (
 	// The path to the scrutinize tarball
 	scrutinizeTarball = "SCRUTINIZE_TARBALL"
 	passwordMountName = v1beta1.PrometheusSecretKeyPassword
+	// The termination message of the analysis container when the analysis failed
+	ScrutinizeAnalysisFailedMsg = "cluster health analysis failed"
+
+	// Workload replay constants
+	workloadReplayMountName = "vwr"
+	// How long, in seconds, the workload replay job keeps its output file
+	// around for the operator to copy it
+	workloadReplayOutputTTL = 3600
 
 	// Client proxy config file name
 	vProxyConfigFile = "config.yaml"
@@ -589,7 +598,8 @@ func buildLicenseVolume(vdb *vapi.VerticaDB) corev1.Volume {
 		Name: vapi.LicensingMountName,
 		VolumeSource: corev1.VolumeSource{
 			Secret: &corev1.SecretVolumeSource{
-				SecretName: vdb.Spec.LicenseSecret,
+				// A license stored in Vault or Azure Key Vault is mounted from its mirror
+				SecretName: secrets.ResolveMirroredSecretName(vdb.Spec.LicenseSecret),
 			},
 		},
 	}
@@ -856,14 +866,15 @@ func buildPodSpec(vdb *vapi.VerticaDB, sc *vapi.Subcluster, ver string) corev1.P
 }
 
 // buildScrutinizePodSpec creates a PodSpec for the scrutinize pod
-func buildScrutinizePodSpec(vscr *v1beta1.VerticaScrutinize, vdb *vapi.VerticaDB, args []string) corev1.PodSpec {
+func buildScrutinizePodSpec(vscr *v1beta1.VerticaScrutinize, vdb *vapi.VerticaDB,
+	args, analysisArgs []string) corev1.PodSpec {
 	termGracePeriod := int64(scrutinizeTermGracePeriod)
 	tarballName := GetTarballName(args)
 	return corev1.PodSpec{
 		NodeSelector:                  vscr.Spec.NodeSelector,
 		Affinity:                      GetK8sAffinity(vapi.Affinity(vscr.Spec.Affinity)),
 		Tolerations:                   vscr.Spec.Tolerations,
-		InitContainers:                makeScrutinizeInitContainers(vscr, vdb, args, tarballName),
+		InitContainers:                makeScrutinizeInitContainers(vscr, vdb, args, analysisArgs, tarballName),
 		Containers:                    []corev1.Container{makeScrutinizeMainContainer(vscr, tarballName)},
 		Volumes:                       buildScrutinizeVolumes(vscr, vdb),
 		TerminationGracePeriodSeconds: &termGracePeriod,
@@ -887,10 +898,14 @@ func makeVerticaContainers(vdb *vapi.VerticaDB, sc *vapi.Subcluster, ver string)
 
 // makeScrutinizeInitContainers creates a list of init container specs that will be
 // part of the scrutinize pod. The first container is the one that collects
-// scrutinize command
+// scrutinize command. If analysis args are given, it is followed by the container
+// that runs the cluster health analysis.
 func makeScrutinizeInitContainers(vscr *v1beta1.VerticaScrutinize, vdb *vapi.VerticaDB,
-	args []string, tarballName string) []corev1.Container {
+	args, analysisArgs []string, tarballName string) []corev1.Container {
 	cnts := []corev1.Container{makeScrutinizeInitContainer(vscr, vdb, args, tarballName)}
+	if len(analysisArgs) > 0 {
+		cnts = append(cnts, makeScrutinizeAnalysisContainer(vscr, vdb, analysisArgs, tarballName))
+	}
 	for i := range vscr.Spec.InitContainers {
 		c := vscr.Spec.InitContainers[i]
 		c.Env = append(c.Env, buildScrutinizeTarballEnvVar(tarballName))
@@ -952,6 +967,59 @@ func BuildBasicAuthSecret(vdb *vapi.VerticaDB, name, username, password string)
 	}
 }
 
+// BuildMirroredSecret builds the Kubernetes secret that mirrors the contents
+// of a secret stored outside of Kubernetes
+func BuildMirroredSecret(vdb *vapi.VerticaDB, nm types.NamespacedName, sourceSecret string, data map[string][]byte) *corev1.Secret {
+	annotations := MakeAnnotationsForObject(vdb)
+	annotations[vmeta.MirroredSecretSourceAnnotation] = sourceSecret
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       nm.Namespace,
+			Name:            nm.Name,
+			Annotations:     annotations,
+			Labels:          MakeCommonLabels(vdb, nil, false, false),
+			OwnerReferences: []metav1.OwnerReference{vdb.GenerateOwnerReference()},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+}
+
+// BuildClientTLSAuthSecret builds the secret that holds the client-server
+// certificate and key, along with the CA certificates of the server TLS
+// configuration and the ones trusted to sign client certificates
+func BuildClientTLSAuthSecret(vdb *vapi.VerticaDB, nm types.NamespacedName, data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       nm.Namespace,
+			Name:            nm.Name,
+			Annotations:     MakeAnnotationsForObject(vdb),
+			Labels:          MakeCommonLabels(vdb, nil, false, false),
+			OwnerReferences: []metav1.OwnerReference{vdb.GenerateOwnerReference()},
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: data,
+	}
+}
+
+// BuildRotatedPasswordSecret builds the secret that stores a superuser
+// password generated by the operator
+func BuildRotatedPasswordSecret(vdb *vapi.VerticaDB, nm types.NamespacedName, passwd string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       nm.Namespace,
+			Name:            nm.Name,
+			Annotations:     MakeAnnotationsForObject(vdb),
+			Labels:          MakeCommonLabels(vdb, nil, false, false),
+			OwnerReferences: []metav1.OwnerReference{vdb.GenerateOwnerReference()},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			names.SuperuserPasswordKey: []byte(passwd),
+		},
+	}
+}
+
 func makeBasicAuthForServiceMonitor(vdb *vapi.VerticaDB, secret string) *monitoringv1.BasicAuth {
 	if vdb.IsHTTPSNMATLSAuthEnabledWithMinVersion() {
 		return nil
@@ -1027,7 +1095,7 @@ func genTLSKeys(vdb *vapi.VerticaDB) (key *corev1.SecretKeySelector,
 
 // BuildHorizontalPodAutoscaler builds a manifest for the horizontal pod autoscaler.
 func BuildHorizontalPodAutoscaler(nm types.NamespacedName, vas *vapi.VerticaAutoscaler) *autoscalingv2.HorizontalPodAutoscaler {
-	return &autoscalingv2.HorizontalPodAutoscaler{
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: nm.Namespace,
 			Name:      nm.Name,
@@ -1044,6 +1112,14 @@ func BuildHorizontalPodAutoscaler(nm types.NamespacedName, vas *vapi.VerticaAuto
 			Behavior:    vas.Spec.CustomAutoscaler.Hpa.Behavior,
 		},
 	}
+	if vas.IsScalingPolicyEnabled() {
+		// The operator makes the scaling decision, so the hpa is pinned to
+		// it. The hpa still collects the metrics that the policy is fed.
+		size := vas.GetScalingPolicySize()
+		hpa.Spec.MinReplicas = &size
+		hpa.Spec.MaxReplicas = size
+	}
+	return hpa
 }
 
 // BuildScaledObject builds a manifest for a keda scaledObject.
@@ -1061,7 +1137,7 @@ func BuildScaledObject(nm types.NamespacedName, vas *vapi.VerticaAutoscaler) *ke
 				Kind:       vapi.VerticaAutoscalerKind,
 				Name:       vas.Name,
 			},
-			MinReplicaCount: so.MinReplicas,
+			MinReplicaCount: vas.GetScaledObjectMinReplicas(),
 			MaxReplicaCount: so.MaxReplicas,
 			PollingInterval: so.PollingInterval,
 			CooldownPeriod:  so.CooldownPeriod,
@@ -1493,6 +1569,17 @@ func makeScrutinizeInitContainer(vscr *v1beta1.VerticaScrutinize, vdb *vapi.Vert
 	return cnt
 }
 
+// makeScrutinizeAnalysisContainer builds the spec of the init container that runs
+// the slow event and lock analyses. It has the same access to the database as
+// the scrutinize init container.
+func makeScrutinizeAnalysisContainer(vscr *v1beta1.VerticaScrutinize, vdb *vapi.VerticaDB,
+	args []string, tarballName string) corev1.Container {
+	cnt := makeScrutinizeInitContainer(vscr, vdb, args, tarballName)
+	cnt.Name = names.ScrutinizeAnalysisContainer
+	cnt.Command = buildScrutinizeAnalysisCmd(args)
+	return cnt
+}
+
 // makeScrutinizeMainContainer builds the spec of the container that will
 // be running after all init containers are completed
 func makeScrutinizeMainContainer(vscr *v1beta1.VerticaScrutinize, tarballName string) corev1.Container {
@@ -1581,11 +1668,12 @@ func makeDefaultReadinessOrStartupProbe(vdb *vapi.VerticaDB, ver string) *corev1
 	if probe := getHTTPServerVersionEndpointProbe(vdb, ver); probe != nil {
 		return probe
 	}
-	// If using GSM, then the superuser password is not a k8s secret. We cannot
-	// use the canary query then because that depends on having the password
-	// mounted in the file system. Default to just checking if the client port
-	// is being listened on.
-	if secrets.IsGSMSecret(vdb.GetPasswordSecret()) {
+	// If using GSM, Vault or Azure Key Vault, then the superuser password is
+	// not a k8s secret. We cannot use the canary query then because that
+	// depends on having the password mounted in the file system. Default to
+	// just checking if the client port is being listened on.
+	if secrets.IsGSMSecret(vdb.GetPasswordSecret()) || secrets.IsVaultSecret(vdb.GetPasswordSecret()) ||
+		secrets.IsAzureKeyVaultSecret(vdb.GetPasswordSecret()) {
 		return makeVerticaClientPortProbe()
 	}
 	return makeCanaryQueryProbe(vdb)
@@ -1946,8 +2034,9 @@ func BuildSandboxConfigMap(nm types.NamespacedName, vdb *vapi.VerticaDB, sandbox
 	}
 }
 
-// BuildScrutinizePod construct the spec for the scrutinize pod
-func BuildScrutinizePod(vscr *v1beta1.VerticaScrutinize, vdb *vapi.VerticaDB, args []string) *corev1.Pod {
+// BuildScrutinizePod construct the spec for the scrutinize pod. analysisArgs
+// can be empty, in which case the cluster health analysis is skipped.
+func BuildScrutinizePod(vscr *v1beta1.VerticaScrutinize, vdb *vapi.VerticaDB, args, analysisArgs []string) *corev1.Pod {
 	return &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        vscr.Name,
@@ -1955,10 +2044,116 @@ func BuildScrutinizePod(vscr *v1beta1.VerticaScrutinize, vdb *vapi.VerticaDB, ar
 			Labels:      maps.Clone(vscr.Spec.Labels),
 			Annotations: maps.Clone(vscr.Spec.Annotations),
 		},
-		Spec: buildScrutinizePodSpec(vscr, vdb, args),
+		Spec: buildScrutinizePodSpec(vscr, vdb, args, analysisArgs),
+	}
+}
+
+// BuildWorkloadReplayJob constructs the spec of the job that runs one step, the
+// capture or the replay, of a VerticaWorkloadReplay. The vcluster command runs
+// in an init container and writes its output file to a shared volume. The main
+// container then holds the file until the operator has copied it to the object
+// store. If inputFile is set, the command waits for the operator to copy that
+// file in before it starts.
+func BuildWorkloadReplayJob(nm types.NamespacedName, vdb *vapi.VerticaDB, inputFile string, args []string) *batchv1.Job {
+	backoffLimit := int32(0)
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nm.Name,
+			Namespace: nm.Namespace,
+		},
+		Spec: batchv1.JobSpec{
+			// The step isn't retried. A failure completes the replay.
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				Spec: buildWorkloadReplayPodSpec(vdb, inputFile, args),
+			},
+		},
 	}
 }
 
+// buildWorkloadReplayPodSpec constructs the spec of the pod of a workload
+// replay job
+func buildWorkloadReplayPodSpec(vdb *vapi.VerticaDB, inputFile string, args []string) corev1.PodSpec {
+	return corev1.PodSpec{
+		InitContainers:     []corev1.Container{makeWorkloadReplayContainer(vdb, inputFile, args)},
+		Containers:         []corev1.Container{makeWorkloadReplayMainContainer(vdb)},
+		Volumes:            buildWorkloadReplayVolumes(vdb),
+		RestartPolicy:      corev1.RestartPolicyNever,
+		SecurityContext:    vdb.Spec.PodSecurityContext,
+		ServiceAccountName: vdb.Spec.ServiceAccountName,
+		ImagePullSecrets:   GetK8sLocalObjectReferenceArray(vdb.Spec.ImagePullSecrets),
+	}
+}
+
+// makeWorkloadReplayContainer builds the spec of the init container that runs
+// the vcluster capture or replay command. Its logs become the termination
+// message if it fails, so the operator can report why.
+func makeWorkloadReplayContainer(vdb *vapi.VerticaDB, inputFile string, args []string) corev1.Container {
+	cnt := corev1.Container{
+		Image:                    vdb.Spec.Image,
+		Name:                     names.WorkloadReplayContainer,
+		Command:                  buildWorkloadReplayCmd(inputFile, args),
+		VolumeMounts:             []corev1.VolumeMount{buildWorkloadReplaySharedVolumeMount()},
+		Env:                      append(buildCommonEnvVars(vdb), buildNMATLSCertsEnvVars(vdb)...),
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+	}
+	if vdb.GetPasswordSecret() != "" {
+		if secrets.IsK8sSecret(vdb.GetPasswordSecret()) {
+			cnt.VolumeMounts = append(cnt.VolumeMounts, corev1.VolumeMount{
+				Name:      passwordMountName,
+				MountPath: paths.ScrutinizeDBPasswordDir,
+			})
+		} else {
+			// vcluster retrieves a password kept in a secret store outside
+			// of k8s, like GSM or Vault, itself
+			cnt.Env = append(cnt.Env, buildScrutinizeDBPasswordEnvVars(
+				names.GenNamespacedName(vdb, vdb.GetPasswordSecret()))...)
+		}
+	}
+	if vmeta.UseNMACertsMount(vdb.Annotations) &&
+		vdb.GetNMATLSSecret() != "" &&
+		secrets.IsK8sSecret(vdb.GetNMATLSSecret()) {
+		cnt.VolumeMounts = append(cnt.VolumeMounts, buildNMACertsVolumeMount()...)
+	}
+	return cnt
+}
+
+// makeWorkloadReplayMainContainer builds the spec of the container that holds
+// the output of a workload replay job until the operator has copied it
+func makeWorkloadReplayMainContainer(vdb *vapi.VerticaDB) corev1.Container {
+	return corev1.Container{
+		Image:        vdb.Spec.Image,
+		Name:         names.WorkloadReplayMainContainer,
+		Command:      buildWorkloadReplayMainCmd(),
+		WorkingDir:   paths.WorkloadReplayTmp,
+		VolumeMounts: []corev1.VolumeMount{buildWorkloadReplaySharedVolumeMount()},
+	}
+}
+
+// buildWorkloadReplaySharedVolumeMount returns the volume mount shared by the
+// containers of a workload replay job
+func buildWorkloadReplaySharedVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      workloadReplayMountName,
+		MountPath: paths.WorkloadReplayTmp,
+	}
+}
+
+// buildWorkloadReplayVolumes returns the volumes of a workload replay job
+func buildWorkloadReplayVolumes(vdb *vapi.VerticaDB) []corev1.Volume {
+	vols := []corev1.Volume{buildEmptyDirVolume(workloadReplayMountName)}
+	if vmeta.UseNMACertsMount(vdb.Annotations) &&
+		vdb.GetNMATLSSecret() != "" &&
+		secrets.IsK8sSecret(vdb.GetNMATLSSecret()) {
+		vols = append(vols, buildNMACertsSecretVolume(vdb))
+	}
+	if vdb.GetPasswordSecret() != "" &&
+		secrets.IsK8sSecret(vdb.GetPasswordSecret()) {
+		vols = append(vols, buildPasswordVolume(vdb))
+	}
+	return vols
+}
+
 // buildPod will construct a spec for a pod.
 // This is only here for testing purposes when we need to construct the pods ourselves.  This
 // bit is typically handled by the statefulset controller.
@@ -2193,6 +2388,39 @@ func buildScrutinizeCmd(args []string) []string {
 	return cmd
 }
 
+// buildWorkloadReplayCmd returns the vcluster command of a workload replay
+// job. If inputFile is set, the command waits for the file to exist first.
+func buildWorkloadReplayCmd(inputFile string, args []string) []string {
+	script := `exec /opt/vertica/bin/vcluster "$@"`
+	if inputFile != "" {
+		script = fmt.Sprintf("until [ -f %q ]; do sleep 1; done; %s", inputFile, script)
+	}
+	cmd := []string{"bash", "-c", script, "--"}
+	cmd = append(cmd, args...)
+	return cmd
+}
+
+// buildWorkloadReplayMainCmd returns the command of the container that holds
+// the output of a workload replay job. It exits successfully once the operator
+// creates the done file, and fails if that doesn't happen within the TTL.
+func buildWorkloadReplayMainCmd() []string {
+	script := fmt.Sprintf("for i in $(seq %d); do [ -f %q ] && exit 0; sleep 1; done; exit 1",
+		workloadReplayOutputTTL, paths.WorkloadReplayDoneFile)
+	return []string{"bash", "-c", script}
+}
+
+// buildScrutinizeAnalysisCmd returns the command that runs the cluster health
+// analysis. A failed analysis must not prevent the main container from
+// starting, as the tarball would not be retrievable anymore. So we always exit
+// successfully and report the failure through the termination message.
+func buildScrutinizeAnalysisCmd(args []string) []string {
+	script := fmt.Sprintf("/opt/vertica/bin/vcluster cluster_health \"$@\" || echo %q > %s",
+		ScrutinizeAnalysisFailedMsg, corev1.TerminationMessagePathDefault)
+	cmd := []string{"bash", "-c", script, "--"}
+	cmd = append(cmd, args...)
+	return cmd
+}
+
 // buildScrutinizeDBpasswordEnvVars returns environment variables that are needed
 // by scrutinize to read password from secret
 func buildScrutinizeDBPasswordEnvVars(nm types.NamespacedName) []corev1.EnvVar {
@@ -2360,6 +2588,17 @@ func GetTarballName(cmd []string) string {
 	return ""
 }
 
+// GetAnalysisReportName extracts the name of the analysis report
+// from the analysis container command
+func GetAnalysisReportName(cmd []string) string {
+	for i := range cmd {
+		if cmd[i] == "--output-file" && i < len(cmd)-1 {
+			return filepath.Base(cmd[i+1])
+		}
+	}
+	return ""
+}
+
 // BuildNMATLSConfigMap builds a configmap with tls secret name in it.
 // The configmap will be mapped to environmental variables in NMA pod
 func BuildNMATLSConfigMap(nm types.NamespacedName, vdb *vapi.VerticaDB) *corev1.ConfigMap {
@@ -2373,11 +2612,12 @@ func BuildNMATLSConfigMap(nm types.NamespacedName, vdb *vapi.VerticaDB) *corev1.
 	} else if !vdb.IsClientServerTLSAuthEnabled() {
 		clientSecretTLSMode = "disable"
 	}
+	// NMA reads the secrets stored in Vault or Azure Key Vault from their mirror
 	secretMap := map[string]string{
 		NMASecretNamespaceEnv:       vdb.ObjectMeta.Namespace,
-		NMASecretNameEnv:            vdb.GetHTTPSNMATLSSecretForConfigMap(),
+		NMASecretNameEnv:            secrets.ResolveMirroredSecretName(vdb.GetHTTPSNMATLSSecretForConfigMap()),
 		NMAClientSecretNamespaceEnv: clientSecretNamespace,
-		NMAClientSecretNameEnv:      clientSecretName,
+		NMAClientSecretNameEnv:      secrets.ResolveMirroredSecretName(clientSecretName),
 		NMAClientSecretTLSModeEnv:   clientSecretTLSMode,
 	}
 	tlsConfigMap := &corev1.ConfigMap{
@@ -27,6 +27,7 @@ import (
 	vmeta "github.com/vertica/vertica-kubernetes/pkg/meta"
 	"github.com/vertica/vertica-kubernetes/pkg/names"
 	"github.com/vertica/vertica-kubernetes/pkg/paths"
+	"github.com/vertica/vertica-kubernetes/pkg/secrets"
 	"github.com/vertica/vertica-kubernetes/pkg/vk8s"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -130,7 +131,7 @@ var _ = Describe("builder", func() {
 		vscr.Spec.Volume = &v1.Volume{
 			Name: testVol,
 		}
-		pod := BuildScrutinizePod(vscr, vdb, []string{})
+		pod := BuildScrutinizePod(vscr, vdb, []string{}, nil)
 		vols := pod.Spec.Volumes
 		Ω(len(vols)).Should(Equal(1))
 		Ω(vols[0].Name).Should(Equal(testVol))
@@ -155,7 +156,7 @@ var _ = Describe("builder", func() {
 			{Name: "init1"},
 			{Name: "init2"},
 		}
-		pod := BuildScrutinizePod(vscr, vdb, []string{})
+		pod := BuildScrutinizePod(vscr, vdb, []string{}, nil)
 		cnts := pod.Spec.InitContainers
 		Ω(len(cnts)).Should(Equal(3))
 		Ω(cnts[0].Name).Should(Equal(names.ScrutinizeInitContainer))
@@ -177,7 +178,7 @@ var _ = Describe("builder", func() {
 			"--db-name", "db",
 			"--tarball-name", tarballName,
 			"--db-user", "dbadmin",
-		})
+		}, nil)
 		cnts := pod.Spec.InitContainers
 		cnts = append(cnts, pod.Spec.Containers...)
 		Ω(len(cnts)).Should(Equal(4))
@@ -203,7 +204,7 @@ var _ = Describe("builder", func() {
 			"ant1": "val3",
 			"ant2": "val4",
 		}
-		pod := BuildScrutinizePod(vscr, vdb, []string{})
+		pod := BuildScrutinizePod(vscr, vdb, []string{}, nil)
 		verifyLabelsAnnotations := func(objectMeta *metav1.ObjectMeta) {
 			Ω(objectMeta.Labels["label1"]).Should(Equal("val1"))
 			Ω(objectMeta.Labels["label2"]).Should(Equal("val2"))
@@ -219,7 +220,7 @@ var _ = Describe("builder", func() {
 		vscr.Annotations[vmeta.ScrutinizePodRestartPolicyAnnotation] = string(v1.RestartPolicyAlways)
 		vscr.Annotations[vmeta.ScrutinizePodTTLAnnotation] = "180"
 		vscr.Annotations[vmeta.ScrutinizeMainContainerImageAnnotation] = "alpine"
-		pod := BuildScrutinizePod(vscr, vdb, []string{})
+		pod := BuildScrutinizePod(vscr, vdb, []string{}, nil)
 
 		Ω(pod.Spec.RestartPolicy).Should(Equal(v1.RestartPolicyAlways))
 		Ω(pod.Spec.Containers[0].Image).Should(Equal("alpine"))
@@ -234,7 +235,7 @@ var _ = Describe("builder", func() {
 			"--hosts", "h1,h2,h3",
 			"--db-name", "db",
 			"--db-user", "dbadmin",
-		})
+		}, nil)
 
 		cnt := pod.Spec.InitContainers[0]
 		Ω(cnt.Image).Should(Equal(vdb.Spec.Image))
@@ -249,11 +250,78 @@ var _ = Describe("builder", func() {
 		Ω(cnt.Command).Should(ContainElement(ContainSubstring("scrutinize")))
 	})
 
+	It("should run the workload replay command in an init container that waits for its input", func() {
+		vdb := vapi.MakeVDB()
+		nm := types.NamespacedName{Namespace: vdb.Namespace, Name: "vwr-replay"}
+		job := BuildWorkloadReplayJob(nm, vdb, "/tmp/vwr/capture.csv", []string{"replay_workload", "--sandbox", "sand"})
+		Ω(job.Name).Should(Equal(nm.Name))
+		Ω(*job.Spec.BackoffLimit).Should(BeZero())
+		Ω(job.Spec.Template.Spec.RestartPolicy).Should(Equal(v1.RestartPolicyNever))
+
+		cnts := job.Spec.Template.Spec.InitContainers
+		Ω(cnts).Should(HaveLen(1))
+		Ω(cnts[0].Name).Should(Equal(names.WorkloadReplayContainer))
+		Ω(cnts[0].Image).Should(Equal(vdb.Spec.Image))
+		Ω(cnts[0].Command).Should(ContainElement(ContainSubstring("/tmp/vwr/capture.csv")))
+		Ω(cnts[0].Command).Should(ContainElements("replay_workload", "--sandbox", "sand"))
+
+		cnts = job.Spec.Template.Spec.Containers
+		Ω(cnts).Should(HaveLen(1))
+		Ω(cnts[0].Name).Should(Equal(names.WorkloadReplayMainContainer))
+		Ω(cnts[0].Command).Should(ContainElement(ContainSubstring(paths.WorkloadReplayDoneFile)))
+
+		// Without an input file, the command starts right away
+		job = BuildWorkloadReplayJob(nm, vdb, "", []string{"capture_workload"})
+		Ω(job.Spec.Template.Spec.InitContainers[0].Command).ShouldNot(ContainElement(ContainSubstring("until")))
+	})
+
+	It("should mount a k8s password secret in the workload replay job and pass others through env vars", func() {
+		vdb := vapi.MakeVDB()
+		vdb.Spec.PasswordSecret = "su-passwd"
+		nm := types.NamespacedName{Namespace: vdb.Namespace, Name: "vwr-capture"}
+		cnt := BuildWorkloadReplayJob(nm, vdb, "", []string{"capture_workload"}).Spec.Template.Spec.InitContainers[0]
+		Ω(makeVolumeMountNames(&cnt)).Should(ContainElement(passwordMountName))
+		Ω(makeEnvVars(&cnt)).ShouldNot(ContainElement(passwordSecretNameEnv))
+
+		vdb.Spec.PasswordSecret = "gsm://projects/123/secrets/su-passwd/versions/1"
+		job := BuildWorkloadReplayJob(nm, vdb, "", []string{"capture_workload"})
+		cnt = job.Spec.Template.Spec.InitContainers[0]
+		Ω(makeVolumeMountNames(&cnt)).ShouldNot(ContainElement(passwordMountName))
+		Ω(makeEnvVars(&cnt)).Should(ContainElements(passwordSecretNameEnv, passwordSecretNamespaceEnv))
+		Ω(job.Spec.Template.Spec.Volumes).ShouldNot(ContainElement(HaveField("Name", passwordMountName)))
+	})
+
+	It("should add the analysis init container after scrutinize only when analysis args are given", func() {
+		vscr := v1beta1.MakeVscr()
+		vdb := vapi.MakeVDB()
+		vscr.Spec.InitContainers = []v1.Container{{Name: "init1"}}
+		pod := BuildScrutinizePod(vscr, vdb, []string{"--tarball-name", "test"}, nil)
+		Ω(pod.Spec.InitContainers).Should(HaveLen(2))
+
+		pod = BuildScrutinizePod(vscr, vdb, []string{"--tarball-name", "test"}, []string{
+			"--hosts", "h1,h2,h3",
+			"--start-time", "2024-01-01 10:00:00",
+			"--output-file", "/tmp/scrutinize/test.analysis.json",
+		})
+		Ω(pod.Spec.InitContainers).Should(HaveLen(3))
+		Ω(pod.Spec.InitContainers[0].Name).Should(Equal(names.ScrutinizeInitContainer))
+		cnt := pod.Spec.InitContainers[1]
+		Ω(cnt.Name).Should(Equal(names.ScrutinizeAnalysisContainer))
+		Ω(cnt.Image).Should(Equal(vdb.Spec.Image))
+		Ω(cnt.Command[:2]).Should(Equal([]string{"bash", "-c"}))
+		Ω(cnt.Command[2]).Should(ContainSubstring("cluster_health"))
+		Ω(cnt.Command[2]).Should(ContainSubstring(v1.TerminationMessagePathDefault))
+		// args are passed positionally so that values with spaces are kept intact
+		Ω(cnt.Command).Should(ContainElement("2024-01-01 10:00:00"))
+		Ω(pod.Spec.InitContainers[2].Name).Should(Equal("init1"))
+		Ω(GetAnalysisReportName(cnt.Command)).Should(Equal("test.analysis.json"))
+	})
+
 	It("should not set any main container resources if none are set for the init container", func() {
 		vscr := v1beta1.MakeVscr()
 		vdb := vapi.MakeVDB()
 		vscr.Spec.Resources = v1.ResourceRequirements{}
-		pod := BuildScrutinizePod(vscr, vdb, []string{})
+		pod := BuildScrutinizePod(vscr, vdb, []string{}, nil)
 		cnt := pod.Spec.Containers[0]
 		verifyNoResourcesSet(&cnt)
 	})
@@ -263,7 +331,7 @@ var _ = Describe("builder", func() {
 		vdb := vapi.MakeVDB()
 		const secretName = "test"
 		vdb.Spec.ImagePullSecrets = []vapi.LocalObjectReference{{Name: secretName}}
-		pod := BuildScrutinizePod(vscr, vdb, []string{})
+		pod := BuildScrutinizePod(vscr, vdb, []string{}, nil)
 		Ω(len(pod.Spec.ImagePullSecrets)).Should(Equal(1))
 		Ω(pod.Spec.ImagePullSecrets[0].Name).Should(Equal(secretName))
 	})
@@ -274,7 +342,7 @@ var _ = Describe("builder", func() {
 		vscr.Annotations[vmeta.GenScrutinizeMainContainerResourcesAnnotationName(v1.ResourceLimitsCPU)] = strconv.Itoa(cpuLimit)
 		vscr.Annotations[vmeta.GenScrutinizeMainContainerResourcesAnnotationName(v1.ResourceLimitsMemory)] = fmt.Sprintf("%dGi", memLimit)
 		vscr.Spec.Resources = makeResources()
-		pod := BuildScrutinizePod(vscr, vdb, []string{})
+		pod := BuildScrutinizePod(vscr, vdb, []string{}, nil)
 		cnt := pod.Spec.Containers[0]
 		actual, _ := cnt.Resources.Limits.Cpu().AsInt64()
 		Ω(actual).Should(Equal(int64(cpuLimit)))
@@ -296,7 +364,7 @@ var _ = Describe("builder", func() {
 		vscr.Annotations[vmeta.GenScrutinizeMainContainerResourcesAnnotationName(v1.ResourceLimitsCPU)] = ""
 		vscr.Annotations[vmeta.GenScrutinizeMainContainerResourcesAnnotationName(v1.ResourceLimitsMemory)] = ""
 		vscr.Spec.Resources = makeResources()
-		pod := BuildScrutinizePod(vscr, vdb, []string{})
+		pod := BuildScrutinizePod(vscr, vdb, []string{}, nil)
 		cnt := pod.Spec.Containers[0]
 		_, ok := cnt.Resources.Limits[v1.ResourceCPU]
 		Ω(ok).Should(BeFalse())
@@ -641,6 +709,26 @@ var _ = Describe("builder", func() {
 		Ω(isPasswdIncludedInPodInfo(vdb, &c)).Should(BeFalse())
 	})
 
+	It("should not use canary query probe if using Vault or Azure Key Vault", func() {
+		for _, passwd := range []string{"vault://secret/dbadmin", "azkv://myvault/dbadmin"} {
+			vdb := vapi.MakeVDB()
+			vdb.Spec.PasswordSecret = passwd
+			vdb.Status.PasswordSecret = &vdb.Spec.PasswordSecret
+			c := buildPodSpec(vdb, &vdb.Spec.Subclusters[0], "")
+			Ω(isPasswdIncludedInPodInfo(vdb, &c)).Should(BeFalse())
+		}
+	})
+
+	It("should mount the mirror of a license stored in Vault", func() {
+		vdb := vapi.MakeVDB()
+		vdb.Spec.LicenseSecret = "vault://secret/vertica/license"
+		vol := buildLicenseVolume(vdb)
+		Ω(vol.Secret.SecretName).Should(Equal(secrets.GetMirroredSecretName(vdb.Spec.LicenseSecret)))
+		vdb.Spec.LicenseSecret = "my-license"
+		vol = buildLicenseVolume(vdb)
+		Ω(vol.Secret.SecretName).Should(Equal("my-license"))
+	})
+
 	It("should override some of the pod securityContext settings", func() {
 		vdb := vapi.MakeVDB()
 		vdb.Spec.PodSecurityContext = &v1.PodSecurityContext{
@@ -784,6 +872,15 @@ var _ = Describe("builder", func() {
 		Ω(configMap.Data[NMASecretNamespaceEnv]).Should(Equal(vdb.Namespace))
 	})
 
+	It("configmap should have the mirror name of a nma cert secret stored in Vault", func() {
+		const vaultSecret = "vault://secret/vertica/nma-tls"
+		vdb := vapi.MakeVDBForHTTP(vaultSecret)
+		vdb.Annotations[vmeta.VClusterOpsAnnotation] = vmeta.VClusterOpsAnnotationTrue
+		vdb.Annotations[vmeta.VersionAnnotation] = vapi.NMAInSideCarDeploymentMinVersion
+		configMap := BuildNMATLSConfigMap(names.GenNamespacedName(vdb, "nma-configmap"), vdb)
+		Ω(configMap.Data[NMASecretNameEnv]).Should(Equal(secrets.GetMirroredSecretName(vaultSecret)))
+	})
+
 	It("should set scaledobject annotation and prometheus unsafessl field", func() {
 		vas := vapi.MakeVASWithMetrics()
 		vas.Spec.CustomAutoscaler.Hpa = nil
@@ -810,6 +907,26 @@ var _ = Describe("builder", func() {
 		Ω(sa.Spec.Triggers[0].Metadata["unsafeSsl"]).Should(Equal(vas.Spec.CustomAutoscaler.ScaledObject.Metrics[0].GetUnsafeSslStr()))
 	})
 
+	It("should pin the hpa to the size decided by the scaling policy", func() {
+		vas := vapi.MakeVASWithMetrics()
+		vas.Spec.TargetSize = 4
+		nm := names.GenHPAName(vas)
+		hpa := BuildHorizontalPodAutoscaler(nm, vas)
+		Ω(*hpa.Spec.MinReplicas).Should(Equal(int32(3)))
+		Ω(hpa.Spec.MaxReplicas).Should(Equal(int32(6)))
+
+		vas.Spec.CustomAutoscaler.ScalingPolicy = &vapi.ScalingPolicySpec{}
+		hpa = BuildHorizontalPodAutoscaler(nm, vas)
+		Ω(*hpa.Spec.MinReplicas).Should(Equal(int32(4)))
+		Ω(hpa.Spec.MaxReplicas).Should(Equal(int32(4)))
+
+		// The decision is kept within the replica range of the hpa
+		vas.Status.PolicyEvaluation = &vapi.ScalingPolicyEvaluation{DesiredSize: 10}
+		hpa = BuildHorizontalPodAutoscaler(nm, vas)
+		Ω(*hpa.Spec.MinReplicas).Should(Equal(int32(6)))
+		Ω(hpa.Spec.MaxReplicas).Should(Equal(int32(6)))
+	})
+
 	It("nma container should have all eight environmental variables", func() {
 		vdb := vapi.MakeVDB()
 		envVars := buildNMATLSCertsEnvVars(vdb)
@@ -1036,7 +1153,7 @@ func verifyScrutinizePasswordMount(secret string, should bool) {
 	vdb := vapi.MakeVDB()
 	vdb.Spec.PasswordSecret = secret
 	vdb.Status.PasswordSecret = &vdb.Spec.PasswordSecret
-	pod := BuildScrutinizePod(vscr, vdb, []string{})
+	pod := BuildScrutinizePod(vscr, vdb, []string{}, nil)
 	cnt := pod.Spec.InitContainers[0]
 	matcher := ContainElement(WithTransform(func(vm v1.VolumeMount) string {
 		return vm.Name
@@ -1053,7 +1170,7 @@ func verifyScrutinizePasswordEnvVars(secret string, offset int, should bool) {
 	vdb := vapi.MakeVDB()
 	vdb.Spec.PasswordSecret = secret
 	vdb.Status.PasswordSecret = &vdb.Spec.PasswordSecret
-	pod := BuildScrutinizePod(vscr, vdb, []string{})
+	pod := BuildScrutinizePod(vscr, vdb, []string{}, nil)
 	cnt := pod.Spec.InitContainers[0]
 	l := len(buildNMATLSCertsEnvVars(vdb)) + len(buildCommonEnvVars(vdb))
 	// l+1 to take into account the tarball env var
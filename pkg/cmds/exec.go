@@ -41,6 +41,10 @@ type PodRunner interface {
 	ExecAdmintools(ctx context.Context, podName types.NamespacedName, contName string, command ...string) (string, string, error)
 	CopyToPod(ctx context.Context, podName types.NamespacedName, contName string, sourceFile string,
 		destFile string, executeCmd ...string) (stdout, stderr string, err error)
+	StreamFromPod(ctx context.Context, podName types.NamespacedName, contName string, out io.Writer,
+		command ...string) (stderr string, err error)
+	StreamToPod(ctx context.Context, podName types.NamespacedName, contName string, in io.Reader,
+		command ...string) (stdout, stderr string, err error)
 	DumpAdmintoolsConf(ctx context.Context, podName types.NamespacedName)
 }
 
@@ -146,6 +150,30 @@ func (c *ClusterPodRunner) CopyToPod(ctx context.Context, podName types.Namespac
 	return execOut.String(), execErr.String(), err
 }
 
+// StreamFromPod executes a command inside of a pod and writes its stdout to
+// the given writer as it is produced. This is meant for commands whose output
+// is too large to be held in memory, such as reading a data file.
+func (c *ClusterPodRunner) StreamFromPod(ctx context.Context, podName types.NamespacedName,
+	contName string, out io.Writer, command ...string) (stderr string, err error) {
+	var execErr bytes.Buffer
+
+	err = c.postExec(ctx, podName, contName, command, out, &execErr, nil)
+	return execErr.String(), err
+}
+
+// StreamToPod executes a command inside of a pod with its stdin read from the
+// given reader.
+func (c *ClusterPodRunner) StreamToPod(ctx context.Context, podName types.NamespacedName,
+	contName string, in io.Reader, command ...string) (stdout, stderr string, err error) {
+	var (
+		execOut bytes.Buffer
+		execErr bytes.Buffer
+	)
+
+	err = c.postExec(ctx, podName, contName, command, &execOut, &execErr, in)
+	return execOut.String(), execErr.String(), err
+}
+
 // ExecVSQL appends options to the vsql command and calls ExecInPod
 func (c *ClusterPodRunner) ExecVSQL(ctx context.Context, podName types.NamespacedName,
 	contName string, command ...string) (stdout, stderr string, err error) {
@@ -238,7 +266,7 @@ func getSupportingPasswdSlice() []string {
 
 // postExec makes the actual POST call to the REST endpoint to do the exec
 func (c *ClusterPodRunner) postExec(ctx context.Context, podName types.NamespacedName, contName string, command []string,
-	execOut, execErr *bytes.Buffer, execIn io.Reader) error {
+	execOut io.Writer, execErr *bytes.Buffer, execIn io.Reader) error {
 	c.logInfoCmd(podName, command...)
 
 	cli, err := kubernetes.NewForConfig(c.Cfg)
@@ -274,7 +302,13 @@ func (c *ClusterPodRunner) postExec(ctx context.Context, podName types.Namespace
 		Stderr: execErr,
 		Stdin:  execIn,
 	})
-	c.Log.Info("ExecInPod stream", "pod", podName, "err", err, "stdout", execOut.String(), "stderr", execErr.String())
+	// The stdout is only logged when it was buffered. Streamed output can be
+	// large and binary.
+	stdout := "<streamed>"
+	if buf, ok := execOut.(*bytes.Buffer); ok {
+		stdout = buf.String()
+	}
+	c.Log.Info("ExecInPod stream", "pod", podName, "err", err, "stdout", stdout, "stderr", execErr.String())
 
 	if err != nil {
 		return fmt.Errorf("could not execute: %v", err)
@@ -18,6 +18,7 @@ package cmds
 import (
 	"context"
 	"fmt"
+	"io"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/types"
@@ -56,6 +57,8 @@ type CmdResult struct {
 type CmdHistory struct {
 	Pod     types.NamespacedName
 	Command []string
+	// Stdin holds what was passed to the command through StreamToPod
+	Stdin string
 }
 
 // ExecInPod is a test stub for a real exec call to a pod.
@@ -101,6 +104,32 @@ func (f *FakePodRunner) CopyToPod(ctx context.Context, podName types.NamespacedN
 	return f.ExecInPod(ctx, podName, contName, executeCmd...)
 }
 
+// StreamFromPod calls ExecInPod and writes the fake stdout to the writer
+func (f *FakePodRunner) StreamFromPod(ctx context.Context, podName types.NamespacedName,
+	contName string, out io.Writer, command ...string) (stderr string, err error) {
+	sout, serr, err := f.ExecInPod(ctx, podName, contName, command...)
+	if err != nil {
+		return serr, err
+	}
+	if _, err := io.WriteString(out, sout); err != nil {
+		return serr, err
+	}
+	return serr, nil
+}
+
+// StreamToPod calls ExecInPod and saves what was read from the reader in the
+// command history
+func (f *FakePodRunner) StreamToPod(ctx context.Context, podName types.NamespacedName,
+	contName string, in io.Reader, command ...string) (stdout, stderr string, err error) {
+	stdin, err := io.ReadAll(in)
+	if err != nil {
+		return "", "", err
+	}
+	sout, serr, err := f.ExecInPod(ctx, podName, contName, command...)
+	f.Histories[len(f.Histories)-1].Stdin = string(stdin)
+	return sout, serr, err
+}
+
 // DumpAdmintoolsConf will log relenvant portions of the admintools.conf for debug purposes.
 func (f *FakePodRunner) DumpAdmintoolsConf(_ context.Context, _ types.NamespacedName) {
 	// no-op
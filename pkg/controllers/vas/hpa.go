@@ -16,6 +16,8 @@
 package vas
 
 import (
+	"fmt"
+
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
 )
 
@@ -78,6 +80,35 @@ func (ms *metricStatus) cmp(mt *autoscalingv2.MetricTarget) int {
 	return errorCmpResult
 }
 
+// ratio returns the metric's value divided by its target. The hpa multiplies
+// the current replica count by this to get the replica count it wants. False
+// is returned if the value and the target are not of the same type.
+func (ms *metricStatus) ratio(mt *autoscalingv2.MetricTarget) (float64, bool) {
+	if ms.status.AverageUtilization != nil && mt.AverageUtilization != nil && *mt.AverageUtilization > 0 {
+		return float64(*ms.status.AverageUtilization) / float64(*mt.AverageUtilization), true
+	}
+	if ms.status.Value != nil && mt.Value != nil && !mt.Value.IsZero() {
+		return ms.status.Value.AsApproximateFloat64() / mt.Value.AsApproximateFloat64(), true
+	}
+	if ms.status.AverageValue != nil && mt.AverageValue != nil && !mt.AverageValue.IsZero() {
+		return ms.status.AverageValue.AsApproximateFloat64() / mt.AverageValue.AsApproximateFloat64(), true
+	}
+	return 0, false
+}
+
+// String returns the metric's value in the form the hpa reports it
+func (ms *metricStatus) String() string {
+	switch {
+	case ms.status.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *ms.status.AverageUtilization)
+	case ms.status.Value != nil:
+		return ms.status.Value.String()
+	case ms.status.AverageValue != nil:
+		return ms.status.AverageValue.String()
+	}
+	return ""
+}
+
 func (ms *metricStatus) cmpAverageUtilization(au int32) int {
 	if *ms.status.AverageUtilization > au {
 		return greaterThanCmpResult
@@ -87,7 +87,8 @@ func (o *ObjReconciler) reconcileHpa(ctx context.Context) error {
 	}
 	if o.Vas.HasScaleInThreshold() {
 		// We keep the current value because it will be changed elsewhere.
-		*expHpa.Spec.MinReplicas = *curHpa.Spec.MinReplicas
+		// The scaling floor still applies on top of it.
+		expHpa.Spec.MinReplicas = o.Vas.ApplyScalingFloor(curHpa.Spec.MinReplicas, expHpa.Spec.MaxReplicas)
 	}
 	return o.updateWorkload(ctx, curHpa, expHpa)
 }
@@ -0,0 +1,316 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/go-logr/logr"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	"github.com/vertica/vertica-kubernetes/pkg/events"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/vasstatus"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	hoursPerWeek = 7 * 24
+
+	// The key in the configmap that holds the load profile
+	loadProfileKey = "profile.json"
+
+	// Defaults for when the predictive spec leaves a field unset
+	defaultPredictiveLeadTimeMinutes  = 15
+	defaultPredictiveMinWeeks         = 2
+	defaultPredictiveSmoothingPercent = 30
+)
+
+// PredictiveReconciler will learn the load for each hour of the week and
+// set a forecast that the hpa cannot scale below
+type PredictiveReconciler struct {
+	VRec *VerticaAutoscalerReconciler
+	Vas  *vapi.VerticaAutoscaler
+	Log  logr.Logger
+	// Returns the current time. Tests override this.
+	Now func() time.Time
+}
+
+func MakePredictiveReconciler(v *VerticaAutoscalerReconciler, vas *vapi.VerticaAutoscaler,
+	log logr.Logger) controllers.ReconcileActor {
+	return &PredictiveReconciler{
+		VRec: v,
+		Vas:  vas,
+		Log:  log.WithName("PredictiveReconciler"),
+		Now:  time.Now,
+	}
+}
+
+// loadProfile is the demand, in pods, for each hour of the week. It is stored
+// as json in a configmap so that it survives operator restarts.
+type loadProfile struct {
+	Hours [hoursPerWeek]loadProfileHour `json:"hours"`
+}
+
+// loadProfileHour is the demand for a single hour of the week
+type loadProfileHour struct {
+	// The peak demand of this hour, averaged over the previous weeks
+	Mean float64 `json:"mean,omitempty"`
+	// The number of weeks that went into the mean
+	Weeks int32 `json:"weeks,omitempty"`
+	// The peak demand seen so far in the latest occurrence of this hour
+	Peak float64 `json:"peak,omitempty"`
+	// The start of the latest occurrence of this hour, in unix seconds
+	PeakHour int64 `json:"peakHour,omitempty"`
+}
+
+// Reconcile will add the current demand to the load profile and forecast
+// the demand at the end of the lead time. The forecast is a floor for the
+// minReplicas of the hpa, so this must run before the hpa is updated. Changes
+// in the hpa status trigger a reconcile, so the forecast is kept current.
+func (p *PredictiveReconciler) Reconcile(ctx context.Context, req *ctrl.Request) (ctrl.Result, error) {
+	if !p.Vas.IsPredictiveEnabled() {
+		if p.Vas.Status.Forecast == nil {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, p.setForecast(ctx, req, nil)
+	}
+
+	curHpa := &autoscalingv2.HorizontalPodAutoscaler{}
+	if err := p.VRec.Client.Get(ctx, names.GenHPAName(p.Vas), curHpa); err != nil {
+		if kerrors.IsNotFound(err) {
+			// Nothing to learn from until the hpa is created
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	pred := p.Vas.Spec.CustomAutoscaler.Predictive
+	loc := time.UTC
+	if pred.TimeZone != "" {
+		var err error
+		if loc, err = time.LoadLocation(pred.TimeZone); err != nil {
+			// The webhook should prevent this
+			return ctrl.Result{}, err
+		}
+	}
+	now := p.Now().In(loc)
+
+	cm, profile, err := p.fetchLoadProfile(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	demand, hasDemand := p.getCurrentDemand(curHpa)
+	if hasDemand && profile.record(now, demand, getSmoothingPercent(pred)) {
+		if err := p.saveLoadProfile(ctx, cm, profile); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	forecast := p.makeForecast(profile, now, int32(math.Ceil(demand)), curHpa)
+	if p.Vas.Status.Forecast != nil && *p.Vas.Status.Forecast == *forecast {
+		return ctrl.Result{}, nil
+	}
+	if forecast.PredictedSize > curHpa.Status.CurrentReplicas &&
+		(p.Vas.Status.Forecast == nil || p.Vas.Status.Forecast.PredictedSize != forecast.PredictedSize) {
+		p.VRec.Eventf(p.Vas, corev1.EventTypeNormal, events.PredictiveScaling, "%s", forecast.Reason)
+	}
+	return ctrl.Result{}, p.setForecast(ctx, req, forecast)
+}
+
+// getCurrentDemand returns the number of pods the current load needs. Like
+// the hpa, it is the current replica count scaled by the ratio between the
+// metric and its target, and the largest metric wins. False is returned if
+// the hpa hasn't reported any metric yet.
+func (p *PredictiveReconciler) getCurrentDemand(curHpa *autoscalingv2.HorizontalPodAutoscaler) (float64, bool) {
+	replicas := curHpa.Status.CurrentReplicas
+	if replicas == 0 {
+		replicas = p.Vas.Status.CurrentSize
+	}
+	mMap := p.Vas.GetMetricMap()
+	demand, found := 0.0, false
+	for i := range curHpa.Status.CurrentMetrics {
+		mStatus := getCurrentMetricStatus(&curHpa.Status.CurrentMetrics[i])
+		if mStatus == nil {
+			continue
+		}
+		md, ok := mMap[mStatus.name]
+		if !ok {
+			continue
+		}
+		mt := vapi.GetMetricTarget(&md.Metric)
+		if mt == nil {
+			continue
+		}
+		ratio, ok := mStatus.ratio(mt)
+		if !ok {
+			continue
+		}
+		demand = max(demand, ratio*float64(replicas))
+		found = true
+	}
+	return demand, found
+}
+
+// makeForecast returns the forecast for the hour we will be in once the lead
+// time has passed, along with the reasoning for the decision
+func (p *PredictiveReconciler) makeForecast(profile *loadProfile, now time.Time, currentDemand int32,
+	curHpa *autoscalingv2.HorizontalPodAutoscaler) *vapi.PredictiveForecast {
+	pred := p.Vas.Spec.CustomAutoscaler.Predictive
+	leadTime := time.Duration(getLeadTimeMinutes(pred)) * time.Minute
+	target := now.Add(leadTime)
+	forecastTime := time.Date(target.Year(), target.Month(), target.Day(), target.Hour(), 0, 0, 0, target.Location())
+	forecast := &vapi.PredictiveForecast{
+		ForecastTime:  metav1.NewTime(forecastTime),
+		CurrentDemand: currentDemand,
+	}
+	slotName := forecastTime.Format("Mon 15:04")
+	hour := &profile.Hours[hourOfWeek(forecastTime)]
+	minWeeks := getMinWeeks(pred)
+	if hour.Weeks < minWeeks {
+		forecast.Reason = fmt.Sprintf("Not enough history for %s: %d of %d weeks", slotName, hour.Weeks, minWeeks)
+		return forecast
+	}
+
+	predicted := int32(math.Ceil(hour.Mean))
+	if hpaMin := p.Vas.Spec.CustomAutoscaler.Hpa.MinReplicas; hpaMin != nil {
+		predicted = max(predicted, *hpaMin)
+	}
+	predicted = min(predicted, p.Vas.Spec.CustomAutoscaler.Hpa.MaxReplicas)
+	forecast.PredictedSize = predicted
+	if predicted > curHpa.Status.CurrentReplicas {
+		forecast.Reason = fmt.Sprintf("Pre-scaling from %d to %d pods for the demand expected at %s",
+			curHpa.Status.CurrentReplicas, predicted, slotName)
+	} else {
+		forecast.Reason = fmt.Sprintf("The %d pods expected at %s are covered by the current %d pods",
+			predicted, slotName, curHpa.Status.CurrentReplicas)
+	}
+	return forecast
+}
+
+// fetchLoadProfile returns the configmap that stores the load profile and the
+// profile itself. The configmap is created if it doesn't exist yet.
+func (p *PredictiveReconciler) fetchLoadProfile(ctx context.Context) (*corev1.ConfigMap, *loadProfile, error) {
+	nm := names.GenLoadProfileConfigMapName(p.Vas)
+	cm := &corev1.ConfigMap{}
+	profile := &loadProfile{}
+	err := p.VRec.Client.Get(ctx, nm, cm)
+	if kerrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      nm.Name,
+				Namespace: nm.Namespace,
+			},
+		}
+		p.Log.Info("Creating configmap for the load profile", "Name", nm.Name)
+		return cm, profile, createObject(ctx, cm, p.VRec.Client, p.Vas)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if data, ok := cm.Data[loadProfileKey]; ok {
+		if err := json.Unmarshal([]byte(data), profile); err != nil {
+			// Start over rather than getting stuck on a bad profile
+			p.Log.Info("The load profile could not be parsed. Starting a new one", "err", err.Error())
+			profile = &loadProfile{}
+		}
+	}
+	return cm, profile, nil
+}
+
+// saveLoadProfile writes the load profile to its configmap
+func (p *PredictiveReconciler) saveLoadProfile(ctx context.Context, cm *corev1.ConfigMap, profile *loadProfile) error {
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return err
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[loadProfileKey] = string(data)
+	return p.VRec.Client.Update(ctx, cm)
+}
+
+// setForecast updates the status and keeps the in-memory copy in sync since
+// the forecast is used when the hpa is built
+func (p *PredictiveReconciler) setForecast(ctx context.Context, req *ctrl.Request, forecast *vapi.PredictiveForecast) error {
+	if err := vasstatus.SetForecast(ctx, p.VRec.Client, p.Log, req, forecast); err != nil {
+		return err
+	}
+	p.Vas.Status.Forecast = forecast
+	return nil
+}
+
+// record adds the demand seen at the given time to the profile. Each hour
+// keeps its peak until the next week's occurrence begins, at which point the
+// peak is averaged into the mean. True is returned if the profile changed.
+func (l *loadProfile) record(now time.Time, demand float64, smoothingPercent int32) bool {
+	start := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), 0, 0, 0, now.Location()).Unix()
+	hour := &l.Hours[hourOfWeek(now)]
+	if hour.PeakHour == start {
+		if demand <= hour.Peak {
+			return false
+		}
+		hour.Peak = demand
+		return true
+	}
+	if hour.PeakHour != 0 {
+		if hour.Weeks == 0 {
+			hour.Mean = hour.Peak
+		} else {
+			weight := float64(smoothingPercent) / 100
+			hour.Mean = hour.Mean*(1-weight) + hour.Peak*weight
+		}
+		hour.Weeks++
+	}
+	hour.Peak = demand
+	hour.PeakHour = start
+	return true
+}
+
+// hourOfWeek returns the index of the hour in the week, starting on Sunday
+func hourOfWeek(t time.Time) int {
+	return int(t.Weekday())*24 + t.Hour()
+}
+
+func getLeadTimeMinutes(pred *vapi.PredictiveSpec) int32 {
+	if pred.LeadTimeMinutes == 0 {
+		return defaultPredictiveLeadTimeMinutes
+	}
+	return pred.LeadTimeMinutes
+}
+
+func getMinWeeks(pred *vapi.PredictiveSpec) int32 {
+	if pred.MinWeeks == 0 {
+		return defaultPredictiveMinWeeks
+	}
+	return pred.MinWeeks
+}
+
+func getSmoothingPercent(pred *vapi.PredictiveSpec) int32 {
+	if pred.SmoothingPercent == 0 {
+		return defaultPredictiveSmoothingPercent
+	}
+	return pred.SmoothingPercent
+}
@@ -0,0 +1,118 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vas
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/v1beta1_test"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("predictive_reconcile", func() {
+	ctx := context.Background()
+
+	// A Monday, 10 minutes before the 9am hour
+	now := time.Date(2025, 6, 2, 8, 50, 0, 0, time.UTC)
+	nextHour := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	It("should fold the peak of each hour into the mean once a week", func() {
+		profile := &loadProfile{}
+		Expect(profile.record(now, 4, 30)).Should(BeTrue())
+		Expect(profile.record(now.Add(time.Minute), 3, 30)).Should(BeFalse())
+		Expect(profile.record(now.Add(time.Minute), 6, 30)).Should(BeTrue())
+		hour := &profile.Hours[hourOfWeek(now)]
+		Expect(hour.Peak).Should(Equal(6.0))
+		Expect(hour.Weeks).Should(Equal(int32(0)))
+
+		Expect(profile.record(now.AddDate(0, 0, 7), 2, 30)).Should(BeTrue())
+		Expect(hour.Mean).Should(Equal(6.0))
+		Expect(hour.Weeks).Should(Equal(int32(1)))
+		Expect(profile.record(now.AddDate(0, 0, 14), 2, 30)).Should(BeTrue())
+		Expect(hour.Mean).Should(BeNumerically("~", 4.8, 0.001))
+		Expect(hour.Weeks).Should(Equal(int32(2)))
+		Expect(hourOfWeek(now)).Should(Equal(24 + 8))
+	})
+
+	It("should learn from the hpa metrics and pre-scale ahead of the forecast", func() {
+		vas := vapi.MakeVASWithMetrics()
+		vas.Spec.TargetSize = 3
+		vas.Spec.CustomAutoscaler.Predictive = &vapi.PredictiveSpec{
+			LeadTimeMinutes: 15,
+			MinWeeks:        2,
+		}
+		v1beta1_test.CreateVAS(ctx, k8sClient, vas)
+		defer v1beta1_test.DeleteVAS(ctx, k8sClient, vas)
+
+		Expect(MakeObjReconciler(vasRec, vas, logger).Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		defer v1beta1_test.DeleteHPA(ctx, k8sClient, vas)
+		hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+		Expect(k8sClient.Get(ctx, names.GenHPAName(vas), hpa)).Should(Succeed())
+		// Twice the cpu target on 3 pods is a demand of 6 pods
+		curCPU := int32(160)
+		hpa.Status.CurrentReplicas = 3
+		hpa.Status.CurrentMetrics = []autoscalingv2.MetricStatus{
+			{
+				Type: autoscalingv2.ResourceMetricSourceType,
+				Resource: &autoscalingv2.ResourceMetricStatus{
+					Name:    corev1.ResourceCPU,
+					Current: autoscalingv2.MetricValueStatus{AverageUtilization: &curCPU},
+				},
+			},
+		}
+		Expect(k8sClient.Status().Update(ctx, hpa)).Should(Succeed())
+
+		runPredictive := func() {
+			act := MakePredictiveReconciler(vasRec, vas, logger)
+			r := act.(*PredictiveReconciler)
+			r.Now = func() time.Time { return now }
+			req := ctrl.Request{NamespacedName: vapi.MakeVASName()}
+			Expect(r.Reconcile(ctx, &req)).Should(Equal(ctrl.Result{}))
+		}
+		runPredictive()
+		cm := &corev1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, names.GenLoadProfileConfigMapName(vas), cm)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, cm)).Should(Succeed()) }()
+		profile := &loadProfile{}
+		Expect(json.Unmarshal([]byte(cm.Data[loadProfileKey]), profile)).Should(Succeed())
+		Expect(profile.Hours[hourOfWeek(now)].Peak).Should(Equal(6.0))
+		Expect(vas.Status.Forecast).ShouldNot(BeNil())
+		Expect(vas.Status.Forecast.CurrentDemand).Should(Equal(int32(6)))
+		Expect(vas.Status.Forecast.PredictedSize).Should(Equal(int32(0)))
+		Expect(vas.Status.Forecast.Reason).Should(ContainSubstring("Not enough history"))
+
+		// Give the next hour two weeks of history
+		profile.Hours[hourOfWeek(nextHour)] = loadProfileHour{Mean: 5.2, Weeks: 2}
+		data, err := json.Marshal(profile)
+		Expect(err).Should(Succeed())
+		cm.Data[loadProfileKey] = string(data)
+		Expect(k8sClient.Update(ctx, cm)).Should(Succeed())
+
+		runPredictive()
+		Expect(vas.Status.Forecast.ForecastTime.Time.Equal(nextHour)).Should(BeTrue())
+		Expect(vas.Status.Forecast.PredictedSize).Should(Equal(int32(6)))
+		Expect(vas.Status.Forecast.Reason).Should(ContainSubstring("Pre-scaling from 3 to 6 pods"))
+		Expect(*vas.GetMinReplicas()).Should(Equal(int32(6)))
+	})
+})
@@ -44,7 +44,7 @@ func MakeScaleinReconciler(v *VerticaAutoscalerReconciler, vas *vapi.VerticaAuto
 // Reconcile will handle updating the hpa based on the metrics current value.
 // Only metrics with a scale in threshold set are taken into account.
 func (s *ScaleinReconciler) Reconcile(ctx context.Context, req *ctrl.Request) (ctrl.Result, error) {
-	if !s.Vas.HasScaleInThreshold() {
+	if !s.Vas.HasScaleInThreshold() || s.Vas.IsScalingPolicyEnabled() {
 		return ctrl.Result{}, nil
 	}
 	nm := names.GenHPAName(s.Vas)
@@ -82,6 +82,8 @@ func (s *ScaleinReconciler) Reconcile(ctx context.Context, req *ctrl.Request) (c
 			break
 		}
 	}
+	// Never scale in below the size required by the schedule or the forecast
+	newMinReplicas = *s.Vas.ApplyScalingFloor(&newMinReplicas, curHpa.Spec.MaxReplicas)
 	if *curHpa.Spec.MinReplicas != newMinReplicas {
 		*curHpa.Spec.MinReplicas = newMinReplicas
 		return ctrl.Result{}, s.VRec.Client.Update(ctx, curHpa)
@@ -0,0 +1,283 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vas
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/go-logr/logr"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	"github.com/vertica/vertica-kubernetes/pkg/events"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/vasstatus"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	// The weighted ratio must differ from 1 by more than this before we
+	// scale. This is the same tolerance the hpa uses.
+	scalingPolicyTolerance = 0.1
+
+	// The weight of a metric that is not listed in the policy
+	defaultMetricWeight = 1
+)
+
+// ScalingPolicyReconciler will combine the hpa metrics into a single scaling
+// decision. The hpa is pinned to that decision when it is built.
+type ScalingPolicyReconciler struct {
+	VRec *VerticaAutoscalerReconciler
+	Vas  *vapi.VerticaAutoscaler
+	Log  logr.Logger
+	// When true, this actor only requeues the vas so that the policy is
+	// evaluated again once the scale in conditions have been met for long
+	// enough. Since a requeue stops the reconcile, this must be one of the
+	// last actors.
+	RequeueOnly bool
+	// Returns the current time. Tests override this.
+	Now func() time.Time
+}
+
+func MakeScalingPolicyReconciler(v *VerticaAutoscalerReconciler, vas *vapi.VerticaAutoscaler,
+	log logr.Logger, requeueOnly bool) controllers.ReconcileActor {
+	return &ScalingPolicyReconciler{
+		VRec:        v,
+		Vas:         vas,
+		Log:         log.WithName("ScalingPolicyReconciler"),
+		RequeueOnly: requeueOnly,
+		Now:         time.Now,
+	}
+}
+
+// Reconcile will evaluate the scaling policy from the current metrics of the
+// hpa and record the result in the status. This must run before the hpa is
+// updated since the hpa is pinned to the size in the status.
+func (s *ScalingPolicyReconciler) Reconcile(ctx context.Context, req *ctrl.Request) (ctrl.Result, error) {
+	if !s.Vas.IsScalingPolicyEnabled() {
+		if s.RequeueOnly || s.Vas.Status.PolicyEvaluation == nil {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, s.setPolicyEvaluation(ctx, req, nil)
+	}
+	if s.RequeueOnly {
+		return ctrl.Result{RequeueAfter: s.getScaleInWait()}, nil
+	}
+
+	curHpa := &autoscalingv2.HorizontalPodAutoscaler{}
+	if err := s.VRec.Client.Get(ctx, names.GenHPAName(s.Vas), curHpa); err != nil {
+		if kerrors.IsNotFound(err) {
+			// Nothing to evaluate until the hpa is created
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	evaluation := s.evaluate(curHpa)
+	if evaluation == nil {
+		s.Log.Info("The hpa hasn't reported any metric yet. Skipping the scaling policy")
+		return ctrl.Result{}, nil
+	}
+	prev := s.Vas.Status.PolicyEvaluation
+	if prev != nil {
+		// Writing the status triggers another reconcile, so we only do it
+		// when something other than the time of the evaluation changed.
+		unchanged := evaluation.DeepCopy()
+		unchanged.EvaluationTime = prev.EvaluationTime
+		if reflect.DeepEqual(unchanged, prev) {
+			return ctrl.Result{}, nil
+		}
+	}
+	if prev == nil || prev.DesiredSize != evaluation.DesiredSize {
+		s.VRec.Eventf(s.Vas, corev1.EventTypeNormal, events.ScalingPolicyDecision, "%s", evaluation.Reason)
+	}
+	return ctrl.Result{}, s.setPolicyEvaluation(ctx, req, evaluation)
+}
+
+// evaluate returns the pod count the policy asks for along with the inputs
+// that went into it. Nil is returned if the hpa hasn't reported any metric
+// that can be evaluated.
+func (s *ScalingPolicyReconciler) evaluate(curHpa *autoscalingv2.HorizontalPodAutoscaler) *vapi.ScalingPolicyEvaluation {
+	policy := s.Vas.Spec.CustomAutoscaler.ScalingPolicy
+	now := metav1.NewTime(s.Now())
+	currentSize := curHpa.Status.CurrentReplicas
+	if currentSize == 0 {
+		currentSize = s.Vas.Status.CurrentSize
+	}
+	currentSize = max(currentSize, 1)
+
+	weights := make(map[string]int32, len(policy.Weights))
+	for i := range policy.Weights {
+		weights[policy.Weights[i].Metric] = policy.Weights[i].Weight
+	}
+	conditions := map[string]*vapi.ScaleInCondition{}
+	if policy.ScaleIn != nil {
+		for i := range policy.ScaleIn.Conditions {
+			conditions[policy.ScaleIn.Conditions[i].Metric] = &policy.ScaleIn.Conditions[i]
+		}
+	}
+
+	evaluation := &vapi.ScalingPolicyEvaluation{EvaluationTime: now}
+	mMap := s.Vas.GetMetricMap()
+	weightedSum, weightSum, conditionsMet := 0.0, 0.0, 0
+	for i := range curHpa.Status.CurrentMetrics {
+		mStatus := getCurrentMetricStatus(&curHpa.Status.CurrentMetrics[i])
+		if mStatus == nil {
+			continue
+		}
+		md, ok := mMap[mStatus.name]
+		if !ok {
+			continue
+		}
+		mt := vapi.GetMetricTarget(&md.Metric)
+		if mt == nil {
+			continue
+		}
+		ratio, ok := mStatus.ratio(mt)
+		if !ok {
+			continue
+		}
+		weight, ok := weights[mStatus.name]
+		if !ok {
+			weight = defaultMetricWeight
+		}
+		weightedSum += float64(weight) * ratio
+		weightSum += float64(weight)
+		me := vapi.PolicyMetricEvaluation{
+			Name:         mStatus.name,
+			Value:        mStatus.String(),
+			Weight:       weight,
+			RatioPercent: int32(math.Round(ratio * 100)),
+		}
+		if cond, found := conditions[mStatus.name]; found {
+			met := isScaleInConditionMet(mStatus, cond)
+			if met {
+				conditionsMet++
+			}
+			me.ScaleInConditionMet = &met
+		}
+		evaluation.Metrics = append(evaluation.Metrics, me)
+	}
+	if len(evaluation.Metrics) == 0 {
+		return nil
+	}
+
+	weightedRatio := 1.0
+	if weightSum > 0 {
+		weightedRatio = weightedSum / weightSum
+	}
+	evaluation.WeightedRatioPercent = int32(math.Round(weightedRatio * 100))
+	desired := currentSize
+	if math.Abs(weightedRatio-1.0) > scalingPolicyTolerance {
+		desired = int32(math.Ceil(weightedRatio * float64(currentSize)))
+	}
+	evaluation.Reason = fmt.Sprintf("The weighted metric ratio is %d%%", evaluation.WeightedRatioPercent)
+
+	if policy.ScaleIn != nil {
+		s.setScaleInConditionsMet(evaluation, conditionsMet, now)
+		if desired < currentSize && !s.hasScaleInConditionsHeld(evaluation, now.Time) {
+			desired = currentSize
+			evaluation.Reason = fmt.Sprintf("%s, but the scale in conditions have not been met for %ds",
+				evaluation.Reason, policy.ScaleIn.DurationSeconds)
+		}
+	}
+
+	hpa := s.Vas.Spec.CustomAutoscaler.Hpa
+	if hpa.MinReplicas != nil {
+		desired = max(desired, *hpa.MinReplicas)
+	}
+	desired = min(*s.Vas.ApplyScalingFloor(&desired, hpa.MaxReplicas), hpa.MaxReplicas)
+	evaluation.DesiredSize = desired
+	switch {
+	case desired > currentSize:
+		evaluation.Reason = fmt.Sprintf("%s. Scaling out from %d to %d pods", evaluation.Reason, currentSize, desired)
+	case desired < currentSize:
+		evaluation.Reason = fmt.Sprintf("%s. Scaling in from %d to %d pods", evaluation.Reason, currentSize, desired)
+	default:
+		evaluation.Reason = fmt.Sprintf("%s. Keeping %d pods", evaluation.Reason, currentSize)
+	}
+	return evaluation
+}
+
+// setScaleInConditionsMet combines the scale in conditions with the operator
+// of the rule. The time they started to be met carries over from the previous
+// evaluation so that we know how long they have been met for.
+func (s *ScalingPolicyReconciler) setScaleInConditionsMet(evaluation *vapi.ScalingPolicyEvaluation,
+	conditionsMet int, now metav1.Time) {
+	rule := s.Vas.Spec.CustomAutoscaler.ScalingPolicy.ScaleIn
+	if rule.Operator == vapi.ScaleInOperatorOr {
+		evaluation.ScaleInConditionsMet = conditionsMet > 0
+	} else {
+		// A condition whose metric isn't reported is not met
+		evaluation.ScaleInConditionsMet = conditionsMet == len(rule.Conditions)
+	}
+	if !evaluation.ScaleInConditionsMet {
+		return
+	}
+	prev := s.Vas.Status.PolicyEvaluation
+	if prev != nil && prev.ScaleInConditionsMet && prev.ScaleInConditionsMetSince != nil {
+		evaluation.ScaleInConditionsMetSince = prev.ScaleInConditionsMetSince.DeepCopy()
+		return
+	}
+	evaluation.ScaleInConditionsMetSince = &now
+}
+
+// hasScaleInConditionsHeld returns true if the scale in conditions have been
+// met for the duration of the rule
+func (s *ScalingPolicyReconciler) hasScaleInConditionsHeld(evaluation *vapi.ScalingPolicyEvaluation, now time.Time) bool {
+	if !evaluation.ScaleInConditionsMet || evaluation.ScaleInConditionsMetSince == nil {
+		return false
+	}
+	duration := time.Duration(s.Vas.Spec.CustomAutoscaler.ScalingPolicy.ScaleIn.DurationSeconds) * time.Second
+	return now.Sub(evaluation.ScaleInConditionsMetSince.Time) >= duration
+}
+
+// getScaleInWait returns how long until the scale in conditions have been met
+// for the duration of the rule. 0 is returned if there is nothing to wait for.
+func (s *ScalingPolicyReconciler) getScaleInWait() time.Duration {
+	evaluation := s.Vas.Status.PolicyEvaluation
+	rule := s.Vas.Spec.CustomAutoscaler.ScalingPolicy.ScaleIn
+	if rule == nil || evaluation == nil || !evaluation.ScaleInConditionsMet || evaluation.ScaleInConditionsMetSince == nil {
+		return 0
+	}
+	duration := time.Duration(rule.DurationSeconds) * time.Second
+	wait := evaluation.ScaleInConditionsMetSince.Add(duration).Sub(s.Now())
+	return max(wait, 0)
+}
+
+// setPolicyEvaluation updates the status and keeps the in-memory copy in sync
+// since the evaluation is used when the hpa is built
+func (s *ScalingPolicyReconciler) setPolicyEvaluation(ctx context.Context, req *ctrl.Request,
+	evaluation *vapi.ScalingPolicyEvaluation) error {
+	if err := vasstatus.SetPolicyEvaluation(ctx, s.VRec.Client, s.Log, req, evaluation); err != nil {
+		return err
+	}
+	s.Vas.Status.PolicyEvaluation = evaluation
+	return nil
+}
+
+// isScaleInConditionMet returns true if the metric is at or below the
+// threshold of the condition
+func isScaleInConditionMet(mStatus *metricStatus, cond *vapi.ScaleInCondition) bool {
+	cmpResult := mStatus.cmp(&cond.Threshold)
+	return cmpResult != errorCmpResult && cmpResult <= equalToCmpResult
+}
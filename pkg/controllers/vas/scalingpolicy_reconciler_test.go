@@ -0,0 +1,157 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vas
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/v1beta1_test"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("scalingpolicy_reconcile", func() {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 2, 8, 50, 0, 0, time.UTC)
+
+	It("should weigh the metrics and hold scale in until the conditions are met long enough", func() {
+		vas := vapi.MakeVASWithMetrics()
+		vas.Spec.TargetSize = 4
+		vas.Spec.CustomAutoscaler.Hpa.Metrics = append(vas.Spec.CustomAutoscaler.Hpa.Metrics, vapi.MetricDefinition{
+			Metric: autoscalingv2.MetricSpec{
+				Type: autoscalingv2.PodsMetricSourceType,
+				Pods: &autoscalingv2.PodsMetricSource{
+					Metric: autoscalingv2.MetricIdentifier{Name: "queue_depth"},
+					Target: autoscalingv2.MetricTarget{
+						Type:         autoscalingv2.AverageValueMetricType,
+						AverageValue: resource.NewQuantity(10, resource.DecimalSI),
+					},
+				},
+			},
+		})
+		lowCPU := int32(30)
+		vas.Spec.CustomAutoscaler.ScalingPolicy = &vapi.ScalingPolicySpec{
+			Weights: []vapi.MetricWeight{{Metric: "queue_depth", Weight: 3}},
+			ScaleIn: &vapi.ScaleInRule{
+				Operator: vapi.ScaleInOperatorAnd,
+				Conditions: []vapi.ScaleInCondition{
+					{
+						Metric: "cpu",
+						Threshold: autoscalingv2.MetricTarget{
+							Type:               autoscalingv2.UtilizationMetricType,
+							AverageUtilization: &lowCPU,
+						},
+					},
+					{
+						Metric: "queue_depth",
+						Threshold: autoscalingv2.MetricTarget{
+							Type:         autoscalingv2.AverageValueMetricType,
+							AverageValue: resource.NewQuantity(0, resource.DecimalSI),
+						},
+					},
+				},
+				DurationSeconds: 600,
+			},
+		}
+		v1beta1_test.CreateVAS(ctx, k8sClient, vas)
+		defer v1beta1_test.DeleteVAS(ctx, k8sClient, vas)
+
+		// The hpa is pinned to the targetSize until the policy is evaluated
+		Expect(MakeObjReconciler(vasRec, vas, logger).Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		defer v1beta1_test.DeleteHPA(ctx, k8sClient, vas)
+		hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+		nm := names.GenHPAName(vas)
+		Expect(k8sClient.Get(ctx, nm, hpa)).Should(Succeed())
+		Expect(*hpa.Spec.MinReplicas).Should(Equal(int32(4)))
+		Expect(hpa.Spec.MaxReplicas).Should(Equal(int32(4)))
+
+		setMetrics := func(cpu int32, queueDepth int64) {
+			Expect(k8sClient.Get(ctx, nm, hpa)).Should(Succeed())
+			hpa.Status.CurrentReplicas = 4
+			hpa.Status.CurrentMetrics = []autoscalingv2.MetricStatus{
+				{
+					Type: autoscalingv2.ResourceMetricSourceType,
+					Resource: &autoscalingv2.ResourceMetricStatus{
+						Name:    corev1.ResourceCPU,
+						Current: autoscalingv2.MetricValueStatus{AverageUtilization: &cpu},
+					},
+				},
+				{
+					Type: autoscalingv2.PodsMetricSourceType,
+					Pods: &autoscalingv2.PodsMetricStatus{
+						Metric:  autoscalingv2.MetricIdentifier{Name: "queue_depth"},
+						Current: autoscalingv2.MetricValueStatus{AverageValue: resource.NewQuantity(queueDepth, resource.DecimalSI)},
+					},
+				},
+			}
+			Expect(k8sClient.Status().Update(ctx, hpa)).Should(Succeed())
+		}
+		runPolicy := func(at time.Time, requeueOnly bool) ctrl.Result {
+			act := MakeScalingPolicyReconciler(vasRec, vas, logger, requeueOnly)
+			r := act.(*ScalingPolicyReconciler)
+			r.Now = func() time.Time { return at }
+			req := ctrl.Request{NamespacedName: vapi.MakeVASName()}
+			res, err := r.Reconcile(ctx, &req)
+			Expect(err).Should(Succeed())
+			return res
+		}
+
+		// Twice the cpu target and the queue depth at its target. The queue
+		// depth weighs 3 times more, so the weighted ratio is 125%.
+		setMetrics(160, 10)
+		runPolicy(now, false)
+		Expect(vas.Status.PolicyEvaluation).ShouldNot(BeNil())
+		Expect(vas.Status.PolicyEvaluation.WeightedRatioPercent).Should(Equal(int32(125)))
+		Expect(vas.Status.PolicyEvaluation.DesiredSize).Should(Equal(int32(5)))
+		Expect(vas.Status.PolicyEvaluation.ScaleInConditionsMet).Should(BeFalse())
+		Expect(vas.Status.PolicyEvaluation.Metrics).Should(HaveLen(2))
+		Expect(vas.Status.PolicyEvaluation.Metrics[0].RatioPercent).Should(Equal(int32(200)))
+		Expect(vas.Status.PolicyEvaluation.Metrics[1].Weight).Should(Equal(int32(3)))
+		Expect(MakeObjReconciler(vasRec, vas, logger).Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(k8sClient.Get(ctx, nm, hpa)).Should(Succeed())
+		Expect(*hpa.Spec.MinReplicas).Should(Equal(int32(5)))
+		Expect(hpa.Spec.MaxReplicas).Should(Equal(int32(5)))
+
+		// Both scale in conditions are met, but not for long enough yet
+		setMetrics(20, 0)
+		runPolicy(now, false)
+		Expect(vas.Status.PolicyEvaluation.ScaleInConditionsMet).Should(BeTrue())
+		Expect(vas.Status.PolicyEvaluation.ScaleInConditionsMetSince.Time.Equal(now)).Should(BeTrue())
+		Expect(vas.Status.PolicyEvaluation.DesiredSize).Should(Equal(int32(4)))
+		Expect(runPolicy(now.Add(5*time.Minute), true)).Should(Equal(ctrl.Result{RequeueAfter: 5 * time.Minute}))
+
+		// Once they have held for the duration, we scale in to the minimum
+		runPolicy(now.Add(10*time.Minute), false)
+		Expect(vas.Status.PolicyEvaluation.ScaleInConditionsMetSince.Time.Equal(now)).Should(BeTrue())
+		Expect(vas.Status.PolicyEvaluation.DesiredSize).Should(Equal(int32(3)))
+		Expect(vas.Status.PolicyEvaluation.Reason).Should(ContainSubstring("Scaling in from 4 to 3 pods"))
+		Expect(runPolicy(now.Add(10*time.Minute), true)).Should(Equal(ctrl.Result{}))
+
+		// The queue is no longer empty, so the conditions start over
+		setMetrics(20, 1)
+		runPolicy(now.Add(11*time.Minute), false)
+		Expect(vas.Status.PolicyEvaluation.ScaleInConditionsMet).Should(BeFalse())
+		Expect(vas.Status.PolicyEvaluation.ScaleInConditionsMetSince).Should(BeNil())
+		Expect(vas.Status.PolicyEvaluation.DesiredSize).Should(Equal(int32(4)))
+	})
+})
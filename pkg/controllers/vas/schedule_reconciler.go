@@ -0,0 +1,250 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vas
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	"github.com/vertica/vertica-kubernetes/pkg/cron"
+	verrors "github.com/vertica/vertica-kubernetes/pkg/errors"
+	"github.com/vertica/vertica-kubernetes/pkg/events"
+	"github.com/vertica/vertica-kubernetes/pkg/vasstatus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ScheduleReconciler will set the targetSize from the active schedule window
+type ScheduleReconciler struct {
+	VRec *VerticaAutoscalerReconciler
+	Vas  *vapi.VerticaAutoscaler
+	Log  logr.Logger
+	// When true, this actor only requeues the vas for the next window
+	// boundary. Since a requeue stops the reconcile, this must be the last
+	// actor.
+	RequeueOnly bool
+	// Returns the current time. Tests override this.
+	Now func() time.Time
+}
+
+func MakeScheduleReconciler(v *VerticaAutoscalerReconciler, vas *vapi.VerticaAutoscaler,
+	log logr.Logger, requeueOnly bool) controllers.ReconcileActor {
+	return &ScheduleReconciler{
+		VRec:        v,
+		Vas:         vas,
+		Log:         log.WithName("ScheduleReconciler"),
+		RequeueOnly: requeueOnly,
+		Now:         time.Now,
+	}
+}
+
+// parsedWindow is a schedule window with its cron expressions parsed
+type parsedWindow struct {
+	*vapi.ScheduleWindow
+	start *cron.Schedule
+	end   *cron.Schedule
+}
+
+// Reconcile will apply the size of the active window to the targetSize
+func (s *ScheduleReconciler) Reconcile(ctx context.Context, req *ctrl.Request) (ctrl.Result, error) {
+	if !s.Vas.IsScheduleEnabled() {
+		if s.RequeueOnly || (s.Vas.Status.ScheduledSize == 0 && s.Vas.Status.ActiveScheduleWindow == "") {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, s.setScheduledSize(ctx, req, "", 0)
+	}
+
+	loc, windows, err := s.parseSchedule()
+	if err != nil {
+		// The webhook should prevent this. There is nothing to do until the
+		// schedule is corrected, so we don't requeue.
+		s.VRec.Eventf(s.Vas, corev1.EventTypeWarning, events.InvalidScalingSchedule,
+			"The scaling schedule is not valid: %s", err)
+		return ctrl.Result{}, nil
+	}
+	now := s.Now().In(loc)
+
+	if s.RequeueOnly {
+		next := nextWindowBoundary(windows, now)
+		if next.IsZero() {
+			return ctrl.Result{}, nil
+		}
+		s.Log.Info("Requeue at the next schedule window boundary", "time", next)
+		// A second is added so that the boundary has passed when we come back.
+		return ctrl.Result{RequeueAfter: next.Sub(now) + time.Second}, nil
+	}
+
+	activeName, scheduledSize, res, err := s.findScheduledSize(ctx, windows, now)
+	if verrors.IsReconcileAborted(res, err) {
+		return res, err
+	}
+	if s.Vas.Status.ActiveScheduleWindow != activeName || s.Vas.Status.ScheduledSize != scheduledSize {
+		if err := s.setScheduledSize(ctx, req, activeName, scheduledSize); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if scheduledSize == 0 {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{}, s.updateTargetSize(ctx, activeName, scheduledSize)
+}
+
+// parseSchedule returns the time zone of the schedule and its parsed windows
+func (s *ScheduleReconciler) parseSchedule() (*time.Location, []parsedWindow, error) {
+	sched := s.Vas.Spec.CustomAutoscaler.Schedule
+	loc := time.UTC
+	if sched.TimeZone != "" {
+		var err error
+		if loc, err = time.LoadLocation(sched.TimeZone); err != nil {
+			return nil, nil, err
+		}
+	}
+	windows := make([]parsedWindow, len(sched.Windows))
+	for i := range sched.Windows {
+		w := &sched.Windows[i]
+		start, err := cron.Parse(w.Start)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid start of window %s: %w", w.Name, err)
+		}
+		end, err := cron.Parse(w.End)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid end of window %s: %w", w.Name, err)
+		}
+		windows[i] = parsedWindow{ScheduleWindow: w, start: start, end: end}
+	}
+	return loc, windows, nil
+}
+
+// findScheduledSize returns the name of the active window and the size it
+// requires. If several windows are active, the largest one wins. If no window
+// is active, the default size is returned for the Schedule type and 0
+// otherwise.
+func (s *ScheduleReconciler) findScheduledSize(ctx context.Context, windows []parsedWindow,
+	now time.Time) (activeName string, scheduledSize int32, res ctrl.Result, err error) {
+	var scSize int32
+	for i := range windows {
+		if !isWindowActive(&windows[i], now) {
+			continue
+		}
+		size := windows[i].TargetSize
+		if windows[i].SubclusterCount > 0 {
+			if scSize == 0 {
+				if scSize, res, err = s.getSubclusterSize(ctx); verrors.IsReconcileAborted(res, err) {
+					return "", 0, res, err
+				}
+			}
+			size = windows[i].SubclusterCount * scSize
+		}
+		if size > scheduledSize {
+			activeName = windows[i].Name
+			scheduledSize = size
+		}
+	}
+	if activeName == "" && s.Vas.IsScheduleType() && s.Vas.Spec.CustomAutoscaler.Schedule.DefaultTargetSize != nil {
+		scheduledSize = *s.Vas.Spec.CustomAutoscaler.Schedule.DefaultTargetSize
+	}
+	return activeName, scheduledSize, ctrl.Result{}, nil
+}
+
+// getSubclusterSize returns the size of a single subcluster. This is used to
+// convert a subcluster count into a pod count.
+func (s *ScheduleReconciler) getSubclusterSize(ctx context.Context) (int32, ctrl.Result, error) {
+	if s.Vas.CanUseTemplate() {
+		return s.Vas.Spec.Template.Size, ctrl.Result{}, nil
+	}
+	vdb := &vapi.VerticaDB{}
+	if res, err := fetchVDB(ctx, s.VRec, s.Vas, vdb); verrors.IsReconcileAborted(res, err) {
+		return 0, res, err
+	}
+	subclusters, _ := vdb.FindSubclusterForServiceName(s.Vas.Spec.ServiceName)
+	if len(subclusters) == 0 {
+		s.VRec.Eventf(s.Vas, corev1.EventTypeWarning, events.SubclusterServiceNameNotFound,
+			"Could not find any subclusters with service name '%s'", s.Vas.Spec.ServiceName)
+		return 0, ctrl.Result{Requeue: true}, nil
+	}
+	return subclusters[0].Size, ctrl.Result{}, nil
+}
+
+// updateTargetSize sets the targetSize to the scheduled size. When the
+// schedule is used with metrics, it is only a floor, so we never lower the
+// targetSize.
+func (s *ScheduleReconciler) updateTargetSize(ctx context.Context, activeName string, scheduledSize int32) error {
+	if s.Vas.Spec.TargetSize == scheduledSize ||
+		(!s.Vas.IsScheduleType() && s.Vas.Spec.TargetSize > scheduledSize) {
+		return nil
+	}
+	oldSize := s.Vas.Spec.TargetSize
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		if err := s.VRec.Client.Get(ctx, client.ObjectKeyFromObject(s.Vas), s.Vas); err != nil {
+			return err
+		}
+		s.Vas.Spec.TargetSize = scheduledSize
+		return s.VRec.Client.Update(ctx, s.Vas)
+	})
+	if err != nil {
+		return err
+	}
+	if activeName == "" {
+		s.VRec.Eventf(s.Vas, corev1.EventTypeNormal, events.ScheduledScaling,
+			"Changed targetSize from %d to %d since no schedule window is active", oldSize, scheduledSize)
+	} else {
+		s.VRec.Eventf(s.Vas, corev1.EventTypeNormal, events.ScheduledScaling,
+			"Changed targetSize from %d to %d for schedule window '%s'", oldSize, scheduledSize, activeName)
+	}
+	return nil
+}
+
+// setScheduledSize updates the status and keeps the in-memory copy in sync
+// since later actors use it.
+func (s *ScheduleReconciler) setScheduledSize(ctx context.Context, req *ctrl.Request, activeName string, scheduledSize int32) error {
+	if err := vasstatus.SetScheduledSize(ctx, s.VRec.Client, s.Log, req, activeName, scheduledSize); err != nil {
+		return err
+	}
+	s.Vas.Status.ActiveScheduleWindow = activeName
+	s.Vas.Status.ScheduledSize = scheduledSize
+	return nil
+}
+
+// isWindowActive returns true if the window started more recently than it
+// ended.
+func isWindowActive(w *parsedWindow, now time.Time) bool {
+	lastStart := w.start.Prev(now)
+	if lastStart.IsZero() {
+		return false
+	}
+	lastEnd := w.end.Prev(now)
+	return lastEnd.IsZero() || lastStart.After(lastEnd)
+}
+
+// nextWindowBoundary returns the next time any window starts or ends. The
+// zero time is returned if there is none.
+func nextWindowBoundary(windows []parsedWindow, now time.Time) time.Time {
+	var next time.Time
+	for i := range windows {
+		for _, t := range []time.Time{windows[i].start.Next(now), windows[i].end.Next(now)} {
+			if !t.IsZero() && (next.IsZero() || t.Before(next)) {
+				next = t
+			}
+		}
+	}
+	return next
+}
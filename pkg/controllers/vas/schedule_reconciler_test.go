@@ -0,0 +1,110 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vas
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	test "github.com/vertica/vertica-kubernetes/pkg/test"
+	"github.com/vertica/vertica-kubernetes/pkg/v1beta1_test"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("schedule_reconcile", func() {
+	ctx := context.Background()
+
+	// A Monday, inside and outside the business day window
+	inWindow := time.Date(2025, 6, 2, 9, 30, 0, 0, time.UTC)
+	outOfWindow := time.Date(2025, 6, 2, 19, 0, 0, 0, time.UTC)
+
+	makeScheduleVAS := func(typ string) *vapi.VerticaAutoscaler {
+		defaultSize := int32(3)
+		vas := vapi.MakeVAS()
+		vas.Spec.ScalingGranularity = vapi.SubclusterScalingGranularity
+		vas.Spec.TargetSize = 3
+		vas.Spec.CustomAutoscaler = &vapi.CustomAutoscalerSpec{
+			Type: typ,
+			Schedule: &vapi.ScheduleSpec{
+				DefaultTargetSize: &defaultSize,
+				Windows: []vapi.ScheduleWindow{
+					{Name: "business", Start: "0 8 * * 1-5", End: "0 18 * * 1-5", SubclusterCount: 2},
+				},
+			},
+		}
+		return vas
+	}
+
+	runSchedule := func(vas *vapi.VerticaAutoscaler, now time.Time, requeueOnly bool) ctrl.Result {
+		act := MakeScheduleReconciler(vasRec, vas, logger, requeueOnly)
+		r := act.(*ScheduleReconciler)
+		r.Now = func() time.Time { return now }
+		req := ctrl.Request{NamespacedName: vapi.MakeVASName()}
+		res, err := r.Reconcile(ctx, &req)
+		Expect(err).Should(Succeed())
+		return res
+	}
+
+	It("should set the targetSize from the active window and fall back to the default", func() {
+		vdb := vapi.MakeVDB()
+		vdb.Spec.Subclusters[0].ServiceName = "sc1"
+		vdb.Spec.Subclusters[0].Size = 4
+		test.CreateVDB(ctx, k8sClient, vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+
+		vas := makeScheduleVAS(vapi.Schedule)
+		v1beta1_test.CreateVAS(ctx, k8sClient, vas)
+		defer v1beta1_test.DeleteVAS(ctx, k8sClient, vas)
+
+		Expect(runSchedule(vas, inWindow, false)).Should(Equal(ctrl.Result{}))
+		fetchVas := &vapi.VerticaAutoscaler{}
+		Expect(k8sClient.Get(ctx, vapi.MakeVASName(), fetchVas)).Should(Succeed())
+		Expect(fetchVas.Spec.TargetSize).Should(Equal(int32(8)))
+		Expect(fetchVas.Status.ActiveScheduleWindow).Should(Equal("business"))
+		Expect(fetchVas.Status.ScheduledSize).Should(Equal(int32(8)))
+
+		Expect(runSchedule(vas, outOfWindow, false)).Should(Equal(ctrl.Result{}))
+		Expect(k8sClient.Get(ctx, vapi.MakeVASName(), fetchVas)).Should(Succeed())
+		Expect(fetchVas.Spec.TargetSize).Should(Equal(int32(3)))
+		Expect(fetchVas.Status.ActiveScheduleWindow).Should(Equal(""))
+	})
+
+	It("should only raise the targetSize when the schedule is a floor for metric scaling", func() {
+		vas := makeScheduleVAS(vapi.HPA)
+		vas.Spec.CustomAutoscaler.Schedule.Windows[0].SubclusterCount = 0
+		vas.Spec.CustomAutoscaler.Schedule.Windows[0].TargetSize = 6
+		vas.Spec.TargetSize = 10
+		v1beta1_test.CreateVAS(ctx, k8sClient, vas)
+		defer v1beta1_test.DeleteVAS(ctx, k8sClient, vas)
+
+		Expect(runSchedule(vas, inWindow, false)).Should(Equal(ctrl.Result{}))
+		fetchVas := &vapi.VerticaAutoscaler{}
+		Expect(k8sClient.Get(ctx, vapi.MakeVASName(), fetchVas)).Should(Succeed())
+		Expect(fetchVas.Spec.TargetSize).Should(Equal(int32(10)))
+		Expect(fetchVas.Status.ScheduledSize).Should(Equal(int32(6)))
+		minReplicas := int32(1)
+		Expect(*vas.ApplyScalingFloor(&minReplicas, 20)).Should(Equal(int32(6)))
+	})
+
+	It("should requeue at the next window boundary", func() {
+		vas := makeScheduleVAS(vapi.Schedule)
+		res := runSchedule(vas, inWindow, true)
+		Expect(res.RequeueAfter).Should(Equal(8*time.Hour + 30*time.Minute + time.Second))
+	})
+})
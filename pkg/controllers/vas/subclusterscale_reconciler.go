@@ -17,27 +17,63 @@ package vas
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/vertica/vcluster/vclusterops"
 	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/pkg/cloud"
 	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	"github.com/vertica/vertica-kubernetes/pkg/dbsql"
 	verrors "github.com/vertica/vertica-kubernetes/pkg/errors"
 	"github.com/vertica/vertica-kubernetes/pkg/events"
+	vmeta "github.com/vertica/vertica-kubernetes/pkg/meta"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/getdrainingstatus"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/manageconnectiondraining"
 	"github.com/vertica/vertica-kubernetes/pkg/vasstatus"
+	vk8s "github.com/vertica/vertica-kubernetes/pkg/vk8s"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
+// How often we check again if the queries in a subcluster chosen for removal
+// have finished
+const scaleInQueryPollInterval = 10 * time.Second
+
 // SubclusterScaleReconciler will scale a VerticaDB by adding or removing subclusters.
 type SubclusterScaleReconciler struct {
 	VRec *VerticaAutoscalerReconciler
 	Vas  *vapi.VerticaAutoscaler
 	Vdb  *vapi.VerticaDB
+	// A connection to the database, used to check how busy the subclusters
+	// are when scaleInSafety is set. If nil, one is opened when needed. This
+	// is set by tests to mock the database.
+	Conn *sql.DB
+	// The dispatcher used to drain the connections of the subclusters chosen
+	// for removal. If nil, one is created when needed. This is set by tests to
+	// mock the vclusterops API.
+	Dispatcher vadmin.Dispatcher
+	// Returns the current time. Tests override this.
+	Now func() time.Time
+	// The activity of each subcluster, keyed by name. This is nil if we
+	// haven't checked it, in which case subclusters are removed last one
+	// first.
+	activity map[string]dbsql.SubclusterActivity
+	// The subclusters chosen for removal in this reconcile
+	victims []vapi.ScaleInVictim
 }
 
 func MakeSubclusterScaleReconciler(r *VerticaAutoscalerReconciler, vas *vapi.VerticaAutoscaler) controllers.ReconcileActor {
-	return &SubclusterScaleReconciler{VRec: r, Vas: vas, Vdb: &vapi.VerticaDB{}}
+	return &SubclusterScaleReconciler{VRec: r, Vas: vas, Vdb: &vapi.VerticaDB{}, Now: time.Now}
 }
 
 // Reconcile will grow/shrink the VerticaDB passed on the target pod count.
@@ -45,6 +81,15 @@ func (s *SubclusterScaleReconciler) Reconcile(ctx context.Context, req *ctrl.Req
 	if s.Vas.Spec.ScalingGranularity != vapi.SubclusterScalingGranularity {
 		return ctrl.Result{}, nil
 	}
+	if s.Conn == nil {
+		// Close the connection if one was opened to check the activity
+		defer func() {
+			if s.Conn != nil {
+				s.Conn.Close()
+				s.Conn = nil
+			}
+		}()
+	}
 
 	return s.scaleSubcluster(ctx, req)
 }
@@ -54,6 +99,7 @@ func (s *SubclusterScaleReconciler) Reconcile(ctx context.Context, req *ctrl.Req
 func (s *SubclusterScaleReconciler) scaleSubcluster(ctx context.Context, req *ctrl.Request) (ctrl.Result, error) {
 	var res ctrl.Result
 	scalingDone := false
+	scalingIn := false
 	// Update the VerticaDB with a retry mechanism for any conflict updates
 	// (i.e. if someone updated the vdb since we last fetched it)
 	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
@@ -66,9 +112,27 @@ func (s *SubclusterScaleReconciler) scaleSubcluster(ctx context.Context, req *ct
 		delta := s.Vas.Spec.TargetSize - totSize
 		switch {
 		case delta < 0:
-			if changed := s.considerRemovingSubclusters(delta * -1); !changed {
+			scalingIn = true
+			if s.Vas.Spec.ScaleInSafety != nil && s.activity == nil {
+				s.fetchSubclusterActivity(ctx)
+			}
+			// Stick with the subclusters chosen by an earlier reconcile until
+			// they are removed. Otherwise, the choice could move around as the
+			// activity changes and we would never finish waiting.
+			s.victims = s.getPendingVictims(delta * -1)
+			if s.victims == nil {
+				s.victims = s.pickSubclustersToRemove(delta * -1)
+			}
+			if len(s.victims) == 0 {
 				return nil
 			}
+			if s.Vas.Spec.ScaleInSafety != nil {
+				if r, e := s.checkScaleInSafety(ctx, req); verrors.IsReconcileAborted(r, e) {
+					res = r
+					return e
+				}
+			}
+			s.removeSubclusters()
 		case delta > 0:
 			if changed := s.considerAddingSubclusters(delta); !changed {
 				return nil
@@ -88,45 +152,402 @@ func (s *SubclusterScaleReconciler) scaleSubcluster(ctx context.Context, req *ct
 		return res, err
 	}
 
+	if !scalingIn && s.Vas.Spec.ScaleInSafety != nil {
+		if err := s.releasePendingVictims(ctx, req); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	if scalingDone {
 		_, totSize := s.Vdb.FindSubclusterForServiceName(s.Vas.Spec.ServiceName)
 		err = vasstatus.ReportScalingOperation(ctx, s.VRec.Client, s.VRec.Log, req, totSize)
+		if err == nil && s.Vas.Spec.ScaleInSafety != nil && len(s.victims) > 0 {
+			err = s.reportRemovedVictims(ctx, req)
+		}
 	}
 	return res, err
 }
 
-// considerRemovingSubclusters will shrink the Vdb by removing subclusters --
-// picking the last one first.  Changes are made in-place in s.Vdb
-func (s *SubclusterScaleReconciler) considerRemovingSubclusters(podsToRemove int32) bool {
-	origNumSubclusters := len(s.Vdb.Spec.Subclusters)
+// pickSubclustersToRemove will choose the subclusters to remove to shrink the
+// Vdb by the given pod count. If we know the activity of the subclusters, the
+// least busy are picked first. Otherwise, we pick the last one first.
+func (s *SubclusterScaleReconciler) pickSubclustersToRemove(podsToRemove int32) []vapi.ScaleInVictim {
+	candidates := []int{}
+	for j := len(s.Vdb.Spec.Subclusters) - 1; j >= 0; j-- {
+		sc := &s.Vdb.Spec.Subclusters[j]
+		if s.Vas.Spec.ServiceName == "" || sc.GetServiceName() == s.Vas.Spec.ServiceName {
+			candidates = append(candidates, j)
+		}
+	}
+	if s.activity != nil {
+		// The sort is stable so that, between subclusters that are equally
+		// busy, the last one is still picked first.
+		sort.SliceStable(candidates, func(i, j int) bool {
+			a := s.activity[s.Vdb.Spec.Subclusters[candidates[i]].Name]
+			b := s.activity[s.Vdb.Spec.Subclusters[candidates[j]].Name]
+			if a.RunningQueries != b.RunningQueries {
+				return a.RunningQueries < b.RunningQueries
+			}
+			return a.ActiveSessions < b.ActiveSessions
+		})
+	}
+
 	minHosts := vapi.KSafety0MinHosts
 	if !s.Vdb.IsKSafety0() {
 		minHosts = vapi.KSafety1MinHosts
 	}
-	for j := len(s.Vdb.Spec.Subclusters) - 1; j >= 0; j-- {
+	primaryCount := s.Vdb.GetPrimaryCount()
+	victims := []vapi.ScaleInVictim{}
+	for rank, j := range candidates {
+		if podsToRemove == 0 {
+			break
+		}
 		sc := &s.Vdb.Spec.Subclusters[j]
-		if s.Vas.Spec.ServiceName == "" || sc.GetServiceName() == s.Vas.Spec.ServiceName {
-			if podsToRemove == 0 {
+		if sc.Size > podsToRemove {
+			continue
+		}
+		if sc.IsPrimary(s.Vdb) {
+			// We will prevent removing a primary if it will lead to a kasafety
+			// rule violation.
+			if primaryCount-int(sc.Size) < minHosts {
+				s.VRec.Log.Info("Removing subcluster will violate ksafety. Skipping to the next one", "Subcluster", sc.Name)
+				continue
+			}
+			primaryCount -= int(sc.Size)
+		}
+		podsToRemove -= sc.Size
+		victim := vapi.ScaleInVictim{Subcluster: sc.Name}
+		if s.activity != nil {
+			a := s.activity[sc.Name]
+			victim.ActiveSessions = a.ActiveSessions
+			victim.RunningQueries = a.RunningQueries
+			victim.Reason = fmt.Sprintf("Ranked %d of %d candidate subclusters by activity: %d running queries and %d active sessions",
+				rank+1, len(candidates), a.RunningQueries, a.ActiveSessions)
+		} else {
+			victim.Reason = "The activity of the subclusters could not be checked, so the last subcluster was picked first"
+		}
+		victims = append(victims, victim)
+	}
+	return victims
+}
+
+// getPendingVictims returns the subclusters that an earlier reconcile chose
+// for removal and that haven't been removed yet. The activity counts are
+// refreshed but the selection time is kept. It returns nil if there are none,
+// or if they no longer fit the vdb and a new choice has to be made.
+func (s *SubclusterScaleReconciler) getPendingVictims(podsToRemove int32) []vapi.ScaleInVictim {
+	if s.Vas.Spec.ScaleInSafety == nil {
+		return nil
+	}
+	scMap := s.Vdb.GenSubclusterMap()
+	victims := []vapi.ScaleInVictim{}
+	for i := range s.Vas.Status.ScaleInVictims {
+		victim := s.Vas.Status.ScaleInVictims[i]
+		if victim.RemovedTime != nil {
+			continue
+		}
+		sc, ok := scMap[victim.Subcluster]
+		if !ok || sc.Size > podsToRemove ||
+			(s.Vas.Spec.ServiceName != "" && sc.GetServiceName() != s.Vas.Spec.ServiceName) {
+			return nil
+		}
+		podsToRemove -= sc.Size
+		if s.activity != nil {
+			a := s.activity[sc.Name]
+			victim.ActiveSessions = a.ActiveSessions
+			victim.RunningQueries = a.RunningQueries
+		}
+		victims = append(victims, victim)
+	}
+	if len(victims) == 0 {
+		return nil
+	}
+	return victims
+}
+
+// removeSubclusters will shrink the Vdb by removing the chosen subclusters.
+// Changes are made in-place in s.Vdb
+func (s *SubclusterScaleReconciler) removeSubclusters() {
+	toRemove := map[string]bool{}
+	for i := range s.victims {
+		toRemove[s.victims[i].Subcluster] = true
+	}
+	scs := []vapi.Subcluster{}
+	for i := range s.Vdb.Spec.Subclusters {
+		sc := &s.Vdb.Spec.Subclusters[i]
+		if toRemove[sc.Name] {
+			s.VRec.Log.Info("Removing subcluster in VerticaDB", "VerticaDB", s.Vdb.Name, "Subcluster", sc.Name)
+			continue
+		}
+		scs = append(scs, *sc)
+	}
+	s.Vdb.Spec.Subclusters = scs
+}
+
+// fetchSubclusterActivity will query the number of sessions and running
+// queries in each subcluster. If the database cannot be reached, the activity
+// is left unset and subclusters are removed in the default order.
+func (s *SubclusterScaleReconciler) fetchSubclusterActivity(ctx context.Context) {
+	if !s.Vdb.IsDBInitialized() {
+		return
+	}
+	if s.Conn == nil {
+		conn, err := dbsql.Open(ctx, s.VRec.Client, s.VRec.Log, s.VRec, s.Vdb)
+		if err != nil {
+			s.VRec.Eventf(s.Vas, corev1.EventTypeWarning, events.ScaleInActivityCheckFailed,
+				"Failed to connect to the database to check the activity of the subclusters: %s", err)
+			return
+		}
+		s.Conn = conn
+	}
+	activity, err := dbsql.GetSubclusterActivity(ctx, s.Conn)
+	if err != nil {
+		s.VRec.Eventf(s.Vas, corev1.EventTypeWarning, events.ScaleInActivityCheckFailed,
+			"Failed to check the activity of the subclusters: %s", err)
+		return
+	}
+	s.activity = activity
+}
+
+// checkScaleInSafety will hold off the removal of the chosen subclusters
+// while they have running queries, until the grace period has passed. Queries
+// still running after that are cancelled if the spec asks for it. The chosen
+// subclusters are recorded in the status.
+func (s *SubclusterScaleReconciler) checkScaleInSafety(ctx context.Context, req *ctrl.Request) (ctrl.Result, error) {
+	safety := s.Vas.Spec.ScaleInSafety
+	gracePeriod := time.Duration(safety.QueryGracePeriodSeconds) * time.Second
+
+	now := s.Now()
+	newlyChosen := map[string]bool{}
+	for i := range s.victims {
+		victim := &s.victims[i]
+		if victim.SelectedTime.IsZero() {
+			newlyChosen[victim.Subcluster] = true
+			victim.SelectedTime = metav1.NewTime(now)
+			s.VRec.Eventf(s.Vas, corev1.EventTypeNormal, events.ScaleInVictimSelected,
+				"Subcluster '%s' was chosen for removal. %s", victim.Subcluster, victim.Reason)
+		}
+	}
+
+	// Stop new connections from landing on the chosen subclusters before we
+	// wait for, or cancel, the queries that are running there.
+	s.drainConnections(ctx)
+
+	requeueAfter := time.Duration(0)
+	for i := range s.victims {
+		victim := &s.victims[i]
+		if victim.RunningQueries == 0 {
+			continue
+		}
+		if remaining := victim.SelectedTime.Add(gracePeriod).Sub(now); remaining > 0 {
+			if newlyChosen[victim.Subcluster] {
+				s.VRec.Eventf(s.Vas, corev1.EventTypeNormal, events.ScaleInWaitingForQueries,
+					"Waiting up to %s for %d queries in subcluster '%s' to finish before removing it",
+					gracePeriod, victim.RunningQueries, victim.Subcluster)
+			}
+			wait := min(remaining, scaleInQueryPollInterval)
+			if requeueAfter == 0 || wait < requeueAfter {
+				requeueAfter = wait
+			}
+			continue
+		}
+		if safety.CancelQueries && s.Conn != nil {
+			cancelled, err := dbsql.CancelSubclusterQueries(ctx, s.Conn, victim.Subcluster)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if cancelled > 0 {
+				s.VRec.Eventf(s.Vas, corev1.EventTypeWarning, events.ScaleInQueriesCancelled,
+					"Cancelled %d queries still running in subcluster '%s' after the grace period of %s",
+					cancelled, victim.Subcluster, gracePeriod)
+			}
+		}
+	}
+
+	if !reflect.DeepEqual(s.victims, s.Vas.Status.ScaleInVictims) {
+		if err := vasstatus.SetScaleInVictims(ctx, s.VRec.Client, s.VRec.Log, req, s.victims); err != nil {
+			return ctrl.Result{}, err
+		}
+		s.Vas.Status.ScaleInVictims = s.victims
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// drainConnections will pause new client connections to the subclusters
+// chosen for removal, so that their sessions wind down while we wait. The
+// current draining status is checked first so that subclusters already paused
+// are left alone. A failure is reported as an event and doesn't hold up the
+// scale-in.
+func (s *SubclusterScaleReconciler) drainConnections(ctx context.Context) {
+	if !s.Vdb.IsDBInitialized() || !vmeta.UseVClusterOps(s.Vdb.Annotations) {
+		return
+	}
+	initiatorIP, dispatcher, err := s.prepareDraining(ctx)
+	if err != nil {
+		s.VRec.Eventf(s.Vas, corev1.EventTypeWarning, events.ScaleInConnectionDrainFailed,
+			"Failed to drain the connections of the subclusters chosen for removal: %s", err)
+		return
+	}
+	if initiatorIP == "" {
+		s.VRec.Log.Info("No running pod found to drain the connections of the subclusters chosen for removal")
+		return
+	}
+
+	statusList, err := dispatcher.GetDrainingStatus(ctx, getdrainingstatus.WithInitiator(initiatorIP))
+	if err != nil {
+		s.VRec.Eventf(s.Vas, corev1.EventTypeWarning, events.ScaleInConnectionDrainFailed,
+			"Failed to get the connection draining status of the subclusters: %s", err)
+		return
+	}
+	paused := map[string]bool{}
+	for i := range statusList {
+		if isConnectionDrainPaused(statusList[i].Status) {
+			paused[strings.ToLower(statusList[i].SubclusterName)] = true
+		}
+	}
+
+	for i := range s.victims {
+		scName := s.victims[i].Subcluster
+		if paused[strings.ToLower(scName)] {
+			continue
+		}
+		err = dispatcher.ManageConnectionDraining(ctx,
+			manageconnectiondraining.WithInitiator(initiatorIP),
+			manageconnectiondraining.WithSubcluster(scName),
+			manageconnectiondraining.WithAction(vclusterops.ActionPause),
+		)
+		if err != nil {
+			s.VRec.Eventf(s.Vas, corev1.EventTypeWarning, events.ScaleInConnectionDrainFailed,
+				"Failed to pause the connections of subcluster '%s': %s", scName, err)
+			continue
+		}
+		s.VRec.Eventf(s.Vas, corev1.EventTypeNormal, events.ScaleInConnectionsPaused,
+			"Paused new client connections to subcluster '%s' ahead of its removal", scName)
+	}
+}
+
+// releasePendingVictims is called when we are no longer scaling in. If an
+// earlier reconcile chose subclusters for removal that are still in the vdb,
+// their connections are resumed and the choice is dropped from the status.
+func (s *SubclusterScaleReconciler) releasePendingVictims(ctx context.Context, req *ctrl.Request) error {
+	scMap := s.Vdb.GenSubclusterMap()
+	s.victims = nil
+	for i := range s.Vas.Status.ScaleInVictims {
+		victim := &s.Vas.Status.ScaleInVictims[i]
+		if victim.RemovedTime == nil {
+			if _, ok := scMap[victim.Subcluster]; ok {
+				s.victims = append(s.victims, *victim)
+			}
+		}
+	}
+	if len(s.victims) == 0 {
+		return nil
+	}
+
+	if s.Vdb.IsDBInitialized() && vmeta.UseVClusterOps(s.Vdb.Annotations) {
+		initiatorIP, dispatcher, err := s.prepareDraining(ctx)
+		if err != nil {
+			return err
+		}
+		for i := range s.victims {
+			if initiatorIP == "" {
 				break
 			}
-			if sc.Size <= podsToRemove {
-				if sc.IsPrimary(s.Vdb) {
-					primaryCount := s.Vdb.GetPrimaryCount()
-					primaryCountAfterScaling := primaryCount - int(sc.Size)
-					// We will prevent removing a primary if it will lead to a kasafety
-					// rule violation.
-					if primaryCountAfterScaling < minHosts {
-						s.VRec.Log.Info("Removing subcluster will violate ksafety. Skipping to the next one", "Subcluster", sc.Name)
-						continue
-					}
+			err = dispatcher.ManageConnectionDraining(ctx,
+				manageconnectiondraining.WithInitiator(initiatorIP),
+				manageconnectiondraining.WithSubcluster(s.victims[i].Subcluster),
+				manageconnectiondraining.WithAction(vclusterops.ActionResume),
+			)
+			if err != nil {
+				return err
+			}
+			s.VRec.Log.Info("Resumed connections of a subcluster that is no longer going to be removed",
+				"Subcluster", s.victims[i].Subcluster)
+		}
+	}
+
+	s.victims = nil
+	if err := vasstatus.SetScaleInVictims(ctx, s.VRec.Client, s.VRec.Log, req, nil); err != nil {
+		return err
+	}
+	s.Vas.Status.ScaleInVictims = nil
+	return nil
+}
+
+// prepareDraining returns the IP of the pod to run the connection draining
+// API from, and the dispatcher to call it with. The IP is empty if there is
+// no running pod outside of the chosen subclusters.
+func (s *SubclusterScaleReconciler) prepareDraining(ctx context.Context) (string, vadmin.Dispatcher, error) {
+	initiatorIP, err := s.findInitiatorIP(ctx)
+	if err != nil || initiatorIP == "" {
+		return "", nil, err
+	}
+	if s.Dispatcher == nil {
+		password, err := vk8s.GetSuperuserPassword(ctx, s.VRec.Client, s.VRec.Log, s.VRec, s.Vdb)
+		if err != nil {
+			return "", nil, err
+		}
+		fetcher := &cloud.SecretFetcher{
+			Client:   s.VRec.Client,
+			Log:      s.VRec.Log,
+			Obj:      s.Vdb,
+			EVWriter: s.VRec,
+		}
+		s.VRec.CacheManager.InitCertCacheForVdb(s.Vdb, fetcher)
+		s.Dispatcher = vadmin.MakeVClusterOps(s.VRec.Log, s.Vdb, s.VRec.Client, password,
+			s.VRec, vadmin.SetupVClusterOps, s.VRec.CacheManager)
+	}
+	return initiatorIP, s.Dispatcher, nil
+}
+
+// findInitiatorIP returns the IP of a running pod in the main cluster that
+// isn't in one of the subclusters chosen for removal.
+func (s *SubclusterScaleReconciler) findInitiatorIP(ctx context.Context) (string, error) {
+	toRemove := map[string]bool{}
+	for i := range s.victims {
+		toRemove[s.victims[i].Subcluster] = true
+	}
+	for i := range s.Vdb.Spec.Subclusters {
+		sc := &s.Vdb.Spec.Subclusters[i]
+		if toRemove[sc.Name] || s.Vdb.GetSubclusterSandboxName(sc.Name) != vapi.MainCluster {
+			continue
+		}
+		for j := int32(0); j < sc.Size; j++ {
+			pod := &corev1.Pod{}
+			if err := s.VRec.Client.Get(ctx, names.GenPodName(s.Vdb, sc, j), pod); err != nil {
+				if kerrors.IsNotFound(err) {
+					continue
 				}
-				podsToRemove -= sc.Size
-				s.VRec.Log.Info("Removing subcluster in VerticaDB", "VerticaDB", s.Vdb.Name, "Subcluster", sc.Name)
-				s.Vdb.Spec.Subclusters = append(s.Vdb.Spec.Subclusters[:j], s.Vdb.Spec.Subclusters[j+1:]...)
+				return "", err
+			}
+			if pod.Status.Phase == corev1.PodRunning && pod.Status.PodIP != "" {
+				return pod.Status.PodIP, nil
 			}
 		}
 	}
-	return origNumSubclusters != len(s.Vdb.Spec.Subclusters)
+	return "", nil
+}
+
+// isConnectionDrainPaused returns true if the draining status of a subcluster
+// shows that new connections are already paused
+func isConnectionDrainPaused(status string) bool {
+	return strings.HasPrefix(strings.ToLower(status), "paus")
+}
+
+// reportRemovedVictims will record in the status the time the chosen
+// subclusters were removed
+func (s *SubclusterScaleReconciler) reportRemovedVictims(ctx context.Context, req *ctrl.Request) error {
+	removedTime := metav1.NewTime(s.Now())
+	victims := make([]vapi.ScaleInVictim, len(s.victims))
+	for i := range s.victims {
+		victims[i] = s.victims[i]
+		victims[i].RemovedTime = &removedTime
+	}
+	if err := vasstatus.SetScaleInVictims(ctx, s.VRec.Client, s.VRec.Log, req, victims); err != nil {
+		return err
+	}
+	s.Vas.Status.ScaleInVictims = victims
+	return nil
 }
 
 // considerAddingSubclusters will grow the Vdb by adding new subclusters.
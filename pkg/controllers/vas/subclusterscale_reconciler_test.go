@@ -18,16 +18,48 @@ package vas
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/vertica/vcluster/vclusterops"
 	vapi "github.com/vertica/vertica-kubernetes/api/v1"
 	v1beta1 "github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/mockvops"
 	test "github.com/vertica/vertica-kubernetes/pkg/test"
 	"github.com/vertica/vertica-kubernetes/pkg/v1beta1_test"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin"
+	"github.com/vertica/vertica-kubernetes/pkg/vdbstatus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
+// mockDrainVClusterOps records the connection draining calls
+type mockDrainVClusterOps struct {
+	mockvops.MockVClusterOps
+	paused  []string
+	actions []vclusterops.ConnectionDrainingAction
+}
+
+func (m *mockDrainVClusterOps) VGetDrainingStatus(_ *vclusterops.VGetDrainingStatusOptions) (
+	vclusterops.DrainingStatusList, error) {
+	dsList := vclusterops.DrainingStatusList{}
+	for _, sc := range m.paused {
+		dsList.StatusList = append(dsList.StatusList, vclusterops.DrainingStatus{SubclusterName: sc, Status: "paused"})
+	}
+	return dsList, nil
+}
+
+func (m *mockDrainVClusterOps) VManageConnectionDraining(options *vclusterops.VManageConnectionDrainingOptions) error {
+	m.actions = append(m.actions, options.Action)
+	if options.Action == vclusterops.ActionPause {
+		m.paused = append(m.paused, options.SCName)
+	}
+	return nil
+}
+
 var _ = Describe("subclusterscale_reconcile", func() {
 	ctx := context.Background()
 
@@ -262,4 +294,170 @@ var _ = Describe("subclusterscale_reconcile", func() {
 		Expect(fetchVdb.Spec.Subclusters[2].Name).Should(Equal(subclustereName + "-1"))
 		Expect(fetchVdb.Spec.Subclusters[2].ServiceName).Should(Equal(serviceName))
 	})
+
+	Context("with scaleInSafety", func() {
+		const ServiceName = "as"
+
+		createVDBAndVAS := func(safety *vapi.ScaleInSafetySpec) (*vapi.VerticaDB, *vapi.VerticaAutoscaler) {
+			vdb := vapi.MakeVDBForVclusterOps()
+			vdb.Spec.Subclusters = []vapi.Subcluster{
+				{Name: "sc1", Size: 3, ServiceName: "pri", Type: vapi.PrimarySubcluster},
+				{Name: "sc2", Size: 3, ServiceName: ServiceName, Type: vapi.SecondarySubcluster},
+				{Name: "sc3", Size: 3, ServiceName: ServiceName, Type: vapi.SecondarySubcluster},
+			}
+			test.CreateVDB(ctx, k8sClient, vdb)
+			Expect(vdbstatus.UpdateCondition(ctx, k8sClient, vdb,
+				vapi.MakeCondition(vapi.DBInitialized, metav1.ConditionTrue, "Initialized"))).Should(Succeed())
+
+			vas := vapi.MakeVAS()
+			vas.Spec.ScalingGranularity = vapi.SubclusterScalingGranularity
+			vas.Spec.ServiceName = ServiceName
+			vas.Spec.TargetSize = 3
+			vas.Spec.ScaleInSafety = safety
+			v1beta1_test.CreateVAS(ctx, k8sClient, vas)
+			return vdb, vas
+		}
+
+		expectActivity := func(mock sqlmock.Sqlmock) {
+			mock.ExpectQuery("FROM v_catalog.subclusters sc LEFT JOIN v_monitor.sessions").
+				WillReturnRows(sqlmock.NewRows([]string{"subcluster_name", "sessions", "queries"}).
+					AddRow("sc1", 5, 0).AddRow("sc2", 3, 1).AddRow("sc3", 4, 2))
+		}
+
+		It("should remove the least busy subcluster first", func() {
+			vdb, vas := createVDBAndVAS(&vapi.ScaleInSafetySpec{})
+			defer test.DeleteVDB(ctx, k8sClient, vdb)
+			defer v1beta1_test.DeleteVAS(ctx, k8sClient, vas)
+
+			db, mock, err := sqlmock.New()
+			Expect(err).Should(Succeed())
+			defer db.Close()
+			expectActivity(mock)
+
+			act := MakeSubclusterScaleReconciler(vasRec, vas)
+			r := act.(*SubclusterScaleReconciler)
+			r.Conn = db
+			req := ctrl.Request{NamespacedName: vapi.MakeVASName()}
+			Expect(r.Reconcile(ctx, &req)).Should(Equal(ctrl.Result{}))
+			Expect(mock.ExpectationsWereMet()).Should(Succeed())
+
+			fetchVdb := &vapi.VerticaDB{}
+			Expect(k8sClient.Get(ctx, vdb.ExtractNamespacedName(), fetchVdb)).Should(Succeed())
+			Expect(fetchVdb.Spec.Subclusters).Should(HaveLen(2))
+			// sc3 is the last subcluster, but it is the busiest
+			Expect(fetchVdb.Spec.Subclusters[1].Name).Should(Equal("sc3"))
+
+			fetchVas := &vapi.VerticaAutoscaler{}
+			Expect(k8sClient.Get(ctx, vapi.MakeVASName(), fetchVas)).Should(Succeed())
+			Expect(fetchVas.Status.ScaleInVictims).Should(HaveLen(1))
+			Expect(fetchVas.Status.ScaleInVictims[0].Subcluster).Should(Equal("sc2"))
+			Expect(fetchVas.Status.ScaleInVictims[0].RunningQueries).Should(Equal(int32(1)))
+			Expect(fetchVas.Status.ScaleInVictims[0].Reason).Should(ContainSubstring("Ranked 1 of 2"))
+			Expect(fetchVas.Status.ScaleInVictims[0].RemovedTime).ShouldNot(BeNil())
+		})
+
+		It("should wait for the queries in the chosen subcluster and then cancel them", func() {
+			vdb, vas := createVDBAndVAS(&vapi.ScaleInSafetySpec{QueryGracePeriodSeconds: 60, CancelQueries: true})
+			defer test.DeleteVDB(ctx, k8sClient, vdb)
+			defer v1beta1_test.DeleteVAS(ctx, k8sClient, vas)
+
+			db, mock, err := sqlmock.New()
+			Expect(err).Should(Succeed())
+			defer db.Close()
+			selected := time.Date(2025, 6, 2, 8, 0, 0, 0, time.UTC)
+			req := ctrl.Request{NamespacedName: vapi.MakeVASName()}
+
+			// Within the grace period we requeue and leave the subcluster alone
+			expectActivity(mock)
+			r := MakeSubclusterScaleReconciler(vasRec, vas).(*SubclusterScaleReconciler)
+			r.Conn = db
+			r.Now = func() time.Time { return selected }
+			Expect(r.Reconcile(ctx, &req)).Should(Equal(ctrl.Result{RequeueAfter: scaleInQueryPollInterval}))
+			fetchVdb := &vapi.VerticaDB{}
+			Expect(k8sClient.Get(ctx, vdb.ExtractNamespacedName(), fetchVdb)).Should(Succeed())
+			Expect(fetchVdb.Spec.Subclusters).Should(HaveLen(3))
+			Expect(vas.Status.ScaleInVictims).Should(HaveLen(1))
+			Expect(vas.Status.ScaleInVictims[0].SelectedTime.Time.Equal(selected)).Should(BeTrue())
+			Expect(vas.Status.ScaleInVictims[0].RemovedTime).Should(BeNil())
+
+			// Once it has passed, the queries are cancelled and the subcluster removed
+			expectActivity(mock)
+			mock.ExpectQuery("WHERE sc.subcluster_name = ").WithArgs("sc2").
+				WillReturnRows(sqlmock.NewRows([]string{"session_id", "statement_id"}).AddRow("v_db_node0004-1:0x10", 3))
+			mock.ExpectExec("INTERRUPT_STATEMENT").WithArgs("v_db_node0004-1:0x10", 3).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			r = MakeSubclusterScaleReconciler(vasRec, vas).(*SubclusterScaleReconciler)
+			r.Conn = db
+			r.Now = func() time.Time { return selected.Add(61 * time.Second) }
+			Expect(r.Reconcile(ctx, &req)).Should(Equal(ctrl.Result{}))
+			Expect(mock.ExpectationsWereMet()).Should(Succeed())
+			Expect(k8sClient.Get(ctx, vdb.ExtractNamespacedName(), fetchVdb)).Should(Succeed())
+			Expect(fetchVdb.Spec.Subclusters).Should(HaveLen(2))
+			Expect(vas.Status.ScaleInVictims[0].SelectedTime.Time.Equal(selected)).Should(BeTrue())
+			Expect(vas.Status.ScaleInVictims[0].RemovedTime).ShouldNot(BeNil())
+		})
+
+		It("should keep the chosen subcluster and drain its connections until it is removed", func() {
+			vdb, vas := createVDBAndVAS(&vapi.ScaleInSafetySpec{QueryGracePeriodSeconds: 60})
+			defer test.DeleteVDB(ctx, k8sClient, vdb)
+			defer v1beta1_test.DeleteVAS(ctx, k8sClient, vas)
+			test.CreatePods(ctx, k8sClient, vdb, test.AllPodsRunning)
+			defer test.DeletePods(ctx, k8sClient, vdb)
+
+			vdb.Spec.HTTPSNMATLS.Secret = "scale-in-drain-test-secret"
+			test.CreateFakeTLSSecret(ctx, vdb, k8sClient, vdb.Spec.HTTPSNMATLS.Secret)
+			defer test.DeleteSecret(ctx, k8sClient, vdb.Spec.HTTPSNMATLS.Secret)
+			mockOps := &mockDrainVClusterOps{}
+			setupAPIFunc := func(logr.Logger, string) (vadmin.VClusterProvider, logr.Logger) {
+				return mockOps, logr.Logger{}
+			}
+			dispatcher := mockvops.MakeMockVClusterOpsDispatcher(vdb, logger, k8sClient, setupAPIFunc)
+
+			db, mock, err := sqlmock.New()
+			Expect(err).Should(Succeed())
+			defer db.Close()
+			selected := time.Date(2025, 6, 2, 8, 0, 0, 0, time.UTC)
+			req := ctrl.Request{NamespacedName: vapi.MakeVASName()}
+
+			expectActivity(mock)
+			r := MakeSubclusterScaleReconciler(vasRec, vas).(*SubclusterScaleReconciler)
+			r.Conn = db
+			r.Dispatcher = dispatcher
+			r.Now = func() time.Time { return selected }
+			Expect(r.Reconcile(ctx, &req)).Should(Equal(ctrl.Result{RequeueAfter: scaleInQueryPollInterval}))
+			Expect(vas.Status.ScaleInVictims).Should(HaveLen(1))
+			Expect(vas.Status.ScaleInVictims[0].Subcluster).Should(Equal("sc2"))
+			Expect(mockOps.paused).Should(Equal([]string{"sc2"}))
+
+			// sc3 is now the least busy, but we stay with sc2 and don't pause
+			// its connections a second time
+			mock.ExpectQuery("FROM v_catalog.subclusters sc LEFT JOIN v_monitor.sessions").
+				WillReturnRows(sqlmock.NewRows([]string{"subcluster_name", "sessions", "queries"}).
+					AddRow("sc1", 5, 0).AddRow("sc2", 3, 4).AddRow("sc3", 0, 0))
+			r = MakeSubclusterScaleReconciler(vasRec, vas).(*SubclusterScaleReconciler)
+			r.Conn = db
+			r.Dispatcher = dispatcher
+			r.Now = func() time.Time { return selected.Add(30 * time.Second) }
+			Expect(r.Reconcile(ctx, &req)).Should(Equal(ctrl.Result{RequeueAfter: scaleInQueryPollInterval}))
+			Expect(mock.ExpectationsWereMet()).Should(Succeed())
+			Expect(vas.Status.ScaleInVictims).Should(HaveLen(1))
+			Expect(vas.Status.ScaleInVictims[0].Subcluster).Should(Equal("sc2"))
+			Expect(vas.Status.ScaleInVictims[0].RunningQueries).Should(Equal(int32(4)))
+			Expect(vas.Status.ScaleInVictims[0].SelectedTime.Time.Equal(selected)).Should(BeTrue())
+			Expect(mockOps.actions).Should(Equal([]vclusterops.ConnectionDrainingAction{vclusterops.ActionPause}))
+
+			// If the scale-in is called off, the connections are resumed
+			fetchVas := &vapi.VerticaAutoscaler{}
+			Expect(k8sClient.Get(ctx, req.NamespacedName, fetchVas)).Should(Succeed())
+			fetchVas.Spec.TargetSize = 6
+			Expect(k8sClient.Update(ctx, fetchVas)).Should(Succeed())
+			r = MakeSubclusterScaleReconciler(vasRec, fetchVas).(*SubclusterScaleReconciler)
+			r.Conn = db
+			r.Dispatcher = dispatcher
+			Expect(r.Reconcile(ctx, &req)).Should(Equal(ctrl.Result{}))
+			Expect(mockOps.actions).Should(Equal([]vclusterops.ConnectionDrainingAction{
+				vclusterops.ActionPause, vclusterops.ActionResume}))
+			Expect(fetchVas.Status.ScaleInVictims).Should(BeEmpty())
+		})
+	})
 })
@@ -25,6 +25,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/pkg/cache"
 	vmeta "github.com/vertica/vertica-kubernetes/pkg/meta"
 	"github.com/vertica/vertica-kubernetes/pkg/names"
 	corev1 "k8s.io/api/core/v1"
@@ -80,10 +81,11 @@ var _ = BeforeSuite(func() {
 	Expect(err).NotTo(HaveOccurred())
 
 	vasRec = &VerticaAutoscalerReconciler{
-		Client: k8sClient,
-		Log:    logger,
-		Scheme: scheme.Scheme,
-		EVRec:  mgr.GetEventRecorderFor(vmeta.OperatorName),
+		Client:       k8sClient,
+		Log:          logger,
+		Scheme:       scheme.Scheme,
+		EVRec:        mgr.GetEventRecorderFor(vmeta.OperatorName),
+		CacheManager: cache.MakeCacheManager(true),
 	}
 })
 
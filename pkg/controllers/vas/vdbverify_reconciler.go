@@ -51,7 +51,8 @@ func (s *VDBVerifyReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (c
 	if vErr != nil {
 		return ctrl.Result{}, vErr
 	}
-	if !vinf.IsEqualOrNewer(vapi.PrometheusMetricsMinVersion) {
+	// Vertica metrics and schedules don't rely on prometheus
+	if s.Vas.IsCustomMetricsEnabled() && !vinf.IsEqualOrNewer(vapi.PrometheusMetricsMinVersion) {
 		ver, _ := s.Vdb.GetVerticaVersionStr()
 		s.VRec.Eventf(s.Vas, corev1.EventTypeWarning, events.PrometheusMetricsNotSupported,
 			"The server version %s does not support prometheus metrics", ver)
@@ -31,6 +31,7 @@ import (
 	"github.com/go-logr/logr"
 	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 	v1vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/pkg/cache"
 	"github.com/vertica/vertica-kubernetes/pkg/controllers"
 	verrors "github.com/vertica/vertica-kubernetes/pkg/errors"
 	"github.com/vertica/vertica-kubernetes/pkg/events"
@@ -41,9 +42,10 @@ import (
 // VerticaAutoscalerReconciler reconciles a VerticaAutoscaler object
 type VerticaAutoscalerReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
-	Log    logr.Logger
-	EVRec  record.EventRecorder
+	Scheme       *runtime.Scheme
+	Log          logr.Logger
+	EVRec        record.EventRecorder
+	CacheManager cache.CacheManager
 }
 
 // +kubebuilder:rbac:groups=vertica.com,resources=verticaautoscalers,verbs=get;list;watch;create;update;patch;delete
@@ -51,6 +53,8 @@ type VerticaAutoscalerReconciler struct {
 // +kubebuilder:rbac:groups=vertica.com,resources=verticaautoscalers/finalizers,verbs=update
 // +kubebuilder:rbac:groups=vertica.com,resources=verticadbs,verbs=get;list;create;update;patch;delete
 // +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
 // +kubebuilder:rbac:groups=keda.sh,resources=scaledobjects,verbs=get;list;watch;create;update;patch
 // +kubebuilder:rbac:groups=keda.sh,resources=triggerauthentications,verbs=get;list;watch;create;update;patch
 
@@ -87,8 +91,23 @@ func (r *VerticaAutoscalerReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		MakeVDBVerifyReconciler(r, vas),
 		// Initialize targetSize in new VerticaAutoscaler objects
 		MakeTargetSizeInitializerReconciler(r, vas),
+		// Apply the size of the active schedule window to the targetSize.
+		// This must be before the hpa/scaledObject is built since the
+		// schedule sets a floor for their minimum replicas.
+		MakeScheduleReconciler(r, vas, log, false /* requeueOnly */),
+		// Learn the load profile from the hpa metrics and forecast the
+		// demand. Like the schedule, the forecast is a floor for the hpa, so
+		// this must be before the hpa is built.
+		MakePredictiveReconciler(r, vas, log),
 		// Update the currentSize in the status
 		MakeRefreshCurrentSizeReconciler(r, vas),
+		// Sample the vertica metrics and set the targetSize from them. This
+		// needs the currentSize, so it must be after the refresh.
+		MakeVerticaMetricReconciler(r, vas, log, false /* requeueOnly */),
+		// Combine the hpa metrics into a single decision with the scaling
+		// policy. The hpa is pinned to the decision, so this must be before
+		// the hpa is updated.
+		MakeScalingPolicyReconciler(r, vas, log, false /* requeueOnly */),
 		// Update the selector in the status
 		MakeRefreshSelectorReconciler(r, vas),
 		// // Create/Update the hpa/scaledObject
@@ -103,6 +122,16 @@ func (r *VerticaAutoscalerReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		// If scaling granularity is Subcluster, this will create or delete
 		// entire subcluster to match the targetSize.
 		MakeSubclusterScaleReconciler(r, vas),
+		// Requeue so that the vertica metrics are sampled again after the
+		// polling interval. This comes before the schedule requeue since the
+		// schedule is reapplied on each of these reconciles anyway.
+		MakeVerticaMetricReconciler(r, vas, log, true /* requeueOnly */),
+		// Requeue for when the scale in conditions of the scaling policy
+		// have been met for long enough.
+		MakeScalingPolicyReconciler(r, vas, log, true /* requeueOnly */),
+		// Requeue at the next schedule window boundary. This must be done
+		// last since the requeue stops the reconcile.
+		MakeScheduleReconciler(r, vas, log, true /* requeueOnly */),
 	}
 
 	// Iterate over each actor
@@ -155,6 +184,15 @@ func (r *VerticaAutoscalerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrlManager.Complete(r)
 }
 
+// Event a wrapper for Event() that also writes a log entry
+func (r *VerticaAutoscalerReconciler) Event(vdb runtime.Object, eventtype, reason, message string) {
+	evWriter := events.Writer{
+		Log:   r.Log,
+		EVRec: r.EVRec,
+	}
+	evWriter.Event(vdb, eventtype, reason, message)
+}
+
 func (r *VerticaAutoscalerReconciler) Eventf(vdb runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
 	evWriter := events.Writer{
 		Log:   r.Log,
@@ -0,0 +1,238 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vas
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"time"
+
+	"github.com/go-logr/logr"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	"github.com/vertica/vertica-kubernetes/pkg/dbsql"
+	verrors "github.com/vertica/vertica-kubernetes/pkg/errors"
+	"github.com/vertica/vertica-kubernetes/pkg/events"
+	"github.com/vertica/vertica-kubernetes/pkg/vasstatus"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// How often the vertica metrics are sampled if the scaledObject doesn't
+	// set a polling interval
+	defaultVerticaMetricPollingInterval = 30 * time.Second
+
+	// The ratio between the metric and its threshold must differ from 1 by
+	// more than this before we scale. This is the same tolerance the hpa uses
+	// and it prevents flapping when the metric hovers around the threshold.
+	verticaMetricTolerance = 0.1
+)
+
+// VerticaMetricReconciler will set the targetSize from metrics sampled
+// directly from the Vertica system tables
+type VerticaMetricReconciler struct {
+	VRec *VerticaAutoscalerReconciler
+	Vas  *vapi.VerticaAutoscaler
+	Log  logr.Logger
+	// When true, this actor only requeues the vas so that the metrics are
+	// sampled again after the polling interval. Since a requeue stops the
+	// reconcile, this must be one of the last actors.
+	RequeueOnly bool
+	// A connection to the database. If nil, one is opened when needed. This
+	// is set by tests to mock the database.
+	Conn *sql.DB
+	// Returns the current time. Tests override this.
+	Now func() time.Time
+}
+
+func MakeVerticaMetricReconciler(v *VerticaAutoscalerReconciler, vas *vapi.VerticaAutoscaler,
+	log logr.Logger, requeueOnly bool) controllers.ReconcileActor {
+	return &VerticaMetricReconciler{
+		VRec:        v,
+		Vas:         vas,
+		Log:         log.WithName("VerticaMetricReconciler"),
+		RequeueOnly: requeueOnly,
+		Now:         time.Now,
+	}
+}
+
+// Reconcile will sample the vertica metrics and change the targetSize to the
+// pod count they ask for
+func (m *VerticaMetricReconciler) Reconcile(ctx context.Context, req *ctrl.Request) (ctrl.Result, error) {
+	if !m.Vas.IsVerticaMetricsEnabled() {
+		if m.RequeueOnly || len(m.Vas.Status.VerticaMetrics) == 0 {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, m.setVerticaMetrics(ctx, req, nil)
+	}
+	if m.RequeueOnly {
+		return ctrl.Result{RequeueAfter: m.getPollingInterval()}, nil
+	}
+
+	vdb := &vapi.VerticaDB{}
+	if res, err := fetchVDB(ctx, m.VRec, m.Vas, vdb); verrors.IsReconcileAborted(res, err) {
+		return res, err
+	}
+	if !vdb.IsDBInitialized() {
+		m.Log.Info("Database is not initialized yet. Skipping the vertica metrics")
+		return ctrl.Result{}, nil
+	}
+	if m.Conn == nil {
+		conn, err := dbsql.Open(ctx, m.VRec.Client, m.Log, m.VRec, vdb)
+		if err != nil {
+			m.Log.Info("failed to connect to the database, skipping the vertica metrics", "err", err.Error())
+			return ctrl.Result{}, m.setScalingActive(ctx, req, corev1.ConditionFalse)
+		}
+		defer conn.Close()
+		m.Conn = conn
+	}
+
+	samples, err := m.sampleMetrics(ctx)
+	if err != nil {
+		m.VRec.Eventf(m.Vas, corev1.EventTypeWarning, events.VerticaMetricSampleFailed,
+			"Failed to sample the vertica metrics: %s", err)
+		return ctrl.Result{}, m.setScalingActive(ctx, req, corev1.ConditionFalse)
+	}
+	if err := m.setVerticaMetrics(ctx, req, samples); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := m.setScalingActive(ctx, req, corev1.ConditionTrue); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, m.updateTargetSize(ctx, samples)
+}
+
+// sampleMetrics queries each vertica metric and returns its value along with
+// the pod count it asks for
+func (m *VerticaMetricReconciler) sampleMetrics(ctx context.Context) ([]vapi.VerticaMetricStatus, error) {
+	so := m.Vas.Spec.CustomAutoscaler.ScaledObject
+	currentSize := m.Vas.Status.CurrentSize
+	if currentSize == 0 {
+		currentSize = m.Vas.Spec.TargetSize
+	}
+	minSize := m.getMinSize()
+	now := metav1.NewTime(m.Now())
+	samples := make([]vapi.VerticaMetricStatus, 0, len(so.Metrics))
+	for i := range so.Metrics {
+		metric := &so.Metrics[i]
+		value, err := dbsql.SampleVerticaMetric(ctx, m.Conn, metric.Vertica)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, vapi.VerticaMetricStatus{
+			Name:        metric.Name,
+			Value:       value,
+			DesiredSize: getDesiredSizeForMetric(metric, value, currentSize, minSize),
+			SampleTime:  now,
+		})
+	}
+	return samples, nil
+}
+
+// updateTargetSize sets the targetSize to the largest pod count asked for by
+// the metrics. It is kept between the min and max replicas.
+func (m *VerticaMetricReconciler) updateTargetSize(ctx context.Context, samples []vapi.VerticaMetricStatus) error {
+	newSize := m.getMinSize()
+	for i := range samples {
+		newSize = max(newSize, samples[i].DesiredSize)
+	}
+	if maxReplicas := m.Vas.Spec.CustomAutoscaler.ScaledObject.MaxReplicas; maxReplicas != nil {
+		newSize = min(newSize, *maxReplicas)
+	}
+	if newSize == m.Vas.Spec.TargetSize {
+		return nil
+	}
+	oldSize := m.Vas.Spec.TargetSize
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		if err := m.VRec.Client.Get(ctx, client.ObjectKeyFromObject(m.Vas), m.Vas); err != nil {
+			return err
+		}
+		m.Vas.Spec.TargetSize = newSize
+		return m.VRec.Client.Update(ctx, m.Vas)
+	})
+	if err != nil {
+		return err
+	}
+	m.VRec.Eventf(m.Vas, corev1.EventTypeNormal, events.VerticaMetricScaling,
+		"Changed targetSize from %d to %d based on the vertica metrics", oldSize, newSize)
+	return nil
+}
+
+// getMinSize returns the minimum pod count, raised to the schedule floor if
+// one applies
+func (m *VerticaMetricReconciler) getMinSize() int32 {
+	minReplicas := m.Vas.GetScaledObjectMinReplicas()
+	if minReplicas == nil {
+		return 0
+	}
+	return *minReplicas
+}
+
+// getPollingInterval returns how long to wait before sampling the metrics again
+func (m *VerticaMetricReconciler) getPollingInterval() time.Duration {
+	pollingInterval := m.Vas.Spec.CustomAutoscaler.ScaledObject.PollingInterval
+	if pollingInterval == nil || *pollingInterval <= 0 {
+		return defaultVerticaMetricPollingInterval
+	}
+	return time.Duration(*pollingInterval) * time.Second
+}
+
+// setVerticaMetrics updates the status and keeps the in-memory copy in sync
+func (m *VerticaMetricReconciler) setVerticaMetrics(ctx context.Context, req *ctrl.Request,
+	samples []vapi.VerticaMetricStatus) error {
+	if err := vasstatus.SetVerticaMetrics(ctx, m.VRec.Client, m.Log, req, samples); err != nil {
+		return err
+	}
+	m.Vas.Status.VerticaMetrics = samples
+	return nil
+}
+
+// setScalingActive sets the condition that tells if the metrics can be sampled
+func (m *VerticaMetricReconciler) setScalingActive(ctx context.Context, req *ctrl.Request, status corev1.ConditionStatus) error {
+	cond := vapi.VerticaAutoscalerCondition{Type: vapi.ScalingActive, Status: status}
+	return vasstatus.UpdateCondition(ctx, m.VRec.Client, m.Log, req, cond)
+}
+
+// getDesiredSizeForMetric returns the pod count a single vertica metric asks
+// for. It follows the hpa algorithm: the current size is multiplied by the
+// ratio between the metric and its threshold. With AverageValue, the threshold
+// is per pod. If a scale in threshold is set, we only scale in once the metric
+// is below it, and then go all the way to the minimum.
+func getDesiredSizeForMetric(metric *vapi.ScaleTrigger, value int64, currentSize, minSize int32) int32 {
+	vm := metric.Vertica
+	if vm.ScaleInThreshold > 0 && value < int64(vm.ScaleInThreshold) {
+		return minSize
+	}
+	currentSize = max(currentSize, 1)
+	ratio := float64(value) / float64(vm.Threshold)
+	if metric.MetricType == autoscalingv2.AverageValueMetricType {
+		ratio /= float64(currentSize)
+	}
+	if math.Abs(ratio-1.0) <= verticaMetricTolerance {
+		return currentSize
+	}
+	desired := int32(math.Ceil(ratio * float64(currentSize)))
+	if vm.ScaleInThreshold > 0 && desired < currentSize {
+		return currentSize
+	}
+	return desired
+}
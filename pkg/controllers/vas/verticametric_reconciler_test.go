@@ -0,0 +1,116 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vas
+
+import (
+	"context"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	test "github.com/vertica/vertica-kubernetes/pkg/test"
+	"github.com/vertica/vertica-kubernetes/pkg/v1beta1_test"
+	"github.com/vertica/vertica-kubernetes/pkg/vdbstatus"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("verticametric_reconcile", func() {
+	ctx := context.Background()
+
+	makeVerticaMetricVAS := func() *vapi.VerticaAutoscaler {
+		vas := vapi.MakeVASWithScaledObject()
+		vas.Spec.TargetSize = 3
+		vas.Spec.CustomAutoscaler.ScaledObject.Metrics = []vapi.ScaleTrigger{
+			{
+				Name:       "queue",
+				Type:       vapi.VerticaTriggerType,
+				MetricType: autoscalingv2.ValueMetricType,
+				Vertica: &vapi.VerticaMetricSpec{
+					Metric:       vapi.VerticaMetricQueueLength,
+					ResourcePool: "general",
+					Threshold:    5,
+				},
+			},
+		}
+		return vas
+	}
+
+	It("should set the targetSize from the sampled metric", func() {
+		vdb := vapi.MakeVDB()
+		test.CreateVDB(ctx, k8sClient, vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+		Expect(vdbstatus.UpdateCondition(ctx, k8sClient, vdb,
+			vapi.MakeCondition(vapi.DBInitialized, metav1.ConditionTrue, "Initialized"))).Should(Succeed())
+
+		vas := makeVerticaMetricVAS()
+		v1beta1_test.CreateVAS(ctx, k8sClient, vas)
+		defer v1beta1_test.DeleteVAS(ctx, k8sClient, vas)
+		vas.Status.CurrentSize = 3
+
+		db, mock, err := sqlmock.New()
+		Expect(err).Should(Succeed())
+		defer db.Close()
+		mock.ExpectQuery("FROM v_monitor.resource_queues").WithArgs("general").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(10))
+
+		act := MakeVerticaMetricReconciler(vasRec, vas, logger, false)
+		r := act.(*VerticaMetricReconciler)
+		r.Conn = db
+		req := ctrl.Request{NamespacedName: vapi.MakeVASName()}
+		Expect(r.Reconcile(ctx, &req)).Should(Equal(ctrl.Result{}))
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+
+		fetchVas := &vapi.VerticaAutoscaler{}
+		Expect(k8sClient.Get(ctx, vapi.MakeVASName(), fetchVas)).Should(Succeed())
+		// Twice the threshold doubles the size, capped at maxReplicas
+		Expect(fetchVas.Spec.TargetSize).Should(Equal(int32(6)))
+		Expect(fetchVas.Status.VerticaMetrics).Should(HaveLen(1))
+		Expect(fetchVas.Status.VerticaMetrics[0].Value).Should(Equal(int64(10)))
+		Expect(fetchVas.Status.VerticaMetrics[0].DesiredSize).Should(Equal(int32(6)))
+		Expect(fetchVas.Status.Conditions[vapi.ScalingActiveIndex].Status).Should(Equal(corev1.ConditionTrue))
+	})
+
+	It("should requeue after the polling interval", func() {
+		vas := makeVerticaMetricVAS()
+		pollingInterval := int32(15)
+		vas.Spec.CustomAutoscaler.ScaledObject.PollingInterval = &pollingInterval
+		act := MakeVerticaMetricReconciler(vasRec, vas, logger, true)
+		req := ctrl.Request{NamespacedName: vapi.MakeVASName()}
+		Expect(act.Reconcile(ctx, &req)).Should(Equal(ctrl.Result{RequeueAfter: 15 * time.Second}))
+	})
+
+	It("should compute the desired size of a metric like the hpa", func() {
+		metric := &makeVerticaMetricVAS().Spec.CustomAutoscaler.ScaledObject.Metrics[0]
+		Expect(getDesiredSizeForMetric(metric, 10, 3, 1)).Should(Equal(int32(6)))
+		// Within the tolerance
+		Expect(getDesiredSizeForMetric(metric, 5, 3, 1)).Should(Equal(int32(3)))
+		Expect(getDesiredSizeForMetric(metric, 0, 3, 1)).Should(Equal(int32(0)))
+
+		// The threshold is per pod with AverageValue
+		metric.MetricType = autoscalingv2.AverageValueMetricType
+		Expect(getDesiredSizeForMetric(metric, 22, 3, 1)).Should(Equal(int32(5)))
+
+		// Between the scale in threshold and the threshold, the size doesn't change
+		metric.Vertica.ScaleInThreshold = 2
+		Expect(getDesiredSizeForMetric(metric, 4, 3, 1)).Should(Equal(int32(3)))
+		Expect(getDesiredSizeForMetric(metric, 1, 3, 1)).Should(Equal(int32(1)))
+	})
+})
@@ -0,0 +1,399 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vbackup
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	v1beta1 "github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/backup"
+	"github.com/vertica/vertica-kubernetes/pkg/cloud"
+	"github.com/vertica/vertica-kubernetes/pkg/cmds"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	verrors "github.com/vertica/vertica-kubernetes/pkg/errors"
+	"github.com/vertica/vertica-kubernetes/pkg/events"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/podfacts"
+	"github.com/vertica/vertica-kubernetes/pkg/vk8s"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	stateNotSupported     = "NotSupported"
+	stateNotReady         = "WaitingForDatabase"
+	stateWaitingForStop   = "WaitingForDatabaseStop"
+	stateBackingUp        = "BackingUp"
+	stateRestoring        = "Restoring"
+	stateComplete         = "Complete"
+	stateFailed           = "Failed"
+	dbNotReadyRequeueTime = 30 * time.Second
+)
+
+type BackupReconciler struct {
+	VRec    *VerticaBackupReconciler
+	Vbackup *v1beta1.VerticaBackup
+	Vdb     *vapi.VerticaDB
+	Log     logr.Logger
+	// The object store that snapshots are saved to. If nil, one is built
+	// from the spec. This is set by tests to mock the object store.
+	Store backup.Store
+	// The pod runner and pod facts. If nil, they are built when needed. These
+	// are set by tests to mock the pods.
+	PRunner cmds.PodRunner
+	PFacts  *podfacts.PodFacts
+}
+
+func MakeBackupReconciler(r *VerticaBackupReconciler, vbackup *v1beta1.VerticaBackup,
+	log logr.Logger) controllers.ReconcileActor {
+	return &BackupReconciler{
+		VRec:    r,
+		Vbackup: vbackup,
+		Log:     log.WithName("BackupReconciler"),
+	}
+}
+
+// Reconcile will run the backup or restore in stages, one pod per iteration.
+// The first iteration starts the operation and every one after it copies the
+// files of the next pending pod, requeueing until every pod is done. A
+// VerticaBackup that has already completed, successfully or not, is left
+// alone.
+func (b *BackupReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (ctrl.Result, error) {
+	if b.Vbackup.FindStatusCondition(v1beta1.BackupComplete) != nil {
+		return ctrl.Result{}, nil
+	}
+
+	b.Vdb = &vapi.VerticaDB{}
+	nm := names.GenNamespacedName(b.Vbackup, b.Vbackup.Spec.VerticaDBName)
+	if res, err := vk8s.FetchVDB(ctx, b.VRec, b.Vbackup, nm, b.Vdb); verrors.IsReconcileAborted(res, err) {
+		return res, err
+	}
+	// Eon mode databases keep their data in communal storage and should use
+	// restore points instead.
+	if b.Vdb.IsEON() {
+		if !b.Vbackup.IsStatusConditionFalse(v1beta1.BackupReady) {
+			b.VRec.Eventf(b.Vbackup, corev1.EventTypeWarning, events.BackupNotSupported,
+				"VerticaDB %q is an Eon mode database. VerticaBackup only supports Enterprise mode databases", b.Vdb.Name)
+		}
+		return ctrl.Result{}, b.setReadyCondition(ctx, metav1.ConditionFalse, "EonModeNotSupported", stateNotSupported)
+	}
+	if !b.Vdb.IsDBInitialized() {
+		return ctrl.Result{RequeueAfter: dbNotReadyRequeueTime},
+			b.setReadyCondition(ctx, metav1.ConditionFalse, "DatabaseNotReady", stateNotReady)
+	}
+
+	if err := b.initPodFacts(ctx); err != nil {
+		return ctrl.Result{}, err
+	}
+	pods, res, err := b.getPods(ctx)
+	if verrors.IsReconcileAborted(res, err) {
+		return res, err
+	}
+	if b.Store == nil {
+		store, err := b.makeStore(ctx)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		b.Store = store
+	}
+
+	if b.Vbackup.Status.StartTime == nil {
+		return b.start(ctx, pods)
+	}
+	return b.runNextPod(ctx, pods)
+}
+
+// initPodFacts will build the pod runner and collect the pod facts of the
+// main cluster, unless they were already set.
+func (b *BackupReconciler) initPodFacts(ctx context.Context) error {
+	if b.PFacts != nil {
+		return nil
+	}
+	password, err := vk8s.GetSuperuserPassword(ctx, b.VRec.Client, b.Log, b.VRec, b.Vdb)
+	if err != nil {
+		return err
+	}
+	if b.PRunner == nil {
+		b.PRunner = cmds.MakeClusterPodRunner(b.Log, b.VRec.Cfg, b.Vdb.GetVerticaUser(), password,
+			b.Vdb.IsClientServerTLSAuthEnabled())
+	}
+	pfacts := podfacts.MakePodFactsForSandboxWithCacheManager(b.VRec, b.PRunner, b.Log, password,
+		vapi.MainCluster, b.VRec.CacheManager)
+	if err := pfacts.Collect(ctx, b.Vdb); err != nil {
+		return err
+	}
+	b.PFacts = &pfacts
+	return nil
+}
+
+// getPods returns the pods to back up or restore, sorted by name. It will
+// requeue if the pods aren't in a state that allows the operation: a backup
+// needs every pod running and a restore needs vertica to be down everywhere.
+func (b *BackupReconciler) getPods(ctx context.Context) ([]*backup.Pod, ctrl.Result, error) {
+	pods := []*backup.Pod{}
+	for nm, pf := range b.PFacts.Detail {
+		if !pf.GetDBExists() {
+			continue
+		}
+		if !pf.GetIsPodRunning() {
+			b.Log.Info("Pod is not running, requeue", "pod", nm)
+			return nil, ctrl.Result{RequeueAfter: dbNotReadyRequeueTime}, nil
+		}
+		if b.Vbackup.IsRestore() && (pf.GetUpNode() || b.Vdb.Spec.AutoRestartVertica) {
+			if !b.Vbackup.IsStatusConditionFalse(v1beta1.BackupReady) {
+				b.VRec.Eventf(b.Vbackup, corev1.EventTypeWarning, events.RestoreRequiresStoppedDB,
+					"The database in VerticaDB %q must be stopped and autoRestartVertica must be false before a restore",
+					b.Vdb.Name)
+			}
+			return nil, ctrl.Result{RequeueAfter: dbNotReadyRequeueTime},
+				b.setReadyCondition(ctx, metav1.ConditionFalse, "DatabaseRunning", stateWaitingForStop)
+		}
+		pods = append(pods, &backup.Pod{Name: nm, VNodeName: pf.GetVnodeName(), ContainerName: pf.GetExecContainerName()})
+	}
+	if len(pods) == 0 {
+		b.Log.Info("No pods with a database found, requeue")
+		return nil, ctrl.Result{RequeueAfter: dbNotReadyRequeueTime}, nil
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name.Name < pods[j].Name.Name })
+	return pods, ctrl.Result{}, nil
+}
+
+// makeStore will build the object store client from the spec
+func (b *BackupReconciler) makeStore(ctx context.Context) (backup.Store, error) {
+	cfg := backup.S3Config{
+		Path:     b.Vbackup.Spec.Path,
+		Endpoint: b.Vbackup.Spec.Endpoint,
+		Region:   b.Vbackup.Spec.Region,
+	}
+	if b.Vbackup.Spec.CredentialSecret != "" {
+		fetcher := cloud.SecretFetcher{
+			Client:   b.VRec.Client,
+			Log:      b.Log,
+			Obj:      b.Vbackup,
+			EVWriter: b.VRec,
+		}
+		secret, err := fetcher.Fetch(ctx, names.GenNamespacedName(b.Vbackup, b.Vbackup.Spec.CredentialSecret))
+		if err != nil {
+			return nil, err
+		}
+		cfg.AccessKey = string(secret[cloud.CommunalAccessKeyName])
+		cfg.SecretKey = string(secret[cloud.CommunalSecretKeyName])
+	}
+	return backup.MakeS3Store(&cfg)
+}
+
+// start will begin the operation. For a backup, every pod is staged first so
+// that the snapshots are taken as close together as possible. The pods are
+// then recorded as pending in the status and copied in later iterations.
+func (b *BackupReconciler) start(ctx context.Context, pods []*backup.Pod) (ctrl.Result, error) {
+	snapshot := b.Vbackup.GetSnapshotName()
+	if b.Vbackup.IsRestore() {
+		b.VRec.Eventf(b.Vbackup, corev1.EventTypeNormal, events.RestoreStarted,
+			"Starting restore of snapshot %q to VerticaDB %q", snapshot, b.Vdb.Name)
+		return ctrl.Result{Requeue: true}, b.markStarted(ctx, stateRestoring, pods)
+	}
+
+	b.VRec.Eventf(b.Vbackup, corev1.EventTypeNormal, events.BackupStarted,
+		"Starting backup of VerticaDB %q to snapshot %q", b.Vdb.Name, snapshot)
+	bkp := b.makeBackuper()
+	for _, pod := range pods {
+		if err := bkp.Stage(ctx, pod); err != nil {
+			return ctrl.Result{}, b.failBackup(ctx, pods, err)
+		}
+	}
+	return ctrl.Result{Requeue: true}, b.markStarted(ctx, stateBackingUp, pods)
+}
+
+// runNextPod will copy the files of the first pod that is still pending and
+// requeue. Once no pod is pending, the operation is marked as complete.
+func (b *BackupReconciler) runNextPod(ctx context.Context, pods []*backup.Pod) (ctrl.Result, error) {
+	var node *v1beta1.VerticaBackupNodeStatus
+	for i := range b.Vbackup.Status.Nodes {
+		if b.Vbackup.Status.Nodes[i].State == v1beta1.BackupNodePending {
+			node = &b.Vbackup.Status.Nodes[i]
+			break
+		}
+	}
+	if node == nil {
+		return ctrl.Result{}, b.succeed(ctx)
+	}
+	var pod *backup.Pod
+	for _, p := range pods {
+		if p.Name.Name == node.PodName {
+			pod = p
+			break
+		}
+	}
+	if pod == nil {
+		b.Log.Info("Pending pod has no database, requeue", "pod", node.PodName)
+		return ctrl.Result{RequeueAfter: dbNotReadyRequeueTime}, nil
+	}
+
+	if b.Vbackup.IsRestore() {
+		rst := backup.Restorer{
+			Log:      b.Log,
+			PRunner:  b.PRunner,
+			Store:    b.Store,
+			Snapshot: b.Vbackup.GetSnapshotName(),
+		}
+		stats, err := rst.Restore(ctx, pod)
+		if err != nil {
+			b.VRec.Eventf(b.Vbackup, corev1.EventTypeWarning, events.RestoreFailed,
+				"Failed to restore snapshot %q to VerticaDB %q: %s", b.Vbackup.GetSnapshotName(), b.Vdb.Name, err)
+			return ctrl.Result{}, b.markCompleted(ctx, err)
+		}
+		return ctrl.Result{Requeue: true}, b.addNodeStats(ctx, stats)
+	}
+
+	stats, err := b.uploadPod(ctx, pod)
+	if err != nil {
+		return ctrl.Result{}, b.failBackup(ctx, pods, err)
+	}
+	return ctrl.Result{Requeue: true}, b.addNodeStats(ctx, stats)
+}
+
+// uploadPod will upload the staged snapshot of one pod. If the manifest of the
+// pod is already in the object store, the upload finished in an earlier
+// iteration that didn't get to update the status, so it isn't done again.
+func (b *BackupReconciler) uploadPod(ctx context.Context, pod *backup.Pod) (*backup.NodeStats, error) {
+	bkp := b.makeBackuper()
+	exists, err := b.Store.Exists(ctx, backup.ManifestKey(bkp.Snapshot, pod.Name.Name))
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		b.Log.Info("Snapshot of pod was already uploaded", "pod", pod.Name)
+		return &backup.NodeStats{Pod: *pod}, bkp.Cleanup(ctx, pod)
+	}
+	return bkp.Upload(ctx, pod)
+}
+
+// failBackup will remove the staged snapshots and record the failure
+func (b *BackupReconciler) failBackup(ctx context.Context, pods []*backup.Pod, errRun error) error {
+	bkp := b.makeBackuper()
+	for _, pod := range pods {
+		if err := bkp.Cleanup(ctx, pod); err != nil {
+			b.Log.Info("failed to cleanup the staged snapshot", "pod", pod.Name, "err", err)
+		}
+	}
+	b.VRec.Eventf(b.Vbackup, corev1.EventTypeWarning, events.BackupFailed,
+		"Failed to back up VerticaDB %q: %s", b.Vdb.Name, errRun)
+	return b.markCompleted(ctx, errRun)
+}
+
+// succeed will record that every pod was processed
+func (b *BackupReconciler) succeed(ctx context.Context) error {
+	snapshot := b.Vbackup.GetSnapshotName()
+	if b.Vbackup.IsRestore() {
+		b.VRec.Eventf(b.Vbackup, corev1.EventTypeNormal, events.RestoreSucceeded,
+			"Restored snapshot %q to VerticaDB %q, transferring %d of %d files", snapshot, b.Vdb.Name,
+			b.Vbackup.Status.TransferredFiles, b.Vbackup.Status.TotalFiles)
+	} else {
+		b.VRec.Eventf(b.Vbackup, corev1.EventTypeNormal, events.BackupSucceeded,
+			"Saved snapshot %q of VerticaDB %q, transferring %d of %d files", snapshot, b.Vdb.Name,
+			b.Vbackup.Status.TransferredFiles, b.Vbackup.Status.TotalFiles)
+	}
+	return b.markCompleted(ctx, nil)
+}
+
+// makeBackuper returns the Backuper for the snapshot of this VerticaBackup
+func (b *BackupReconciler) makeBackuper() *backup.Backuper {
+	return &backup.Backuper{
+		Log:      b.Log,
+		PRunner:  b.PRunner,
+		Store:    b.Store,
+		Snapshot: b.Vbackup.GetSnapshotName(),
+		VDBName:  b.Vdb.Name,
+		DBName:   b.Vdb.Spec.DBName,
+		Dirs:     []string{b.Vdb.GetDBCatalogPath(), b.Vdb.GetDBDataPath()},
+	}
+}
+
+// addNodeStats will record the progress of one pod in the status and mark it
+// as complete
+func (b *BackupReconciler) addNodeStats(ctx context.Context, stats *backup.NodeStats) error {
+	return vk8s.UpdateStatus(ctx, b.VRec.Client, b.Log, b.Vbackup, func(vbackup *v1beta1.VerticaBackup) error {
+		for i := range vbackup.Status.Nodes {
+			node := &vbackup.Status.Nodes[i]
+			if node.PodName != stats.Pod.Name.Name {
+				continue
+			}
+			node.State = v1beta1.BackupNodeComplete
+			node.TotalFiles = stats.TotalFiles
+			node.TransferredFiles = stats.TransferredFiles
+			node.TransferredBytes = stats.TransferredBytes
+			vbackup.Status.TotalFiles += stats.TotalFiles
+			vbackup.Status.TransferredFiles += stats.TransferredFiles
+			vbackup.Status.TransferredBytes += stats.TransferredBytes
+		}
+		return nil
+	})
+}
+
+// markStarted will record in the status that the operation has started, with
+// every pod pending
+func (b *BackupReconciler) markStarted(ctx context.Context, state string, pods []*backup.Pod) error {
+	return vk8s.UpdateStatus(ctx, b.VRec.Client, b.Log, b.Vbackup, func(vbackup *v1beta1.VerticaBackup) error {
+		vbackup.Status.State = state
+		vbackup.Status.SnapshotName = vbackup.GetSnapshotName()
+		vbackup.Status.StartTime = &metav1.Time{Time: time.Now().UTC()}
+		vbackup.Status.TotalFiles = 0
+		vbackup.Status.TransferredFiles = 0
+		vbackup.Status.TransferredBytes = 0
+		vbackup.Status.Nodes = make([]v1beta1.VerticaBackupNodeStatus, 0, len(pods))
+		for _, pod := range pods {
+			vbackup.Status.Nodes = append(vbackup.Status.Nodes, v1beta1.VerticaBackupNodeStatus{
+				PodName:   pod.Name.Name,
+				VNodeName: pod.VNodeName,
+				State:     v1beta1.BackupNodePending,
+			})
+		}
+		meta.SetStatusCondition(&vbackup.Status.Conditions,
+			*vapi.MakeCondition(v1beta1.BackupReady, metav1.ConditionTrue, "Ready"))
+		return nil
+	})
+}
+
+// markCompleted will record the outcome of the operation in the status. A
+// failure is not returned as an error because the operation isn't retried.
+func (b *BackupReconciler) markCompleted(ctx context.Context, errRun error) error {
+	return vk8s.UpdateStatus(ctx, b.VRec.Client, b.Log, b.Vbackup, func(vbackup *v1beta1.VerticaBackup) error {
+		vbackup.Status.CompletionTime = &metav1.Time{Time: time.Now().UTC()}
+		if errRun != nil {
+			vbackup.Status.State = stateFailed
+			cond := vapi.MakeCondition(v1beta1.BackupComplete, metav1.ConditionFalse, "Failed")
+			cond.Message = errRun.Error()
+			meta.SetStatusCondition(&vbackup.Status.Conditions, *cond)
+			return nil
+		}
+		vbackup.Status.State = stateComplete
+		meta.SetStatusCondition(&vbackup.Status.Conditions,
+			*vapi.MakeCondition(v1beta1.BackupComplete, metav1.ConditionTrue, "Succeeded"))
+		return nil
+	})
+}
+
+// setReadyCondition will update the BackupReady condition and the state
+func (b *BackupReconciler) setReadyCondition(ctx context.Context, status metav1.ConditionStatus, reason, state string) error {
+	return vk8s.UpdateConditions(ctx, b.VRec.Client, b.Log, b.Vbackup,
+		[]*metav1.Condition{vapi.MakeCondition(v1beta1.BackupReady, status, reason)}, state)
+}
@@ -0,0 +1,181 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vbackup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	v1beta1 "github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/backup"
+	"github.com/vertica/vertica-kubernetes/pkg/cmds"
+	"github.com/vertica/vertica-kubernetes/pkg/podfacts"
+	"github.com/vertica/vertica-kubernetes/pkg/test"
+	"github.com/vertica/vertica-kubernetes/pkg/vdbstatus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("backup_reconcile", func() {
+	ctx := context.Background()
+
+	createEnterpriseVDB := func() *vapi.VerticaDB {
+		vdb := vapi.MakeVDB()
+		vdb.Spec.ShardCount = 0
+		vdb.Spec.Subclusters[0].Size = 2
+		test.CreateVDB(ctx, k8sClient, vdb)
+		Expect(vdbstatus.UpdateCondition(ctx, k8sClient, vdb,
+			vapi.MakeCondition(vapi.DBInitialized, metav1.ConditionTrue, "Initialized"))).Should(Succeed())
+		return vdb
+	}
+
+	makePodFacts := func(vdb *vapi.VerticaDB, upNodes uint) *podfacts.PodFacts {
+		pfacts := podfacts.MakePodFacts(vbackupRec, &cmds.FakePodRunner{}, logger, nil)
+		pfacts.ConstructsDetail(vdb, []uint{upNodes})
+		for _, pf := range pfacts.Detail {
+			pf.SetDBExists(true)
+			pf.SetIsPodRunning(true)
+		}
+		return &pfacts
+	}
+
+	It("should not support Eon mode databases", func() {
+		vdb := vapi.MakeVDB()
+		test.CreateVDB(ctx, k8sClient, vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+
+		vbackup := v1beta1.MakeVbackup()
+		Expect(k8sClient.Create(ctx, vbackup)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vbackup)).Should(Succeed()) }()
+
+		recon := MakeBackupReconciler(vbackupRec, vbackup, logger)
+		Expect(recon.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(vbackup.IsStatusConditionFalse(v1beta1.BackupReady)).Should(BeTrue())
+		Expect(vbackup.Status.State).Should(Equal(stateNotSupported))
+	})
+
+	It("should wait for the database to stop before a restore", func() {
+		vdb := createEnterpriseVDB()
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+
+		vbackup := v1beta1.MakeVbackup()
+		vbackup.Spec.Operation = v1beta1.BackupOperationRestore
+		vbackup.Spec.SnapshotName = "snap1"
+		Expect(k8sClient.Create(ctx, vbackup)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vbackup)).Should(Succeed()) }()
+
+		recon := MakeBackupReconciler(vbackupRec, vbackup, logger)
+		recon.(*BackupReconciler).PFacts = makePodFacts(vdb, 1)
+		recon.(*BackupReconciler).Store = backup.MakeMemStore()
+		Expect(recon.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{RequeueAfter: dbNotReadyRequeueTime}))
+		Expect(vbackup.IsStatusConditionFalse(v1beta1.BackupReady)).Should(BeTrue())
+		Expect(vbackup.Status.State).Should(Equal(stateWaitingForStop))
+	})
+
+	It("should back up one pod per iteration and record the progress", func() {
+		vdb := createEnterpriseVDB()
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+
+		vbackup := v1beta1.MakeVbackup()
+		Expect(k8sClient.Create(ctx, vbackup)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vbackup)).Should(Succeed()) }()
+
+		pfacts := makePodFacts(vdb, 2)
+		fpr := &cmds.FakePodRunner{Results: cmds.CmdResults{}}
+		for nm := range pfacts.Detail {
+			content := fmt.Sprintf("data of %s", nm.Name)
+			fpr.Results[nm] = []cmds.CmdResult{
+				{}, // stage
+				{Stdout: fmt.Sprintf("%d ./v1.ros\n---\n%s  ./v1.ros\n", len(content), backup.HashContent([]byte(content)))},
+				{Stdout: content}, // upload of v1.ros
+			}
+		}
+		store := backup.MakeMemStore()
+		reconcile := func() ctrl.Result {
+			recon := MakeBackupReconciler(vbackupRec, vbackup, logger)
+			recon.(*BackupReconciler).PFacts = pfacts
+			recon.(*BackupReconciler).PRunner = fpr
+			recon.(*BackupReconciler).Store = store
+			res, err := recon.Reconcile(ctx, &ctrl.Request{})
+			Expect(err).Should(Succeed())
+			return res
+		}
+
+		// The first iteration stages every pod and marks them pending
+		Expect(reconcile()).Should(Equal(ctrl.Result{Requeue: true}))
+		Expect(vbackup.Status.State).Should(Equal(stateBackingUp))
+		Expect(vbackup.Status.Nodes).Should(HaveLen(2))
+		for i := range vbackup.Status.Nodes {
+			Expect(vbackup.Status.Nodes[i].State).Should(Equal(v1beta1.BackupNodePending))
+		}
+		Expect(store.Objects).Should(BeEmpty())
+
+		// Each following iteration uploads one pod
+		Expect(reconcile()).Should(Equal(ctrl.Result{Requeue: true}))
+		Expect(vbackup.Status.Nodes[0].State).Should(Equal(v1beta1.BackupNodeComplete))
+		Expect(vbackup.Status.Nodes[1].State).Should(Equal(v1beta1.BackupNodePending))
+		Expect(vbackup.Status.TransferredFiles).Should(Equal(1))
+		Expect(reconcile()).Should(Equal(ctrl.Result{Requeue: true}))
+		Expect(vbackup.Status.Nodes[1].State).Should(Equal(v1beta1.BackupNodeComplete))
+		Expect(vbackup.FindStatusCondition(v1beta1.BackupComplete)).Should(BeNil())
+
+		// The last iteration marks the backup as complete
+		Expect(reconcile()).Should(Equal(ctrl.Result{}))
+		Expect(vbackup.IsStatusConditionTrue(v1beta1.BackupComplete)).Should(BeTrue())
+		Expect(vbackup.Status.State).Should(Equal(stateComplete))
+		Expect(vbackup.Status.SnapshotName).Should(Equal(vbackup.Name))
+		Expect(vbackup.Status.TotalFiles).Should(Equal(2))
+		Expect(vbackup.Status.TransferredFiles).Should(Equal(2))
+		Expect(vbackup.Status.CompletionTime).ShouldNot(BeNil())
+		for nm := range pfacts.Detail {
+			Expect(store.Objects).Should(HaveKey(backup.ManifestKey(vbackup.Name, nm.Name)))
+		}
+
+		// A completed backup is not run again
+		putCount := store.PutCount
+		Expect(reconcile()).Should(Equal(ctrl.Result{}))
+		Expect(store.PutCount).Should(Equal(putCount))
+	})
+
+	It("should not upload a pod again if its manifest was already saved", func() {
+		vdb := createEnterpriseVDB()
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+
+		vbackup := v1beta1.MakeVbackup()
+		Expect(k8sClient.Create(ctx, vbackup)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vbackup)).Should(Succeed()) }()
+
+		pfacts := makePodFacts(vdb, 2)
+		store := backup.MakeMemStore()
+		recon := MakeBackupReconciler(vbackupRec, vbackup, logger)
+		recon.(*BackupReconciler).PFacts = pfacts
+		recon.(*BackupReconciler).PRunner = &cmds.FakePodRunner{}
+		recon.(*BackupReconciler).Store = store
+		Expect(recon.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{Requeue: true}))
+
+		pod := vbackup.Status.Nodes[0].PodName
+		Expect(store.Put(ctx, backup.ManifestKey(vbackup.Name, pod), strings.NewReader("{}"))).Should(Succeed())
+		putCount := store.PutCount
+		Expect(recon.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{Requeue: true}))
+		Expect(vbackup.Status.Nodes[0].State).Should(Equal(v1beta1.BackupNodeComplete))
+		Expect(vbackup.Status.Nodes[0].TransferredFiles).Should(Equal(0))
+		Expect(store.PutCount).Should(Equal(putCount))
+	})
+})
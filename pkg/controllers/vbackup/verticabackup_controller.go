@@ -0,0 +1,206 @@
+/*
+Copyright [2021-2024] Open Text.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vbackup
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/go-logr/logr"
+	v1vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/cache"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	verrors "github.com/vertica/vertica-kubernetes/pkg/errors"
+	"github.com/vertica/vertica-kubernetes/pkg/events"
+	"github.com/vertica/vertica-kubernetes/pkg/meta"
+)
+
+const (
+	vdbNameField = ".spec.verticaDBName"
+)
+
+// VerticaBackupReconciler reconciles a VerticaBackup object
+type VerticaBackupReconciler struct {
+	client.Client
+	Scheme       *runtime.Scheme
+	Log          logr.Logger
+	Cfg          *rest.Config
+	EVRec        record.EventRecorder
+	Concurrency  int
+	CacheManager cache.CacheManager
+}
+
+// +kubebuilder:rbac:groups=vertica.com,resources=verticabackups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=vertica.com,resources=verticabackups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=vertica.com,resources=verticabackups/finalizers,verbs=update
+
+// Reconcile will run the backup or restore described by the VerticaBackup. Each
+// VerticaBackup runs once; its outcome is recorded in the status.
+func (r *VerticaBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("vbackup", req.NamespacedName)
+	log.Info("starting reconcile of VerticaBackup")
+
+	vbackup := &vapi.VerticaBackup{}
+	err := r.Get(ctx, req.NamespacedName, vbackup)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, cound have been deleted after reconcile request.
+			log.Info("VerticaBackup resource not found.  Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "failed to get VerticaBackup")
+		return ctrl.Result{}, err
+	}
+
+	if meta.IsPauseAnnotationSet(vbackup.Annotations) {
+		log.Info(fmt.Sprintf("The pause annotation %s is set. Suspending the iteration", meta.PauseOperatorAnnotation),
+			"result", ctrl.Result{}, "err", nil)
+		return ctrl.Result{}, nil
+	}
+
+	// Iterate over each actor
+	actors := r.constructActors(vbackup, log)
+	var res ctrl.Result
+	for _, act := range actors {
+		log.Info("starting actor", "name", fmt.Sprintf("%T", act))
+		res, err = act.Reconcile(ctx, &req)
+		// Error or a request to requeue will stop the reconciliation.
+		if verrors.IsReconcileAborted(res, err) {
+			log.Info("aborting reconcile of VerticaBackup", "result", res, "err", err)
+			return res, err
+		}
+	}
+
+	log.Info("ending reconcile of VerticaBackup", "result", res, "err", err)
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *VerticaBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := r.setupFieldIndexer(mgr.GetFieldIndexer()); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&vapi.VerticaBackup{}).
+		// Watch the VerticaDB so that a pending restore is retried as soon as
+		// autoRestartVertica is turned off.
+		Watches(
+			&v1vapi.VerticaDB{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForVerticaDB),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.Concurrency}).
+		Complete(r)
+}
+
+// setupFieldIndexer will setup an index over the VerticaDB name. This allows
+// us to lookup the backups that refer to a VerticaDB.
+func (r *VerticaBackupReconciler) setupFieldIndexer(indx client.FieldIndexer) error {
+	return indx.IndexField(context.Background(), &vapi.VerticaBackup{}, vdbNameField,
+		func(rawObj client.Object) []string {
+			return []string{rawObj.(*vapi.VerticaBackup).Spec.VerticaDBName}
+		})
+}
+
+// findObjectsForVerticaDB will generate requests to reconcile
+// VerticaBackups based on watched VerticaDB.
+func (r *VerticaBackupReconciler) findObjectsForVerticaDB(ctx context.Context,
+	vdb client.Object) []reconcile.Request {
+	backups := &vapi.VerticaBackupList{}
+	listOps := &client.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector(vdbNameField, vdb.GetName()),
+		Namespace:     vdb.GetNamespace(),
+	}
+	err := r.List(ctx, backups, listOps)
+	if err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, len(backups.Items))
+	for i := range backups.Items {
+		requests[i] = reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      backups.Items[i].GetName(),
+				Namespace: backups.Items[i].GetNamespace(),
+			},
+		}
+	}
+	return requests
+}
+
+// constructActors will a list of actors that should be run for the reconcile.
+// Order matters in that some actors depend on the successeful execution of
+// earlier ones.
+func (r *VerticaBackupReconciler) constructActors(vbackup *vapi.VerticaBackup,
+	log logr.Logger) []controllers.ReconcileActor {
+	// The actors that will be applied, in sequence, to reconcile a vbackup.
+	actors := []controllers.ReconcileActor{
+		// Take or restore the snapshot
+		MakeBackupReconciler(r, vbackup, log),
+	}
+	return actors
+}
+
+// Event a wrapper for Event() that also writes a log entry
+func (r *VerticaBackupReconciler) Event(vbackup runtime.Object, eventtype, reason, message string) {
+	evWriter := events.Writer{
+		Log:   r.Log,
+		EVRec: r.EVRec,
+	}
+	evWriter.Event(vbackup, eventtype, reason, message)
+}
+
+// Eventf is a wrapper for Eventf() that also writes a log entry
+func (r *VerticaBackupReconciler) Eventf(vbackup runtime.Object, eventtype, reason, messageFmt string,
+	args ...interface{}) {
+	evWriter := events.Writer{
+		Log:   r.Log,
+		EVRec: r.EVRec,
+	}
+	evWriter.Eventf(vbackup, eventtype, reason, messageFmt, args...)
+}
+
+// GetClient gives access to the Kubernetes client
+func (r *VerticaBackupReconciler) GetClient() client.Client {
+	return r.Client
+}
+
+// GetEventRecorder gives access to the event recorder
+func (r *VerticaBackupReconciler) GetEventRecorder() record.EventRecorder {
+	return r.EVRec
+}
+
+// GetConfig gives access to *rest.Config
+func (r *VerticaBackupReconciler) GetConfig() *rest.Config {
+	return r.Cfg
+}
@@ -17,20 +17,45 @@ package vdb
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
 	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/pkg/builder"
+	"github.com/vertica/vertica-kubernetes/pkg/cloud"
+	verrors "github.com/vertica/vertica-kubernetes/pkg/errors"
 	"github.com/vertica/vertica-kubernetes/pkg/events"
 	vmeta "github.com/vertica/vertica-kubernetes/pkg/meta"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/security"
+	"github.com/vertica/vertica-kubernetes/pkg/vdbstatus"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/util/retry"
 
 	"github.com/vertica/vertica-kubernetes/pkg/controllers"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const (
+	// How long to wait before issuing a certificate again after the rotation to it failed
+	autoIssueRetryInterval = time.Hour
+	// How often to check whether cert-manager has issued a requested certificate
+	certManagerPollInterval = 10 * time.Second
+)
+
+var certManagerCertificateGVK = schema.GroupVersionKind{
+	Group:   vapi.CertManagerGroup,
+	Version: "v1",
+	Kind:    "Certificate",
+}
+
 type AutoCertRotateReconciler struct {
 	VRec *VerticaDBReconciler
 	Vdb  *vapi.VerticaDB // Vdb is the CRD we are acting on.
@@ -85,11 +110,29 @@ func (r *AutoCertRotateReconciler) autoRotateByTLSConfig(ctx context.Context, tl
 		})
 	}
 
+	// If user has cleared autoRotate.issue from spec, remove the expiry fields from status
+	if r.Vdb.GetTLSConfigAutoIssue(tlsConfig) == nil && r.Vdb.GetTLSConfigByName(tlsConfig) != nil &&
+		r.Vdb.GetTLSConfigByName(tlsConfig).NextRotation != nil {
+		r.Log.Info("autoRotate.issue has been removed from spec; clearing status fields", "tlsConfig", tlsConfig)
+		return ctrl.Result{}, r.updateTLSStatus(ctx, tlsConfig, func(status *vapi.TLSConfigStatus) {
+			status.CertExpiry = nil
+			status.NextRotation = nil
+		})
+	}
+
 	// no-op if auto-rotate disabled
 	if !r.Vdb.IsAutoCertRotationEnabled(tlsConfig) {
 		return ctrl.Result{}, nil
 	}
 
+	// The operator issues the certificates itself; there is no secret list to initialize.
+	if r.Vdb.IsAutoCertIssueEnabled(tlsConfig) {
+		if r.Init {
+			return ctrl.Result{}, nil
+		}
+		return r.autoIssueByTLSConfig(ctx, tlsConfig)
+	}
+
 	// If next update is not set, no auto-rotate is scheduled. This is likely right after auto-rotate has been
 	// first set up. So, set first secret and configure status.
 	nextUpdate := r.Vdb.GetTLSNextUpdate(tlsConfig)
@@ -306,3 +349,294 @@ func (r *AutoCertRotateReconciler) updateTLSStatus(
 		return r.VRec.Client.Status().Update(ctx, r.Vdb)
 	})
 }
+
+// autoIssueByTLSConfig handles auto-rotation when the operator issues the
+// certificates. It records the expiry of the certificate in use and, once the
+// renewal time has passed, issues a replacement and rotates to it. Otherwise it
+// requeues for the renewal time.
+func (r *AutoCertRotateReconciler) autoIssueByTLSConfig(ctx context.Context, tlsConfig string) (ctrl.Result, error) {
+	// Wait until the secret in the spec is the one in use. Either TLS hasn't
+	// been set up yet, or a rotation is still in progress.
+	current := r.Vdb.GetSecretInUse(tlsConfig)
+	if current == "" || current != r.Vdb.GetTLSConfigSpecByName(tlsConfig).Secret {
+		return ctrl.Result{}, nil
+	}
+
+	certData, res, err := r.fetchSecret(ctx, current)
+	if verrors.IsReconcileAborted(res, err) {
+		return res, err
+	}
+	cert, err := security.DecodeCertificate(certData[corev1.TLSCertKey])
+	if err != nil {
+		r.VRec.Eventf(r.Vdb, corev1.EventTypeWarning, events.TLSAutoRotateFailed,
+			"Cannot read the certificate in secret %s for TLS config %s: %s", current, tlsConfig, err)
+		return ctrl.Result{}, nil
+	}
+	nextRotation := r.Vdb.GetTLSConfigAutoIssue(tlsConfig).GetCertRenewalTime(cert.NotBefore, cert.NotAfter)
+	if err := r.setCertExpiryStatus(ctx, tlsConfig, cert.NotAfter, nextRotation); err != nil {
+		return ctrl.Result{}, err
+	}
+	newSecret := r.genIssuedSecretName(tlsConfig, nextRotation)
+	if err := r.deleteReplacedIssuedSecrets(ctx, tlsConfig, current, newSecret); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Since this can take a long time, for testing purposes, the annotation
+	// triggers the rotation now.
+	if r.Vdb.Annotations[vmeta.TriggerAutoTLSRotateAnnotation] == "" && time.Until(nextRotation) > 0 {
+		return ctrl.Result{RequeueAfter: time.Until(nextRotation)}, nil
+	}
+
+	// If we already rotated to this certificate and it failed, the old one was
+	// restored. We wait a while so that a bad issuer doesn't cause a rotation
+	// loop, then issue it again.
+	if r.Vdb.GetTLSConfigByName(tlsConfig).AutoRotateFailedSecret == newSecret {
+		retryAt := r.Vdb.GetTLSLastUpdate(tlsConfig).Add(autoIssueRetryInterval)
+		if time.Until(retryAt) > 0 {
+			return ctrl.Result{RequeueAfter: time.Until(retryAt)}, nil
+		}
+		r.Log.Info("Previous TLS rotation with issued secret failed; issuing it again",
+			"failedSecret", newSecret, "tlsConfig", tlsConfig)
+		if err := r.deleteIssuedSecret(ctx, tlsConfig, newSecret); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	ready, err := r.issueCertificate(ctx, tlsConfig, newSecret)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !ready {
+		return ctrl.Result{RequeueAfter: certManagerPollInterval}, nil
+	}
+	return r.rotateToIssuedSecret(ctx, tlsConfig, newSecret)
+}
+
+// genIssuedSecretName returns the name of the secret that replaces the
+// certificate due at renewAt. It is deterministic so that a certificate still
+// being issued by cert-manager is picked up in a later reconcile.
+func (r *AutoCertRotateReconciler) genIssuedSecretName(tlsConfig string, renewAt time.Time) string {
+	return fmt.Sprintf("%s%d", r.genIssuedSecretPrefix(tlsConfig), renewAt.Unix())
+}
+
+// genIssuedSecretPrefix returns the prefix shared by the names of all of the
+// secrets issued for a TLS config
+func (r *AutoCertRotateReconciler) genIssuedSecretPrefix(tlsConfig string) string {
+	kind := "https"
+	if tlsConfig == vapi.ClientServerTLSConfigName {
+		kind = "clientserver"
+	}
+	return fmt.Sprintf("%s-%s-tls-", r.Vdb.Name, kind)
+}
+
+// deleteReplacedIssuedSecrets removes the secrets issued for a TLS config that
+// are no longer needed. It is called once the rotation to the current secret
+// has succeeded, so only the current secret and the one being issued to
+// replace it are kept.
+func (r *AutoCertRotateReconciler) deleteReplacedIssuedSecrets(ctx context.Context, tlsConfig, current, next string) error {
+	secrets := corev1.SecretList{}
+	if err := r.VRec.Client.List(ctx, &secrets, client.InNamespace(r.Vdb.Namespace),
+		client.MatchingLabels(builder.MakeOperatorLabels(r.Vdb))); err != nil {
+		return err
+	}
+	prefix := r.genIssuedSecretPrefix(tlsConfig)
+	for i := range secrets.Items {
+		name := secrets.Items[i].Name
+		if !strings.HasPrefix(name, prefix) || name == current || name == next {
+			continue
+		}
+		r.Log.Info("Deleting TLS secret replaced by auto-rotation", "secret", name, "tlsConfig", tlsConfig)
+		if err := r.deleteIssuedSecret(ctx, tlsConfig, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// issueCertificate creates the secret for a replacement certificate if it
+// doesn't exist yet. It returns true once the secret has a certificate in it.
+func (r *AutoCertRotateReconciler) issueCertificate(ctx context.Context, tlsConfig, secretName string) (bool, error) {
+	secret := corev1.Secret{}
+	err := r.VRec.Client.Get(ctx, names.GenNamespacedName(r.Vdb, secretName), &secret)
+	if err == nil {
+		return len(secret.Data[corev1.TLSCertKey]) > 0, nil
+	}
+	if !kerrors.IsNotFound(err) {
+		return false, err
+	}
+
+	issue := r.Vdb.GetTLSConfigAutoIssue(tlsConfig)
+	if issue.IssuerRef != nil {
+		return false, r.requestCertManagerCertificate(ctx, tlsConfig, secretName, issue.IssuerRef)
+	}
+	return r.signWithCASecret(ctx, tlsConfig, secretName, issue.CASecret)
+}
+
+// signWithCASecret issues a certificate signed by the CA in caSecret and
+// stores it in a new secret. It returns false if the CA secret isn't there yet.
+func (r *AutoCertRotateReconciler) signWithCASecret(ctx context.Context, tlsConfig, secretName, caSecret string) (bool, error) {
+	caData, res, err := r.fetchSecret(ctx, caSecret)
+	if verrors.IsReconcileAborted(res, err) {
+		return false, err
+	}
+	ca, err := security.NewCertificateFromPEM(caData[corev1.TLSCertKey], caData[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		r.VRec.Eventf(r.Vdb, corev1.EventTypeWarning, events.TLSAutoRotateFailed,
+			"Cannot issue a certificate for TLS config %s with the CA in secret %s: %s", tlsConfig, caSecret, err)
+		return false, err
+	}
+	cert, err := security.NewCertificateWithIPs(ca, r.Vdb.GetExpectedCertCommonName(tlsConfig),
+		security.GetDNSNames(r.Vdb.Namespace), nil)
+	if err != nil {
+		return false, err
+	}
+	secret := security.GenSecret(secretName, r.Vdb.Namespace, cert, ca)
+	secret.Annotations = builder.MakeAnnotationsForObject(r.Vdb)
+	secret.Labels = builder.MakeCommonLabels(r.Vdb, nil, false, false)
+	secret.OwnerReferences = []v1.OwnerReference{r.Vdb.GenerateOwnerReference()}
+	if err := r.VRec.Client.Create(ctx, secret); err != nil {
+		return false, err
+	}
+	r.VRec.Eventf(r.Vdb, corev1.EventTypeNormal, events.TLSCertIssued,
+		"Issued a certificate for TLS config %s in secret %s", tlsConfig, secretName)
+	return true, nil
+}
+
+// requestCertManagerCertificate creates a cert-manager Certificate that writes
+// the replacement certificate to secretName. cert-manager fills in the secret
+// asynchronously, so the caller must poll for it.
+func (r *AutoCertRotateReconciler) requestCertManagerCertificate(ctx context.Context, tlsConfig, secretName string,
+	issuerRef *vapi.CertManagerIssuerRef) error {
+	cert := r.makeCertManagerCertificate(secretName)
+	err := unstructured.SetNestedField(cert.Object, map[string]interface{}{
+		"secretName": secretName,
+		"commonName": r.Vdb.GetExpectedCertCommonName(tlsConfig),
+		"usages":     []interface{}{"server auth", "client auth", "digital signature", "key encipherment"},
+		"privateKey": map[string]interface{}{
+			"algorithm": "RSA",
+			"encoding":  "PKCS1",
+			"size":      int64(2048),
+		},
+		"issuerRef": map[string]interface{}{
+			"name":  issuerRef.Name,
+			"kind":  issuerRef.GetIssuerKind(),
+			"group": issuerRef.GetIssuerGroup(),
+		},
+	}, "spec")
+	if err != nil {
+		return err
+	}
+	if err := unstructured.SetNestedStringSlice(cert.Object, security.GetDNSNames(r.Vdb.Namespace), "spec", "dnsNames"); err != nil {
+		return err
+	}
+	if err := unstructured.SetNestedStringMap(cert.Object, builder.MakeCommonLabels(r.Vdb, nil, false, false),
+		"spec", "secretTemplate", "labels"); err != nil {
+		return err
+	}
+
+	err = r.VRec.Client.Create(ctx, cert)
+	if kerrors.IsAlreadyExists(err) {
+		r.Log.Info("Waiting for cert-manager to issue the certificate", "secret", secretName, "tlsConfig", tlsConfig)
+		return nil
+	}
+	if err != nil {
+		r.VRec.Eventf(r.Vdb, corev1.EventTypeWarning, events.TLSAutoRotateFailed,
+			"Cannot request a certificate from cert-manager %s %s for TLS config %s: %s",
+			issuerRef.GetIssuerKind(), issuerRef.Name, tlsConfig, err)
+		return err
+	}
+	r.VRec.Eventf(r.Vdb, corev1.EventTypeNormal, events.TLSCertIssued,
+		"Requested a certificate for TLS config %s from cert-manager %s %s in secret %s",
+		tlsConfig, issuerRef.GetIssuerKind(), issuerRef.Name, secretName)
+	return nil
+}
+
+// makeCertManagerCertificate returns a cert-manager Certificate object with
+// only the metadata filled in.
+func (r *AutoCertRotateReconciler) makeCertManagerCertificate(name string) *unstructured.Unstructured {
+	cert := &unstructured.Unstructured{}
+	cert.SetGroupVersionKind(certManagerCertificateGVK)
+	cert.SetName(name)
+	cert.SetNamespace(r.Vdb.Namespace)
+	cert.SetLabels(builder.MakeCommonLabels(r.Vdb, nil, false, false))
+	cert.SetOwnerReferences([]v1.OwnerReference{r.Vdb.GenerateOwnerReference()})
+	return cert
+}
+
+// deleteCertManagerCertificate removes the cert-manager Certificate for an
+// issued secret. This stops cert-manager from renewing the secret in place,
+// as the operator rotates to a new secret for each renewal.
+func (r *AutoCertRotateReconciler) deleteCertManagerCertificate(ctx context.Context, tlsConfig, name string) error {
+	if r.Vdb.GetTLSConfigAutoIssue(tlsConfig).IssuerRef == nil {
+		return nil
+	}
+	err := r.VRec.Client.Delete(ctx, r.makeCertManagerCertificate(name))
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// deleteIssuedSecret removes a secret the operator issued, along with its
+// cert-manager Certificate, so that it can be issued again.
+func (r *AutoCertRotateReconciler) deleteIssuedSecret(ctx context.Context, tlsConfig, name string) error {
+	if err := r.deleteCertManagerCertificate(ctx, tlsConfig, name); err != nil {
+		return err
+	}
+	secret := &corev1.Secret{}
+	secret.SetName(name)
+	secret.SetNamespace(r.Vdb.Namespace)
+	err := r.VRec.Client.Delete(ctx, secret)
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// rotateToIssuedSecret updates the secret in the VDB spec to the issued one,
+// which triggers the cert rotation on the next iteration.
+func (r *AutoCertRotateReconciler) rotateToIssuedSecret(ctx context.Context, tlsConfig, secretName string) (ctrl.Result, error) {
+	if err := r.deleteCertManagerCertificate(ctx, tlsConfig, secretName); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.updateTLSSecretSpec(ctx, tlsConfig, secretName); err != nil {
+		r.Log.Error(err, "Failed to update VerticaDB spec during rotate", "tlsConfig", tlsConfig)
+		return ctrl.Result{}, err
+	}
+	if err := r.updateTLSStatus(ctx, tlsConfig, func(status *vapi.TLSConfigStatus) {
+		status.AutoRotateSecrets = nil
+		status.LastUpdate = v1.NewTime(time.Now())
+	}); err != nil {
+		r.Log.Error(err, "Failed to update VerticaDB status during rotate", "tlsConfig", tlsConfig)
+		return ctrl.Result{}, err
+	}
+
+	r.VRec.Eventf(r.Vdb, corev1.EventTypeNormal, events.TLSAutoRotateSucceeded,
+		"Auto-rotate triggered for TLS config %s with issued secret %s", tlsConfig, secretName)
+	return ctrl.Result{}, nil
+}
+
+// setCertExpiryStatus records the expiry and the renewal time of the
+// certificate in use. The status is only written if either has changed.
+func (r *AutoCertRotateReconciler) setCertExpiryStatus(ctx context.Context, tlsConfig string,
+	certExpiry, nextRotation time.Time) error {
+	status := r.Vdb.GetTLSConfigByName(tlsConfig)
+	newStatus := status.DeepCopy()
+	newStatus.CertExpiry = &v1.Time{Time: certExpiry}
+	newStatus.NextRotation = &v1.Time{Time: nextRotation}
+	if newStatus.CertExpiry.Equal(status.CertExpiry) && newStatus.NextRotation.Equal(status.NextRotation) {
+		return nil
+	}
+	return vdbstatus.UpdateTLSConfigs(ctx, r.VRec.Client, r.Vdb, []*vapi.TLSConfigStatus{newStatus})
+}
+
+// fetchSecret reads a secret from any of the supported secret stores
+func (r *AutoCertRotateReconciler) fetchSecret(ctx context.Context, secretName string) (map[string][]byte, ctrl.Result, error) {
+	fetcher := cloud.SecretFetcher{
+		Client:   r.VRec.GetClient(),
+		Log:      r.Log,
+		Obj:      r.Vdb,
+		EVWriter: r.VRec,
+	}
+	return fetcher.FetchAllowRequeue(ctx, names.GenNamespacedName(r.Vdb, secretName))
+}
@@ -0,0 +1,137 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vdb
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/pkg/builder"
+	vmeta "github.com/vertica/vertica-kubernetes/pkg/meta"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/paths"
+	"github.com/vertica/vertica-kubernetes/pkg/security"
+	"github.com/vertica/vertica-kubernetes/pkg/test"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("autocertrotate_reconcile", func() {
+	ctx := context.Background()
+
+	It("should issue a replacement certificate signed by the CA secret", func() {
+		const caSecretName = "autoissue-ca"
+		const currentSecretName = "autoissue-current"
+		vdb := vapi.MakeVDB()
+		vapi.SetVDBWithHTTPSTLSConfigSet(vdb, currentSecretName)
+		vdb.Spec.HTTPSNMATLS.Secret = currentSecretName
+		vdb.Spec.HTTPSNMATLS.AutoRotate = &vapi.TLSAutoRotate{
+			Issue: &vapi.TLSCertIssue{CASecret: caSecretName, RenewAtLifetimePercent: 50},
+		}
+		status := vdb.Status
+		test.CreateVDB(ctx, k8sClient, vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+		vdb.Status = status
+		Expect(k8sClient.Status().Update(ctx, vdb)).Should(Succeed())
+
+		ca, err := security.NewSelfSignedCACertificate()
+		Expect(err).Should(Succeed())
+		cert, err := security.NewCertificate(ca, vdb.GetVerticaUser(), security.GetDNSNames(vdb.Namespace))
+		Expect(err).Should(Succeed())
+		for _, secret := range []*corev1.Secret{
+			security.GenSecret(caSecretName, vdb.Namespace, ca, ca),
+			security.GenSecret(currentSecretName, vdb.Namespace, cert, ca),
+		} {
+			Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+			defer deleteSecret(ctx, vdb, secret.Name)
+		}
+
+		// The certificate is new, so we wait until half of its lifetime has passed
+		r := MakeAutoCertRotateReconciler(vdbRec, logger, vdb, false)
+		res, err := r.Reconcile(ctx, &ctrl.Request{})
+		Expect(err).Should(Succeed())
+		Expect(res.RequeueAfter).Should(BeNumerically(">", 24*time.Hour))
+		tlsStatus := vdb.GetTLSConfigByName(vapi.HTTPSNMATLSConfigName)
+		Expect(tlsStatus.CertExpiry).ShouldNot(BeNil())
+		Expect(tlsStatus.NextRotation).ShouldNot(BeNil())
+		Expect(tlsStatus.NextRotation.Before(tlsStatus.CertExpiry)).Should(BeTrue())
+		Expect(vdb.Spec.HTTPSNMATLS.Secret).Should(Equal(currentSecretName))
+
+		// Force the rotation now
+		vdb.Annotations[vmeta.TriggerAutoTLSRotateAnnotation] = "true"
+		Expect(k8sClient.Update(ctx, vdb)).Should(Succeed())
+		h := r.(*AutoCertRotateReconciler)
+		newSecretName := h.genIssuedSecretName(vapi.HTTPSNMATLSConfigName, tlsStatus.NextRotation.Time)
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		defer deleteSecret(ctx, vdb, newSecretName)
+		Expect(vdb.Spec.HTTPSNMATLS.Secret).Should(Equal(newSecretName))
+		Expect(vdb.GetTLSConfigByName(vapi.HTTPSNMATLSConfigName).LastUpdate.IsZero()).Should(BeFalse())
+
+		newSecret := &corev1.Secret{}
+		Expect(k8sClient.Get(ctx, names.GenNamespacedName(vdb, newSecretName), newSecret)).Should(Succeed())
+		Expect(security.ValidateCertificateCommonName(newSecret.Data[corev1.TLSCertKey], vdb.GetVerticaUser())).Should(Succeed())
+		Expect(newSecret.Data[paths.HTTPServerCACrtName]).Should(Equal(ca.TLSCrt()))
+	})
+
+	It("should delete the issued secret replaced by a successful rotation", func() {
+		const caSecretName = "autoissue-ca"
+		vdb := vapi.MakeVDB()
+		vdb.Spec.HTTPSNMATLS.AutoRotate = &vapi.TLSAutoRotate{
+			Issue: &vapi.TLSCertIssue{CASecret: caSecretName, RenewAtLifetimePercent: 50},
+		}
+		r := MakeAutoCertRotateReconciler(vdbRec, logger, vdb, false)
+		h := r.(*AutoCertRotateReconciler)
+		replacedSecretName := h.genIssuedSecretName(vapi.HTTPSNMATLSConfigName, time.Unix(1000, 0))
+		currentSecretName := h.genIssuedSecretName(vapi.HTTPSNMATLSConfigName, time.Unix(2000, 0))
+		vapi.SetVDBWithHTTPSTLSConfigSet(vdb, currentSecretName)
+		vdb.Spec.HTTPSNMATLS.Secret = currentSecretName
+		status := vdb.Status
+		test.CreateVDB(ctx, k8sClient, vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+		vdb.Status = status
+		Expect(k8sClient.Status().Update(ctx, vdb)).Should(Succeed())
+
+		ca, err := security.NewSelfSignedCACertificate()
+		Expect(err).Should(Succeed())
+		cert, err := security.NewCertificate(ca, vdb.GetVerticaUser(), security.GetDNSNames(vdb.Namespace))
+		Expect(err).Should(Succeed())
+		for _, secret := range []*corev1.Secret{
+			security.GenSecret(caSecretName, vdb.Namespace, ca, ca),
+			security.GenSecret(currentSecretName, vdb.Namespace, cert, ca),
+			security.GenSecret(replacedSecretName, vdb.Namespace, cert, ca),
+		} {
+			if secret.Name != caSecretName {
+				secret.Labels = builder.MakeCommonLabels(vdb, nil, false, false)
+			}
+			Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+		}
+		defer deleteSecret(ctx, vdb, caSecretName)
+		defer deleteSecret(ctx, vdb, currentSecretName)
+
+		res, err := r.Reconcile(ctx, &ctrl.Request{})
+		Expect(err).Should(Succeed())
+		Expect(res.RequeueAfter).Should(BeNumerically(">", 0))
+		secret := &corev1.Secret{}
+		err = k8sClient.Get(ctx, names.GenNamespacedName(vdb, replacedSecretName), secret)
+		Expect(kerrors.IsNotFound(err)).Should(BeTrue())
+		Expect(k8sClient.Get(ctx, names.GenNamespacedName(vdb, currentSecretName), secret)).Should(Succeed())
+		Expect(k8sClient.Get(ctx, names.GenNamespacedName(vdb, caSecretName), secret)).Should(Succeed())
+	})
+})
@@ -0,0 +1,259 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/pkg/cloud"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	"github.com/vertica/vertica-kubernetes/pkg/events"
+	vmeta "github.com/vertica/vertica-kubernetes/pkg/meta"
+	"github.com/vertica/vertica-kubernetes/pkg/metrics"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/security"
+	"github.com/vertica/vertica-kubernetes/pkg/vdbstatus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	// Names used for the TLS secrets that don't have a tls config
+	nmaTLSConfigName   = "nma"
+	proxyTLSConfigName = "proxy"
+
+	certExpiryDateFormat = "2006-01-02"
+)
+
+// CertExpiryReconciler reports how long the certificates in the TLS secrets of
+// the VerticaDB are valid for. It exports the expiry time as a metric, and
+// warns through events and the CertificateExpiringSoon condition once a
+// certificate is within one of the thresholds of the cert-expiry-warning-days
+// annotation.
+type CertExpiryReconciler struct {
+	VRec *VerticaDBReconciler
+	Vdb  *vapi.VerticaDB // Vdb is the CRD we are acting on.
+	Log  logr.Logger
+}
+
+// certExpiryDetail has the expiry of the certificate in a TLS secret. A secret
+// can be shared by more than one tls config.
+type certExpiryDetail struct {
+	secret     string
+	tlsConfigs []string
+	notAfter   time.Time
+}
+
+// MakeCertExpiryReconciler will build a CertExpiryReconciler object
+func MakeCertExpiryReconciler(vdbrecon *VerticaDBReconciler, log logr.Logger, vdb *vapi.VerticaDB) controllers.ReconcileActor {
+	return &CertExpiryReconciler{
+		VRec: vdbrecon,
+		Vdb:  vdb,
+		Log:  log.WithName("CertExpiryReconciler"),
+	}
+}
+
+// Reconcile will check the expiry of each certificate. It doesn't requeue
+// itself. Instead, it records when the next threshold is crossed so that the
+// VerticaDB is reconciled again at that time.
+func (c *CertExpiryReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (ctrl.Result, error) {
+	certs := c.collectCertExpiry(ctx)
+	c.updateMetrics(certs)
+
+	thresholds := vmeta.GetCertExpiryWarningDays(c.Vdb.Annotations)
+	now := time.Now()
+	warnings := []string{}
+	anyExpired := false
+	for _, cert := range certs {
+		usedFor := strings.Join(cert.tlsConfigs, ", ")
+		expiry := cert.notAfter.Format(certExpiryDateFormat)
+		if !now.Before(cert.notAfter) {
+			anyExpired = true
+			c.VRec.Eventf(c.Vdb, corev1.EventTypeWarning, events.TLSCertExpired,
+				"The certificate in secret %s used for %s expired on %s", cert.secret, usedFor, expiry)
+			warnings = append(warnings, fmt.Sprintf("%s (%s) expired on %s", cert.secret, usedFor, expiry))
+			continue
+		}
+		days, ok := getCrossedCertExpiryThreshold(cert.notAfter.Sub(now), thresholds)
+		if !ok {
+			continue
+		}
+		// The message only changes when the next threshold is crossed, so the
+		// event is aggregated rather than repeated every reconcile.
+		c.VRec.Eventf(c.Vdb, corev1.EventTypeWarning, events.TLSCertExpiringSoon,
+			"The certificate in secret %s used for %s expires within %d days, on %s", cert.secret, usedFor, days, expiry)
+		warnings = append(warnings, fmt.Sprintf("%s (%s) expires on %s", cert.secret, usedFor, expiry))
+	}
+	if err := c.updateCondition(ctx, warnings, anyExpired); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, c.updateNextCheckTime(ctx, getNextCertExpiryCheckTime(certs, thresholds, now))
+}
+
+// collectCertExpiry reads the certificate of each TLS secret in use. Secrets
+// that can't be read are skipped; the reconcilers that use them report that.
+func (c *CertExpiryReconciler) collectCertExpiry(ctx context.Context) []*certExpiryDetail {
+	certs := []*certExpiryDetail{}
+	bySecret := map[string]*certExpiryDetail{}
+	add := func(tlsConfig, secret string) {
+		if secret == "" {
+			return
+		}
+		if cert, ok := bySecret[secret]; ok {
+			cert.tlsConfigs = append(cert.tlsConfigs, tlsConfig)
+			return
+		}
+		notAfter, err := c.readCertExpiry(ctx, secret)
+		if err != nil {
+			c.Log.Info("Skipping expiry check of TLS secret", "secret", secret, "tlsConfig", tlsConfig, "err", err)
+			return
+		}
+		bySecret[secret] = &certExpiryDetail{secret: secret, tlsConfigs: []string{tlsConfig}, notAfter: notAfter}
+		certs = append(certs, bySecret[secret])
+	}
+
+	if c.Vdb.IsHTTPSNMATLSAuthEnabled() {
+		add(vapi.HTTPSNMATLSConfigName, c.Vdb.GetHTTPSNMATLSSecret())
+	}
+	if c.Vdb.IsClientServerTLSAuthEnabled() {
+		add(vapi.ClientServerTLSConfigName, c.Vdb.GetClientServerTLSSecret())
+	}
+	add(nmaTLSConfigName, c.Vdb.GetNMATLSSecret())
+	if vmeta.UseVProxy(c.Vdb.Annotations) && c.Vdb.Spec.Proxy != nil {
+		add(proxyTLSConfigName, c.Vdb.Spec.Proxy.TLSSecret)
+	}
+	return certs
+}
+
+// readCertExpiry returns the NotAfter time of the certificate in a TLS secret
+func (c *CertExpiryReconciler) readCertExpiry(ctx context.Context, secretName string) (time.Time, error) {
+	fetcher := cloud.SecretFetcher{
+		Client:   c.VRec.GetClient(),
+		Log:      c.Log,
+		Obj:      c.Vdb,
+		EVWriter: c.VRec,
+	}
+	secret, err := fetcher.Fetch(ctx, names.GenNamespacedName(c.Vdb, secretName))
+	if err != nil {
+		return time.Time{}, err
+	}
+	cert, err := security.DecodeCertificate(secret[corev1.TLSCertKey])
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cert.NotAfter, nil
+}
+
+// updateMetrics sets the expiry metric of each certificate. It holds the
+// expiry time rather than the time left so that it stays accurate between
+// reconciles. The old series are removed first since the secret names change
+// when certs are rotated.
+func (c *CertExpiryReconciler) updateMetrics(certs []*certExpiryDetail) {
+	metrics.CertificateExpiryTimestamp.DeletePartialMatch(prometheus.Labels{
+		metrics.NamespaceLabel: c.Vdb.Namespace,
+		metrics.VerticaDBLabel: c.Vdb.Name,
+	})
+	for _, cert := range certs {
+		for _, tlsConfig := range cert.tlsConfigs {
+			labels := metrics.MakeVDBLabels(c.Vdb)
+			labels[metrics.TLSConfigLabel] = tlsConfig
+			labels[metrics.SecretLabel] = cert.secret
+			metrics.CertificateExpiryTimestamp.With(labels).Set(float64(cert.notAfter.Unix()))
+		}
+	}
+}
+
+// updateCondition sets the CertificateExpiringSoon condition. It is only set
+// to false if it was true before, so that it doesn't show up for a VerticaDB
+// whose certificates have always been valid.
+func (c *CertExpiryReconciler) updateCondition(ctx context.Context, warnings []string, anyExpired bool) error {
+	if len(warnings) == 0 {
+		if !c.Vdb.IsStatusConditionTrue(vapi.CertificateExpiringSoon) {
+			return nil
+		}
+		cond := vapi.MakeCondition(vapi.CertificateExpiringSoon, metav1.ConditionFalse, vapi.CertificatesValidReason)
+		return vdbstatus.UpdateCondition(ctx, c.VRec.Client, c.Vdb, cond)
+	}
+	reason := vapi.CertificateExpiringReason
+	if anyExpired {
+		reason = vapi.CertificateExpiredReason
+	}
+	cond := vapi.MakeCondition(vapi.CertificateExpiringSoon, metav1.ConditionTrue, reason)
+	cond.Message = strings.Join(warnings, "; ")
+	return vdbstatus.UpdateCondition(ctx, c.VRec.Client, c.Vdb, cond)
+}
+
+// updateNextCheckTime records when the certificates must be checked again.
+// The status is only updated if the time changed.
+func (c *CertExpiryReconciler) updateNextCheckTime(ctx context.Context, next time.Time) error {
+	cur := c.Vdb.Status.CertExpiryNextCheckTime
+	if (next.IsZero() && cur == nil) || (cur != nil && cur.Time.Equal(next)) {
+		return nil
+	}
+	return vdbstatus.Update(ctx, c.VRec.Client, c.Vdb, func(vdb *vapi.VerticaDB) error {
+		if next.IsZero() {
+			vdb.Status.CertExpiryNextCheckTime = nil
+		} else {
+			vdb.Status.CertExpiryNextCheckTime = &metav1.Time{Time: next}
+		}
+		return nil
+	})
+}
+
+// getNextCertExpiryCheckTime returns the earliest time, after now, at which a
+// certificate crosses a threshold or expires. It returns the zero time if
+// there is none.
+func getNextCertExpiryCheckTime(certs []*certExpiryDetail, thresholds []int, now time.Time) time.Time {
+	next := time.Time{}
+	consider := func(t time.Time) {
+		if t.After(now) && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+	for _, cert := range certs {
+		consider(cert.notAfter)
+		for _, days := range thresholds {
+			consider(cert.notAfter.Add(-time.Duration(days) * 24 * time.Hour))
+		}
+	}
+	if next.IsZero() {
+		return next
+	}
+	// metav1.Time is serialized with a precision of one second. We round up
+	// so that the threshold has been crossed by the time we check again.
+	return next.Truncate(time.Second).Add(time.Second)
+}
+
+// getCrossedCertExpiryThreshold returns the smallest threshold, in days, that
+// the time left is within. The thresholds must be sorted from largest to
+// smallest. It returns false if no threshold has been crossed.
+func getCrossedCertExpiryThreshold(timeLeft time.Duration, thresholds []int) (int, bool) {
+	crossed := 0
+	for _, days := range thresholds {
+		if timeLeft > time.Duration(days)*24*time.Hour {
+			break
+		}
+		crossed = days
+	}
+	return crossed, crossed > 0
+}
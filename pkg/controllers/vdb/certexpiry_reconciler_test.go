@@ -0,0 +1,109 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vdb
+
+import (
+	"context"
+	"crypto/x509"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	vmeta "github.com/vertica/vertica-kubernetes/pkg/meta"
+	"github.com/vertica/vertica-kubernetes/pkg/metrics"
+	"github.com/vertica/vertica-kubernetes/pkg/security"
+	"github.com/vertica/vertica-kubernetes/pkg/test"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("certexpiry_reconcile", func() {
+	ctx := context.Background()
+
+	It("should find the smallest threshold crossed", func() {
+		const day = 24 * time.Hour
+		thresholds := []int{30, 7, 1}
+		_, ok := getCrossedCertExpiryThreshold(40*day, thresholds)
+		Expect(ok).Should(BeFalse())
+		days, ok := getCrossedCertExpiryThreshold(20*day, thresholds)
+		Expect(ok).Should(BeTrue())
+		Expect(days).Should(Equal(30))
+		days, ok = getCrossedCertExpiryThreshold(7*day, thresholds)
+		Expect(ok).Should(BeTrue())
+		Expect(days).Should(Equal(7))
+		days, ok = getCrossedCertExpiryThreshold(time.Hour, thresholds)
+		Expect(ok).Should(BeTrue())
+		Expect(days).Should(Equal(1))
+	})
+
+	It("should find the next time a cert crosses a threshold or expires", func() {
+		const day = 24 * time.Hour
+		now := time.Now()
+		thresholds := []int{30, 7}
+		Expect(getNextCertExpiryCheckTime(nil, thresholds, now).IsZero()).Should(BeTrue())
+
+		certs := []*certExpiryDetail{{notAfter: now.Add(20 * day)}, {notAfter: now.Add(40 * day)}}
+		Expect(getNextCertExpiryCheckTime(certs, thresholds, now)).Should(BeTemporally("~", now.Add(10*day), time.Second))
+		certs = []*certExpiryDetail{{notAfter: now.Add(5 * day)}}
+		Expect(getNextCertExpiryCheckTime(certs, thresholds, now)).Should(BeTemporally("~", now.Add(5*day), time.Second))
+		certs = []*certExpiryDetail{{notAfter: now.Add(-day)}}
+		Expect(getNextCertExpiryCheckTime(certs, thresholds, now).IsZero()).Should(BeTrue())
+	})
+
+	It("should set the expiry metric and condition for a cert that expires soon", func() {
+		const secretName = "certexpiry-nma"
+		vdb := vapi.MakeVDB()
+		vdb.Spec.NMATLSSecret = secretName
+		vdb.Spec.HTTPSNMATLS = &vapi.TLSConfigSpec{Enabled: vapi.BoolPtr(false)}
+		vdb.Annotations[vmeta.CertExpiryWarningDaysAnnotation] = "10,3"
+		test.CreateVDB(ctx, k8sClient, vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+
+		ca, err := security.NewSelfSignedCACertificate()
+		Expect(err).Should(Succeed())
+		cert, err := security.NewTestCertificate(ca, vdb.GetVerticaUser(), nil, nil,
+			time.Now().Add(-time.Hour), time.Now().Add(5*24*time.Hour),
+			[]x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+			x509.KeyUsageDigitalSignature, false)
+		Expect(err).Should(Succeed())
+		Expect(k8sClient.Create(ctx, security.GenSecret(secretName, vdb.Namespace, cert, ca))).Should(Succeed())
+		defer deleteSecret(ctx, vdb, secretName)
+
+		r := MakeCertExpiryReconciler(vdbRec, logger, vdb)
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+
+		labels := metrics.MakeVDBLabels(vdb)
+		labels[metrics.TLSConfigLabel] = nmaTLSConfigName
+		labels[metrics.SecretLabel] = secretName
+		expiry := testutil.ToFloat64(metrics.CertificateExpiryTimestamp.With(labels))
+		Expect(expiry).Should(BeNumerically("~", time.Now().Add(5*24*time.Hour).Unix(), 60))
+		// The next check is when the cert crosses the 3 days threshold
+		Expect(vdb.GetCertExpiryNextCheckIn(time.Now())).Should(BeNumerically("~", 2*24*time.Hour, time.Minute))
+
+		cond := vdb.FindStatusCondition(vapi.CertificateExpiringSoon)
+		Expect(cond).ShouldNot(BeNil())
+		Expect(cond.Status).Should(Equal(metav1.ConditionTrue))
+		Expect(cond.Reason).Should(Equal(vapi.CertificateExpiringReason))
+		Expect(cond.Message).Should(ContainSubstring(secretName))
+
+		// Once the threshold is lowered below the time left, the condition is cleared
+		vdb.Annotations[vmeta.CertExpiryWarningDaysAnnotation] = "3"
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(vdb.IsStatusConditionFalse(vapi.CertificateExpiringSoon)).Should(BeTrue())
+	})
+})
@@ -0,0 +1,430 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vdb
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/builder"
+	"github.com/vertica/vertica-kubernetes/pkg/cloud"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	"github.com/vertica/vertica-kubernetes/pkg/events"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/podfacts"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/setclienttlsauth"
+	"github.com/vertica/vertica-kubernetes/pkg/vdbstatus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ClientTLSAuthReconciler will keep the trusted client CA and the TLS
+// authentication methods of the database in sync with spec.clientTLSAuth
+type ClientTLSAuthReconciler struct {
+	VRec       *VerticaDBReconciler
+	Vdb        *vapi.VerticaDB // Vdb is the CRD we are acting on.
+	Log        logr.Logger
+	Dispatcher vadmin.Dispatcher
+	PFacts     *podfacts.PodFacts
+}
+
+// tlsAuthGrantees are the users and roles that the VerticaUser and
+// VerticaRole objects of the database create
+type tlsAuthGrantees struct {
+	// Users and roles that are ready in the database. Patterns are matched
+	// against them.
+	ReadyUsers []string
+	ReadyRoles []string
+	// All users and roles, ready or not
+	Users []string
+	Roles []string
+}
+
+func MakeClientTLSAuthReconciler(vdbrecon *VerticaDBReconciler, log logr.Logger, vdb *vapi.VerticaDB,
+	dispatcher vadmin.Dispatcher, pfacts *podfacts.PodFacts) controllers.ReconcileActor {
+	return &ClientTLSAuthReconciler{
+		VRec:       vdbrecon,
+		Vdb:        vdb,
+		Log:        log.WithName("ClientTLSAuthReconciler"),
+		Dispatcher: dispatcher,
+		PFacts:     pfacts,
+	}
+}
+
+// Reconcile will compare the TLS authentication methods in the spec with the
+// ones the operator created, and apply the difference in a single call. A
+// method whose host or priority changed is dropped and created again.
+func (c *ClientTLSAuthReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (ctrl.Result, error) {
+	if c.Vdb.Spec.ClientTLSAuth == nil && c.Vdb.Status.ClientTLSAuth == nil {
+		return ctrl.Result{}, nil
+	}
+	if !c.Vdb.UseVClusterOpsDeployment() || !c.Vdb.IsDBInitialized() {
+		return ctrl.Result{}, nil
+	}
+	// The trusted CA is added to the server TLS configuration, so we wait
+	// for the TLS reconciler to have set it up.
+	if c.Vdb.Spec.ClientTLSAuth != nil && !c.Vdb.IsClientServerConfigEnabled() {
+		return ctrl.Result{}, nil
+	}
+
+	// The trusted CA is set with the client-server certificate in use. We
+	// wait for a rotation of that certificate to finish, after which the
+	// trusted CA is added again.
+	if c.Vdb.IsClientTLSAuthEnabled() && (!c.Vdb.NoClientServerRotationNeeded() || c.Vdb.IsTLSCertRollbackNeeded()) {
+		c.Log.Info("Client-server TLS config is being changed. Requeue reconciliation.")
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	grantees, err := c.getGrantees(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	desired := c.buildDesiredState(grantees)
+	var caData map[string][]byte
+	if desired != nil {
+		caData, err = c.buildTrustedCASecretData(ctx)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		desired.TrustedCADigest = c.getTrustedCADigest(caData)
+	}
+	opts := c.buildOptions(desired, grantees)
+	if len(opts) == 0 {
+		// There is nothing to change in the database, but the state can still
+		// be behind. This happens when a matched user or role was dropped, or
+		// when spec.clientTLSAuth is removed, since the trusted CA stays in
+		// the server TLS configuration.
+		if reflect.DeepEqual(desired, c.Vdb.Status.ClientTLSAuth) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, vdbstatus.SetClientTLSAuth(ctx, c.VRec.Client, c.Vdb, desired)
+	}
+
+	if err := c.PFacts.Collect(ctx, c.Vdb); err != nil {
+		return ctrl.Result{}, err
+	}
+	initiator, ok := c.PFacts.FindFirstUpPod(false, "")
+	if !ok {
+		c.Log.Info("No up pod found to set client tls authentication. Requeue reconciliation.")
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	if c.isTrustedCAChanged(desired) {
+		if err := c.saveTrustedCASecret(ctx, caData); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	c.Log.Info("Setting client tls authentication")
+	opts = append(opts, setclienttlsauth.WithInitiator(initiator.GetPodIP()))
+	if err := c.Dispatcher.SetClientTLSAuth(ctx, opts...); err != nil {
+		c.VRec.Eventf(c.Vdb, corev1.EventTypeWarning, events.ClientTLSAuthConfigFailed,
+			"Failed to set client tls authentication: %s", err.Error())
+		return ctrl.Result{}, err
+	}
+	c.VRec.Event(c.Vdb, corev1.EventTypeNormal, events.ClientTLSAuthConfigured,
+		"Client tls authentication is in sync with spec.clientTLSAuth")
+	return ctrl.Result{}, vdbstatus.SetClientTLSAuth(ctx, c.VRec.Client, c.Vdb, desired)
+}
+
+// getGrantees returns the users and roles that the VerticaUser and
+// VerticaRole objects of this database create
+func (c *ClientTLSAuthReconciler) getGrantees(ctx context.Context) (*tlsAuthGrantees, error) {
+	g := &tlsAuthGrantees{}
+	vusrs := v1beta1.VerticaUserList{}
+	if err := c.VRec.List(ctx, &vusrs, client.InNamespace(c.Vdb.Namespace)); err != nil {
+		return nil, err
+	}
+	for i := range vusrs.Items {
+		vusr := &vusrs.Items[i]
+		if vusr.Spec.VerticaDBName != c.Vdb.Name || vusr.DeletionTimestamp != nil {
+			continue
+		}
+		g.Users = append(g.Users, vusr.GetUserName())
+		if vusr.IsStatusConditionTrue(v1beta1.UserReady) {
+			g.ReadyUsers = append(g.ReadyUsers, vusr.GetUserName())
+		}
+	}
+	vroles := v1beta1.VerticaRoleList{}
+	if err := c.VRec.List(ctx, &vroles, client.InNamespace(c.Vdb.Namespace)); err != nil {
+		return nil, err
+	}
+	for i := range vroles.Items {
+		vrole := &vroles.Items[i]
+		if vrole.Spec.VerticaDBName != c.Vdb.Name || vrole.DeletionTimestamp != nil {
+			continue
+		}
+		g.Roles = append(g.Roles, vrole.GetRoleName())
+		if vrole.IsStatusConditionTrue(v1beta1.RoleReady) {
+			g.ReadyRoles = append(g.ReadyRoles, vrole.GetRoleName())
+		}
+	}
+	return g, nil
+}
+
+// buildDesiredState returns the client tls authentication that the spec asks
+// for, with the user and role patterns resolved. A user or role that was
+// already matched stays matched while it isn't ready, such as during an
+// update. It returns nil if the spec doesn't have any.
+func (c *ClientTLSAuthReconciler) buildDesiredState(g *tlsAuthGrantees) *vapi.ClientTLSAuthStatus {
+	if c.Vdb.Spec.ClientTLSAuth == nil {
+		return nil
+	}
+	state := &vapi.ClientTLSAuthStatus{
+		TrustedCASecret: c.Vdb.Spec.ClientTLSAuth.TrustedCASecret,
+	}
+	for i := range c.Vdb.Spec.ClientTLSAuth.Methods {
+		m := &c.Vdb.Spec.ClientTLSAuth.Methods[i]
+		var prevMatched []string
+		if a := c.Vdb.GetClientTLSAuthMethodStatus(m.Name); a != nil {
+			prevMatched = a.MatchedGrantees
+		}
+		named, matched := resolveTLSAuthGrantees(m.Users, matchCandidates(g.ReadyUsers, g.Users, prevMatched))
+		namedRoles, matchedRoles := resolveTLSAuthGrantees(m.Roles, matchCandidates(g.ReadyRoles, g.Roles, prevMatched))
+		named = sortedUnique(append(named, namedRoles...))
+		matched = slices.DeleteFunc(sortedUnique(append(matched, matchedRoles...)), func(n string) bool {
+			return slices.Contains(named, n)
+		})
+		state.Methods = append(state.Methods, vapi.TLSAuthMethodStatus{
+			Name:            m.Name,
+			Host:            m.GetHost(),
+			Priority:        m.Priority,
+			Grantees:        named,
+			MatchedGrantees: matched,
+		})
+	}
+	return state
+}
+
+// buildOptions returns the options to bring the database from the applied
+// state, in the status, to the desired one. It returns nothing if there is
+// no change.
+func (c *ClientTLSAuthReconciler) buildOptions(desired *vapi.ClientTLSAuthStatus,
+	g *tlsAuthGrantees) []setclienttlsauth.Option {
+	applied := c.Vdb.Status.ClientTLSAuth
+	if applied == nil {
+		applied = &vapi.ClientTLSAuthStatus{}
+	}
+	if desired == nil {
+		desired = &vapi.ClientTLSAuthStatus{}
+	}
+	opts := []setclienttlsauth.Option{}
+	if c.isTrustedCAChanged(desired) {
+		opts = append(opts, setclienttlsauth.WithTrustedCASecret(names.GenClientTLSAuthSecretName(c.Vdb).Name,
+			c.Vdb.Namespace, c.Vdb.GetClientServerTLSModeInUse()))
+	}
+
+	recreated := map[string]bool{}
+	for i := range applied.Methods {
+		a := &applied.Methods[i]
+		d := findTLSAuthMethod(desired.Methods, a.Name)
+		if d != nil && d.Host == a.Host && d.Priority == a.Priority {
+			continue
+		}
+		for _, grantee := range revocableTLSAuthGrantees(a, g) {
+			opts = append(opts, setclienttlsauth.WithRevoke(a.Name, grantee))
+		}
+		opts = append(opts, setclienttlsauth.WithDropMethod(a.Name))
+		recreated[a.Name] = true
+	}
+	for i := range desired.Methods {
+		d := &desired.Methods[i]
+		a := findTLSAuthMethod(applied.Methods, d.Name)
+		if a == nil || recreated[a.Name] {
+			opts = append(opts, setclienttlsauth.WithCreateMethod(d.Name, d.Host, d.Priority))
+			for _, grantee := range allTLSAuthGrantees(d) {
+				opts = append(opts, setclienttlsauth.WithGrant(d.Name, grantee))
+			}
+			continue
+		}
+		wanted := allTLSAuthGrantees(d)
+		for _, grantee := range revocableTLSAuthGrantees(a, g) {
+			if !slices.Contains(wanted, grantee) {
+				opts = append(opts, setclienttlsauth.WithRevoke(a.Name, grantee))
+			}
+		}
+		granted := allTLSAuthGrantees(a)
+		for _, grantee := range wanted {
+			if !slices.Contains(granted, grantee) {
+				opts = append(opts, setclienttlsauth.WithGrant(d.Name, grantee))
+			}
+		}
+	}
+	return opts
+}
+
+// isTrustedCAChanged returns true if the trusted CA, or the client-server TLS
+// config it was set with, changed since it was last added to the database
+func (c *ClientTLSAuthReconciler) isTrustedCAChanged(desired *vapi.ClientTLSAuthStatus) bool {
+	if desired == nil || desired.TrustedCASecret == "" {
+		return false
+	}
+	applied := c.Vdb.Status.ClientTLSAuth
+	return applied == nil || desired.TrustedCASecret != applied.TrustedCASecret ||
+		desired.TrustedCADigest != applied.TrustedCADigest
+}
+
+// buildTrustedCASecretData returns the contents of the secret that the server
+// TLS config is set from. It has the client-server certificate and key in
+// use. Its CA certificates are the ones of the client-server secret followed
+// by the trusted ones, so that both are kept.
+func (c *ClientTLSAuthReconciler) buildTrustedCASecretData(ctx context.Context) (map[string][]byte, error) {
+	fetcher := cloud.SecretFetcher{
+		Client:   c.VRec.GetClient(),
+		Log:      c.Log,
+		Obj:      c.Vdb,
+		EVWriter: c.VRec,
+	}
+	serverSecret, err := fetcher.Fetch(ctx, names.GenNamespacedName(c.Vdb, c.Vdb.GetClientServerTLSSecretInUse()))
+	if err != nil {
+		return nil, err
+	}
+	trustedCASecret := c.Vdb.Spec.ClientTLSAuth.TrustedCASecret
+	caSecret, err := fetcher.Fetch(ctx, names.GenNamespacedName(c.Vdb, trustedCASecret))
+	if err != nil {
+		return nil, err
+	}
+	trustedCA := bytes.TrimSpace(caSecret[corev1.ServiceAccountRootCAKey])
+	if len(trustedCA) == 0 {
+		return nil, fmt.Errorf("trusted CA secret %q does not have the key %q", trustedCASecret,
+			corev1.ServiceAccountRootCAKey)
+	}
+	caCerts := [][]byte{}
+	if serverCA := bytes.TrimSpace(serverSecret[corev1.ServiceAccountRootCAKey]); len(serverCA) > 0 {
+		caCerts = append(caCerts, serverCA)
+	}
+	caCerts = append(caCerts, trustedCA)
+	return map[string][]byte{
+		corev1.TLSCertKey:              serverSecret[corev1.TLSCertKey],
+		corev1.TLSPrivateKeyKey:        serverSecret[corev1.TLSPrivateKeyKey],
+		corev1.ServiceAccountRootCAKey: append(bytes.Join(caCerts, []byte("\n")), '\n'),
+	}, nil
+}
+
+// getTrustedCADigest returns the digest of the server TLS config that the
+// trusted CA is set with. It changes when the certificates, or the
+// client-server secret and mode in use, change.
+func (c *ClientTLSAuthReconciler) getTrustedCADigest(data map[string][]byte) string {
+	hasher := sha256.New()
+	for _, part := range [][]byte{
+		[]byte(c.Vdb.GetClientServerTLSSecretInUse()),
+		[]byte(c.Vdb.GetClientServerTLSModeInUse()),
+		data[corev1.TLSCertKey],
+		data[corev1.ServiceAccountRootCAKey],
+	} {
+		hasher.Write(part)
+		hasher.Write([]byte{0})
+	}
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// saveTrustedCASecret creates or updates the secret that the server TLS
+// config is set from
+func (c *ClientTLSAuthReconciler) saveTrustedCASecret(ctx context.Context, data map[string][]byte) error {
+	nm := names.GenClientTLSAuthSecretName(c.Vdb)
+	secret := &corev1.Secret{}
+	if err := c.VRec.Client.Get(ctx, nm, secret); err != nil {
+		if !kerrors.IsNotFound(err) {
+			return err
+		}
+		c.Log.Info("Creating secret for the trusted client CA", "secret", nm.Name)
+		return c.VRec.Client.Create(ctx, builder.BuildClientTLSAuthSecret(c.Vdb, nm, data))
+	}
+	if reflect.DeepEqual(secret.Data, data) {
+		return nil
+	}
+	secret.Data = data
+	c.Log.Info("Updating secret for the trusted client CA", "secret", nm.Name)
+	return c.VRec.Client.Update(ctx, secret)
+}
+
+// resolveTLSAuthGrantees splits the users or roles of a method into the
+// names given as is and the names that a pattern matched
+func resolveTLSAuthGrantees(entries, candidates []string) (named, matched []string) {
+	for _, entry := range entries {
+		if !strings.ContainsAny(entry, "*?[\\") {
+			named = append(named, entry)
+			continue
+		}
+		for _, candidate := range candidates {
+			if ok, err := path.Match(entry, candidate); err == nil && ok {
+				matched = append(matched, candidate)
+			}
+		}
+	}
+	return named, matched
+}
+
+// revocableTLSAuthGrantees returns the grantees of an applied method that can
+// be revoked. A grantee that a pattern matched is skipped once its
+// VerticaUser or VerticaRole is gone, as the drop of the user or role already
+// removed the grant.
+func revocableTLSAuthGrantees(a *vapi.TLSAuthMethodStatus, g *tlsAuthGrantees) []string {
+	grantees := slices.Clone(a.Grantees)
+	for _, grantee := range a.MatchedGrantees {
+		if slices.Contains(g.Users, grantee) || slices.Contains(g.Roles, grantee) {
+			grantees = append(grantees, grantee)
+		}
+	}
+	return grantees
+}
+
+// matchCandidates returns the names a pattern can match: the ready ones, and
+// the ones that were already matched and still exist
+func matchCandidates(ready, all, prevMatched []string) []string {
+	candidates := slices.Clone(ready)
+	for _, name := range prevMatched {
+		if slices.Contains(all, name) {
+			candidates = append(candidates, name)
+		}
+	}
+	return sortedUnique(candidates)
+}
+
+// allTLSAuthGrantees returns the named and matched grantees of a method
+func allTLSAuthGrantees(m *vapi.TLSAuthMethodStatus) []string {
+	return append(slices.Clone(m.Grantees), m.MatchedGrantees...)
+}
+
+// findTLSAuthMethod returns the method with the given name, or nil if there
+// isn't one
+func findTLSAuthMethod(methods []vapi.TLSAuthMethodStatus, name string) *vapi.TLSAuthMethodStatus {
+	for i := range methods {
+		if methods[i].Name == name {
+			return &methods[i]
+		}
+	}
+	return nil
+}
+
+// sortedUnique returns the sorted input without duplicates
+func sortedUnique(names []string) []string {
+	slices.Sort(names)
+	return slices.Compact(names)
+}
@@ -0,0 +1,186 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vdb
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	vops "github.com/vertica/vcluster/vclusterops"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/cmds"
+	"github.com/vertica/vertica-kubernetes/pkg/mockvops"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/test"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// mockClientTLSAuthVClusterOps records the options of the set client tls
+// auth calls
+type mockClientTLSAuthVClusterOps struct {
+	mockvops.MockVClusterOps
+	calls []*vops.VSetClientTLSAuthOptions
+}
+
+func (m *mockClientTLSAuthVClusterOps) VSetClientTLSAuth(options *vops.VSetClientTLSAuthOptions) error {
+	m.calls = append(m.calls, options)
+	return nil
+}
+
+var _ = Describe("clienttlsauth_reconciler", func() {
+	ctx := context.Background()
+
+	It("should be a no-op if client tls authentication isn't configured", func() {
+		vdb := vapi.MakeVDBForVclusterOps()
+		fpr := &cmds.FakePodRunner{}
+		pfacts := createPodFactsDefault(fpr)
+		r := MakeClientTLSAuthReconciler(vdbRec, logger, vdb, mockVClusterOpsDispatcher(vdb), pfacts)
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(vdb.Status.ClientTLSAuth).Should(BeNil())
+	})
+
+	It("should create, grant, recreate and drop the tls authentication methods", func() {
+		vdb := vapi.MakeVDBForVclusterOps()
+		vapi.SetVDBForTLS(vdb)
+		vdb.Spec.ClientTLSAuth = &vapi.ClientTLSAuthSpec{
+			TrustedCASecret: "client-ca",
+			Methods: []vapi.TLSAuthMethod{
+				{Name: "svc_tls", Host: "10.0.0.0/8", Users: []string{"svc-*", "etl"}},
+			},
+		}
+		vdb.Spec.HTTPSNMATLS.Secret = "https-secret"
+		vdb.Spec.ClientServerTLS.Secret = "client-server-secret"
+		vdb.Spec.ClientServerTLS.Mode = "try_verify"
+		test.CreateFakeTLSSecret(ctx, vdb, k8sClient, vdb.GetHTTPSNMATLSSecret())
+		defer test.DeleteSecret(ctx, k8sClient, vdb.GetHTTPSNMATLSSecret())
+		test.CreateFakeTLSSecret(ctx, vdb, k8sClient, vdb.GetClientServerTLSSecret())
+		defer test.DeleteSecret(ctx, k8sClient, vdb.GetClientServerTLSSecret())
+		Expect(k8sClient.Create(ctx, test.BuildTLSSecret(vdb, "client-ca", "", "", "client-ca-cert"))).Should(Succeed())
+		defer test.DeleteSecret(ctx, k8sClient, "client-ca")
+		defer test.DeleteSecret(ctx, k8sClient, names.GenClientTLSAuthSecretName(vdb).Name)
+		test.CreateVDB(ctx, k8sClient, vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+		test.CreatePods(ctx, k8sClient, vdb, test.AllPodsRunning)
+		defer test.DeletePods(ctx, k8sClient, vdb)
+		meta.SetStatusCondition(&vdb.Status.Conditions,
+			*vapi.MakeCondition(vapi.DBInitialized, metav1.ConditionTrue, "Initialized"))
+		vdb.Status.TLSConfigs = []vapi.TLSConfigStatus{
+			*vapi.MakeHTTPSNMATLSConfig(vdb.GetHTTPSNMATLSSecret(), "try_verify"),
+			*vapi.MakeClientServerTLSConfig("client-server-secret", "try_verify"),
+		}
+		Expect(k8sClient.Status().Update(ctx, vdb)).Should(Succeed())
+
+		// Only the user that is ready is matched by the pattern
+		for _, name := range []string{"svc-a", "svc-b"} {
+			vusr := v1beta1.MakeVusr()
+			vusr.Name = name
+			vusr.Spec.UserName = ""
+			Expect(k8sClient.Create(ctx, vusr)).Should(Succeed())
+			defer func() { Expect(k8sClient.Delete(ctx, vusr)).Should(Succeed()) }()
+			if name == "svc-a" {
+				meta.SetStatusCondition(&vusr.Status.Conditions,
+					*vapi.MakeCondition(v1beta1.UserReady, metav1.ConditionTrue, "Reconciled"))
+				Expect(k8sClient.Status().Update(ctx, vusr)).Should(Succeed())
+			}
+		}
+
+		mock := &mockClientTLSAuthVClusterOps{}
+		setupAPIFunc := func(logr.Logger, string) (vadmin.VClusterProvider, logr.Logger) {
+			return mock, logr.Logger{}
+		}
+		dispatcher := mockvops.MakeMockVClusterOpsDispatcher(vdb, logger, k8sClient, setupAPIFunc)
+		pfacts := createPodFactsDefault(&cmds.FakePodRunner{})
+		r := MakeClientTLSAuthReconciler(vdbRec, logger, vdb, dispatcher, pfacts)
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+
+		Expect(mock.calls).Should(HaveLen(1))
+		Expect(mock.calls[0].TrustedCAConfig.ConfigMap[vops.TLSSecretManagerKeySecretName]).
+			Should(Equal(names.GenClientTLSAuthSecretName(vdb).Name))
+		Expect(mock.calls[0].TrustedCAConfig.ConfigMap[vops.TLSSecretManagerKeyTLSMode]).Should(Equal("try_verify"))
+		caSecret := &corev1.Secret{}
+		Expect(k8sClient.Get(ctx, names.GenClientTLSAuthSecretName(vdb), caSecret)).Should(Succeed())
+		Expect(string(caSecret.Data[corev1.ServiceAccountRootCAKey])).Should(Equal(test.TestCaCertValue + "\nclient-ca-cert\n"))
+		Expect(string(caSecret.Data[corev1.TLSCertKey])).Should(Equal(test.TestCertValue))
+		Expect(mock.calls[0].CreateMethods).Should(Equal([]vops.ClientTLSAuthMethod{
+			{Name: "svc_tls", Host: "10.0.0.0/8"}}))
+		Expect(mock.calls[0].Grants).Should(Equal([]vops.ClientTLSAuthGrant{
+			{Method: "svc_tls", Grantee: "etl"}, {Method: "svc_tls", Grantee: "svc-a"}}))
+		Expect(vdb.Status.ClientTLSAuth).ShouldNot(BeNil())
+		Expect(vdb.Status.ClientTLSAuth.Methods).Should(Equal([]vapi.TLSAuthMethodStatus{
+			{Name: "svc_tls", Host: "10.0.0.0/8", Grantees: []string{"etl"}, MatchedGrantees: []string{"svc-a"}}}))
+
+		// Nothing is called again until something changes
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(mock.calls).Should(HaveLen(1))
+
+		// The trusted CA waits for a rotation of the client-server
+		// certificate, then is added again with the new one
+		test.CreateFakeTLSSecret(ctx, vdb, k8sClient, "client-server-secret-2")
+		defer test.DeleteSecret(ctx, k8sClient, "client-server-secret-2")
+		vdb.Spec.ClientServerTLS.Secret = "client-server-secret-2"
+		Expect(k8sClient.Update(ctx, vdb)).Should(Succeed())
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{Requeue: true}))
+		Expect(mock.calls).Should(HaveLen(1))
+		vapi.SetTLSConfigs(&vdb.Status.TLSConfigs, vapi.MakeClientServerTLSConfig("client-server-secret-2", "try_verify"))
+		Expect(k8sClient.Status().Update(ctx, vdb)).Should(Succeed())
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(mock.calls).Should(HaveLen(2))
+		Expect(mock.calls[1].TrustedCAConfig.ConfigMap[vops.TLSSecretManagerKeySecretName]).
+			Should(Equal(names.GenClientTLSAuthSecretName(vdb).Name))
+		Expect(mock.calls[1].CreateMethods).Should(BeEmpty())
+
+		// A new host recreates the method
+		vdb.Spec.ClientTLSAuth.Methods[0].Host = "10.1.0.0/16"
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(mock.calls).Should(HaveLen(3))
+		Expect(mock.calls[2].TrustedCAConfig.ConfigMap).Should(BeEmpty())
+		Expect(mock.calls[2].Revokes).Should(HaveLen(2))
+		Expect(mock.calls[2].DropMethods).Should(Equal([]string{"svc_tls"}))
+		Expect(mock.calls[2].CreateMethods).Should(Equal([]vops.ClientTLSAuthMethod{
+			{Name: "svc_tls", Host: "10.1.0.0/16"}}))
+		Expect(mock.calls[2].Grants).Should(HaveLen(2))
+
+		// Removing the spec drops the methods and forgets the state
+		vdb.Spec.ClientTLSAuth = nil
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(mock.calls).Should(HaveLen(4))
+		Expect(mock.calls[3].DropMethods).Should(Equal([]string{"svc_tls"}))
+		Expect(mock.calls[3].CreateMethods).Should(BeEmpty())
+		Expect(vdb.Status.ClientTLSAuth).Should(BeNil())
+	})
+
+	It("should only revoke a matched grantee while its VerticaUser exists", func() {
+		applied := &vapi.TLSAuthMethodStatus{Name: "svc_tls", Grantees: []string{"etl"},
+			MatchedGrantees: []string{"svc-a", "svc-b"}}
+		g := &tlsAuthGrantees{Users: []string{"svc-a"}}
+		Expect(revocableTLSAuthGrantees(applied, g)).Should(Equal([]string{"etl", "svc-a"}))
+
+		named, matched := resolveTLSAuthGrantees([]string{"etl", "svc-*"}, []string{"svc-a", "app"})
+		Expect(named).Should(Equal([]string{"etl"}))
+		Expect(matched).Should(Equal([]string{"svc-a"}))
+
+		// A user that was matched stays a candidate while it isn't ready
+		Expect(matchCandidates([]string{"app"}, []string{"app", "svc-a", "svc-b"}, []string{"svc-a", "svc-c"})).
+			Should(Equal([]string{"app", "svc-a"}))
+	})
+})
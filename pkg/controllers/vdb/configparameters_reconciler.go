@@ -0,0 +1,294 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vdb
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/pkg/cmds"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	"github.com/vertica/vertica-kubernetes/pkg/events"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/podfacts"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/getconfigparameter"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/setconfigparameter"
+	"github.com/vertica/vertica-kubernetes/pkg/vdbstatus"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// ConfigParametersReconciler will keep the configuration parameters in the
+// database in sync with spec.configurationParameters
+type ConfigParametersReconciler struct {
+	VRec       *VerticaDBReconciler
+	Vdb        *vapi.VerticaDB // Vdb is the CRD we are acting on.
+	Log        logr.Logger
+	PRunner    cmds.PodRunner
+	Dispatcher vadmin.Dispatcher
+	PFacts     *podfacts.PodFacts
+}
+
+func MakeConfigParametersReconciler(vdbrecon *VerticaDBReconciler, log logr.Logger, vdb *vapi.VerticaDB,
+	prunner cmds.PodRunner, dispatcher vadmin.Dispatcher, pfacts *podfacts.PodFacts) controllers.ReconcileActor {
+	return &ConfigParametersReconciler{
+		VRec:       vdbrecon,
+		Vdb:        vdb,
+		Log:        log.WithName("ConfigParametersReconciler"),
+		PRunner:    prunner,
+		Dispatcher: dispatcher,
+		PFacts:     pfacts,
+	}
+}
+
+// Reconcile will set any configuration parameter whose value in the database
+// differs from the one in the spec, and clear the ones that were set before
+// but are no longer in the spec. It then records the parameters that need a
+// restart to take effect.
+func (c *ConfigParametersReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (ctrl.Result, error) {
+	if len(c.Vdb.Spec.ConfigurationParameters) == 0 && len(c.Vdb.Status.ConfigurationParametersPendingRestart) == 0 &&
+		len(c.Vdb.Status.AppliedConfigurationParameters) == 0 {
+		return ctrl.Result{}, nil
+	}
+	if !c.Vdb.UseVClusterOpsDeployment() || !c.Vdb.IsDBInitialized() {
+		return ctrl.Result{}, nil
+	}
+
+	if err := c.PFacts.Collect(ctx, c.Vdb); err != nil {
+		return ctrl.Result{}, err
+	}
+	initiator, ok := c.PFacts.FindFirstUpPod(false, "")
+	if !ok {
+		c.Log.Info("No up pod found to set the configuration parameters. Requeue reconciliation.")
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	applied := []vapi.AppliedConfigurationParameter{}
+	for _, name := range slices.Sorted(maps.Keys(c.Vdb.Spec.ConfigurationParameters)) {
+		param := c.Vdb.Spec.ConfigurationParameters[name]
+		levels := c.getLevels(&param)
+		if err := c.reconcileParameter(ctx, initiator.GetPodIP(), name, param.Value, levels); err != nil {
+			return ctrl.Result{}, err
+		}
+		applied = append(applied, vapi.AppliedConfigurationParameter{Name: name, Levels: levels})
+	}
+	if err := c.clearRemovedParameters(ctx, initiator.GetPodIP(), applied); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := c.updateApplied(ctx, applied); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, c.updatePendingRestart(ctx, initiator)
+}
+
+// clearRemovedParameters will clear each configuration parameter in the
+// status at the levels it is no longer set at. That is the case when the
+// parameter was removed from the spec or its scope changed. A node level for
+// a node that is no longer in the database is dropped without clearing it.
+func (c *ConfigParametersReconciler) clearRemovedParameters(ctx context.Context, initiatorIP string,
+	applied []vapi.AppliedConfigurationParameter) error {
+	// configuration parameter names are case insensitive
+	desired := map[string]map[string]bool{}
+	for i := range applied {
+		levels := map[string]bool{}
+		for _, level := range applied[i].Levels {
+			levels[level] = true
+		}
+		desired[strings.ToLower(applied[i].Name)] = levels
+	}
+	for i := range c.Vdb.Status.AppliedConfigurationParameters {
+		prev := &c.Vdb.Status.AppliedConfigurationParameters[i]
+		for _, level := range prev.Levels {
+			if desired[strings.ToLower(prev.Name)][level] || !c.isLevelInDB(level) {
+				continue
+			}
+			c.Log.Info("Clearing configuration parameter", "name", prev.Name, "level", level)
+			err := c.Dispatcher.SetConfigurationParameter(ctx,
+				setconfigparameter.WithUserName(c.Vdb.GetVerticaUser()),
+				setconfigparameter.WithInitiatorIP(initiatorIP),
+				setconfigparameter.WithConfigParameter(prev.Name),
+				setconfigparameter.WithValue(configParamClearValue),
+				setconfigparameter.WithLevel(level),
+			)
+			if err != nil {
+				c.VRec.Eventf(c.Vdb, corev1.EventTypeWarning, events.ConfigParameterChangeFailed,
+					"Failed to clear configuration parameter %q%s", prev.Name, describeLevel(level))
+				return err
+			}
+			c.VRec.Eventf(c.Vdb, corev1.EventTypeNormal, events.ConfigParameterCleared,
+				"Cleared configuration parameter %q%s because it was removed from the spec", prev.Name, describeLevel(level))
+		}
+	}
+	return nil
+}
+
+// isLevelInDB returns true if the level is the database level or the node
+// level of a node that is in the database
+func (c *ConfigParametersReconciler) isLevelInDB(level string) bool {
+	if level == ConfigParamLevelDatabase {
+		return true
+	}
+	for _, pf := range c.PFacts.Detail {
+		if pf.GetDBExists() && pf.GetVnodeName() != "" && genNodeConfigParamLevel(pf.GetVnodeName()) == level {
+			return true
+		}
+	}
+	return false
+}
+
+// updateApplied will record the configuration parameters that are set in the
+// database, and the levels they are set at, in the status
+func (c *ConfigParametersReconciler) updateApplied(ctx context.Context, applied []vapi.AppliedConfigurationParameter) error {
+	if len(applied) == 0 {
+		applied = nil
+	}
+	if reflect.DeepEqual(applied, c.Vdb.Status.AppliedConfigurationParameters) {
+		return nil
+	}
+	return vdbstatus.SetAppliedConfigurationParameters(ctx, c.VRec.Client, c.Vdb, applied)
+}
+
+// reconcileParameter will set a single configuration parameter at each of the
+// levels it applies to, if its current value differs from the desired one.
+func (c *ConfigParametersReconciler) reconcileParameter(ctx context.Context, initiatorIP, name, value string,
+	levels []string) error {
+	for _, level := range levels {
+		current, err := c.Dispatcher.GetConfigurationParameter(ctx,
+			getconfigparameter.WithUserName(c.Vdb.GetVerticaUser()),
+			getconfigparameter.WithInitiatorIP(initiatorIP),
+			getconfigparameter.WithConfigParameter(name),
+			getconfigparameter.WithLevel(level),
+		)
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(current) == value {
+			continue
+		}
+
+		c.Log.Info("Setting configuration parameter", "name", name, "level", level, "currentValue", current,
+			"newValue", value)
+		err = c.Dispatcher.SetConfigurationParameter(ctx,
+			setconfigparameter.WithUserName(c.Vdb.GetVerticaUser()),
+			setconfigparameter.WithInitiatorIP(initiatorIP),
+			setconfigparameter.WithConfigParameter(name),
+			setconfigparameter.WithValue(value),
+			setconfigparameter.WithLevel(level),
+		)
+		if err != nil {
+			c.VRec.Eventf(c.Vdb, corev1.EventTypeWarning, events.ConfigParameterChangeFailed,
+				"Failed to set configuration parameter %q%s to %q", name, describeLevel(level), value)
+			return err
+		}
+		c.VRec.Eventf(c.Vdb, corev1.EventTypeNormal, events.ConfigParameterChanged,
+			"Changed configuration parameter %q%s from %q to %q", name, describeLevel(level), current, value)
+	}
+	return nil
+}
+
+// getLevels returns the levels to set a configuration parameter at. A
+// subcluster scope is expanded to each node in the subcluster that is in the
+// database.
+func (c *ConfigParametersReconciler) getLevels(param *vapi.ConfigurationParameter) []string {
+	switch {
+	case param.Node != "":
+		return []string{genNodeConfigParamLevel(param.Node)}
+	case param.Subcluster != "":
+		levels := []string{}
+		for _, pf := range c.PFacts.Detail {
+			if pf.GetSubclusterName() == param.Subcluster && pf.GetDBExists() && pf.GetVnodeName() != "" {
+				levels = append(levels, genNodeConfigParamLevel(pf.GetVnodeName()))
+			}
+		}
+		slices.Sort(levels)
+		return levels
+	default:
+		return []string{ConfigParamLevelDatabase}
+	}
+}
+
+// updatePendingRestart will query the database for the configuration
+// parameters in the spec whose new value only takes effect after a restart,
+// and store them in the status.
+func (c *ConfigParametersReconciler) updatePendingRestart(ctx context.Context, initiator *podfacts.PodFact) error {
+	pending := []string{}
+	if len(c.Vdb.Spec.ConfigurationParameters) > 0 {
+		// configuration parameter names are case insensitive, so we map the
+		// names returned by the database back to the names in the spec
+		specNames := map[string]string{}
+		quoted := []string{}
+		for name := range c.Vdb.Spec.ConfigurationParameters {
+			specNames[strings.ToLower(name)] = name
+			quoted = append(quoted, fmt.Sprintf("'%s'", strings.ToLower(name)))
+		}
+		slices.Sort(quoted)
+		sql := fmt.Sprintf(
+			"select distinct parameter_name"+
+				" from configuration_parameters"+
+				" where change_requires_restart"+
+				" and current_value <> restart_value"+
+				" and lower(parameter_name) in (%s)", strings.Join(quoted, ","))
+		stdout, stderr, err := c.PRunner.ExecVSQL(ctx, initiator.GetName(), names.ServerContainer, "-tAc", sql)
+		if err != nil {
+			c.Log.Error(err, "failed to find the configuration parameters that need a restart", "stderr", stderr)
+			return err
+		}
+		for _, line := range strings.Split(stdout, "\n") {
+			if name, ok := specNames[strings.ToLower(strings.TrimSpace(line))]; ok {
+				pending = append(pending, name)
+			}
+		}
+		slices.Sort(pending)
+	}
+
+	if slices.Equal(pending, c.Vdb.Status.ConfigurationParametersPendingRestart) {
+		return nil
+	}
+	if len(pending) > 0 {
+		c.VRec.Eventf(c.Vdb, corev1.EventTypeWarning, events.ConfigParametersPendingRestart,
+			"The database must be restarted for these configuration parameters to take effect: %s",
+			strings.Join(pending, ", "))
+	}
+	if len(pending) == 0 {
+		pending = nil
+	}
+	return vdbstatus.SetConfigurationParametersPendingRestart(ctx, c.VRec.Client, c.Vdb, pending)
+}
+
+// configParamClearValue is the value that clears a configuration parameter at
+// a level, so that it goes back to its default or inherited value
+const configParamClearValue = "null"
+
+// genNodeConfigParamLevel returns the level to set a configuration parameter
+// for a single node
+func genNodeConfigParamLevel(vnodeName string) string {
+	return fmt.Sprintf("NODE %s", vnodeName)
+}
+
+// describeLevel returns a description of the level for use in events
+func describeLevel(level string) string {
+	if level == ConfigParamLevelDatabase {
+		return ""
+	}
+	return fmt.Sprintf(" at level %q", level)
+}
@@ -0,0 +1,170 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vdb
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	vops "github.com/vertica/vcluster/vclusterops"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/pkg/cmds"
+	"github.com/vertica/vertica-kubernetes/pkg/mockvops"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/test"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// mockConfigParameterVClusterOps records the configuration parameters that are
+// set
+type mockConfigParameterVClusterOps struct {
+	mockvops.MockVClusterOps
+	set []vops.VSetConfigurationParameterOptions
+}
+
+func (m *mockConfigParameterVClusterOps) VSetConfigurationParameters(options *vops.VSetConfigurationParameterOptions) error {
+	m.set = append(m.set, vops.VSetConfigurationParameterOptions{
+		ConfigParameter: options.ConfigParameter,
+		Value:           options.Value,
+		Level:           options.Level,
+	})
+	return nil
+}
+
+var _ = Describe("configparameters_reconciler", func() {
+	ctx := context.Background()
+
+	It("should be a no-op if no configuration parameters are set", func() {
+		vdb := vapi.MakeVDBForVclusterOps()
+		fpr := &cmds.FakePodRunner{}
+		pfacts := createPodFactsDefault(fpr)
+		r := MakeConfigParametersReconciler(vdbRec, logger, vdb, fpr, mockVClusterOpsDispatcher(vdb), pfacts)
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(fpr.Histories).Should(BeEmpty())
+	})
+
+	It("should set the parameters and record the ones pending a restart", func() {
+		vdb := vapi.MakeVDBForVclusterOps()
+		vdb.Spec.ConfigurationParameters = map[string]vapi.ConfigurationParameter{
+			"MaxClientSessions": {Value: "100"},
+			"EnableSSL":         {Value: "1", Subcluster: vdb.Spec.Subclusters[0].Name},
+		}
+		vdb.Spec.HTTPSNMATLS.Secret = "config-params-tls"
+		test.CreateFakeTLSSecret(ctx, vdb, k8sClient, vdb.GetHTTPSNMATLSSecret())
+		defer test.DeleteSecret(ctx, k8sClient, vdb.GetHTTPSNMATLSSecret())
+		test.CreateVDB(ctx, k8sClient, vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+		test.CreatePods(ctx, k8sClient, vdb, test.AllPodsRunning)
+		defer test.DeletePods(ctx, k8sClient, vdb)
+		meta.SetStatusCondition(&vdb.Status.Conditions,
+			*vapi.MakeCondition(vapi.DBInitialized, metav1.ConditionTrue, "Initialized"))
+		Expect(k8sClient.Status().Update(ctx, vdb)).Should(Succeed())
+
+		fpr := &cmds.FakePodRunner{Results: cmds.CmdResults{}}
+		pfacts := createPodFactsDefault(fpr)
+		// collect the pod facts first so that they don't consume the vsql result
+		Expect(pfacts.Collect(ctx, vdb)).Should(Succeed())
+		pn := names.GenPodName(vdb, &vdb.Spec.Subclusters[0], 0)
+		fpr.Results[pn] = []cmds.CmdResult{{Stdout: "maxclientsessions\n"}}
+		r := MakeConfigParametersReconciler(vdbRec, logger, vdb, fpr, mockVClusterOpsDispatcher(vdb), pfacts)
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+
+		vsqlCmds := fpr.FindCommands("from configuration_parameters")
+		Expect(vsqlCmds).Should(HaveLen(1))
+		Expect(vsqlCmds[0].Pod).Should(Equal(pn))
+		fetchedVdb := &vapi.VerticaDB{}
+		Expect(k8sClient.Get(ctx, vdb.ExtractNamespacedName(), fetchedVdb)).Should(Succeed())
+		Expect(fetchedVdb.Status.ConfigurationParametersPendingRestart).Should(Equal([]string{"MaxClientSessions"}))
+
+		// The status is cleared once nothing is pending
+		fpr.Results[pn] = []cmds.CmdResult{{Stdout: ""}}
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(k8sClient.Get(ctx, vdb.ExtractNamespacedName(), fetchedVdb)).Should(Succeed())
+		Expect(fetchedVdb.Status.ConfigurationParametersPendingRestart).Should(BeEmpty())
+	})
+
+	It("should clear the parameters that are no longer in the spec", func() {
+		vdb := vapi.MakeVDBForVclusterOps()
+		vdb.Spec.ConfigurationParameters = map[string]vapi.ConfigurationParameter{
+			"MaxClientSessions": {Value: "100"},
+		}
+		vdb.Spec.HTTPSNMATLS.Secret = "clear-config-params-tls"
+		test.CreateFakeTLSSecret(ctx, vdb, k8sClient, vdb.GetHTTPSNMATLSSecret())
+		defer test.DeleteSecret(ctx, k8sClient, vdb.GetHTTPSNMATLSSecret())
+		test.CreateVDB(ctx, k8sClient, vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+		test.CreatePods(ctx, k8sClient, vdb, test.AllPodsRunning)
+		defer test.DeletePods(ctx, k8sClient, vdb)
+		meta.SetStatusCondition(&vdb.Status.Conditions,
+			*vapi.MakeCondition(vapi.DBInitialized, metav1.ConditionTrue, "Initialized"))
+		vdb.Status.AppliedConfigurationParameters = []vapi.AppliedConfigurationParameter{
+			// EnableSSL was removed from the spec. The node that is no longer
+			// in the database is skipped.
+			{Name: "EnableSSL", Levels: []string{"NODE v_db_node0001", "NODE v_db_node0009"}},
+			{Name: "MaxClientSessions", Levels: []string{ConfigParamLevelDatabase}},
+		}
+		Expect(k8sClient.Status().Update(ctx, vdb)).Should(Succeed())
+
+		fpr := &cmds.FakePodRunner{}
+		pfacts := createPodFactsDefault(fpr)
+		Expect(pfacts.Collect(ctx, vdb)).Should(Succeed())
+		setVerticaNodeNameInPodFacts(vdb, &vdb.Spec.Subclusters[0], pfacts)
+		mock := &mockConfigParameterVClusterOps{}
+		setupAPIFunc := func(logr.Logger, string) (vadmin.VClusterProvider, logr.Logger) {
+			return mock, logr.Logger{}
+		}
+		dispatcher := mockvops.MakeMockVClusterOpsDispatcher(vdb, logger, k8sClient, setupAPIFunc)
+		r := MakeConfigParametersReconciler(vdbRec, logger, vdb, fpr, dispatcher, pfacts)
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+
+		// The mock returns an empty current value, so MaxClientSessions is
+		// set, and EnableSSL is cleared on the node that is still in the db.
+		Expect(mock.set).Should(ConsistOf(
+			vops.VSetConfigurationParameterOptions{ConfigParameter: "MaxClientSessions", Value: "100",
+				Level: ConfigParamLevelDatabase},
+			vops.VSetConfigurationParameterOptions{ConfigParameter: "EnableSSL", Value: configParamClearValue,
+				Level: "NODE v_db_node0001"},
+		))
+		fetchedVdb := &vapi.VerticaDB{}
+		Expect(k8sClient.Get(ctx, vdb.ExtractNamespacedName(), fetchedVdb)).Should(Succeed())
+		Expect(fetchedVdb.Status.AppliedConfigurationParameters).Should(Equal([]vapi.AppliedConfigurationParameter{
+			{Name: "MaxClientSessions", Levels: []string{ConfigParamLevelDatabase}},
+		}))
+	})
+
+	It("should expand a subcluster scope to each node in the subcluster", func() {
+		vdb := vapi.MakeVDBForVclusterOps()
+		vdb.Spec.Subclusters[0].Size = 2
+		test.CreatePods(ctx, k8sClient, vdb, test.AllPodsRunning)
+		defer test.DeletePods(ctx, k8sClient, vdb)
+		fpr := &cmds.FakePodRunner{}
+		pfacts := createPodFactsDefault(fpr)
+		Expect(pfacts.Collect(ctx, vdb)).Should(Succeed())
+		setVerticaNodeNameInPodFacts(vdb, &vdb.Spec.Subclusters[0], pfacts)
+		r := MakeConfigParametersReconciler(vdbRec, logger, vdb, fpr, mockVClusterOpsDispatcher(vdb), pfacts)
+		c := r.(*ConfigParametersReconciler)
+		Expect(c.getLevels(&vapi.ConfigurationParameter{Value: "1"})).Should(Equal([]string{ConfigParamLevelDatabase}))
+		Expect(c.getLevels(&vapi.ConfigurationParameter{Value: "1", Node: "v_db_node0005"})).
+			Should(Equal([]string{"NODE v_db_node0005"}))
+		Expect(c.getLevels(&vapi.ConfigurationParameter{Value: "1", Subcluster: vdb.Spec.Subclusters[0].Name})).
+			Should(Equal([]string{"NODE v_db_node0001", "NODE v_db_node0002"}))
+	})
+})
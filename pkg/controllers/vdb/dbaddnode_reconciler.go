@@ -59,8 +59,7 @@ func MakeDBAddNodeReconciler(vdbrecon *VerticaDBReconciler, log logr.Logger,
 
 // Reconcile will ensure a DB exists and create one if it doesn't
 func (d *DBAddNodeReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (ctrl.Result, error) {
-	// no-op for ScheduleOnly init policy
-	if d.Vdb.Spec.InitPolicy == vapi.CommunalInitPolicyScheduleOnly {
+	if d.shouldSkipReconcile() {
 		return ctrl.Result{}, nil
 	}
 
@@ -78,8 +77,7 @@ func (d *DBAddNodeReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (c
 	scStatusMap := d.Vdb.GenSubclusterStatusMap()
 	for i := range d.Vdb.Spec.Subclusters {
 		sc := &d.Vdb.Spec.Subclusters[i]
-		scStatus, found := scStatusMap[sc.Name]
-		if found && scStatus.Shutdown {
+		if isSubclusterShutdown(scStatusMap, sc.Name) {
 			// subclusters that have been shut down must
 			// be ignored.
 			continue
@@ -108,10 +106,7 @@ func (d *DBAddNodeReconciler) findAddNodePods(scName string) ([]*podfacts.PodFac
 		if v.GetSubclusterName() != scName {
 			continue
 		}
-		if !v.GetDBExists() {
-			if v.GetShutdown() {
-				continue
-			}
+		if needsAddNode(v) {
 			if !v.GetIsPodRunning() || !v.GetIsInstalled() {
 				// We want to group all of the add nodes in a single admintools call.
 				// Doing so limits the impact on any running queries.  So if there is at
@@ -197,3 +192,39 @@ func (d *DBAddNodeReconciler) runAddNodeForPod(ctx context.Context,
 		"Successfully added database nodes and it took %s", time.Since(start).Truncate(time.Second))
 	return nil
 }
+
+// shouldSkipReconcile returns true if add node is never run. This is the
+// case for the ScheduleOnly init policy.
+func (d *DBAddNodeReconciler) shouldSkipReconcile() bool {
+	return d.Vdb.Spec.InitPolicy == vapi.CommunalInitPolicyScheduleOnly
+}
+
+// needsAddNode returns true if the pod needs to be added to the database
+func needsAddNode(pf *podfacts.PodFact) bool {
+	return !pf.GetDBExists() && !pf.GetShutdown()
+}
+
+// isSubclusterShutdown returns true if the status shows that the subcluster
+// has been shut down. Add node ignores those subclusters.
+func isSubclusterShutdown(scStatusMap map[string]*vapi.SubclusterStatus, scName string) bool {
+	scStatus, found := scStatusMap[scName]
+	return found && scStatus.Shutdown
+}
+
+// Plan reports the pods that would be added to the database, including the
+// ones that will be created by a scale out
+func (d *DBAddNodeReconciler) Plan(ctx context.Context) ([]vapi.PlannedAction, error) {
+	if d.shouldSkipReconcile() || !d.Vdb.IsDBInitialized() {
+		return nil, nil
+	}
+	if err := d.PFacts.Collect(ctx, d.Vdb); err != nil {
+		return nil, err
+	}
+	scNames, pods := findPodsToAddToDB(d.Vdb, d.PFacts)
+	actions := []vapi.PlannedAction{}
+	for _, scName := range scNames {
+		actions = append(actions, makePlannedAction("DBAddNode", scName, pods[scName],
+			"Add %d nodes to subcluster %s", len(pods[scName]), scName))
+	}
+	return actions, nil
+}
@@ -60,8 +60,7 @@ func MakeDBAddSubclusterReconciler(vdbrecon *VerticaDBReconciler, log logr.Logge
 
 // Reconcile will ensure a subcluster exists for each one defined in the vdb.
 func (d *DBAddSubclusterReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (ctrl.Result, error) {
-	// no-op for ScheduleOnly init policy or if not EON
-	if d.Vdb.Spec.InitPolicy == vapi.CommunalInitPolicyScheduleOnly || !d.Vdb.IsEON() {
+	if d.shouldSkipReconcile() {
 		return ctrl.Result{}, nil
 	}
 
@@ -147,3 +146,41 @@ func (d *DBAddSubclusterReconciler) createSubcluster(ctx context.Context, sc *va
 		"Added new subcluster '%s'", sc.Name)
 	return nil
 }
+
+// shouldSkipReconcile returns true if subclusters are never added. This is
+// the case for the ScheduleOnly init policy and for Enterprise databases.
+func (d *DBAddSubclusterReconciler) shouldSkipReconcile() bool {
+	return d.Vdb.Spec.InitPolicy == vapi.CommunalInitPolicyScheduleOnly || !d.Vdb.IsEON()
+}
+
+// Plan reports the subclusters that would be added to the database
+func (d *DBAddSubclusterReconciler) Plan(ctx context.Context) ([]vapi.PlannedAction, error) {
+	if d.shouldSkipReconcile() || !d.Vdb.IsDBInitialized() {
+		return nil, nil
+	}
+	if err := d.PFacts.Collect(ctx, d.Vdb); err != nil {
+		return nil, err
+	}
+	// A subcluster is added when none of its pods are part of the database
+	scNames, _ := groupPodsBySubcluster(d.PFacts, func(pf *podfacts.PodFact) bool {
+		return pf.GetDBExists()
+	})
+	inDB := map[string]bool{}
+	for _, scName := range scNames {
+		inDB[scName] = true
+	}
+	scSbMap := d.Vdb.GenSubclusterSandboxMap()
+	actions := []vapi.PlannedAction{}
+	for i := range d.Vdb.Spec.Subclusters {
+		sc := &d.Vdb.Spec.Subclusters[i]
+		if _, ok := scSbMap[sc.Name]; ok || inDB[sc.Name] {
+			continue
+		}
+		if _, ok := d.Vdb.FindSubclusterStatus(sc.Name); ok {
+			continue
+		}
+		actions = append(actions, makePlannedAction("DBAddSubcluster", sc.Name, nil,
+			"Add %s subcluster %s to the database", sc.Type, sc.Name))
+	}
+	return actions, nil
+}
@@ -79,8 +79,7 @@ func (d *DBRemoveNodeReconciler) CollectPFacts(ctx context.Context) error {
 // everything in Vdb. We will know if we are scaling in by comparing the
 // expected subcluster size with the current.
 func (d *DBRemoveNodeReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (ctrl.Result, error) {
-	// no-op for ScheduleOnly init policy
-	if d.Vdb.Spec.InitPolicy == vapi.CommunalInitPolicyScheduleOnly {
+	if d.shouldSkipReconcile() {
 		return ctrl.Result{}, nil
 	}
 
@@ -99,11 +98,7 @@ func (d *DBRemoveNodeReconciler) Reconcile(ctx context.Context, _ *ctrl.Request)
 		return ctrl.Result{Requeue: changed}, err
 	}
 
-	// Use the finder so that we check only the subclusters that are in the vdb.
-	// Any nodes that are in subclusters that we are removing are handled by the
-	// DBRemoveSubcusterReconciler.
-	finder := iter.MakeSubclusterFinder(d.VRec.Client, d.Vdb)
-	subclusters, err := finder.FindSubclusters(ctx, iter.FindInVdb, vapi.MainCluster)
+	subclusters, err := d.findSubclustersToScaleIn(ctx)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -228,3 +223,46 @@ func (d *DBRemoveNodeReconciler) updateSubclusterStatus(ctx context.Context, rem
 	}
 	return vdbstatus.Update(ctx, d.VRec.Client, d.Vdb, refreshInPlace)
 }
+
+// shouldSkipReconcile returns true if nodes are never removed. This is the
+// case for the ScheduleOnly init policy.
+func (d *DBRemoveNodeReconciler) shouldSkipReconcile() bool {
+	return d.Vdb.Spec.InitPolicy == vapi.CommunalInitPolicyScheduleOnly
+}
+
+// findSubclustersToScaleIn returns the subclusters to check for scale in.
+// Only the subclusters that are in the vdb are checked. Any nodes that are in
+// subclusters that we are removing are handled by the
+// DBRemoveSubclusterReconciler.
+func (d *DBRemoveNodeReconciler) findSubclustersToScaleIn(ctx context.Context) ([]*vapi.Subcluster, error) {
+	finder := iter.MakeSubclusterFinder(d.VRec.Client, d.Vdb)
+	return finder.FindSubclusters(ctx, iter.FindInVdb, vapi.MainCluster)
+}
+
+// Plan reports the nodes that would be removed from the subclusters that are
+// being scaled in
+func (d *DBRemoveNodeReconciler) Plan(ctx context.Context) ([]vapi.PlannedAction, error) {
+	if d.shouldSkipReconcile() {
+		return nil, nil
+	}
+	if err := d.PFacts.Collect(ctx, d.Vdb); err != nil {
+		return nil, err
+	}
+	subclusters, err := d.findSubclustersToScaleIn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	inScope := map[string]bool{}
+	for _, sc := range subclusters {
+		inScope[sc.Name] = true
+	}
+	scNames, pods := groupPodsBySubcluster(d.PFacts, func(p *pf.PodFact) bool {
+		return inScope[p.GetSubclusterName()] && p.GetDBExists() && p.GetIsPendingDelete()
+	})
+	actions := []vapi.PlannedAction{}
+	for _, scName := range scNames {
+		actions = append(actions, makePlannedAction("DBRemoveNode", scName, pods[scName],
+			"Remove %d nodes from subcluster %s", len(pods[scName]), scName))
+	}
+	return actions, nil
+}
@@ -67,8 +67,7 @@ func MakeDBRemoveSubclusterReconciler(vdbrecon *VerticaDBReconciler, log logr.Lo
 
 // Reconcile will remove any subcluster that no longer exists in the vdb.
 func (d *DBRemoveSubclusterReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (ctrl.Result, error) {
-	// no-op for ScheduleOnly init policy
-	if d.Vdb.Spec.InitPolicy == vapi.CommunalInitPolicyScheduleOnly {
+	if d.shouldSkipReconcile() {
 		return ctrl.Result{}, nil
 	}
 
@@ -101,9 +100,7 @@ func (d *DBRemoveSubclusterReconciler) Reconcile(ctx context.Context, _ *ctrl.Re
 
 // removeExtraSubclusters will compare subclusters in vertica with vdb and remove any extra ones
 func (d *DBRemoveSubclusterReconciler) removeExtraSubclusters(ctx context.Context) (ctrl.Result, error) {
-	finder := iter.MakeSubclusterFinder(d.VRec.Client, d.Vdb)
-	// Find all subclusters not in the vdb.  These are the ones we want to remove.
-	subclusters, err := finder.FindSubclusters(ctx, iter.FindNotInVdb, vapi.MainCluster)
+	subclusters, err := d.findSubclustersToRemove(ctx)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -262,3 +259,39 @@ func (d *DBRemoveSubclusterReconciler) changeDefaultSubcluster(ctx context.Conte
 	_, _, err := d.PRunner.ExecVSQL(ctx, d.ATPod.GetName(), names.ServerContainer, cmd...)
 	return err
 }
+
+// shouldSkipReconcile returns true if subclusters are never removed. This is
+// the case for the ScheduleOnly init policy.
+func (d *DBRemoveSubclusterReconciler) shouldSkipReconcile() bool {
+	return d.Vdb.Spec.InitPolicy == vapi.CommunalInitPolicyScheduleOnly
+}
+
+// findSubclustersToRemove returns the subclusters that have objects but are
+// no longer in the vdb. These are the ones we want to remove.
+func (d *DBRemoveSubclusterReconciler) findSubclustersToRemove(ctx context.Context) ([]*vapi.Subcluster, error) {
+	finder := iter.MakeSubclusterFinder(d.VRec.Client, d.Vdb)
+	return finder.FindSubclusters(ctx, iter.FindNotInVdb, vapi.MainCluster)
+}
+
+// Plan reports the subclusters that would be removed from the database
+func (d *DBRemoveSubclusterReconciler) Plan(ctx context.Context) ([]vapi.PlannedAction, error) {
+	if d.shouldSkipReconcile() {
+		return nil, nil
+	}
+	if err := d.PFacts.Collect(ctx, d.Vdb); err != nil {
+		return nil, err
+	}
+	subclusters, err := d.findSubclustersToRemove(ctx)
+	if err != nil {
+		return nil, err
+	}
+	actions := []vapi.PlannedAction{}
+	for _, sc := range subclusters {
+		_, pods := groupPodsBySubcluster(d.PFacts, func(pf *podfacts.PodFact) bool {
+			return pf.GetSubclusterName() == sc.Name && pf.GetDBExists()
+		})
+		actions = append(actions, makePlannedAction("DBRemoveSubcluster", sc.Name, pods[sc.Name],
+			"Remove subcluster %s and its nodes from the database", sc.Name))
+	}
+	return actions, nil
+}
@@ -54,6 +54,11 @@ func MakeDrainNodeReconciler(vdbrecon *VerticaDBReconciler,
 // as pending delete.  This will drain those pods that we are going to scale
 // down before we actually remove them from the cluster.
 func (s *DrainNodeReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (ctrl.Result, error) {
+	// If timeout is zero, we move on to the next reconciler (DBRemoveNodeReconciler|DBRemoveSubclusterReconciler)
+	if s.shouldSkipReconcile() {
+		return ctrl.Result{}, nil
+	}
+
 	if err := s.PFacts.Collect(ctx, s.Vdb); err != nil {
 		return ctrl.Result{}, err
 	}
@@ -61,10 +66,6 @@ func (s *DrainNodeReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (c
 	// Note: this reconciler depends on the client routing reconciler to have run
 	// and directed traffic away from pending delete pods.
 	timeoutInt := s.Vdb.GetActiveConnectionsDrainSeconds()
-	// If timeout is zero, we move on to the next reconciler (DBRemoveNodeReconciler|DBRemoveSubclusterReconciler)
-	if timeoutInt == 0 {
-		return ctrl.Result{}, nil
-	}
 
 	pfs, err := s.getPendingDeletePods(ctx)
 	if err != nil {
@@ -225,3 +226,30 @@ func calculateRequeueDelay(elapsed, timeout time.Duration) time.Duration {
 	// Requeue more frequently as you get closer to timeout
 	return 1 * time.Second
 }
+
+// shouldSkipReconcile returns true if connections are not drained before
+// nodes are removed. This is the case when the drain timeout is zero.
+func (s *DrainNodeReconciler) shouldSkipReconcile() bool {
+	return s.Vdb.GetActiveConnectionsDrainSeconds() == 0
+}
+
+// Plan reports the pods whose connections would be drained before they are
+// removed from the database.
+func (s *DrainNodeReconciler) Plan(ctx context.Context) ([]vapi.PlannedAction, error) {
+	if s.shouldSkipReconcile() {
+		return nil, nil
+	}
+	timeout := s.Vdb.GetActiveConnectionsDrainSeconds()
+	if err := s.PFacts.Collect(ctx, s.Vdb); err != nil {
+		return nil, err
+	}
+	scNames, pods := groupPodsBySubcluster(s.PFacts, func(pf *podfacts.PodFact) bool {
+		return pf.GetIsPendingDelete() && pf.GetUpNode()
+	})
+	actions := []vapi.PlannedAction{}
+	for _, scName := range scNames {
+		actions = append(actions, makePlannedAction("DrainNode", scName, pods[scName],
+			"Drain active connections for up to %d seconds", timeout))
+	}
+	return actions, nil
+}
@@ -0,0 +1,153 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vdb
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/pkg/builder"
+	"github.com/vertica/vertica-kubernetes/pkg/cloud"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	verrors "github.com/vertica/vertica-kubernetes/pkg/errors"
+	vmeta "github.com/vertica/vertica-kubernetes/pkg/meta"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/secrets"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// ExternalSecretMirrorReconciler copies the secrets that are stored in a
+// secret store that Vertica cannot read from, such as HashiCorp Vault or
+// Azure Key Vault, into Kubernetes secrets. Those are the license and the TLS
+// secrets, which are mounted in the pods or read by Vertica and NMA.
+type ExternalSecretMirrorReconciler struct {
+	VRec          *VerticaDBReconciler
+	Vdb           *vapi.VerticaDB // Vdb is the CRD we are acting on.
+	Log           logr.Logger
+	SecretFetcher cloud.SecretFetcher
+}
+
+func MakeExternalSecretMirrorReconciler(vdbrecon *VerticaDBReconciler, log logr.Logger,
+	vdb *vapi.VerticaDB) controllers.ReconcileActor {
+	return &ExternalSecretMirrorReconciler{
+		VRec: vdbrecon,
+		Vdb:  vdb,
+		Log:  log.WithName("ExternalSecretMirrorReconciler"),
+		SecretFetcher: cloud.SecretFetcher{
+			Client:   vdbrecon.GetClient(),
+			Log:      log.WithName("ExternalSecretMirrorReconciler"),
+			Obj:      vdb,
+			EVWriter: vdbrecon,
+		},
+	}
+}
+
+// Reconcile will create or update the Kubernetes secret that mirrors each
+// secret stored in Vault or Azure Key Vault.
+func (e *ExternalSecretMirrorReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (ctrl.Result, error) {
+	for _, secretName := range e.getMirroredSecrets() {
+		if res, err := e.reconcileMirror(ctx, secretName); verrors.IsReconcileAborted(res, err) {
+			return res, err
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// getMirroredSecrets returns the secrets referenced in the vdb that need a
+// mirror. The TLS secrets in use are included so that a rollback to them is
+// possible.
+func (e *ExternalSecretMirrorReconciler) getMirroredSecrets() []string {
+	candidates := []string{
+		e.Vdb.Spec.LicenseSecret,
+		e.Vdb.GetNMATLSSecret(),
+		e.Vdb.GetHTTPSNMATLSSecret(),
+		e.Vdb.GetHTTPSNMATLSSecretInUse(),
+		e.Vdb.GetClientServerTLSSecret(),
+		e.Vdb.GetClientServerTLSSecretInUse(),
+	}
+	mirrored := []string{}
+	seen := make(map[string]bool, len(candidates))
+	for _, secretName := range candidates {
+		if seen[secretName] || !secrets.IsMirroredSecret(secretName) {
+			continue
+		}
+		seen[secretName] = true
+		mirrored = append(mirrored, secretName)
+	}
+	return mirrored
+}
+
+// reconcileMirror reads the secret from its secret store and makes sure its
+// Kubernetes mirror has the same contents.
+func (e *ExternalSecretMirrorReconciler) reconcileMirror(ctx context.Context, secretName string) (ctrl.Result, error) {
+	data, res, err := e.SecretFetcher.FetchAllowRequeue(ctx, names.GenNamespacedName(e.Vdb, secretName))
+	if verrors.IsReconcileAborted(res, err) {
+		return res, err
+	}
+
+	nm := names.GenNamespacedName(e.Vdb, secrets.GetMirroredSecretName(secretName))
+	curSec := &corev1.Secret{}
+	err = e.VRec.GetClient().Get(ctx, nm, curSec)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		e.Log.Info("Creating mirror of external secret", "secret", secretName, "mirror", nm.Name)
+		return ctrl.Result{}, e.VRec.GetClient().Create(ctx, builder.BuildMirroredSecret(e.Vdb, nm, secretName, data))
+	}
+
+	updated := false
+	if !reflect.DeepEqual(curSec.Data, data) {
+		curSec.Data = data
+		updated = true
+	}
+	// The mirror can be shared by vdbs in the same namespace that reference
+	// the same external secret. It is only garbage collected once all of
+	// them are gone. Only the vdb that created it is its controller.
+	if !e.isOwnedByVdb(curSec) {
+		ownerRef := e.Vdb.GenerateOwnerReference()
+		isController := false
+		ownerRef.Controller = &isController
+		curSec.OwnerReferences = append(curSec.OwnerReferences, ownerRef)
+		updated = true
+	}
+	if curSec.Annotations[vmeta.MirroredSecretSourceAnnotation] != secretName {
+		if curSec.Annotations == nil {
+			curSec.Annotations = map[string]string{}
+		}
+		curSec.Annotations[vmeta.MirroredSecretSourceAnnotation] = secretName
+		updated = true
+	}
+	if !updated {
+		return ctrl.Result{}, nil
+	}
+	e.Log.Info("Updating mirror of external secret", "secret", secretName, "mirror", nm.Name)
+	return ctrl.Result{}, e.VRec.GetClient().Update(ctx, curSec)
+}
+
+// isOwnedByVdb returns true if the vdb is one of the owners of the secret
+func (e *ExternalSecretMirrorReconciler) isOwnedByVdb(sec *corev1.Secret) bool {
+	for i := range sec.OwnerReferences {
+		if sec.OwnerReferences[i].UID == e.Vdb.UID {
+			return true
+		}
+	}
+	return false
+}
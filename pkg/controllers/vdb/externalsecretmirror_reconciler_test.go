@@ -0,0 +1,91 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vdb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	vmeta "github.com/vertica/vertica-kubernetes/pkg/meta"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/secrets"
+	"github.com/vertica/vertica-kubernetes/pkg/test"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("externalsecretmirror_reconcile", func() {
+	ctx := context.Background()
+
+	It("should mirror a license stored in Vault into a k8s secret", func() {
+		license := "license-v1"
+		vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v1/secret/data/vertica/license" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			Expect(json.NewEncoder(w).Encode(map[string]any{
+				"data": map[string]any{"data": map[string]string{"license.dat": license}},
+			})).Should(Succeed())
+		}))
+		defer vault.Close()
+		GinkgoT().Setenv(secrets.VaultAddrEnv, vault.URL)
+		GinkgoT().Setenv(secrets.VaultTokenEnv, "root-token")
+
+		vdb := vapi.MakeVDB()
+		vdb.Spec.LicenseSecret = "vault://secret/vertica/license"
+		test.CreateVDB(ctx, k8sClient, vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+
+		r := MakeExternalSecretMirrorReconciler(vdbRec, logger, vdb)
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		mirrorName := secrets.GetMirroredSecretName(vdb.Spec.LicenseSecret)
+		defer deleteSecret(ctx, vdb, mirrorName)
+
+		mirror := &corev1.Secret{}
+		Expect(k8sClient.Get(ctx, names.GenNamespacedName(vdb, mirrorName), mirror)).Should(Succeed())
+		Expect(string(mirror.Data["license.dat"])).Should(Equal("license-v1"))
+		Expect(mirror.Annotations[vmeta.MirroredSecretSourceAnnotation]).Should(Equal(vdb.Spec.LicenseSecret))
+		Expect(mirror.OwnerReferences).Should(HaveLen(1))
+
+		license = "license-v2"
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(k8sClient.Get(ctx, names.GenNamespacedName(vdb, mirrorName), mirror)).Should(Succeed())
+		Expect(string(mirror.Data["license.dat"])).Should(Equal("license-v2"))
+	})
+
+	It("should requeue if the external secret does not exist", func() {
+		vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer vault.Close()
+		GinkgoT().Setenv(secrets.VaultAddrEnv, vault.URL)
+		GinkgoT().Setenv(secrets.VaultTokenEnv, "root-token")
+
+		vdb := vapi.MakeVDB()
+		vdb.Spec.LicenseSecret = "vault://secret/vertica/not-there"
+		test.CreateVDB(ctx, k8sClient, vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+
+		r := MakeExternalSecretMirrorReconciler(vdbRec, logger, vdb)
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{Requeue: true}))
+	})
+})
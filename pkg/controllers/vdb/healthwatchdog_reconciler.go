@@ -0,0 +1,342 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vdb
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	vops "github.com/vertica/vcluster/vclusterops"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	"github.com/vertica/vertica-kubernetes/pkg/events"
+	"github.com/vertica/vertica-kubernetes/pkg/metrics"
+	"github.com/vertica/vertica-kubernetes/pkg/podfacts"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/cancelwatchdogquery"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/gethealthwatchdog"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/sethealthwatchdog"
+	"github.com/vertica/vertica-kubernetes/pkg/vdbstatus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// The keys of a health watchdog value that identify a runaway query
+const (
+	watchdogSessionIDKey   = "session_id"
+	watchdogStatementIDKey = "statement_id"
+	watchdogRequestKey     = "request"
+)
+
+// HealthWatchdogReconciler will keep the health watchdog in sync with
+// spec.healthWatchdog and report, or cancel if asked to, the runaway queries
+// that it flags
+type HealthWatchdogReconciler struct {
+	VRec       *VerticaDBReconciler
+	Vdb        *vapi.VerticaDB // Vdb is the CRD we are acting on.
+	Log        logr.Logger
+	Dispatcher vadmin.Dispatcher
+	PFacts     *podfacts.PodFacts
+}
+
+// runawayQuery is a query that the health watchdog flagged
+type runawayQuery struct {
+	SessionID   string
+	StatementID int64
+	Request     string
+}
+
+func MakeHealthWatchdogReconciler(vdbrecon *VerticaDBReconciler, log logr.Logger, vdb *vapi.VerticaDB,
+	dispatcher vadmin.Dispatcher, pfacts *podfacts.PodFacts) controllers.ReconcileActor {
+	return &HealthWatchdogReconciler{
+		VRec:       vdbrecon,
+		Vdb:        vdb,
+		Log:        log.WithName("HealthWatchdogReconciler"),
+		Dispatcher: dispatcher,
+		PFacts:     pfacts,
+	}
+}
+
+// Reconcile will set the watchdog parameters and policy that changed since
+// they were last set. If enabled, it then checks the watchdog for runaway
+// queries, once per check interval.
+func (h *HealthWatchdogReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (ctrl.Result, error) {
+	if h.Vdb.Spec.HealthWatchdog == nil && h.Vdb.Status.HealthWatchdog == nil {
+		return ctrl.Result{}, nil
+	}
+	if !h.Vdb.UseVClusterOpsDeployment() || !h.Vdb.IsDBInitialized() {
+		return ctrl.Result{}, nil
+	}
+
+	if err := h.PFacts.Collect(ctx, h.Vdb); err != nil {
+		return ctrl.Result{}, err
+	}
+	initiator, ok := h.PFacts.FindFirstUpPod(false, "")
+	if !ok {
+		h.Log.Info("No up pod found to configure the health watchdog. Requeue reconciliation.")
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	if err := h.reconcileParameters(ctx, initiator.GetPodIP()); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := h.reconcilePolicy(ctx, initiator.GetPodIP()); err != nil {
+		return ctrl.Result{}, err
+	}
+	if h.Vdb.Spec.HealthWatchdog == nil {
+		return ctrl.Result{}, vdbstatus.ClearHealthWatchdog(ctx, h.VRec.Client, h.Vdb)
+	}
+	return ctrl.Result{}, h.checkRunawayQueries(ctx, initiator.GetPodIP())
+}
+
+// reconcileParameters will set the watchdog parameters whose value differs
+// from the one last set, and clear the ones removed from the spec
+func (h *HealthWatchdogReconciler) reconcileParameters(ctx context.Context, initiatorIP string) error {
+	var desired, applied map[string]string
+	if h.Vdb.Spec.HealthWatchdog != nil {
+		desired = h.Vdb.Spec.HealthWatchdog.Parameters
+	}
+	if h.Vdb.Status.HealthWatchdog != nil {
+		applied = h.Vdb.Status.HealthWatchdog.Parameters
+	}
+	if maps.Equal(desired, applied) {
+		return nil
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(desired)) {
+		if cur, ok := applied[name]; ok && cur == desired[name] {
+			continue
+		}
+		err := h.setHealthWatchdog(ctx, fmt.Sprintf("parameter %q to %q", name, desired[name]),
+			sethealthwatchdog.WithInitiator(initiatorIP),
+			sethealthwatchdog.WithConfigParameter(name, desired[name]))
+		if err != nil {
+			return err
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(applied)) {
+		if _, ok := desired[name]; ok {
+			continue
+		}
+		err := h.setHealthWatchdog(ctx, fmt.Sprintf("parameter %q to its default", name),
+			sethealthwatchdog.WithInitiator(initiatorIP),
+			sethealthwatchdog.WithClearConfigParameter(name))
+		if err != nil {
+			return err
+		}
+	}
+	return vdbstatus.UpdateHealthWatchdog(ctx, h.VRec.Client, h.Vdb, func(status *vapi.HealthWatchdogStatus) {
+		status.Parameters = maps.Clone(desired)
+	})
+}
+
+// reconcilePolicy will set the watchdog policy if it differs from the one
+// last set. The watchdog has no way to reset a policy, so removing the policy
+// from the spec only forgets it.
+func (h *HealthWatchdogReconciler) reconcilePolicy(ctx context.Context, initiatorIP string) error {
+	var desired, applied map[string]string
+	if h.Vdb.Spec.HealthWatchdog != nil {
+		desired = h.Vdb.Spec.HealthWatchdog.Policy
+	}
+	if h.Vdb.Status.HealthWatchdog != nil {
+		applied = h.Vdb.Status.HealthWatchdog.Policy
+	}
+	if maps.Equal(desired, applied) {
+		return nil
+	}
+
+	if len(desired) > 0 {
+		err := h.setHealthWatchdog(ctx, "policy",
+			sethealthwatchdog.WithInitiator(initiatorIP),
+			sethealthwatchdog.WithPolicy(desired))
+		if err != nil {
+			return err
+		}
+	}
+	return vdbstatus.UpdateHealthWatchdog(ctx, h.VRec.Client, h.Vdb, func(status *vapi.HealthWatchdogStatus) {
+		status.Policy = maps.Clone(desired)
+	})
+}
+
+// setHealthWatchdog will call the watchdog set API and record an event with
+// the outcome
+func (h *HealthWatchdogReconciler) setHealthWatchdog(ctx context.Context, desc string,
+	opts ...sethealthwatchdog.Option) error {
+	h.Log.Info("Setting health watchdog", "setting", desc)
+	if err := h.Dispatcher.SetHealthWatchdog(ctx, opts...); err != nil {
+		h.VRec.Eventf(h.Vdb, corev1.EventTypeWarning, events.HealthWatchdogConfigFailed,
+			"Failed to set health watchdog %s", desc)
+		return err
+	}
+	h.VRec.Eventf(h.Vdb, corev1.EventTypeNormal, events.HealthWatchdogConfigured,
+		"Set health watchdog %s", desc)
+	return nil
+}
+
+// checkRunawayQueries will ask the watchdog for the runaway queries it flags
+// and report them. They are only cancelled if runawayQueryAction is Cancel.
+// This is done at most once per check interval. A failure is reported and
+// retried at the next interval, so it doesn't hold up the reconcile.
+func (h *HealthWatchdogReconciler) checkRunawayQueries(ctx context.Context, initiatorIP string) error {
+	if !h.Vdb.IsHealthWatchdogCheckEnabled() || h.Vdb.GetHealthWatchdogNextCheckIn(time.Now()) > 0 {
+		return nil
+	}
+
+	found, cancelled := 0, 0
+	values, err := h.Dispatcher.GetHealthWatchdog(ctx,
+		gethealthwatchdog.WithInitiator(initiatorIP),
+		gethealthwatchdog.WithAction(gethealthwatchdog.ActionCheckClusterHealth))
+	if err != nil {
+		h.Log.Error(err, "failed to check the health watchdog for runaway queries")
+		h.VRec.Eventf(h.Vdb, corev1.EventTypeWarning, events.HealthWatchdogCheckFailed,
+			"Failed to check the health watchdog for runaway queries: %s", err.Error())
+		metrics.HealthWatchdogCheckFailed.With(metrics.MakeVDBLabels(h.Vdb)).Inc()
+	} else {
+		queries := findRunawayQueries(values)
+		found = len(queries)
+		h.reportQueries(queries)
+		if found > 0 && h.Vdb.IsHealthWatchdogCancelEnabled() {
+			cancelled = h.cancelQueries(ctx, initiatorIP, queries)
+		}
+	}
+
+	now := metav1.Now()
+	return vdbstatus.UpdateHealthWatchdog(ctx, h.VRec.Client, h.Vdb, func(status *vapi.HealthWatchdogStatus) {
+		status.LastCheckTime = &now
+		if err == nil {
+			status.RunawayQueries = found
+		}
+		status.CancelledQueries += cancelled
+	})
+}
+
+// reportQueries will record an event for each runaway query that the
+// watchdog flagged and update the metrics
+func (h *HealthWatchdogReconciler) reportQueries(queries []runawayQuery) {
+	metrics.HealthWatchdogRunawayQueries.With(metrics.MakeVDBLabels(h.Vdb)).Set(float64(len(queries)))
+	for i := range queries {
+		h.VRec.Eventf(h.Vdb, corev1.EventTypeWarning, events.HealthWatchdogRunawayQuery,
+			"Health watchdog flagged a runaway query in session %s%s", queries[i].SessionID,
+			describeRequest(queries[i].Request))
+	}
+}
+
+// cancelQueries will cancel the given queries through the watchdog and
+// return how many were cancelled
+func (h *HealthWatchdogReconciler) cancelQueries(ctx context.Context, initiatorIP string,
+	queries []runawayQuery) int {
+	opts := []cancelwatchdogquery.Option{cancelwatchdogquery.WithInitiator(initiatorIP)}
+	requests := map[string]string{}
+	for i := range queries {
+		opts = append(opts, cancelwatchdogquery.WithSession(queries[i].SessionID, queries[i].StatementID))
+		requests[queries[i].SessionID] = queries[i].Request
+	}
+	h.Log.Info("Cancelling runaway queries found by the health watchdog", "count", len(queries))
+	responses, err := h.Dispatcher.CancelWatchdogQuery(ctx, opts...)
+	if err != nil {
+		h.Log.Error(err, "failed to cancel runaway queries")
+		h.VRec.Eventf(h.Vdb, corev1.EventTypeWarning, events.HealthWatchdogCancelFailed,
+			"Failed to cancel %d runaway queries: %s", len(queries), err.Error())
+		metrics.HealthWatchdogCancelFailed.With(metrics.MakeVDBLabels(h.Vdb)).Add(float64(len(queries)))
+		return 0
+	}
+
+	cancelled := 0
+	for i := range responses {
+		resp := &responses[i]
+		if !isWatchdogCancelSuccess(resp) {
+			h.VRec.Eventf(h.Vdb, corev1.EventTypeWarning, events.HealthWatchdogCancelFailed,
+				"Failed to cancel runaway query in session %s: %s", resp.SessionID, resp.Message)
+			metrics.HealthWatchdogCancelFailed.With(metrics.MakeVDBLabels(h.Vdb)).Inc()
+			continue
+		}
+		cancelled++
+		h.VRec.Eventf(h.Vdb, corev1.EventTypeWarning, events.HealthWatchdogQueryCancelled,
+			"Cancelled runaway query in session %s%s", resp.SessionID, describeRequest(requests[resp.SessionID]))
+	}
+	metrics.HealthWatchdogCancelledQueries.With(metrics.MakeVDBLabels(h.Vdb)).Add(float64(cancelled))
+	return cancelled
+}
+
+// findRunawayQueries returns the queries that the watchdog flagged when it
+// checked the cluster health. These are the values with a session ID. The
+// same query can be flagged by several hosts, so the list is deduplicated.
+func findRunawayQueries(values []vops.HealthWatchdogHostValues) []runawayQuery {
+	queries := []runawayQuery{}
+	seen := map[string]bool{}
+	for i := range values {
+		for _, val := range values[i].Values {
+			sessionID, _ := val[watchdogSessionIDKey].(string)
+			if sessionID == "" {
+				continue
+			}
+			q := runawayQuery{SessionID: sessionID, StatementID: parseStatementID(val[watchdogStatementIDKey])}
+			q.Request, _ = val[watchdogRequestKey].(string)
+			key := fmt.Sprintf("%s/%d", q.SessionID, q.StatementID)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			queries = append(queries, q)
+		}
+	}
+	return queries
+}
+
+// parseStatementID converts the statement ID of a watchdog value. Numbers
+// in the JSON response are decoded as float64. Zero is returned if it is
+// missing, which means the whole session is cancelled.
+func parseStatementID(val any) int64 {
+	switch v := val.(type) {
+	case float64:
+		return int64(v)
+	case string:
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0
+		}
+		return id
+	default:
+		return 0
+	}
+}
+
+// isWatchdogCancelSuccess returns true if the watchdog reports the query as
+// cancelled
+func isWatchdogCancelSuccess(resp *vops.HealthWatchdogCancelQueryResponse) bool {
+	status := strings.ToLower(resp.Status)
+	return !strings.Contains(status, "fail") && !strings.Contains(status, "error")
+}
+
+// describeRequest returns the query text for use in events, truncated so
+// that the event stays readable
+func describeRequest(request string) string {
+	const maxLen = 128
+	if request == "" {
+		return ""
+	}
+	if runes := []rune(request); len(runes) > maxLen {
+		request = string(runes[:maxLen]) + "..."
+	}
+	return fmt.Sprintf(": %s", request)
+}
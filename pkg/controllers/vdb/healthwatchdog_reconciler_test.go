@@ -0,0 +1,181 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vdb
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	vops "github.com/vertica/vcluster/vclusterops"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/pkg/cmds"
+	"github.com/vertica/vertica-kubernetes/pkg/mockvops"
+	"github.com/vertica/vertica-kubernetes/pkg/test"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// mockWatchdogVClusterOps records the health watchdog calls and reports a
+// runaway query
+type mockWatchdogVClusterOps struct {
+	mockvops.MockVClusterOps
+	setActions []string
+	cancelled  []vops.HealthWatchdogCancelQueryOptions
+}
+
+func (m *mockWatchdogVClusterOps) VHealthWatchdogSet(options *vops.VHealthWatchdogSetOptions) error {
+	m.setActions = append(m.setActions, options.Action+":"+options.ParameterName)
+	return nil
+}
+
+func (m *mockWatchdogVClusterOps) VHealthWatchdogGet(_ *vops.VHealthWatchdogGetOptions) (
+	*[]vops.HealthWatchdogHostValues, error) {
+	query := vops.HealthWatchdogValue{"session_id": "sess1", "statement_id": float64(3), "request": "select sleep(1000)"}
+	values := []vops.HealthWatchdogHostValues{
+		{Host: "10.0.0.1", Values: []vops.HealthWatchdogValue{query, {"status": "ok"}}},
+		{Host: "10.0.0.2", Values: []vops.HealthWatchdogValue{query}},
+	}
+	return &values, nil
+}
+
+func (m *mockWatchdogVClusterOps) VHealthWatchdogCancelQuery(options *vops.VHealthWatchdogCancelQueryOptions) (
+	[]vops.HealthWatchdogCancelQueryResponse, error) {
+	m.cancelled = append(m.cancelled, options.Sessions...)
+	resp := []vops.HealthWatchdogCancelQueryResponse{}
+	for _, s := range options.Sessions {
+		resp = append(resp, vops.HealthWatchdogCancelQueryResponse{Status: "success", SessionID: s.SessionID,
+			StatementID: s.StatementID})
+	}
+	return resp, nil
+}
+
+var _ = Describe("healthwatchdog_reconciler", func() {
+	ctx := context.Background()
+
+	It("should be a no-op if the health watchdog isn't configured", func() {
+		vdb := vapi.MakeVDBForVclusterOps()
+		fpr := &cmds.FakePodRunner{}
+		pfacts := createPodFactsDefault(fpr)
+		r := MakeHealthWatchdogReconciler(vdbRec, logger, vdb, mockVClusterOpsDispatcher(vdb), pfacts)
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(vdb.Status.HealthWatchdog).Should(BeNil())
+	})
+
+	It("should configure the watchdog and cancel the runaway queries", func() {
+		vdb := vapi.MakeVDBForVclusterOps()
+		vdb.Spec.HealthWatchdog = &vapi.HealthWatchdogSpec{
+			Parameters:           map[string]string{"QueryRuntimeLimit": "600"},
+			Policy:               map[string]string{"cancel": "true"},
+			RunawayQueryAction:   vapi.RunawayQueryActionCancel,
+			CheckIntervalSeconds: 60,
+		}
+		vdb.Spec.HTTPSNMATLS.Secret = "watchdog-tls"
+		test.CreateFakeTLSSecret(ctx, vdb, k8sClient, vdb.GetHTTPSNMATLSSecret())
+		defer test.DeleteSecret(ctx, k8sClient, vdb.GetHTTPSNMATLSSecret())
+		test.CreateVDB(ctx, k8sClient, vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+		test.CreatePods(ctx, k8sClient, vdb, test.AllPodsRunning)
+		defer test.DeletePods(ctx, k8sClient, vdb)
+		meta.SetStatusCondition(&vdb.Status.Conditions,
+			*vapi.MakeCondition(vapi.DBInitialized, metav1.ConditionTrue, "Initialized"))
+		Expect(k8sClient.Status().Update(ctx, vdb)).Should(Succeed())
+
+		mock := &mockWatchdogVClusterOps{}
+		setupAPIFunc := func(logr.Logger, string) (vadmin.VClusterProvider, logr.Logger) {
+			return mock, logr.Logger{}
+		}
+		dispatcher := mockvops.MakeMockVClusterOpsDispatcher(vdb, logger, k8sClient, setupAPIFunc)
+		pfacts := createPodFactsDefault(&cmds.FakePodRunner{})
+		r := MakeHealthWatchdogReconciler(vdbRec, logger, vdb, dispatcher, pfacts)
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+
+		Expect(mock.setActions).Should(Equal([]string{
+			"set_config_parameter:QueryRuntimeLimit", "set_health_watchdog_policy:"}))
+		Expect(mock.cancelled).Should(Equal([]vops.HealthWatchdogCancelQueryOptions{
+			{SessionID: "sess1", StatementID: 3}}))
+		Expect(vdb.Status.HealthWatchdog).ShouldNot(BeNil())
+		Expect(vdb.Status.HealthWatchdog.Parameters).Should(Equal(vdb.Spec.HealthWatchdog.Parameters))
+		Expect(vdb.Status.HealthWatchdog.RunawayQueries).Should(Equal(1))
+		Expect(vdb.Status.HealthWatchdog.CancelledQueries).Should(Equal(1))
+		Expect(vdb.GetHealthWatchdogNextCheckIn(time.Now())).Should(BeNumerically(">", 0))
+
+		// Nothing is set again, and no check is done, until something changes
+		// or the interval passes
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(mock.setActions).Should(HaveLen(2))
+		Expect(mock.cancelled).Should(HaveLen(1))
+
+		// A parameter removed from the spec is cleared
+		vdb.Spec.HealthWatchdog.Parameters = nil
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(mock.setActions).Should(HaveLen(3))
+		Expect(mock.setActions[2]).Should(Equal("clear_config_parameter:QueryRuntimeLimit"))
+		Expect(vdb.Status.HealthWatchdog.Parameters).Should(BeEmpty())
+	})
+
+	It("should only report the runaway queries unless cancelling is enabled", func() {
+		vdb := vapi.MakeVDBForVclusterOps()
+		vdb.Spec.HealthWatchdog = &vapi.HealthWatchdogSpec{
+			RunawayQueryAction:   vapi.RunawayQueryActionReport,
+			CheckIntervalSeconds: 60,
+		}
+		vdb.Spec.HTTPSNMATLS.Secret = "watchdog-tls"
+		test.CreateFakeTLSSecret(ctx, vdb, k8sClient, vdb.GetHTTPSNMATLSSecret())
+		defer test.DeleteSecret(ctx, k8sClient, vdb.GetHTTPSNMATLSSecret())
+		test.CreateVDB(ctx, k8sClient, vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+		test.CreatePods(ctx, k8sClient, vdb, test.AllPodsRunning)
+		defer test.DeletePods(ctx, k8sClient, vdb)
+		meta.SetStatusCondition(&vdb.Status.Conditions,
+			*vapi.MakeCondition(vapi.DBInitialized, metav1.ConditionTrue, "Initialized"))
+		Expect(k8sClient.Status().Update(ctx, vdb)).Should(Succeed())
+
+		mock := &mockWatchdogVClusterOps{}
+		setupAPIFunc := func(logr.Logger, string) (vadmin.VClusterProvider, logr.Logger) {
+			return mock, logr.Logger{}
+		}
+		dispatcher := mockvops.MakeMockVClusterOpsDispatcher(vdb, logger, k8sClient, setupAPIFunc)
+		pfacts := createPodFactsDefault(&cmds.FakePodRunner{})
+		r := MakeHealthWatchdogReconciler(vdbRec, logger, vdb, dispatcher, pfacts)
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+
+		Expect(mock.cancelled).Should(BeEmpty())
+		Expect(vdb.Status.HealthWatchdog).ShouldNot(BeNil())
+		Expect(vdb.Status.HealthWatchdog.LastCheckTime).ShouldNot(BeNil())
+		Expect(vdb.Status.HealthWatchdog.RunawayQueries).Should(Equal(1))
+		Expect(vdb.Status.HealthWatchdog.CancelledQueries).Should(Equal(0))
+	})
+
+	It("should find the runaway queries in the watchdog values", func() {
+		values := []vops.HealthWatchdogHostValues{
+			{Host: "h1", Values: []vops.HealthWatchdogValue{
+				{"session_id": "s1"},
+				{"session_id": "s2", "statement_id": "7"},
+				{"node_name": "v_db_node0001"},
+			}},
+			{Host: "h2", Values: []vops.HealthWatchdogValue{{"session_id": "s1"}}},
+		}
+		Expect(findRunawayQueries(values)).Should(Equal([]runawayQuery{
+			{SessionID: "s1"},
+			{SessionID: "s2", StatementID: 7},
+		}))
+	})
+})
@@ -23,6 +23,7 @@ import (
 	"github.com/vertica/vertica-kubernetes/pkg/builder"
 	"github.com/vertica/vertica-kubernetes/pkg/controllers"
 	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/secrets"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -70,24 +71,27 @@ func (h *NMACertConfigMapReconciler) Reconcile(ctx context.Context, _ *ctrl.Requ
 	if !h.Vdb.IsAnyTLSAuthEnabledWithMinVersion() {
 		return ctrl.Result{}, nil
 	}
-	if configMap.Data[builder.NMASecretNameEnv] == h.Vdb.GetHTTPSNMATLSSecretForConfigMap() &&
-		configMap.Data[builder.NMAClientSecretNameEnv] == h.Vdb.GetClientServerTLSSecretForConfigMap() &&
+	// NMA reads the secrets stored in Vault or Azure Key Vault from their mirror
+	nmaSecret := secrets.ResolveMirroredSecretName(h.Vdb.GetHTTPSNMATLSSecretForConfigMap())
+	clientServerSecret := secrets.ResolveMirroredSecretName(h.Vdb.GetClientServerTLSSecretForConfigMap())
+	if configMap.Data[builder.NMASecretNameEnv] == nmaSecret &&
+		configMap.Data[builder.NMAClientSecretNameEnv] == clientServerSecret &&
 		configMap.Data[builder.NMASecretNamespaceEnv] == h.Vdb.ObjectMeta.Namespace &&
 		configMap.Data[builder.NMAClientSecretNamespaceEnv] == h.Vdb.ObjectMeta.Namespace &&
 		configMap.Data[builder.NMAClientSecretTLSModeEnv] == h.Vdb.GetNMAClientServerTLSMode() {
 		return ctrl.Result{}, nil
 	}
 
-	configMap.Data[builder.NMASecretNameEnv] = h.Vdb.GetHTTPSNMATLSSecretForConfigMap()
+	configMap.Data[builder.NMASecretNameEnv] = nmaSecret
 	configMap.Data[builder.NMASecretNamespaceEnv] = h.Vdb.ObjectMeta.Namespace
-	configMap.Data[builder.NMAClientSecretNameEnv] = h.Vdb.GetClientServerTLSSecretForConfigMap()
+	configMap.Data[builder.NMAClientSecretNameEnv] = clientServerSecret
 	configMap.Data[builder.NMAClientSecretNamespaceEnv] = h.Vdb.ObjectMeta.Namespace
 	configMap.Data[builder.NMAClientSecretTLSModeEnv] = h.Vdb.GetNMAClientServerTLSMode()
 
 	err = h.VRec.GetClient().Update(ctx, configMap)
 	if err == nil {
-		h.Log.Info("updated tls cert secret configmap", "name", configMapName.Name, "nma-secret", h.Vdb.GetHTTPSNMATLSSecretForConfigMap(),
-			"clientserver-secret", h.Vdb.GetClientServerTLSSecretForConfigMap())
+		h.Log.Info("updated tls cert secret configmap", "name", configMapName.Name, "nma-secret", nmaSecret,
+			"clientserver-secret", clientServerSecret)
 	}
 	return ctrl.Result{}, err
 }
@@ -325,7 +325,7 @@ func (o *ObjReconciler) checkForCreatedSubclusters(ctx context.Context) (ctrl.Re
 // checkForDeletedSubcluster will remove any objects that were created for
 // subclusters that don't exist anymore.
 func (o *ObjReconciler) checkForDeletedSubcluster(ctx context.Context) (ctrl.Result, error) {
-	if o.Mode&ObjReconcileModePreserveScaling != 0 {
+	if o.isScalingPreserved() {
 		// Bypass this check since we won't be doing any scale in with this reconcile
 		return ctrl.Result{}, nil
 	}
@@ -730,7 +730,7 @@ func (o *ObjReconciler) handleStatefulSetUpdate(ctx context.Context, sc *vapi.Su
 	// uninstall.  If we haven't yet done that we will requeue the
 	// reconciliation.  This will cause us to go through the remove node and
 	// uninstall reconcile actors to properly handle the scale in.
-	if o.Mode&ObjReconcileModePreserveScaling == 0 {
+	if !o.isScalingPreserved() {
 		if r, e := o.checkIfReadyForStsUpdate(sc.Size, curSts); verrors.IsReconcileAborted(r, e) {
 			return r, e
 		}
@@ -748,7 +748,7 @@ func (o *ObjReconciler) handleStatefulSetUpdate(ctx context.Context, sc *vapi.Su
 
 	// Preserve scaling if told to do so. This is used when doing early
 	// reconciliation so that we have any necessary pods started.
-	if o.Mode&ObjReconcileModePreserveScaling != 0 && o.shouldPreserveStsSize(curSts, expSts) {
+	if o.isScalingPreserved() && o.shouldPreserveStsSize(curSts, expSts) {
 		expSts.Spec.Replicas = curSts.Spec.Replicas
 	}
 	// Preserve the delete policy as they may be changed temporarily by upgrade,
@@ -823,6 +823,12 @@ func (o *ObjReconciler) copyHealthProbes(curSts, expSts *appsv1.StatefulSet) err
 	return nil
 }
 
+// isScalingPreserved returns true if this reconcile keeps the current size of
+// the statefulsets. Statefulsets aren't resized or deleted in that case.
+func (o *ObjReconciler) isScalingPreserved() bool {
+	return o.Mode&ObjReconcileModePreserveScaling != 0
+}
+
 // shouldPreserveStsSize returns true if the current sts size should be preserved.
 // However, if the current sts size is 0 and different from the expected sts size,
 // we should not preserve it as we are restarting a sts that was in shutdown state
@@ -1237,3 +1243,43 @@ func (o *ObjReconciler) checkConfigChanges(expSts, curSts *appsv1.StatefulSet) {
 		o.Log.Info("Config hash changed", "currentHash", currentHash, "newHash", newHash)
 	}
 }
+
+// Plan reports the statefulsets that would be created, resized or deleted.
+// Only the actor that reconciles all of the objects reports its plan.
+func (o *ObjReconciler) Plan(ctx context.Context) ([]vapi.PlannedAction, error) {
+	if o.Mode&ObjReconcileModeAll == 0 {
+		return nil, nil
+	}
+	finder := iter.MakeSubclusterFinder(o.Rec.GetClient(), o.Vdb)
+	subclusters, err := finder.FindSubclusters(ctx, iter.FindAll, vapi.MainCluster)
+	if err != nil {
+		return nil, err
+	}
+	scMap := o.Vdb.GenSubclusterMap()
+	actions := []vapi.PlannedAction{}
+	for _, sc := range subclusters {
+		stsName := names.GenStsName(o.Vdb, sc)
+		sts := &appsv1.StatefulSet{}
+		if err := o.Rec.GetClient().Get(ctx, stsName, sts); err != nil {
+			if !kerrors.IsNotFound(err) {
+				return nil, err
+			}
+			actions = append(actions, makePlannedAction("Obj", sc.Name, nil,
+				"Create statefulset %s with %d pods", stsName.Name, sc.Size))
+			continue
+		}
+		if o.isScalingPreserved() {
+			continue
+		}
+		if _, ok := scMap[sc.Name]; !ok {
+			actions = append(actions, makePlannedAction("Obj", sc.Name, nil,
+				"Delete statefulset %s of the subcluster removed from the spec", stsName.Name))
+			continue
+		}
+		if sts.Spec.Replicas != nil && *sts.Spec.Replicas != sc.Size {
+			actions = append(actions, makePlannedAction("Obj", sc.Name, nil,
+				"Resize statefulset %s from %d to %d pods", stsName.Name, *sts.Spec.Replicas, sc.Size))
+		}
+	}
+	return actions, nil
+}
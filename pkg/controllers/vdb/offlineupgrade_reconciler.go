@@ -460,3 +460,8 @@ func (o *OfflineUpgradeReconciler) anyPodsRunningWithOldImage(ctx context.Contex
 func (o *OfflineUpgradeReconciler) postNextStatusMsg(ctx context.Context, msgIndex int) (ctrl.Result, error) {
 	return ctrl.Result{}, o.Manager.postNextStatusMsg(ctx, OfflineUpgradeStatusMsgs, msgIndex, o.PFacts.GetSandboxName())
 }
+
+// Plan reports if an offline upgrade would be done
+func (o *OfflineUpgradeReconciler) Plan(ctx context.Context) ([]vapi.PlannedAction, error) {
+	return planUpgrade(ctx, &o.Manager, o.PFacts.GetSandboxName(), "OfflineUpgrade", "offline")
+}
@@ -1827,3 +1827,8 @@ func genBaseNameWithUUID(baseName, sep string) string {
 	u := uuid.NewString()
 	return fmt.Sprintf("%s%s%s", baseName, sep, u[0:5])
 }
+
+// Plan reports if an online upgrade would be done
+func (r *OnlineUpgradeReconciler) Plan(ctx context.Context) ([]vapi.PlannedAction, error) {
+	return planUpgrade(ctx, &r.Manager, vapi.MainCluster, "OnlineUpgrade", "online")
+}
@@ -0,0 +1,390 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vdb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/pkg/builder"
+	"github.com/vertica/vertica-kubernetes/pkg/cmds"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	"github.com/vertica/vertica-kubernetes/pkg/events"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/podfacts"
+	"github.com/vertica/vertica-kubernetes/pkg/security"
+	"github.com/vertica/vertica-kubernetes/pkg/vdbstatus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// The length of the superuser passwords generated by the operator
+const rotatedPasswordLength = 32
+
+// PasswordRotationReconciler will rotate the superuser password on the
+// interval set in spec.passwordRotation.
+//
+// A rotation goes through these steps, each of which can be interrupted:
+//  1. The new password is stored in a new secret.
+//  2. The new secret is recorded in status.passwordRotation.pendingSecret.
+//  3. The password is changed in the database.
+//  4. status.passwordSecret and status.passwordRotation.currentSecret are
+//     set to the new secret, and the pending secret is cleared.
+//
+// The first rotation is due one interval after the operator finds the policy
+// set. While the policy is set, the password in spec.passwordSecret is
+// ignored in favour of the one of the last rotation.
+//
+// While a rotation is pending, the operator doesn't know which of the two
+// passwords is in effect. It tries each of them to find out, and then
+// completes the rotation.
+type PasswordRotationReconciler struct {
+	VRec    *VerticaDBReconciler
+	Log     logr.Logger
+	Vdb     *vapi.VerticaDB // Vdb is the CRD we are acting on.
+	PFacts  *podfacts.PodFacts
+	PRunner cmds.PodRunner
+	// When true, the reconciler only completes a rotation that was
+	// interrupted. It never starts a new one.
+	RecoverOnly bool
+}
+
+// MakePasswordRotationReconciler will build a PasswordRotationReconciler object
+func MakePasswordRotationReconciler(vdbrecon *VerticaDBReconciler, log logr.Logger, vdb *vapi.VerticaDB,
+	prunner cmds.PodRunner, pfacts *podfacts.PodFacts, recoverOnly bool) controllers.ReconcileActor {
+	return &PasswordRotationReconciler{
+		VRec:        vdbrecon,
+		Log:         log.WithName("PasswordRotationReconciler"),
+		Vdb:         vdb,
+		PFacts:      pfacts,
+		PRunner:     prunner,
+		RecoverOnly: recoverOnly,
+	}
+}
+
+// Reconcile will complete a pending rotation of the superuser password, or
+// start a new one if it is due. The next rotation is scheduled by the
+// controller once all of the actors have run.
+func (p *PasswordRotationReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (ctrl.Result, error) {
+	if !p.Vdb.IsDBInitialized() || p.Vdb.Status.PasswordSecret == nil {
+		return ctrl.Result{}, nil
+	}
+	if p.Vdb.IsPasswordRotationPending() {
+		return p.completePendingRotation(ctx)
+	}
+	if p.RecoverOnly {
+		return ctrl.Result{}, nil
+	}
+	if updated, err := p.trackRotationPolicy(ctx); updated || err != nil {
+		return ctrl.Result{}, err
+	}
+	if !p.isRotationDue() {
+		return ctrl.Result{}, nil
+	}
+	return p.rotatePassword(ctx)
+}
+
+// trackRotationPolicy will record in the status when the rotation policy is
+// set, so that the first rotation is due one interval later. When the policy
+// is removed, the rotation state is cleared so that spec.passwordSecret is
+// in effect again. It returns true if the status was updated.
+func (p *PasswordRotationReconciler) trackRotationPolicy(ctx context.Context) (bool, error) {
+	rs := p.Vdb.Status.PasswordRotation
+	if p.Vdb.IsPasswordRotationEnabled() {
+		if rs != nil && rs.EnabledTime != nil {
+			return false, nil
+		}
+		p.Log.Info("Password rotation enabled", "interval", p.Vdb.GetPasswordRotationInterval())
+		return true, vdbstatus.UpdatePasswordRotation(ctx, p.VRec.GetClient(), p.Vdb, func(s *vapi.PasswordRotationStatus) {
+			s.EnabledTime = &metav1.Time{Time: time.Now()}
+		})
+	}
+	if rs == nil || (rs.EnabledTime == nil && rs.LastRotationTime == nil && rs.CurrentSecret == "") {
+		return false, nil
+	}
+	p.Log.Info("Password rotation disabled")
+	return true, vdbstatus.UpdatePasswordRotation(ctx, p.VRec.GetClient(), p.Vdb, func(s *vapi.PasswordRotationStatus) {
+		s.EnabledTime = nil
+		s.LastRotationTime = nil
+		s.CurrentSecret = ""
+	})
+}
+
+// isRotationDue returns true if a new rotation must be started now
+func (p *PasswordRotationReconciler) isRotationDue() bool {
+	if !p.Vdb.IsPasswordRotationEnabled() || len(p.Vdb.Spec.Sandboxes) > 0 {
+		return false
+	}
+	// Let the PasswordSecretReconciler apply a password change made by the
+	// user first
+	if p.Vdb.GetDesiredPasswordSecret() != *p.Vdb.Status.PasswordSecret {
+		return false
+	}
+	return p.Vdb.GetPasswordRotationNextIn(time.Now()) == 0
+}
+
+// rotatePassword will generate a new superuser password and change it in
+// the database
+func (p *PasswordRotationReconciler) rotatePassword(ctx context.Context) (ctrl.Result, error) {
+	pf, res, err := p.findInitiator(ctx)
+	if pf == nil {
+		return res, err
+	}
+
+	secretName := p.Vdb.GetPasswordRotationSecretName(p.getRotations() + 1)
+	newPasswd, err := p.getOrCreateRotatedSecret(ctx, secretName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	// Record the new secret before the password is changed so that we can
+	// log in with it if we are interrupted right after the change.
+	err = vdbstatus.UpdatePasswordRotation(ctx, p.VRec.GetClient(), p.Vdb, func(s *vapi.PasswordRotationStatus) {
+		s.PendingSecret = secretName
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	p.Log.Info("Rotating the superuser password", "newSecret", secretName)
+	if err := p.alterPassword(ctx, pf, *p.PFacts.VerticaSUPassword, newPasswd); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, p.completeRotation(ctx, secretName, newPasswd)
+}
+
+// completePendingRotation will find out which of the current or the pending
+// password is in effect in the database, and complete the rotation. If
+// neither password works, nothing is changed so that the secrets can be
+// fixed manually.
+func (p *PasswordRotationReconciler) completePendingRotation(ctx context.Context) (ctrl.Result, error) {
+	pf, res, err := p.findInitiator(ctx)
+	if pf == nil {
+		// The database may need to be restarted first. We only wait for it
+		// when we are the last chance to complete the rotation.
+		if p.RecoverOnly {
+			return ctrl.Result{}, nil
+		}
+		return res, err
+	}
+
+	secretName := p.Vdb.Status.PasswordRotation.PendingSecret
+	curPasswd := *p.PFacts.VerticaSUPassword
+	pendingPasswd, found, err := p.readRotatedSecret(ctx, secretName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !found {
+		// The pending secret is gone, so the password could not have been
+		// changed with it. Drop the rotation if the current password works.
+		if !p.canLogin(ctx, pf, curPasswd) {
+			return ctrl.Result{}, p.reportLockout(secretName)
+		}
+		p.Log.Info("Pending password secret not found. Restarting the rotation", "secret", secretName)
+		return ctrl.Result{Requeue: true}, vdbstatus.UpdatePasswordRotation(ctx, p.VRec.GetClient(), p.Vdb,
+			func(s *vapi.PasswordRotationStatus) {
+				s.PendingSecret = ""
+			})
+	}
+
+	p.Log.Info("Completing an interrupted rotation of the superuser password", "pendingSecret", secretName)
+	switch {
+	case p.canLogin(ctx, pf, curPasswd):
+		if err := p.alterPassword(ctx, pf, curPasswd, pendingPasswd); err != nil {
+			return ctrl.Result{}, err
+		}
+	case p.canLogin(ctx, pf, pendingPasswd):
+		p.Log.Info("The pending password is already in effect in the database")
+	default:
+		return ctrl.Result{}, p.reportLockout(secretName)
+	}
+	return ctrl.Result{}, p.completeRotation(ctx, secretName, pendingPasswd)
+}
+
+// findInitiator returns the pod to run the vsql commands in. It returns nil
+// if there is no up pod, along with the result to return.
+func (p *PasswordRotationReconciler) findInitiator(ctx context.Context) (*podfacts.PodFact, ctrl.Result, error) {
+	if err := p.PFacts.Collect(ctx, p.Vdb); err != nil {
+		return nil, ctrl.Result{}, err
+	}
+	pf, found := p.PFacts.FindFirstPrimaryUpPod()
+	if !found {
+		p.Log.Info("No up nodes found. Requeue the rotation of the superuser password.")
+		return nil, ctrl.Result{Requeue: true}, nil
+	}
+	return pf, ctrl.Result{}, nil
+}
+
+// getOrCreateRotatedSecret returns the password stored in the secret of the
+// next rotation. The secret is created with a new password if it doesn't
+// exist. It can exist if a prior attempt was interrupted before the rotation
+// was recorded in the status.
+func (p *PasswordRotationReconciler) getOrCreateRotatedSecret(ctx context.Context, secretName string) (string, error) {
+	passwd, found, err := p.readRotatedSecret(ctx, secretName)
+	if err != nil || found {
+		return passwd, err
+	}
+	passwd, err = security.NewPassword(rotatedPasswordLength)
+	if err != nil {
+		return "", err
+	}
+	p.Log.Info("Creating secret for the rotated superuser password", "secret", secretName)
+	nm := names.GenNamespacedName(p.Vdb, secretName)
+	return passwd, p.VRec.GetClient().Create(ctx, builder.BuildRotatedPasswordSecret(p.Vdb, nm, passwd))
+}
+
+// readRotatedSecret returns the password stored in a secret created by the
+// operator for a rotation. It returns false if the secret doesn't exist.
+func (p *PasswordRotationReconciler) readRotatedSecret(ctx context.Context, secretName string) (string, bool, error) {
+	sec := &corev1.Secret{}
+	err := p.VRec.GetClient().Get(ctx, names.GenNamespacedName(p.Vdb, secretName), sec)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	if !p.isOwnedByVdb(sec) {
+		return "", false, fmt.Errorf("cannot rotate the superuser password: secret %q exists and was not created by the operator",
+			secretName)
+	}
+	passwd, ok := sec.Data[names.SuperuserPasswordKey]
+	if !ok || len(passwd) == 0 {
+		return "", false, fmt.Errorf("secret %q does not have the key %q", secretName, names.SuperuserPasswordKey)
+	}
+	return string(passwd), true, nil
+}
+
+// alterPassword will change the superuser password in the database. It logs
+// in with the given password rather than the one in the pod runner, as the
+// pod runner can have a password that is no longer in effect.
+func (p *PasswordRotationReconciler) alterPassword(ctx context.Context, pf *podfacts.PodFact, curPasswd, newPasswd string) error {
+	sql := fmt.Sprintf(`ALTER USER %s IDENTIFIED BY '%s';`, p.Vdb.GetVerticaUser(), newPasswd)
+	_, stderr, err := p.execVSQL(ctx, pf, curPasswd, sql)
+	if err != nil {
+		p.VRec.Eventf(p.Vdb, corev1.EventTypeWarning, events.SuperuserPasswordRotationFailed,
+			"Failed to rotate the superuser password: %s", stderr)
+		return fmt.Errorf("failed to rotate the superuser password: %w", err)
+	}
+	return nil
+}
+
+// canLogin returns true if the superuser can log in with the given password
+func (p *PasswordRotationReconciler) canLogin(ctx context.Context, pf *podfacts.PodFact, passwd string) bool {
+	_, stderr, err := p.execVSQL(ctx, pf, passwd, "SELECT 1;")
+	if err != nil {
+		p.Log.Info("Superuser login attempt failed", "stderr", stderr)
+		return false
+	}
+	return true
+}
+
+// execVSQL runs the given SQL with vsql as the superuser with the given
+// password
+func (p *PasswordRotationReconciler) execVSQL(ctx context.Context, pf *podfacts.PodFact, passwd,
+	sql string) (stdout, stderr string, err error) {
+	cmd := cmds.UpdateVsqlCmd(p.Vdb.GetVerticaUser(), &passwd, p.Vdb.IsClientServerTLSAuthEnabled(), "-tAc", sql)
+	return p.PRunner.ExecInPod(ctx, pf.GetName(), names.ServerContainer, cmd...)
+}
+
+// completeRotation will point the vdb to the secret of the new password,
+// which is in effect in the database, and delete the secrets of old rotations.
+// The spec is left untouched: the secret of the rotation is kept in the status.
+func (p *PasswordRotationReconciler) completeRotation(ctx context.Context, secretName, newPasswd string) error {
+	rotations := p.getRotations() + 1
+	err := vdbstatus.Update(ctx, p.VRec.GetClient(), p.Vdb, func(vdbChg *vapi.VerticaDB) error {
+		statusSecret := secretName
+		vdbChg.Status.PasswordSecret = &statusSecret
+		if vdbChg.Status.PasswordRotation == nil {
+			vdbChg.Status.PasswordRotation = &vapi.PasswordRotationStatus{}
+		}
+		vdbChg.Status.PasswordRotation.LastRotationTime = &metav1.Time{Time: time.Now()}
+		vdbChg.Status.PasswordRotation.Rotations = rotations
+		vdbChg.Status.PasswordRotation.PendingSecret = ""
+		vdbChg.Status.PasswordRotation.CurrentSecret = secretName
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	// prunner, podfacts and dispatcher share the pointer
+	// reset one of them will also reset the password in the others
+	p.PFacts.SetSUPassword(newPasswd)
+	p.VRec.Eventf(p.Vdb, corev1.EventTypeNormal, events.SuperuserPasswordRotated,
+		"Rotated the superuser password. The new password is in secret %q", secretName)
+
+	// The secret of the prior rotation is kept until the next one so that
+	// the pods created before this rotation can still mount it.
+	return p.deleteOldRotatedSecret(ctx, rotations-2)
+}
+
+// deleteOldRotatedSecret will delete the secret that the operator created
+// for the given rotation. A secret set by the user is never deleted as it
+// isn't owned by the vdb.
+func (p *PasswordRotationReconciler) deleteOldRotatedSecret(ctx context.Context, rotation int) error {
+	if rotation < 1 || !p.Vdb.IsPasswordRotationEnabled() {
+		return nil
+	}
+	secretName := p.Vdb.GetPasswordRotationSecretName(rotation)
+	sec := &corev1.Secret{}
+	err := p.VRec.GetClient().Get(ctx, names.GenNamespacedName(p.Vdb, secretName), sec)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !p.isOwnedByVdb(sec) {
+		return nil
+	}
+	p.Log.Info("Deleting the secret of an old superuser password", "secret", secretName)
+	if err := p.VRec.GetClient().Delete(ctx, sec); err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// reportLockout logs an event when neither the current nor the pending
+// password let the operator log in to the database
+func (p *PasswordRotationReconciler) reportLockout(pendingSecret string) error {
+	p.VRec.Eventf(p.Vdb, corev1.EventTypeWarning, events.SuperuserPasswordRotationFailed,
+		"Cannot log in to the database with the password in secret %q nor the one in secret %q. "+
+			"Fix the password in one of the secrets to complete the rotation",
+		*p.Vdb.Status.PasswordSecret, pendingSecret)
+	return fmt.Errorf("cannot log in with the current or the pending superuser password")
+}
+
+// getRotations returns the number of rotations done so far
+func (p *PasswordRotationReconciler) getRotations() int {
+	if p.Vdb.Status.PasswordRotation == nil {
+		return 0
+	}
+	return p.Vdb.Status.PasswordRotation.Rotations
+}
+
+// isOwnedByVdb returns true if the vdb is one of the owners of the secret
+func (p *PasswordRotationReconciler) isOwnedByVdb(sec *corev1.Secret) bool {
+	for i := range sec.OwnerReferences {
+		if sec.OwnerReferences[i].UID == p.Vdb.UID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,205 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vdb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/pkg/builder"
+	"github.com/vertica/vertica-kubernetes/pkg/cmds"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/podfacts"
+	"github.com/vertica/vertica-kubernetes/pkg/test"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("passwordrotation_reconciler", func() {
+	ctx := context.Background()
+	const userSecret = "su-passwd"
+	const oldPasswd = "old-passwd"
+
+	// createRotationVDB creates an initialized vdb whose superuser password
+	// is in a secret set by the user. The rotation policy was enabled long
+	// enough ago for a rotation to be due.
+	createRotationVDB := func() *vapi.VerticaDB {
+		vdb := vapi.MakeVDB()
+		vdb.Spec.PasswordSecret = userSecret
+		vdb.Spec.PasswordRotation = &vapi.PasswordRotationPolicy{IntervalDays: 90, TargetSecret: "su-rotated"}
+		test.CreateSuperuserPasswordSecret(ctx, vdb, k8sClient, userSecret, oldPasswd)
+		test.CreateVDB(ctx, k8sClient, vdb)
+		test.CreatePods(ctx, k8sClient, vdb, test.AllPodsRunning)
+		meta.SetStatusCondition(&vdb.Status.Conditions,
+			*vapi.MakeCondition(vapi.DBInitialized, metav1.ConditionTrue, "Initialized"))
+		statusSecret := userSecret
+		vdb.Status.PasswordSecret = &statusSecret
+		vdb.Status.PasswordRotation = &vapi.PasswordRotationStatus{
+			EnabledTime: &metav1.Time{Time: time.Now().Add(-91 * 24 * time.Hour)},
+		}
+		Expect(k8sClient.Status().Update(ctx, vdb)).Should(Succeed())
+		return vdb
+	}
+
+	deleteRotationVDB := func(vdb *vapi.VerticaDB) {
+		test.DeletePods(ctx, k8sClient, vdb)
+		test.DeleteVDB(ctx, k8sClient, vdb)
+		test.DeleteSecret(ctx, k8sClient, userSecret)
+		for i := 1; i <= 3; i++ {
+			test.DeleteSecret(ctx, k8sClient, vdb.GetPasswordRotationSecretName(i))
+		}
+	}
+
+	// makeRotationPodFacts returns pod facts with their own password so
+	// that the rotation doesn't change the password shared by the tests
+	makeRotationPodFacts := func(fpr *cmds.FakePodRunner) *podfacts.PodFacts {
+		passwd := oldPasswd
+		pfacts := podfacts.MakePodFacts(vdbRec, fpr, logger, &passwd)
+		pfacts.OverrideFunc = defaultPodFactOverrider
+		return &pfacts
+	}
+
+	It("should be a no-op if the password rotation isn't set", func() {
+		vdb := vapi.MakeVDB()
+		fpr := &cmds.FakePodRunner{}
+		r := MakePasswordRotationReconciler(vdbRec, logger, vdb, fpr, makeRotationPodFacts(fpr), false)
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(fpr.Histories).Should(BeEmpty())
+	})
+
+	It("should count the first rotation interval from when the policy is enabled", func() {
+		vdb := createRotationVDB()
+		defer deleteRotationVDB(vdb)
+		vdb.Status.PasswordRotation = nil
+		Expect(k8sClient.Status().Update(ctx, vdb)).Should(Succeed())
+
+		fpr := &cmds.FakePodRunner{}
+		r := MakePasswordRotationReconciler(vdbRec, logger, vdb, fpr, makeRotationPodFacts(fpr), false)
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(fpr.Histories).Should(BeEmpty())
+		Expect(vdb.Status.PasswordRotation.EnabledTime).ShouldNot(BeNil())
+		Expect(vdb.GetPasswordRotationNextIn(time.Now())).Should(BeNumerically(">", 89*24*time.Hour))
+
+		// Nothing is rotated before the interval passes
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(fpr.Histories).Should(BeEmpty())
+		Expect(vdb.Status.PasswordRotation.Rotations).Should(BeZero())
+
+		// Removing the policy clears the rotation state
+		vdb.Spec.PasswordRotation = nil
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(vdb.Status.PasswordRotation.EnabledTime).Should(BeNil())
+	})
+
+	It("should rotate the superuser password when it is due", func() {
+		vdb := createRotationVDB()
+		defer deleteRotationVDB(vdb)
+
+		fpr := &cmds.FakePodRunner{}
+		pfacts := makeRotationPodFacts(fpr)
+		r := MakePasswordRotationReconciler(vdbRec, logger, vdb, fpr, pfacts, false)
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+
+		rotatedSecret := vdb.GetPasswordRotationSecretName(1)
+		Expect(fpr.FindCommands("ALTER USER")).Should(HaveLen(1))
+		Expect(vdb.Spec.PasswordSecret).Should(Equal(userSecret))
+		Expect(*vdb.Status.PasswordSecret).Should(Equal(rotatedSecret))
+		Expect(vdb.Status.PasswordRotation.CurrentSecret).Should(Equal(rotatedSecret))
+		Expect(vdb.GetPasswordSecret()).Should(Equal(rotatedSecret))
+		fetchVdb := &vapi.VerticaDB{}
+		Expect(k8sClient.Get(ctx, vdb.ExtractNamespacedName(), fetchVdb)).Should(Succeed())
+		Expect(fetchVdb.Spec.PasswordSecret).Should(Equal(userSecret))
+		Expect(vdb.Status.PasswordRotation.Rotations).Should(Equal(1))
+		Expect(vdb.Status.PasswordRotation.PendingSecret).Should(BeEmpty())
+		Expect(vdb.GetPasswordRotationNextIn(time.Now())).Should(BeNumerically(">", 0))
+
+		sec := &corev1.Secret{}
+		Expect(k8sClient.Get(ctx, names.GenNamespacedName(vdb, rotatedSecret), sec)).Should(Succeed())
+		Expect(*pfacts.VerticaSUPassword).Should(Equal(string(sec.Data[names.SuperuserPasswordKey])))
+		Expect(*pfacts.VerticaSUPassword).ShouldNot(Equal(oldPasswd))
+
+		// Nothing is done until the interval passes
+		fpr.Histories = nil
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(fpr.Histories).Should(BeEmpty())
+
+		// The secret set by the user is kept
+		Expect(k8sClient.Get(ctx, names.GenNamespacedName(vdb, userSecret), sec)).Should(Succeed())
+	})
+
+	It("should complete an interrupted rotation when the pending password is in effect", func() {
+		vdb := createRotationVDB()
+		defer deleteRotationVDB(vdb)
+
+		pendingSecret := vdb.GetPasswordRotationSecretName(1)
+		Expect(k8sClient.Create(ctx, builder.BuildRotatedPasswordSecret(vdb,
+			names.GenNamespacedName(vdb, pendingSecret), "new-passwd"))).Should(Succeed())
+		vdb.Status.PasswordRotation.PendingSecret = pendingSecret
+		Expect(k8sClient.Status().Update(ctx, vdb)).Should(Succeed())
+
+		// The login with the current password fails, the one with the
+		// pending password works
+		fpr := &cmds.FakePodRunner{}
+		pfacts := makeRotationPodFacts(fpr)
+		Expect(pfacts.Collect(ctx, vdb)).Should(Succeed())
+		pn := names.GenPodName(vdb, &vdb.Spec.Subclusters[0], 0)
+		fpr.Results = cmds.CmdResults{
+			pn: []cmds.CmdResult{{Err: errors.New("invalid username or password")}, {}},
+		}
+		r := MakePasswordRotationReconciler(vdbRec, logger, vdb, fpr, pfacts, true)
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+
+		Expect(fpr.FindCommands("ALTER USER")).Should(BeEmpty())
+		Expect(vdb.Spec.PasswordSecret).Should(Equal(userSecret))
+		Expect(*vdb.Status.PasswordSecret).Should(Equal(pendingSecret))
+		Expect(vdb.Status.PasswordRotation.CurrentSecret).Should(Equal(pendingSecret))
+		Expect(vdb.Status.PasswordRotation.Rotations).Should(Equal(1))
+		Expect(vdb.IsPasswordRotationPending()).Should(BeFalse())
+		Expect(*pfacts.VerticaSUPassword).Should(Equal("new-passwd"))
+	})
+
+	It("should not change anything if neither password works", func() {
+		vdb := createRotationVDB()
+		defer deleteRotationVDB(vdb)
+
+		pendingSecret := vdb.GetPasswordRotationSecretName(1)
+		Expect(k8sClient.Create(ctx, builder.BuildRotatedPasswordSecret(vdb,
+			names.GenNamespacedName(vdb, pendingSecret), "new-passwd"))).Should(Succeed())
+		vdb.Status.PasswordRotation.PendingSecret = pendingSecret
+		Expect(k8sClient.Status().Update(ctx, vdb)).Should(Succeed())
+
+		fpr := &cmds.FakePodRunner{}
+		pfacts := makeRotationPodFacts(fpr)
+		Expect(pfacts.Collect(ctx, vdb)).Should(Succeed())
+		pn := names.GenPodName(vdb, &vdb.Spec.Subclusters[0], 0)
+		loginErr := cmds.CmdResult{Err: errors.New("invalid username or password")}
+		fpr.Results = cmds.CmdResults{pn: []cmds.CmdResult{loginErr, loginErr}}
+		r := MakePasswordRotationReconciler(vdbRec, logger, vdb, fpr, pfacts, false)
+		_, err := r.Reconcile(ctx, &ctrl.Request{})
+		Expect(err).ShouldNot(Succeed())
+
+		Expect(vdb.Spec.PasswordSecret).Should(Equal(userSecret))
+		Expect(*vdb.Status.PasswordSecret).Should(Equal(userSecret))
+		Expect(vdb.Status.PasswordRotation.PendingSecret).Should(Equal(pendingSecret))
+		Expect(fpr.FindCommands("ALTER USER")).Should(BeEmpty())
+	})
+})
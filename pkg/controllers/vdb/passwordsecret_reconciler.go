@@ -68,6 +68,12 @@ func (a *PasswordSecretReconciler) Reconcile(ctx context.Context, _ *ctrl.Reques
 		return ctrl.Result{}, a.updatePasswordSecretStatus(ctx)
 	}
 
+	// The PasswordRotationReconciler owns the password secret until the
+	// rotation it started is completed
+	if a.Vdb.IsPasswordRotationPending() {
+		return ctrl.Result{}, nil
+	}
+
 	// No actions needed if status content is the same to spec
 	if a.statusMatchesSpec() {
 		return ctrl.Result{}, nil
@@ -81,16 +87,18 @@ func (a *PasswordSecretReconciler) Reconcile(ctx context.Context, _ *ctrl.Reques
 	return ctrl.Result{}, a.updatePasswordSecretStatus(ctx)
 }
 
-// statusMatchesSpec checks if the password secret status is the same to spec or not
+// statusMatchesSpec checks if the password secret status is the same to spec or not.
+// While the password is rotated by the operator, the secret of the last
+// rotation is used in place of the one in the spec.
 func (a *PasswordSecretReconciler) statusMatchesSpec() bool {
-	return a.Vdb.Spec.PasswordSecret == *a.Vdb.Status.PasswordSecret
+	return a.Vdb.GetDesiredPasswordSecret() == *a.Vdb.Status.PasswordSecret
 }
 
 // updatePasswordSecret will update the password secret in the database
 func (a *PasswordSecretReconciler) updatePasswordSecret(ctx context.Context) (ctrl.Result, error) {
 	// The password to be updated to
 	newPasswd, err := vk8s.GetCustomSuperuserPassword(ctx, a.VRec.Client, a.Log, a.VRec, a.Vdb,
-		a.Vdb.Spec.PasswordSecret, names.SuperuserPasswordKey)
+		a.Vdb.GetDesiredPasswordSecret(), names.SuperuserPasswordKey)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -98,7 +106,7 @@ func (a *PasswordSecretReconciler) updatePasswordSecret(ctx context.Context) (ct
 	// No-op if password is the same
 	if *a.PFacts.VerticaSUPassword == *newPasswd {
 		a.Log.Info("WARNING: password in secret is the same as current password", "current password secret",
-			a.Vdb.Status.PasswordSecret, "new password secret", a.Vdb.Spec.PasswordSecret)
+			a.Vdb.Status.PasswordSecret, "new password secret", a.Vdb.GetDesiredPasswordSecret())
 		return ctrl.Result{}, nil
 	}
 
@@ -124,7 +132,7 @@ func (a *PasswordSecretReconciler) updatePasswordSecret(ctx context.Context) (ct
 
 	a.VRec.Eventf(a.Vdb, corev1.EventTypeNormal, events.SuperuserPasswordSecretUpdateSucceeded,
 		"Superuser password update succeeded")
-	a.VRec.Log.Info("Successfully updated superuser password secret", "stdout", stdout, "new secret", a.Vdb.Spec.PasswordSecret)
+	a.VRec.Log.Info("Successfully updated superuser password secret", "stdout", stdout, "new secret", a.Vdb.GetDesiredPasswordSecret())
 
 	// reset password used in vdb
 	a.resetVDBPassword(*newPasswd)
@@ -135,7 +143,7 @@ func (a *PasswordSecretReconciler) updatePasswordSecret(ctx context.Context) (ct
 func (a *PasswordSecretReconciler) updatePasswordSecretStatus(ctx context.Context) error {
 	updateStatus := func(vdbChg *vapi.VerticaDB) error {
 		// make a copy of the password secret in the status
-		statusSecret := a.Vdb.Spec.PasswordSecret
+		statusSecret := a.Vdb.GetDesiredPasswordSecret()
 		vdbChg.Status.PasswordSecret = &statusSecret
 		return nil
 	}
@@ -50,6 +50,21 @@ var _ = Describe("passwordsecret_reconcile", func() {
 		Expect(a.statusMatchesSpec()).To(BeTrue())
 	})
 
+	It("should compare the status with the secret of the last rotation when the password is rotated", func() {
+		vdb := vapi.MakeVDB()
+		vdb.Spec.PasswordSecret = suPassword1
+		vdb.Spec.PasswordRotation = &vapi.PasswordRotationPolicy{TargetSecret: suPassword2}
+		rotatedSecret := vdb.GetPasswordRotationSecretName(1)
+		vdb.Status.PasswordSecret = &rotatedSecret
+		vdb.Status.PasswordRotation = &vapi.PasswordRotationStatus{CurrentSecret: rotatedSecret}
+		a := PasswordSecretReconciler{Vdb: vdb, Log: logger}
+		Expect(a.statusMatchesSpec()).To(BeTrue())
+
+		// The spec is in effect again once the policy is removed
+		vdb.Spec.PasswordRotation = nil
+		Expect(a.statusMatchesSpec()).To(BeFalse())
+	})
+
 	It("should update status when status.passwordSecret is nil", func() {
 		vdb := vapi.MakeVDB()
 
@@ -0,0 +1,136 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vdb
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/podfacts"
+	"github.com/vertica/vertica-kubernetes/pkg/vdbstatus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// PlanReporter is implemented by the actors that can report the actions they
+// would take without taking them. Plan uses the same decision logic as
+// Reconcile, but only reads the current state.
+type PlanReporter interface {
+	Plan(ctx context.Context) ([]vapi.PlannedAction, error)
+}
+
+// reconcilePlan will ask each actor, in the order they would run, for the
+// actions it would take. The result is saved in the status. None of the
+// actors are run.
+func (r *VerticaDBReconciler) reconcilePlan(ctx context.Context, log logr.Logger, vdb *vapi.VerticaDB,
+	actors []controllers.ReconcileActor) (ctrl.Result, error) {
+	plan := &vapi.ReconcilePlan{
+		GeneratedTime:      metav1.Now(),
+		ObservedGeneration: vdb.Generation,
+	}
+	for _, act := range actors {
+		planner, ok := act.(PlanReporter)
+		if !ok {
+			continue
+		}
+		actions, err := planner.Plan(ctx)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to plan %T: %w", act, err)
+		}
+		plan.Actions = append(plan.Actions, actions...)
+	}
+	log.Info("Computed plan for VerticaDB", "actions", len(plan.Actions))
+	// Updating the status triggers another reconcile. Skip the update if
+	// nothing changed so that we don't loop.
+	if cur := vdb.Status.Plan; cur != nil && cur.ObservedGeneration == plan.ObservedGeneration &&
+		reflect.DeepEqual(cur.Actions, plan.Actions) {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{}, vdbstatus.SetPlan(ctx, r.Client, vdb, plan)
+}
+
+// makePlannedAction builds a PlannedAction for the given pods. The pods are
+// listed by name in sorted order.
+func makePlannedAction(reconciler, scName string, pods []string, descFmt string,
+	args ...interface{}) vapi.PlannedAction {
+	action := vapi.PlannedAction{
+		Reconciler:  reconciler,
+		Subcluster:  scName,
+		Description: fmt.Sprintf(descFmt, args...),
+	}
+	action.Pods = append(action.Pods, pods...)
+	sort.Strings(action.Pods)
+	return action
+}
+
+// groupPodsBySubcluster returns the names of the pods that pass the filter
+// grouped by subcluster. The subcluster names are returned in sorted order.
+func groupPodsBySubcluster(pfacts *podfacts.PodFacts, filterFunc func(pf *podfacts.PodFact) bool) (
+	scNames []string, pods map[string][]string) {
+	pods = map[string][]string{}
+	for _, pf := range pfacts.Detail {
+		if !filterFunc(pf) {
+			continue
+		}
+		if _, ok := pods[pf.GetSubclusterName()]; !ok {
+			scNames = append(scNames, pf.GetSubclusterName())
+		}
+		pods[pf.GetSubclusterName()] = append(pods[pf.GetSubclusterName()], pf.GetName().Name)
+	}
+	sort.Strings(scNames)
+	return scNames, pods
+}
+
+// findPodsToAddToDB returns the names of the pods that add node would run
+// for, grouped by subcluster. During a scale out, the pods don't exist until
+// the ObjReconciler resizes the statefulset, so every pod of the spec that
+// isn't in the pod facts is included as well. The subcluster names are
+// returned in sorted order.
+func findPodsToAddToDB(vdb *vapi.VerticaDB, pfacts *podfacts.PodFacts) (scNames []string, pods map[string][]string) {
+	pods = map[string][]string{}
+	scStatusMap := vdb.GenSubclusterStatusMap()
+	scSbMap := vdb.GenSubclusterSandboxMap()
+	for i := range vdb.Spec.Subclusters {
+		sc := &vdb.Spec.Subclusters[i]
+		if _, ok := scSbMap[sc.Name]; ok || sc.Shutdown || isSubclusterShutdown(scStatusMap, sc.Name) {
+			continue
+		}
+		for j := int32(0); j < sc.Size; j++ {
+			nm := names.GenPodName(vdb, sc, j)
+			if pf, ok := pfacts.Detail[nm]; ok && !needsAddNode(pf) {
+				continue
+			}
+			pods[sc.Name] = append(pods[sc.Name], nm.Name)
+		}
+		if len(pods[sc.Name]) > 0 {
+			scNames = append(scNames, sc.Name)
+		}
+	}
+	sort.Strings(scNames)
+	return scNames, pods
+}
+
+// joinNames returns the names as a comma separated string
+func joinNames(nms []string) string {
+	return strings.Join(nms, ", ")
+}
@@ -0,0 +1,157 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/pkg/cmds"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/test"
+	"github.com/vertica/vertica-kubernetes/pkg/vdbstatus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("plan", func() {
+	ctx := context.Background()
+
+	createInitializedVDB := func(vdb *vapi.VerticaDB) {
+		test.CreateVDB(ctx, k8sClient, vdb)
+		Expect(vdbstatus.UpdateCondition(ctx, k8sClient, vdb,
+			vapi.MakeCondition(vapi.DBInitialized, metav1.ConditionTrue, "Initialized"))).Should(Succeed())
+	}
+
+	It("should plan add node and rebalance for a scale out", func() {
+		vdb := vapi.MakeVDB()
+		vdb.Spec.Subclusters[0].Size = 3
+		createInitializedVDB(vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+		test.CreatePods(ctx, k8sClient, vdb, test.AllPodsRunning)
+		defer test.DeletePods(ctx, k8sClient, vdb)
+
+		fpr := &cmds.FakePodRunner{}
+		pfacts := createPodFactsWithNoDB(ctx, vdb, fpr, 1)
+		act := MakeDBAddNodeReconciler(vdbRec, logger, vdb, fpr, pfacts, nil)
+		actions, err := act.(PlanReporter).Plan(ctx)
+		Expect(err).Should(Succeed())
+		Expect(actions).Should(HaveLen(1))
+		Expect(actions[0].Reconciler).Should(Equal("DBAddNode"))
+		Expect(actions[0].Subcluster).Should(Equal(vdb.Spec.Subclusters[0].Name))
+		Expect(actions[0].Pods).Should(HaveLen(1))
+
+		act = MakeRebalanceShardsReconciler(vdbRec, logger, vdb, fpr, pfacts, "")
+		actions, err = act.(PlanReporter).Plan(ctx)
+		Expect(err).Should(Succeed())
+		Expect(actions).Should(HaveLen(1))
+		Expect(actions[0].Subcluster).Should(Equal(vdb.Spec.Subclusters[0].Name))
+		Expect(fpr.FindCommands("rebalance_shards")).Should(BeEmpty())
+	})
+
+	It("should plan every step of a scale out in the order the actors run", func() {
+		vdb := vapi.MakeVDB()
+		vdb.Spec.Subclusters[0].Size = 3
+		createInitializedVDB(vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+		test.CreatePods(ctx, k8sClient, vdb, test.AllPodsRunning)
+		defer test.DeletePods(ctx, k8sClient, vdb)
+
+		// Scale out before the statefulset is resized, so the new pods don't
+		// exist yet
+		sc := &vdb.Spec.Subclusters[0]
+		sc.Size = 5
+		fpr := &cmds.FakePodRunner{}
+		pfacts := createPodFactsDefault(fpr)
+		actors := vdbRec.constructActors(logger, vdb, &cmds.ClusterPodRunner{}, pfacts, mockVClusterOpsDispatcher(vdb))
+		Expect(vdbRec.reconcilePlan(ctx, logger, vdb, actors)).Should(Equal(ctrl.Result{}))
+		Expect(vdb.Status.Plan).ShouldNot(BeNil())
+		Expect(vdb.Status.Plan.Actions).Should(Equal([]vapi.PlannedAction{
+			{
+				Reconciler:  "Obj",
+				Subcluster:  sc.Name,
+				Description: fmt.Sprintf("Resize statefulset %s from 3 to 5 pods", names.GenStsName(vdb, sc).Name),
+			},
+			{
+				Reconciler:  "DBAddNode",
+				Subcluster:  sc.Name,
+				Pods:        []string{names.GenPodName(vdb, sc, 3).Name, names.GenPodName(vdb, sc, 4).Name},
+				Description: fmt.Sprintf("Add 2 nodes to subcluster %s", sc.Name),
+			},
+			{
+				Reconciler:  "RebalanceShards",
+				Subcluster:  sc.Name,
+				Description: fmt.Sprintf("Rebalance the shards of subcluster %s", sc.Name),
+			},
+		}))
+		expectOnlyPodFactsGathered(fpr)
+	})
+
+	It("should plan remove node for a scale in", func() {
+		vdb := vapi.MakeVDB()
+		vdb.Spec.Subclusters[0].Size = 3
+		createInitializedVDB(vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+		test.CreatePods(ctx, k8sClient, vdb, test.AllPodsRunning)
+		defer test.DeletePods(ctx, k8sClient, vdb)
+
+		vdb.Spec.Subclusters[0].Size = 2
+		fpr := &cmds.FakePodRunner{}
+		pfacts := createPodFactsDefault(fpr)
+		act := MakeDBRemoveNodeReconciler(vdbRec, logger, vdb, fpr, pfacts, nil)
+		actions, err := act.(PlanReporter).Plan(ctx)
+		Expect(err).Should(Succeed())
+		Expect(actions).Should(HaveLen(1))
+		Expect(actions[0].Reconciler).Should(Equal("DBRemoveNode"))
+		Expect(actions[0].Pods).Should(ConsistOf(names.GenPodName(vdb, &vdb.Spec.Subclusters[0], 2).Name))
+	})
+
+	It("should save the plan in the status without running the actors", func() {
+		vdb := vapi.MakeVDB()
+		vdb.Spec.Subclusters[0].Size = 2
+		createInitializedVDB(vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+		test.CreatePods(ctx, k8sClient, vdb, test.AllPodsRunning)
+		defer test.DeletePods(ctx, k8sClient, vdb)
+
+		fpr := &cmds.FakePodRunner{}
+		pfacts := createPodFactsWithNoDB(ctx, vdb, fpr, 2)
+		actors := []controllers.ReconcileActor{
+			MakeDBAddNodeReconciler(vdbRec, logger, vdb, fpr, pfacts, nil),
+			MakeRebalanceShardsReconciler(vdbRec, logger, vdb, fpr, pfacts, ""),
+		}
+		Expect(vdbRec.reconcilePlan(ctx, logger, vdb, actors)).Should(Equal(ctrl.Result{}))
+		Expect(vdb.Status.Plan).ShouldNot(BeNil())
+		Expect(vdb.Status.Plan.ObservedGeneration).Should(Equal(vdb.Generation))
+		Expect(vdb.Status.Plan.Actions).Should(HaveLen(2))
+		Expect(vdb.Status.Plan.Actions[0].Reconciler).Should(Equal("DBAddNode"))
+		Expect(vdb.Status.Plan.Actions[1].Reconciler).Should(Equal("RebalanceShards"))
+		expectOnlyPodFactsGathered(fpr)
+	})
+})
+
+// expectOnlyPodFactsGathered checks that the only commands run in the pods were
+// the ones that collect the pod facts. Planning must not change anything.
+func expectOnlyPodFactsGathered(fpr *cmds.FakePodRunner) {
+	for i := range fpr.Histories {
+		ExpectWithOffset(1, strings.Join(fpr.Histories[i].Command, " ")).Should(ContainSubstring("pod-fact-gather"))
+	}
+}
@@ -960,3 +960,8 @@ func findSubclusterActiveSessions(
 	}
 	return sessionIds, nil
 }
+
+// Plan reports if a read-only online upgrade would be done
+func (r *ReadOnlyOnlineUpgradeReconciler) Plan(ctx context.Context) ([]vapi.PlannedAction, error) {
+	return planUpgrade(ctx, &r.Manager, r.PFacts.GetSandboxName(), "ReadOnlyOnlineUpgrade", "read-only online")
+}
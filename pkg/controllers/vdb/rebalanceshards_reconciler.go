@@ -17,16 +17,26 @@ package vdb
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	vapi "github.com/vertica/vertica-kubernetes/api/v1"
 	"github.com/vertica/vertica-kubernetes/pkg/cmds"
 	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	verrors "github.com/vertica/vertica-kubernetes/pkg/errors"
 	"github.com/vertica/vertica-kubernetes/pkg/events"
+	vmeta "github.com/vertica/vertica-kubernetes/pkg/meta"
+	"github.com/vertica/vertica-kubernetes/pkg/metrics"
 	"github.com/vertica/vertica-kubernetes/pkg/names"
 	"github.com/vertica/vertica-kubernetes/pkg/podfacts"
+	"github.com/vertica/vertica-kubernetes/pkg/vdbstatus"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
@@ -55,8 +65,7 @@ func MakeRebalanceShardsReconciler(vdbrecon *VerticaDBReconciler, log logr.Logge
 
 // Reconcile will ensure each node has at least one shard subscription
 func (s *RebalanceShardsReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (ctrl.Result, error) {
-	// Skip if not running in EON mode
-	if !s.Vdb.IsEON() {
+	if s.shouldSkipReconcile() {
 		return ctrl.Result{}, nil
 	}
 
@@ -69,6 +78,14 @@ func (s *RebalanceShardsReconciler) Reconcile(ctx context.Context, _ *ctrl.Reque
 		return ctrl.Result{}, nil
 	}
 
+	// If the last rebalance ran past its maximum duration, we wait before
+	// trying again. We don't requeue from here so that the actors after this
+	// one still run. The retry is scheduled at the end of the reconcile.
+	if retryIn := s.Vdb.GetRebalanceShardsRetryIn(time.Now()); retryIn > 0 {
+		s.Log.Info("Backing off from rebalance_shards after the last one timed out", "retryIn", retryIn)
+		return ctrl.Result{}, nil
+	}
+
 	atPod, ok := s.PFacts.FindFirstUpPod(false, "")
 	if !ok {
 		s.Log.Info("No pod found to run vsql from. Requeue reconciliation.")
@@ -76,8 +93,9 @@ func (s *RebalanceShardsReconciler) Reconcile(ctx context.Context, _ *ctrl.Reque
 	}
 
 	for i := range scToRebalance {
-		if err := s.rebalanceShards(ctx, atPod, scToRebalance[i]); err != nil {
-			return ctrl.Result{}, err
+		res, err := s.rebalanceShards(ctx, atPod, scToRebalance[i])
+		if verrors.IsReconcileAborted(res, err) {
+			return res, err
 		}
 		s.PFacts.Invalidate() // Refresh due to shard subscriptions have changed
 	}
@@ -103,19 +121,231 @@ func (s *RebalanceShardsReconciler) findShardsToRebalance() []string {
 	return scToRebalance
 }
 
-// rebalanceShards will run rebalance_shards for the given subcluster
-func (s *RebalanceShardsReconciler) rebalanceShards(ctx context.Context, atPod *podfacts.PodFact, scName string) error {
+// rebalanceShards will run rebalance_shards for the given subcluster. The
+// rebalance is run in the background while we poll its progress. If it runs
+// past the maximum duration, we cancel it and back off.
+func (s *RebalanceShardsReconciler) rebalanceShards(ctx context.Context, atPod *podfacts.PodFact, scName string) (ctrl.Result, error) {
 	podName := atPod.GetName()
 	selectCmd := fmt.Sprintf("select rebalance_shards('%s')", scName)
 	cmd := []string{
 		"-tAc", selectCmd,
 	}
-	_, _, err := s.PRunner.ExecVSQL(ctx, podName, names.ServerContainer, cmd...)
+
+	start := time.Now()
+	tracker := rebalanceProgress{Subcluster: scName, StartTime: start}
+	if err := s.updateProgressCondition(ctx, &tracker); err != nil {
+		return ctrl.Result{}, err
+	}
+	labels := metrics.MakeVDBLabels(s.Vdb)
+	metrics.RebalanceShardsAttempt.With(labels).Inc()
+
+	rebalanceCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := s.PRunner.ExecVSQL(rebalanceCtx, podName, names.ServerContainer, cmd...)
+		done <- err
+	}()
+
+	maxDuration := time.Duration(vmeta.GetRebalanceShardsMaxDuration(s.Vdb.Annotations)) * time.Second
+	pollingDuration := time.Duration(vmeta.GetRebalanceShardsPollingFrequency(s.Vdb.Annotations)) * time.Second
+	ticker := time.NewTicker(pollingDuration)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-done:
+			return s.finishRebalance(ctx, &tracker, err)
+		case <-ticker.C:
+			if maxDuration > 0 && time.Since(start) > maxDuration {
+				// Stopping vsql doesn't stop the rebalance in the server, so
+				// its session is closed first.
+				s.cancelRebalance(ctx, atPod, scName)
+				cancel()
+				return s.timeoutRebalance(ctx, &tracker, maxDuration)
+			}
+			// A failure to get the progress is not fatal. We will try again at
+			// the next poll.
+			if err := s.pollProgress(ctx, atPod, &tracker); err != nil {
+				s.Log.Info("Failed to get the rebalance progress", "subcluster", scName, "err", err)
+				continue
+			}
+			if err := s.updateProgressCondition(ctx, &tracker); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
+}
+
+// finishRebalance records the outcome of a rebalance that has returned
+func (s *RebalanceShardsReconciler) finishRebalance(ctx context.Context, tracker *rebalanceProgress, rebalanceErr error) (ctrl.Result, error) {
+	labels := metrics.MakeVDBLabels(s.Vdb)
+	elapsed := time.Since(tracker.StartTime)
+	metrics.RebalanceShardsDuration.With(labels).Observe(elapsed.Seconds())
+	s.resetProgressMetrics()
+
+	if rebalanceErr != nil {
+		metrics.RebalanceShardsFailed.With(labels).Inc()
+		s.VRec.Eventf(s.Vdb, corev1.EventTypeWarning, events.RebalanceShardsFailed,
+			"Failed to rebalance the shards of subcluster '%s'", tracker.Subcluster)
+		cond := vapi.MakeCondition(vapi.RebalanceShardsInProgress, metav1.ConditionFalse, vapi.RebalanceShardsFailedReason)
+		cond.Message = fmt.Sprintf("rebalance_shards failed for subcluster %s", tracker.Subcluster)
+		if err := vdbstatus.UpdateCondition(ctx, s.VRec.Client, s.Vdb, cond); err != nil {
+			return ctrl.Result{}, errors.Join(rebalanceErr, err)
+		}
+		return ctrl.Result{}, rebalanceErr
+	}
+
+	s.VRec.Eventf(s.Vdb, corev1.EventTypeNormal, events.RebalanceShards,
+		"Successfully called 'rebalance_shards' for '%s' in %s", tracker.Subcluster, elapsed.Truncate(time.Second))
+	cond := vapi.MakeCondition(vapi.RebalanceShardsInProgress, metav1.ConditionFalse, vapi.RebalanceShardsCompletedReason)
+	cond.Message = fmt.Sprintf("Rebalanced the shards of subcluster %s", tracker.Subcluster)
+	return ctrl.Result{}, vdbstatus.UpdateCondition(ctx, s.VRec.Client, s.Vdb, cond)
+}
+
+// cancelRebalance closes the session that runs rebalance_shards for the
+// subcluster. A failure is logged, since the rebalance may have just finished.
+func (s *RebalanceShardsReconciler) cancelRebalance(ctx context.Context, atPod *podfacts.PodFact, scName string) {
+	sql := fmt.Sprintf("select close_session(session_id) from v_monitor.sessions "+
+		"where current_statement ilike 'select rebalance_shards(''%s'')%%'", scName)
+	if _, stderr, err := s.PRunner.ExecVSQL(ctx, atPod.GetName(), names.ServerContainer, "-tAc", sql); err != nil {
+		s.Log.Info("Failed to cancel the rebalance", "subcluster", scName, "stderr", stderr, "err", err)
+	}
+}
+
+// timeoutRebalance records that we gave up waiting for a rebalance. The
+// condition it sets makes us back off for the max duration before trying
+// again.
+func (s *RebalanceShardsReconciler) timeoutRebalance(ctx context.Context, tracker *rebalanceProgress,
+	maxDuration time.Duration) (ctrl.Result, error) {
+	labels := metrics.MakeVDBLabels(s.Vdb)
+	metrics.RebalanceShardsFailed.With(labels).Inc()
+	metrics.RebalanceShardsDuration.With(labels).Observe(time.Since(tracker.StartTime).Seconds())
+	s.resetProgressMetrics()
+
+	s.VRec.Eventf(s.Vdb, corev1.EventTypeWarning, events.RebalanceShardsTimedOut,
+		"The rebalance of subcluster '%s' did not finish within %s and was cancelled. It is %d%% complete. "+
+			"Backing off before trying again",
+		tracker.Subcluster, maxDuration, tracker.percent())
+	cond := vapi.MakeCondition(vapi.RebalanceShardsInProgress, metav1.ConditionFalse, vapi.RebalanceShardsTimedOutReason)
+	cond.Message = fmt.Sprintf("Timed out after %s while rebalancing subcluster %s: %s",
+		maxDuration, tracker.Subcluster, tracker.String())
+	return ctrl.Result{}, vdbstatus.UpdateCondition(ctx, s.VRec.Client, s.Vdb, cond)
+}
+
+// pollProgress will query the database to find out how far along the
+// rebalance is. Progress is measured by the shard subscriptions of the
+// subcluster that have reached the ACTIVE state.
+func (s *RebalanceShardsReconciler) pollProgress(ctx context.Context, atPod *podfacts.PodFact, tracker *rebalanceProgress) error {
+	sql := fmt.Sprintf(
+		"select count(*) from v_catalog.node_subscriptions ns join v_catalog.subclusters sc "+
+			"on ns.node_name = sc.node_name where sc.subcluster_name = '%s' and ns.subscription_state != 'ACTIVE'",
+		tracker.Subcluster)
+	stdout, _, err := s.PRunner.ExecVSQL(ctx, atPod.GetName(), names.ServerContainer, "-tAc", sql)
 	if err != nil {
 		return err
 	}
-	s.VRec.Eventf(s.Vdb, corev1.EventTypeNormal, events.RebalanceShards,
-		"Successfully called 'rebalance_shards' for '%s'", scName)
+	pending, err := parseRebalanceProgress(stdout)
+	if err != nil {
+		return err
+	}
+	tracker.update(pending)
 
+	labels := metrics.MakeVDBLabels(s.Vdb)
+	metrics.RebalanceShardsProgress.With(labels).Set(float64(tracker.percent()))
+	metrics.RebalanceShardsMoved.With(labels).Set(float64(tracker.ShardsMoved))
 	return nil
 }
+
+// updateProgressCondition sets the RebalanceShardsInProgress condition with
+// the current progress of the rebalance.
+func (s *RebalanceShardsReconciler) updateProgressCondition(ctx context.Context, tracker *rebalanceProgress) error {
+	cond := vapi.MakeCondition(vapi.RebalanceShardsInProgress, metav1.ConditionTrue, vapi.RebalanceShardsRunningReason)
+	cond.Message = fmt.Sprintf("Rebalancing subcluster %s: %s", tracker.Subcluster, tracker.String())
+	return vdbstatus.UpdateCondition(ctx, s.VRec.Client, s.Vdb, cond)
+}
+
+// resetProgressMetrics zeroes the gauges that track an ongoing rebalance
+func (s *RebalanceShardsReconciler) resetProgressMetrics() {
+	labels := metrics.MakeVDBLabels(s.Vdb)
+	metrics.RebalanceShardsProgress.With(labels).Set(0)
+	metrics.RebalanceShardsMoved.With(labels).Set(0)
+}
+
+// rebalanceProgress tracks how far along a single rebalance_shards call is
+type rebalanceProgress struct {
+	Subcluster string
+	StartTime  time.Time
+	// The most shard subscriptions we have seen pending. This is our best
+	// estimate of the total number of subscriptions the rebalance will move.
+	TotalShards int
+	// The number of shard subscriptions that have reached the ACTIVE state
+	ShardsMoved int
+}
+
+// update refreshes the progress from the latest poll
+func (r *rebalanceProgress) update(pending int) {
+	if pending > r.TotalShards {
+		r.TotalShards = pending
+	}
+	r.ShardsMoved = r.TotalShards - pending
+}
+
+// percent returns the percentage of shard subscriptions that were moved
+func (r *rebalanceProgress) percent() int {
+	if r.TotalShards == 0 {
+		return 0
+	}
+	const full = 100
+	return r.ShardsMoved * full / r.TotalShards
+}
+
+func (r *rebalanceProgress) String() string {
+	return fmt.Sprintf("%d%% complete (%d/%d shards active)",
+		r.percent(), r.ShardsMoved, r.TotalShards)
+}
+
+// parseRebalanceProgress parses the output of the progress query, which is
+// the number of shard subscriptions that aren't ACTIVE yet
+func parseRebalanceProgress(stdout string) (int, error) {
+	pending, err := strconv.Atoi(strings.TrimSpace(stdout))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse pending subscriptions from %q: %w", stdout, err)
+	}
+	return pending, nil
+}
+
+// shouldSkipReconcile returns true if shards are never rebalanced. Only Eon
+// mode databases have shards.
+func (s *RebalanceShardsReconciler) shouldSkipReconcile() bool {
+	return !s.Vdb.IsEON()
+}
+
+// Plan reports the subclusters whose shards would be rebalanced. This includes
+// subclusters that are going to get new nodes, since add node runs before this
+// actor.
+func (s *RebalanceShardsReconciler) Plan(ctx context.Context) ([]vapi.PlannedAction, error) {
+	if s.shouldSkipReconcile() || !s.Vdb.IsDBInitialized() {
+		return nil, nil
+	}
+	if err := s.PFacts.Collect(ctx, s.Vdb); err != nil {
+		return nil, err
+	}
+	scNames := []string{}
+	addNodeScNames, _ := findPodsToAddToDB(s.Vdb, s.PFacts)
+	for _, scName := range addNodeScNames {
+		if s.ScName == "" || s.ScName == scName {
+			scNames = append(scNames, scName)
+		}
+	}
+	scNames = append(scNames, s.findShardsToRebalance()...)
+	sort.Strings(scNames)
+	actions := []vapi.PlannedAction{}
+	for i, scName := range scNames {
+		if i > 0 && scNames[i-1] == scName {
+			continue
+		}
+		actions = append(actions, makePlannedAction("RebalanceShards", scName, nil,
+			"Rebalance the shards of subcluster %s", scName))
+	}
+	return actions, nil
+}
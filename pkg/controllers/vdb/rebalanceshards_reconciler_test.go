@@ -17,14 +17,18 @@ package vdb
 
 import (
 	"context"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	vapi "github.com/vertica/vertica-kubernetes/api/v1"
 	"github.com/vertica/vertica-kubernetes/pkg/cmds"
+	vmeta "github.com/vertica/vertica-kubernetes/pkg/meta"
 	"github.com/vertica/vertica-kubernetes/pkg/names"
 	"github.com/vertica/vertica-kubernetes/pkg/podfacts"
 	"github.com/vertica/vertica-kubernetes/pkg/test"
+	"github.com/vertica/vertica-kubernetes/pkg/vdbstatus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
@@ -37,6 +41,8 @@ var _ = Describe("rebalanceshards_reconcile", func() {
 			{Name: "sc1", Size: 1},
 			{Name: "sc2", Size: 1},
 		}
+		test.CreateVDB(ctx, k8sClient, vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
 		test.CreatePods(ctx, k8sClient, vdb, test.AllPodsRunning)
 		defer test.DeletePods(ctx, k8sClient, vdb)
 
@@ -55,6 +61,10 @@ var _ = Describe("rebalanceshards_reconcile", func() {
 		Expect(len(atCmd)).Should(Equal(1))
 		atCmd = fpr.FindCommands("select rebalance_shards('sc2')")
 		Expect(len(atCmd)).Should(Equal(0))
+		cond := vdb.FindStatusCondition(vapi.RebalanceShardsInProgress)
+		Expect(cond).ShouldNot(BeNil())
+		Expect(cond.Status).Should(Equal(metav1.ConditionFalse))
+		Expect(cond.Reason).Should(Equal(vapi.RebalanceShardsCompletedReason))
 	})
 
 	It("should only run rebalance shards against specified subcluster ", func() {
@@ -63,6 +73,8 @@ var _ = Describe("rebalanceshards_reconcile", func() {
 			{Name: "sc1", Size: 1},
 			{Name: "sc2", Size: 1},
 		}
+		test.CreateVDB(ctx, k8sClient, vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
 		test.CreatePods(ctx, k8sClient, vdb, test.AllPodsRunning)
 		defer test.DeletePods(ctx, k8sClient, vdb)
 
@@ -81,4 +93,45 @@ var _ = Describe("rebalanceshards_reconcile", func() {
 		atCmd = fpr.FindCommands("select rebalance_shards('sc2')")
 		Expect(len(atCmd)).Should(Equal(1))
 	})
+
+	It("should back off if the last rebalance timed out", func() {
+		vdb := vapi.MakeVDB()
+		vdb.Annotations[vmeta.RebalanceShardsMaxDurationAnnotation] = "600"
+		test.CreateVDB(ctx, k8sClient, vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+		test.CreatePods(ctx, k8sClient, vdb, test.AllPodsRunning)
+		defer test.DeletePods(ctx, k8sClient, vdb)
+		Expect(vdbstatus.UpdateCondition(ctx, k8sClient, vdb,
+			vapi.MakeCondition(vapi.RebalanceShardsInProgress, metav1.ConditionFalse, vapi.RebalanceShardsTimedOutReason))).Should(Succeed())
+
+		fpr := &cmds.FakePodRunner{}
+		pfacts := podfacts.MakePodFacts(vdbRec, fpr, logger, &testPassword)
+		Expect(pfacts.Collect(ctx, vdb)).Should(Succeed())
+		pn := names.GenPodName(vdb, &vdb.Spec.Subclusters[0], 0)
+		pfacts.Detail[pn].SetUpNode(true)
+		pfacts.Detail[pn].SetShardSubscriptions(0)
+		r := MakeRebalanceShardsReconciler(vdbRec, logger, vdb, fpr, &pfacts, "")
+		// The actors that follow must still run, so the retry is scheduled
+		// at the end of the reconcile rather than by this actor.
+		Expect(r.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(fpr.FindCommands("select rebalance_shards")).Should(BeEmpty())
+		Expect(vdb.GetRebalanceShardsRetryIn(time.Now())).Should(BeNumerically(">", 0))
+		Expect(scheduleNextPeriodicTask(vdb, ctrl.Result{}).RequeueAfter).Should(BeNumerically(">", 0))
+	})
+
+	It("should parse the rebalance progress and compute the percentage", func() {
+		pending, err := parseRebalanceProgress("6\n")
+		Expect(err).Should(Succeed())
+		Expect(pending).Should(Equal(6))
+		_, err = parseRebalanceProgress("garbage")
+		Expect(err).ShouldNot(Succeed())
+
+		p := rebalanceProgress{Subcluster: "sc1"}
+		p.update(8)
+		Expect(p.percent()).Should(Equal(0))
+		p.update(2)
+		Expect(p.ShardsMoved).Should(Equal(6))
+		Expect(p.percent()).Should(Equal(75))
+		Expect(p.String()).Should(Equal("75% complete (6/8 shards active)"))
+	})
 })
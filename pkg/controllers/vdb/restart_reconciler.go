@@ -107,15 +107,9 @@ func (r *RestartReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (ctr
 		return ctrl.Result{}, err
 	}
 
-	// If the create/revive database process fails, we skip restarting the cluster to redo the create/revive process.
-	// restart reconciler is only skipped for VClusterOps.
-	// In Admintools, the IP is cached in admintool.conf and needs to be updated.
-	if r.Vdb.UseVClusterOpsDeployment() {
-		isSet := r.Vdb.IsStatusConditionTrue(vapi.DBInitialized)
-		if !isSet {
-			r.Log.Info("Skipping restart reconciler since create_db or revive_db failed")
-			return ctrl.Result{}, nil
-		}
+	if r.shouldSkipReconcile() {
+		r.Log.Info("Skipping restart reconciler since create_db or revive_db failed")
+		return ctrl.Result{}, nil
 	}
 
 	if err := r.PFacts.Collect(ctx, r.Vdb); err != nil {
@@ -131,11 +125,8 @@ func (r *RestartReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (ctr
 		}
 	}
 	// We have two paths.  If the entire cluster is down we have separate
-	// admin commands to run.  Cluster operations only apply if the entire
-	// vertica cluster is managed by k8s.  We skip that if initPolicy is
-	// ScheduleOnly.
-	if r.PFacts.GetUpNodeAndNotReadOnlyCount() == 0 &&
-		r.Vdb.Spec.InitPolicy != vapi.CommunalInitPolicyScheduleOnly {
+	// admin commands to run.
+	if r.isClusterRestartNeeded() {
 		// We cannot restart the cluster if half or more of the nodes are shutdown
 		if r.PFacts.HasShutdownQuorum() {
 			return ctrl.Result{}, nil
@@ -789,3 +780,53 @@ func (r *RestartReconciler) getReIPPods(isRestartNode bool) []*podfacts.PodFact
 	}
 	return r.PFacts.FindReIPPods(podfacts.DBCheckAny)
 }
+
+// shouldSkipReconcile returns true if the restart must be skipped. If the
+// create/revive database process fails, we skip restarting the cluster to
+// redo the create/revive process. This is only skipped for VClusterOps. In
+// Admintools, the IP is cached in admintool.conf and needs to be updated.
+func (r *RestartReconciler) shouldSkipReconcile() bool {
+	return r.Vdb.UseVClusterOpsDeployment() && !r.Vdb.IsStatusConditionTrue(vapi.DBInitialized)
+}
+
+// isClusterRestartNeeded returns true if the entire cluster is down, which
+// requires a cluster restart rather than restarting individual nodes. Cluster
+// operations only apply if the entire vertica cluster is managed by k8s, so
+// this is never true if initPolicy is ScheduleOnly.
+func (r *RestartReconciler) isClusterRestartNeeded() bool {
+	return r.PFacts.GetUpNodeAndNotReadOnlyCount() == 0 &&
+		r.Vdb.Spec.InitPolicy != vapi.CommunalInitPolicyScheduleOnly
+}
+
+// Plan reports the pods whose vertica process would be restarted
+func (r *RestartReconciler) Plan(ctx context.Context) ([]vapi.PlannedAction, error) {
+	if !r.Vdb.Spec.AutoRestartVertica || r.shouldSkipReconcile() {
+		return nil, nil
+	}
+	if err := r.PFacts.Collect(ctx, r.Vdb); err != nil {
+		return nil, err
+	}
+	scNames, pods := groupPodsBySubcluster(r.PFacts, func(pf *podfacts.PodFact) bool {
+		return pf.GetDBExists() && !pf.GetUpNode() && !pf.GetIsPendingDelete() && !pf.GetShutdown()
+	})
+	if len(scNames) == 0 {
+		return nil, nil
+	}
+	if r.isClusterRestartNeeded() {
+		if r.PFacts.HasShutdownQuorum() {
+			return nil, nil
+		}
+		allPods := []string{}
+		for _, scName := range scNames {
+			allPods = append(allPods, pods[scName]...)
+		}
+		return []vapi.PlannedAction{makePlannedAction("Restart", "", allPods,
+			"Restart the database in subclusters %s", joinNames(scNames))}, nil
+	}
+	actions := []vapi.PlannedAction{}
+	for _, scName := range scNames {
+		actions = append(actions, makePlannedAction("Restart", scName, pods[scName],
+			"Restart %d nodes in subcluster %s", len(pods[scName]), scName))
+	}
+	return actions, nil
+}
@@ -190,7 +190,8 @@ func (r *RollbackAfterCertRotationReconciler) updateTLSConfigInVdb(ctx context.C
 
 // setAutoRotateStatus will set the AutoRotateFailedSecret in status with the failing secret.
 // This is used to indicate that the auto-rotation of TLS secrets has failed
-// and the operator should auto-rotate to the next secret.
+// and the operator should auto-rotate to the next secret, or issue the
+// certificate again when the operator issues them.
 func (r *RollbackAfterCertRotationReconciler) setAutoRotateStatus(ctx context.Context) (ctrl.Result, error) {
 	tlsConfigName := vapi.HTTPSNMATLSConfigName
 	failedSecret := r.Vdb.GetHTTPSNMATLSSecret()
@@ -199,7 +200,7 @@ func (r *RollbackAfterCertRotationReconciler) setAutoRotateStatus(ctx context.Co
 		failedSecret = r.Vdb.GetClientServerTLSSecret()
 	}
 
-	if len(r.Vdb.GetAutoRotateSecrets(tlsConfigName)) == 0 {
+	if len(r.Vdb.GetAutoRotateSecrets(tlsConfigName)) == 0 && !r.Vdb.IsAutoCertIssueEnabled(tlsConfigName) {
 		return ctrl.Result{}, nil
 	}
 
@@ -68,9 +68,7 @@ func MakeSandboxSubclusterReconciler(vdbrecon *VerticaDBReconciler, log logr.Log
 
 // Reconcile will add subclusters to sandboxes if we found any qualified subclusters
 func (s *SandboxSubclusterReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (ctrl.Result, error) {
-	// no-op for ScheduleOnly init policy or enterprise db or no sandboxes
-	if s.Vdb.Spec.InitPolicy == vapi.CommunalInitPolicyScheduleOnly ||
-		!s.Vdb.IsEON() || len(s.Vdb.Spec.Sandboxes) == 0 {
+	if s.shouldSkipReconcile() {
 		return ctrl.Result{}, nil
 	}
 
@@ -372,20 +370,34 @@ func (s *SandboxSubclusterReconciler) updateSandboxConfigMapFields(curCM, newCM
 	return updated
 }
 
+// shouldSkipReconcile returns true if subclusters are never sandboxed. This
+// is the case for the ScheduleOnly init policy, for Enterprise databases and
+// when there are no sandboxes in the vdb.
+func (s *SandboxSubclusterReconciler) shouldSkipReconcile() bool {
+	return s.Vdb.Spec.InitPolicy == vapi.CommunalInitPolicyScheduleOnly ||
+		!s.Vdb.IsEON() || len(s.Vdb.Spec.Sandboxes) == 0
+}
+
+// needsSandbox returns the sandbox that the pod needs to be moved to. It
+// returns false for pods in subclusters that aren't sandboxed in the vdb and
+// for pods that are already in their target sandbox.
+func needsSandbox(pf *podfacts.PodFact, vdbScSbMap map[string]string) (string, bool) {
+	sb, ok := vdbScSbMap[pf.GetSubclusterName()]
+	if !ok || sb == pf.GetSandbox() {
+		return "", false
+	}
+	return sb, true
+}
+
 // fetchSubclustersWithSandboxes will return the qualified subclusters with their sandboxes
 func (s *SandboxSubclusterReconciler) fetchSubclustersWithSandboxes() (map[string]string, bool) {
 	vdbScSbMap := s.Vdb.GenSubclusterSandboxMap()
 	targetScSbMap := make(map[string]string)
 	for _, v := range s.PFacts.Detail {
-		sb, ok := vdbScSbMap[v.GetSubclusterName()]
-		// skip the pod in the subcluster that doesn't need to be sandboxed
+		sb, ok := needsSandbox(v, vdbScSbMap)
 		if !ok {
 			continue
 		}
-		// skip the pod in the subcluster that already in the target sandbox
-		if sb == v.GetSandbox() {
-			continue
-		}
 		// the pod to be added in a sandbox should have a running node
 		if !v.GetUpNode() {
 			return targetScSbMap, false
@@ -508,3 +520,24 @@ func (s *SandboxSubclusterReconciler) waitForSandboxScUp(ctx context.Context, sb
 	}
 	return nil
 }
+
+// Plan reports the subclusters that would be moved to a sandbox
+func (s *SandboxSubclusterReconciler) Plan(ctx context.Context) ([]vapi.PlannedAction, error) {
+	if s.shouldSkipReconcile() {
+		return nil, nil
+	}
+	if err := s.PFacts.Collect(ctx, s.Vdb); err != nil {
+		return nil, err
+	}
+	vdbScSbMap := s.Vdb.GenSubclusterSandboxMap()
+	scNames, pods := groupPodsBySubcluster(s.PFacts, func(pf *podfacts.PodFact) bool {
+		_, ok := needsSandbox(pf, vdbScSbMap)
+		return ok
+	})
+	actions := []vapi.PlannedAction{}
+	for _, scName := range scNames {
+		actions = append(actions, makePlannedAction("SandboxSubcluster", scName, pods[scName],
+			"Move subcluster %s to sandbox %s", scName, vdbScSbMap[scName]))
+	}
+	return actions, nil
+}
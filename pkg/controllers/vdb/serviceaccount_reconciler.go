@@ -74,10 +74,12 @@ func (s *ServiceAccountReconciler) Reconcile(ctx context.Context, _ *ctrl.Reques
 	}
 
 	// There is no need to create the role and rolebinding when the following condition is met:
-	//	- NMA reads certs from mounted volume or non-k8s secret store
+	//	- NMA reads certs from mounted volume or non-k8s secret store. Secrets
+	//	  in Vault or Azure Key Vault are read from their k8s mirror.
 	if s.Vdb.UseVClusterOpsDeployment() &&
 		(vmeta.UseNMACertsMount(s.Vdb.Annotations) ||
-			(!secrets.IsK8sSecret(s.Vdb.GetNMATLSSecret()) && !secrets.IsK8sSecret(s.Vdb.GetClientServerTLSSecret()))) {
+			(!secrets.IsK8sSecret(secrets.ResolveMirroredSecretName(s.Vdb.GetNMATLSSecret())) &&
+				!secrets.IsK8sSecret(secrets.ResolveMirroredSecretName(s.Vdb.GetClientServerTLSSecret())))) {
 		return ctrl.Result{}, s.saveServiceAccountNameInVDB(ctx, sa.Name)
 	}
 
@@ -163,7 +163,8 @@ func (t *TLSConfigManager) updateTLSConfig(ctx context.Context, initiator string
 		secretManager = vops.AWSSecretManagerType
 	default:
 		keyConfig, certConfig, caCertConfig = t.getK8sCertsConfig(cacheDuration)
-		secretName = t.NewSecret
+		// Secrets in Vault or Azure Key Vault are read from their mirror
+		secretName = secrets.ResolveMirroredSecretName(t.NewSecret)
 		secretManager = vops.K8sSecretManagerType
 	}
 	opts := []rotatetlscerts.Option{
@@ -279,9 +280,11 @@ func (t *TLSConfigManager) checkNMATLSConfigMap(ctx context.Context) (ctrl.Resul
 
 	var isUpToDate bool
 	if t.isHTTPSTLSConfig() {
-		isUpToDate = configMap.Data[builder.NMASecretNameEnv] == t.Vdb.GetHTTPSNMATLSSecretForConfigMap()
+		isUpToDate = configMap.Data[builder.NMASecretNameEnv] ==
+			secrets.ResolveMirroredSecretName(t.Vdb.GetHTTPSNMATLSSecretForConfigMap())
 	} else {
-		isUpToDate = configMap.Data[builder.NMAClientSecretNameEnv] == t.Vdb.GetClientServerTLSSecretForConfigMap() &&
+		isUpToDate = configMap.Data[builder.NMAClientSecretNameEnv] ==
+			secrets.ResolveMirroredSecretName(t.Vdb.GetClientServerTLSSecretForConfigMap()) &&
 			configMap.Data[builder.NMAClientSecretTLSModeEnv] == t.Vdb.GetNMAClientServerTLSMode()
 	}
 
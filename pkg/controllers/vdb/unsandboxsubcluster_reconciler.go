@@ -17,6 +17,7 @@ package vdb
 
 import (
 	"context"
+	"sort"
 
 	"github.com/go-logr/logr"
 	"github.com/google/uuid"
@@ -48,8 +49,7 @@ func MakeUnsandboxSubclusterReconciler(vdbrecon *VerticaDBReconciler, log logr.L
 
 // Reconcile will update sandbox config maps for triggering sandbox controller
 func (r *UnsandboxSubclusterReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (ctrl.Result, error) {
-	// no-op for ScheduleOnly init policy or enterprise db
-	if r.Vdb.Spec.InitPolicy == vapi.CommunalInitPolicyScheduleOnly || !r.Vdb.IsEON() {
+	if r.shouldSkipReconcile() {
 		return ctrl.Result{}, nil
 	}
 
@@ -61,6 +61,12 @@ func (r *UnsandboxSubclusterReconciler) Reconcile(ctx context.Context, _ *ctrl.R
 	return ctrl.Result{}, nil
 }
 
+// shouldSkipReconcile returns true if subclusters are never unsandboxed. This
+// is the case for the ScheduleOnly init policy and for Enterprise databases.
+func (r *UnsandboxSubclusterReconciler) shouldSkipReconcile() bool {
+	return r.Vdb.Spec.InitPolicy == vapi.CommunalInitPolicyScheduleOnly || !r.Vdb.IsEON()
+}
+
 // updateSandboxConfigMaps will add a trigger ID to sandbox config maps for triggering sandbox controller
 func (r *UnsandboxSubclusterReconciler) updateSandboxConfigMaps(ctx context.Context) error {
 	unsandboxSbScMap := r.Vdb.GenSandboxSubclusterMapForUnsandbox()
@@ -79,3 +85,26 @@ func (r *UnsandboxSubclusterReconciler) updateSandboxConfigMaps(ctx context.Cont
 
 	return nil
 }
+
+// Plan reports the subclusters that would be moved out of their sandbox
+func (r *UnsandboxSubclusterReconciler) Plan(_ context.Context) ([]vapi.PlannedAction, error) {
+	if r.shouldSkipReconcile() {
+		return nil, nil
+	}
+	unsandboxSbScMap := r.Vdb.GenSandboxSubclusterMapForUnsandbox()
+	sbNames := make([]string, 0, len(unsandboxSbScMap))
+	for sb := range unsandboxSbScMap {
+		sbNames = append(sbNames, sb)
+	}
+	sort.Strings(sbNames)
+	actions := []vapi.PlannedAction{}
+	for _, sb := range sbNames {
+		scNames := unsandboxSbScMap[sb]
+		sort.Strings(scNames)
+		for _, scName := range scNames {
+			actions = append(actions, makePlannedAction("UnsandboxSubcluster", scName, nil,
+				"Move subcluster %s out of sandbox %s", scName, sb))
+		}
+	}
+	return actions, nil
+}
@@ -1039,3 +1039,15 @@ func (i *UpgradeManager) getSubclusterNameFromSts(ctx context.Context, stsName s
 	}
 	return scNameFromLabel, nil
 }
+
+// planUpgrade returns the planned action for an upgrade if the upgrade
+// manager would start or continue one in the given sandbox. It uses the same
+// check that the upgrade reconcilers use before they do anything.
+func planUpgrade(ctx context.Context, mgr *UpgradeManager, sandbox, reconciler, upgradeType string) ([]vapi.PlannedAction, error) {
+	ok, err := mgr.IsUpgradeNeeded(ctx, sandbox)
+	if !ok || err != nil {
+		return nil, err
+	}
+	return []vapi.PlannedAction{makePlannedAction(reconciler, "", nil,
+		"Run an %s upgrade to image %s", upgradeType, mgr.Vdb.Spec.Image)}, nil
+}
@@ -43,6 +43,7 @@ import (
 
 	"github.com/google/uuid"
 	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/api/v1beta1"
 	"github.com/vertica/vertica-kubernetes/pkg/cache"
 	"github.com/vertica/vertica-kubernetes/pkg/cloud"
 	"github.com/vertica/vertica-kubernetes/pkg/cmds"
@@ -55,6 +56,7 @@ import (
 	"github.com/vertica/vertica-kubernetes/pkg/metrics"
 	"github.com/vertica/vertica-kubernetes/pkg/podfacts"
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin"
+	"github.com/vertica/vertica-kubernetes/pkg/vdbstatus"
 	"github.com/vertica/vertica-kubernetes/pkg/vk8s"
 )
 
@@ -84,11 +86,12 @@ type VerticaDBReconciler struct {
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;update;delete;patch
 // +kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
 // +kubebuilder:rbac:groups="",resources=pods/status,verbs=update
-// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;update;delete;create
 // +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;update
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups="monitoring.coreos.com",resources=servicemonitors,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;create;delete
 
 // SetupWithManager sets up the controller with the Manager.
 //
@@ -113,6 +116,18 @@ func (r *VerticaDBReconciler) SetupWithManager(mgr ctrl.Manager, options control
 			handler.EnqueueRequestsFromMapFunc(r.findObjectsForSecretOrConfigMap),
 			builder.WithPredicates(r.predicateFuncs(), predicate.ResourceVersionChangedPredicate{}),
 		).
+		// The users and roles can be granted a TLS authentication method
+		// through a pattern in spec.clientTLSAuth
+		Watches(
+			&v1beta1.VerticaUser{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForUserOrRole),
+			builder.WithPredicates(userOrRolePredicateFuncs(v1beta1.UserReady)),
+		).
+		Watches(
+			&v1beta1.VerticaRole{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForUserOrRole),
+			builder.WithPredicates(userOrRolePredicateFuncs(v1beta1.RoleReady)),
+		).
 		WithEventFilter(predicate.NewPredicateFuncs(func(obj client.Object) bool {
 			if r.Namespace == "" {
 				return true
@@ -192,6 +207,15 @@ func (r *VerticaDBReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	r.InitCertCacheForVdb(vdb)
 	// Iterate over each actor
 	actors := r.constructActors(log, vdb, prunner, &pfacts, dispatcher)
+	if vmeta.IsPlanAnnotationSet(vdb.Annotations) {
+		log.Info(fmt.Sprintf("The plan annotation %s is set. Computing the plan without running it", vmeta.PlanAnnotation))
+		return r.reconcilePlan(ctx, log, vdb, actors)
+	}
+	if vdb.Status.Plan != nil {
+		if err := vdbstatus.SetPlan(ctx, r.Client, vdb, nil); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
 	for _, act := range actors {
 		log.Info("starting actor", "name", fmt.Sprintf("%T", act))
 		res, err = act.Reconcile(ctx, &req)
@@ -205,15 +229,40 @@ func (r *VerticaDBReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 				res.Requeue = false
 				res.RequeueAfter = time.Second * time.Duration(vdb.GetRequeueTime())
 			}
+			// An actor that waits for a future time, such as the next
+			// certificate rotation, must not delay the periodic tasks.
+			if err == nil && !res.Requeue && res.RequeueAfter > 0 {
+				res = scheduleNextPeriodicTask(vdb, res)
+			}
 			log.Info("aborting reconcile of VerticaDB", "result", res, "err", err)
 			return res, err
 		}
 	}
+	res = scheduleNextPeriodicTask(vdb, res)
 	r.CleanCacheForVdb(vdb)
 	log.Info("ending reconcile of VerticaDB", "result", res, "err", err)
 	return res, err
 }
 
+// scheduleNextPeriodicTask returns the result with RequeueAfter set to the
+// time of the next periodic task, if it is sooner. The health watchdog is
+// checked for runaway queries, and the superuser password is rotated, on an
+// interval. A rebalance that timed out is retried once its backoff is over.
+// The TLS certificates are checked again when one of them crosses an expiry
+// warning threshold.
+// They don't requeue themselves so that they don't stop the actors that
+// follow them.
+func scheduleNextPeriodicTask(vdb *vapi.VerticaDB, res ctrl.Result) ctrl.Result {
+	now := time.Now()
+	for _, next := range []time.Duration{vdb.GetHealthWatchdogNextCheckIn(now), vdb.GetPasswordRotationNextIn(now),
+		vdb.GetRebalanceShardsRetryIn(now), vdb.GetCertExpiryNextCheckIn(now)} {
+		if next > 0 && (res.RequeueAfter == 0 || next < res.RequeueAfter) {
+			res.RequeueAfter = next
+		}
+	}
+	return res
+}
+
 // constructActors will a list of actors that should be run for the reconcile.
 // Order matters in that some actors depend on the successeful execution of
 // earlier ones.
@@ -236,10 +285,15 @@ func (r *VerticaDBReconciler) constructActors(log logr.Logger, vdb *vapi.Vertica
 		MakeObjReconciler(r, log, vdb, pfacts, ObjReconcileModeAnnotation),
 		// Validate the vdb after operator upgraded
 		MakeValidateVDBReconciler(r, log, vdb),
+		// Copy the license and TLS secrets stored in Vault or Azure Key Vault
+		// to Kubernetes secrets that the pods, Vertica and NMA can read
+		MakeExternalSecretMirrorReconciler(r, log, vdb),
 		// Initialize TLS secret if autoRotation is set
 		MakeAutoCertRotateReconciler(r, log, vdb, true /* init */),
 		// Always generate cert first if nothing is provided
 		MakeTLSServerCertGenReconciler(r, log, vdb),
+		// Report how long the certificates in the TLS secrets are valid for
+		MakeCertExpiryReconciler(r, log, vdb),
 		// Set up configmap which stores env variables for NMA container
 		MakeNMACertConfigMapReconciler(r, log, vdb),
 		// Trigger sandbox upgrade when the image field for the sandbox
@@ -278,6 +332,10 @@ func (r *VerticaDBReconciler) constructActors(log logr.Logger, vdb *vapi.Vertica
 		MakeObservedConfigObjsReconciler(r, log, vdb),
 		// Add annotations/labels to each pod about the host running them
 		MakeAnnotateAndLabelPodReconciler(r, log, vdb, pfacts),
+		// Complete a superuser password rotation that was interrupted. This
+		// is done early so that the actors that follow use the password in
+		// effect in the database.
+		MakePasswordRotationReconciler(r, log, vdb, prunner, pfacts, true /* recoverOnly */),
 		// Set up TLS config if users turn it on
 		MakeTLSReconciler(r, log, vdb, prunner, dispatcher, pfacts),
 		// Update the service monitor that will allow prometheus to scrape the
@@ -303,6 +361,9 @@ func (r *VerticaDBReconciler) constructActors(log logr.Logger, vdb *vapi.Vertica
 		MakeRestartReconciler(r, log, vdb, prunner, pfacts, true, dispatcher),
 		// Check the password secret and update it if needed
 		MakePasswordSecretReconciler(r, log, vdb, prunner, pfacts, dispatcher),
+		// Rotate the superuser password if spec.passwordRotation is set and
+		// the rotation is due
+		MakePasswordRotationReconciler(r, log, vdb, prunner, pfacts, false /* recoverOnly */),
 		MakeMetricReconciler(r, log, vdb, prunner, pfacts),
 		MakeStatusReconcilerWithShutdown(r.Client, r.Scheme, log, vdb, pfacts),
 		// Ensure we add labels to any pod rescheduled so that Service objects route traffic to it.
@@ -340,6 +401,13 @@ func (r *VerticaDBReconciler) constructActors(log logr.Logger, vdb *vapi.Vertica
 		// reconcile tls version and cipher suite
 		MakeDBTLSConfigReconciler(r, log, vdb, prunner, dispatcher, pfacts),
 		MakeTLSReconciler(r, log, vdb, prunner, dispatcher, pfacts),
+		// Set the configuration parameters from the spec in the database
+		MakeConfigParametersReconciler(r, log, vdb, prunner, dispatcher, pfacts),
+		// Configure the health watchdog and cancel the runaway queries it finds
+		MakeHealthWatchdogReconciler(r, log, vdb, dispatcher, pfacts),
+		// Keep the TLS authentication methods for database users in sync
+		// with spec.clientTLSAuth
+		MakeClientTLSAuthReconciler(r, log, vdb, dispatcher, pfacts),
 		// Update the service monitor that will allow prometheus to scrape the
 		// metrics from the vertica pods.
 		MakeServiceMonitorReconciler(vdb, r, log, pfacts),
@@ -432,6 +500,56 @@ func (r *VerticaDBReconciler) findObjectsForSecretOrConfigMap(_ context.Context,
 	return requests
 }
 
+// findObjectsForUserOrRole returns the VerticaDB of a VerticaUser or
+// VerticaRole, if that VerticaDB has client tls authentication
+func (r *VerticaDBReconciler) findObjectsForUserOrRole(ctx context.Context, obj client.Object) []reconcile.Request {
+	var vdbName string
+	switch o := obj.(type) {
+	case *v1beta1.VerticaUser:
+		vdbName = o.Spec.VerticaDBName
+	case *v1beta1.VerticaRole:
+		vdbName = o.Spec.VerticaDBName
+	default:
+		return []reconcile.Request{}
+	}
+	nm := types.NamespacedName{Namespace: obj.GetNamespace(), Name: vdbName}
+	vdb := &vapi.VerticaDB{}
+	if err := r.Get(ctx, nm, vdb); err != nil {
+		return []reconcile.Request{}
+	}
+	if vdb.Spec.ClientTLSAuth == nil {
+		return []reconcile.Request{}
+	}
+	return []reconcile.Request{{NamespacedName: nm}}
+}
+
+// userOrRolePredicateFuncs only lets through the VerticaUser or VerticaRole
+// events that can change who is matched by a pattern: a create, a delete, a
+// spec change or a change of the ready condition.
+func userOrRolePredicateFuncs(readyCondition string) predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() {
+				return true
+			}
+			return isReadyConditionTrue(e.ObjectOld, readyCondition) !=
+				isReadyConditionTrue(e.ObjectNew, readyCondition)
+		},
+	}
+}
+
+// isReadyConditionTrue returns true if the ready condition of a VerticaUser
+// or VerticaRole is true
+func isReadyConditionTrue(obj client.Object, readyCondition string) bool {
+	switch o := obj.(type) {
+	case *v1beta1.VerticaUser:
+		return o.IsStatusConditionTrue(readyCondition)
+	case *v1beta1.VerticaRole:
+		return o.IsStatusConditionTrue(readyCondition)
+	}
+	return false
+}
+
 func (r *VerticaDBReconciler) predicateFuncs() predicate.Funcs {
 	return predicate.Funcs{
 		CreateFunc: func(e event.CreateEvent) bool {
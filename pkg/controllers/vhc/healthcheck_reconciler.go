@@ -0,0 +1,283 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vhc
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	v1beta1 "github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/cloud"
+	"github.com/vertica/vertica-kubernetes/pkg/cmds"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	verrors "github.com/vertica/vertica-kubernetes/pkg/errors"
+	"github.com/vertica/vertica-kubernetes/pkg/events"
+	"github.com/vertica/vertica-kubernetes/pkg/metrics"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/podfacts"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/clusterhealth"
+	"github.com/vertica/vertica-kubernetes/pkg/vk8s"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	stateScheduled = "Scheduled"
+	stateSuspended = "Suspended"
+	stateChecking  = "Checking"
+	stateHealthy   = "Healthy"
+	stateUnhealthy = "Unhealthy"
+	stateFailed    = "Check failed"
+)
+
+type HealthCheckReconciler struct {
+	VRec *VerticaHealthCheckReconciler
+	Vhc  *v1beta1.VerticaHealthCheck
+	Log  logr.Logger
+	Vdb  *vapi.VerticaDB
+}
+
+func MakeHealthCheckReconciler(r *VerticaHealthCheckReconciler, vhc *v1beta1.VerticaHealthCheck,
+	log logr.Logger) controllers.ReconcileActor {
+	return &HealthCheckReconciler{
+		VRec: r,
+		Vhc:  vhc,
+		Log:  log.WithName("HealthCheckReconciler"),
+	}
+}
+
+// Reconcile will run the health analyses if the interval has elapsed since
+// the last check. Otherwise, it requeues the vhc so that it is reconciled
+// again when the next check is due.
+func (h *HealthCheckReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (ctrl.Result, error) {
+	// no-op if the VerticaDB hasn't been verified yet
+	if !h.Vhc.IsStatusConditionTrue(v1beta1.HealthCheckReady) {
+		return ctrl.Result{}, nil
+	}
+
+	if h.Vhc.Spec.Suspend {
+		return ctrl.Result{}, h.updateNextCheckTime(ctx, stateSuspended, time.Time{})
+	}
+
+	now := time.Now().UTC()
+	next := h.getNextCheckTime()
+	if now.Before(next) {
+		return ctrl.Result{RequeueAfter: next.Sub(now)}, h.updateNextCheckTime(ctx, "", next)
+	}
+
+	if res, err := h.fetchVdb(ctx); verrors.IsReconcileAborted(res, err) {
+		return res, err
+	}
+	hostIP, res, err := h.findInitiatorIP(ctx)
+	if verrors.IsReconcileAborted(res, err) {
+		return res, err
+	}
+	dispatcher, err := h.makeDispatcher(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := h.runHealthCheck(ctx, dispatcher, hostIP, h.getCheckStartTime(now), now); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	next = now.Add(h.Vhc.GetInterval())
+	return ctrl.Result{RequeueAfter: next.Sub(now)}, h.updateNextCheckTime(ctx, "", next)
+}
+
+// getNextCheckTime returns the time the next health check is due. The first
+// check is run right away.
+func (h *HealthCheckReconciler) getNextCheckTime() time.Time {
+	if h.Vhc.Status.LastCheckTime == nil {
+		return time.Time{}
+	}
+	return h.Vhc.Status.LastCheckTime.Time.UTC().Add(h.Vhc.GetInterval())
+}
+
+// getCheckStartTime returns the start of the time range to analyze. It picks
+// up where the last check ended so that no event is missed or counted twice.
+// The range never goes back more than one interval, so a check that resumes
+// after a long suspension doesn't report stale findings.
+func (h *HealthCheckReconciler) getCheckStartTime(now time.Time) time.Time {
+	earliest := now.Add(-h.Vhc.GetInterval())
+	if h.Vhc.Status.LastCheckTime == nil || h.Vhc.Status.LastCheckTime.Time.Before(earliest) {
+		return earliest
+	}
+	return h.Vhc.Status.LastCheckTime.Time.UTC()
+}
+
+// fetchVdb will fetch the VerticaDB referenced by the vhc
+func (h *HealthCheckReconciler) fetchVdb(ctx context.Context) (ctrl.Result, error) {
+	vdb := &vapi.VerticaDB{}
+	nm := names.GenNamespacedName(h.Vhc, h.Vhc.Spec.VerticaDBName)
+	if res, err := vk8s.FetchVDB(ctx, h.VRec, h.Vhc, nm, vdb); verrors.IsReconcileAborted(res, err) {
+		return res, err
+	}
+	h.Vdb = vdb
+	return ctrl.Result{}, nil
+}
+
+// findInitiatorIP returns the IP of an up pod in the main cluster that we can
+// run the vclusterops API from. It requeues if there is no such pod.
+func (h *HealthCheckReconciler) findInitiatorIP(ctx context.Context) (string, ctrl.Result, error) {
+	username := h.Vdb.GetVerticaUser()
+	password, err := vk8s.GetSuperuserPassword(ctx, h.VRec.Client, h.Log, h.VRec, h.Vdb)
+	if err != nil {
+		return "", ctrl.Result{}, err
+	}
+	prunner := cmds.MakeClusterPodRunner(h.Log, h.VRec.Cfg, username, password, h.Vdb.IsClientServerTLSAuthEnabled())
+	pfacts := podfacts.MakePodFactsForSandboxWithCacheManager(h.VRec, prunner, h.Log, password,
+		vapi.MainCluster, h.VRec.CacheManager)
+	if err := pfacts.Collect(ctx, h.Vdb); err != nil {
+		return "", ctrl.Result{}, err
+	}
+	hostIP, ok := pfacts.FindFirstUpPodIP(false, "")
+	if !ok {
+		h.Log.Info("No up pod found to run the health check. Requeuing.")
+		return "", ctrl.Result{Requeue: true}, nil
+	}
+	return hostIP, ctrl.Result{}, nil
+}
+
+// makeDispatcher will create a Dispatcher object for the vclusterops API
+func (h *HealthCheckReconciler) makeDispatcher(ctx context.Context) (vadmin.Dispatcher, error) {
+	password, err := vk8s.GetSuperuserPassword(ctx, h.VRec.Client, h.Log, h.VRec, h.Vdb)
+	if err != nil {
+		return nil, err
+	}
+	fetcher := &cloud.SecretFetcher{
+		Client:   h.VRec.Client,
+		Log:      h.Log,
+		Obj:      h.Vdb,
+		EVWriter: h.VRec,
+	}
+	h.VRec.CacheManager.InitCertCacheForVdb(h.Vdb, fetcher)
+	return vadmin.MakeVClusterOps(h.Log, h.Vdb, h.VRec.GetClient(), password,
+		h.VRec, vadmin.SetupVClusterOps, h.VRec.CacheManager), nil
+}
+
+// runHealthCheck will analyze the events between start and end, and publish
+// the findings in the status, as events and as metrics. A failure to run the
+// analyses is recorded in the status and is not returned as an error; the
+// next attempt happens after the interval.
+func (h *HealthCheckReconciler) runHealthCheck(ctx context.Context, dispatcher vadmin.Dispatcher,
+	hostIP string, start, end time.Time) error {
+	err := vk8s.UpdateStatus(ctx, h.VRec.Client, h.Log, h.Vhc, func(vhc *v1beta1.VerticaHealthCheck) error {
+		vhc.Status.State = stateChecking
+		vhc.Status.LastCheckTime = &metav1.Time{Time: end}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	report, errRun := dispatcher.ClusterHealth(ctx,
+		clusterhealth.WithInitiator(hostIP),
+		clusterhealth.WithTimeRange(start, end),
+		clusterhealth.WithLockWaitThreshold(h.Vhc.GetLockWaitThreshold()),
+		clusterhealth.WithSlowEventThreshold(h.Vhc.GetSlowEventThreshold()),
+	)
+	if errRun != nil {
+		h.VRec.Eventf(h.Vhc, corev1.EventTypeWarning, events.HealthCheckFailed,
+			"Failed to check the health of VerticaDB %q: %s", h.Vhc.Spec.VerticaDBName, errRun)
+		metrics.HealthCheckFailed.With(metrics.MakeVDBLabels(h.Vdb)).Inc()
+		return vk8s.UpdateStatus(ctx, h.VRec.Client, h.Log, h.Vhc, func(vhc *v1beta1.VerticaHealthCheck) error {
+			vhc.Status.State = stateFailed
+			cond := vapi.MakeCondition(v1beta1.Healthy, metav1.ConditionFalse, "CheckFailed")
+			cond.Message = errRun.Error()
+			meta.SetStatusCondition(&vhc.Status.Conditions, *cond)
+			return nil
+		})
+	}
+
+	summary := summarizeReport(report, h.Vhc.GetLockWaitThreshold(), h.Vhc.GetSlowEventThreshold())
+	h.emitEvents(summary)
+	h.setMetrics(summary)
+	return vk8s.UpdateStatus(ctx, h.VRec.Client, h.Log, h.Vhc, func(vhc *v1beta1.VerticaHealthCheck) error {
+		vhc.Status.LockWaitEvents = summary.lockWaitEvents
+		vhc.Status.MaxLockWaitMilliseconds = summary.maxLockWait.Milliseconds()
+		vhc.Status.SlowEvents = summary.slowEvents
+		vhc.Status.MaxSlowEventMilliseconds = summary.maxSlowEvent.Milliseconds()
+		vhc.Status.MissingLockReleases = summary.missingReleases
+		vhc.Status.Findings = summary.findings
+		if summary.isHealthy() {
+			vhc.Status.State = stateHealthy
+			meta.SetStatusCondition(&vhc.Status.Conditions,
+				*vapi.MakeCondition(v1beta1.Healthy, metav1.ConditionTrue, "NoFindings"))
+		} else {
+			vhc.Status.State = stateUnhealthy
+			cond := vapi.MakeCondition(v1beta1.Healthy, metav1.ConditionFalse, "FindingsOverThreshold")
+			cond.Message = summary.String()
+			meta.SetStatusCondition(&vhc.Status.Conditions, *cond)
+		}
+		return nil
+	})
+}
+
+// emitEvents will write one event for each kind of finding. An event is
+// also written when the database becomes healthy again.
+func (h *HealthCheckReconciler) emitEvents(summary *healthSummary) {
+	if summary.lockWaitEvents > 0 {
+		h.VRec.Eventf(h.Vhc, corev1.EventTypeWarning, events.HealthCheckLockWait,
+			"Found %d lock wait events longer than %s. The longest series lasted %s",
+			summary.lockWaitEvents, h.Vhc.GetLockWaitThreshold(), summary.maxLockWait)
+	}
+	if summary.slowEvents > 0 {
+		h.VRec.Eventf(h.Vhc, corev1.EventTypeWarning, events.HealthCheckSlowEvent,
+			"Found %d mutex events slower than %s. The slowest took %s",
+			summary.slowEvents, h.Vhc.GetSlowEventThreshold(), summary.maxSlowEvent)
+	}
+	if summary.missingReleases > 0 {
+		h.VRec.Eventf(h.Vhc, corev1.EventTypeWarning, events.HealthCheckMissingRelease,
+			"Found %d locks that were never released", summary.missingReleases)
+	}
+	if summary.isHealthy() && h.Vhc.IsStatusConditionFalse(v1beta1.Healthy) {
+		h.VRec.Eventf(h.Vhc, corev1.EventTypeNormal, events.HealthCheckRecovered,
+			"No findings over the thresholds for VerticaDB %q", h.Vhc.Spec.VerticaDBName)
+	}
+}
+
+// setMetrics will export the summary of the last check
+func (h *HealthCheckReconciler) setMetrics(summary *healthSummary) {
+	labels := metrics.MakeVDBLabels(h.Vdb)
+	metrics.HealthCheckLockWaitEvents.With(labels).Set(float64(summary.lockWaitEvents))
+	metrics.HealthCheckMaxLockWait.With(labels).Set(summary.maxLockWait.Seconds())
+	metrics.HealthCheckSlowEvents.With(labels).Set(float64(summary.slowEvents))
+	metrics.HealthCheckMaxSlowEvent.With(labels).Set(summary.maxSlowEvent.Seconds())
+	metrics.HealthCheckMissingLockReleases.With(labels).Set(float64(summary.missingReleases))
+}
+
+// updateNextCheckTime sets the next check time. A zero time clears it from
+// the status. The state is only changed if one is given.
+func (h *HealthCheckReconciler) updateNextCheckTime(ctx context.Context, state string, next time.Time) error {
+	return vk8s.UpdateStatus(ctx, h.VRec.Client, h.Log, h.Vhc, func(vhc *v1beta1.VerticaHealthCheck) error {
+		if state != "" {
+			vhc.Status.State = state
+		} else if vhc.Status.State == stateSuspended || vhc.Status.State == "" {
+			vhc.Status.State = stateScheduled
+		}
+		if next.IsZero() {
+			vhc.Status.NextCheckTime = nil
+		} else {
+			vhc.Status.NextCheckTime = &metav1.Time{Time: next}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,124 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vhc
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	v1beta1 "github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/mockvops"
+	"github.com/vertica/vertica-kubernetes/pkg/test"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin"
+	"github.com/vertica/vertica-kubernetes/pkg/vk8s"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("healthcheck_reconcile", func() {
+	ctx := context.Background()
+
+	It("should be a no-op if the health check isn't ready", func() {
+		vhc := v1beta1.MakeVhc()
+		Expect(k8sClient.Create(ctx, vhc)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vhc)).Should(Succeed()) }()
+
+		recon := MakeHealthCheckReconciler(vhcRec, vhc, logger)
+		Expect(recon.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(vhc.Status.NextCheckTime).Should(BeNil())
+	})
+
+	It("should requeue until the next check is due", func() {
+		vhc := v1beta1.MakeVhc()
+		Expect(k8sClient.Create(ctx, vhc)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vhc)).Should(Succeed()) }()
+		setHealthCheckReady(ctx, vhc)
+		Expect(vk8s.UpdateStatus(ctx, k8sClient, logger, vhc, func(v *v1beta1.VerticaHealthCheck) error {
+			v.Status.LastCheckTime = &metav1.Time{Time: time.Now().UTC()}
+			return nil
+		})).Should(Succeed())
+
+		recon := MakeHealthCheckReconciler(vhcRec, vhc, logger)
+		res, err := recon.Reconcile(ctx, &ctrl.Request{})
+		Expect(err).Should(Succeed())
+		Expect(res.RequeueAfter).Should(BeNumerically(">", 0))
+		Expect(res.RequeueAfter).Should(BeNumerically("<=", vhc.GetInterval()))
+		Expect(vhc.Status.State).Should(Equal(stateScheduled))
+		Expect(vhc.Status.NextCheckTime).ShouldNot(BeNil())
+	})
+
+	It("should clear the next check time when suspended", func() {
+		vhc := v1beta1.MakeVhc()
+		vhc.Spec.Suspend = true
+		Expect(k8sClient.Create(ctx, vhc)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vhc)).Should(Succeed()) }()
+		setHealthCheckReady(ctx, vhc)
+
+		recon := MakeHealthCheckReconciler(vhcRec, vhc, logger)
+		Expect(recon.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(vhc.Status.State).Should(Equal(stateSuspended))
+		Expect(vhc.Status.NextCheckTime).Should(BeNil())
+	})
+
+	It("should analyze from the last check but no further back than one interval", func() {
+		vhc := v1beta1.MakeVhc()
+		r := &HealthCheckReconciler{Vhc: vhc}
+		now := time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC)
+		Expect(r.getNextCheckTime().IsZero()).Should(BeTrue())
+		Expect(r.getCheckStartTime(now)).Should(Equal(now.Add(-5 * time.Minute)))
+
+		vhc.Status.LastCheckTime = &metav1.Time{Time: now.Add(-2 * time.Minute)}
+		Expect(r.getCheckStartTime(now)).Should(Equal(now.Add(-2 * time.Minute)))
+		Expect(r.getNextCheckTime()).Should(Equal(now.Add(3 * time.Minute)))
+
+		vhc.Status.LastCheckTime = &metav1.Time{Time: now.Add(-time.Hour)}
+		Expect(r.getCheckStartTime(now)).Should(Equal(now.Add(-5 * time.Minute)))
+	})
+
+	It("should record a healthy outcome when there are no findings", func() {
+		vdb := vapi.MakeVDB()
+		secretName := "tls-1"
+		vdb.Spec.HTTPSNMATLS.Secret = secretName
+		setupAPIFunc := func(logr.Logger, string) (vadmin.VClusterProvider, logr.Logger) {
+			return &mockvops.MockVClusterOps{}, logr.Logger{}
+		}
+		dispatcher := mockvops.MakeMockVClusterOpsDispatcher(vdb, logger, k8sClient, setupAPIFunc)
+		test.CreateFakeTLSSecret(ctx, dispatcher.VDB, k8sClient, secretName)
+		defer test.DeleteSecret(ctx, k8sClient, secretName)
+
+		vhc := v1beta1.MakeVhc()
+		Expect(k8sClient.Create(ctx, vhc)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vhc)).Should(Succeed()) }()
+
+		r := &HealthCheckReconciler{VRec: vhcRec, Vhc: vhc, Log: logger, Vdb: vdb}
+		end := time.Now().UTC()
+		Expect(r.runHealthCheck(ctx, dispatcher, "10.10.10.10", end.Add(-time.Minute), end)).Should(Succeed())
+		Expect(vhc.Status.LastCheckTime).ShouldNot(BeNil())
+		Expect(vhc.Status.State).Should(Equal(stateHealthy))
+		Expect(vhc.IsStatusConditionTrue(v1beta1.Healthy)).Should(BeTrue())
+		Expect(vhc.Status.Findings).Should(BeEmpty())
+	})
+})
+
+func setHealthCheckReady(ctx context.Context, vhc *v1beta1.VerticaHealthCheck) {
+	Expect(vk8s.UpdateConditions(ctx, k8sClient, logger, vhc,
+		[]*metav1.Condition{vapi.MakeCondition(v1beta1.HealthCheckReady, metav1.ConditionTrue, "Verified")},
+		stateScheduled)).Should(Succeed())
+}
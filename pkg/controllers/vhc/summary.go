@@ -0,0 +1,174 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vhc
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	v1beta1 "github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin"
+)
+
+// maxFindings is the number of findings kept in the status. Only the
+// longest ones are kept so that the status stays small during a lock storm.
+const maxFindings = 10
+
+// healthSummary is the outcome of one health check after the thresholds of
+// the vhc were applied
+type healthSummary struct {
+	lockWaitEvents  int
+	maxLockWait     time.Duration
+	slowEvents      int
+	maxSlowEvent    time.Duration
+	missingReleases int
+	findings        []v1beta1.VerticaHealthCheckFinding
+}
+
+// isHealthy returns true if nothing over the thresholds was found
+func (s *healthSummary) isHealthy() bool {
+	return s.lockWaitEvents == 0 && s.slowEvents == 0 && s.missingReleases == 0
+}
+
+// String is used for the message of the Healthy condition
+func (s *healthSummary) String() string {
+	return fmt.Sprintf("%d lock wait events, %d slow events, %d missing lock releases",
+		s.lockWaitEvents, s.slowEvents, s.missingReleases)
+}
+
+// summarizeReport will count the findings of the report that are over the
+// thresholds. vclusterops already filters with the same thresholds, except
+// for the lock wait series which it only reports past a fixed length. We
+// apply both thresholds again so the summary is consistent with the spec.
+func summarizeReport(report *vadmin.ClusterHealthReport, lockWaitThreshold,
+	slowEventThreshold time.Duration) *healthSummary {
+	summary := &healthSummary{}
+	if report == nil {
+		return summary
+	}
+
+	for i := range report.LockWaits {
+		lw := &report.LockWaits[i]
+		secs, err := strconv.ParseFloat(lw.MaxDuration, 64)
+		if err != nil {
+			continue
+		}
+		dur := time.Duration(secs * float64(time.Second))
+		if dur < lockWaitThreshold {
+			continue
+		}
+		summary.lockWaitEvents += lw.TotalWaitEvents
+		summary.maxLockWait = max(summary.maxLockWait, dur)
+		finding := v1beta1.VerticaHealthCheckFinding{
+			Type:                 v1beta1.HealthFindingLockWait,
+			NodeName:             lw.NodeName,
+			Time:                 lw.WaitStartTime,
+			DurationMilliseconds: dur.Milliseconds(),
+		}
+		if lw.LockWaitEvents != nil && len(*lw.LockWaitEvents) > 0 {
+			first := &(*lw.LockWaitEvents)[0]
+			finding.Description = describeLock(first.Mode, first.ObjectName, first.Description)
+			finding.SessionID = first.SessionID
+			finding.TransactionID = first.TxnID
+		}
+		summary.findings = append(summary.findings, finding)
+	}
+
+	for i := range report.SlowEvents {
+		ev := report.SlowEvents[i].Event
+		if ev == nil {
+			continue
+		}
+		us, err := strconv.ParseInt(ev.DurationUs, 10, 64)
+		if err != nil {
+			continue
+		}
+		dur := time.Duration(us) * time.Microsecond
+		if dur < slowEventThreshold {
+			continue
+		}
+		summary.slowEvents++
+		summary.maxSlowEvent = max(summary.maxSlowEvent, dur)
+		summary.findings = append(summary.findings, v1beta1.VerticaHealthCheckFinding{
+			Type:                 v1beta1.HealthFindingSlowEvent,
+			NodeName:             ev.NodeName,
+			Time:                 ev.Time,
+			DurationMilliseconds: dur.Milliseconds(),
+			Description:          ev.EventDescription,
+			SessionID:            ev.SessionID,
+			TransactionID:        ev.TxnID,
+		})
+	}
+
+	for i := range report.MissingReleases {
+		mr := &report.MissingReleases[i]
+		summary.missingReleases++
+		dur, _ := parseIntervalDuration(mr.Duration)
+		summary.findings = append(summary.findings, v1beta1.VerticaHealthCheckFinding{
+			Type:                 v1beta1.HealthFindingMissingRelease,
+			NodeName:             mr.NodeName,
+			Time:                 mr.StartTime,
+			DurationMilliseconds: dur.Milliseconds(),
+			Description:          describeLock(mr.Mode, mr.ObjectName, mr.Description),
+			SessionID:            mr.SessionID,
+			TransactionID:        mr.TxnID,
+		})
+	}
+
+	sort.SliceStable(summary.findings, func(i, j int) bool {
+		return summary.findings[i].DurationMilliseconds > summary.findings[j].DurationMilliseconds
+	})
+	if len(summary.findings) > maxFindings {
+		summary.findings = summary.findings[:maxFindings]
+	}
+	return summary
+}
+
+// describeLock returns a short description of a lock, such as "X lock on
+// public.t1"
+func describeLock(mode, objectName, desc string) string {
+	if mode == "" || objectName == "" {
+		return desc
+	}
+	return fmt.Sprintf("%s lock on %s", mode, objectName)
+}
+
+// parseIntervalDuration parses a duration in the HH:MM:SS[.ffffff] format
+// that the data collector tables use
+func parseIntervalDuration(s string) (time.Duration, error) {
+	const numParts = 3
+	parts := strings.Split(s, ":")
+	if len(parts) != numParts {
+		return 0, fmt.Errorf("invalid interval %q, expected HH:MM:SS", s)
+	}
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid hours in interval %q: %w", s, err)
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid minutes in interval %q: %w", s, err)
+	}
+	secs, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid seconds in interval %q: %w", s, err)
+	}
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute +
+		time.Duration(secs*float64(time.Second)), nil
+}
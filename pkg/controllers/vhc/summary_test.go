@@ -0,0 +1,85 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vhc
+
+import (
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	vops "github.com/vertica/vcluster/vclusterops"
+	v1beta1 "github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin"
+)
+
+var _ = Describe("summary", func() {
+	It("should only count findings over the thresholds", func() {
+		var slowEvents []vops.SlowEventNode
+		Expect(json.Unmarshal([]byte(`[
+			{"depth": 0, "slow_event": {"node_name": "v_vertdb_node0001", "duration_us": "3500000",
+				"event_description": "GlobalCatalogLock"}},
+			{"depth": 1, "slow_event": {"node_name": "v_vertdb_node0002", "duration_us": "200000"}}
+		]`), &slowEvents)).Should(Succeed())
+		report := &vadmin.ClusterHealthReport{
+			SlowEvents: slowEvents,
+			LockWaits: []vops.NodeLockEvents{
+				{NodeName: "v_vertdb_node0001", MaxDuration: "12.5000", TotalWaitEvents: 4,
+					LockWaitEvents: &[]vops.DcLockAttempts{{Mode: "X", ObjectName: "public.t1"}}},
+				{NodeName: "v_vertdb_node0002", MaxDuration: "2.0000", TotalWaitEvents: 7},
+			},
+			MissingReleases: []vops.DcLockAttempts{
+				{NodeName: "v_vertdb_node0003", Duration: "00:00:20.5", Mode: "S", ObjectName: "public.t2"},
+			},
+		}
+
+		summary := summarizeReport(report, 5*time.Second, time.Second)
+		Expect(summary.isHealthy()).Should(BeFalse())
+		Expect(summary.lockWaitEvents).Should(Equal(4))
+		Expect(summary.maxLockWait).Should(Equal(12500 * time.Millisecond))
+		Expect(summary.slowEvents).Should(Equal(1))
+		Expect(summary.maxSlowEvent).Should(Equal(3500 * time.Millisecond))
+		Expect(summary.missingReleases).Should(Equal(1))
+		Expect(summary.findings).Should(HaveLen(3))
+		Expect(summary.findings[0].Type).Should(Equal(v1beta1.HealthFindingMissingRelease))
+		Expect(summary.findings[0].DurationMilliseconds).Should(Equal(int64(20500)))
+		Expect(summary.findings[1].Type).Should(Equal(v1beta1.HealthFindingLockWait))
+		Expect(summary.findings[1].Description).Should(Equal("X lock on public.t1"))
+		Expect(summary.findings[2].Type).Should(Equal(v1beta1.HealthFindingSlowEvent))
+		Expect(summary.findings[2].Description).Should(Equal("GlobalCatalogLock"))
+	})
+
+	It("should be healthy for an empty report", func() {
+		Expect(summarizeReport(nil, time.Second, time.Second).isHealthy()).Should(BeTrue())
+		Expect(summarizeReport(&vadmin.ClusterHealthReport{}, time.Second, time.Second).isHealthy()).Should(BeTrue())
+	})
+
+	It("should keep only the longest findings", func() {
+		report := &vadmin.ClusterHealthReport{}
+		for i := 0; i < maxFindings+5; i++ {
+			report.MissingReleases = append(report.MissingReleases, vops.DcLockAttempts{Duration: "00:00:01"})
+		}
+		summary := summarizeReport(report, time.Second, time.Second)
+		Expect(summary.missingReleases).Should(Equal(maxFindings + 5))
+		Expect(summary.findings).Should(HaveLen(maxFindings))
+	})
+
+	It("should parse data collector intervals", func() {
+		Expect(parseIntervalDuration("01:02:03.5")).Should(Equal(time.Hour + 2*time.Minute + 3500*time.Millisecond))
+		_, err := parseIntervalDuration("12.5")
+		Expect(err).ShouldNot(Succeed())
+	})
+})
@@ -0,0 +1,84 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vhc
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	v1beta1 "github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	verrors "github.com/vertica/vertica-kubernetes/pkg/errors"
+	"github.com/vertica/vertica-kubernetes/pkg/events"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/vk8s"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const stateIncompatibleDB = "Incompatible"
+
+type VdbVerifyReconciler struct {
+	VRec *VerticaHealthCheckReconciler
+	Vhc  *v1beta1.VerticaHealthCheck
+	Log  logr.Logger
+}
+
+func MakeVdbVerifyReconciler(r *VerticaHealthCheckReconciler, vhc *v1beta1.VerticaHealthCheck,
+	log logr.Logger) controllers.ReconcileActor {
+	return &VdbVerifyReconciler{
+		VRec: r,
+		Vhc:  vhc,
+		Log:  log.WithName("VdbVerifyReconciler"),
+	}
+}
+
+// Reconcile will verify the VerticaDB in the Vhc CR exists and is deployed
+// with vclusterops. The health analyses read the data collector tables
+// through the NMA, so they aren't available for admintools deployments. This
+// is checked on every iteration because the VerticaDB can change over the
+// life of the health check.
+func (v *VdbVerifyReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (ctrl.Result, error) {
+	vdb := &vapi.VerticaDB{}
+	nm := names.GenNamespacedName(v.Vhc, v.Vhc.Spec.VerticaDBName)
+	if res, err := vk8s.FetchVDB(ctx, v.VRec, v.Vhc, nm, vdb); verrors.IsReconcileAborted(res, err) {
+		return res, err
+	}
+
+	if !vdb.UseVClusterOpsDeployment() {
+		return v.setNotReady(ctx, events.VhcAdmintoolsNotSupported, "AdmintoolsNotSupported",
+			"Health checks are not supported for admintools deployments")
+	}
+
+	if v.Vhc.IsStatusConditionTrue(v1beta1.HealthCheckReady) {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{}, vk8s.UpdateConditions(ctx, v.VRec.Client, v.Log, v.Vhc,
+		[]*metav1.Condition{vapi.MakeCondition(v1beta1.HealthCheckReady, metav1.ConditionTrue, "Verified")},
+		stateScheduled)
+}
+
+// setNotReady will log an event and set the HealthCheckReady condition to false
+func (v *VdbVerifyReconciler) setNotReady(ctx context.Context, eventReason, condReason, msg string) (ctrl.Result, error) {
+	if !v.Vhc.IsStatusConditionFalse(v1beta1.HealthCheckReady) {
+		v.VRec.Event(v.Vhc, corev1.EventTypeWarning, eventReason, msg)
+	}
+	return ctrl.Result{}, vk8s.UpdateConditions(ctx, v.VRec.Client, v.Log, v.Vhc,
+		[]*metav1.Condition{vapi.MakeCondition(v1beta1.HealthCheckReady, metav1.ConditionFalse, condReason)},
+		stateIncompatibleDB)
+}
@@ -0,0 +1,154 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vrole
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/go-logr/logr"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	v1beta1 "github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	"github.com/vertica/vertica-kubernetes/pkg/dbsql"
+	verrors "github.com/vertica/vertica-kubernetes/pkg/errors"
+	"github.com/vertica/vertica-kubernetes/pkg/events"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/vk8s"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	stateReady    = "Ready"
+	stateNotReady = "WaitingForDatabase"
+	stateFailed   = "Failed"
+
+	// How long to wait before checking again if the database is ready
+	dbNotReadyRequeueTime = 30 * time.Second
+)
+
+type RoleReconciler struct {
+	VRec  *VerticaRoleReconciler
+	Vrole *v1beta1.VerticaRole
+	Log   logr.Logger
+	// A connection to the database. If nil, one is opened when needed. This
+	// is set by tests to mock the database.
+	Conn *sql.DB
+}
+
+func MakeRoleReconciler(r *VerticaRoleReconciler, vrole *v1beta1.VerticaRole,
+	log logr.Logger) controllers.ReconcileActor {
+	return &RoleReconciler{
+		VRec:  r,
+		Vrole: vrole,
+		Log:   log.WithName("RoleReconciler"),
+	}
+}
+
+// Reconcile will create the role if it doesn't exist, then sync the roles
+// and privileges granted to it with the spec. The role isn't dropped when the
+// VerticaRole is deleted.
+func (r *RoleReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (ctrl.Result, error) {
+	vdb := &vapi.VerticaDB{}
+	nm := names.GenNamespacedName(r.Vrole, r.Vrole.Spec.VerticaDBName)
+	if res, err := vk8s.FetchVDB(ctx, r.VRec, r.Vrole, nm, vdb); verrors.IsReconcileAborted(res, err) {
+		return res, err
+	}
+
+	if !vdb.IsDBInitialized() {
+		if !r.Vrole.IsStatusConditionFalse(v1beta1.RoleReady) {
+			r.VRec.Eventf(r.Vrole, corev1.EventTypeNormal, events.DatabaseNotReady,
+				"Waiting for the database in VerticaDB %q to be initialized", vdb.Name)
+		}
+		return ctrl.Result{RequeueAfter: dbNotReadyRequeueTime}, r.setCondition(ctx, metav1.ConditionFalse,
+			"DatabaseNotReady", stateNotReady)
+	}
+
+	if r.Conn == nil {
+		conn, err := dbsql.Open(ctx, r.VRec.Client, r.Log, r.VRec, vdb)
+		if err != nil {
+			r.Log.Info("failed to connect to the database, requeue", "err", err.Error())
+			return ctrl.Result{RequeueAfter: dbNotReadyRequeueTime}, nil
+		}
+		defer conn.Close()
+		r.Conn = conn
+	}
+
+	if err := r.syncRole(ctx); err != nil {
+		r.VRec.Eventf(r.Vrole, corev1.EventTypeWarning, events.RoleUpdateFailed,
+			"Failed to update role %q: %s", r.Vrole.GetRoleName(), err.Error())
+		if updErr := r.setCondition(ctx, metav1.ConditionFalse, "UpdateFailed", stateFailed); updErr != nil {
+			return ctrl.Result{}, updErr
+		}
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// syncRole will run all of the SQL needed to make the role match the spec,
+// then record what was applied in the status.
+func (r *RoleReconciler) syncRole(ctx context.Context) error {
+	roleName := r.Vrole.GetRoleName()
+	exists, err := dbsql.RoleExists(ctx, r.Conn, roleName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if _, err := r.Conn.ExecContext(ctx, fmt.Sprintf("CREATE ROLE %s", dbsql.QuoteIdentifier(roleName))); err != nil {
+			return fmt.Errorf("failed to create role: %w", err)
+		}
+		r.VRec.Eventf(r.Vrole, corev1.EventTypeNormal, events.RoleCreated,
+			"Created role %q in VerticaDB %q", roleName, r.Vrole.Spec.VerticaDBName)
+	}
+
+	if err := dbsql.SyncRoles(ctx, r.Conn, roleName, r.Vrole.Status.GrantedRoles, r.Vrole.Spec.Roles); err != nil {
+		return err
+	}
+	if err := dbsql.SyncGrants(ctx, r.Conn, roleName, r.Vrole.Status.AppliedGrants, r.Vrole.Spec.Grants); err != nil {
+		return err
+	}
+
+	if exists && r.isStatusOutOfDate() {
+		r.VRec.Eventf(r.Vrole, corev1.EventTypeNormal, events.RoleUpdated,
+			"Updated role %q in VerticaDB %q", roleName, r.Vrole.Spec.VerticaDBName)
+	}
+	return vk8s.UpdateStatus(ctx, r.VRec.Client, r.Log, r.Vrole, func(vrole *v1beta1.VerticaRole) error {
+		vrole.Status.GrantedRoles = r.Vrole.Spec.Roles
+		vrole.Status.AppliedGrants = r.Vrole.Spec.Grants
+		vrole.Status.State = stateReady
+		meta.SetStatusCondition(&vrole.Status.Conditions, *vapi.MakeCondition(v1beta1.RoleReady, metav1.ConditionTrue, "Reconciled"))
+		return nil
+	})
+}
+
+// isStatusOutOfDate returns true if the spec differs from what was last
+// applied to the role
+func (r *RoleReconciler) isStatusOutOfDate() bool {
+	return !reflect.DeepEqual(r.Vrole.Status.GrantedRoles, r.Vrole.Spec.Roles) ||
+		!reflect.DeepEqual(r.Vrole.Status.AppliedGrants, r.Vrole.Spec.Grants)
+}
+
+// setCondition will update the RoleReady condition and the state
+func (r *RoleReconciler) setCondition(ctx context.Context, status metav1.ConditionStatus, reason, state string) error {
+	return vk8s.UpdateConditions(ctx, r.VRec.Client, r.Log, r.Vrole,
+		[]*metav1.Condition{vapi.MakeCondition(v1beta1.RoleReady, status, reason)}, state)
+}
@@ -0,0 +1,103 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vrole
+
+import (
+	"context"
+	"errors"
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	v1beta1 "github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/test"
+	"github.com/vertica/vertica-kubernetes/pkg/vdbstatus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("role_reconcile", func() {
+	ctx := context.Background()
+
+	createInitializedVDB := func() *vapi.VerticaDB {
+		vdb := vapi.MakeVDB()
+		test.CreateVDB(ctx, k8sClient, vdb)
+		Expect(vdbstatus.UpdateCondition(ctx, k8sClient, vdb,
+			vapi.MakeCondition(vapi.DBInitialized, metav1.ConditionTrue, "Initialized"))).Should(Succeed())
+		return vdb
+	}
+
+	It("should requeue if VerticaDB doesn't exist", func() {
+		vrole := v1beta1.MakeVrole()
+		Expect(k8sClient.Create(ctx, vrole)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vrole)).Should(Succeed()) }()
+
+		req := ctrl.Request{NamespacedName: v1beta1.MakeSampleVroleName()}
+		Expect(vroleRec.Reconcile(ctx, req)).Should(Equal(ctrl.Result{Requeue: true}))
+	})
+
+	It("should create the role and grant its privileges", func() {
+		vdb := createInitializedVDB()
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+
+		vrole := v1beta1.MakeVrole()
+		vrole.Spec.Roles = []string{"reader"}
+		Expect(k8sClient.Create(ctx, vrole)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vrole)).Should(Succeed()) }()
+
+		db, mock, err := sqlmock.New()
+		Expect(err).Should(Succeed())
+		defer db.Close()
+		mock.ExpectQuery("SELECT COUNT").WithArgs("app_role").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec(regexp.QuoteMeta(`CREATE ROLE "app_role"`)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`GRANT "reader" TO "app_role"`)).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`GRANT SELECT, INSERT ON ALL TABLES IN SCHEMA "store" TO "app_role"`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		recon := MakeRoleReconciler(vroleRec, vrole, logger)
+		recon.(*RoleReconciler).Conn = db
+		Expect(recon.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+		Expect(vrole.IsStatusConditionTrue(v1beta1.RoleReady)).Should(BeTrue())
+		Expect(vrole.Status.GrantedRoles).Should(Equal([]string{"reader"}))
+		Expect(vrole.Status.AppliedGrants).Should(Equal(vrole.Spec.Grants))
+	})
+
+	It("should set the condition to false if a grant fails", func() {
+		vdb := createInitializedVDB()
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+
+		vrole := v1beta1.MakeVrole()
+		Expect(k8sClient.Create(ctx, vrole)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vrole)).Should(Succeed()) }()
+
+		db, mock, err := sqlmock.New()
+		Expect(err).Should(Succeed())
+		defer db.Close()
+		mock.ExpectQuery("SELECT COUNT").WithArgs("app_role").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectExec("GRANT SELECT").WillReturnError(errors.New("schema store does not exist"))
+
+		recon := MakeRoleReconciler(vroleRec, vrole, logger)
+		recon.(*RoleReconciler).Conn = db
+		_, err = recon.Reconcile(ctx, &ctrl.Request{})
+		Expect(err).ShouldNot(Succeed())
+		Expect(vrole.IsStatusConditionFalse(v1beta1.RoleReady)).Should(BeTrue())
+		Expect(vrole.Status.State).Should(Equal(stateFailed))
+		Expect(vrole.Status.AppliedGrants).Should(BeEmpty())
+	})
+})
@@ -0,0 +1,206 @@
+/*
+Copyright [2021-2024] Open Text.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vrole
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/go-logr/logr"
+	v1vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/cache"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	verrors "github.com/vertica/vertica-kubernetes/pkg/errors"
+	"github.com/vertica/vertica-kubernetes/pkg/events"
+	"github.com/vertica/vertica-kubernetes/pkg/meta"
+)
+
+const (
+	vdbNameField = ".spec.verticaDBName"
+)
+
+// VerticaRoleReconciler reconciles a VerticaRole object
+type VerticaRoleReconciler struct {
+	client.Client
+	Scheme       *runtime.Scheme
+	Log          logr.Logger
+	Cfg          *rest.Config
+	EVRec        record.EventRecorder
+	Concurrency  int
+	CacheManager cache.CacheManager
+}
+
+// +kubebuilder:rbac:groups=vertica.com,resources=verticaroles,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=vertica.com,resources=verticaroles/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=vertica.com,resources=verticaroles/finalizers,verbs=update
+
+// Reconcile will create the role in the database and make its roles and
+// privileges match what is in the VerticaRole.
+func (r *VerticaRoleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("vrole", req.NamespacedName)
+	log.Info("starting reconcile of VerticaRole")
+
+	vrole := &vapi.VerticaRole{}
+	err := r.Get(ctx, req.NamespacedName, vrole)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, cound have been deleted after reconcile request.
+			log.Info("VerticaRole resource not found.  Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "failed to get VerticaRole")
+		return ctrl.Result{}, err
+	}
+
+	if meta.IsPauseAnnotationSet(vrole.Annotations) {
+		log.Info(fmt.Sprintf("The pause annotation %s is set. Suspending the iteration", meta.PauseOperatorAnnotation),
+			"result", ctrl.Result{}, "err", nil)
+		return ctrl.Result{}, nil
+	}
+
+	// Iterate over each actor
+	actors := r.constructActors(vrole, log)
+	var res ctrl.Result
+	for _, act := range actors {
+		log.Info("starting actor", "name", fmt.Sprintf("%T", act))
+		res, err = act.Reconcile(ctx, &req)
+		// Error or a request to requeue will stop the reconciliation.
+		if verrors.IsReconcileAborted(res, err) {
+			log.Info("aborting reconcile of VerticaRole", "result", res, "err", err)
+			return res, err
+		}
+	}
+
+	log.Info("ending reconcile of VerticaRole", "result", res, "err", err)
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *VerticaRoleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := r.setupFieldIndexer(mgr.GetFieldIndexer()); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&vapi.VerticaRole{}).
+		// Watch the VerticaDB so that a role that was blocked, because
+		// the VerticaDB wasn't ready, is picked up again.
+		Watches(
+			&v1vapi.VerticaDB{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForVerticaDB),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.Concurrency}).
+		Complete(r)
+}
+
+// setupFieldIndexer will setup an index over the VerticaDB name. This allows
+// us to lookup the roles that refer to a VerticaDB.
+func (r *VerticaRoleReconciler) setupFieldIndexer(indx client.FieldIndexer) error {
+	return indx.IndexField(context.Background(), &vapi.VerticaRole{}, vdbNameField,
+		func(rawObj client.Object) []string {
+			return []string{rawObj.(*vapi.VerticaRole).Spec.VerticaDBName}
+		})
+}
+
+// findObjectsForVerticaDB will generate requests to reconcile
+// VerticaRoles based on watched VerticaDB.
+func (r *VerticaRoleReconciler) findObjectsForVerticaDB(ctx context.Context,
+	vdb client.Object) []reconcile.Request {
+	roles := &vapi.VerticaRoleList{}
+	listOps := &client.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector(vdbNameField, vdb.GetName()),
+		Namespace:     vdb.GetNamespace(),
+	}
+	err := r.List(ctx, roles, listOps)
+	if err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, len(roles.Items))
+	for i := range roles.Items {
+		requests[i] = reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      roles.Items[i].GetName(),
+				Namespace: roles.Items[i].GetNamespace(),
+			},
+		}
+	}
+	return requests
+}
+
+// constructActors will a list of actors that should be run for the reconcile.
+// Order matters in that some actors depend on the successeful execution of
+// earlier ones.
+func (r *VerticaRoleReconciler) constructActors(vrole *vapi.VerticaRole,
+	log logr.Logger) []controllers.ReconcileActor {
+	// The actors that will be applied, in sequence, to reconcile a vrole.
+	actors := []controllers.ReconcileActor{
+		// Create the role and sync its roles and privileges
+		MakeRoleReconciler(r, vrole, log),
+	}
+	return actors
+}
+
+// Event a wrapper for Event() that also writes a log entry
+func (r *VerticaRoleReconciler) Event(vrole runtime.Object, eventtype, reason, message string) {
+	evWriter := events.Writer{
+		Log:   r.Log,
+		EVRec: r.EVRec,
+	}
+	evWriter.Event(vrole, eventtype, reason, message)
+}
+
+// Eventf is a wrapper for Eventf() that also writes a log entry
+func (r *VerticaRoleReconciler) Eventf(vrole runtime.Object, eventtype, reason, messageFmt string,
+	args ...interface{}) {
+	evWriter := events.Writer{
+		Log:   r.Log,
+		EVRec: r.EVRec,
+	}
+	evWriter.Eventf(vrole, eventtype, reason, messageFmt, args...)
+}
+
+// GetClient gives access to the Kubernetes client
+func (r *VerticaRoleReconciler) GetClient() client.Client {
+	return r.Client
+}
+
+// GetEventRecorder gives access to the event recorder
+func (r *VerticaRoleReconciler) GetEventRecorder() record.EventRecorder {
+	return r.EVRec
+}
+
+// GetConfig gives access to *rest.Config
+func (r *VerticaRoleReconciler) GetConfig() *rest.Config {
+	return r.Cfg
+}
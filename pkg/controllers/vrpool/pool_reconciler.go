@@ -0,0 +1,259 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vrpool
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	v1beta1 "github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	"github.com/vertica/vertica-kubernetes/pkg/dbsql"
+	verrors "github.com/vertica/vertica-kubernetes/pkg/errors"
+	"github.com/vertica/vertica-kubernetes/pkg/events"
+	vmeta "github.com/vertica/vertica-kubernetes/pkg/meta"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/vk8s"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	stateReady              = "Ready"
+	stateNotReady           = "WaitingForDatabase"
+	stateSubclusterNotFound = "SubclusterNotFound"
+	stateFailed             = "Failed"
+
+	// The finalizer is needed so that the pool can be dropped from the
+	// database before the VerticaResourcePool is removed.
+	resourcePoolFinalizer = "vertica.com/resource-pool"
+
+	// How long to wait before checking again if the database is ready
+	dbNotReadyRequeueTime = 30 * time.Second
+)
+
+type PoolReconciler struct {
+	VRec   *VerticaResourcePoolReconciler
+	Vrpool *v1beta1.VerticaResourcePool
+	Log    logr.Logger
+	// A connection to the database. If nil, one is opened when needed. This
+	// is set by tests to mock the database.
+	Conn *sql.DB
+}
+
+func MakePoolReconciler(r *VerticaResourcePoolReconciler, vrpool *v1beta1.VerticaResourcePool,
+	log logr.Logger) controllers.ReconcileActor {
+	return &PoolReconciler{
+		VRec:   r,
+		Vrpool: vrpool,
+		Log:    log.WithName("PoolReconciler"),
+	}
+}
+
+// Reconcile will create the resource pool if it doesn't exist, or alter it so
+// that its settings match the spec. If the pool is bound to a subcluster, the
+// settings are scaled to the current size of the subcluster.
+func (p *PoolReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (ctrl.Result, error) {
+	if !p.Vrpool.DeletionTimestamp.IsZero() {
+		return p.reconcileDelete(ctx)
+	}
+	if !controllerutil.ContainsFinalizer(p.Vrpool, resourcePoolFinalizer) {
+		controllerutil.AddFinalizer(p.Vrpool, resourcePoolFinalizer)
+		if err := p.VRec.Client.Update(ctx, p.Vrpool); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	vdb := &vapi.VerticaDB{}
+	nm := names.GenNamespacedName(p.Vrpool, p.Vrpool.Spec.VerticaDBName)
+	if res, err := vk8s.FetchVDB(ctx, p.VRec, p.Vrpool, nm, vdb); verrors.IsReconcileAborted(res, err) {
+		return res, err
+	}
+	if !vdb.IsDBInitialized() {
+		if !p.Vrpool.IsStatusConditionFalse(v1beta1.ResourcePoolReady) {
+			p.VRec.Eventf(p.Vrpool, corev1.EventTypeNormal, events.DatabaseNotReady,
+				"Waiting for the database in VerticaDB %q to be initialized", vdb.Name)
+		}
+		return ctrl.Result{RequeueAfter: dbNotReadyRequeueTime}, p.setCondition(ctx, metav1.ConditionFalse,
+			"DatabaseNotReady", stateNotReady)
+	}
+
+	scSize := int32(0)
+	if p.Vrpool.Spec.Subcluster != "" {
+		sc := vdb.GetSubcluster(p.Vrpool.Spec.Subcluster)
+		if sc == nil {
+			// The VerticaDB is watched, so we are called again once the
+			// subcluster is added.
+			p.VRec.Eventf(p.Vrpool, corev1.EventTypeWarning, events.ResourcePoolSubclusterNotFound,
+				"The subcluster %q was not found in VerticaDB %q", p.Vrpool.Spec.Subcluster, vdb.Name)
+			return ctrl.Result{}, p.setCondition(ctx, metav1.ConditionFalse, "SubclusterNotFound", stateSubclusterNotFound)
+		}
+		scSize = sc.Size
+	}
+	settings, err := p.Vrpool.GetScaledSettings(scSize)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if p.Conn == nil {
+		conn, err := dbsql.Open(ctx, p.VRec.Client, p.Log, p.VRec, vdb)
+		if err != nil {
+			p.Log.Info("failed to connect to the database, requeue", "err", err.Error())
+			return ctrl.Result{RequeueAfter: dbNotReadyRequeueTime}, nil
+		}
+		defer conn.Close()
+		p.Conn = conn
+	}
+
+	if err := p.syncPool(ctx, scSize, &settings); err != nil {
+		p.VRec.Eventf(p.Vrpool, corev1.EventTypeWarning, events.ResourcePoolUpdateFailed,
+			"Failed to update resource pool %q: %s", p.Vrpool.GetPoolName(), err.Error())
+		if updErr := p.setCondition(ctx, metav1.ConditionFalse, "UpdateFailed", stateFailed); updErr != nil {
+			return ctrl.Result{}, updErr
+		}
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// syncPool will create or alter the pool, then record the settings that were
+// applied in the status.
+func (p *PoolReconciler) syncPool(ctx context.Context, scSize int32, settings *v1beta1.ResourcePoolSettings) error {
+	poolName := p.Vrpool.GetPoolName()
+	exists, err := dbsql.ResourcePoolExists(ctx, p.Conn, poolName, p.Vrpool.Spec.Subcluster)
+	if err != nil {
+		return err
+	}
+	params := dbsql.ResourcePoolParams{
+		MemorySize:         settings.MemorySize,
+		MaxConcurrency:     settings.MaxConcurrency,
+		PlannedConcurrency: settings.PlannedConcurrency,
+		Priority:           p.Vrpool.Spec.Priority,
+		QueueTimeout:       p.Vrpool.Spec.QueueTimeout,
+	}
+	if !exists {
+		if _, err := p.Conn.ExecContext(ctx, dbsql.CreateResourcePoolSQL(poolName, p.Vrpool.Spec.Subcluster, &params)); err != nil {
+			return fmt.Errorf("failed to create resource pool: %w", err)
+		}
+		p.VRec.Eventf(p.Vrpool, corev1.EventTypeNormal, events.ResourcePoolCreated,
+			"Created resource pool %q in VerticaDB %q", poolName, p.Vrpool.Spec.VerticaDBName)
+	} else {
+		if _, err := p.Conn.ExecContext(ctx, dbsql.AlterResourcePoolSQL(poolName, p.Vrpool.Spec.Subcluster, &params)); err != nil {
+			return fmt.Errorf("failed to alter resource pool: %w", err)
+		}
+		if p.Vrpool.Spec.Scaling != nil && p.Vrpool.Status.SubclusterSize != 0 && p.Vrpool.Status.SubclusterSize != scSize {
+			p.VRec.Eventf(p.Vrpool, corev1.EventTypeNormal, events.ResourcePoolScaled,
+				"Scaled resource pool %q for subcluster %q going from %d to %d pods", poolName,
+				p.Vrpool.Spec.Subcluster, p.Vrpool.Status.SubclusterSize, scSize)
+		}
+	}
+
+	return vk8s.UpdateStatus(ctx, p.VRec.Client, p.Log, p.Vrpool, func(vrpool *v1beta1.VerticaResourcePool) error {
+		vrpool.Status.SubclusterSize = scSize
+		vrpool.Status.MemorySize = settings.MemorySize
+		vrpool.Status.MaxConcurrency = settings.MaxConcurrency
+		vrpool.Status.PlannedConcurrency = settings.PlannedConcurrency
+		vrpool.Status.State = stateReady
+		meta.SetStatusCondition(&vrpool.Status.Conditions,
+			*vapi.MakeCondition(v1beta1.ResourcePoolReady, metav1.ConditionTrue, "Reconciled"))
+		return nil
+	})
+}
+
+// reconcileDelete will drop the pool from the database and then remove the
+// finalizer. There is nothing to drop if the VerticaDB is gone or being
+// deleted, the database was never created, or the subcluster of the pool was
+// removed. The drop can also be skipped with an annotation, which is the way
+// out when the database can't be reached.
+func (p *PoolReconciler) reconcileDelete(ctx context.Context) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(p.Vrpool, resourcePoolFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	if vmeta.SkipResourcePoolDrop(p.Vrpool.Annotations) {
+		p.Log.Info("Skipping the drop of the resource pool because of the annotation",
+			"annotation", vmeta.SkipResourcePoolDropAnnotation)
+	} else {
+		vdb := &vapi.VerticaDB{}
+		nm := names.GenNamespacedName(p.Vrpool, p.Vrpool.Spec.VerticaDBName)
+		err := p.VRec.Client.Get(ctx, nm, vdb)
+		if err != nil && !kerrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		if err == nil && p.needsDrop(vdb) {
+			if res, err := p.dropPool(ctx, vdb); verrors.IsReconcileAborted(res, err) {
+				return res, err
+			}
+		}
+	}
+
+	controllerutil.RemoveFinalizer(p.Vrpool, resourcePoolFinalizer)
+	return ctrl.Result{}, p.VRec.Client.Update(ctx, p.Vrpool)
+}
+
+// needsDrop returns true if the pool could still exist in the database and
+// must be dropped before the VerticaResourcePool goes away.
+func (p *PoolReconciler) needsDrop(vdb *vapi.VerticaDB) bool {
+	if !vdb.DeletionTimestamp.IsZero() || !vdb.IsDBInitialized() {
+		return false
+	}
+	// A pool that is for a subcluster is removed along with the subcluster
+	return p.Vrpool.Spec.Subcluster == "" || vdb.GetSubcluster(p.Vrpool.Spec.Subcluster) != nil
+}
+
+// dropPool will drop the pool if it exists in the database
+func (p *PoolReconciler) dropPool(ctx context.Context, vdb *vapi.VerticaDB) (ctrl.Result, error) {
+	if p.Conn == nil {
+		conn, err := dbsql.Open(ctx, p.VRec.Client, p.Log, p.VRec, vdb)
+		if err != nil {
+			p.Log.Info("failed to connect to the database to drop the resource pool, requeue", "err", err.Error())
+			p.VRec.Eventf(p.Vrpool, corev1.EventTypeWarning, events.ResourcePoolUpdateFailed,
+				"Cannot drop resource pool %q because the database can't be reached. Set the annotation %q "+
+					"to \"true\" to delete without dropping it", p.Vrpool.GetPoolName(), vmeta.SkipResourcePoolDropAnnotation)
+			return ctrl.Result{RequeueAfter: dbNotReadyRequeueTime}, nil
+		}
+		defer conn.Close()
+		p.Conn = conn
+	}
+
+	poolName := p.Vrpool.GetPoolName()
+	exists, err := dbsql.ResourcePoolExists(ctx, p.Conn, poolName, p.Vrpool.Spec.Subcluster)
+	if err != nil || !exists {
+		return ctrl.Result{}, err
+	}
+	if _, err := p.Conn.ExecContext(ctx, dbsql.DropResourcePoolSQL(poolName, p.Vrpool.Spec.Subcluster)); err != nil {
+		p.VRec.Eventf(p.Vrpool, corev1.EventTypeWarning, events.ResourcePoolUpdateFailed,
+			"Failed to drop resource pool %q: %s", poolName, err.Error())
+		return ctrl.Result{}, err
+	}
+	p.VRec.Eventf(p.Vrpool, corev1.EventTypeNormal, events.ResourcePoolDropped,
+		"Dropped resource pool %q from VerticaDB %q", poolName, p.Vrpool.Spec.VerticaDBName)
+	return ctrl.Result{}, nil
+}
+
+// setCondition will update the ResourcePoolReady condition and the state
+func (p *PoolReconciler) setCondition(ctx context.Context, status metav1.ConditionStatus, reason, state string) error {
+	return vk8s.UpdateConditions(ctx, p.VRec.Client, p.Log, p.Vrpool,
+		[]*metav1.Condition{vapi.MakeCondition(v1beta1.ResourcePoolReady, status, reason)}, state)
+}
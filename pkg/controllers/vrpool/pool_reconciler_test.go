@@ -0,0 +1,157 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vrpool
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	v1beta1 "github.com/vertica/vertica-kubernetes/api/v1beta1"
+	vmeta "github.com/vertica/vertica-kubernetes/pkg/meta"
+	"github.com/vertica/vertica-kubernetes/pkg/test"
+	"github.com/vertica/vertica-kubernetes/pkg/vdbstatus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var _ = Describe("pool_reconcile", func() {
+	ctx := context.Background()
+
+	createInitializedVDB := func(vdb *vapi.VerticaDB) {
+		test.CreateVDB(ctx, k8sClient, vdb)
+		Expect(vdbstatus.UpdateCondition(ctx, k8sClient, vdb,
+			vapi.MakeCondition(vapi.DBInitialized, metav1.ConditionTrue, "Initialized"))).Should(Succeed())
+	}
+
+	deleteVrpool := func(vrpool *v1beta1.VerticaResourcePool) {
+		// The reconciler may have updated the object, so refetch it first
+		Expect(k8sClient.Get(ctx, v1beta1.MakeSampleVrpoolName(), vrpool)).Should(Succeed())
+		controllerutil.RemoveFinalizer(vrpool, resourcePoolFinalizer)
+		Expect(k8sClient.Update(ctx, vrpool)).Should(Succeed())
+		Expect(k8sClient.Delete(ctx, vrpool)).Should(Succeed())
+	}
+
+	It("should requeue if VerticaDB doesn't exist", func() {
+		vrpool := v1beta1.MakeVrpool()
+		Expect(k8sClient.Create(ctx, vrpool)).Should(Succeed())
+		defer deleteVrpool(vrpool)
+
+		req := ctrl.Request{NamespacedName: v1beta1.MakeSampleVrpoolName()}
+		Expect(vrpoolRec.Reconcile(ctx, req)).Should(Equal(ctrl.Result{Requeue: true}))
+	})
+
+	It("should set the condition to false if the subcluster doesn't exist", func() {
+		vdb := vapi.MakeVDB()
+		createInitializedVDB(vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+
+		vrpool := v1beta1.MakeVrpool()
+		vrpool.Spec.Subcluster = "not-there"
+		Expect(k8sClient.Create(ctx, vrpool)).Should(Succeed())
+		defer deleteVrpool(vrpool)
+
+		recon := MakePoolReconciler(vrpoolRec, vrpool, logger)
+		Expect(recon.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(vrpool.IsStatusConditionFalse(v1beta1.ResourcePoolReady)).Should(BeTrue())
+		Expect(vrpool.Status.State).Should(Equal(stateSubclusterNotFound))
+	})
+
+	It("should create the pool with settings scaled to the subcluster size", func() {
+		vdb := vapi.MakeVDB()
+		vdb.Spec.Subclusters[0].Size = 4
+		createInitializedVDB(vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+
+		vrpool := v1beta1.MakeVrpool()
+		vrpool.Spec.Scaling = &v1beta1.VerticaResourcePoolScaling{
+			BaseSize:                    3,
+			MemorySizePercentPerPod:     50,
+			MaxConcurrencyPercentPerPod: 50,
+		}
+		Expect(k8sClient.Create(ctx, vrpool)).Should(Succeed())
+		defer deleteVrpool(vrpool)
+
+		db, mock, err := sqlmock.New()
+		Expect(err).Should(Succeed())
+		defer db.Close()
+		mock.ExpectQuery("SELECT COUNT").WithArgs("etl_pool", vdb.Spec.Subclusters[0].Name).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		mock.ExpectExec(regexp.QuoteMeta(fmt.Sprintf(`CREATE RESOURCE POOL "etl_pool" FOR SUBCLUSTER "%s" MEMORYSIZE '6G' MAXCONCURRENCY 6`,
+			vdb.Spec.Subclusters[0].Name))).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		recon := MakePoolReconciler(vrpoolRec, vrpool, logger)
+		recon.(*PoolReconciler).Conn = db
+		Expect(recon.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+		Expect(controllerutil.ContainsFinalizer(vrpool, resourcePoolFinalizer)).Should(BeTrue())
+		Expect(vrpool.IsStatusConditionTrue(v1beta1.ResourcePoolReady)).Should(BeTrue())
+		Expect(vrpool.Status.SubclusterSize).Should(Equal(int32(4)))
+		Expect(vrpool.Status.MemorySize).Should(Equal("6G"))
+		Expect(*vrpool.Status.MaxConcurrency).Should(Equal(int32(6)))
+	})
+
+	It("should drop the pool when the VerticaResourcePool is deleted", func() {
+		vdb := vapi.MakeVDB()
+		createInitializedVDB(vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+
+		vrpool := v1beta1.MakeVrpool()
+		vrpool.Finalizers = []string{resourcePoolFinalizer}
+		Expect(k8sClient.Create(ctx, vrpool)).Should(Succeed())
+		Expect(k8sClient.Delete(ctx, vrpool)).Should(Succeed())
+		Expect(k8sClient.Get(ctx, v1beta1.MakeSampleVrpoolName(), vrpool)).Should(Succeed())
+
+		db, mock, err := sqlmock.New()
+		Expect(err).Should(Succeed())
+		defer db.Close()
+		mock.ExpectQuery("SELECT COUNT").WithArgs("etl_pool", vdb.Spec.Subclusters[0].Name).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectExec(regexp.QuoteMeta(`DROP RESOURCE POOL "etl_pool" FOR SUBCLUSTER`)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		recon := MakePoolReconciler(vrpoolRec, vrpool, logger)
+		recon.(*PoolReconciler).Conn = db
+		Expect(recon.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+		Expect(controllerutil.ContainsFinalizer(vrpool, resourcePoolFinalizer)).Should(BeFalse())
+	})
+
+	It("should remove the finalizer without dropping the pool if the annotation is set", func() {
+		vdb := vapi.MakeVDB()
+		createInitializedVDB(vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+
+		vrpool := v1beta1.MakeVrpool()
+		vrpool.Finalizers = []string{resourcePoolFinalizer}
+		vrpool.Annotations = map[string]string{vmeta.SkipResourcePoolDropAnnotation: "true"}
+		Expect(k8sClient.Create(ctx, vrpool)).Should(Succeed())
+		Expect(k8sClient.Delete(ctx, vrpool)).Should(Succeed())
+		Expect(k8sClient.Get(ctx, v1beta1.MakeSampleVrpoolName(), vrpool)).Should(Succeed())
+
+		db, mock, err := sqlmock.New()
+		Expect(err).Should(Succeed())
+		defer db.Close()
+
+		recon := MakePoolReconciler(vrpoolRec, vrpool, logger)
+		recon.(*PoolReconciler).Conn = db
+		Expect(recon.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+		Expect(controllerutil.ContainsFinalizer(vrpool, resourcePoolFinalizer)).Should(BeFalse())
+	})
+})
@@ -0,0 +1,63 @@
+/*
+Copyright [2021-2024] Open Text.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vrpool
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/vertica/vertica-kubernetes/pkg/cache"
+	"github.com/vertica/vertica-kubernetes/pkg/test"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+var testEnv *test.EnvTest
+var k8sClient client.Client
+var vrpoolRec *VerticaResourcePoolReconciler
+var logger logr.Logger
+
+func TestAPIs(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "VerticaResourcePool Suite")
+}
+
+var _ = BeforeSuite(func() {
+	logger = zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true))
+	logf.SetLogger(logger)
+
+	testEnv = test.StartEnvTest(filepath.Join("..", "..", "..", "config", "crd", "bases"))
+	k8sClient = testEnv.Client
+	vrpoolRec = &VerticaResourcePoolReconciler{
+		Client:       k8sClient,
+		Scheme:       scheme.Scheme,
+		Cfg:          testEnv.Cfg,
+		Log:          logger,
+		EVRec:        testEnv.EVRec,
+		CacheManager: cache.MakeCacheManager(true),
+	}
+})
+
+var _ = AfterSuite(func() {
+	testEnv.Stop()
+})
@@ -0,0 +1,225 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vrpq
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/vertica/vcluster/vclusterops"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	v1beta1 "github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	verrors "github.com/vertica/vertica-kubernetes/pkg/errors"
+	"github.com/vertica/vertica-kubernetes/pkg/events"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/vk8s"
+	vrpqstatus "github.com/vertica/vertica-kubernetes/pkg/vrpqstatus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	stateReviveCopyCreated = "Revive copy VerticaDB created"
+	stateReviveCopyFailed  = "Revive copy failed"
+
+	// The annotation that kubectl apply leaves on objects. It describes the
+	// source VerticaDB, so it must not be carried over to the clone.
+	lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+// ReviveCopyReconciler will revive a copy of the database at a restore point
+// found by the query into a new VerticaDB when spec.reviveCopy is set.
+type ReviveCopyReconciler struct {
+	VRec *VerticaRestorePointsQueryReconciler
+	Vrpq *v1beta1.VerticaRestorePointsQuery
+	Log  logr.Logger
+}
+
+func MakeReviveCopyReconciler(r *VerticaRestorePointsQueryReconciler, vrpq *v1beta1.VerticaRestorePointsQuery,
+	log logr.Logger) controllers.ReconcileActor {
+	return &ReviveCopyReconciler{
+		VRec: r,
+		Vrpq: vrpq,
+		Log:  log.WithName("ReviveCopyReconciler"),
+	}
+}
+
+func (r *ReviveCopyReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (ctrl.Result, error) {
+	// no-op if a revive copy wasn't requested or it was already handled
+	if r.Vrpq.Spec.ReviveCopy == nil || r.Vrpq.IsStatusConditionPresent(v1beta1.ReviveCopyComplete) {
+		return ctrl.Result{}, nil
+	}
+
+	// We can only pick a restore point once the query succeeded
+	cond := r.Vrpq.FindStatusCondition(v1beta1.QueryComplete)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != "Completed" {
+		return ctrl.Result{}, nil
+	}
+
+	restorePoint, err := r.selectRestorePoint()
+	if err != nil {
+		r.VRec.Event(r.Vrpq, corev1.EventTypeWarning, events.RestorePointNotFound, err.Error())
+		return ctrl.Result{}, r.setReviveCopyFailed(ctx, "RestorePointNotFound", err.Error(), nil)
+	}
+
+	srcVdb := &vapi.VerticaDB{}
+	nm := names.GenNamespacedName(r.Vrpq, r.Vrpq.Spec.VerticaDBName)
+	if res, err := vk8s.FetchVDB(ctx, r.VRec, r.Vrpq, nm, srcVdb); verrors.IsReconcileAborted(res, err) {
+		return res, err
+	}
+
+	// Reviving into the source's communal path would take over the source
+	// database, so the revive must use its own copy of the communal data.
+	if isSameCommunalPath(srcVdb.Spec.Communal.Path, r.Vrpq.Spec.ReviveCopy.CommunalPath) {
+		msg := fmt.Sprintf("Cannot revive a copy from communal path %q because it is the communal path of VerticaDB %q",
+			r.Vrpq.Spec.ReviveCopy.CommunalPath, srcVdb.Name)
+		r.VRec.Event(r.Vrpq, corev1.EventTypeWarning, events.ReviveCopyCommunalPathInvalid, msg)
+		return ctrl.Result{}, r.setReviveCopyFailed(ctx, "InvalidCommunalPath", msg, restorePoint)
+	}
+
+	vdb := r.buildReviveCopyVdb(srcVdb, restorePoint)
+	if err := r.VRec.Client.Create(ctx, vdb); err != nil {
+		if kerrors.IsAlreadyExists(err) {
+			msg := fmt.Sprintf("Cannot revive a copy into VerticaDB %q because it already exists", vdb.Name)
+			r.VRec.Event(r.Vrpq, corev1.EventTypeWarning, events.ReviveCopyVerticaDBExists, msg)
+			return ctrl.Result{}, r.setReviveCopyFailed(ctx, "VerticaDBExists", msg, restorePoint)
+		}
+		return ctrl.Result{}, err
+	}
+	r.VRec.Eventf(r.Vrpq, corev1.EventTypeNormal, events.ReviveCopyVerticaDBCreated,
+		"Created VerticaDB %q to revive a copy at restore point %q from archive %q with communal path %q",
+		vdb.Name, restorePoint.ID, restorePoint.Archive, vdb.Spec.Communal.Path)
+	return ctrl.Result{}, vrpqstatus.UpdateReviveCopy(ctx, r.VRec.Client, r.Log, r.Vrpq,
+		[]*metav1.Condition{vapi.MakeCondition(v1beta1.ReviveCopyComplete, metav1.ConditionTrue, "Created")},
+		stateReviveCopyCreated, restorePoint, vdb.Name)
+}
+
+// selectRestorePoint will pick the restore point from the query results that
+// matches the revive copy options.
+func (r *ReviveCopyReconciler) selectRestorePoint() (*vclusterops.RestorePoint, error) {
+	restore := r.Vrpq.Spec.ReviveCopy
+	archive := restore.ArchiveName
+	if archive == "" && r.Vrpq.Spec.FilterOptions != nil {
+		archive = r.Vrpq.Spec.FilterOptions.ArchiveName
+	}
+
+	if restore.ID != "" {
+		for i := range r.Vrpq.Status.RestorePoints {
+			rp := &r.Vrpq.Status.RestorePoints[i]
+			if rp.ID == restore.ID && (archive == "" || rp.Archive == archive) {
+				selected := *rp
+				return &selected, nil
+			}
+		}
+		return nil, fmt.Errorf("no restore point with id %q was found", restore.ID)
+	}
+
+	// The timestamp was validated by the webhook. This converts a date only
+	// value to the end of that day.
+	options := vclusterops.ShowRestorePointFilterOptions{EndTimestamp: restore.Timestamp}
+	if err := options.ValidateAndStandardizeTimestampsIfAny(); err != nil {
+		return nil, err
+	}
+	target, err := time.Parse(time.DateTime, options.EndTimestamp)
+	if err != nil {
+		return nil, err
+	}
+	var selected *vclusterops.RestorePoint
+	var selectedTime time.Time
+	for i := range r.Vrpq.Status.RestorePoints {
+		rp := &r.Vrpq.Status.RestorePoints[i]
+		if archive != "" && rp.Archive != archive {
+			continue
+		}
+		rpTime, err := time.Parse(time.DateTime, rp.Timestamp)
+		if err != nil {
+			r.Log.Info("skipping restore point with an unparseable timestamp", "id", rp.ID, "timestamp", rp.Timestamp)
+			continue
+		}
+		if rpTime.After(target) {
+			continue
+		}
+		if selected == nil || rpTime.After(selectedTime) {
+			rpCopy := *rp
+			selected = &rpCopy
+			selectedTime = rpTime
+		}
+	}
+	if selected == nil {
+		return nil, fmt.Errorf("no restore point was found at or before %q", restore.Timestamp)
+	}
+	return selected, nil
+}
+
+// buildReviveCopyVdb returns the VerticaDB to create for the revive copy. It is a
+// clone of the source VerticaDB that revives from the restore point found in
+// the communal path given in spec.reviveCopy.
+func (r *ReviveCopyReconciler) buildReviveCopyVdb(srcVdb *vapi.VerticaDB, restorePoint *vclusterops.RestorePoint) *vapi.VerticaDB {
+	vdb := &vapi.VerticaDB{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: vapi.GroupVersion.String(),
+			Kind:       vapi.VerticaDBKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        r.Vrpq.Spec.ReviveCopy.TargetVerticaDBName,
+			Namespace:   r.Vrpq.Namespace,
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+		},
+		Spec: *srcVdb.Spec.DeepCopy(),
+	}
+	for k, v := range srcVdb.Labels {
+		vdb.Labels[k] = v
+	}
+	for k, v := range srcVdb.Annotations {
+		if k == lastAppliedConfigAnnotation {
+			continue
+		}
+		vdb.Annotations[k] = v
+	}
+
+	vdb.Spec.InitPolicy = vapi.CommunalInitPolicyRevive
+	vdb.Spec.RestorePoint = &vapi.RestorePointPolicy{
+		Archive: restorePoint.Archive,
+		ID:      restorePoint.ID,
+	}
+	vdb.Spec.Communal.Path = r.Vrpq.Spec.ReviveCopy.CommunalPath
+	// Sandboxes and the revive order only make sense for the source database
+	vdb.Spec.Sandboxes = nil
+	vdb.Spec.ReviveOrder = nil
+	return vdb
+}
+
+// isSameCommunalPath returns true if the two communal paths refer to the same
+// location. A trailing slash is ignored.
+func isSameCommunalPath(path1, path2 string) bool {
+	return strings.TrimSuffix(path1, "/") == strings.TrimSuffix(path2, "/")
+}
+
+// setReviveCopyFailed records that the revive copy could not be done
+func (r *ReviveCopyReconciler) setReviveCopyFailed(ctx context.Context, reason, msg string,
+	restorePoint *vclusterops.RestorePoint) error {
+	cond := vapi.MakeCondition(v1beta1.ReviveCopyComplete, metav1.ConditionFalse, reason)
+	cond.Message = msg
+	return vrpqstatus.UpdateReviveCopy(ctx, r.VRec.Client, r.Log, r.Vrpq,
+		[]*metav1.Condition{cond}, stateReviveCopyFailed, restorePoint, "")
+}
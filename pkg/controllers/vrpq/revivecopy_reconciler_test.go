@@ -0,0 +1,146 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vrpq
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/vertica/vcluster/vclusterops"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	v1beta1 "github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/test"
+	vrpqstatus "github.com/vertica/vertica-kubernetes/pkg/vrpqstatus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var testRestorePoints = []vclusterops.RestorePoint{
+	{Archive: "db", ID: "id-1", Index: 3, Timestamp: "2024-03-01 10:00:00.123456"},
+	{Archive: "db", ID: "id-2", Index: 2, Timestamp: "2024-03-02 10:00:00.123456"},
+	{Archive: "db", ID: "id-3", Index: 1, Timestamp: "2024-03-03 10:00:00.123456"},
+	{Archive: "other", ID: "id-4", Index: 1, Timestamp: "2024-03-02 12:00:00.123456"},
+}
+
+var _ = Describe("restore_reconcile", func() {
+	ctx := context.Background()
+
+	It("should select a restore point by id or timestamp", func() {
+		vrpq := v1beta1.MakeVrpq()
+		vrpq.Status.RestorePoints = testRestorePoints
+		vrpq.Spec.ReviveCopy = &v1beta1.VerticaRestorePointQueryReviveCopyOptions{
+			TargetVerticaDBName: "restored",
+			ID:                  "id-2",
+			CommunalPath:        "s3://scratch/db",
+		}
+		r := &ReviveCopyReconciler{Vrpq: vrpq, Log: logger}
+		rp, err := r.selectRestorePoint()
+		Expect(err).Should(Succeed())
+		Expect(rp.ID).Should(Equal("id-2"))
+
+		vrpq.Spec.ReviveCopy.ID = ""
+		vrpq.Spec.ReviveCopy.Timestamp = "2024-03-02"
+		vrpq.Spec.ReviveCopy.ArchiveName = "db"
+		rp, err = r.selectRestorePoint()
+		Expect(err).Should(Succeed())
+		Expect(rp.ID).Should(Equal("id-2"))
+
+		vrpq.Spec.ReviveCopy.ArchiveName = ""
+		rp, err = r.selectRestorePoint()
+		Expect(err).Should(Succeed())
+		Expect(rp.ID).Should(Equal("id-4"))
+
+		vrpq.Spec.ReviveCopy.Timestamp = "2024-02-28 23:59:59"
+		_, err = r.selectRestorePoint()
+		Expect(err).ShouldNot(Succeed())
+	})
+
+	It("should clone the source VerticaDB to revive from the restore point", func() {
+		vdb := vapi.MakeVDB()
+		vdb.Spec.Communal.Path = "s3://bucket/db/"
+		vrpq := v1beta1.MakeVrpq()
+		vrpq.Spec.ReviveCopy = &v1beta1.VerticaRestorePointQueryReviveCopyOptions{
+			TargetVerticaDBName: "restored",
+			ID:                  "id-2",
+		}
+		r := &ReviveCopyReconciler{Vrpq: vrpq, Log: logger}
+		restored := r.buildReviveCopyVdb(vdb, &testRestorePoints[1])
+		Expect(restored.Name).Should(Equal("restored"))
+		Expect(restored.Spec.InitPolicy).Should(Equal(vapi.CommunalInitPolicyRevive))
+		Expect(restored.Spec.RestorePoint.Archive).Should(Equal("db"))
+		Expect(restored.Spec.RestorePoint.ID).Should(Equal("id-2"))
+		Expect(restored.Spec.Communal.Path).Should(Equal("s3://scratch/db"))
+		Expect(restored.Spec.Communal.CredentialSecret).Should(Equal(vdb.Spec.Communal.CredentialSecret))
+		Expect(restored.Spec.DBName).Should(Equal(vdb.Spec.DBName))
+	})
+
+	It("should refuse to restore into the communal path of the source", func() {
+		vdb := vapi.MakeVDB()
+		test.CreateVDB(ctx, k8sClient, vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+
+		vrpq := v1beta1.MakeVrpq()
+		vrpq.Spec.ReviveCopy = &v1beta1.VerticaRestorePointQueryReviveCopyOptions{
+			TargetVerticaDBName: "restored",
+			ID:                  "id-3",
+			CommunalPath:        vdb.Spec.Communal.Path + "/",
+		}
+		Expect(k8sClient.Create(ctx, vrpq)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vrpq)).Should(Succeed()) }()
+		Expect(vrpqstatus.Update(ctx, k8sClient, logger, vrpq,
+			[]*metav1.Condition{vapi.MakeCondition(v1beta1.QueryComplete, metav1.ConditionTrue, "Completed")},
+			stateSuccessQuery, testRestorePoints)).Should(Succeed())
+
+		recon := MakeReviveCopyReconciler(vrpqRec, vrpq, logger)
+		Expect(recon.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(vrpq.IsStatusConditionFalse(v1beta1.ReviveCopyComplete)).Should(BeTrue())
+		Expect(vrpq.FindStatusCondition(v1beta1.ReviveCopyComplete).Reason).Should(Equal("InvalidCommunalPath"))
+		Expect(vrpq.Status.RevivedVerticaDBName).Should(Equal(""))
+	})
+
+	It("should create the VerticaDB once the query completes", func() {
+		vdb := vapi.MakeVDB()
+		test.CreateVDB(ctx, k8sClient, vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+
+		vrpq := v1beta1.MakeVrpq()
+		vrpq.Spec.ReviveCopy = &v1beta1.VerticaRestorePointQueryReviveCopyOptions{
+			TargetVerticaDBName: "restored",
+			ID:                  "id-3",
+			CommunalPath:        "s3://scratch/db",
+		}
+		Expect(k8sClient.Create(ctx, vrpq)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vrpq)).Should(Succeed()) }()
+		Expect(vrpqstatus.Update(ctx, k8sClient, logger, vrpq,
+			[]*metav1.Condition{vapi.MakeCondition(v1beta1.QueryComplete, metav1.ConditionTrue, "Completed")},
+			stateSuccessQuery, testRestorePoints)).Should(Succeed())
+
+		recon := MakeReviveCopyReconciler(vrpqRec, vrpq, logger)
+		Expect(recon.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(vrpq.IsStatusConditionTrue(v1beta1.ReviveCopyComplete)).Should(BeTrue())
+		Expect(vrpq.Status.RevivedVerticaDBName).Should(Equal("restored"))
+		Expect(vrpq.Status.RevivedRestorePoint.ID).Should(Equal("id-3"))
+		Expect(vrpq.Status.RestorePoints).Should(HaveLen(len(testRestorePoints)))
+
+		restored := &vapi.VerticaDB{}
+		nm := types.NamespacedName{Namespace: vdb.Namespace, Name: "restored"}
+		Expect(k8sClient.Get(ctx, nm, restored)).Should(Succeed())
+		defer test.DeleteVDB(ctx, k8sClient, restored)
+		Expect(restored.Spec.RestorePoint.ID).Should(Equal("id-3"))
+	})
+})
@@ -121,6 +121,8 @@ func (r *VerticaRestorePointsQueryReconciler) constructActors(vrpq *vapi.Vertica
 		MakeVdbVerifyReconciler(r, vrpq, log),
 		// Handle calls to show restore points
 		MakeRestorePointsQueryReconciler(r, vrpq, log),
+		// Revive a restore point from the query results into a new VerticaDB
+		MakeReviveCopyReconciler(r, vrpq, log),
 	}
 	return actors
 }
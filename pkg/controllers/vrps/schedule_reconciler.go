@@ -0,0 +1,343 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vrps
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	vops "github.com/vertica/vcluster/vclusterops"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	v1beta1 "github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/cloud"
+	"github.com/vertica/vertica-kubernetes/pkg/cmds"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	"github.com/vertica/vertica-kubernetes/pkg/cron"
+	verrors "github.com/vertica/vertica-kubernetes/pkg/errors"
+	"github.com/vertica/vertica-kubernetes/pkg/events"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/podfacts"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/createarchive"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/removerestorepoint"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/saverestorepoint"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/showrestorepoints"
+	config "github.com/vertica/vertica-kubernetes/pkg/vdbconfig"
+	"github.com/vertica/vertica-kubernetes/pkg/vk8s"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	stateScheduled       = "Scheduled"
+	stateSuspended       = "Suspended"
+	stateSaving          = "Saving restore point"
+	stateInvalidSchedule = "Invalid schedule"
+	stateNoUpcomingRun   = "No upcoming run"
+)
+
+type ScheduleReconciler struct {
+	VRec *VerticaRestorePointScheduleReconciler
+	Vrps *v1beta1.VerticaRestorePointSchedule
+	Log  logr.Logger
+	Vdb  *vapi.VerticaDB
+}
+
+func MakeScheduleReconciler(r *VerticaRestorePointScheduleReconciler, vrps *v1beta1.VerticaRestorePointSchedule,
+	log logr.Logger) controllers.ReconcileActor {
+	return &ScheduleReconciler{
+		VRec: r,
+		Vrps: vrps,
+		Log:  log.WithName("ScheduleReconciler"),
+	}
+}
+
+// Reconcile will save a restore point if the schedule is due. Otherwise, it
+// requeues the vrps so that it is reconciled again at the next scheduled time.
+func (s *ScheduleReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (ctrl.Result, error) {
+	// no-op if the VerticaDB hasn't been verified yet
+	if !s.Vrps.IsStatusConditionTrue(v1beta1.ScheduleReady) {
+		return ctrl.Result{}, nil
+	}
+
+	sched, err := cron.Parse(s.Vrps.Spec.Schedule)
+	if err != nil {
+		// The webhook should prevent this. There is nothing to do until the
+		// schedule is corrected, so we don't requeue.
+		if s.Vrps.Status.State != stateInvalidSchedule {
+			s.VRec.Eventf(s.Vrps, corev1.EventTypeWarning, events.InvalidRestorePointSchedule,
+				"The schedule %q is not valid: %s", s.Vrps.Spec.Schedule, err)
+		}
+		return ctrl.Result{}, s.updateScheduleStatus(ctx, stateInvalidSchedule, time.Time{})
+	}
+
+	if s.Vrps.Spec.Suspend {
+		return ctrl.Result{}, s.updateScheduleStatus(ctx, stateSuspended, time.Time{})
+	}
+
+	now := time.Now().UTC()
+	next := s.getNextRunTime(sched)
+	if next.IsZero() {
+		return ctrl.Result{}, s.updateScheduleStatus(ctx, stateNoUpcomingRun, time.Time{})
+	}
+	if now.Before(next) {
+		return ctrl.Result{RequeueAfter: next.Sub(now)}, s.updateScheduleStatus(ctx, stateScheduled, next)
+	}
+
+	// The schedule is due. If several runs were missed, for instance because
+	// the operator was down, they are collapsed into a single restore point.
+	if res, err2 := s.fetchVdb(ctx); verrors.IsReconcileAborted(res, err2) {
+		return res, err2
+	}
+	hostIP, res, err := s.findInitiatorIP(ctx)
+	if verrors.IsReconcileAborted(res, err) {
+		return res, err
+	}
+	dispatcher, err := s.makeDispatcher(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := s.runSaveRestorePoint(ctx, dispatcher, hostIP, now); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	next = sched.Next(now)
+	if next.IsZero() {
+		return ctrl.Result{}, s.updateScheduleStatus(ctx, stateNoUpcomingRun, time.Time{})
+	}
+	return ctrl.Result{RequeueAfter: next.Sub(now)}, s.updateScheduleStatus(ctx, stateScheduled, next)
+}
+
+// getNextRunTime returns the next time a restore point is due. It is computed
+// from the last attempt, or the creation of the vrps if there hasn't been one.
+func (s *ScheduleReconciler) getNextRunTime(sched *cron.Schedule) time.Time {
+	base := s.Vrps.CreationTimestamp.Time
+	if s.Vrps.Status.LastScheduleTime != nil {
+		base = s.Vrps.Status.LastScheduleTime.Time
+	}
+	return sched.Next(base.UTC())
+}
+
+// fetchVdb will fetch the VerticaDB referenced by the vrps
+func (s *ScheduleReconciler) fetchVdb(ctx context.Context) (ctrl.Result, error) {
+	vdb := &vapi.VerticaDB{}
+	nm := names.GenNamespacedName(s.Vrps, s.Vrps.Spec.VerticaDBName)
+	if res, err := vk8s.FetchVDB(ctx, s.VRec, s.Vrps, nm, vdb); verrors.IsReconcileAborted(res, err) {
+		return res, err
+	}
+	s.Vdb = vdb
+	return ctrl.Result{}, nil
+}
+
+// findInitiatorIP returns the IP of an up pod in the main cluster that we can
+// run the vclusterops API from. It requeues if there is no such pod.
+func (s *ScheduleReconciler) findInitiatorIP(ctx context.Context) (string, ctrl.Result, error) {
+	username := s.Vdb.GetVerticaUser()
+	password, err := vk8s.GetSuperuserPassword(ctx, s.VRec.Client, s.Log, s.VRec, s.Vdb)
+	if err != nil {
+		return "", ctrl.Result{}, err
+	}
+	prunner := cmds.MakeClusterPodRunner(s.Log, s.VRec.Cfg, username, password, s.Vdb.IsClientServerTLSAuthEnabled())
+	pfacts := podfacts.MakePodFactsForSandboxWithCacheManager(s.VRec, prunner, s.Log, password,
+		vapi.MainCluster, s.VRec.CacheManager)
+	if err := pfacts.Collect(ctx, s.Vdb); err != nil {
+		return "", ctrl.Result{}, err
+	}
+	hostIP, ok := pfacts.FindFirstUpPodIP(false, "")
+	if !ok {
+		s.Log.Info("No up pod found to save a scheduled restore point. Requeuing.")
+		return "", ctrl.Result{Requeue: true}, nil
+	}
+	return hostIP, ctrl.Result{}, nil
+}
+
+// makeDispatcher will create a Dispatcher object for the vclusterops API
+func (s *ScheduleReconciler) makeDispatcher(ctx context.Context) (vadmin.Dispatcher, error) {
+	password, err := vk8s.GetSuperuserPassword(ctx, s.VRec.Client, s.Log, s.VRec, s.Vdb)
+	if err != nil {
+		return nil, err
+	}
+	fetcher := &cloud.SecretFetcher{
+		Client:   s.VRec.Client,
+		Log:      s.Log,
+		Obj:      s.Vdb,
+		EVWriter: s.VRec,
+	}
+	s.VRec.CacheManager.InitCertCacheForVdb(s.Vdb, fetcher)
+	return vadmin.MakeVClusterOps(s.Log, s.Vdb, s.VRec.GetClient(), password,
+		s.VRec, vadmin.SetupVClusterOps, s.VRec.CacheManager), nil
+}
+
+// runSaveRestorePoint will create the archive, if needed, and save a restore
+// point to it. A failure is recorded in the status and is not returned as an
+// error; the next attempt happens at the next scheduled time.
+func (s *ScheduleReconciler) runSaveRestorePoint(ctx context.Context, dispatcher vadmin.Dispatcher,
+	hostIP string, now time.Time) error {
+	err := vk8s.UpdateStatus(ctx, s.VRec.Client, s.Log, s.Vrps, func(vrps *v1beta1.VerticaRestorePointSchedule) error {
+		vrps.Status.State = stateSaving
+		vrps.Status.LastScheduleTime = &metav1.Time{Time: now}
+		meta.SetStatusCondition(&vrps.Status.Conditions,
+			*vapi.MakeCondition(v1beta1.SavingRestorePoint, metav1.ConditionTrue, "Started"))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	archive := s.Vrps.Spec.ArchiveName
+	s.VRec.Eventf(s.Vrps, corev1.EventTypeNormal, events.ScheduledRestorePointStarted,
+		"Starting scheduled restore point to archive %q", archive)
+	start := time.Now()
+	errRun := s.saveRestorePoint(ctx, dispatcher, hostIP)
+	if errRun != nil {
+		s.VRec.Eventf(s.Vrps, corev1.EventTypeWarning, events.ScheduledRestorePointFailed,
+			"Failed to save scheduled restore point to archive %q: %s", archive, errRun)
+		return vk8s.UpdateStatus(ctx, s.VRec.Client, s.Log, s.Vrps, func(vrps *v1beta1.VerticaRestorePointSchedule) error {
+			vrps.Status.LastFailedTime = &metav1.Time{Time: time.Now().UTC()}
+			meta.SetStatusCondition(&vrps.Status.Conditions,
+				*vapi.MakeCondition(v1beta1.SavingRestorePoint, metav1.ConditionFalse, "Failed"))
+			cond := vapi.MakeCondition(v1beta1.LastRestorePointSaved, metav1.ConditionFalse, "Failed")
+			cond.Message = errRun.Error()
+			meta.SetStatusCondition(&vrps.Status.Conditions, *cond)
+			return nil
+		})
+	}
+	s.VRec.Eventf(s.Vrps, corev1.EventTypeNormal, events.ScheduledRestorePointSucceeded,
+		"Successfully saved scheduled restore point to archive %q. It took %s",
+		archive, time.Since(start).Truncate(time.Second))
+	// The restore point is saved, so a failure to prune doesn't fail the run.
+	// Pruning is attempted again after the next restore point is saved.
+	if errPrune := s.pruneRestorePoints(ctx, dispatcher, hostIP); errPrune != nil {
+		s.Log.Error(errPrune, "failed to prune restore points", "archive", archive)
+		s.VRec.Eventf(s.Vrps, corev1.EventTypeWarning, events.RestorePointPruneFailed,
+			"Failed to prune restore points in archive %q: %s", archive, errPrune)
+	}
+	return vk8s.UpdateStatus(ctx, s.VRec.Client, s.Log, s.Vrps, func(vrps *v1beta1.VerticaRestorePointSchedule) error {
+		vrps.Status.LastSuccessfulTime = &metav1.Time{Time: time.Now().UTC()}
+		meta.SetStatusCondition(&vrps.Status.Conditions,
+			*vapi.MakeCondition(v1beta1.SavingRestorePoint, metav1.ConditionFalse, "Completed"))
+		meta.SetStatusCondition(&vrps.Status.Conditions,
+			*vapi.MakeCondition(v1beta1.LastRestorePointSaved, metav1.ConditionTrue, "Succeeded"))
+		return nil
+	})
+}
+
+// saveRestorePoint calls the vclusterops APIs to create the archive and save
+// a restore point in it. The archive limit is only set when the archive is
+// created, so retention is enforced separately by pruneRestorePoints.
+func (s *ScheduleReconciler) saveRestorePoint(ctx context.Context, dispatcher vadmin.Dispatcher, hostIP string) error {
+	err := dispatcher.CreateArchive(ctx,
+		createarchive.WithInitiator(hostIP),
+		createarchive.WithArchiveName(s.Vrps.Spec.ArchiveName),
+		createarchive.WithNumRestorePoints(s.Vrps.Spec.NumRestorePoints),
+		createarchive.WithSandbox(vapi.MainCluster),
+	)
+	// This will be replaced by a vproblem in VER-96975
+	if err != nil && !strings.Contains(err.Error(), "Duplicate object on host") {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	return dispatcher.SaveRestorePoint(ctx,
+		saverestorepoint.WithInitiator(hostIP),
+		saverestorepoint.WithArchiveName(s.Vrps.Spec.ArchiveName),
+		saverestorepoint.WithSandbox(vapi.MainCluster),
+	)
+}
+
+// pruneRestorePoints removes the oldest restore points in the archive so that
+// at most spec.numRestorePoints are kept. This is done on every run so that a
+// change to numRestorePoints applies to an archive that already exists.
+func (s *ScheduleReconciler) pruneRestorePoints(ctx context.Context, dispatcher vadmin.Dispatcher, hostIP string) error {
+	if s.Vrps.Spec.NumRestorePoints == 0 {
+		return nil
+	}
+	// Listing the restore points reads them from communal storage, so we need
+	// the same config parameters that are used to access it.
+	gen := config.ConfigParamsGenerator{
+		VRec: s.VRec,
+		Log:  s.Log,
+		Vdb:  s.Vdb,
+	}
+	if res, err := gen.ConstructConfigParms(ctx); verrors.IsReconcileAborted(res, err) {
+		if err == nil {
+			err = fmt.Errorf("communal access config parameters are not available")
+		}
+		return err
+	}
+	restorePoints, err := dispatcher.ShowRestorePoints(ctx,
+		showrestorepoints.WithInitiator(s.Vrps.ExtractNamespacedName(), hostIP),
+		showrestorepoints.WithCommunalPath(s.Vdb.GetCommunalPath()),
+		showrestorepoints.WithConfigurationParams(gen.ConfigurationParams.GetMap()),
+		showrestorepoints.WithArchiveNameFilter(s.Vrps.Spec.ArchiveName),
+	)
+	if err != nil {
+		return err
+	}
+	for _, rp := range getRestorePointsToPrune(restorePoints, s.Vrps.Spec.ArchiveName, s.Vrps.Spec.NumRestorePoints) {
+		s.Log.Info("Removing restore point beyond the retention limit", "archive", rp.Archive,
+			"id", rp.ID, "index", rp.Index, "timestamp", rp.Timestamp)
+		err = dispatcher.RemoveRestorePoint(ctx,
+			removerestorepoint.WithInitiator(hostIP),
+			removerestorepoint.WithArchiveName(rp.Archive),
+			removerestorepoint.WithIndex(rp.Index),
+			removerestorepoint.WithSandbox(vapi.MainCluster),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to remove restore point %s at index %d: %w", rp.ID, rp.Index, err)
+		}
+	}
+	return nil
+}
+
+// getRestorePointsToPrune returns the restore points in the archive that go
+// beyond the retention limit, oldest first. A lower index is more recent.
+// Restore points are removed by index, and removing one only shifts the
+// indexes of those older than it, so removing the oldest first keeps the
+// indexes of the remaining ones valid.
+func getRestorePointsToPrune(restorePoints []vops.RestorePoint, archive string, limit int) []vops.RestorePoint {
+	inArchive := []vops.RestorePoint{}
+	for i := range restorePoints {
+		if restorePoints[i].Archive == archive {
+			inArchive = append(inArchive, restorePoints[i])
+		}
+	}
+	if limit <= 0 || len(inArchive) <= limit {
+		return nil
+	}
+	sort.Slice(inArchive, func(i, j int) bool {
+		return inArchive[i].Index > inArchive[j].Index
+	})
+	return inArchive[:len(inArchive)-limit]
+}
+
+// updateScheduleStatus sets the state and the next scheduled time. A zero
+// next time clears it from the status.
+func (s *ScheduleReconciler) updateScheduleStatus(ctx context.Context, state string, next time.Time) error {
+	return vk8s.UpdateStatus(ctx, s.VRec.Client, s.Log, s.Vrps, func(vrps *v1beta1.VerticaRestorePointSchedule) error {
+		vrps.Status.State = state
+		if next.IsZero() {
+			vrps.Status.NextScheduleTime = nil
+		} else {
+			vrps.Status.NextScheduleTime = &metav1.Time{Time: next}
+		}
+		return nil
+	})
+}
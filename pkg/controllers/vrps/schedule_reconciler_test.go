@@ -0,0 +1,136 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vrps
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	vops "github.com/vertica/vcluster/vclusterops"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	v1beta1 "github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/cron"
+	"github.com/vertica/vertica-kubernetes/pkg/mockvops"
+	"github.com/vertica/vertica-kubernetes/pkg/test"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin"
+	"github.com/vertica/vertica-kubernetes/pkg/vk8s"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("schedule_reconcile", func() {
+	ctx := context.Background()
+
+	It("should be a no-op if the schedule isn't ready", func() {
+		vrps := v1beta1.MakeVrps()
+		Expect(k8sClient.Create(ctx, vrps)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vrps)).Should(Succeed()) }()
+
+		recon := MakeScheduleReconciler(vrpsRec, vrps, logger)
+		Expect(recon.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(vrps.Status.NextScheduleTime).Should(BeNil())
+	})
+
+	It("should requeue until the next scheduled time", func() {
+		vrps := v1beta1.MakeVrps()
+		vrps.Spec.Schedule = "@yearly"
+		Expect(k8sClient.Create(ctx, vrps)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vrps)).Should(Succeed()) }()
+		setScheduleReady(ctx, vrps)
+
+		recon := MakeScheduleReconciler(vrpsRec, vrps, logger)
+		res, err := recon.Reconcile(ctx, &ctrl.Request{})
+		Expect(err).Should(Succeed())
+		Expect(res.RequeueAfter).Should(BeNumerically(">", 0))
+		Expect(vrps.Status.State).Should(Equal(stateScheduled))
+		Expect(vrps.Status.NextScheduleTime).ShouldNot(BeNil())
+		Expect(vrps.Status.NextScheduleTime.Month()).Should(Equal(time.January))
+	})
+
+	It("should clear the next scheduled time when suspended", func() {
+		vrps := v1beta1.MakeVrps()
+		vrps.Spec.Suspend = true
+		Expect(k8sClient.Create(ctx, vrps)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vrps)).Should(Succeed()) }()
+		setScheduleReady(ctx, vrps)
+
+		recon := MakeScheduleReconciler(vrpsRec, vrps, logger)
+		Expect(recon.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(vrps.Status.State).Should(Equal(stateSuspended))
+		Expect(vrps.Status.NextScheduleTime).Should(BeNil())
+	})
+
+	It("should compute the next run from the last scheduled time", func() {
+		vrps := v1beta1.MakeVrps()
+		vrps.CreationTimestamp = metav1.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC)
+		r := &ScheduleReconciler{Vrps: vrps}
+		sched, err := cron.Parse(vrps.Spec.Schedule)
+		Expect(err).Should(Succeed())
+		Expect(r.getNextRunTime(sched)).Should(Equal(time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC)))
+
+		vrps.Status.LastScheduleTime = &metav1.Time{Time: time.Date(2025, 3, 14, 0, 0, 5, 0, time.UTC)}
+		Expect(r.getNextRunTime(sched)).Should(Equal(time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)))
+	})
+
+	It("should record the outcome of a scheduled restore point", func() {
+		vdb := vapi.MakeVDB()
+		secretName := "tls-1"
+		vdb.Spec.HTTPSNMATLS.Secret = secretName
+		setupAPIFunc := func(logr.Logger, string) (vadmin.VClusterProvider, logr.Logger) {
+			return &mockvops.MockVClusterOps{}, logr.Logger{}
+		}
+		dispatcher := mockvops.MakeMockVClusterOpsDispatcher(vdb, logger, k8sClient, setupAPIFunc)
+		test.CreateFakeTLSSecret(ctx, dispatcher.VDB, k8sClient, secretName)
+		defer test.DeleteSecret(ctx, k8sClient, secretName)
+
+		vrps := v1beta1.MakeVrps()
+		Expect(k8sClient.Create(ctx, vrps)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vrps)).Should(Succeed()) }()
+
+		r := &ScheduleReconciler{VRec: vrpsRec, Vrps: vrps, Log: logger, Vdb: vdb}
+		now := time.Now().UTC()
+		Expect(r.runSaveRestorePoint(ctx, dispatcher, "10.10.10.10", now)).Should(Succeed())
+		Expect(vrps.Status.LastScheduleTime).ShouldNot(BeNil())
+		Expect(vrps.Status.LastSuccessfulTime).ShouldNot(BeNil())
+		Expect(vrps.IsStatusConditionFalse(v1beta1.SavingRestorePoint)).Should(BeTrue())
+		Expect(vrps.IsStatusConditionTrue(v1beta1.LastRestorePointSaved)).Should(BeTrue())
+	})
+
+	It("should prune the oldest restore points beyond the limit", func() {
+		restorePoints := []vops.RestorePoint{
+			{Archive: "db", ID: "id-1", Index: 1},
+			{Archive: "db", ID: "id-4", Index: 4},
+			{Archive: "other", ID: "id-9", Index: 5},
+			{Archive: "db", ID: "id-2", Index: 2},
+			{Archive: "db", ID: "id-3", Index: 3},
+		}
+		Expect(getRestorePointsToPrune(restorePoints, "db", 0)).Should(BeEmpty())
+		Expect(getRestorePointsToPrune(restorePoints, "db", 4)).Should(BeEmpty())
+		Expect(getRestorePointsToPrune(restorePoints, "db", 2)).Should(Equal([]vops.RestorePoint{
+			{Archive: "db", ID: "id-4", Index: 4},
+			{Archive: "db", ID: "id-3", Index: 3},
+		}))
+	})
+})
+
+func setScheduleReady(ctx context.Context, vrps *v1beta1.VerticaRestorePointSchedule) {
+	Expect(vk8s.UpdateConditions(ctx, k8sClient, logger, vrps,
+		[]*metav1.Condition{vapi.MakeCondition(v1beta1.ScheduleReady, metav1.ConditionTrue, "Verified")},
+		stateScheduled)).Should(Succeed())
+}
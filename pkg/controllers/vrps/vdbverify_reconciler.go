@@ -0,0 +1,99 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vrps
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	v1beta1 "github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	verrors "github.com/vertica/vertica-kubernetes/pkg/errors"
+	"github.com/vertica/vertica-kubernetes/pkg/events"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/vk8s"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const stateIncompatibleDB = "Incompatible"
+
+type VdbVerifyReconciler struct {
+	VRec *VerticaRestorePointScheduleReconciler
+	Vrps *v1beta1.VerticaRestorePointSchedule
+	Log  logr.Logger
+}
+
+func MakeVdbVerifyReconciler(r *VerticaRestorePointScheduleReconciler, vrps *v1beta1.VerticaRestorePointSchedule,
+	log logr.Logger) controllers.ReconcileActor {
+	return &VdbVerifyReconciler{
+		VRec: r,
+		Vrps: vrps,
+		Log:  log.WithName("VdbVerifyReconciler"),
+	}
+}
+
+// Reconcile will verify the VerticaDB in the Vrps CR exists, is an Eon mode
+// database deployed with vclusterops, and runs a vertica version that can
+// save restore points. Unlike a one-shot query, this is checked on every
+// iteration because the VerticaDB can change over the life of the schedule.
+func (v *VdbVerifyReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (ctrl.Result, error) {
+	vdb := &vapi.VerticaDB{}
+	nm := names.GenNamespacedName(v.Vrps, v.Vrps.Spec.VerticaDBName)
+	if res, err := vk8s.FetchVDB(ctx, v.VRec, v.Vrps, nm, vdb); verrors.IsReconcileAborted(res, err) {
+		return res, err
+	}
+
+	if !vdb.UseVClusterOpsDeployment() {
+		return v.setNotReady(ctx, events.VrpsAdmintoolsNotSupported, "AdmintoolsNotSupported",
+			"Scheduled restore points are not supported for admintools deployments")
+	}
+
+	if !vdb.IsEON() {
+		return v.setNotReady(ctx, events.InDBSaveRestorePointNotSupported, "EnterpriseNotSupported",
+			"Scheduled restore points are only supported for Eon mode databases")
+	}
+
+	vinf, err := vdb.MakeVersionInfoCheck()
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !vinf.IsEqualOrNewer(vapi.SaveRestorePointNMAOpsMinVersion) {
+		return v.setNotReady(ctx, events.UnsupportedVerticaVersion, "IncompatibleDB",
+			fmt.Sprintf("The Vertica version %q doesn't support saving restore points. The minimum version supported is %s.",
+				vinf.VdbVer, vapi.SaveRestorePointNMAOpsMinVersion))
+	}
+
+	if v.Vrps.IsStatusConditionTrue(v1beta1.ScheduleReady) {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{}, vk8s.UpdateConditions(ctx, v.VRec.Client, v.Log, v.Vrps,
+		[]*metav1.Condition{vapi.MakeCondition(v1beta1.ScheduleReady, metav1.ConditionTrue, "Verified")},
+		stateScheduled)
+}
+
+// setNotReady will log an event and set the ScheduleReady condition to false
+func (v *VdbVerifyReconciler) setNotReady(ctx context.Context, eventReason, condReason, msg string) (ctrl.Result, error) {
+	if !v.Vrps.IsStatusConditionFalse(v1beta1.ScheduleReady) {
+		v.VRec.Event(v.Vrps, corev1.EventTypeWarning, eventReason, msg)
+	}
+	return ctrl.Result{}, vk8s.UpdateConditions(ctx, v.VRec.Client, v.Log, v.Vrps,
+		[]*metav1.Condition{vapi.MakeCondition(v1beta1.ScheduleReady, metav1.ConditionFalse, condReason)},
+		stateIncompatibleDB)
+}
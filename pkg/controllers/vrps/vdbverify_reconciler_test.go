@@ -0,0 +1,92 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vrps
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	v1beta1 "github.com/vertica/vertica-kubernetes/api/v1beta1"
+	vmeta "github.com/vertica/vertica-kubernetes/pkg/meta"
+
+	"github.com/vertica/vertica-kubernetes/pkg/test"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("vdbverify_reconcile", func() {
+	ctx := context.Background()
+
+	It("should requeue if VerticaDB doesn't exist", func() {
+		vrps := v1beta1.MakeVrps()
+		Expect(k8sClient.Create(ctx, vrps)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vrps)).Should(Succeed()) }()
+
+		req := ctrl.Request{NamespacedName: v1beta1.MakeSampleVrpsName()}
+		Expect(vrpsRec.Reconcile(ctx, req)).Should(Equal(ctrl.Result{Requeue: true}))
+	})
+
+	It("should set the scheduleReady condition to false with admintools", func() {
+		vdb := vapi.MakeVDB()
+		vdb.Annotations[vmeta.VersionAnnotation] = "v24.4.0"
+		test.CreateVDB(ctx, k8sClient, vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+
+		vrps := v1beta1.MakeVrps()
+		Expect(k8sClient.Create(ctx, vrps)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vrps)).Should(Succeed()) }()
+		recon := MakeVdbVerifyReconciler(vrpsRec, vrps, logger)
+		result, err := recon.Reconcile(ctx, &ctrl.Request{})
+		Expect(err).Should(Succeed())
+		Expect(result).Should(Equal(ctrl.Result{}))
+		Expect(vrps.IsStatusConditionFalse(v1beta1.ScheduleReady)).Should(BeTrue())
+		Expect(vrps.Status.Conditions[0].Reason).Should(Equal("AdmintoolsNotSupported"))
+		Expect(vrps.Status.State).Should(Equal(stateIncompatibleDB))
+	})
+
+	It("should set the scheduleReady condition to false for an old vertica version", func() {
+		vdb := vapi.MakeVDB()
+		vdb.Annotations[vmeta.VClusterOpsAnnotation] = vmeta.VClusterOpsAnnotationTrue
+		vdb.Annotations[vmeta.VersionAnnotation] = "v24.2.0"
+		test.CreateVDB(ctx, k8sClient, vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+
+		vrps := v1beta1.MakeVrps()
+		Expect(k8sClient.Create(ctx, vrps)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vrps)).Should(Succeed()) }()
+		recon := MakeVdbVerifyReconciler(vrpsRec, vrps, logger)
+		Expect(recon.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(vrps.IsStatusConditionFalse(v1beta1.ScheduleReady)).Should(BeTrue())
+		Expect(vrps.Status.Conditions[0].Reason).Should(Equal("IncompatibleDB"))
+	})
+
+	It("should set the scheduleReady condition to true for a compatible database", func() {
+		vdb := vapi.MakeVDB()
+		vdb.Annotations[vmeta.VClusterOpsAnnotation] = vmeta.VClusterOpsAnnotationTrue
+		vdb.Annotations[vmeta.VersionAnnotation] = "v24.4.0"
+		test.CreateVDB(ctx, k8sClient, vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+
+		vrps := v1beta1.MakeVrps()
+		Expect(k8sClient.Create(ctx, vrps)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vrps)).Should(Succeed()) }()
+		recon := MakeVdbVerifyReconciler(vrpsRec, vrps, logger)
+		Expect(recon.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(vrps.IsStatusConditionTrue(v1beta1.ScheduleReady)).Should(BeTrue())
+		Expect(vrps.Status.State).Should(Equal(stateScheduled))
+	})
+})
@@ -0,0 +1,209 @@
+/*
+Copyright [2021-2024] Open Text.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vrps
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/go-logr/logr"
+	v1vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/cache"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	verrors "github.com/vertica/vertica-kubernetes/pkg/errors"
+	"github.com/vertica/vertica-kubernetes/pkg/events"
+	"github.com/vertica/vertica-kubernetes/pkg/meta"
+)
+
+const (
+	vdbNameField = ".spec.verticaDBName"
+)
+
+// VerticaRestorePointScheduleReconciler reconciles a VerticaRestorePointSchedule object
+type VerticaRestorePointScheduleReconciler struct {
+	client.Client
+	Scheme       *runtime.Scheme
+	Log          logr.Logger
+	Cfg          *rest.Config
+	EVRec        record.EventRecorder
+	Concurrency  int
+	CacheManager cache.CacheManager
+}
+
+// +kubebuilder:rbac:groups=vertica.com,resources=verticarestorepointschedules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=vertica.com,resources=verticarestorepointschedules/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=vertica.com,resources=verticarestorepointschedules/finalizers,verbs=update
+
+// Reconcile will save a restore point whenever the schedule of the
+// VerticaRestorePointSchedule is due. In between runs it requeues itself for
+// the next scheduled time.
+func (r *VerticaRestorePointScheduleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("vrps", req.NamespacedName)
+	log.Info("starting reconcile of VerticaRestorePointSchedule")
+
+	vrps := &vapi.VerticaRestorePointSchedule{}
+	err := r.Get(ctx, req.NamespacedName, vrps)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Request object not found, cound have been deleted after reconcile request.
+			log.Info("VerticaRestorePointSchedule resource not found.  Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "failed to get VerticaRestorePointSchedule")
+		return ctrl.Result{}, err
+	}
+
+	if meta.IsPauseAnnotationSet(vrps.Annotations) {
+		log.Info(fmt.Sprintf("The pause annotation %s is set. Suspending the iteration", meta.PauseOperatorAnnotation),
+			"result", ctrl.Result{}, "err", nil)
+		return ctrl.Result{}, nil
+	}
+
+	// Iterate over each actor
+	actors := r.constructActors(vrps, log)
+	var res ctrl.Result
+	for _, act := range actors {
+		log.Info("starting actor", "name", fmt.Sprintf("%T", act))
+		res, err = act.Reconcile(ctx, &req)
+		// Error or a request to requeue will stop the reconciliation.
+		if verrors.IsReconcileAborted(res, err) {
+			log.Info("aborting reconcile of VerticaRestorePointSchedule", "result", res, "err", err)
+			return res, err
+		}
+	}
+
+	log.Info("ending reconcile of VerticaRestorePointSchedule", "result", res, "err", err)
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *VerticaRestorePointScheduleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := r.setupFieldIndexer(mgr.GetFieldIndexer()); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&vapi.VerticaRestorePointSchedule{}).
+		// Watch the VerticaDB so that a schedule that was blocked, because
+		// the VerticaDB wasn't ready or compatible, is picked up again.
+		Watches(
+			&v1vapi.VerticaDB{},
+			handler.EnqueueRequestsFromMapFunc(r.findObjectsForVerticaDB),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.Concurrency}).
+		Complete(r)
+}
+
+// setupFieldIndexer will setup an index over the VerticaDB name. This allows
+// us to lookup the schedules that refer to a VerticaDB.
+func (r *VerticaRestorePointScheduleReconciler) setupFieldIndexer(indx client.FieldIndexer) error {
+	return indx.IndexField(context.Background(), &vapi.VerticaRestorePointSchedule{}, vdbNameField,
+		func(rawObj client.Object) []string {
+			return []string{rawObj.(*vapi.VerticaRestorePointSchedule).Spec.VerticaDBName}
+		})
+}
+
+// findObjectsForVerticaDB will generate requests to reconcile
+// VerticaRestorePointSchedules based on watched VerticaDB.
+func (r *VerticaRestorePointScheduleReconciler) findObjectsForVerticaDB(ctx context.Context,
+	vdb client.Object) []reconcile.Request {
+	schedules := &vapi.VerticaRestorePointScheduleList{}
+	listOps := &client.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector(vdbNameField, vdb.GetName()),
+		Namespace:     vdb.GetNamespace(),
+	}
+	err := r.List(ctx, schedules, listOps)
+	if err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, len(schedules.Items))
+	for i := range schedules.Items {
+		requests[i] = reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      schedules.Items[i].GetName(),
+				Namespace: schedules.Items[i].GetNamespace(),
+			},
+		}
+	}
+	return requests
+}
+
+// constructActors will a list of actors that should be run for the reconcile.
+// Order matters in that some actors depend on the successeful execution of
+// earlier ones.
+func (r *VerticaRestorePointScheduleReconciler) constructActors(vrps *vapi.VerticaRestorePointSchedule,
+	log logr.Logger) []controllers.ReconcileActor {
+	// The actors that will be applied, in sequence, to reconcile a vrps.
+	actors := []controllers.ReconcileActor{
+		// Verify the VerticaDB supports saving restore points
+		MakeVdbVerifyReconciler(r, vrps, log),
+		// Save a restore point when the schedule is due
+		MakeScheduleReconciler(r, vrps, log),
+	}
+	return actors
+}
+
+// Event a wrapper for Event() that also writes a log entry
+func (r *VerticaRestorePointScheduleReconciler) Event(vrps runtime.Object, eventtype, reason, message string) {
+	evWriter := events.Writer{
+		Log:   r.Log,
+		EVRec: r.EVRec,
+	}
+	evWriter.Event(vrps, eventtype, reason, message)
+}
+
+// Eventf is a wrapper for Eventf() that also writes a log entry
+func (r *VerticaRestorePointScheduleReconciler) Eventf(vrps runtime.Object, eventtype, reason, messageFmt string,
+	args ...interface{}) {
+	evWriter := events.Writer{
+		Log:   r.Log,
+		EVRec: r.EVRec,
+	}
+	evWriter.Eventf(vrps, eventtype, reason, messageFmt, args...)
+}
+
+// GetClient gives access to the Kubernetes client
+func (r *VerticaRestorePointScheduleReconciler) GetClient() client.Client {
+	return r.Client
+}
+
+// GetEventRecorder gives access to the event recorder
+func (r *VerticaRestorePointScheduleReconciler) GetEventRecorder() record.EventRecorder {
+	return r.EVRec
+}
+
+// GetConfig gives access to *rest.Config
+func (r *VerticaRestorePointScheduleReconciler) GetConfig() *rest.Config {
+	return r.Cfg
+}
@@ -0,0 +1,110 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vscr
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	v1 "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/builder"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	"github.com/vertica/vertica-kubernetes/pkg/events"
+	"github.com/vertica/vertica-kubernetes/pkg/paths"
+	"github.com/vertica/vertica-kubernetes/pkg/vk8s"
+	"github.com/vertica/vertica-kubernetes/pkg/vscrstatus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// AnalysisPollingReconciler waits for the cluster health analysis, that runs
+// after the scrutinize collection, to finish
+type AnalysisPollingReconciler struct {
+	VRec *VerticaScrutinizeReconciler
+	Vscr *v1beta1.VerticaScrutinize
+	Log  logr.Logger
+}
+
+func MakeAnalysisPollingReconciler(r *VerticaScrutinizeReconciler, vscr *v1beta1.VerticaScrutinize,
+	log logr.Logger) controllers.ReconcileActor {
+	return &AnalysisPollingReconciler{
+		VRec: r,
+		Vscr: vscr,
+		Log:  log.WithName("AnalysisPollingReconciler"),
+	}
+}
+
+func (a *AnalysisPollingReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (ctrl.Result, error) {
+	// no-op if the scrutinize collection did not succeed or the
+	// analysis is already done
+	cond := a.Vscr.FindStatusCondition(v1beta1.ScrutinizeCollectionFinished)
+	if cond == nil || cond.Reason != events.VclusterOpsScrutinizeSucceeded ||
+		a.Vscr.IsStatusConditionPresent(v1beta1.ScrutinizeAnalysisFinished) {
+		return ctrl.Result{}, nil
+	}
+
+	pod := &corev1.Pod{}
+	if err := a.VRec.Client.Get(ctx, a.Vscr.ExtractNamespacedName(), pod); err != nil {
+		if errors.IsNotFound(err) {
+			a.Log.Info("Scrutinize pod not found.")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	cnt := vk8s.GetScrutinizeAnalysisContainer(pod.Spec.InitContainers)
+	if cnt == nil {
+		// the analysis was not enabled when the pod was created
+		return ctrl.Result{}, nil
+	}
+	return a.checkAnalysisContainerStatus(ctx, pod, cnt)
+}
+
+// checkAnalysisContainerStatus checks the status of the analysis container
+// and updates the status with the report name once it is done
+func (a *AnalysisPollingReconciler) checkAnalysisContainerStatus(ctx context.Context, pod *corev1.Pod,
+	cnt *corev1.Container) (ctrl.Result, error) {
+	cntStatus := vk8s.FindScrutinizeAnalysisContainerStatus(pod)
+	if cntStatus == nil || cntStatus.State.Terminated == nil {
+		a.Log.Info("Waiting for the cluster health analysis to finish")
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	stat := a.Vscr.Status.DeepCopy()
+	// The analysis container always exits successfully so that it does not
+	// block the main container. A failure is reported through its
+	// termination message.
+	term := cntStatus.State.Terminated
+	if term.ExitCode != 0 || term.Message != "" {
+		a.VRec.Eventf(a.Vscr, corev1.EventTypeWarning, events.ScrutinizeAnalysisFailed,
+			"Cluster health analysis failed for the VerticaDB named '%s'. Check %s in the scrutinize pod for details",
+			a.Vscr.Spec.VerticaDBName, paths.ScrutinizeAnalysisLogFile)
+		cond := v1.MakeCondition(v1beta1.ScrutinizeAnalysisFinished, metav1.ConditionTrue, events.ScrutinizeAnalysisFailed)
+		stat.Conditions = []metav1.Condition{*cond}
+		return ctrl.Result{}, vscrstatus.UpdateStatus(ctx, a.VRec.Client, a.Vscr, stat)
+	}
+
+	stat.AnalysisReportName = builder.GetAnalysisReportName(cnt.Command)
+	a.VRec.Eventf(a.Vscr, corev1.EventTypeNormal, events.ScrutinizeAnalysisSucceeded,
+		"Successfully completed the cluster health analysis for the VerticaDB named '%s'. Report: %s",
+		a.Vscr.Spec.VerticaDBName, stat.AnalysisReportName)
+	cond := v1.MakeCondition(v1beta1.ScrutinizeAnalysisFinished, metav1.ConditionTrue, events.ScrutinizeAnalysisSucceeded)
+	stat.Conditions = []metav1.Condition{*cond}
+	return ctrl.Result{}, vscrstatus.UpdateStatus(ctx, a.VRec.Client, a.Vscr, stat)
+}
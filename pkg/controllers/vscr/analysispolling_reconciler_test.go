@@ -0,0 +1,110 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vscr
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/builder"
+	"github.com/vertica/vertica-kubernetes/pkg/events"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/v1beta1_test"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("analysispolling_reconciler", func() {
+	ctx := context.Background()
+
+	It("should set the report name once the analysis container is done", func() {
+		vscr := v1beta1.MakeVscr()
+		v1beta1_test.CreateVSCR(ctx, k8sClient, vscr)
+		defer v1beta1_test.DeleteVSCR(ctx, k8sClient, vscr)
+		cond := v1.MakeCondition(v1beta1.ScrutinizeCollectionFinished, metav1.ConditionTrue,
+			events.VclusterOpsScrutinizeSucceeded)
+		meta.SetStatusCondition(&vscr.Status.Conditions, *cond)
+
+		pod := builder.BuildScrutinizePod(vscr, v1.MakeVDB(), []string{"--tarball-name", "test"},
+			[]string{"--output-file", "/tmp/scrutinize/test.analysis.json"})
+		Expect(k8sClient.Create(ctx, pod)).Should(Succeed())
+		defer v1beta1_test.DeleteScrutinizePod(ctx, k8sClient, vscr)
+
+		// analysis still running
+		pod.Status.InitContainerStatuses = []corev1.ContainerStatus{
+			{Name: names.ScrutinizeInitContainer, Ready: true},
+			{Name: names.ScrutinizeAnalysisContainer, State: corev1.ContainerState{
+				Running: &corev1.ContainerStateRunning{},
+			}},
+		}
+		Expect(k8sClient.Status().Update(ctx, pod)).Should(Succeed())
+		runAnalysisPollingReconcile(ctx, vscr, true)
+		Expect(vscr.IsStatusConditionPresent(v1beta1.ScrutinizeAnalysisFinished)).Should(BeFalse())
+
+		// analysis failed, reported through the termination message
+		pod.Status.InitContainerStatuses[1].State = corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{Message: builder.ScrutinizeAnalysisFailedMsg},
+		}
+		Expect(k8sClient.Status().Update(ctx, pod)).Should(Succeed())
+		runAnalysisPollingReconcile(ctx, vscr, false)
+		cond = vscr.FindStatusCondition(v1beta1.ScrutinizeAnalysisFinished)
+		Expect(cond).ShouldNot(BeNil())
+		Expect(cond.Reason).Should(Equal(events.ScrutinizeAnalysisFailed))
+		Expect(vscr.Status.AnalysisReportName).Should(BeEmpty())
+
+		// analysis succeeded
+		meta.RemoveStatusCondition(&vscr.Status.Conditions, v1beta1.ScrutinizeAnalysisFinished)
+		pod.Status.InitContainerStatuses[1].State = corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{},
+		}
+		Expect(k8sClient.Status().Update(ctx, pod)).Should(Succeed())
+		runAnalysisPollingReconcile(ctx, vscr, false)
+		cond = vscr.FindStatusCondition(v1beta1.ScrutinizeAnalysisFinished)
+		Expect(cond).ShouldNot(BeNil())
+		Expect(cond.Reason).Should(Equal(events.ScrutinizeAnalysisSucceeded))
+		Expect(vscr.Status.AnalysisReportName).Should(Equal("test.analysis.json"))
+	})
+
+	It("should be a no-op if the analysis was not enabled", func() {
+		vscr := v1beta1.MakeVscr()
+		v1beta1_test.CreateVSCR(ctx, k8sClient, vscr)
+		defer v1beta1_test.DeleteVSCR(ctx, k8sClient, vscr)
+		cond := v1.MakeCondition(v1beta1.ScrutinizeCollectionFinished, metav1.ConditionTrue,
+			events.VclusterOpsScrutinizeSucceeded)
+		meta.SetStatusCondition(&vscr.Status.Conditions, *cond)
+		v1beta1_test.CreateScrutinizePod(ctx, k8sClient, vscr)
+		defer v1beta1_test.DeleteScrutinizePod(ctx, k8sClient, vscr)
+
+		runAnalysisPollingReconcile(ctx, vscr, false)
+		Expect(vscr.IsStatusConditionPresent(v1beta1.ScrutinizeAnalysisFinished)).Should(BeFalse())
+	})
+})
+
+func runAnalysisPollingReconcile(ctx context.Context, vscr *v1beta1.VerticaScrutinize, requeue bool) {
+	r := MakeAnalysisPollingReconciler(vscrRec, vscr, logger)
+	res, err := r.Reconcile(ctx, &ctrl.Request{})
+	Expect(err).Should(Succeed())
+	if requeue {
+		Expect(res).Should(Equal(ctrl.Result{Requeue: true}))
+	} else {
+		Expect(res).Should(Equal(ctrl.Result{}))
+	}
+}
@@ -17,6 +17,7 @@ package vscr
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 	"strings"
 	"time"
@@ -27,6 +28,7 @@ import (
 	"github.com/vertica/vertica-kubernetes/pkg/builder"
 	"github.com/vertica/vertica-kubernetes/pkg/controllers"
 	verrors "github.com/vertica/vertica-kubernetes/pkg/errors"
+	"github.com/vertica/vertica-kubernetes/pkg/events"
 	"github.com/vertica/vertica-kubernetes/pkg/iter"
 	vmeta "github.com/vertica/vertica-kubernetes/pkg/meta"
 	"github.com/vertica/vertica-kubernetes/pkg/names"
@@ -39,6 +41,10 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
+// analysisTimeFmt is the time format, in UTC, that the cluster_health
+// command expects for its start and end time
+const analysisTimeFmt = "2006-01-02 15:04:05"
+
 type ScrutinizeCmdArgs struct {
 	hosts       []string
 	username    string
@@ -121,7 +127,7 @@ func (s *ScrutinizePodReconciler) collectInfoFromVdb(ctx context.Context) (ctrl.
 // createPod creates the scrutinize pod
 func (s *ScrutinizePodReconciler) createPod(ctx context.Context) error {
 	s.ScrArgs.tarballName = generateScrutinizeID()
-	pod := builder.BuildScrutinizePod(s.Vscr, s.Vdb, s.buildScrutinizeCmdArgs(s.Vdb))
+	pod := builder.BuildScrutinizePod(s.Vscr, s.Vdb, s.buildScrutinizeCmdArgs(s.Vdb), s.buildAnalysisCmdArgs(s.Vdb))
 	s.Log.Info("Creating scrutinize pod", "Name", s.Vscr.ExtractNamespacedName())
 	err := ctrl.SetControllerReference(s.Vscr, pod, s.VRec.Scheme)
 	if err != nil {
@@ -178,6 +184,41 @@ func (s *ScrutinizePodReconciler) buildScrutinizeCmdArgs(vdb *v1.VerticaDB) []st
 		}
 	}
 
+	return appendPasswordArgs(cmd, vdb)
+}
+
+// buildAnalysisCmdArgs returns the arguments of the vcluster cluster_health
+// command, which runs the slow event and lock analyses over the same time
+// window that scrutinize collects logs for. It returns nil if the analysis
+// is not enabled or cannot run.
+func (s *ScrutinizePodReconciler) buildAnalysisCmdArgs(vdb *v1.VerticaDB) []string {
+	if !vmeta.IsScrutinizeAnalysisEnabled(s.Vscr.Annotations) {
+		return nil
+	}
+	// Unlike scrutinize, cluster_health is not able to fetch the password
+	// from a secret store outside of k8s.
+	if vdb.GetPasswordSecret() != "" && !secrets.IsK8sSecret(vdb.GetPasswordSecret()) {
+		s.VRec.Eventf(s.Vscr, corev1.EventTypeWarning, events.ScrutinizeAnalysisSkipped,
+			"Skipping the cluster health analysis as the password secret '%s' is not stored in Kubernetes",
+			vdb.GetPasswordSecret())
+		return nil
+	}
+	oldest, newest := s.Vscr.GetLogAgeWindow(time.Now())
+	cmd := []string{
+		"--db-name", vdb.Spec.DBName,
+		"--db-user", s.ScrArgs.username,
+		"--hosts", strings.Join(s.ScrArgs.hosts, ","),
+		"--log-path", paths.ScrutinizeAnalysisLogFile,
+		"--start-time", oldest.Format(analysisTimeFmt),
+		"--end-time", newest.Format(analysisTimeFmt),
+		"--output-file", fmt.Sprintf("%s/%s.analysis.json", paths.ScrutinizeTmp, s.ScrArgs.tarballName),
+	}
+	return appendPasswordArgs(cmd, vdb)
+}
+
+// appendPasswordArgs adds the password flag, shared by the scrutinize
+// and the analysis commands, to the given args
+func appendPasswordArgs(cmd []string, vdb *v1.VerticaDB) []string {
 	// if there is no password, we need to explicitly
 	// set the password flag with empty string as value,
 	// to still assume password as the authentication method
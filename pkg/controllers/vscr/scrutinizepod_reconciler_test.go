@@ -17,6 +17,7 @@ package vscr
 
 import (
 	"context"
+	"fmt"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -163,6 +164,37 @@ var _ = Describe("scrutinizepod_reconciler", func() {
 		Expect(args).Should(ContainElement(ContainSubstring("--log-age-newest-time")))
 	})
 
+	It("should only build the analysis args when the analysis is enabled", func() {
+		vdb := v1.MakeVDB()
+		s := &ScrutinizePodReconciler{
+			VRec: vscrRec,
+			ScrArgs: &ScrutinizeCmdArgs{
+				hosts:       []string{"h1", "h2"},
+				username:    "dbadmin",
+				tarballName: "VerticaScrutinize.20240101120000",
+			},
+		}
+		s.Vscr = v1beta1.MakeVscr()
+		Expect(s.buildAnalysisCmdArgs(vdb)).Should(BeEmpty())
+
+		s.Vscr.Annotations[vmeta.ScrutinizeAnalysisAnnotation] = "true"
+		s.Vscr.Annotations[vmeta.ScrutinizeLogAgeHours] = "8"
+		args := s.buildAnalysisCmdArgs(vdb)
+		Expect(args).Should(ContainElements("--db-name", vdb.Spec.DBName, "--hosts", "h1,h2",
+			"--start-time", "--end-time", "--password="))
+		Expect(args).Should(ContainElement(
+			fmt.Sprintf("%s/VerticaScrutinize.20240101120000.analysis.json", paths.ScrutinizeTmp)))
+
+		// cluster_health cannot read the password from an external secret store
+		vdb.Spec.PasswordSecret = "gsm://secret"
+		vdb.Status.PasswordSecret = &vdb.Spec.PasswordSecret
+		Expect(s.buildAnalysisCmdArgs(vdb)).Should(BeEmpty())
+
+		vdb.Spec.PasswordSecret = "test-secret"
+		vdb.Status.PasswordSecret = &vdb.Spec.PasswordSecret
+		Expect(s.buildAnalysisCmdArgs(vdb)).Should(ContainElements("--password-file", paths.ScrutinizeDBPasswordFile))
+	})
+
 	It("should create scrutinize pod for sandbox", func() {
 		vdb := v1.MakeVDBForScrutinize()
 
@@ -162,6 +162,7 @@ func (r *VerticaScrutinizeReconciler) constructActors(vscr *v1beta1.VerticaScrut
 		MakeVDBVerifyReconciler(r, vscr, log),
 		MakeScrutinizePodReconciler(r, vscr, log),
 		MakePodPollingReconciler(r, vscr, log),
+		MakeAnalysisPollingReconciler(r, vscr, log),
 	}
 }
 
@@ -0,0 +1,271 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vusr
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	v1beta1 "github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/cloud"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	"github.com/vertica/vertica-kubernetes/pkg/dbsql"
+	verrors "github.com/vertica/vertica-kubernetes/pkg/errors"
+	"github.com/vertica/vertica-kubernetes/pkg/events"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/vk8s"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	stateReady    = "Ready"
+	stateNotReady = "WaitingForDatabase"
+	stateFailed   = "Failed"
+
+	// The pool a user is assigned when the spec doesn't name one
+	defaultResourcePool = "general"
+	// How long to wait before checking again if the database is ready
+	dbNotReadyRequeueTime = 30 * time.Second
+)
+
+type UserReconciler struct {
+	VRec *VerticaUserReconciler
+	Vusr *v1beta1.VerticaUser
+	Log  logr.Logger
+	// A connection to the database. If nil, one is opened when needed. This
+	// is set by tests to mock the database.
+	Conn *sql.DB
+}
+
+func MakeUserReconciler(r *VerticaUserReconciler, vusr *v1beta1.VerticaUser,
+	log logr.Logger) controllers.ReconcileActor {
+	return &UserReconciler{
+		VRec: r,
+		Vusr: vusr,
+		Log:  log.WithName("UserReconciler"),
+	}
+}
+
+// Reconcile will create the user if it doesn't exist, then sync its password,
+// resource pool, roles and privileges with the spec. The user isn't dropped
+// when the VerticaUser is deleted.
+func (u *UserReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (ctrl.Result, error) {
+	vdb := &vapi.VerticaDB{}
+	nm := names.GenNamespacedName(u.Vusr, u.Vusr.Spec.VerticaDBName)
+	if res, err := vk8s.FetchVDB(ctx, u.VRec, u.Vusr, nm, vdb); verrors.IsReconcileAborted(res, err) {
+		return res, err
+	}
+
+	if !vdb.IsDBInitialized() {
+		if !u.Vusr.IsStatusConditionFalse(v1beta1.UserReady) {
+			u.VRec.Eventf(u.Vusr, corev1.EventTypeNormal, events.DatabaseNotReady,
+				"Waiting for the database in VerticaDB %q to be initialized", vdb.Name)
+		}
+		return ctrl.Result{RequeueAfter: dbNotReadyRequeueTime}, u.setCondition(ctx, metav1.ConditionFalse,
+			"DatabaseNotReady", stateNotReady)
+	}
+
+	if u.Conn == nil {
+		conn, err := dbsql.Open(ctx, u.VRec.Client, u.Log, u.VRec, vdb)
+		if err != nil {
+			u.Log.Info("failed to connect to the database, requeue", "err", err.Error())
+			return ctrl.Result{RequeueAfter: dbNotReadyRequeueTime}, nil
+		}
+		defer conn.Close()
+		u.Conn = conn
+	}
+
+	if err := u.syncUser(ctx); err != nil {
+		u.VRec.Eventf(u.Vusr, corev1.EventTypeWarning, events.UserUpdateFailed,
+			"Failed to update user %q: %s", u.Vusr.GetUserName(), err.Error())
+		if updErr := u.setCondition(ctx, metav1.ConditionFalse, "UpdateFailed", stateFailed); updErr != nil {
+			return ctrl.Result{}, updErr
+		}
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// syncUser will run all of the SQL needed to make the user match the spec,
+// then record what was applied in the status.
+func (u *UserReconciler) syncUser(ctx context.Context) error {
+	userName := u.Vusr.GetUserName()
+	exists, err := dbsql.UserExists(ctx, u.Conn, userName)
+	if err != nil {
+		return err
+	}
+
+	passwd, err := u.getPassword(ctx)
+	if err != nil {
+		return err
+	}
+	digest := u.getPasswordDigest(passwd)
+	pool := u.Vusr.Spec.ResourcePool
+	if pool == "" {
+		pool = defaultResourcePool
+	}
+
+	if !exists {
+		stmt := fmt.Sprintf("CREATE USER %s", dbsql.QuoteIdentifier(userName))
+		if passwd != "" {
+			stmt += fmt.Sprintf(" IDENTIFIED BY %s", dbsql.QuoteLiteral(passwd))
+		}
+		stmt += fmt.Sprintf(" RESOURCE POOL %s", dbsql.QuoteIdentifier(pool))
+		if _, err := u.Conn.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+		u.VRec.Eventf(u.Vusr, corev1.EventTypeNormal, events.UserCreated,
+			"Created user %q in VerticaDB %q", userName, u.Vusr.Spec.VerticaDBName)
+	} else {
+		if err := u.alterUser(ctx, userName, passwd, digest, pool); err != nil {
+			return err
+		}
+	}
+
+	if err := dbsql.SyncRoles(ctx, u.Conn, userName, u.Vusr.Status.GrantedRoles, u.Vusr.Spec.Roles); err != nil {
+		return err
+	}
+	if err := u.setDefaultRoles(ctx, userName); err != nil {
+		return err
+	}
+	if err := dbsql.SyncGrants(ctx, u.Conn, userName, u.Vusr.Status.AppliedGrants, u.Vusr.Spec.Grants); err != nil {
+		return err
+	}
+
+	if exists && u.isStatusOutOfDate(digest) {
+		u.VRec.Eventf(u.Vusr, corev1.EventTypeNormal, events.UserUpdated,
+			"Updated user %q in VerticaDB %q", userName, u.Vusr.Spec.VerticaDBName)
+	}
+	return vk8s.UpdateStatus(ctx, u.VRec.Client, u.Log, u.Vusr, func(vusr *v1beta1.VerticaUser) error {
+		secret := u.Vusr.Spec.PasswordSecret
+		vusr.Status.PasswordSecret = &secret
+		vusr.Status.PasswordDigest = digest
+		vusr.Status.ResourcePool = u.Vusr.Spec.ResourcePool
+		vusr.Status.GrantedRoles = u.Vusr.Spec.Roles
+		vusr.Status.AppliedGrants = u.Vusr.Spec.Grants
+		vusr.Status.State = stateReady
+		meta.SetStatusCondition(&vusr.Status.Conditions, *vapi.MakeCondition(v1beta1.UserReady, metav1.ConditionTrue, "Reconciled"))
+		return nil
+	})
+}
+
+// alterUser will change the password and resource pool of an existing user
+// if they differ from what was last applied.
+func (u *UserReconciler) alterUser(ctx context.Context, userName, passwd, digest, pool string) error {
+	// The password is set when the secret name changes or when the password
+	// stored in the secret changes. A user that existed before the
+	// VerticaUser keeps its password unless a secret is given.
+	prevSecret := u.Vusr.Status.PasswordSecret
+	if (prevSecret == nil && u.Vusr.Spec.PasswordSecret != "") ||
+		(prevSecret != nil && *prevSecret != u.Vusr.Spec.PasswordSecret) ||
+		(u.Vusr.Spec.PasswordSecret != "" && u.Vusr.Status.PasswordDigest != digest) {
+		stmt := fmt.Sprintf("ALTER USER %s IDENTIFIED BY %s", dbsql.QuoteIdentifier(userName), dbsql.QuoteLiteral(passwd))
+		if _, err := u.Conn.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to change the password: %w", err)
+		}
+	}
+	if u.Vusr.Status.ResourcePool != u.Vusr.Spec.ResourcePool || u.Vusr.Status.PasswordSecret == nil {
+		stmt := fmt.Sprintf("ALTER USER %s RESOURCE POOL %s", dbsql.QuoteIdentifier(userName), dbsql.QuoteIdentifier(pool))
+		if _, err := u.Conn.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to set the resource pool: %w", err)
+		}
+	}
+	return nil
+}
+
+// setDefaultRoles will make all of the granted roles default roles so they
+// are enabled when the user logs in.
+func (u *UserReconciler) setDefaultRoles(ctx context.Context, userName string) error {
+	roles := "NONE"
+	if len(u.Vusr.Spec.Roles) > 0 {
+		quoted := make([]string, len(u.Vusr.Spec.Roles))
+		for i := range u.Vusr.Spec.Roles {
+			quoted[i] = dbsql.QuoteIdentifier(u.Vusr.Spec.Roles[i])
+		}
+		roles = strings.Join(quoted, ", ")
+	} else if len(u.Vusr.Status.GrantedRoles) == 0 {
+		// Nothing was ever granted, so there are no default roles to clear
+		return nil
+	}
+	stmt := fmt.Sprintf("ALTER USER %s DEFAULT ROLE %s", dbsql.QuoteIdentifier(userName), roles)
+	if _, err := u.Conn.ExecContext(ctx, stmt); err != nil {
+		return fmt.Errorf("failed to set default roles: %w", err)
+	}
+	return nil
+}
+
+// getPassword returns the password stored in the password secret. An empty
+// string is returned if there is no password secret.
+func (u *UserReconciler) getPassword(ctx context.Context) (string, error) {
+	if u.Vusr.Spec.PasswordSecret == "" {
+		return "", nil
+	}
+	fetcher := cloud.SecretFetcher{
+		Client:   u.VRec.Client,
+		Log:      u.Log,
+		Obj:      u.Vusr,
+		EVWriter: u.VRec,
+	}
+	secret, err := fetcher.Fetch(ctx, names.GenNamespacedName(u.Vusr, u.Vusr.Spec.PasswordSecret))
+	if err != nil {
+		return "", err
+	}
+	passwd, ok := secret[names.SuperuserPasswordKey]
+	if !ok {
+		return "", fmt.Errorf("password not found, secret must have a key with name %q", names.SuperuserPasswordKey)
+	}
+	return string(passwd), nil
+}
+
+// getPasswordDigest returns the digest of the password that is kept in the
+// status. It is salted with the uid of the VerticaUser so that the status
+// can't be matched against precomputed hashes. An empty password has an empty
+// digest.
+func (u *UserReconciler) getPasswordDigest(passwd string) string {
+	if passwd == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(string(u.Vusr.UID) + ":" + passwd))
+	return hex.EncodeToString(sum[:])
+}
+
+// isStatusOutOfDate returns true if the spec differs from what was last
+// applied to the user
+func (u *UserReconciler) isStatusOutOfDate(digest string) bool {
+	st := &u.Vusr.Status
+	return st.PasswordSecret == nil || *st.PasswordSecret != u.Vusr.Spec.PasswordSecret ||
+		st.PasswordDigest != digest ||
+		st.ResourcePool != u.Vusr.Spec.ResourcePool ||
+		!reflect.DeepEqual(st.GrantedRoles, u.Vusr.Spec.Roles) ||
+		!reflect.DeepEqual(st.AppliedGrants, u.Vusr.Spec.Grants)
+}
+
+// setCondition will update the UserReady condition and the state
+func (u *UserReconciler) setCondition(ctx context.Context, status metav1.ConditionStatus, reason, state string) error {
+	return vk8s.UpdateConditions(ctx, u.VRec.Client, u.Log, u.Vusr,
+		[]*metav1.Condition{vapi.MakeCondition(v1beta1.UserReady, status, reason)}, state)
+}
@@ -0,0 +1,169 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vusr
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	v1beta1 "github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/test"
+	"github.com/vertica/vertica-kubernetes/pkg/vdbstatus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("user_reconcile", func() {
+	ctx := context.Background()
+
+	createInitializedVDB := func() *vapi.VerticaDB {
+		vdb := vapi.MakeVDB()
+		test.CreateVDB(ctx, k8sClient, vdb)
+		Expect(vdbstatus.UpdateCondition(ctx, k8sClient, vdb,
+			vapi.MakeCondition(vapi.DBInitialized, metav1.ConditionTrue, "Initialized"))).Should(Succeed())
+		return vdb
+	}
+
+	expectExec := func(mock sqlmock.Sqlmock, stmt string) {
+		mock.ExpectExec(regexp.QuoteMeta(stmt)).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+
+	It("should requeue if VerticaDB doesn't exist", func() {
+		vusr := v1beta1.MakeVusr()
+		Expect(k8sClient.Create(ctx, vusr)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vusr)).Should(Succeed()) }()
+
+		req := ctrl.Request{NamespacedName: v1beta1.MakeSampleVusrName()}
+		Expect(vusrRec.Reconcile(ctx, req)).Should(Equal(ctrl.Result{Requeue: true}))
+	})
+
+	It("should wait for the database to be initialized", func() {
+		vdb := vapi.MakeVDB()
+		test.CreateVDB(ctx, k8sClient, vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+
+		vusr := v1beta1.MakeVusr()
+		Expect(k8sClient.Create(ctx, vusr)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vusr)).Should(Succeed()) }()
+
+		recon := MakeUserReconciler(vusrRec, vusr, logger)
+		Expect(recon.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{RequeueAfter: dbNotReadyRequeueTime}))
+		Expect(vusr.IsStatusConditionFalse(v1beta1.UserReady)).Should(BeTrue())
+		Expect(vusr.Status.State).Should(Equal(stateNotReady))
+	})
+
+	It("should create the user with its roles and grants", func() {
+		vdb := createInitializedVDB()
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+
+		vusr := v1beta1.MakeVusr()
+		Expect(k8sClient.Create(ctx, vusr)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vusr)).Should(Succeed()) }()
+
+		db, mock, err := sqlmock.New()
+		Expect(err).Should(Succeed())
+		defer db.Close()
+		mock.ExpectQuery("SELECT COUNT").WithArgs("app_user").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		expectExec(mock, `CREATE USER "app_user" RESOURCE POOL "general"`)
+		expectExec(mock, `GRANT "app_role" TO "app_user"`)
+		expectExec(mock, `ALTER USER "app_user" DEFAULT ROLE "app_role"`)
+		expectExec(mock, `GRANT USAGE ON SCHEMA "store" TO "app_user"`)
+
+		recon := MakeUserReconciler(vusrRec, vusr, logger)
+		recon.(*UserReconciler).Conn = db
+		Expect(recon.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+		Expect(vusr.IsStatusConditionTrue(v1beta1.UserReady)).Should(BeTrue())
+		Expect(vusr.Status.State).Should(Equal(stateReady))
+		Expect(vusr.Status.GrantedRoles).Should(Equal([]string{"app_role"}))
+		Expect(vusr.Status.AppliedGrants).Should(Equal(vusr.Spec.Grants))
+	})
+
+	It("should revoke roles and grants removed from the spec", func() {
+		vdb := createInitializedVDB()
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+
+		vusr := v1beta1.MakeVusr()
+		vusr.Spec.Roles = nil
+		vusr.Spec.Grants = nil
+		vusr.Spec.ResourcePool = "batch"
+		Expect(k8sClient.Create(ctx, vusr)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vusr)).Should(Succeed()) }()
+		noSecret := ""
+		vusr.Status.PasswordSecret = &noSecret
+		vusr.Status.GrantedRoles = []string{"app_role"}
+		vusr.Status.AppliedGrants = v1beta1.MakeVusr().Spec.Grants
+		Expect(k8sClient.Status().Update(ctx, vusr)).Should(Succeed())
+
+		db, mock, err := sqlmock.New()
+		Expect(err).Should(Succeed())
+		defer db.Close()
+		mock.ExpectQuery("SELECT COUNT").WithArgs("app_user").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		expectExec(mock, `ALTER USER "app_user" RESOURCE POOL "batch"`)
+		expectExec(mock, `REVOKE "app_role" FROM "app_user"`)
+		expectExec(mock, `ALTER USER "app_user" DEFAULT ROLE NONE`)
+		expectExec(mock, `REVOKE USAGE ON SCHEMA "store" FROM "app_user"`)
+
+		recon := MakeUserReconciler(vusrRec, vusr, logger)
+		recon.(*UserReconciler).Conn = db
+		Expect(recon.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+		Expect(vusr.Status.GrantedRoles).Should(BeEmpty())
+		Expect(vusr.Status.AppliedGrants).Should(BeEmpty())
+		Expect(vusr.Status.ResourcePool).Should(Equal("batch"))
+	})
+
+	It("should change the password when the contents of the secret change", func() {
+		vdb := createInitializedVDB()
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+		const secretName = "app-user-password"
+		secret := test.BuildSuperuserPasswordSecret(vdb, secretName, "")
+		secret.StringData = nil
+		secret.Data = map[string][]byte{names.SuperuserPasswordKey: []byte("new-password")}
+		Expect(k8sClient.Create(ctx, secret)).Should(Succeed())
+		defer test.DeleteSecret(ctx, k8sClient, secretName)
+
+		vusr := v1beta1.MakeVusr()
+		vusr.Spec.PasswordSecret = secretName
+		vusr.Spec.Roles = nil
+		vusr.Spec.Grants = nil
+		Expect(k8sClient.Create(ctx, vusr)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vusr)).Should(Succeed()) }()
+		recon := MakeUserReconciler(vusrRec, vusr, logger)
+		u := recon.(*UserReconciler)
+		prevSecret := secretName
+		vusr.Status.PasswordSecret = &prevSecret
+		vusr.Status.PasswordDigest = u.getPasswordDigest("old-password")
+		vusr.Status.ResourcePool = vusr.Spec.ResourcePool
+		Expect(k8sClient.Status().Update(ctx, vusr)).Should(Succeed())
+
+		db, mock, err := sqlmock.New()
+		Expect(err).Should(Succeed())
+		defer db.Close()
+		mock.ExpectQuery("SELECT COUNT").WithArgs("app_user").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		expectExec(mock, `ALTER USER "app_user" IDENTIFIED BY 'new-password'`)
+
+		u.Conn = db
+		Expect(recon.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+		Expect(vusr.Status.PasswordDigest).Should(Equal(u.getPasswordDigest("new-password")))
+	})
+})
@@ -0,0 +1,590 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vwr
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	v1beta1 "github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/backup"
+	"github.com/vertica/vertica-kubernetes/pkg/builder"
+	"github.com/vertica/vertica-kubernetes/pkg/cloud"
+	"github.com/vertica/vertica-kubernetes/pkg/cmds"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	verrors "github.com/vertica/vertica-kubernetes/pkg/errors"
+	"github.com/vertica/vertica-kubernetes/pkg/events"
+	vmeta "github.com/vertica/vertica-kubernetes/pkg/meta"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/paths"
+	"github.com/vertica/vertica-kubernetes/pkg/podfacts"
+	"github.com/vertica/vertica-kubernetes/pkg/secrets"
+	"github.com/vertica/vertica-kubernetes/pkg/vk8s"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	stateReady            = "Ready"
+	stateNotReady         = "Incompatible"
+	stateWaitingToCapture = "WaitingForCaptureWindow"
+	stateCapturing        = "Capturing"
+	stateReplaying        = "Replaying"
+	stateComplete         = "Complete"
+	stateFailed           = "Failed"
+)
+
+// The steps of a workload replay. Each one runs in its own job.
+const (
+	stepCapture = "capture"
+	stepReplay  = "replay"
+)
+
+const (
+	// The files, in the shared volume of the job, that the workload and the
+	// report are written to
+	captureFile = paths.WorkloadReplayTmp + "/capture.csv"
+	reportFile  = paths.WorkloadReplayTmp + "/replay-report.csv"
+	// The format vcluster expects for the start and end of the capture window
+	workloadTimeLayout = "2006-01-02 15:04:05.999999-07"
+	// How often we check on a running job
+	jobPollInterval = 10 * time.Second
+)
+
+type WorkloadReplayReconciler struct {
+	VRec *VerticaWorkloadReplayReconciler
+	Vwr  *v1beta1.VerticaWorkloadReplay
+	Log  logr.Logger
+	Vdb  *vapi.VerticaDB
+	// The object store that the capture and report are saved to. If nil, one
+	// is built from the spec. This is set by tests to mock the object store.
+	Store backup.Store
+	// The pod runner used to copy files in and out of the job pods. If nil,
+	// one is built. This is set by tests to mock the exec calls.
+	PRunner cmds.PodRunner
+}
+
+func MakeWorkloadReplayReconciler(r *VerticaWorkloadReplayReconciler, vwr *v1beta1.VerticaWorkloadReplay,
+	log logr.Logger) controllers.ReconcileActor {
+	return &WorkloadReplayReconciler{
+		VRec: r,
+		Vwr:  vwr,
+		Log:  log.WithName("WorkloadReplayReconciler"),
+	}
+}
+
+// Reconcile will capture the workload of the main cluster once the capture
+// window has passed, and then replay it in the sandbox. Each step runs in a
+// job that we check on every reconcile. A VerticaWorkloadReplay that has
+// already completed, successfully or not, is left alone.
+func (w *WorkloadReplayReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (ctrl.Result, error) {
+	// no-op if the VerticaDB hasn't been verified or if the replay is done
+	if !w.Vwr.IsStatusConditionTrue(v1beta1.WorkloadReplayReady) ||
+		w.Vwr.FindStatusCondition(v1beta1.WorkloadReplayComplete) != nil {
+		return ctrl.Result{}, nil
+	}
+
+	// The capture can only include queries that have finished, so we wait
+	// for the end of the window.
+	now := time.Now()
+	if now.Before(w.Vwr.Spec.CaptureEndTime.Time) {
+		return ctrl.Result{RequeueAfter: w.Vwr.Spec.CaptureEndTime.Sub(now)},
+			vk8s.UpdateConditions(ctx, w.VRec.Client, w.Log, w.Vwr, nil, stateWaitingToCapture)
+	}
+
+	if res, err := w.fetchVdb(ctx); verrors.IsReconcileAborted(res, err) {
+		return res, err
+	}
+	if err := w.setup(ctx); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !w.Vwr.IsStatusConditionTrue(v1beta1.WorkloadCaptured) {
+		if res, err := w.reconcileStep(ctx, stepCapture); verrors.IsReconcileAborted(res, err) {
+			return res, err
+		}
+		// The capture may have just finished. If it failed, the replay is done.
+		if !w.Vwr.IsStatusConditionTrue(v1beta1.WorkloadCaptured) ||
+			w.Vwr.FindStatusCondition(v1beta1.WorkloadReplayComplete) != nil {
+			return ctrl.Result{}, nil
+		}
+	}
+	return w.reconcileStep(ctx, stepReplay)
+}
+
+// fetchVdb will fetch the VerticaDB referenced by the vwr
+func (w *WorkloadReplayReconciler) fetchVdb(ctx context.Context) (ctrl.Result, error) {
+	vdb := &vapi.VerticaDB{}
+	nm := names.GenNamespacedName(w.Vwr, w.Vwr.Spec.VerticaDBName)
+	if res, err := vk8s.FetchVDB(ctx, w.VRec, w.Vwr, nm, vdb); verrors.IsReconcileAborted(res, err) {
+		return res, err
+	}
+	w.Vdb = vdb
+	return ctrl.Result{}, nil
+}
+
+// setup will build the object store client and the pod runner if the tests
+// didn't set them
+func (w *WorkloadReplayReconciler) setup(ctx context.Context) error {
+	if w.Store == nil {
+		store, err := w.makeStore(ctx)
+		if err != nil {
+			return err
+		}
+		w.Store = store
+	}
+	if w.PRunner == nil {
+		password, err := vk8s.GetSuperuserPassword(ctx, w.VRec.Client, w.Log, w.VRec, w.Vdb)
+		if err != nil {
+			return err
+		}
+		w.PRunner = cmds.MakeClusterPodRunner(w.Log, w.VRec.Cfg, w.Vdb.GetVerticaUser(), password,
+			w.Vdb.IsClientServerTLSAuthEnabled())
+	}
+	return nil
+}
+
+// reconcileStep will drive the job of a step, the capture or the replay. The
+// job is created if it doesn't exist yet. Otherwise, its files are copied in
+// and out while it runs, and its outcome is recorded once it is done.
+func (w *WorkloadReplayReconciler) reconcileStep(ctx context.Context, step string) (ctrl.Result, error) {
+	job := &batchv1.Job{}
+	nm := names.GenWorkloadReplayJobName(w.Vwr, step)
+	if err := w.VRec.Client.Get(ctx, nm, job); err != nil {
+		if !kerrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		return w.startJob(ctx, step, nm)
+	}
+
+	switch {
+	case isJobConditionTrue(job, batchv1.JobFailed):
+		return ctrl.Result{}, w.failStep(ctx, step, job)
+	case isJobConditionTrue(job, batchv1.JobComplete):
+		return ctrl.Result{}, w.finishStep(ctx, step, job)
+	default:
+		return w.pollJob(ctx, step, job)
+	}
+}
+
+// startJob will create the job of a step. The capture runs against the main
+// cluster and the replay against the sandbox.
+func (w *WorkloadReplayReconciler) startJob(ctx context.Context, step string, nm types.NamespacedName) (ctrl.Result, error) {
+	cluster := vapi.MainCluster
+	if step == stepReplay {
+		cluster = w.Vwr.Spec.Sandbox
+	}
+	hostIP, res, err := w.findInitiatorIP(ctx, cluster)
+	if verrors.IsReconcileAborted(res, err) {
+		return res, err
+	}
+	return ctrl.Result{RequeueAfter: jobPollInterval}, w.createJob(ctx, step, nm, hostIP)
+}
+
+// createJob will create the job that runs a step from the given host, and
+// record in the status that the step has started
+func (w *WorkloadReplayReconciler) createJob(ctx context.Context, step string, nm types.NamespacedName, hostIP string) error {
+	var job *batchv1.Job
+	var jobID int64
+	if step == stepCapture {
+		job = builder.BuildWorkloadReplayJob(nm, w.Vdb, "", w.buildCaptureArgs(hostIP))
+	} else {
+		jobID = time.Now().UnixNano()
+		job = builder.BuildWorkloadReplayJob(nm, w.Vdb, captureFile, w.buildReplayArgs(hostIP, jobID))
+	}
+	// Deleting the vwr cleans up its jobs
+	if err := ctrl.SetControllerReference(w.Vwr, job, w.VRec.Scheme); err != nil {
+		return err
+	}
+	if err := w.VRec.Client.Create(ctx, job); err != nil {
+		return err
+	}
+	w.Log.Info("Created workload replay job", "name", nm, "step", step)
+
+	err := vk8s.UpdateStatus(ctx, w.VRec.Client, w.Log, w.Vwr, func(vwr *v1beta1.VerticaWorkloadReplay) error {
+		if step == stepCapture {
+			vwr.Status.State = stateCapturing
+			vwr.Status.StartTime = &metav1.Time{Time: time.Now().UTC()}
+		} else {
+			vwr.Status.State = stateReplaying
+			vwr.Status.ReplayJobID = jobID
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if step == stepCapture {
+		w.VRec.Eventf(w.Vwr, corev1.EventTypeNormal, events.WorkloadCaptureStarted,
+			"Starting capture of the workload of VerticaDB %q between %s and %s", w.Vdb.Name,
+			w.Vwr.Spec.CaptureStartTime.UTC().Format(time.RFC3339), w.Vwr.Spec.CaptureEndTime.UTC().Format(time.RFC3339))
+	} else {
+		w.VRec.Eventf(w.Vwr, corev1.EventTypeNormal, events.WorkloadReplayStarted,
+			"Starting replay of %d queries in sandbox %q", w.Vwr.Status.CapturedQueries, w.Vwr.Spec.Sandbox)
+	}
+	return nil
+}
+
+// buildCaptureArgs returns the vcluster arguments of the capture
+func (w *WorkloadReplayReconciler) buildCaptureArgs(hostIP string) []string {
+	args := []string{
+		"capture_workload",
+		"--db-name", w.Vdb.Spec.DBName,
+		"--db-user", w.Vdb.GetVerticaUser(),
+		"--hosts", hostIP,
+		"--start-timestamp", w.Vwr.Spec.CaptureStartTime.UTC().Format(workloadTimeLayout),
+		"--end-timestamp", w.Vwr.Spec.CaptureEndTime.UTC().Format(workloadTimeLayout),
+		"--workload-file", captureFile,
+	}
+	return w.appendPasswordArgs(args)
+}
+
+// buildReplayArgs returns the vcluster arguments of the replay
+func (w *WorkloadReplayReconciler) buildReplayArgs(hostIP string, jobID int64) []string {
+	args := []string{
+		"replay_workload",
+		"--db-name", w.Vdb.Spec.DBName,
+		"--db-user", w.Vdb.GetVerticaUser(),
+		"--hosts", hostIP,
+		"--sandbox", w.Vwr.Spec.Sandbox,
+		"--workload-file", captureFile,
+		"--replay-results-file", reportFile,
+		"--job-id", strconv.FormatInt(jobID, 10),
+	}
+	return w.appendPasswordArgs(args)
+}
+
+// appendPasswordArgs adds the password flag to the given args
+func (w *WorkloadReplayReconciler) appendPasswordArgs(args []string) []string {
+	// if there is no password, we need to explicitly set the password flag
+	// with empty string as value, to still assume password authentication
+	if w.Vdb.GetPasswordSecret() == "" {
+		return append(args, "--password=")
+	}
+	// when the password secret is on k8s, it is mounted in the job pod.
+	// Otherwise, vcluster reads it from the secret store itself.
+	if secrets.IsK8sSecret(w.Vdb.GetPasswordSecret()) {
+		return append(args, "--password-file", paths.ScrutinizeDBPasswordFile)
+	}
+	return args
+}
+
+// pollJob will check on a running job. The captured workload is copied in
+// once the replay command waits for it, and the output file is copied to the
+// object store once the command is done. After that, the job is told it can
+// exit.
+func (w *WorkloadReplayReconciler) pollJob(ctx context.Context, step string, job *batchv1.Job) (ctrl.Result, error) {
+	pod, ok, err := w.findJobPod(ctx, job)
+	if !ok {
+		w.Log.Info("Waiting for the pod of the workload replay job", "job", job.Name)
+		return ctrl.Result{RequeueAfter: jobPollInterval}, err
+	}
+	podName := types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}
+
+	cntStatus := vk8s.FindWorkloadReplayContainerStatus(pod)
+	if step == stepReplay && cntStatus != nil && cntStatus.State.Running != nil &&
+		job.Annotations[vmeta.WorkloadReplayInputCopiedAnnotation] != vmeta.AnnotationTrue {
+		if err := w.copyCaptureToJob(ctx, job, podName); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	mainStatus := vk8s.FindWorkloadReplayMainContainerStatus(pod)
+	if mainStatus != nil && mainStatus.State.Running != nil {
+		if err := w.copyOutputFromJob(ctx, step, podName); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: jobPollInterval}, nil
+}
+
+// findJobPod returns the pod of the job. The bool is false if it doesn't
+// exist yet.
+func (w *WorkloadReplayReconciler) findJobPod(ctx context.Context, job *batchv1.Job) (*corev1.Pod, bool, error) {
+	pods := &corev1.PodList{}
+	err := w.VRec.Client.List(ctx, pods, client.InNamespace(job.Namespace),
+		client.MatchingLabels{batchv1.JobNameLabel: job.Name})
+	if err != nil || len(pods.Items) == 0 {
+		return nil, false, err
+	}
+	return &pods.Items[0], true, nil
+}
+
+// copyCaptureToJob will stream the captured workload from the object store
+// into the job that replays it. The file is renamed once complete so the
+// replay doesn't start on a partial file.
+func (w *WorkloadReplayReconciler) copyCaptureToJob(ctx context.Context, job *batchv1.Job, podName types.NamespacedName) error {
+	body, err := w.Store.Get(ctx, w.Vwr.GetCaptureObjectKey())
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	cmd := []string{"bash", "-c", fmt.Sprintf("cat > %s.part && mv %s.part %s", captureFile, captureFile, captureFile)}
+	if _, stderr, err := w.PRunner.StreamToPod(ctx, podName, names.WorkloadReplayContainer, body, cmd...); err != nil {
+		return fmt.Errorf("failed to copy the captured workload to pod %s: %s: %w", podName, stderr, err)
+	}
+
+	patch := client.MergeFrom(job.DeepCopy())
+	if job.Annotations == nil {
+		job.Annotations = map[string]string{}
+	}
+	job.Annotations[vmeta.WorkloadReplayInputCopiedAnnotation] = vmeta.AnnotationTrue
+	return w.VRec.Client.Patch(ctx, job, patch)
+}
+
+// copyOutputFromJob will stream the output file of a step, the captured
+// workload or the replay report, from the job to the object store. The job
+// then exits.
+func (w *WorkloadReplayReconciler) copyOutputFromJob(ctx context.Context, step string, podName types.NamespacedName) error {
+	fileName, key := captureFile, w.Vwr.GetCaptureObjectKey()
+	if step == stepReplay {
+		fileName, key = reportFile, w.Vwr.GetReportObjectKey()
+	}
+
+	pr, pw := io.Pipe()
+	errCh := make(chan error, 1)
+	go func() {
+		stderr, err := w.PRunner.StreamFromPod(ctx, podName, names.WorkloadReplayMainContainer, pw, "cat", fileName)
+		if err != nil {
+			err = fmt.Errorf("failed to read %s from pod %s: %s: %w", fileName, podName, stderr, err)
+		}
+		pw.CloseWithError(err)
+		errCh <- err
+	}()
+	errPut := w.Store.Put(ctx, key, pr)
+	// Unblock the stream if the upload stopped early
+	pr.Close()
+	errStream := <-errCh
+	if errPut != nil {
+		return errPut
+	}
+	if errStream != nil {
+		return errStream
+	}
+
+	_, stderr, err := w.PRunner.ExecInPod(ctx, podName, names.WorkloadReplayMainContainer, "touch", paths.WorkloadReplayDoneFile)
+	if err != nil {
+		return fmt.Errorf("failed to release the workload replay job pod %s: %s: %w", podName, stderr, err)
+	}
+	return nil
+}
+
+// finishStep will record the outcome of a step whose job completed. Its
+// output was copied to the object store before the job exited.
+func (w *WorkloadReplayReconciler) finishStep(ctx context.Context, step string, job *batchv1.Job) error {
+	if step == stepCapture {
+		if err := w.finishCapture(ctx); err != nil {
+			return err
+		}
+	} else if err := w.finishReplay(ctx); err != nil {
+		return err
+	}
+	return w.deleteJob(ctx, job)
+}
+
+// finishCapture will count the captured queries and mark the workload as
+// captured
+func (w *WorkloadReplayReconciler) finishCapture(ctx context.Context) error {
+	body, err := w.Store.Get(ctx, w.Vwr.GetCaptureObjectKey())
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	queries, err := countCapturedQueries(body)
+	if err != nil {
+		w.VRec.Eventf(w.Vwr, corev1.EventTypeWarning, events.WorkloadCaptureFailed,
+			"Failed to capture the workload of VerticaDB %q: %s", w.Vdb.Name, err)
+		return w.markCompleted(ctx, err, nil)
+	}
+
+	w.VRec.Eventf(w.Vwr, corev1.EventTypeNormal, events.WorkloadCaptureSucceeded,
+		"Captured %d queries from VerticaDB %q", queries, w.Vdb.Name)
+	return vk8s.UpdateStatus(ctx, w.VRec.Client, w.Log, w.Vwr, func(vwr *v1beta1.VerticaWorkloadReplay) error {
+		vwr.Status.CapturedQueries = queries
+		vwr.Status.CapturePath = vwr.GetObjectPath(vwr.GetCaptureObjectKey())
+		meta.SetStatusCondition(&vwr.Status.Conditions,
+			*vapi.MakeCondition(v1beta1.WorkloadCaptured, metav1.ConditionTrue, "Captured"))
+		return nil
+	})
+}
+
+// finishReplay will summarize the replay report in the status. The outcome
+// completes the vwr.
+func (w *WorkloadReplayReconciler) finishReplay(ctx context.Context) error {
+	body, err := w.Store.Get(ctx, w.Vwr.GetReportObjectKey())
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	summary, err := summarizeReport(body, w.Vwr.GetRegressionThresholdPercent())
+	if err != nil {
+		w.VRec.Eventf(w.Vwr, corev1.EventTypeWarning, events.WorkloadReplayFailed,
+			"Failed to replay the workload in sandbox %q: %s", w.Vwr.Spec.Sandbox, err)
+		return w.markCompleted(ctx, err, nil)
+	}
+
+	if summary.regressions > 0 || summary.errors > 0 {
+		w.VRec.Eventf(w.Vwr, corev1.EventTypeWarning, events.WorkloadReplayRegressions,
+			"Replay in sandbox %q found regressions: %s", w.Vwr.Spec.Sandbox, summary)
+	} else {
+		w.VRec.Eventf(w.Vwr, corev1.EventTypeNormal, events.WorkloadReplaySucceeded,
+			"Replay in sandbox %q completed without regressions: %s", w.Vwr.Spec.Sandbox, summary)
+	}
+	return w.markCompleted(ctx, nil, summary)
+}
+
+// failStep will complete the vwr with the reason the job of a step failed.
+// The job is kept so that its logs can be inspected.
+func (w *WorkloadReplayReconciler) failStep(ctx context.Context, step string, job *batchv1.Job) error {
+	errRun := fmt.Errorf("job %s failed: %s", job.Name, w.getJobFailureMessage(ctx, job))
+	if step == stepCapture {
+		w.VRec.Eventf(w.Vwr, corev1.EventTypeWarning, events.WorkloadCaptureFailed,
+			"Failed to capture the workload of VerticaDB %q: %s", w.Vdb.Name, errRun)
+	} else {
+		w.VRec.Eventf(w.Vwr, corev1.EventTypeWarning, events.WorkloadReplayFailed,
+			"Failed to replay the workload in sandbox %q: %s", w.Vwr.Spec.Sandbox, errRun)
+	}
+	return w.markCompleted(ctx, errRun, nil)
+}
+
+// getJobFailureMessage returns why a job failed. This is the termination
+// message of the vcluster command, which holds the end of its output, if
+// there is one. Otherwise, it is the message of the failed job condition.
+func (w *WorkloadReplayReconciler) getJobFailureMessage(ctx context.Context, job *batchv1.Job) string {
+	pod, ok, err := w.findJobPod(ctx, job)
+	if err != nil {
+		w.Log.Info("Failed to find the pod of the workload replay job", "job", job.Name, "err", err)
+	}
+	if ok {
+		for _, cntStatus := range []*corev1.ContainerStatus{
+			vk8s.FindWorkloadReplayContainerStatus(pod),
+			vk8s.FindWorkloadReplayMainContainerStatus(pod),
+		} {
+			if cntStatus != nil && cntStatus.State.Terminated != nil &&
+				cntStatus.State.Terminated.ExitCode != 0 && cntStatus.State.Terminated.Message != "" {
+				return cntStatus.State.Terminated.Message
+			}
+		}
+	}
+	for i := range job.Status.Conditions {
+		if job.Status.Conditions[i].Type == batchv1.JobFailed {
+			return job.Status.Conditions[i].Message
+		}
+	}
+	return ""
+}
+
+// deleteJob will delete a job, and its pod, once its output is saved
+func (w *WorkloadReplayReconciler) deleteJob(ctx context.Context, job *batchv1.Job) error {
+	err := w.VRec.Client.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// isJobConditionTrue returns true if the job has the given condition set to true
+func isJobConditionTrue(job *batchv1.Job, condType batchv1.JobConditionType) bool {
+	for i := range job.Status.Conditions {
+		if job.Status.Conditions[i].Type == condType {
+			return job.Status.Conditions[i].Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// findInitiatorIP returns the IP of an up pod in the given cluster, which is
+// either the main cluster or a sandbox. It requeues if there is no such pod.
+func (w *WorkloadReplayReconciler) findInitiatorIP(ctx context.Context, cluster string) (string, ctrl.Result, error) {
+	password, err := vk8s.GetSuperuserPassword(ctx, w.VRec.Client, w.Log, w.VRec, w.Vdb)
+	if err != nil {
+		return "", ctrl.Result{}, err
+	}
+	pfacts := podfacts.MakePodFactsForSandboxWithCacheManager(w.VRec, w.PRunner, w.Log, password,
+		cluster, w.VRec.CacheManager)
+	if err := pfacts.Collect(ctx, w.Vdb); err != nil {
+		return "", ctrl.Result{}, err
+	}
+	hostIP, ok := pfacts.FindFirstUpPodIP(false, "")
+	if !ok {
+		w.Log.Info("No up pod found to run the workload capture or replay. Requeuing.", "cluster", cluster)
+		return "", ctrl.Result{Requeue: true}, nil
+	}
+	return hostIP, ctrl.Result{}, nil
+}
+
+// makeStore will build the object store client from the spec
+func (w *WorkloadReplayReconciler) makeStore(ctx context.Context) (backup.Store, error) {
+	cfg := backup.S3Config{
+		Path:     w.Vwr.Spec.Path,
+		Endpoint: w.Vwr.Spec.Endpoint,
+		Region:   w.Vwr.Spec.Region,
+	}
+	if w.Vwr.Spec.CredentialSecret != "" {
+		fetcher := cloud.SecretFetcher{
+			Client:   w.VRec.Client,
+			Log:      w.Log,
+			Obj:      w.Vwr,
+			EVWriter: w.VRec,
+		}
+		secret, err := fetcher.Fetch(ctx, names.GenNamespacedName(w.Vwr, w.Vwr.Spec.CredentialSecret))
+		if err != nil {
+			return nil, err
+		}
+		cfg.AccessKey = string(secret[cloud.CommunalAccessKeyName])
+		cfg.SecretKey = string(secret[cloud.CommunalSecretKeyName])
+	}
+	return backup.MakeS3Store(&cfg)
+}
+
+// markCompleted will record the outcome of the capture and replay in the
+// status. A failure is not returned as an error because the operation isn't
+// retried.
+func (w *WorkloadReplayReconciler) markCompleted(ctx context.Context, errRun error, summary *replaySummary) error {
+	return vk8s.UpdateStatus(ctx, w.VRec.Client, w.Log, w.Vwr, func(vwr *v1beta1.VerticaWorkloadReplay) error {
+		vwr.Status.CompletionTime = &metav1.Time{Time: time.Now().UTC()}
+		if errRun != nil {
+			vwr.Status.State = stateFailed
+			cond := vapi.MakeCondition(v1beta1.WorkloadReplayComplete, metav1.ConditionFalse, "Failed")
+			cond.Message = errRun.Error()
+			meta.SetStatusCondition(&vwr.Status.Conditions, *cond)
+			return nil
+		}
+		vwr.Status.State = stateComplete
+		vwr.Status.ReportPath = vwr.GetObjectPath(vwr.GetReportObjectKey())
+		vwr.Status.ReplayedQueries = summary.replayedQueries
+		vwr.Status.OriginalDurationMilliseconds = summary.originalDuration
+		vwr.Status.ReplayDurationMilliseconds = summary.replayDuration
+		vwr.Status.Regressions = summary.regressions
+		vwr.Status.Errors = summary.errors
+		vwr.Status.TopRegressions = summary.topRegressions
+		cond := vapi.MakeCondition(v1beta1.WorkloadReplayComplete, metav1.ConditionTrue, "Succeeded")
+		cond.Message = summary.String()
+		meta.SetStatusCondition(&vwr.Status.Conditions, *cond)
+		return nil
+	})
+}
@@ -0,0 +1,289 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vwr
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	v1beta1 "github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/backup"
+	"github.com/vertica/vertica-kubernetes/pkg/cmds"
+	vmeta "github.com/vertica/vertica-kubernetes/pkg/meta"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/paths"
+	"github.com/vertica/vertica-kubernetes/pkg/vk8s"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const testCapture = "node_name,session_id,start_timestamp,end_timestamp,request,request_duration_ms,error_details\n" +
+	"n1,s1,t1,t2,select 1,100,\n" +
+	"n1,s1,t3,t4,select 2,100,\n"
+
+var _ = Describe("replay_reconcile", func() {
+	ctx := context.Background()
+
+	It("should be a no-op if the replay isn't ready", func() {
+		vwr := v1beta1.MakeVwr()
+		Expect(k8sClient.Create(ctx, vwr)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vwr)).Should(Succeed()) }()
+
+		recon := MakeWorkloadReplayReconciler(vwrRec, vwr, logger)
+		Expect(recon.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(vwr.Status.State).Should(Equal(""))
+	})
+
+	It("should wait for the end of the capture window", func() {
+		vwr := v1beta1.MakeVwr()
+		vwr.Spec.CaptureStartTime = metav1.NewTime(time.Now())
+		vwr.Spec.CaptureEndTime = metav1.NewTime(time.Now().Add(time.Hour))
+		Expect(k8sClient.Create(ctx, vwr)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vwr)).Should(Succeed()) }()
+		setWorkloadReplayReady(ctx, vwr)
+
+		recon := MakeWorkloadReplayReconciler(vwrRec, vwr, logger)
+		res, err := recon.Reconcile(ctx, &ctrl.Request{})
+		Expect(err).Should(Succeed())
+		Expect(res.RequeueAfter).Should(BeNumerically(">", 0))
+		Expect(res.RequeueAfter).Should(BeNumerically("<=", time.Hour))
+		Expect(vwr.Status.State).Should(Equal(stateWaitingToCapture))
+	})
+
+	It("should run the capture in a job owned by the vwr", func() {
+		vwr := v1beta1.MakeVwr()
+		Expect(k8sClient.Create(ctx, vwr)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vwr)).Should(Succeed()) }()
+		setWorkloadReplayReady(ctx, vwr)
+
+		r := makeTestReplayReconciler(vwr, backup.MakeMemStore(), &cmds.FakePodRunner{})
+		nm := names.GenWorkloadReplayJobName(vwr, stepCapture)
+		Expect(r.createJob(ctx, stepCapture, nm, "10.10.10.10")).Should(Succeed())
+		defer deleteJob(ctx, nm)
+		Expect(vwr.Status.State).Should(Equal(stateCapturing))
+		Expect(vwr.Status.StartTime).ShouldNot(BeNil())
+
+		job := &batchv1.Job{}
+		Expect(k8sClient.Get(ctx, nm, job)).Should(Succeed())
+		Expect(job.OwnerReferences).Should(HaveLen(1))
+		Expect(job.OwnerReferences[0].Name).Should(Equal(vwr.Name))
+		Expect(job.Spec.Template.Spec.InitContainers).Should(HaveLen(1))
+		Expect(job.Spec.Template.Spec.InitContainers[0].Command).Should(ContainElements(
+			"capture_workload", "--hosts", "10.10.10.10", "--workload-file", captureFile, "--password="))
+	})
+
+	It("should copy the capture out of the job and record it once the job completes", func() {
+		vwr := v1beta1.MakeVwr()
+		Expect(k8sClient.Create(ctx, vwr)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vwr)).Should(Succeed()) }()
+		setWorkloadReplayReady(ctx, vwr)
+
+		store := backup.MakeMemStore()
+		fpr := &cmds.FakePodRunner{}
+		r := makeTestReplayReconciler(vwr, store, fpr)
+		nm := names.GenWorkloadReplayJobName(vwr, stepCapture)
+		Expect(r.createJob(ctx, stepCapture, nm, "10.10.10.10")).Should(Succeed())
+		defer deleteJob(ctx, nm)
+
+		// The capture command is done and the main container holds the file
+		pod := createJobPod(ctx, nm,
+			corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}},
+			corev1.ContainerState{Running: &corev1.ContainerStateRunning{}})
+		defer func() { Expect(k8sClient.Delete(ctx, pod)).Should(Succeed()) }()
+		podName := types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}
+		fpr.Results = cmds.CmdResults{podName: []cmds.CmdResult{{Stdout: testCapture}}}
+
+		res, err := r.reconcileStep(ctx, stepCapture)
+		Expect(err).Should(Succeed())
+		Expect(res.RequeueAfter).Should(Equal(jobPollInterval))
+		Expect(store.Objects[vwr.GetCaptureObjectKey()]).Should(Equal([]byte(testCapture)))
+		Expect(fpr.FindCommands("touch", paths.WorkloadReplayDoneFile)).Should(HaveLen(1))
+		Expect(vwr.IsStatusConditionTrue(v1beta1.WorkloadCaptured)).Should(BeFalse())
+
+		setJobCondition(ctx, nm, batchv1.JobComplete, "")
+		Expect(r.reconcileStep(ctx, stepCapture)).Should(Equal(ctrl.Result{}))
+		Expect(vwr.IsStatusConditionTrue(v1beta1.WorkloadCaptured)).Should(BeTrue())
+		Expect(vwr.Status.CapturedQueries).Should(Equal(2))
+		Expect(vwr.Status.CapturePath).Should(Equal("s3://bucket/replays/vwr-sample/capture.csv"))
+		// The job is removed once its output is saved
+		Expect(kerrors.IsNotFound(k8sClient.Get(ctx, nm, &batchv1.Job{}))).Should(BeTrue())
+	})
+
+	It("should copy the capture into the replay job and summarize the report", func() {
+		vwr := v1beta1.MakeVwr()
+		Expect(k8sClient.Create(ctx, vwr)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vwr)).Should(Succeed()) }()
+		setWorkloadReplayReady(ctx, vwr)
+
+		store := backup.MakeMemStore()
+		store.Objects[vwr.GetCaptureObjectKey()] = []byte(testCapture)
+		fpr := &cmds.FakePodRunner{}
+		r := makeTestReplayReconciler(vwr, store, fpr)
+		nm := names.GenWorkloadReplayJobName(vwr, stepReplay)
+		Expect(r.createJob(ctx, stepReplay, nm, "10.10.10.11")).Should(Succeed())
+		defer deleteJob(ctx, nm)
+		Expect(vwr.Status.State).Should(Equal(stateReplaying))
+		Expect(vwr.Status.ReplayJobID).ShouldNot(BeZero())
+
+		// The replay command waits for the captured workload
+		pod := createJobPod(ctx, nm,
+			corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{}})
+		defer func() { Expect(k8sClient.Delete(ctx, pod)).Should(Succeed()) }()
+		Expect(r.reconcileStep(ctx, stepReplay)).Should(Equal(ctrl.Result{RequeueAfter: jobPollInterval}))
+		copies := fpr.FindCommands(fmt.Sprintf("cat > %s.part", captureFile))
+		Expect(copies).Should(HaveLen(1))
+		Expect(copies[0].Stdin).Should(Equal(testCapture))
+		job := &batchv1.Job{}
+		Expect(k8sClient.Get(ctx, nm, job)).Should(Succeed())
+		Expect(job.Annotations).Should(HaveKeyWithValue(vmeta.WorkloadReplayInputCopiedAnnotation, vmeta.AnnotationTrue))
+
+		// The capture is only copied once
+		Expect(r.reconcileStep(ctx, stepReplay)).Should(Equal(ctrl.Result{RequeueAfter: jobPollInterval}))
+		Expect(fpr.FindCommands(fmt.Sprintf("cat > %s.part", captureFile))).Should(HaveLen(1))
+
+		// The replay is done and the main container holds the report
+		setJobPodStatus(ctx, pod,
+			corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}},
+			corev1.ContainerState{Running: &corev1.ContainerStateRunning{}})
+		podName := types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}
+		fpr.Results = cmds.CmdResults{podName: []cmds.CmdResult{{Stdout: testReportHeader +
+			"select 1,100,n1,100,n4,\n" +
+			"select 2,100,n1,300,n4,\n"}}}
+		Expect(r.reconcileStep(ctx, stepReplay)).Should(Equal(ctrl.Result{RequeueAfter: jobPollInterval}))
+		Expect(store.Objects).Should(HaveKey(vwr.GetReportObjectKey()))
+
+		setJobCondition(ctx, nm, batchv1.JobComplete, "")
+		Expect(r.reconcileStep(ctx, stepReplay)).Should(Equal(ctrl.Result{}))
+		Expect(vwr.Status.State).Should(Equal(stateComplete))
+		Expect(vwr.IsStatusConditionTrue(v1beta1.WorkloadReplayComplete)).Should(BeTrue())
+		Expect(vwr.Status.ReplayedQueries).Should(Equal(2))
+		Expect(vwr.Status.Regressions).Should(Equal(1))
+		Expect(vwr.Status.Errors).Should(Equal(0))
+		Expect(vwr.Status.TopRegressions).Should(HaveLen(1))
+		Expect(vwr.Status.ReportPath).Should(Equal("s3://bucket/replays/vwr-sample/replay-report.csv"))
+	})
+
+	It("should complete with a failure if the capture job fails", func() {
+		vwr := v1beta1.MakeVwr()
+		Expect(k8sClient.Create(ctx, vwr)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vwr)).Should(Succeed()) }()
+		setWorkloadReplayReady(ctx, vwr)
+
+		r := makeTestReplayReconciler(vwr, backup.MakeMemStore(), &cmds.FakePodRunner{})
+		nm := names.GenWorkloadReplayJobName(vwr, stepCapture)
+		Expect(r.createJob(ctx, stepCapture, nm, "10.10.10.10")).Should(Succeed())
+		defer deleteJob(ctx, nm)
+		pod := createJobPod(ctx, nm,
+			corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Message: "connection refused"}},
+			corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{}})
+		defer func() { Expect(k8sClient.Delete(ctx, pod)).Should(Succeed()) }()
+		setJobCondition(ctx, nm, batchv1.JobFailed, "BackoffLimitExceeded")
+
+		Expect(r.reconcileStep(ctx, stepCapture)).Should(Equal(ctrl.Result{}))
+		Expect(vwr.Status.State).Should(Equal(stateFailed))
+		cond := vwr.FindStatusCondition(v1beta1.WorkloadReplayComplete)
+		Expect(cond).ShouldNot(BeNil())
+		Expect(cond.Status).Should(Equal(metav1.ConditionFalse))
+		Expect(cond.Message).Should(ContainSubstring("connection refused"))
+		Expect(vwr.IsStatusConditionTrue(v1beta1.WorkloadCaptured)).Should(BeFalse())
+		// The failed job is kept for its logs
+		Expect(k8sClient.Get(ctx, nm, &batchv1.Job{})).Should(Succeed())
+	})
+})
+
+func makeTestReplayReconciler(vwr *v1beta1.VerticaWorkloadReplay, store backup.Store,
+	fpr *cmds.FakePodRunner) *WorkloadReplayReconciler {
+	return &WorkloadReplayReconciler{VRec: vwrRec, Vwr: vwr, Log: logger, Vdb: vapi.MakeVDBForVclusterOps(),
+		Store: store, PRunner: fpr}
+}
+
+func setWorkloadReplayReady(ctx context.Context, vwr *v1beta1.VerticaWorkloadReplay) {
+	Expect(vk8s.UpdateConditions(ctx, k8sClient, logger, vwr,
+		[]*metav1.Condition{vapi.MakeCondition(v1beta1.WorkloadReplayReady, metav1.ConditionTrue, "Verified")},
+		stateReady)).Should(Succeed())
+}
+
+// createJobPod creates the pod of a workload replay job with the given states
+// for its containers
+func createJobPod(ctx context.Context, jobName types.NamespacedName, initState,
+	mainState corev1.ContainerState) *corev1.Pod {
+	job := &batchv1.Job{}
+	ExpectWithOffset(1, k8sClient.Get(ctx, jobName, job)).Should(Succeed())
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-abcde", jobName.Name),
+			Namespace: jobName.Namespace,
+			Labels:    map[string]string{batchv1.JobNameLabel: jobName.Name},
+		},
+		Spec: job.Spec.Template.Spec,
+	}
+	ExpectWithOffset(1, k8sClient.Create(ctx, pod)).Should(Succeed())
+	setJobPodStatus(ctx, pod, initState, mainState)
+	return pod
+}
+
+// setJobPodStatus sets the states of the containers of a workload replay job pod
+func setJobPodStatus(ctx context.Context, pod *corev1.Pod, initState, mainState corev1.ContainerState) {
+	ExpectWithOffset(1, k8sClient.Get(ctx, client.ObjectKeyFromObject(pod), pod)).Should(Succeed())
+	pod.Status.InitContainerStatuses = []corev1.ContainerStatus{
+		{Name: names.WorkloadReplayContainer, State: initState},
+	}
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{Name: names.WorkloadReplayMainContainer, State: mainState},
+	}
+	ExpectWithOffset(1, k8sClient.Status().Update(ctx, pod)).Should(Succeed())
+}
+
+// setJobCondition marks a job as complete or failed
+func setJobCondition(ctx context.Context, nm types.NamespacedName, condType batchv1.JobConditionType, reason string) {
+	job := &batchv1.Job{}
+	ExpectWithOffset(1, k8sClient.Get(ctx, nm, job)).Should(Succeed())
+	now := metav1.Now()
+	job.Status.StartTime = &now
+	if condType == batchv1.JobComplete {
+		job.Status.Succeeded = 1
+		job.Status.CompletionTime = &now
+		job.Status.Conditions = append(job.Status.Conditions, batchv1.JobCondition{
+			Type: batchv1.JobSuccessCriteriaMet, Status: corev1.ConditionTrue, LastTransitionTime: now})
+	} else {
+		job.Status.Failed = 1
+		job.Status.Conditions = append(job.Status.Conditions, batchv1.JobCondition{
+			Type: batchv1.JobFailureTarget, Status: corev1.ConditionTrue, Reason: reason, LastTransitionTime: now})
+	}
+	job.Status.Conditions = append(job.Status.Conditions, batchv1.JobCondition{
+		Type: condType, Status: corev1.ConditionTrue, Reason: reason, LastTransitionTime: now})
+	ExpectWithOffset(1, k8sClient.Status().Update(ctx, job)).Should(Succeed())
+}
+
+func deleteJob(ctx context.Context, nm types.NamespacedName) {
+	job := &batchv1.Job{}
+	err := k8sClient.Get(ctx, nm, job)
+	if kerrors.IsNotFound(err) {
+		return
+	}
+	Expect(err).Should(Succeed())
+	Expect(k8sClient.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground))).Should(Succeed())
+}
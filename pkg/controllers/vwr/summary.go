@@ -0,0 +1,183 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vwr
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	v1beta1 "github.com/vertica/vertica-kubernetes/api/v1beta1"
+)
+
+const (
+	// maxTopRegressions is the number of regressions kept in the status. The
+	// complete list is in the report.
+	maxTopRegressions = 10
+	// maxRequestLength is the number of characters of a query kept in the
+	// status
+	maxRequestLength = 256
+	// minRegressionMilliseconds is the smallest slowdown that can count as a
+	// regression. Short queries vary too much from run to run to compare
+	// them by percentage alone.
+	minRegressionMilliseconds = 10
+)
+
+// Columns of the replay report written by vclusterops
+const (
+	reportColRequest          = "request"
+	reportColOriginalDuration = "original_duration_ms"
+	reportColReplayDuration   = "replay_duration_ms"
+	reportColError            = "error"
+)
+
+// replaySummary is the outcome of a replay after the regression threshold of
+// the vwr was applied
+type replaySummary struct {
+	replayedQueries  int
+	originalDuration int64
+	replayDuration   int64
+	regressions      int
+	errors           int
+	topRegressions   []v1beta1.VerticaWorkloadReplayRegression
+}
+
+// String is used for the message of the events and the condition
+func (s *replaySummary) String() string {
+	return fmt.Sprintf("%d queries replayed, %d regressions, %d errors, total duration %dms originally and %dms replayed",
+		s.replayedQueries, s.regressions, s.errors, s.originalDuration, s.replayDuration)
+}
+
+// countCapturedQueries returns the number of queries in a workload capture
+// file. The first row is the header.
+func countCapturedQueries(r io.Reader) (int, error) {
+	rdr := csv.NewReader(r)
+	count := -1
+	for {
+		_, err := rdr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read the captured workload: %w", err)
+		}
+		count++
+	}
+	return max(count, 0), nil
+}
+
+// summarizeReport will read the replay report and find the queries that
+// regressed by more than thresholdPercent or that failed
+func summarizeReport(r io.Reader, thresholdPercent int) (*replaySummary, error) {
+	rdr := csv.NewReader(r)
+	header, err := rdr.Read()
+	if errors.Is(err, io.EOF) {
+		return &replaySummary{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the header of the replay report: %w", err)
+	}
+	cols := map[string]int{}
+	for i, name := range header {
+		cols[name] = i
+	}
+	for _, name := range []string{reportColRequest, reportColOriginalDuration, reportColReplayDuration, reportColError} {
+		if _, ok := cols[name]; !ok {
+			return nil, fmt.Errorf("column %s is missing from the replay report", name)
+		}
+	}
+
+	summary := &replaySummary{}
+	candidates := []v1beta1.VerticaWorkloadReplayRegression{}
+	for {
+		row, err := rdr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the replay report: %w", err)
+		}
+		reg, err := parseReportRow(row, cols)
+		if err != nil {
+			return nil, err
+		}
+		summary.replayedQueries++
+		summary.originalDuration += reg.OriginalDurationMilliseconds
+		summary.replayDuration += reg.ReplayDurationMilliseconds
+		switch {
+		case reg.Error != "":
+			summary.errors++
+		case isRegression(reg, thresholdPercent):
+			summary.regressions++
+		default:
+			continue
+		}
+		candidates = append(candidates, *reg)
+	}
+	summary.topRegressions = pickTopRegressions(candidates)
+	return summary, nil
+}
+
+// parseReportRow converts one row of the replay report
+func parseReportRow(row []string, cols map[string]int) (*v1beta1.VerticaWorkloadReplayRegression, error) {
+	original, err := strconv.ParseInt(row[cols[reportColOriginalDuration]], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s in the replay report: %w", reportColOriginalDuration, err)
+	}
+	replay, err := strconv.ParseInt(row[cols[reportColReplayDuration]], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s in the replay report: %w", reportColReplayDuration, err)
+	}
+	request := row[cols[reportColRequest]]
+	if runes := []rune(request); len(runes) > maxRequestLength {
+		request = string(runes[:maxRequestLength]) + "..."
+	}
+	return &v1beta1.VerticaWorkloadReplayRegression{
+		Request:                      request,
+		OriginalDurationMilliseconds: original,
+		ReplayDurationMilliseconds:   replay,
+		Error:                        row[cols[reportColError]],
+	}, nil
+}
+
+// isRegression returns true if the replayed query was slower than the
+// original one by more than thresholdPercent
+func isRegression(reg *v1beta1.VerticaWorkloadReplayRegression, thresholdPercent int) bool {
+	slowdown := reg.ReplayDurationMilliseconds - reg.OriginalDurationMilliseconds
+	if slowdown < minRegressionMilliseconds {
+		return false
+	}
+	return slowdown*100 > reg.OriginalDurationMilliseconds*int64(thresholdPercent)
+}
+
+// pickTopRegressions returns the worst regressions. Failed queries come
+// first, followed by the queries with the largest slowdown.
+func pickTopRegressions(regs []v1beta1.VerticaWorkloadReplayRegression) []v1beta1.VerticaWorkloadReplayRegression {
+	sort.SliceStable(regs, func(i, j int) bool {
+		if (regs[i].Error != "") != (regs[j].Error != "") {
+			return regs[i].Error != ""
+		}
+		return regs[i].ReplayDurationMilliseconds-regs[i].OriginalDurationMilliseconds >
+			regs[j].ReplayDurationMilliseconds-regs[j].OriginalDurationMilliseconds
+	})
+	if len(regs) > maxTopRegressions {
+		regs = regs[:maxTopRegressions]
+	}
+	return regs
+}
@@ -0,0 +1,79 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vwr
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const testReportHeader = "request,original_duration_ms,original_node_name,replay_duration_ms,replay_node_name,error\n"
+
+var _ = Describe("summary", func() {
+	It("should count the regressions over the threshold and the errors", func() {
+		report := testReportHeader +
+			"select 1,100,v_db_node0001,110,v_db_node0004,\n" +
+			"select 2,100,v_db_node0001,150,v_db_node0004,\n" +
+			"\"select 3, 4\",1000,v_db_node0002,900,v_db_node0005,\n" +
+			"select 5,200,v_db_node0002,0,,Table t1 does not exist\n"
+		summary, err := summarizeReport(strings.NewReader(report), 20)
+		Expect(err).Should(Succeed())
+		Expect(summary.replayedQueries).Should(Equal(4))
+		Expect(summary.originalDuration).Should(Equal(int64(1400)))
+		Expect(summary.replayDuration).Should(Equal(int64(1160)))
+		Expect(summary.regressions).Should(Equal(1))
+		Expect(summary.errors).Should(Equal(1))
+		Expect(summary.topRegressions).Should(HaveLen(2))
+		Expect(summary.topRegressions[0].Error).Should(Equal("Table t1 does not exist"))
+		Expect(summary.topRegressions[1].Request).Should(Equal("select 2"))
+		Expect(summary.topRegressions[1].ReplayDurationMilliseconds).Should(Equal(int64(150)))
+	})
+
+	It("should ignore small slowdowns of short queries", func() {
+		report := testReportHeader + "select 1,1,v_db_node0001,5,v_db_node0004,\n"
+		summary, err := summarizeReport(strings.NewReader(report), 20)
+		Expect(err).Should(Succeed())
+		Expect(summary.regressions).Should(Equal(0))
+		Expect(summary.topRegressions).Should(BeEmpty())
+	})
+
+	It("should keep only the worst regressions and truncate long queries", func() {
+		report := testReportHeader
+		for i := 0; i < maxTopRegressions+5; i++ {
+			report += strings.Repeat("x", maxRequestLength+10) + ",100,n1,1000,n2,\n"
+		}
+		summary, err := summarizeReport(strings.NewReader(report), 20)
+		Expect(err).Should(Succeed())
+		Expect(summary.regressions).Should(Equal(maxTopRegressions + 5))
+		Expect(summary.topRegressions).Should(HaveLen(maxTopRegressions))
+		Expect(summary.topRegressions[0].Request).Should(HaveLen(maxRequestLength + len("...")))
+	})
+
+	It("should fail if the report is missing a column", func() {
+		_, err := summarizeReport(strings.NewReader("request,error\nselect 1,\n"), 20)
+		Expect(err).ShouldNot(Succeed())
+	})
+
+	It("should count the queries of a capture", func() {
+		capture := "node_name,session_id,start_timestamp,end_timestamp,request,request_duration_ms,error_details\n" +
+			"n1,s1,t1,t2,\"select 1,\n2\",10,\n" +
+			"n1,s1,t3,t4,select 2,20,\n"
+		Expect(countCapturedQueries(strings.NewReader(capture))).Should(Equal(2))
+		Expect(countCapturedQueries(strings.NewReader(""))).Should(Equal(0))
+	})
+})
@@ -0,0 +1,103 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vwr
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	v1beta1 "github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/controllers"
+	verrors "github.com/vertica/vertica-kubernetes/pkg/errors"
+	"github.com/vertica/vertica-kubernetes/pkg/events"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/vk8s"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+type VdbVerifyReconciler struct {
+	VRec *VerticaWorkloadReplayReconciler
+	Vwr  *v1beta1.VerticaWorkloadReplay
+	Log  logr.Logger
+}
+
+func MakeVdbVerifyReconciler(r *VerticaWorkloadReplayReconciler, vwr *v1beta1.VerticaWorkloadReplay,
+	log logr.Logger) controllers.ReconcileActor {
+	return &VdbVerifyReconciler{
+		VRec: r,
+		Vwr:  vwr,
+		Log:  log.WithName("VdbVerifyReconciler"),
+	}
+}
+
+// Reconcile will verify the VerticaDB in the Vwr CR exists, is deployed with
+// vclusterops, runs an image whose vcluster has the capture and replay
+// commands and has the sandbox to replay in. Workload capture and replay go
+// through the NMA, so they aren't available for admintools deployments.
+func (v *VdbVerifyReconciler) Reconcile(ctx context.Context, _ *ctrl.Request) (ctrl.Result, error) {
+	// Nothing to verify once the replay is done
+	if v.Vwr.FindStatusCondition(v1beta1.WorkloadReplayComplete) != nil {
+		return ctrl.Result{}, nil
+	}
+
+	vdb := &vapi.VerticaDB{}
+	nm := names.GenNamespacedName(v.Vwr, v.Vwr.Spec.VerticaDBName)
+	if res, err := vk8s.FetchVDB(ctx, v.VRec, v.Vwr, nm, vdb); verrors.IsReconcileAborted(res, err) {
+		return res, err
+	}
+
+	if !vdb.UseVClusterOpsDeployment() {
+		return v.setNotReady(ctx, events.VwrAdmintoolsNotSupported, "AdmintoolsNotSupported",
+			"Workload replay is not supported for admintools deployments")
+	}
+	// The version is known once the database pods have run. We wait for it
+	// rather than start a job whose vcluster may not know the commands.
+	vinf, ok := vdb.MakeVersionInfo()
+	if !ok {
+		v.Log.Info("Waiting for the version of the VerticaDB to be known")
+		return ctrl.Result{Requeue: true}, nil
+	}
+	if !vinf.IsEqualOrNewer(vapi.WorkloadReplayMinVersion) {
+		return v.setNotReady(ctx, events.VwrIncompatibleDB, "IncompatibleDB",
+			fmt.Sprintf("The Vertica version %q doesn't support workload replay. The minimum version is %q",
+				vinf.VdbVer, vapi.WorkloadReplayMinVersion))
+	}
+	if vdb.GetSandbox(v.Vwr.Spec.Sandbox) == nil {
+		return v.setNotReady(ctx, events.VwrSandboxNotFound, "SandboxNotFound",
+			fmt.Sprintf("Sandbox %q is not defined in VerticaDB %q", v.Vwr.Spec.Sandbox, vdb.Name))
+	}
+
+	if v.Vwr.IsStatusConditionTrue(v1beta1.WorkloadReplayReady) {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{}, vk8s.UpdateConditions(ctx, v.VRec.Client, v.Log, v.Vwr,
+		[]*metav1.Condition{vapi.MakeCondition(v1beta1.WorkloadReplayReady, metav1.ConditionTrue, "Verified")},
+		stateReady)
+}
+
+// setNotReady will log an event and set the WorkloadReplayReady condition to false
+func (v *VdbVerifyReconciler) setNotReady(ctx context.Context, eventReason, condReason, msg string) (ctrl.Result, error) {
+	if !v.Vwr.IsStatusConditionFalse(v1beta1.WorkloadReplayReady) {
+		v.VRec.Event(v.Vwr, corev1.EventTypeWarning, eventReason, msg)
+	}
+	return ctrl.Result{}, vk8s.UpdateConditions(ctx, v.VRec.Client, v.Log, v.Vwr,
+		[]*metav1.Condition{vapi.MakeCondition(v1beta1.WorkloadReplayReady, metav1.ConditionFalse, condReason)},
+		stateNotReady)
+}
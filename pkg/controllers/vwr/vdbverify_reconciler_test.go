@@ -0,0 +1,87 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vwr
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	v1beta1 "github.com/vertica/vertica-kubernetes/api/v1beta1"
+	vmeta "github.com/vertica/vertica-kubernetes/pkg/meta"
+	"github.com/vertica/vertica-kubernetes/pkg/test"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var _ = Describe("vdbverify_reconcile", func() {
+	ctx := context.Background()
+
+	makeReplayVDB := func(ver string) *vapi.VerticaDB {
+		vdb := vapi.MakeVDB()
+		vdb.Annotations[vmeta.VClusterOpsAnnotation] = vmeta.VClusterOpsAnnotationTrue
+		vdb.Annotations[vmeta.VersionAnnotation] = ver
+		vdb.Spec.Sandboxes = []vapi.Sandbox{{Name: "sand"}}
+		return vdb
+	}
+
+	It("should reject a VerticaDB whose image doesn't have the workload commands", func() {
+		vdb := makeReplayVDB("v25.4.0")
+		test.CreateVDB(ctx, k8sClient, vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+
+		vwr := v1beta1.MakeVwr()
+		Expect(k8sClient.Create(ctx, vwr)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vwr)).Should(Succeed()) }()
+
+		recon := MakeVdbVerifyReconciler(vwrRec, vwr, logger)
+		Expect(recon.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(vwr.IsStatusConditionFalse(v1beta1.WorkloadReplayReady)).Should(BeTrue())
+		Expect(vwr.FindStatusCondition(v1beta1.WorkloadReplayReady).Reason).Should(Equal("IncompatibleDB"))
+		Expect(vwr.Status.State).Should(Equal(stateNotReady))
+	})
+
+	It("should wait for the version of the VerticaDB to be known", func() {
+		vdb := makeReplayVDB("")
+		delete(vdb.Annotations, vmeta.VersionAnnotation)
+		test.CreateVDB(ctx, k8sClient, vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+
+		vwr := v1beta1.MakeVwr()
+		Expect(k8sClient.Create(ctx, vwr)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vwr)).Should(Succeed()) }()
+
+		recon := MakeVdbVerifyReconciler(vwrRec, vwr, logger)
+		Expect(recon.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{Requeue: true}))
+		Expect(vwr.FindStatusCondition(v1beta1.WorkloadReplayReady)).Should(BeNil())
+	})
+
+	It("should be ready with a password secret stored outside of Kubernetes", func() {
+		vdb := makeReplayVDB(vapi.WorkloadReplayMinVersion)
+		vdb.Spec.PasswordSecret = "gsm://projects/123/secrets/su-passwd/versions/1"
+		test.CreateVDB(ctx, k8sClient, vdb)
+		defer test.DeleteVDB(ctx, k8sClient, vdb)
+
+		vwr := v1beta1.MakeVwr()
+		Expect(k8sClient.Create(ctx, vwr)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vwr)).Should(Succeed()) }()
+
+		recon := MakeVdbVerifyReconciler(vwrRec, vwr, logger)
+		Expect(recon.Reconcile(ctx, &ctrl.Request{})).Should(Equal(ctrl.Result{}))
+		Expect(vwr.IsStatusConditionTrue(v1beta1.WorkloadReplayReady)).Should(BeTrue())
+		Expect(vwr.Status.State).Should(Equal(stateReady))
+	})
+})
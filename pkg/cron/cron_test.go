@@ -0,0 +1,103 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cron
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCron(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "cron Suite")
+}
+
+func mustParseTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	Expect(err).Should(Succeed())
+	return t
+}
+
+var _ = Describe("cron", func() {
+	It("should reject invalid expressions", func() {
+		for _, expr := range []string{
+			"", "* * * *", "* * * * * *", "60 * * * *", "* 24 * * *", "* * 0 * *",
+			"* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "a * * * *",
+			"1/2/3 * * * *", "@every",
+		} {
+			_, err := Parse(expr)
+			Expect(err).ShouldNot(Succeed(), expr)
+		}
+	})
+
+	It("should accept valid expressions", func() {
+		for _, expr := range []string{
+			"* * * * *", "*/15 * * * *", "0 2 * * *", "0 0 1,15 * *", "30 8-18/2 * * mon-fri",
+			"0 0 * jan,jul sun", "0 0 * * 7", "@daily", "@HOURLY", "5/10 * * * *",
+		} {
+			_, err := Parse(expr)
+			Expect(err).Should(Succeed(), expr)
+		}
+	})
+
+	It("should compute the next run time", func() {
+		s, err := Parse("30 2 * * *")
+		Expect(err).Should(Succeed())
+		Expect(s.Next(mustParseTime("2025-03-10T01:00:00Z"))).Should(Equal(mustParseTime("2025-03-10T02:30:00Z")))
+		Expect(s.Next(mustParseTime("2025-03-10T02:30:00Z"))).Should(Equal(mustParseTime("2025-03-11T02:30:00Z")))
+		Expect(s.Next(mustParseTime("2025-12-31T23:59:00Z"))).Should(Equal(mustParseTime("2026-01-01T02:30:00Z")))
+
+		s, err = Parse("*/20 * * * *")
+		Expect(err).Should(Succeed())
+		Expect(s.Next(mustParseTime("2025-03-10T01:41:12Z"))).Should(Equal(mustParseTime("2025-03-10T02:00:00Z")))
+	})
+
+	It("should handle day-of-week and day-of-month", func() {
+		// 2025-03-10 is a Monday
+		s, err := Parse("0 9 * * sat,sun")
+		Expect(err).Should(Succeed())
+		Expect(s.Next(mustParseTime("2025-03-10T10:00:00Z"))).Should(Equal(mustParseTime("2025-03-15T09:00:00Z")))
+
+		// When both are restricted, either one matching is enough
+		s, err = Parse("0 0 20 * 3")
+		Expect(err).Should(Succeed())
+		Expect(s.Next(mustParseTime("2025-03-10T10:00:00Z"))).Should(Equal(mustParseTime("2025-03-12T00:00:00Z")))
+		Expect(s.Next(mustParseTime("2025-03-19T10:00:00Z"))).Should(Equal(mustParseTime("2025-03-20T00:00:00Z")))
+
+		s, err = Parse("0 0 * * 7")
+		Expect(err).Should(Succeed())
+		Expect(s.Next(mustParseTime("2025-03-10T10:00:00Z"))).Should(Equal(mustParseTime("2025-03-16T00:00:00Z")))
+	})
+
+	It("should return zero time for a schedule that never fires", func() {
+		s, err := Parse("0 0 30 2 *")
+		Expect(err).Should(Succeed())
+		Expect(s.Next(mustParseTime("2025-03-10T10:00:00Z")).IsZero()).Should(BeTrue())
+	})
+
+	It("should compute the previous run time", func() {
+		s, err := Parse("0 8 * * mon-fri")
+		Expect(err).Should(Succeed())
+		// Saturday morning goes back to Friday
+		Expect(s.Prev(mustParseTime("2025-03-15T10:00:00Z"))).Should(Equal(mustParseTime("2025-03-14T08:00:00Z")))
+		Expect(s.Prev(mustParseTime("2025-03-14T08:00:30Z"))).Should(Equal(mustParseTime("2025-03-14T08:00:00Z")))
+		Expect(s.Prev(mustParseTime("2025-03-14T07:59:00Z"))).Should(Equal(mustParseTime("2025-03-13T08:00:00Z")))
+	})
+})
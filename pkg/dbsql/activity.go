@@ -0,0 +1,98 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dbsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// SubclusterActivity is how busy a subcluster is
+type SubclusterActivity struct {
+	// The number of client sessions connected to the subcluster
+	ActiveSessions int32
+	// The number of sessions that are running a query
+	RunningQueries int32
+}
+
+// The session we query from is excluded from all of the counts since it is
+// always active.
+const notCurrentSession = "s.session_id NOT IN (SELECT session_id FROM v_monitor.current_session)"
+
+// GetSubclusterActivity returns the activity of each subcluster in the
+// database, keyed by subcluster name
+func GetSubclusterActivity(ctx context.Context, conn *sql.DB) (map[string]SubclusterActivity, error) {
+	query := "SELECT sc.subcluster_name, COUNT(s.session_id), COUNT(s.statement_id) " +
+		"FROM v_catalog.subclusters sc LEFT JOIN v_monitor.sessions s " +
+		"ON s.node_name = sc.node_name AND " + notCurrentSession + " " +
+		"GROUP BY sc.subcluster_name"
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the subcluster activity: %w", err)
+	}
+	defer rows.Close()
+	activity := map[string]SubclusterActivity{}
+	for rows.Next() {
+		var name string
+		var a SubclusterActivity
+		if err := rows.Scan(&name, &a.ActiveSessions, &a.RunningQueries); err != nil {
+			return nil, fmt.Errorf("failed to read the subcluster activity: %w", err)
+		}
+		activity[name] = a
+	}
+	return activity, rows.Err()
+}
+
+// CancelSubclusterQueries interrupts the queries running in the subcluster.
+// It returns the number of queries that were interrupted.
+func CancelSubclusterQueries(ctx context.Context, conn *sql.DB, subcluster string) (int, error) {
+	query := "SELECT s.session_id, s.statement_id FROM v_monitor.sessions s " +
+		"JOIN v_catalog.subclusters sc ON s.node_name = sc.node_name " +
+		"WHERE sc.subcluster_name = ? AND s.statement_id IS NOT NULL AND " + notCurrentSession
+	rows, err := conn.QueryContext(ctx, query, subcluster)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find the queries running in subcluster %s: %w", subcluster, err)
+	}
+	type statement struct {
+		sessionID   string
+		statementID int64
+	}
+	stmts := []statement{}
+	for rows.Next() {
+		var st statement
+		if err := rows.Scan(&st.sessionID, &st.statementID); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to read the queries running in subcluster %s: %w", subcluster, err)
+		}
+		stmts = append(stmts, st)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	cancelled := 0
+	for _, st := range stmts {
+		// The statement may have finished since we looked it up, so a failure
+		// to interrupt it is not an error.
+		if _, err := conn.ExecContext(ctx, "SELECT INTERRUPT_STATEMENT(?, ?)", st.sessionID, st.statementID); err != nil {
+			continue
+		}
+		cancelled++
+	}
+	return cancelled, nil
+}
@@ -0,0 +1,92 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dbsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+
+	"github.com/go-logr/logr"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/pkg/builder"
+	"github.com/vertica/vertica-kubernetes/pkg/events"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+	"github.com/vertica/vertica-kubernetes/pkg/vk8s"
+	// Register the vertica driver with database/sql
+	_ "github.com/vertica/vertica-sql-go"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	driverName = "vertica"
+
+	TLSModeNone   = "none"
+	TLSModeServer = "server"
+)
+
+// Open will open a connection to the database as the superuser. It connects
+// through the service object of the first primary subcluster, so it must be
+// called only when the database is up.
+func Open(ctx context.Context, cl client.Client, log logr.Logger, e events.EVWriter, vdb *vapi.VerticaDB) (*sql.DB, error) {
+	passwd, err := vk8s.GetSuperuserPassword(ctx, cl, log, e, vdb)
+	if err != nil {
+		return nil, err
+	}
+	connStr, err := MakeConnString(vdb, vdb.GetVerticaUser(), *passwd)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := sql.Open(driverName, connStr)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.PingContext(ctx); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to connect to database %s: %w", vdb.Spec.DBName, err)
+	}
+	return conn, nil
+}
+
+// MakeConnString will build the connection string for the vertica driver. The
+// host is the service object of the first primary subcluster.
+func MakeConnString(vdb *vapi.VerticaDB, user, passwd string) (string, error) {
+	sc := vdb.GetFirstPrimarySubcluster()
+	if sc == nil {
+		return "", fmt.Errorf("could not find a primary subcluster in VerticaDB %s", vdb.Name)
+	}
+	svc := names.GenExtSvcName(vdb, sc)
+	port := vdb.Spec.ServiceClientPort
+	if sc.ServiceClientPort > 0 {
+		port = sc.ServiceClientPort
+	}
+	if port == 0 {
+		port = builder.VerticaClientPort
+	}
+	tlsMode := TLSModeNone
+	if vdb.IsClientServerTLSAuthEnabled() {
+		tlsMode = TLSModeServer
+	}
+	u := url.URL{
+		Scheme:   driverName,
+		User:     url.UserPassword(user, passwd),
+		Host:     fmt.Sprintf("%s.%s:%d", svc.Name, svc.Namespace, port),
+		Path:     "/" + vdb.Spec.DBName,
+		RawQuery: url.Values{"tlsmode": []string{tlsMode}}.Encode(),
+	}
+	return u.String(), nil
+}
@@ -0,0 +1,167 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dbsql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/names"
+)
+
+func TestDBSQL(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "dbsql Suite")
+}
+
+var _ = Describe("dbsql", func() {
+	ctx := context.Background()
+
+	It("should quote identifiers and literals", func() {
+		Expect(QuoteIdentifier("app_user")).Should(Equal(`"app_user"`))
+		Expect(QuoteIdentifier(`a"b`)).Should(Equal(`"a""b"`))
+		Expect(QuoteLiteral("it's")).Should(Equal(`'it''s'`))
+		Expect(QuoteObjectName("store.orders")).Should(Equal(`"store"."orders"`))
+	})
+
+	It("should generate grant and revoke statements", func() {
+		g := v1beta1.VerticaGrant{Privileges: []string{"SELECT", "INSERT"}, ObjectType: v1beta1.GrantObjectTypeTable,
+			ObjectName: "store.orders"}
+		Expect(GrantSQL(&g, "app_user")).Should(Equal(`GRANT SELECT, INSERT ON TABLE "store"."orders" TO "app_user"`))
+		Expect(RevokeSQL(&g, "app_user")).Should(Equal(`REVOKE SELECT, INSERT ON TABLE "store"."orders" FROM "app_user"`))
+
+		g = v1beta1.VerticaGrant{Privileges: []string{"SELECT"}, ObjectType: v1beta1.GrantObjectTypeAllTablesInSchema,
+			ObjectName: "store"}
+		Expect(GrantSQL(&g, "r")).Should(Equal(`GRANT SELECT ON ALL TABLES IN SCHEMA "store" TO "r"`))
+
+		g = v1beta1.VerticaGrant{Privileges: []string{"READ"}, ObjectType: v1beta1.GrantObjectTypeStorageLocation,
+			ObjectName: "/data/loc"}
+		Expect(GrantSQL(&g, "r")).Should(Equal(`GRANT READ ON LOCATION '/data/loc' TO "r"`))
+	})
+
+	It("should build a connection string for the first primary subcluster", func() {
+		vdb := vapi.MakeVDB()
+		vdb.Spec.ServiceClientPort = 5433
+		connStr, err := MakeConnString(vdb, "dbadmin", "p@ss word")
+		Expect(err).Should(Succeed())
+		svc := names.GenExtSvcName(vdb, &vdb.Spec.Subclusters[0])
+		Expect(connStr).Should(Equal(fmt.Sprintf("vertica://dbadmin:p%%40ss%%20word@%s.%s:5433/%s?tlsmode=server",
+			svc.Name, svc.Namespace, vdb.Spec.DBName)))
+	})
+
+	It("should revoke roles and grants that are no longer in the spec", func() {
+		db, mock, err := sqlmock.New()
+		Expect(err).Should(Succeed())
+		defer db.Close()
+
+		mock.ExpectExec(`REVOKE "old_role" FROM "u1"`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`GRANT "reader" TO "u1"`).WillReturnResult(sqlmock.NewResult(0, 0))
+		Expect(SyncRoles(ctx, db, "u1", []string{"reader", "old_role"}, []string{"reader"})).Should(Succeed())
+
+		kept := v1beta1.VerticaGrant{Privileges: []string{"USAGE"}, ObjectType: v1beta1.GrantObjectTypeSchema, ObjectName: "s1"}
+		dropped := v1beta1.VerticaGrant{Privileges: []string{"USAGE"}, ObjectType: v1beta1.GrantObjectTypeSchema, ObjectName: "s2"}
+		mock.ExpectExec(`REVOKE USAGE ON SCHEMA "s2" FROM "u1"`).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`GRANT USAGE ON SCHEMA "s1" TO "u1"`).WillReturnResult(sqlmock.NewResult(0, 0))
+		Expect(SyncGrants(ctx, db, "u1", []v1beta1.VerticaGrant{kept, dropped}, []v1beta1.VerticaGrant{kept})).Should(Succeed())
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+
+	It("should check if a user exists", func() {
+		db, mock, err := sqlmock.New()
+		Expect(err).Should(Succeed())
+		defer db.Close()
+
+		mock.ExpectQuery("SELECT COUNT").WithArgs("u1").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		Expect(UserExists(ctx, db, "u1")).Should(BeTrue())
+		mock.ExpectQuery("SELECT COUNT").WithArgs("r1").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		Expect(RoleExists(ctx, db, "r1")).Should(BeFalse())
+	})
+
+	It("should generate resource pool statements", func() {
+		maxConcurrency := int32(4)
+		p := ResourcePoolParams{MemorySize: "4G", MaxConcurrency: &maxConcurrency}
+		Expect(CreateResourcePoolSQL("etl", "sc1", &p)).Should(
+			Equal(`CREATE RESOURCE POOL "etl" FOR SUBCLUSTER "sc1" MEMORYSIZE '4G' MAXCONCURRENCY 4`))
+		Expect(AlterResourcePoolSQL("etl", "", &p)).Should(
+			Equal(`ALTER RESOURCE POOL "etl" MEMORYSIZE '4G' MAXCONCURRENCY 4 PLANNEDCONCURRENCY DEFAULT PRIORITY DEFAULT QUEUETIMEOUT DEFAULT`))
+		Expect(DropResourcePoolSQL("etl", "sc1")).Should(Equal(`DROP RESOURCE POOL "etl" FOR SUBCLUSTER "sc1"`))
+	})
+
+	It("should check if a resource pool exists for a subcluster", func() {
+		db, mock, err := sqlmock.New()
+		Expect(err).Should(Succeed())
+		defer db.Close()
+
+		mock.ExpectQuery("SELECT COUNT.*subcluster_name").WithArgs("etl", "sc1").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		Expect(ResourcePoolExists(ctx, db, "etl", "sc1")).Should(BeTrue())
+		mock.ExpectQuery("SELECT COUNT.*subcluster_name").WithArgs("etl").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		Expect(ResourcePoolExists(ctx, db, "etl", "")).Should(BeFalse())
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+
+	It("should sample vertica metrics from the system tables", func() {
+		db, mock, err := sqlmock.New()
+		Expect(err).Should(Succeed())
+		defer db.Close()
+
+		vm := vapi.VerticaMetricSpec{Metric: vapi.VerticaMetricQueueLength, ResourcePool: "general"}
+		mock.ExpectQuery("FROM v_monitor.resource_queues").WithArgs("general").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+		Expect(SampleVerticaMetric(ctx, db, &vm)).Should(Equal(int64(7)))
+
+		vm = vapi.VerticaMetricSpec{Metric: vapi.VerticaMetricQueryLatency}
+		query, _, err := VerticaMetricQuery(&vm)
+		Expect(err).Should(Succeed())
+		Expect(query).Should(ContainSubstring("-60, NOW()"))
+		vm.Metric = "notametric"
+		_, err = SampleVerticaMetric(ctx, db, &vm)
+		Expect(err).ShouldNot(Succeed())
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+
+	It("should find the activity of each subcluster and cancel its queries", func() {
+		db, mock, err := sqlmock.New()
+		Expect(err).Should(Succeed())
+		defer db.Close()
+
+		mock.ExpectQuery("FROM v_catalog.subclusters sc LEFT JOIN v_monitor.sessions").
+			WillReturnRows(sqlmock.NewRows([]string{"subcluster_name", "sessions", "queries"}).
+				AddRow("sc1", 4, 1).AddRow("sc2", 0, 0))
+		activity, err := GetSubclusterActivity(ctx, db)
+		Expect(err).Should(Succeed())
+		Expect(activity).Should(HaveLen(2))
+		Expect(activity["sc1"]).Should(Equal(SubclusterActivity{ActiveSessions: 4, RunningQueries: 1}))
+
+		mock.ExpectQuery("WHERE sc.subcluster_name = ").WithArgs("sc1").
+			WillReturnRows(sqlmock.NewRows([]string{"session_id", "statement_id"}).
+				AddRow("v_db_node0001-1:0x10", 3).AddRow("v_db_node0001-1:0x11", 8))
+		mock.ExpectExec("INTERRUPT_STATEMENT").WithArgs("v_db_node0001-1:0x10", 3).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("INTERRUPT_STATEMENT").WithArgs("v_db_node0001-1:0x11", 8).
+			WillReturnError(fmt.Errorf("statement not found"))
+		Expect(CancelSubclusterQueries(ctx, db, "sc1")).Should(Equal(1))
+		Expect(mock.ExpectationsWereMet()).Should(Succeed())
+	})
+})
@@ -0,0 +1,154 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dbsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/vertica/vertica-kubernetes/api/v1beta1"
+)
+
+// QuoteIdentifier will quote an identifier, such as a user or role name, so
+// it can be safely embedded in a SQL statement.
+func QuoteIdentifier(id string) string {
+	return `"` + strings.ReplaceAll(id, `"`, `""`) + `"`
+}
+
+// QuoteLiteral will quote a string literal, such as a password, so it can be
+// safely embedded in a SQL statement.
+func QuoteLiteral(lit string) string {
+	return "'" + strings.ReplaceAll(lit, "'", "''") + "'"
+}
+
+// QuoteObjectName will quote each part of a possibly qualified object name.
+// For instance, store.orders becomes "store"."orders".
+func QuoteObjectName(name string) string {
+	parts := strings.Split(name, ".")
+	for i := range parts {
+		parts[i] = QuoteIdentifier(parts[i])
+	}
+	return strings.Join(parts, ".")
+}
+
+// quoteIdentifiers will quote each identifier and return them as a comma
+// separated list.
+func quoteIdentifiers(ids []string) string {
+	quoted := make([]string, len(ids))
+	for i := range ids {
+		quoted[i] = QuoteIdentifier(ids[i])
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// objectClause returns the ON clause of a grant or revoke statement
+func objectClause(g *v1beta1.VerticaGrant) string {
+	if g.ObjectType == v1beta1.GrantObjectTypeDatabase {
+		return fmt.Sprintf("DATABASE %s", QuoteIdentifier(g.ObjectName))
+	}
+	if g.ObjectType == v1beta1.GrantObjectTypeStorageLocation {
+		return fmt.Sprintf("LOCATION %s", QuoteLiteral(g.ObjectName))
+	}
+	return fmt.Sprintf("%s %s", g.ObjectType, QuoteObjectName(g.ObjectName))
+}
+
+// GrantSQL returns the statement that grants the privileges to the grantee
+func GrantSQL(g *v1beta1.VerticaGrant, grantee string) string {
+	return fmt.Sprintf("GRANT %s ON %s TO %s", strings.Join(g.Privileges, ", "), objectClause(g), QuoteIdentifier(grantee))
+}
+
+// RevokeSQL returns the statement that revokes the privileges from the grantee
+func RevokeSQL(g *v1beta1.VerticaGrant, grantee string) string {
+	return fmt.Sprintf("REVOKE %s ON %s FROM %s", strings.Join(g.Privileges, ", "), objectClause(g), QuoteIdentifier(grantee))
+}
+
+// SyncRoles will grant the desired roles to the grantee. Any role in applied
+// that is no longer desired is revoked.
+func SyncRoles(ctx context.Context, conn *sql.DB, grantee string, applied, desired []string) error {
+	want := make(map[string]bool, len(desired))
+	for _, r := range desired {
+		want[strings.ToLower(r)] = true
+	}
+	revoke := []string{}
+	for _, r := range applied {
+		if !want[strings.ToLower(r)] {
+			revoke = append(revoke, r)
+		}
+	}
+	if len(revoke) > 0 {
+		stmt := fmt.Sprintf("REVOKE %s FROM %s", quoteIdentifiers(revoke), QuoteIdentifier(grantee))
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to revoke roles from %s: %w", grantee, err)
+		}
+	}
+	if len(desired) > 0 {
+		stmt := fmt.Sprintf("GRANT %s TO %s", quoteIdentifiers(desired), QuoteIdentifier(grantee))
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to grant roles to %s: %w", grantee, err)
+		}
+	}
+	return nil
+}
+
+// SyncGrants will grant the desired privileges to the grantee. Any grant in
+// applied that is no longer desired is revoked first.
+func SyncGrants(ctx context.Context, conn *sql.DB, grantee string, applied, desired []v1beta1.VerticaGrant) error {
+	for i := range applied {
+		if containsGrant(desired, &applied[i]) {
+			continue
+		}
+		if _, err := conn.ExecContext(ctx, RevokeSQL(&applied[i], grantee)); err != nil {
+			return fmt.Errorf("failed to revoke privileges from %s: %w", grantee, err)
+		}
+	}
+	for i := range desired {
+		if _, err := conn.ExecContext(ctx, GrantSQL(&desired[i], grantee)); err != nil {
+			return fmt.Errorf("failed to grant privileges to %s: %w", grantee, err)
+		}
+	}
+	return nil
+}
+
+// containsGrant returns true if the grant is found in the list
+func containsGrant(grants []v1beta1.VerticaGrant, g *v1beta1.VerticaGrant) bool {
+	for i := range grants {
+		if reflect.DeepEqual(grants[i], *g) {
+			return true
+		}
+	}
+	return false
+}
+
+// UserExists returns true if the user is defined in the database
+func UserExists(ctx context.Context, conn *sql.DB, userName string) (bool, error) {
+	return objectExists(ctx, conn, "SELECT COUNT(*) FROM v_catalog.users WHERE lower(user_name) = lower(?)", userName)
+}
+
+// RoleExists returns true if the role is defined in the database
+func RoleExists(ctx context.Context, conn *sql.DB, roleName string) (bool, error) {
+	return objectExists(ctx, conn, "SELECT COUNT(*) FROM v_catalog.roles WHERE lower(name) = lower(?)", roleName)
+}
+
+func objectExists(ctx context.Context, conn *sql.DB, query, name string) (bool, error) {
+	var count int
+	if err := conn.QueryRowContext(ctx, query, name).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,68 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dbsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+)
+
+// DefaultMetricLookbackSeconds is how far back queryLatency looks when the
+// spec doesn't say.
+const DefaultMetricLookbackSeconds = 60
+
+// VerticaMetricQuery returns the query, and its arguments, that samples the
+// given vertica metric from the system tables. The query returns a single
+// integer.
+func VerticaMetricQuery(vm *vapi.VerticaMetricSpec) (query string, args []any, err error) {
+	switch vm.Metric {
+	case vapi.VerticaMetricQueueLength:
+		if vm.ResourcePool == "" {
+			return "SELECT COUNT(*) FROM v_monitor.resource_queues", nil, nil
+		}
+		return "SELECT COUNT(*) FROM v_monitor.resource_queues WHERE lower(pool_name) = lower(?)",
+			[]any{vm.ResourcePool}, nil
+	case vapi.VerticaMetricActiveSessions:
+		// The session we sample from is excluded since it is always active
+		return "SELECT COUNT(*) FROM v_monitor.sessions WHERE statement_id IS NOT NULL " +
+			"AND session_id NOT IN (SELECT session_id FROM v_monitor.current_session)", nil, nil
+	case vapi.VerticaMetricQueryLatency:
+		lookback := vm.LookbackSeconds
+		if lookback <= 0 {
+			lookback = DefaultMetricLookbackSeconds
+		}
+		return fmt.Sprintf("SELECT COALESCE(AVG(request_duration_ms), 0)::INT FROM v_monitor.query_requests "+
+			"WHERE end_timestamp > TIMESTAMPADD('second', -%d, NOW())", lookback), nil, nil
+	default:
+		return "", nil, fmt.Errorf("unknown vertica metric %q", vm.Metric)
+	}
+}
+
+// SampleVerticaMetric returns the current value of the given vertica metric
+func SampleVerticaMetric(ctx context.Context, conn *sql.DB, vm *vapi.VerticaMetricSpec) (int64, error) {
+	query, args, err := VerticaMetricQuery(vm)
+	if err != nil {
+		return 0, err
+	}
+	var value int64
+	if err := conn.QueryRowContext(ctx, query, args...).Scan(&value); err != nil {
+		return 0, fmt.Errorf("failed to sample vertica metric %s: %w", vm.Metric, err)
+	}
+	return value, nil
+}
@@ -0,0 +1,110 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dbsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// ResourcePoolParams are the settings of a resource pool. A nil or empty
+// setting is left to the database default.
+type ResourcePoolParams struct {
+	MemorySize         string
+	MaxConcurrency     *int32
+	PlannedConcurrency *int32
+	Priority           *int32
+	QueueTimeout       *int32
+}
+
+// ResourcePoolExists returns true if the resource pool is defined in the
+// database. If subcluster is set, only the pool for that subcluster counts.
+// Otherwise, only the pool that is global to the database counts.
+func ResourcePoolExists(ctx context.Context, conn *sql.DB, poolName, subcluster string) (bool, error) {
+	var count int
+	var err error
+	if subcluster == "" {
+		err = conn.QueryRowContext(ctx,
+			"SELECT COUNT(*) FROM v_catalog.resource_pools WHERE lower(name) = lower(?) "+
+				"AND coalesce(subcluster_name, '') = ''", poolName).Scan(&count)
+	} else {
+		err = conn.QueryRowContext(ctx,
+			"SELECT COUNT(*) FROM v_catalog.resource_pools WHERE lower(name) = lower(?) "+
+				"AND lower(subcluster_name) = lower(?)", poolName, subcluster).Scan(&count)
+	}
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CreateResourcePoolSQL returns the statement that creates the resource pool.
+// If subcluster is set, the pool only exists on the nodes of that subcluster.
+func CreateResourcePoolSQL(poolName, subcluster string, p *ResourcePoolParams) string {
+	return fmt.Sprintf("CREATE RESOURCE POOL %s%s", poolTarget(poolName, subcluster), p.clause(false))
+}
+
+// AlterResourcePoolSQL returns the statement that changes every setting of the
+// resource pool. Settings that aren't given are reset to their default.
+func AlterResourcePoolSQL(poolName, subcluster string, p *ResourcePoolParams) string {
+	return fmt.Sprintf("ALTER RESOURCE POOL %s%s", poolTarget(poolName, subcluster), p.clause(true))
+}
+
+// DropResourcePoolSQL returns the statement that drops the resource pool
+func DropResourcePoolSQL(poolName, subcluster string) string {
+	return fmt.Sprintf("DROP RESOURCE POOL %s", poolTarget(poolName, subcluster))
+}
+
+// poolTarget returns the pool name along with the subcluster it is for
+func poolTarget(poolName, subcluster string) string {
+	if subcluster == "" {
+		return QuoteIdentifier(poolName)
+	}
+	return fmt.Sprintf("%s FOR SUBCLUSTER %s", QuoteIdentifier(poolName), QuoteIdentifier(subcluster))
+}
+
+// clause returns the settings part of a create or alter statement. When
+// withDefaults is true, unset settings are reset with the DEFAULT keyword.
+func (p *ResourcePoolParams) clause(withDefaults bool) string {
+	var sb strings.Builder
+	addParam := func(name, val string) {
+		if val == "" {
+			if !withDefaults {
+				return
+			}
+			val = "DEFAULT"
+		}
+		fmt.Fprintf(&sb, " %s %s", name, val)
+	}
+	intVal := func(v *int32) string {
+		if v == nil {
+			return ""
+		}
+		return fmt.Sprintf("%d", *v)
+	}
+	memSize := ""
+	if p.MemorySize != "" {
+		memSize = QuoteLiteral(p.MemorySize)
+	}
+	addParam("MEMORYSIZE", memSize)
+	addParam("MAXCONCURRENCY", intVal(p.MaxConcurrency))
+	addParam("PLANNEDCONCURRENCY", intVal(p.PlannedConcurrency))
+	addParam("PRIORITY", intVal(p.Priority))
+	addParam("QUEUETIMEOUT", intVal(p.QueueTimeout))
+	return sb.String()
+}
@@ -85,6 +85,8 @@ const (
 	SuperuserPasswordSecretNotFound        = "SuperuserPasswordSecretNotFound"
 	SuperuserPasswordSecretUpdateSucceeded = "SuperuserPasswordSecretUpdateSucceeded"
 	SuperuserPasswordSecretUpdateFailed    = "SuperuserPasswordSecretUpdateFailed"
+	SuperuserPasswordRotated               = "SuperuserPasswordRotated"
+	SuperuserPasswordRotationFailed        = "SuperuserPasswordRotationFailed"
 	UnsupportedVerticaVersion              = "UnsupportedVerticaVersion"
 	ATConfPartiallyCopied                  = "ATConfPartiallyCopied"
 	AuthParmsCopyFailed                    = "AuthParmsCopyFailed"
@@ -103,6 +105,8 @@ const (
 	OperatorUpgrade                        = "OperatorUpgrade"
 	InvalidUpgradePath                     = "InvalidUpgradePath"
 	RebalanceShards                        = "RebalanceShards"
+	RebalanceShardsFailed                  = "RebalanceShardsFailed"
+	RebalanceShardsTimedOut                = "RebalanceShardsTimedOut"
 	DrainNodeRetry                         = "DrainNodeRetry"
 	DrainSubclusterRetry                   = "DrainSubclusterRetry"
 	DrainSubclusterTimeout                 = "DrainSubclusterTimeout"
@@ -146,10 +150,25 @@ const (
 	TLSCertRollbackSucceeded               = "TLSCertRollbackSucceeded"
 	TLSAutoRotateSucceeded                 = "TLSAutoRotateSucceeded"
 	TLSAutoRotateFailed                    = "TLSAutoRotateFailed"
+	TLSCertIssued                          = "TLSCertIssued"
+	TLSCertExpiringSoon                    = "TLSCertExpiringSoon"
+	TLSCertExpired                         = "TLSCertExpired"
 	DBTLSUpdateStarted                     = "DBTLSUpdateStarted"
 	DBTLSUpdateSucceeded                   = "DBTLSUpdateSucceeded"
 	DBTLSUpdateFailed                      = "DBTLSUpdateFailed"
 	DeploymentMethodMismatch               = "DeploymentMethodMismatch"
+	ConfigParameterChanged                 = "ConfigParameterChanged"
+	ConfigParameterChangeFailed            = "ConfigParameterChangeFailed"
+	ConfigParameterCleared                 = "ConfigParameterCleared"
+	ConfigParametersPendingRestart         = "ConfigParametersPendingRestart"
+	HealthWatchdogConfigured               = "HealthWatchdogConfigured"
+	HealthWatchdogConfigFailed             = "HealthWatchdogConfigFailed"
+	HealthWatchdogCheckFailed              = "HealthWatchdogCheckFailed"
+	HealthWatchdogRunawayQuery             = "HealthWatchdogRunawayQuery"
+	HealthWatchdogQueryCancelled           = "HealthWatchdogQueryCancelled"
+	HealthWatchdogCancelFailed             = "HealthWatchdogCancelFailed"
+	ClientTLSAuthConfigured                = "ClientTLSAuthConfigured"
+	ClientTLSAuthConfigFailed              = "ClientTLSAuthConfigFailed"
 )
 
 // Constants for VerticaAutoscaler reconciler
@@ -158,6 +177,18 @@ const (
 	VerticaDBNotFound             = "VerticaDBNotFound"
 	NoSubclusterTemplate          = "NoSubclusterTemplate"
 	PrometheusMetricsNotSupported = "PrometheusMetricsNotSupported"
+	ScheduledScaling              = "ScheduledScaling"
+	InvalidScalingSchedule        = "InvalidScalingSchedule"
+	VerticaMetricSampleFailed     = "VerticaMetricSampleFailed"
+	VerticaMetricScaling          = "VerticaMetricScaling"
+	PredictiveScaling             = "PredictiveScaling"
+	ScaleInVictimSelected         = "ScaleInVictimSelected"
+	ScaleInWaitingForQueries      = "ScaleInWaitingForQueries"
+	ScaleInQueriesCancelled       = "ScaleInQueriesCancelled"
+	ScaleInActivityCheckFailed    = "ScaleInActivityCheckFailed"
+	ScaleInConnectionsPaused      = "ScaleInConnectionsPaused"
+	ScaleInConnectionDrainFailed  = "ScaleInConnectionDrainFailed"
+	ScalingPolicyDecision         = "ScalingPolicyDecision"
 )
 
 // Constants for VerticaScrutinize reconciler
@@ -167,6 +198,9 @@ const (
 	VclusterOpsScrutinizeNotSupported = "VclusterOpsScrutinizeNotSupported"
 	VclusterOpsScrutinizeSucceeded    = "VclusterOpsScrutinizeSucceeded"
 	VclusterOpsScrutinizeFailed       = "VclusterOpsScrutinizeFailed"
+	ScrutinizeAnalysisSucceeded       = "ScrutinizeAnalysisSucceeded"
+	ScrutinizeAnalysisFailed          = "ScrutinizeAnalysisFailed"
+	ScrutinizeAnalysisSkipped         = "ScrutinizeAnalysisSkipped"
 	SandboxNotFound                   = "SandboxNotFound"
 )
 
@@ -181,14 +215,84 @@ const (
 
 // Constants for VerticaRestorePointsQuery reconciler
 const (
-	RestoreNotSupported        = "RestoreNotSupported"
-	VrpqAdmintoolsNotSupported = "AdmintoolsNotSupported"
-	ShowRestorePointsStarted   = "ShowRestorePointsStarted"
-	ShowRestorePointsFailed    = "ShowRestorePointsFailed"
-	ShowRestorePointsSucceeded = "ShowRestorePointsSucceeded"
+	RestoreNotSupported           = "RestoreNotSupported"
+	VrpqAdmintoolsNotSupported    = "AdmintoolsNotSupported"
+	ShowRestorePointsStarted      = "ShowRestorePointsStarted"
+	ShowRestorePointsFailed       = "ShowRestorePointsFailed"
+	ShowRestorePointsSucceeded    = "ShowRestorePointsSucceeded"
+	RestorePointNotFound          = "RestorePointNotFound"
+	ReviveCopyVerticaDBCreated    = "ReviveCopyVerticaDBCreated"
+	ReviveCopyVerticaDBExists     = "ReviveCopyVerticaDBExists"
+	ReviveCopyCommunalPathInvalid = "ReviveCopyCommunalPathInvalid"
+)
+
+// Constants for VerticaRestorePointSchedule reconciler
+const (
+	VrpsAdmintoolsNotSupported     = "AdmintoolsNotSupported"
+	ScheduledRestorePointStarted   = "ScheduledRestorePointStarted"
+	ScheduledRestorePointFailed    = "ScheduledRestorePointFailed"
+	ScheduledRestorePointSucceeded = "ScheduledRestorePointSucceeded"
+	InvalidRestorePointSchedule    = "InvalidRestorePointSchedule"
+	RestorePointPruneFailed        = "RestorePointPruneFailed"
 )
 
 // Constants for sandbox ConfigMap reconciler
 const (
 	SandboxNotSupported = "SandboxNotSupported"
 )
+
+// Constants for VerticaUser and VerticaRole reconcilers
+const (
+	UserCreated      = "UserCreated"
+	UserUpdated      = "UserUpdated"
+	UserUpdateFailed = "UserUpdateFailed"
+	RoleCreated      = "RoleCreated"
+	RoleUpdated      = "RoleUpdated"
+	RoleUpdateFailed = "RoleUpdateFailed"
+	DatabaseNotReady = "DatabaseNotReady"
+)
+
+// Constants for VerticaResourcePool reconciler
+const (
+	ResourcePoolCreated            = "ResourcePoolCreated"
+	ResourcePoolScaled             = "ResourcePoolScaled"
+	ResourcePoolDropped            = "ResourcePoolDropped"
+	ResourcePoolUpdateFailed       = "ResourcePoolUpdateFailed"
+	ResourcePoolSubclusterNotFound = "ResourcePoolSubclusterNotFound"
+)
+
+// Constants for VerticaBackup reconciler
+const (
+	BackupStarted            = "BackupStarted"
+	BackupSucceeded          = "BackupSucceeded"
+	BackupFailed             = "BackupFailed"
+	BackupNotSupported       = "BackupNotSupported"
+	RestoreStarted           = "RestoreStarted"
+	RestoreSucceeded         = "RestoreSucceeded"
+	RestoreFailed            = "RestoreFailed"
+	RestoreRequiresStoppedDB = "RestoreRequiresStoppedDB"
+)
+
+// Constants for VerticaHealthCheck reconciler
+const (
+	VhcAdmintoolsNotSupported = "AdmintoolsNotSupported"
+	HealthCheckFailed         = "HealthCheckFailed"
+	HealthCheckLockWait       = "HealthCheckLockWait"
+	HealthCheckSlowEvent      = "HealthCheckSlowEvent"
+	HealthCheckMissingRelease = "HealthCheckMissingLockRelease"
+	HealthCheckRecovered      = "HealthCheckRecovered"
+)
+
+// Constants for VerticaWorkloadReplay reconciler
+const (
+	VwrAdmintoolsNotSupported = "AdmintoolsNotSupported"
+	VwrSandboxNotFound        = "SandboxNotFound"
+	VwrIncompatibleDB         = "IncompatibleDB"
+	WorkloadCaptureStarted    = "WorkloadCaptureStarted"
+	WorkloadCaptureSucceeded  = "WorkloadCaptureSucceeded"
+	WorkloadCaptureFailed     = "WorkloadCaptureFailed"
+	WorkloadReplayStarted     = "WorkloadReplayStarted"
+	WorkloadReplaySucceeded   = "WorkloadReplaySucceeded"
+	WorkloadReplayFailed      = "WorkloadReplayFailed"
+	WorkloadReplayRegressions = "WorkloadReplayRegressions"
+)
@@ -22,6 +22,7 @@ import (
 
 	vapi "github.com/vertica/vertica-kubernetes/api/v1"
 	"github.com/vertica/vertica-kubernetes/pkg/paths"
+	"github.com/vertica/vertica-kubernetes/pkg/secrets"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -37,7 +38,8 @@ func GetPath(ctx context.Context, clnt client.Client, vdb *vapi.VerticaDB) (stri
 	secret := &corev1.Secret{}
 	nm := types.NamespacedName{
 		Namespace: vdb.Namespace,
-		Name:      vdb.Spec.LicenseSecret,
+		// A license stored in Vault or Azure Key Vault is read from its mirror
+		Name: secrets.ResolveMirroredSecretName(vdb.Spec.LicenseSecret),
 	}
 	if err := clnt.Get(ctx, nm, secret); err != nil {
 		return "", err
@@ -17,6 +17,7 @@ package meta
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -45,6 +46,12 @@ const (
 	// true|ON|1 value.
 	PauseOperatorAnnotation = "vertica.com/pause"
 
+	// If this annotation is set on a VerticaDB, the operator stops acting on
+	// it. Instead, it computes the actions it would take for the current spec
+	// and records them in status.plan. This can be used to review a change
+	// before it is applied. Remove the annotation to apply the plan.
+	PlanAnnotation = "vertica.com/plan"
+
 	// This is a feature flag for using vertica without admintools. Set this
 	// annotation in the VerticaDB that you want to use the new vclusterOps
 	// library for any vertica admin task. The value of this annotation is
@@ -348,6 +355,11 @@ const (
 	// attempted, should issue an error indicating so.
 	ScrutinizeLogAgeHours = "vertica.com/scrutinize-log-age-hours"
 
+	// Set this to true to have the scrutinize pod also run the slow event and
+	// lock analyses over the same time window that scrutinize collects logs
+	// for. The results are written as a JSON report next to the tarball.
+	ScrutinizeAnalysisAnnotation = "vertica.com/scrutinize-analysis"
+
 	// This is applied to the statefulset to identify what replica group it is
 	// in. Replica groups are assigned during online upgrade. Valid values
 	// are defined under the annotation name.
@@ -439,6 +451,28 @@ const (
 	ReplicationPollingFrequencyAnnotation = "vertica.com/replication-polling-frequency"
 	ReplicationDefaultPollingFrequency    = 5
 
+	// The maximum amount of time, in seconds, the operator waits for a shard
+	// rebalance to finish. When exceeded, the operator stops waiting and backs
+	// off for the same amount of time before it tries the rebalance again. A
+	// value of 0 means there is no limit.
+	RebalanceShardsMaxDurationAnnotation = "vertica.com/rebalance-shards-max-duration"
+	// How often, in seconds, the operator polls the progress of an ongoing
+	// shard rebalance.
+	RebalanceShardsPollingFrequencyAnnotation = "vertica.com/rebalance-shards-polling-frequency"
+	RebalanceShardsDefaultPollingFrequency    = 10
+
+	// A comma separated list of days before a certificate expires at which the
+	// operator warns about it. The operator sends a warning event and sets the
+	// CertificateExpiringSoon condition once a certificate is within the
+	// largest threshold.
+	CertExpiryWarningDaysAnnotation = "vertica.com/cert-expiry-warning-days"
+	CertExpiryDefaultWarningDays    = "30,7,1"
+
+	// Set this on a VerticaResourcePool to delete it without dropping the
+	// resource pool from the database. Use it when the database can't be
+	// reached, as the deletion otherwise waits until the pool can be dropped.
+	SkipResourcePoolDropAnnotation = "vertica.com/skip-resource-pool-drop"
+
 	// Annotation set in a sandbox configMap. Indicates that routing must be disabled
 	// on the sandbox nodes.
 	DisableRoutingAnnotation = "vertica.com/disable-routing"
@@ -482,6 +516,15 @@ const (
 
 	// This is an internal annotation. It is used to indicate we've set HTTPS TLS in offline upgrade.
 	OfflineUpgradeHTTPSSetAnnotation = "vertica.com/offline-https-set"
+
+	// This is an internal annotation. It is set on the Kubernetes secrets that
+	// mirror a secret stored in HashiCorp Vault or Azure Key Vault, and holds
+	// the path reference of the source secret.
+	MirroredSecretSourceAnnotation = "vertica.com/mirrored-secret-source" // #nosec G101
+
+	// This is an internal annotation. It is set on the job that replays a
+	// workload once the operator has copied the captured workload into it.
+	WorkloadReplayInputCopiedAnnotation = "vertica.com/workload-replay-input-copied"
 )
 
 // IsPauseAnnotationSet will check the annotations for a special value that will
@@ -490,6 +533,12 @@ func IsPauseAnnotationSet(annotations map[string]string) bool {
 	return lookupBoolAnnotation(annotations, PauseOperatorAnnotation, false /* default value */)
 }
 
+// IsPlanAnnotationSet will check the annotations for the plan annotation. If
+// set, the operator only computes the actions it would take.
+func IsPlanAnnotationSet(annotations map[string]string) bool {
+	return lookupBoolAnnotation(annotations, PlanAnnotation, false /* default value */)
+}
+
 // UseVClusterOps returns true if all admin commands should use the vclusterOps
 // library rather than admintools.
 func UseVClusterOps(annotations map[string]string) bool {
@@ -793,6 +842,12 @@ func GetScrutinizeLogAgeHours(annotations map[string]string) int {
 	return lookupIntAnnotation(annotations, ScrutinizeLogAgeHours, 0 /* default value */)
 }
 
+// IsScrutinizeAnalysisEnabled returns true if the cluster health analysis
+// should run as part of scrutinize
+func IsScrutinizeAnalysisEnabled(annotations map[string]string) bool {
+	return lookupBoolAnnotation(annotations, ScrutinizeAnalysisAnnotation, false /* default value */)
+}
+
 // GetOnlineUpgradeSandbox returns the name of the sandbox used for online upgrade.
 func GetOnlineUpgradeSandbox(annotations map[string]string) string {
 	return lookupStringAnnotation(annotations, OnlineUpgradeSandboxAnnotation, "")
@@ -871,6 +926,56 @@ func GetReplicationPollingFrequency(annotations map[string]string) int {
 	return lookupIntAnnotation(annotations, ReplicationPollingFrequencyAnnotation, ReplicationDefaultPollingFrequency)
 }
 
+// GetRebalanceShardsMaxDuration returns the maximum number of seconds the
+// operator waits for a shard rebalance. 0 means there is no limit.
+func GetRebalanceShardsMaxDuration(annotations map[string]string) int {
+	return lookupIntAnnotation(annotations, RebalanceShardsMaxDurationAnnotation, 0 /* default value */)
+}
+
+// GetRebalanceShardsPollingFrequency returns the frequency (in seconds) the
+// operator will poll the progress of a shard rebalance
+func GetRebalanceShardsPollingFrequency(annotations map[string]string) int {
+	freq := lookupIntAnnotation(annotations, RebalanceShardsPollingFrequencyAnnotation, RebalanceShardsDefaultPollingFrequency)
+	if freq <= 0 {
+		return RebalanceShardsDefaultPollingFrequency
+	}
+	return freq
+}
+
+// SkipResourcePoolDrop returns true if a VerticaResourcePool can be deleted
+// without dropping its resource pool from the database
+func SkipResourcePoolDrop(annotations map[string]string) bool {
+	return lookupBoolAnnotation(annotations, SkipResourcePoolDropAnnotation, false /* default value */)
+}
+
+// GetCertExpiryWarningDays returns the thresholds, in days before expiry, at
+// which the operator warns about a certificate. They are sorted from largest
+// to smallest. An invalid list falls back to the default.
+func GetCertExpiryWarningDays(annotations map[string]string) []int {
+	days, err := parseCertExpiryWarningDays(lookupStringAnnotation(annotations, CertExpiryWarningDaysAnnotation,
+		CertExpiryDefaultWarningDays))
+	if err != nil {
+		days, _ = parseCertExpiryWarningDays(CertExpiryDefaultWarningDays)
+	}
+	return days
+}
+
+func parseCertExpiryWarningDays(val string) ([]int, error) {
+	days := []int{}
+	for _, d := range strings.Split(val, ",") {
+		day, err := strconv.Atoi(strings.TrimSpace(d))
+		if err != nil {
+			return nil, err
+		}
+		if day <= 0 {
+			return nil, fmt.Errorf("threshold must be a positive number of days: %d", day)
+		}
+		days = append(days, day)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(days)))
+	return days, nil
+}
+
 // GetDisableRouting returns true if routing must be disabled on the sandbox
 // nodes.
 func GetDisableRouting(annotations map[string]string) bool {
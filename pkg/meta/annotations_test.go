@@ -45,6 +45,14 @@ var _ = Describe("annotations", func() {
 		Ω(IsPauseAnnotationSet(ann)).Should(BeFalse())
 	})
 
+	It("should treat the plan annotation as a bool", func() {
+		Ω(IsPlanAnnotationSet(nil)).Should(BeFalse())
+		ann := map[string]string{PlanAnnotation: "true"}
+		Ω(IsPlanAnnotationSet(ann)).Should(BeTrue())
+		ann[PlanAnnotation] = "false"
+		Ω(IsPlanAnnotationSet(ann)).Should(BeFalse())
+	})
+
 	It("should treat vclusterOps annotation as a bool", func() {
 		ann := map[string]string{VClusterOpsAnnotation: VClusterOpsAnnotationTrue}
 		Ω(UseVClusterOps(ann)).Should(BeTrue())
@@ -179,6 +187,29 @@ var _ = Describe("annotations", func() {
 		}
 		Ω(GetScrutinizeLogAgeHours(ann)).Should(Equal(logAgeHours))
 	})
+
+	It("should return the rebalance shards settings based on the annotations map", func() {
+		ann := map[string]string{}
+		Ω(GetRebalanceShardsMaxDuration(ann)).Should(Equal(0))
+		Ω(GetRebalanceShardsPollingFrequency(ann)).Should(Equal(RebalanceShardsDefaultPollingFrequency))
+		ann[RebalanceShardsMaxDurationAnnotation] = "3600"
+		ann[RebalanceShardsPollingFrequencyAnnotation] = "0"
+		Ω(GetRebalanceShardsMaxDuration(ann)).Should(Equal(3600))
+		Ω(GetRebalanceShardsPollingFrequency(ann)).Should(Equal(RebalanceShardsDefaultPollingFrequency))
+		ann[RebalanceShardsPollingFrequencyAnnotation] = "30"
+		Ω(GetRebalanceShardsPollingFrequency(ann)).Should(Equal(30))
+	})
+
+	It("should return the cert expiry warning days sorted from largest to smallest", func() {
+		ann := map[string]string{}
+		Ω(GetCertExpiryWarningDays(ann)).Should(Equal([]int{30, 7, 1}))
+		ann[CertExpiryWarningDaysAnnotation] = "14, 60,3"
+		Ω(GetCertExpiryWarningDays(ann)).Should(Equal([]int{60, 14, 3}))
+		ann[CertExpiryWarningDaysAnnotation] = "14,abc"
+		Ω(GetCertExpiryWarningDays(ann)).Should(Equal([]int{30, 7, 1}))
+		ann[CertExpiryWarningDaysAnnotation] = "0"
+		Ω(GetCertExpiryWarningDays(ann)).Should(Equal([]int{30, 7, 1}))
+	})
 })
 
 func makeResourceAnnotations(fn func(resourceName corev1.ResourceName) string) map[string]string {
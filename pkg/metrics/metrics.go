@@ -30,20 +30,28 @@ const (
 
 	// The subsystem is the second part of the name.  This comes after the
 	// namespace and before the metric name.
-	UpgradeSubsystem        = "upgrade"
-	ClusterRestartSubsystem = "cluster_restart"
-	NodesRestartSubsystem   = "nodes_restart"
-	SubclusterSubsystem     = "subclusters"
+	UpgradeSubsystem         = "upgrade"
+	ClusterRestartSubsystem  = "cluster_restart"
+	NodesRestartSubsystem    = "nodes_restart"
+	SubclusterSubsystem      = "subclusters"
+	RebalanceShardsSubsystem = "rebalance_shards"
+	HealthCheckSubsystem     = "health_check"
+	HealthWatchdogSubsystem  = "health_watchdog"
+	CertificateSubsystem     = "certificate"
 
 	// Names of the labels that we can apply to metrics.
 	NamespaceLabel        = "namespace"
 	VerticaDBLabel        = "verticadb"
 	SubclusterOidLabel    = "subcluster_oid"
 	ReviveInstanceIDLabel = "revive_instance_id"
+	TLSConfigLabel        = "tls_config"
+	SecretLabel           = "secret"
 )
 
 var (
 	AdminToolsBucket = []float64{1, 5, 10, 30, 60, 120, 300, 600}
+	// A rebalance of a big Eon database can run for over an hour
+	RebalanceShardsBucket = []float64{10, 30, 60, 300, 600, 1800, 3600, 7200}
 
 	UpgradeCount = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -143,6 +151,161 @@ var (
 		},
 		[]string{NamespaceLabel, VerticaDBLabel, ReviveInstanceIDLabel, SubclusterOidLabel},
 	)
+	RebalanceShardsAttempt = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: RebalanceShardsSubsystem,
+			Name:      "attempted_total",
+			Help:      "The number of times we attempted to rebalance the shards of a subcluster",
+		},
+		[]string{NamespaceLabel, VerticaDBLabel, ReviveInstanceIDLabel},
+	)
+	RebalanceShardsFailed = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: RebalanceShardsSubsystem,
+			Name:      "failed_total",
+			Help:      "The number of times a shard rebalance failed or exceeded its maximum duration",
+		},
+		[]string{NamespaceLabel, VerticaDBLabel, ReviveInstanceIDLabel},
+	)
+	RebalanceShardsDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: RebalanceShardsSubsystem,
+			Name:      "seconds",
+			Help:      "The number of seconds it took to rebalance the shards of a subcluster",
+			Buckets:   RebalanceShardsBucket,
+		},
+		[]string{NamespaceLabel, VerticaDBLabel, ReviveInstanceIDLabel},
+	)
+	RebalanceShardsProgress = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: RebalanceShardsSubsystem,
+			Name:      "progress_percent",
+			Help:      "The percentage of shard subscriptions that reached the ACTIVE state in the ongoing rebalance",
+		},
+		[]string{NamespaceLabel, VerticaDBLabel, ReviveInstanceIDLabel},
+	)
+	RebalanceShardsMoved = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: RebalanceShardsSubsystem,
+			Name:      "shards_moved",
+			Help:      "The number of shard subscriptions that reached the ACTIVE state in the ongoing rebalance",
+		},
+		[]string{NamespaceLabel, VerticaDBLabel, ReviveInstanceIDLabel},
+	)
+	HealthCheckFailed = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: HealthCheckSubsystem,
+			Name:      "failed_total",
+			Help:      "The number of times a health check could not be run",
+		},
+		[]string{NamespaceLabel, VerticaDBLabel, ReviveInstanceIDLabel},
+	)
+	HealthCheckLockWaitEvents = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: HealthCheckSubsystem,
+			Name:      "lock_wait_events",
+			Help:      "The number of lock wait events over the threshold found by the last health check",
+		},
+		[]string{NamespaceLabel, VerticaDBLabel, ReviveInstanceIDLabel},
+	)
+	HealthCheckMaxLockWait = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: HealthCheckSubsystem,
+			Name:      "max_lock_wait_seconds",
+			Help:      "The longest series of lock waits found by the last health check",
+		},
+		[]string{NamespaceLabel, VerticaDBLabel, ReviveInstanceIDLabel},
+	)
+	HealthCheckSlowEvents = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: HealthCheckSubsystem,
+			Name:      "slow_events",
+			Help:      "The number of mutex events over the threshold found by the last health check",
+		},
+		[]string{NamespaceLabel, VerticaDBLabel, ReviveInstanceIDLabel},
+	)
+	HealthCheckMaxSlowEvent = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: HealthCheckSubsystem,
+			Name:      "max_slow_event_seconds",
+			Help:      "The duration of the slowest mutex event found by the last health check",
+		},
+		[]string{NamespaceLabel, VerticaDBLabel, ReviveInstanceIDLabel},
+	)
+	HealthCheckMissingLockReleases = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: HealthCheckSubsystem,
+			Name:      "missing_lock_releases",
+			Help:      "The number of locks that were never released found by the last health check",
+		},
+		[]string{NamespaceLabel, VerticaDBLabel, ReviveInstanceIDLabel},
+	)
+	HealthWatchdogRunawayQueries = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: HealthWatchdogSubsystem,
+			Name:      "runaway_queries",
+			Help:      "The number of runaway queries that the health watchdog flagged at the last check",
+		},
+		[]string{NamespaceLabel, VerticaDBLabel, ReviveInstanceIDLabel},
+	)
+	HealthWatchdogCancelledQueries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: HealthWatchdogSubsystem,
+			Name:      "cancelled_queries_total",
+			Help:      "The number of runaway queries found by the health watchdog that were cancelled",
+		},
+		[]string{NamespaceLabel, VerticaDBLabel, ReviveInstanceIDLabel},
+	)
+	HealthWatchdogCancelFailed = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: HealthWatchdogSubsystem,
+			Name:      "cancel_failed_total",
+			Help:      "The number of runaway queries found by the health watchdog that could not be cancelled",
+		},
+		[]string{NamespaceLabel, VerticaDBLabel, ReviveInstanceIDLabel},
+	)
+	HealthWatchdogCheckFailed = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: HealthWatchdogSubsystem,
+			Name:      "check_failed_total",
+			Help:      "The number of times the health watchdog could not be checked for runaway queries",
+		},
+		[]string{NamespaceLabel, VerticaDBLabel, ReviveInstanceIDLabel},
+	)
+	CertificateExpiryTimestamp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: CertificateSubsystem,
+			Name:      "expiry_timestamp_seconds",
+			Help: "The time, in seconds since the epoch, at which the certificate in a TLS secret of the " +
+				"VerticaDB expires. Subtract time() from it to get the time left.",
+		},
+		[]string{NamespaceLabel, VerticaDBLabel, ReviveInstanceIDLabel, TLSConfigLabel, SecretLabel},
+	)
+	WebhookCertificateExpirySeconds = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: CertificateSubsystem,
+			Name:      "webhook_expiry_seconds",
+			Help:      "The number of seconds until the certificate served by the operator's webhook expires",
+		},
+		[]string{NamespaceLabel},
+	)
 	// Add new metrics above this comment.
 	//
 	// Once a metric is added a few other things need to be updated:
@@ -170,6 +333,23 @@ func init() {
 		TotalNodeCount,
 		RunningNodeCount,
 		UpNodeCount,
+		RebalanceShardsAttempt,
+		RebalanceShardsFailed,
+		RebalanceShardsDuration,
+		RebalanceShardsProgress,
+		RebalanceShardsMoved,
+		HealthCheckFailed,
+		HealthCheckLockWaitEvents,
+		HealthCheckMaxLockWait,
+		HealthCheckSlowEvents,
+		HealthCheckMaxSlowEvent,
+		HealthCheckMissingLockReleases,
+		HealthWatchdogRunawayQueries,
+		HealthWatchdogCancelledQueries,
+		HealthWatchdogCancelFailed,
+		HealthWatchdogCheckFailed,
+		CertificateExpiryTimestamp,
+		WebhookCertificateExpirySeconds,
 	)
 }
 
@@ -206,6 +386,22 @@ func HandleVDBDelete(namespaceName, vdbName string, log logr.Logger) {
 	TotalNodeCount.DeletePartialMatch(labels)
 	RunningNodeCount.DeletePartialMatch(labels)
 	UpNodeCount.DeletePartialMatch(labels)
+	RebalanceShardsAttempt.DeletePartialMatch(labels)
+	RebalanceShardsFailed.DeletePartialMatch(labels)
+	RebalanceShardsDuration.DeletePartialMatch(labels)
+	RebalanceShardsProgress.DeletePartialMatch(labels)
+	RebalanceShardsMoved.DeletePartialMatch(labels)
+	HealthCheckFailed.DeletePartialMatch(labels)
+	HealthCheckLockWaitEvents.DeletePartialMatch(labels)
+	HealthCheckMaxLockWait.DeletePartialMatch(labels)
+	HealthCheckSlowEvents.DeletePartialMatch(labels)
+	HealthCheckMaxSlowEvent.DeletePartialMatch(labels)
+	HealthCheckMissingLockReleases.DeletePartialMatch(labels)
+	HealthWatchdogRunawayQueries.DeletePartialMatch(labels)
+	HealthWatchdogCancelledQueries.DeletePartialMatch(labels)
+	HealthWatchdogCancelFailed.DeletePartialMatch(labels)
+	HealthWatchdogCheckFailed.DeletePartialMatch(labels)
+	CertificateExpiryTimestamp.DeletePartialMatch(labels)
 }
 
 // HandleVDBInit will initialized metrics that use verticadb as a
@@ -223,6 +419,21 @@ func HandleVDBInit(vdb *vapi.VerticaDB) {
 	NodesRestartAttempt.WithLabelValues(vdb.Namespace, vdb.Name, reviveInstanceID)
 	NodesRestartFailed.WithLabelValues(vdb.Namespace, vdb.Name, reviveInstanceID)
 	NodesRestartDuration.WithLabelValues(vdb.Namespace, vdb.Name, reviveInstanceID)
+	RebalanceShardsAttempt.WithLabelValues(vdb.Namespace, vdb.Name, reviveInstanceID)
+	RebalanceShardsFailed.WithLabelValues(vdb.Namespace, vdb.Name, reviveInstanceID)
+	RebalanceShardsDuration.WithLabelValues(vdb.Namespace, vdb.Name, reviveInstanceID)
+	RebalanceShardsProgress.WithLabelValues(vdb.Namespace, vdb.Name, reviveInstanceID)
+	RebalanceShardsMoved.WithLabelValues(vdb.Namespace, vdb.Name, reviveInstanceID)
+	HealthCheckFailed.WithLabelValues(vdb.Namespace, vdb.Name, reviveInstanceID)
+	HealthCheckLockWaitEvents.WithLabelValues(vdb.Namespace, vdb.Name, reviveInstanceID)
+	HealthCheckMaxLockWait.WithLabelValues(vdb.Namespace, vdb.Name, reviveInstanceID)
+	HealthCheckSlowEvents.WithLabelValues(vdb.Namespace, vdb.Name, reviveInstanceID)
+	HealthCheckMaxSlowEvent.WithLabelValues(vdb.Namespace, vdb.Name, reviveInstanceID)
+	HealthCheckMissingLockReleases.WithLabelValues(vdb.Namespace, vdb.Name, reviveInstanceID)
+	HealthWatchdogRunawayQueries.WithLabelValues(vdb.Namespace, vdb.Name, reviveInstanceID)
+	HealthWatchdogCancelledQueries.WithLabelValues(vdb.Namespace, vdb.Name, reviveInstanceID)
+	HealthWatchdogCancelFailed.WithLabelValues(vdb.Namespace, vdb.Name, reviveInstanceID)
+	HealthWatchdogCheckFailed.WithLabelValues(vdb.Namespace, vdb.Name, reviveInstanceID)
 }
 
 // MakeVDBLabels return a prometheus.Labels that includes the VerticaDB name
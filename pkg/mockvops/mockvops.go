@@ -99,6 +99,12 @@ func (*MockVClusterOps) VCreateArchive(_ *vclusterops.VCreateArchiveOptions) err
 func (*MockVClusterOps) VSaveRestorePoint(_ *vclusterops.VSaveRestorePointOptions) error {
 	return nil
 }
+func (*MockVClusterOps) VRemoveRestorePoint(_ *vclusterops.VRemoveRestorePointOptions) error {
+	return nil
+}
+func (*MockVClusterOps) VDropArchive(_ *vclusterops.VDropArchiveOptions) error {
+	return nil
+}
 func (*MockVClusterOps) VPromoteSandboxToMain(_ *vclusterops.VPromoteSandboxToMainOptions) error {
 	return nil
 }
@@ -120,6 +126,9 @@ func (m *MockVClusterOps) VPollSubclusterState(_ *vclusterops.VPollSubclusterSta
 func (*MockVClusterOps) VManageConnectionDraining(_ *vclusterops.VManageConnectionDrainingOptions) error {
 	return nil
 }
+func (*MockVClusterOps) VGetDrainingStatus(_ *vclusterops.VGetDrainingStatusOptions) (vclusterops.DrainingStatusList, error) {
+	return vclusterops.DrainingStatusList{}, nil
+}
 func (*MockVClusterOps) VRotateNMACerts(_ *vclusterops.VRotateNMACertsOptions) error {
 	return nil
 }
@@ -139,6 +148,28 @@ func (*MockVClusterOps) VPollHTTPS(_ *vclusterops.VPollHTTPSOptions) error {
 	return nil
 }
 
+func (*MockVClusterOps) VClusterHealth(_ *vclusterops.VClusterHealthOptions) error {
+	return nil
+}
+
+func (*MockVClusterOps) VHealthWatchdogSet(_ *vclusterops.VHealthWatchdogSetOptions) error {
+	return nil
+}
+
+func (*MockVClusterOps) VHealthWatchdogGet(_ *vclusterops.VHealthWatchdogGetOptions) (
+	*[]vclusterops.HealthWatchdogHostValues, error) {
+	return &[]vclusterops.HealthWatchdogHostValues{}, nil
+}
+
+func (*MockVClusterOps) VHealthWatchdogCancelQuery(_ *vclusterops.VHealthWatchdogCancelQueryOptions) (
+	[]vclusterops.HealthWatchdogCancelQueryResponse, error) {
+	return nil, nil
+}
+
+func (*MockVClusterOps) VSetClientTLSAuth(_ *vclusterops.VSetClientTLSAuthOptions) error {
+	return nil
+}
+
 // MakeMockVClusterOpsDispatch will create a mock vcluster dispatcher
 func MakeMockVClusterOpsDispatcher(vdb *vapi.VerticaDB, logger logr.Logger, cl client.Client,
 	setupAPIFunc func(logr.Logger, string) (vadmin.VClusterProvider, logr.Logger)) *vadmin.VClusterOps {
@@ -25,11 +25,14 @@ import (
 )
 
 const (
-	ServerContainer         = "server"
-	NMAContainer            = "nma"
-	ProxyContainer          = "proxy"
-	ScrutinizeInitContainer = "scrutinize"
-	ScrutinizeMainContainer = "main"
+	ServerContainer             = "server"
+	NMAContainer                = "nma"
+	ProxyContainer              = "proxy"
+	ScrutinizeInitContainer     = "scrutinize"
+	ScrutinizeMainContainer     = "main"
+	ScrutinizeAnalysisContainer = "analysis"
+	WorkloadReplayContainer     = "workload"
+	WorkloadReplayMainContainer = "main"
 )
 
 const (
@@ -139,6 +142,12 @@ func GenScaledObjectName(vas *vapi.VerticaAutoscaler) types.NamespacedName {
 	return GenNamespacedName(vas, fmt.Sprintf("%s-keda", vas.Name))
 }
 
+// GenLoadProfileConfigMapName returns the name of the configmap that stores
+// the load profile learned by the predictive policy of the autoscaler
+func GenLoadProfileConfigMapName(vas *vapi.VerticaAutoscaler) types.NamespacedName {
+	return GenNamespacedName(vas, fmt.Sprintf("%s-load-profile", vas.Name))
+}
+
 func GenAuthSecretName(vas *vapi.VerticaAutoscaler, secretName string) types.NamespacedName {
 	return GenNamespacedName(vas, secretName)
 }
@@ -155,6 +164,18 @@ func GenBasicauthSecretName(vdb *vapi.VerticaDB) types.NamespacedName {
 	return GenNamespacedName(vdb, fmt.Sprintf("%s-basic-auth", vdb.Name))
 }
 
+// GenClientTLSAuthSecretName returns the name of the secret that the server
+// TLS configuration is set from when it trusts a client CA
+func GenClientTLSAuthSecretName(vdb *vapi.VerticaDB) types.NamespacedName {
+	return GenNamespacedName(vdb, fmt.Sprintf("%s-client-tls-ca", vdb.Name))
+}
+
 func GenSvcMonitorName(vdb *vapi.VerticaDB) types.NamespacedName {
 	return GenNamespacedName(vdb, fmt.Sprintf("%s-svc-monitor", vdb.Name))
 }
+
+// GenWorkloadReplayJobName returns the name of the job that runs a step, the
+// capture or the replay, of a VerticaWorkloadReplay
+func GenWorkloadReplayJobName(vwr client.Object, step string) types.NamespacedName {
+	return GenNamespacedName(vwr, fmt.Sprintf("%s-%s", vwr.GetName(), step))
+}
@@ -210,6 +210,48 @@ func GetVerticaReplicatorConcurrency() int {
 	return lookupIntEnvVar("CONCURRENCY_VERTICAREPLICATOR", envMustExist)
 }
 
+// GetVerticaRestorePointScheduleConcurrency returns the number of goroutines
+// that will service VerticaRestorePointSchedule CRs.
+func GetVerticaRestorePointScheduleConcurrency() int {
+	return lookupIntEnvVar("CONCURRENCY_VERTICARESTOREPOINTSCHEDULE", envMustExist)
+}
+
+// GetVerticaUserConcurrency returns the number of goroutines that will service
+// VerticaUser CRs.
+func GetVerticaUserConcurrency() int {
+	return lookupIntEnvVar("CONCURRENCY_VERTICAUSER", envMustExist)
+}
+
+// GetVerticaRoleConcurrency returns the number of goroutines that will service
+// VerticaRole CRs.
+func GetVerticaRoleConcurrency() int {
+	return lookupIntEnvVar("CONCURRENCY_VERTICAROLE", envMustExist)
+}
+
+// GetVerticaResourcePoolConcurrency returns the number of goroutines that will service
+// VerticaResourcePool CRs.
+func GetVerticaResourcePoolConcurrency() int {
+	return lookupIntEnvVar("CONCURRENCY_VERTICARESOURCEPOOL", envMustExist)
+}
+
+// GetVerticaBackupConcurrency returns the number of goroutines that will service
+// VerticaBackup CRs.
+func GetVerticaBackupConcurrency() int {
+	return lookupIntEnvVar("CONCURRENCY_VERTICABACKUP", envMustExist)
+}
+
+// GetVerticaHealthCheckConcurrency returns the number of goroutines that will
+// service VerticaHealthCheck CRs.
+func GetVerticaHealthCheckConcurrency() int {
+	return lookupIntEnvVar("CONCURRENCY_VERTICAHEALTHCHECK", envMustExist)
+}
+
+// GetVerticaWorkloadReplayConcurrency returns the number of goroutines that
+// will service VerticaWorkloadReplay CRs.
+func GetVerticaWorkloadReplayConcurrency() int {
+	return lookupIntEnvVar("CONCURRENCY_VERTICAWORKLOADREPLAY", envMustExist)
+}
+
 // GetPrefixName returns the common prefix for all objects used to deploy the
 // operator.
 func GetPrefixName() string {
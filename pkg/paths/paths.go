@@ -40,8 +40,11 @@ const (
 	LogPath                   = "/opt/vertica/log"
 	ScrutinizeTmp             = "/tmp/scrutinize"
 	ScrutinizeLogFile         = "/tmp/scrutinize/vcluster.log"
+	ScrutinizeAnalysisLogFile = "/tmp/scrutinize/vcluster-analysis.log"
 	ScrutinizeDBPasswordDir   = "/etc/password"
 	ScrutinizeDBPasswordFile  = "/etc/password/password"
+	WorkloadReplayTmp         = "/tmp/vwr"
+	WorkloadReplayDoneFile    = "/tmp/vwr/done"
 	PodInfoPath               = "/etc/podinfo"
 	AdminToolsConf            = "/opt/vertica/config/admintools.conf"
 	AuthParmsFile             = "/tmp/auth_parms.conf"
@@ -0,0 +1,217 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The environment variables used to authenticate to Azure Key Vault. They are
+// injected in the pod by the Azure workload identity webhook when the service
+// account is annotated with azure.workload.identity/client-id.
+const (
+	AzureClientIDEnv           = "AZURE_CLIENT_ID"
+	AzureTenantIDEnv           = "AZURE_TENANT_ID"
+	AzureFederatedTokenFileEnv = "AZURE_FEDERATED_TOKEN_FILE"
+	AzureAuthorityHostEnv      = "AZURE_AUTHORITY_HOST"
+
+	defaultAzureAuthorityHost = "https://login.microsoftonline.com/"
+	// The DNS suffix of key vaults in the Azure public cloud
+	defaultAzureKeyVaultDNSSuffix = "vault.azure.net"
+	azureKeyVaultAPIVersion       = "7.4"
+	azureClientAssertionType      = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+	// Number of seconds before the expiry of an access token that we get a
+	// new one.
+	azureTokenExpiryMarginSeconds = 60
+)
+
+// azureTokenResponse is the part of the token response of the Microsoft
+// identity platform that we care about
+type azureTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// azureSecretBundle is the part of the Key Vault get secret response that we
+// care about
+type azureSecretBundle struct {
+	Value string `json:"value"`
+}
+
+// azureTokenCache keeps the access token of the last token exchange so that
+// we don't exchange a token for every secret read.
+var azureTokenCache = struct {
+	sync.Mutex
+	key     string
+	token   string
+	expires time.Time
+}{}
+
+// readFromAzureKeyVault will fetch a secret from Azure Key Vault. The
+// secretName should be of the format azkv://<vault>/<name> or
+// azkv://<vault>/<name>@<version>. Like the other secret stores, the value of
+// the secret is a JSON object whose values are base64 encoded.
+func (m *MultiSourceSecretFetcher) readFromAzureKeyVault(ctx context.Context, secretName string) (map[string][]byte, error) {
+	keyVault, name, version, err := GetAzureKeyVaultSecret(secretName)
+	if err != nil {
+		return nil, err
+	}
+	host := keyVault
+	if !strings.Contains(host, ".") {
+		host = fmt.Sprintf("%s.%s", keyVault, defaultAzureKeyVaultDNSSuffix)
+	}
+	clnt := m.getHTTPClient()
+	token, err := getAzureAccessToken(ctx, clnt, getAzureKeyVaultScope(host))
+	if err != nil {
+		return nil, err
+	}
+	m.Log.Info("Reading secret from Azure Key Vault", "host", host, "name", name, "version", version)
+
+	secretPath := fmt.Sprintf("/secrets/%s", name)
+	if version != "" {
+		secretPath = fmt.Sprintf("%s/%s", secretPath, version)
+	}
+	reqURL := url.URL{
+		Scheme:   "https",
+		Host:     host,
+		Path:     secretPath,
+		RawQuery: url.Values{"api-version": []string{azureKeyVaultAPIVersion}}.Encode(),
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL.String(), http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build the Azure Key Vault request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	body, statusCode, err := doHTTPRequest(clnt, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch secret from Azure Key Vault: %w", err)
+	}
+	if statusCode == http.StatusNotFound {
+		return nil, errors.Join(fmt.Errorf("azure key vault returned status %d: %s", statusCode, body), &NotFoundError{
+			msg: fmt.Sprintf("Could not find the secret '%s' in Azure Key Vault", secretName),
+		})
+	}
+	if statusCode == http.StatusUnauthorized {
+		// The cached token may no longer be valid. Get a new one next time.
+		resetAzureTokenCache()
+	}
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch secret '%s' from Azure Key Vault: status %d: %s",
+			secretName, statusCode, body)
+	}
+
+	bundle := azureSecretBundle{}
+	if err := json.Unmarshal(body, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the Azure Key Vault response for '%s': %w", secretName, err)
+	}
+	contents := make(map[string][]byte)
+	err = json.Unmarshal([]byte(bundle.Value), &contents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the contents of the Azure Key Vault secret '%s': %w", secretName, err)
+	}
+	return contents, nil
+}
+
+// getAzureKeyVaultScope returns the OAuth scope for the key vault with the
+// given host. The scope depends on the cloud the key vault is in.
+func getAzureKeyVaultScope(host string) string {
+	_, dnsSuffix, found := strings.Cut(host, ".")
+	if !found || dnsSuffix == "" {
+		dnsSuffix = defaultAzureKeyVaultDNSSuffix
+	}
+	return fmt.Sprintf("https://%s/.default", dnsSuffix)
+}
+
+// getAzureAccessToken returns an access token for the given scope. It
+// exchanges the federated service account token, projected in the pod by the
+// Azure workload identity webhook, for an access token of the Microsoft
+// identity platform.
+func getAzureAccessToken(ctx context.Context, clnt *http.Client, scope string) (string, error) {
+	clientID := os.Getenv(AzureClientIDEnv)
+	tenantID := os.Getenv(AzureTenantIDEnv)
+	tokenFile := os.Getenv(AzureFederatedTokenFileEnv)
+	if clientID == "" || tenantID == "" || tokenFile == "" {
+		return "", fmt.Errorf("%s, %s and %s must be set to authenticate to Azure Key Vault. "+
+			"Is Azure workload identity enabled for the service account?",
+			AzureClientIDEnv, AzureTenantIDEnv, AzureFederatedTokenFileEnv)
+	}
+	authorityHost := os.Getenv(AzureAuthorityHostEnv)
+	if authorityHost == "" {
+		authorityHost = defaultAzureAuthorityHost
+	}
+
+	cacheKey := strings.Join([]string{authorityHost, tenantID, clientID, scope}, "|")
+	azureTokenCache.Lock()
+	defer azureTokenCache.Unlock()
+	if azureTokenCache.key == cacheKey && time.Now().Before(azureTokenCache.expires) {
+		return azureTokenCache.token, nil
+	}
+
+	assertion, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the federated token for Azure: %w", err)
+	}
+	form := url.Values{
+		"client_id":             []string{clientID},
+		"scope":                 []string{scope},
+		"grant_type":            []string{"client_credentials"},
+		"client_assertion_type": []string{azureClientAssertionType},
+		"client_assertion":      []string{strings.TrimSpace(string(assertion))},
+	}
+	tokenURL := fmt.Sprintf("%s/%s/oauth2/v2.0/token", strings.TrimSuffix(authorityHost, "/"), tenantID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to build the Azure token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	body, statusCode, err := doHTTPRequest(clnt, req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get an access token for Azure Key Vault: %w", err)
+	}
+	if statusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get an access token for Azure Key Vault: status %d: %s", statusCode, body)
+	}
+	resp := azureTokenResponse{}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("failed to unmarshal the Azure token response: %w", err)
+	}
+	if resp.AccessToken == "" {
+		return "", fmt.Errorf("the Azure token response did not have an access token")
+	}
+
+	azureTokenCache.key = cacheKey
+	azureTokenCache.token = resp.AccessToken
+	azureTokenCache.expires = time.Now().Add(time.Duration(resp.ExpiresIn-azureTokenExpiryMarginSeconds) * time.Second)
+	return resp.AccessToken, nil
+}
+
+// resetAzureTokenCache drops the cached Azure access token
+func resetAzureTokenCache() {
+	azureTokenCache.Lock()
+	defer azureTokenCache.Unlock()
+	azureTokenCache.key = ""
+	azureTokenCache.token = ""
+	azureTokenCache.expires = time.Time{}
+}
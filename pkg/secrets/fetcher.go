@@ -21,6 +21,9 @@ import (
 	"errors"
 	"fmt"
 	"hash/crc32"
+	"io"
+	"net/http"
+	"time"
 
 	gsm "cloud.google.com/go/secretmanager/apiv1"
 	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
@@ -35,13 +38,22 @@ import (
 )
 
 // MultiSourceSecretFetcher is secret reader that handles retrival from
-// different sources such as Kubernetes secret store and Google Secrets Manager
-// (GSM).
+// different sources such as Kubernetes secret store, Google Secrets Manager
+// (GSM), AWS Secrets Manager, HashiCorp Vault and Azure Key Vault.
 type MultiSourceSecretFetcher struct {
 	Log       Logger
 	K8sClient Client // K8s client to use. If omitted, we will use StandardK8sClient struct
+	// HTTP client to use for the secret stores that we access through their
+	// REST API (Vault and Azure Key Vault). If omitted, we use a client that
+	// times out after httpRequestTimeout.
+	HTTPClient *http.Client
 }
 
+// httpRequestTimeout is how long a request to a secret store accessed through
+// its REST API can take, including reading the response body. It keeps a
+// reconcile from hanging on a store that doesn't respond.
+const httpRequestTimeout = 30 * time.Second
+
 // Client is the interface we must implement for any k8s calls. This allows the
 // caller to use their own client, which may provide some benefits beyond the
 // standard k8s client (e.g. caching in use by the operator-sdk)
@@ -77,6 +89,10 @@ func (m *MultiSourceSecretFetcher) Fetch(ctx context.Context, secretName types.N
 		return m.readFromGSM(ctx, secretName.Name)
 	case IsAWSSecretsManagerSecret(secretName.Name):
 		return m.readFromAWS(secretName.Name)
+	case IsVaultSecret(secretName.Name):
+		return m.readFromVault(ctx, secretName.Name)
+	case IsAzureKeyVaultSecret(secretName.Name):
+		return m.readFromAzureKeyVault(ctx, secretName.Name)
 	default:
 		return m.readFromK8s(ctx, secretName)
 	}
@@ -138,7 +154,7 @@ func (m *MultiSourceSecretFetcher) readFromGSM(ctx context.Context, secName stri
 // should be of the format awssm://<secret-arn> or awssm://<secret-arn>@<version-id>.
 func (m *MultiSourceSecretFetcher) readFromAWS(secretName string) (map[string][]byte, error) {
 	secretARNWithVersionID := RemovePathReference(secretName)
-	secretARN, versionID := getAWSSecretVersionID(secretARNWithVersionID)
+	secretARN, versionID := getSecretVersionID(secretARNWithVersionID)
 	if !arn.IsARN(secretARN) {
 		return nil, fmt.Errorf("the secret name '%s' to fetch from AWS is not an ARN", secretARN)
 	}
@@ -193,6 +209,30 @@ func (m *MultiSourceSecretFetcher) readFromAWS(secretName string) (map[string][]
 	return contents, nil
 }
 
+// getHTTPClient returns the http client to use for the secret stores accessed
+// through their REST API
+func (m *MultiSourceSecretFetcher) getHTTPClient() *http.Client {
+	if m.HTTPClient != nil {
+		return m.HTTPClient
+	}
+	return &http.Client{Timeout: httpRequestTimeout}
+}
+
+// doHTTPRequest sends the request and returns the response body along with
+// the status code
+func doHTTPRequest(clnt *http.Client, req *http.Request) (body []byte, statusCode int, err error) {
+	resp, err := clnt.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("failed to read the response body: %w", err)
+	}
+	return body, resp.StatusCode, nil
+}
+
 func GetAWSRegion(secretName string) (string, error) {
 	secretARN := RemovePathReference(secretName)
 	arnComp, err := arn.Parse(secretARN)
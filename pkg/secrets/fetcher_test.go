@@ -16,8 +16,19 @@
 package secrets
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -75,8 +86,236 @@ var _ = Describe("secrets/fetcher", func() {
 		ok := errors.As(err, &nfe)
 		Ω(ok).Should(BeTrue())
 	})
+
+	It("should time out the requests to the secret stores read over http", func() {
+		fetcher := MultiSourceSecretFetcher{Log: logger}
+		Ω(fetcher.getHTTPClient().Timeout).Should(Equal(httpRequestTimeout))
+	})
+})
+
+var _ = Describe("secrets/fetcher/vault", func() {
+	ctx := context.Background()
+
+	It("should read secret from Vault with a token", func() {
+		vault := makeFakeVaultServer("root-token")
+		defer vault.Close()
+		GinkgoT().Setenv(VaultAddrEnv, vault.URL)
+		GinkgoT().Setenv(VaultTokenEnv, "root-token")
+
+		fetcher := MultiSourceSecretFetcher{Log: logger}
+		data, err := fetcher.Fetch(ctx, types.NamespacedName{Namespace: "default", Name: "vault://secret/vertica/superuser"})
+		Ω(err).Should(Succeed())
+		Ω(string(data["password"])).Should(Equal("supersecret"))
+		Ω(string(data["port"])).Should(Equal("5433"))
+
+		data, err = fetcher.Fetch(ctx, types.NamespacedName{Namespace: "default", Name: "vault://secret/vertica/superuser@1"})
+		Ω(err).Should(Succeed())
+		Ω(string(data["password"])).Should(Equal("oldsecret"))
+	})
+
+	It("should return secret not found if the Vault secret doesn't exist", func() {
+		vault := makeFakeVaultServer("root-token")
+		defer vault.Close()
+		GinkgoT().Setenv(VaultAddrEnv, vault.URL)
+		GinkgoT().Setenv(VaultTokenEnv, "root-token")
+
+		fetcher := MultiSourceSecretFetcher{Log: logger}
+		_, err := fetcher.Fetch(ctx, types.NamespacedName{Namespace: "default", Name: "vault://secret/not-exist"})
+		Ω(err).ShouldNot(Succeed())
+		nfe := &NotFoundError{}
+		Ω(errors.As(err, &nfe)).Should(BeTrue())
+	})
+
+	It("should log in to Vault with the Kubernetes auth method", func() {
+		vault := makeFakeVaultServer("k8s-token")
+		defer vault.Close()
+		resetVaultTokenCache()
+		GinkgoT().Setenv(VaultAddrEnv, vault.URL)
+		GinkgoT().Setenv(VaultTokenEnv, "")
+		GinkgoT().Setenv(VaultRoleEnv, "vertica")
+		origPath := serviceAccountTokenPath
+		defer func() { serviceAccountTokenPath = origPath }()
+		serviceAccountTokenPath = filepath.Join(GinkgoT().TempDir(), "token")
+		Ω(os.WriteFile(serviceAccountTokenPath, []byte("sa-jwt"), 0600)).Should(Succeed())
+
+		fetcher := MultiSourceSecretFetcher{Log: logger}
+		data, err := fetcher.Fetch(ctx, types.NamespacedName{Namespace: "default", Name: "vault://secret/vertica/superuser"})
+		Ω(err).Should(Succeed())
+		Ω(string(data["password"])).Should(Equal("supersecret"))
+
+		GinkgoT().Setenv(VaultRoleEnv, "")
+		_, err = fetcher.Fetch(ctx, types.NamespacedName{Namespace: "default", Name: "vault://secret/vertica/superuser"})
+		Ω(err).ShouldNot(Succeed())
+	})
 })
 
+// These run against a real Vault dev server, so they are skipped if the
+// vault binary isn't installed.
+var _ = Describe("secrets/fetcher/vault-dev-server", func() {
+	ctx := context.Background()
+
+	It("should read secret from a Vault dev server", func() {
+		addr := startVaultDevServer("root-token")
+		GinkgoT().Setenv(VaultAddrEnv, addr)
+		GinkgoT().Setenv(VaultTokenEnv, "root-token")
+		writeVaultSecret(addr, "root-token", "vertica/superuser", map[string]any{"password": "oldsecret"})
+		writeVaultSecret(addr, "root-token", "vertica/superuser", map[string]any{"password": "supersecret", "port": 5433})
+
+		fetcher := MultiSourceSecretFetcher{Log: logger}
+		data, err := fetcher.Fetch(ctx, types.NamespacedName{Namespace: "default", Name: "vault://secret/vertica/superuser"})
+		Ω(err).Should(Succeed())
+		Ω(string(data["password"])).Should(Equal("supersecret"))
+		Ω(string(data["port"])).Should(Equal("5433"))
+
+		data, err = fetcher.Fetch(ctx, types.NamespacedName{Namespace: "default", Name: "vault://secret/vertica/superuser@1"})
+		Ω(err).Should(Succeed())
+		Ω(string(data["password"])).Should(Equal("oldsecret"))
+
+		_, err = fetcher.Fetch(ctx, types.NamespacedName{Namespace: "default", Name: "vault://secret/not-exist"})
+		Ω(err).ShouldNot(Succeed())
+		nfe := &NotFoundError{}
+		Ω(errors.As(err, &nfe)).Should(BeTrue())
+
+		GinkgoT().Setenv(VaultTokenEnv, "wrong-token")
+		_, err = fetcher.Fetch(ctx, types.NamespacedName{Namespace: "default", Name: "vault://secret/vertica/superuser"})
+		Ω(err).ShouldNot(Succeed())
+		Ω(errors.As(err, &nfe)).Should(BeFalse())
+	})
+})
+
+var _ = Describe("secrets/fetcher/azkv", func() {
+	ctx := context.Background()
+
+	It("should read secret from Azure Key Vault", func() {
+		azure := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/my-tenant/oauth2/v2.0/token":
+				Ω(r.ParseForm()).Should(Succeed())
+				if r.PostForm.Get("client_assertion") != "federated-jwt" || r.PostForm.Get("client_id") != "my-client" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				writeJSON(w, map[string]any{"access_token": "azure-token", "expires_in": 3600})
+			case r.Header.Get("Authorization") != "Bearer azure-token":
+				w.WriteHeader(http.StatusUnauthorized)
+			case r.Method == http.MethodGet && r.URL.Path == "/secrets/superuser":
+				// The value is a JSON object with base64 encoded values
+				writeJSON(w, map[string]any{"value": `{"password":"c3VwZXJzZWNyZXQ="}`})
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				writeJSON(w, map[string]any{"error": map[string]string{"code": "SecretNotFound"}})
+			}
+		}))
+		defer azure.Close()
+		resetAzureTokenCache()
+		tokenFile := filepath.Join(GinkgoT().TempDir(), "azure-identity-token")
+		Ω(os.WriteFile(tokenFile, []byte("federated-jwt"), 0600)).Should(Succeed())
+		GinkgoT().Setenv(AzureClientIDEnv, "my-client")
+		GinkgoT().Setenv(AzureTenantIDEnv, "my-tenant")
+		GinkgoT().Setenv(AzureFederatedTokenFileEnv, tokenFile)
+		GinkgoT().Setenv(AzureAuthorityHostEnv, azure.URL)
+
+		fetcher := MultiSourceSecretFetcher{Log: logger, HTTPClient: azure.Client()}
+		host := strings.TrimPrefix(azure.URL, "https://")
+		data, err := fetcher.Fetch(ctx, types.NamespacedName{Namespace: "default", Name: "azkv://" + host + "/superuser"})
+		Ω(err).Should(Succeed())
+		Ω(string(data["password"])).Should(Equal("supersecret"))
+
+		_, err = fetcher.Fetch(ctx, types.NamespacedName{Namespace: "default", Name: "azkv://" + host + "/not-exist"})
+		Ω(err).ShouldNot(Succeed())
+		nfe := &NotFoundError{}
+		Ω(errors.As(err, &nfe)).Should(BeTrue())
+	})
+})
+
+// makeVaultDevServer returns a server that answers like a Vault dev server
+// with the KV v2 secrets engine mounted at secret/ and the Kubernetes auth
+// method enabled. The given token is the one accepted to read secrets.
+func makeFakeVaultServer(token string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/v1/auth/kubernetes/login" {
+			login := map[string]string{}
+			Ω(json.NewDecoder(r.Body).Decode(&login)).Should(Succeed())
+			if login["role"] != "vertica" || login["jwt"] != "sa-jwt" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			writeJSON(w, map[string]any{"auth": map[string]any{"client_token": token, "lease_duration": 3600}})
+			return
+		}
+		if r.Header.Get(vaultTokenHeader) != token {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.Method != http.MethodGet || r.URL.Path != "/v1/secret/data/vertica/superuser" {
+			w.WriteHeader(http.StatusNotFound)
+			writeJSON(w, map[string]any{"errors": []string{}})
+			return
+		}
+		data := map[string]any{"password": "supersecret", "port": 5433}
+		if r.URL.Query().Get("version") == "1" {
+			data = map[string]any{"password": "oldsecret"}
+		}
+		writeJSON(w, map[string]any{"data": map[string]any{
+			"data":     data,
+			"metadata": map[string]any{"version": 2, "deletion_time": "", "destroyed": false},
+		}})
+	}))
+}
+
+// startVaultDevServer starts a Vault dev server, which has the KV v2 secrets
+// engine mounted at secret/, and returns its address. The server is stopped at
+// the end of the test. The test is skipped if vault isn't installed.
+func startVaultDevServer(rootToken string) string {
+	vaultBin, err := exec.LookPath("vault")
+	if err != nil {
+		Skip("the vault binary is needed to run a Vault dev server")
+	}
+	lsn, err := net.Listen("tcp", "127.0.0.1:0")
+	Ω(err).Should(Succeed())
+	listenAddr := lsn.Addr().String()
+	Ω(lsn.Close()).Should(Succeed())
+
+	cmd := exec.Command(vaultBin, "server", "-dev", "-dev-root-token-id="+rootToken,
+		"-dev-listen-address="+listenAddr)
+	cmd.Env = append(os.Environ(), "VAULT_DEV_ROOT_TOKEN_ID="+rootToken)
+	Ω(cmd.Start()).Should(Succeed())
+	DeferCleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+
+	addr := "http://" + listenAddr
+	Eventually(func() (int, error) {
+		resp, err := http.Get(addr + "/v1/sys/health")
+		if err != nil {
+			return 0, err
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode, nil
+	}).WithTimeout(30 * time.Second).WithPolling(200 * time.Millisecond).Should(Equal(http.StatusOK))
+	return addr
+}
+
+// writeVaultSecret writes a new version of a secret in the KV v2 secrets
+// engine mounted at secret/
+func writeVaultSecret(addr, token, secretPath string, data map[string]any) {
+	body, err := json.Marshal(map[string]any{"data": data})
+	Ω(err).Should(Succeed())
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/v1/secret/data/%s", addr, secretPath), bytes.NewReader(body))
+	Ω(err).Should(Succeed())
+	req.Header.Set(vaultTokenHeader, token)
+	resp, err := http.DefaultClient.Do(req)
+	Ω(err).Should(Succeed())
+	defer resp.Body.Close()
+	Ω(resp.StatusCode).Should(Equal(http.StatusOK))
+}
+
+func writeJSON(w http.ResponseWriter, obj any) {
+	w.Header().Set("Content-Type", "application/json")
+	Ω(json.NewEncoder(w).Encode(obj)).Should(Succeed())
+}
+
 func makeStandardK8sClient() *StandardK8sClient {
 	return &StandardK8sClient{
 		Config: config,
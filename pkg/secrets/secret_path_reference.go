@@ -16,6 +16,9 @@
 package secrets
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"strings"
 )
 
@@ -25,16 +28,26 @@ const (
 	secretSourceK8s sourceType = iota
 	secretSourceGSM
 	secretSourceAWSSM
+	secretSourceVault
+	secretSourceAzureKV
 	defaultSecretSource = secretSourceK8s
 
 	gsmPrefix   = "gsm"   // Google Secret Manager
 	awssmPrefix = "awssm" // AWS Secrets Manager
+	vaultPrefix = "vault" // HashiCorp Vault
+	azkvPrefix  = "azkv"  // Azure Key Vault
+
+	// Number of hex characters of the hash used in the name of a mirrored
+	// secret
+	mirroredSecretHashLen = 16
 )
 
 // Maps a given prefix to the SourceType
 var prefixToPathReference = map[string]sourceType{
 	gsmPrefix:   secretSourceGSM,
 	awssmPrefix: secretSourceAWSSM,
+	vaultPrefix: secretSourceVault,
+	azkvPrefix:  secretSourceAzureKV,
 }
 
 // getSecretSourceType returns the source of a secret given its name
@@ -50,9 +63,9 @@ func getSecretSourceType(secretName string) (stype sourceType, nameWithoutPathRe
 	return source, comps[1]
 }
 
-// getAWSSecretVersionID given a secret name, parse it and return the name
-// and the version id
-func getAWSSecretVersionID(secretName string) (nameWithoutVersionID, versionID string) {
+// getSecretVersionID given a secret name, parse it and return the name
+// and the version id that follows the '@' separator
+func getSecretVersionID(secretName string) (nameWithoutVersionID, versionID string) {
 	comps := strings.Split(secretName, "@")
 	if len(comps) <= 1 {
 		return secretName, ""
@@ -75,6 +88,20 @@ func IsAWSSecretsManagerSecret(secretName string) bool {
 	return stype == secretSourceAWSSM
 }
 
+// IsVaultSecret returns true if the given secret name is stored in the KV v2
+// secrets engine of HashiCorp Vault.
+func IsVaultSecret(secretName string) bool {
+	stype, _ := getSecretSourceType(secretName)
+	return stype == secretSourceVault
+}
+
+// IsAzureKeyVaultSecret returns true if the given secret name is stored in
+// Azure Key Vault.
+func IsAzureKeyVaultSecret(secretName string) bool {
+	stype, _ := getSecretSourceType(secretName)
+	return stype == secretSourceAzureKV
+}
+
 // IsK8sSecret returns true if the given secret should be fetched directly from
 // Kubernetes secret manager.
 func IsK8sSecret(secretName string) bool {
@@ -82,6 +109,34 @@ func IsK8sSecret(secretName string) bool {
 	return stype == secretSourceK8s
 }
 
+// IsMirroredSecret returns true if the given secret is stored in a secret store
+// that Vertica and its node management agent cannot read from, such as
+// HashiCorp Vault and Azure Key Vault. The operator mirrors the contents of
+// such a secret in a Kubernetes secret for them to use.
+func IsMirroredSecret(secretName string) bool {
+	stype, _ := getSecretSourceType(secretName)
+	return stype == secretSourceVault || stype == secretSourceAzureKV
+}
+
+// GetMirroredSecretName returns the name of the Kubernetes secret that
+// mirrors the given secret. The name is derived from the secret path
+// reference, so it is stable across reconciles.
+func GetMirroredSecretName(secretName string) string {
+	prefix, _, _ := strings.Cut(secretName, "://")
+	sum := sha256.Sum256([]byte(secretName))
+	return fmt.Sprintf("%s-mirror-%s", prefix, hex.EncodeToString(sum[:])[:mirroredSecretHashLen])
+}
+
+// ResolveMirroredSecretName returns the name of the Kubernetes secret that
+// mirrors the given secret if it is mirrored. Otherwise, the name is returned
+// as is.
+func ResolveMirroredSecretName(secretName string) string {
+	if IsMirroredSecret(secretName) {
+		return GetMirroredSecretName(secretName)
+	}
+	return secretName
+}
+
 // RemovePathReference returns the name of the secret without the path reference
 func RemovePathReference(secretName string) string {
 	_, nameWithoutPathReference := getSecretSourceType(secretName)
@@ -90,6 +145,33 @@ func RemovePathReference(secretName string) string {
 
 func GetAWSSecretARN(secretName string) (secretARN, versionID string) {
 	secretARNWithVersionID := RemovePathReference(secretName)
-	secretARN, versionID = getAWSSecretVersionID(secretARNWithVersionID)
+	secretARN, versionID = getSecretVersionID(secretARNWithVersionID)
 	return
 }
+
+// GetVaultSecretPath parses a secret name of the format
+// vault://<mount>/<path> or vault://<mount>/<path>@<version> and returns the
+// mount of the KV v2 secrets engine, the path of the secret in it and the
+// optional version.
+func GetVaultSecretPath(secretName string) (mount, secretPath, version string, err error) {
+	pathWithVersion, version := getSecretVersionID(RemovePathReference(secretName))
+	mount, secretPath, found := strings.Cut(strings.Trim(pathWithVersion, "/"), "/")
+	if !found || mount == "" || secretPath == "" {
+		return "", "", "", fmt.Errorf("the secret name '%s' must be of the format vault://<mount>/<path>", secretName)
+	}
+	return mount, secretPath, version, nil
+}
+
+// GetAzureKeyVaultSecret parses a secret name of the format
+// azkv://<vault>/<name> or azkv://<vault>/<name>@<version> and returns the key
+// vault, the name of the secret in it and the optional version. The key vault
+// is either a vault name or, for clouds other than the Azure public cloud, the
+// fully qualified host name of the vault.
+func GetAzureKeyVaultSecret(secretName string) (keyVault, name, version string, err error) {
+	nameWithVersion, version := getSecretVersionID(RemovePathReference(secretName))
+	keyVault, name, found := strings.Cut(nameWithVersion, "/")
+	if !found || keyVault == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", "", fmt.Errorf("the secret name '%s' must be of the format azkv://<vault>/<name>", secretName)
+	}
+	return keyVault, name, version, nil
+}
@@ -31,6 +31,53 @@ var _ = Describe("secrets/secret_path_reference", func() {
 		Ω(RemovePathReference("awssm://aws-secrets-manager-secret")).Should(Equal("aws-secrets-manager-secret"))
 	})
 
+	It("should parse secret name for Vault", func() {
+		Ω(IsVaultSecret("vault://secret/vertica/superuser")).Should(BeTrue())
+		mount, secretPath, version, err := GetVaultSecretPath("vault://secret/vertica/superuser")
+		Ω(err).Should(Succeed())
+		Ω(mount).Should(Equal("secret"))
+		Ω(secretPath).Should(Equal("vertica/superuser"))
+		Ω(version).Should(Equal(""))
+		mount, secretPath, version, err = GetVaultSecretPath("vault://kv/superuser@3")
+		Ω(err).Should(Succeed())
+		Ω(mount).Should(Equal("kv"))
+		Ω(secretPath).Should(Equal("superuser"))
+		Ω(version).Should(Equal("3"))
+		_, _, _, err = GetVaultSecretPath("vault://superuser")
+		Ω(err).ShouldNot(Succeed())
+	})
+
+	It("should parse secret name for Azure Key Vault", func() {
+		Ω(IsAzureKeyVaultSecret("azkv://myvault/superuser")).Should(BeTrue())
+		keyVault, name, version, err := GetAzureKeyVaultSecret("azkv://myvault/superuser@abc123")
+		Ω(err).Should(Succeed())
+		Ω(keyVault).Should(Equal("myvault"))
+		Ω(name).Should(Equal("superuser"))
+		Ω(version).Should(Equal("abc123"))
+		keyVault, _, version, err = GetAzureKeyVaultSecret("azkv://myvault.vault.azure.cn/superuser")
+		Ω(err).Should(Succeed())
+		Ω(keyVault).Should(Equal("myvault.vault.azure.cn"))
+		Ω(version).Should(Equal(""))
+		_, _, _, err = GetAzureKeyVaultSecret("azkv://myvault")
+		Ω(err).ShouldNot(Succeed())
+		_, _, _, err = GetAzureKeyVaultSecret("azkv://myvault/a/b")
+		Ω(err).ShouldNot(Succeed())
+	})
+
+	It("should give a mirror name to Vault and Azure Key Vault secrets", func() {
+		Ω(IsMirroredSecret("vault://secret/superuser")).Should(BeTrue())
+		Ω(IsMirroredSecret("azkv://myvault/superuser")).Should(BeTrue())
+		Ω(IsMirroredSecret("gsm://projects/blah/mysecret/versions/1")).Should(BeFalse())
+		Ω(IsMirroredSecret("secret")).Should(BeFalse())
+		mirror := GetMirroredSecretName("vault://secret/superuser")
+		Ω(mirror).Should(MatchRegexp("^vault-mirror-[0-9a-f]{16}$"))
+		Ω(GetMirroredSecretName("vault://secret/superuser")).Should(Equal(mirror))
+		Ω(GetMirroredSecretName("vault://secret/superuser@2")).ShouldNot(Equal(mirror))
+		Ω(IsK8sSecret(mirror)).Should(BeTrue())
+		Ω(ResolveMirroredSecretName("vault://secret/superuser")).Should(Equal(mirror))
+		Ω(ResolveMirroredSecretName("secret")).Should(Equal("secret"))
+	})
+
 	It("should parse secret name for k8s as a fallback", func() {
 		Ω(IsK8sSecret("secret")).Should(BeTrue())
 		Ω(RemovePathReference("secret")).Should(Equal("secret"))
@@ -39,5 +86,7 @@ var _ = Describe("secrets/secret_path_reference", func() {
 		Ω(RemovePathReference(sn)).Should(Equal("abc://default-to-k8s-if-unknown-path-reference"))
 		Ω(IsGSMSecret(sn)).Should(BeFalse())
 		Ω(IsAWSSecretsManagerSecret(sn)).Should(BeFalse())
+		Ω(IsVaultSecret(sn)).Should(BeFalse())
+		Ω(IsAzureKeyVaultSecret(sn)).Should(BeFalse())
 	})
 })
@@ -0,0 +1,283 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The environment variables that tell us how to reach and authenticate to
+// HashiCorp Vault. They follow the names used by the vault CLI where one
+// exists.
+const (
+	// The address of the Vault server (e.g. https://vault.vault:8200)
+	VaultAddrEnv = "VAULT_ADDR"
+	// An optional Vault token. When set, it is used as is rather than logging
+	// in with the Kubernetes auth method. This is meant for the Vault dev
+	// server and testing.
+	VaultTokenEnv = "VAULT_TOKEN"
+	// An optional Vault Enterprise namespace
+	VaultNamespaceEnv = "VAULT_NAMESPACE"
+	// An optional path to a PEM encoded CA cert used to verify the Vault server
+	VaultCACertEnv = "VAULT_CACERT"
+	// The role to log in with the Kubernetes auth method
+	VaultRoleEnv = "VAULT_ROLE"
+	// The mount path of the Kubernetes auth method. Defaults to kubernetes.
+	VaultAuthPathEnv = "VAULT_AUTH_PATH"
+
+	defaultVaultAuthPath = "kubernetes"
+	vaultTokenHeader     = "X-Vault-Token"
+	vaultNamespaceHeader = "X-Vault-Namespace"
+	// Fraction of the lease duration of a Vault token after which we log in
+	// again rather than reuse it.
+	vaultTokenRenewRatio = 0.8
+)
+
+// The service account token that is presented to Vault for the Kubernetes
+// auth method
+var serviceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token" //nolint:gosec
+
+// vaultLoginResponse is the part of the response of the Kubernetes auth
+// method login that we care about
+type vaultLoginResponse struct {
+	Auth struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int    `json:"lease_duration"`
+	} `json:"auth"`
+}
+
+// vaultKVv2Response is the part of a KV v2 read response that we care about
+type vaultKVv2Response struct {
+	Data struct {
+		Data     map[string]any `json:"data"`
+		Metadata struct {
+			DeletionTime string `json:"deletion_time"`
+			Destroyed    bool   `json:"destroyed"`
+		} `json:"metadata"`
+	} `json:"data"`
+}
+
+// vaultTokenCache keeps the token from the last Kubernetes auth login so that
+// we don't log in for every secret read.
+var vaultTokenCache = struct {
+	sync.Mutex
+	key     string
+	token   string
+	expires time.Time
+}{}
+
+// readFromVault will fetch a secret from the KV v2 secrets engine of
+// HashiCorp Vault. The secretName should be of the format
+// vault://<mount>/<path> or vault://<mount>/<path>@<version>.
+func (m *MultiSourceSecretFetcher) readFromVault(ctx context.Context, secretName string) (map[string][]byte, error) {
+	mount, secretPath, version, err := GetVaultSecretPath(secretName)
+	if err != nil {
+		return nil, err
+	}
+	addr := strings.TrimSuffix(os.Getenv(VaultAddrEnv), "/")
+	if addr == "" {
+		return nil, fmt.Errorf("the %s environment variable must be set to read the secret '%s' from Vault",
+			VaultAddrEnv, secretName)
+	}
+	clnt, err := m.getVaultHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	token, err := getVaultToken(ctx, clnt, addr)
+	if err != nil {
+		return nil, err
+	}
+	m.Log.Info("Reading secret from Vault", "addr", addr, "mount", mount, "path", secretPath, "version", version)
+
+	reqURL := fmt.Sprintf("%s/v1/%s/data/%s", addr, mount, secretPath)
+	if version != "" {
+		reqURL += "?" + url.Values{"version": []string{version}}.Encode()
+	}
+	req, err := newVaultRequest(ctx, http.MethodGet, reqURL, token, nil)
+	if err != nil {
+		return nil, err
+	}
+	body, statusCode, err := doHTTPRequest(clnt, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch secret from Vault: %w", err)
+	}
+	if statusCode == http.StatusNotFound {
+		return nil, errors.Join(fmt.Errorf("vault returned status %d", statusCode), &NotFoundError{
+			msg: fmt.Sprintf("Could not find the secret '%s' in Vault", secretName),
+		})
+	}
+	if statusCode == http.StatusForbidden {
+		// The cached token may have been revoked. Log in again next time.
+		resetVaultTokenCache()
+	}
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch secret '%s' from Vault: status %d: %s", secretName, statusCode, body)
+	}
+
+	resp := vaultKVv2Response{}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the contents of the Vault secret '%s': %w", secretName, err)
+	}
+	if resp.Data.Data == nil || resp.Data.Metadata.Destroyed || resp.Data.Metadata.DeletionTime != "" {
+		return nil, &NotFoundError{
+			msg: fmt.Sprintf("The secret '%s' in Vault has been deleted", secretName),
+		}
+	}
+	return convertVaultData(resp.Data.Data)
+}
+
+// convertVaultData converts the key/value pairs of a KV v2 secret to the
+// format returned by the fetcher. String values are taken as is. Any other
+// value is kept in its JSON form.
+func convertVaultData(data map[string]any) (map[string][]byte, error) {
+	contents := make(map[string][]byte, len(data))
+	for k, v := range data {
+		if s, ok := v.(string); ok {
+			contents[k] = []byte(s)
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert the value of key '%s' in the Vault secret: %w", k, err)
+		}
+		contents[k] = b
+	}
+	return contents, nil
+}
+
+// getVaultToken returns the token to use for Vault requests. The token from
+// the environment is used when it is set. Otherwise, we log in with the
+// Kubernetes auth method using the service account token of the pod.
+func getVaultToken(ctx context.Context, clnt *http.Client, addr string) (string, error) {
+	if token := os.Getenv(VaultTokenEnv); token != "" {
+		return token, nil
+	}
+	role := os.Getenv(VaultRoleEnv)
+	if role == "" {
+		return "", fmt.Errorf("either %s or %s must be set to authenticate to Vault", VaultTokenEnv, VaultRoleEnv)
+	}
+	authPath := strings.Trim(os.Getenv(VaultAuthPathEnv), "/")
+	if authPath == "" {
+		authPath = defaultVaultAuthPath
+	}
+
+	cacheKey := strings.Join([]string{addr, os.Getenv(VaultNamespaceEnv), authPath, role}, "|")
+	vaultTokenCache.Lock()
+	defer vaultTokenCache.Unlock()
+	if vaultTokenCache.key == cacheKey && time.Now().Before(vaultTokenCache.expires) {
+		return vaultTokenCache.token, nil
+	}
+
+	jwt, err := os.ReadFile(serviceAccountTokenPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the service account token for the Vault login: %w", err)
+	}
+	payload, err := json.Marshal(map[string]string{
+		"role": role,
+		"jwt":  strings.TrimSpace(string(jwt)),
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := newVaultRequest(ctx, http.MethodPost, fmt.Sprintf("%s/v1/auth/%s/login", addr, authPath), "",
+		bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	body, statusCode, err := doHTTPRequest(clnt, req)
+	if err != nil {
+		return "", fmt.Errorf("failed to log in to Vault: %w", err)
+	}
+	if statusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to log in to Vault with role '%s': status %d: %s", role, statusCode, body)
+	}
+	resp := vaultLoginResponse{}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("failed to unmarshal the Vault login response: %w", err)
+	}
+	if resp.Auth.ClientToken == "" {
+		return "", fmt.Errorf("the Vault login with role '%s' did not return a token", role)
+	}
+
+	vaultTokenCache.key = cacheKey
+	vaultTokenCache.token = resp.Auth.ClientToken
+	vaultTokenCache.expires = time.Now().Add(
+		time.Duration(float64(resp.Auth.LeaseDuration)*vaultTokenRenewRatio) * time.Second)
+	return resp.Auth.ClientToken, nil
+}
+
+// resetVaultTokenCache drops the cached Vault token
+func resetVaultTokenCache() {
+	vaultTokenCache.Lock()
+	defer vaultTokenCache.Unlock()
+	vaultTokenCache.key = ""
+	vaultTokenCache.token = ""
+	vaultTokenCache.expires = time.Time{}
+}
+
+// newVaultRequest builds a request to the Vault API
+func newVaultRequest(ctx context.Context, method, reqURL, token string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build the Vault request: %w", err)
+	}
+	if token != "" {
+		req.Header.Set(vaultTokenHeader, token)
+	}
+	if ns := os.Getenv(VaultNamespaceEnv); ns != "" {
+		req.Header.Set(vaultNamespaceHeader, ns)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// getVaultHTTPClient returns the http client to use for Vault. If a CA cert is
+// given in the environment, it is used to verify the Vault server.
+func (m *MultiSourceSecretFetcher) getVaultHTTPClient() (*http.Client, error) {
+	caCertFile := os.Getenv(VaultCACertEnv)
+	if m.HTTPClient != nil || caCertFile == "" {
+		return m.getHTTPClient(), nil
+	}
+	caCert, err := os.ReadFile(caCertFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the Vault CA cert: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("no valid certs found in the Vault CA cert file %s", caCertFile)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		RootCAs:    pool,
+		MinVersion: tls.VersionTLS12,
+	}
+	return &http.Client{Transport: transport, Timeout: httpRequestTimeout}, nil
+}
@@ -36,6 +36,19 @@ type certificate struct {
 	tlsCrt []byte
 }
 
+// NewCertificateFromPEM returns a Certificate for an existing PEM encoded
+// certificate and PKCS#1 private key, such as a CA stored in a secret.
+func NewCertificateFromPEM(tlsCrt, tlsKey []byte) (Certificate, error) {
+	c := &certificate{tlsKey: tlsKey, tlsCrt: tlsCrt}
+	if _, err := c.Buildx509(); err != nil {
+		return nil, err
+	}
+	if _, err := c.BuildPrivateKey(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
 func (c *certificate) TLSKey() []byte { return c.tlsKey }
 func (c *certificate) TLSCrt() []byte { return c.tlsCrt }
 
@@ -32,6 +32,19 @@ var _ = Describe("security", func() {
 		Expect(err).Should(Succeed())
 		verifyCerts(NewCertificate(caCert, "dbadmin", []string{"host1", "host2"}))
 	})
+
+	It("generate a cert signed by a CA loaded from PEM", func() {
+		caCert, err := NewSelfSignedCACertificate()
+		Expect(err).Should(Succeed())
+		loadedCA, err := NewCertificateFromPEM(caCert.TLSCrt(), caCert.TLSKey())
+		Expect(err).Should(Succeed())
+		cert, err := NewCertificateWithIPs(loadedCA, "dbadmin", GetDNSNames("ns"), nil)
+		verifyCerts(cert, err)
+		Expect(ValidateCertificateCommonName(cert.TLSCrt(), "dbadmin")).Should(Succeed())
+
+		_, err = NewCertificateFromPEM(caCert.TLSCrt(), []byte("not a key"))
+		Expect(err).ShouldNot(Succeed())
+	})
 })
 
 func verifyCerts(cert Certificate, err error) {
@@ -0,0 +1,78 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package security
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+// The characters that a generated password is made of. Quotes, backslashes
+// and spaces are left out so that the password can be used as is in a SQL
+// string literal or on a command line.
+const (
+	passwordLowerChars   = "abcdefghijklmnopqrstuvwxyz"
+	passwordUpperChars   = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	passwordDigitChars   = "0123456789"
+	passwordSpecialChars = "!#%*+-.:=?@^_~"
+
+	// The minimum length of a generated password. It leaves room for one
+	// character of each class.
+	minPasswordLength = 4
+)
+
+// NewPassword generates a random password of the given length. The password
+// has at least one lowercase letter, one uppercase letter, one digit and one
+// special character so that it satisfies the usual password complexity
+// rules.
+func NewPassword(length int) (string, error) {
+	if length < minPasswordLength {
+		return "", fmt.Errorf("password length must be at least %d", minPasswordLength)
+	}
+	classes := []string{passwordLowerChars, passwordUpperChars, passwordDigitChars, passwordSpecialChars}
+	allChars := passwordLowerChars + passwordUpperChars + passwordDigitChars + passwordSpecialChars
+	passwd := make([]byte, length)
+	for i := range passwd {
+		chars := allChars
+		if i < len(classes) {
+			chars = classes[i]
+		}
+		c, err := randomChar(chars)
+		if err != nil {
+			return "", err
+		}
+		passwd[i] = c
+	}
+	// Shuffle so that the guaranteed characters are not always first
+	for i := len(passwd) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate a password: %w", err)
+		}
+		passwd[i], passwd[j.Int64()] = passwd[j.Int64()], passwd[i]
+	}
+	return string(passwd), nil
+}
+
+// randomChar picks a random character from the given string
+func randomChar(chars string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate a password: %w", err)
+	}
+	return chars[n.Int64()], nil
+}
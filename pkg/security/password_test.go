@@ -0,0 +1,45 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package security
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("password", func() {
+	It("should generate a password with each class of characters", func() {
+		passwd, err := NewPassword(32)
+		Expect(err).Should(Succeed())
+		Expect(passwd).Should(HaveLen(32))
+		Expect(strings.ContainsAny(passwd, passwordLowerChars)).Should(BeTrue())
+		Expect(strings.ContainsAny(passwd, passwordUpperChars)).Should(BeTrue())
+		Expect(strings.ContainsAny(passwd, passwordDigitChars)).Should(BeTrue())
+		Expect(strings.ContainsAny(passwd, passwordSpecialChars)).Should(BeTrue())
+		Expect(strings.ContainsAny(passwd, `'"\ `)).Should(BeFalse())
+
+		other, err := NewPassword(32)
+		Expect(err).Should(Succeed())
+		Expect(other).ShouldNot(Equal(passwd))
+	})
+
+	It("should fail if the password is too short", func() {
+		_, err := NewPassword(3)
+		Expect(err).ShouldNot(Succeed())
+	})
+})
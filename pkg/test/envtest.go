@@ -0,0 +1,79 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/api/v1beta1"
+	vmeta "github.com/vertica/vertica-kubernetes/pkg/meta"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+// EnvTest is a test environment for a controller test suite. It runs an API
+// server with the CRDs of the operator installed.
+type EnvTest struct {
+	Env    *envtest.Environment
+	Cfg    *rest.Config
+	Client client.Client
+	EVRec  record.EventRecorder
+}
+
+// StartEnvTest bootstraps the test environment. crdDir is the path of
+// config/crd/bases relative to the test package. It is meant to be called
+// from a BeforeSuite.
+func StartEnvTest(crdDir string) *EnvTest {
+	By("bootstrapping test environment")
+	e := &EnvTest{
+		Env: &envtest.Environment{
+			CRDDirectoryPaths:     []string{crdDir},
+			ErrorIfCRDPathMissing: true,
+		},
+	}
+
+	var err error
+	e.Cfg, err = e.Env.Start()
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	ExpectWithOffset(1, e.Cfg).NotTo(BeNil())
+
+	ExpectWithOffset(1, v1beta1.AddToScheme(scheme.Scheme)).To(Succeed())
+	ExpectWithOffset(1, vapi.AddToScheme(scheme.Scheme)).To(Succeed())
+
+	e.Client, err = client.New(e.Cfg, client.Options{Scheme: scheme.Scheme})
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+
+	mgr, err := ctrl.NewManager(e.Cfg, ctrl.Options{
+		Scheme:  scheme.Scheme,
+		Metrics: metricsserver.Options{BindAddress: "0"}, // Disable metrics for the test
+	})
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	e.EVRec = mgr.GetEventRecorderFor(vmeta.OperatorName)
+	return e
+}
+
+// Stop tears down the test environment. It is meant to be called from an
+// AfterSuite.
+func (e *EnvTest) Stop() {
+	By("tearing down the test environment")
+	ExpectWithOffset(1, e.Env.Stop()).To(Succeed())
+}
@@ -75,7 +75,7 @@ func CreateScrutinizePod(ctx context.Context, c client.Client, vscr *vapi.Vertic
 	vdb := v1vapi.MakeVDB()
 	pod := builder.BuildScrutinizePod(vscr, vdb, []string{
 		"--tarball-name", "test",
-	})
+	}, nil)
 	ExpectWithOffset(1, c.Create(ctx, pod)).Should(Succeed())
 }
 
@@ -0,0 +1,96 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vadmin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	vops "github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vertica-kubernetes/pkg/net"
+	"github.com/vertica/vertica-kubernetes/pkg/tls"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/clusterhealth"
+)
+
+// clusterHealthTimeLayout is the format vclusterops expects for the start and
+// end time of the analyses. The times are in UTC.
+const clusterHealthTimeLayout = "2006-01-02 15:04:05.999999"
+
+// ClusterHealth will run the slow event cascade, lock cascade and missing
+// lock release analyses over the given time range.
+func (v *VClusterOps) ClusterHealth(ctx context.Context, opts ...clusterhealth.Option) (*ClusterHealthReport, error) {
+	v.setupForAPICall("ClusterHealth")
+	defer v.tearDownForAPICall()
+	v.Log.Info("Starting vcluster ClusterHealth")
+
+	certs, err := v.retrieveHTTPSCerts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s := clusterhealth.Params{}
+	s.Make(opts...)
+
+	vcOpts := v.genClusterHealthOptions(&s, certs)
+	err = v.VClusterHealth(vcOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check cluster health: %w", err)
+	}
+
+	report := &ClusterHealthReport{
+		SlowEvents: vcOpts.SlowEventCascade,
+		LockWaits:  vcOpts.LockEventCascade,
+	}
+	if vcOpts.MissingReleasesResult != nil {
+		report.MissingReleases = *vcOpts.MissingReleasesResult
+	}
+	v.Log.Info("Successfully checked cluster health", "slowEvents", len(report.SlowEvents),
+		"lockWaits", len(report.LockWaits), "missingReleases", len(report.MissingReleases))
+	return report, nil
+}
+
+func (v *VClusterOps) genClusterHealthOptions(s *clusterhealth.Params,
+	certs *tls.HTTPSCerts) *vops.VClusterHealthOptions {
+	opts := vops.VClusterHealthFactory()
+
+	opts.RawHosts = append(opts.RawHosts, s.InitiatorIP)
+	opts.DBName = v.VDB.Spec.DBName
+	opts.IsEon = v.VDB.IsEON()
+	opts.IPv6 = net.IsIPv6(s.InitiatorIP)
+
+	opts.StartTime = s.StartTime.UTC().Format(clusterHealthTimeLayout)
+	opts.EndTime = s.EndTime.UTC().Format(clusterHealthTimeLayout)
+	if s.SlowEventThreshold > 0 {
+		opts.MinMutexDuration = fmt.Sprintf("%d", s.SlowEventThreshold.Microseconds())
+	}
+	if s.LockWaitThreshold > 0 {
+		opts.LockAttemptThresHold = formatLockThreshold(s.LockWaitThreshold)
+		opts.LockReleaseThresHold = opts.LockAttemptThresHold
+	}
+
+	opts.UserName = v.VDB.GetVerticaUser()
+	v.setAuthentication(&opts.DatabaseOptions, v.VDB.GetVerticaUser(), v.Password, certs)
+
+	return &opts
+}
+
+// formatLockThreshold returns the duration in the HH:MM:SS interval format
+// that the lock analyses use
+func formatLockThreshold(d time.Duration) string {
+	secs := int64(d.Round(time.Second) / time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", secs/3600, (secs%3600)/60, secs%60)
+}
@@ -0,0 +1,88 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vadmin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	vops "github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vertica-kubernetes/pkg/test"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/clusterhealth"
+)
+
+const (
+	TestHealthStartTime = "2025-03-10 10:00:00"
+	TestHealthEndTime   = "2025-03-10 10:05:00"
+)
+
+// mock version of VClusterHealth() that is invoked inside VClusterOps.ClusterHealth()
+func (m *MockVClusterOps) VClusterHealth(options *vops.VClusterHealthOptions) error {
+	// verify common options
+	err := m.VerifyCommonOptions(&options.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	// verify hosts and eon mode
+	err = m.VerifyInitiatorIPAndEonMode(&options.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	// verify basic options
+	if options.StartTime != TestHealthStartTime || options.EndTime != TestHealthEndTime {
+		return fmt.Errorf("failed to retrieve time range: %s - %s", options.StartTime, options.EndTime)
+	}
+	if options.MinMutexDuration != "250000" {
+		return fmt.Errorf("failed to retrieve slow event threshold: %s", options.MinMutexDuration)
+	}
+	if options.LockAttemptThresHold != "00:01:30" || options.LockReleaseThresHold != "00:01:30" {
+		return fmt.Errorf("failed to retrieve lock threshold: %s", options.LockAttemptThresHold)
+	}
+
+	options.LockEventCascade = []vops.NodeLockEvents{{NodeName: "v_db_node0001", MaxDuration: "12.5000"}}
+	options.MissingReleasesResult = &[]vops.DcLockAttempts{{NodeName: "v_db_node0002"}}
+
+	// verify auth options
+	return m.VerifyCerts(&options.DatabaseOptions)
+}
+
+var _ = Describe("cluster_health_vc", func() {
+	ctx := context.Background()
+
+	It("should call vclusterOps library with cluster_health task", func() {
+		dispatcher := mockVClusterOpsDispatcher()
+		dispatcher.VDB.Spec.DBName = TestDBName
+		dispatcher.VDB.Spec.HTTPSNMATLS.Secret = "cluster-health"
+		test.CreateFakeTLSSecret(ctx, dispatcher.VDB, dispatcher.Client, dispatcher.VDB.Spec.HTTPSNMATLS.Secret)
+		defer test.DeleteSecret(ctx, dispatcher.Client, dispatcher.VDB.Spec.HTTPSNMATLS.Secret)
+
+		start := time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC)
+		report, err := dispatcher.ClusterHealth(ctx,
+			clusterhealth.WithInitiator(TestInitiatorIP),
+			clusterhealth.WithTimeRange(start, start.Add(5*time.Minute)),
+			clusterhealth.WithLockWaitThreshold(90*time.Second),
+			clusterhealth.WithSlowEventThreshold(250*time.Millisecond))
+		Ω(err).Should(Succeed())
+		Ω(report.LockWaits).Should(HaveLen(1))
+		Ω(report.MissingReleases).Should(HaveLen(1))
+		Ω(report.SlowEvents).Should(BeEmpty())
+	})
+})
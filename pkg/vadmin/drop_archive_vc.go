@@ -0,0 +1,71 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vadmin
+
+import (
+	"context"
+
+	vops "github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vertica-kubernetes/pkg/net"
+	"github.com/vertica/vertica-kubernetes/pkg/tls"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/droparchive"
+)
+
+// DropArchive will drop an archive, and all of its restore points, from the database
+//
+//nolint:dupl
+func (v *VClusterOps) DropArchive(ctx context.Context, opts ...droparchive.Option) error {
+	v.setupForAPICall("DropArchive")
+	defer v.tearDownForAPICall()
+	v.Log.Info("Starting vcluster DropArchive")
+
+	// get the certs
+	certs, err := v.retrieveHTTPSCerts(ctx)
+	if err != nil {
+		return err
+	}
+
+	s := droparchive.Params{}
+	s.Make(opts...)
+
+	// call vclusterOps library to drop the archive
+	vopts := v.genDropArchiveOptions(&s, certs)
+	err = v.VDropArchive(&vopts)
+	if err != nil {
+		v.Log.Error(err, "failed to drop archive", "archive name",
+			vopts.ArchiveName, "sandbox", vopts.Sandbox)
+		return err
+	}
+
+	v.Log.Info("Successfully dropped archive", "archive name",
+		vopts.ArchiveName, "sandbox", vopts.Sandbox)
+	return nil
+}
+
+func (v *VClusterOps) genDropArchiveOptions(s *droparchive.Params, certs *tls.HTTPSCerts) vops.VDropArchiveOptions {
+	opts := vops.VDropArchiveFactory()
+
+	opts.DBName = v.VDB.Spec.DBName
+	opts.IsEon = v.VDB.IsEON()
+	opts.RawHosts = append(opts.RawHosts, s.InitiatorIP)
+	opts.ArchiveName = s.ArchiveName
+	opts.Sandbox = s.Sandbox
+	opts.IPv6 = net.IsIPv6(s.InitiatorIP)
+
+	v.setAuthentication(&opts.DatabaseOptions, v.VDB.GetVerticaUser(), v.Password, certs)
+
+	return opts
+}
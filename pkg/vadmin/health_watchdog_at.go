@@ -0,0 +1,40 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vadmin
+
+import (
+	"context"
+	"errors"
+
+	vops "github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/cancelwatchdogquery"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/gethealthwatchdog"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/sethealthwatchdog"
+)
+
+func (a *Admintools) SetHealthWatchdog(_ context.Context, _ ...sethealthwatchdog.Option) error {
+	return errors.New("SetHealthWatchdog is not supported for admintools deployments")
+}
+
+func (a *Admintools) GetHealthWatchdog(_ context.Context, _ ...gethealthwatchdog.Option) (
+	[]vops.HealthWatchdogHostValues, error) {
+	return nil, errors.New("GetHealthWatchdog is not supported for admintools deployments")
+}
+
+func (a *Admintools) CancelWatchdogQuery(_ context.Context, _ ...cancelwatchdogquery.Option) (
+	[]vops.HealthWatchdogCancelQueryResponse, error) {
+	return nil, errors.New("CancelWatchdogQuery is not supported for admintools deployments")
+}
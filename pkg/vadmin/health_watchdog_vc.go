@@ -0,0 +1,163 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vadmin
+
+import (
+	"context"
+	"fmt"
+
+	vops "github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vertica-kubernetes/pkg/net"
+	"github.com/vertica/vertica-kubernetes/pkg/tls"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/cancelwatchdogquery"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/gethealthwatchdog"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/sethealthwatchdog"
+)
+
+// SetHealthWatchdog will set or clear a health watchdog parameter, or set the
+// watchdog policy
+func (v *VClusterOps) SetHealthWatchdog(ctx context.Context, opts ...sethealthwatchdog.Option) error {
+	v.setupForAPICall("SetHealthWatchdog")
+	defer v.tearDownForAPICall()
+	v.Log.Info("Starting vcluster SetHealthWatchdog")
+
+	certs, err := v.retrieveHTTPSCerts(ctx)
+	if err != nil {
+		return err
+	}
+
+	s := sethealthwatchdog.Params{}
+	s.Make(opts...)
+
+	vcOpts := v.genSetHealthWatchdogOptions(&s, certs)
+	err = v.VHealthWatchdogSet(vcOpts)
+	if err != nil {
+		return fmt.Errorf("failed to set health watchdog %s %q: %w", s.Action, s.ParameterName, err)
+	}
+
+	v.Log.Info("Successfully set health watchdog", "action", s.Action, "parameterName", s.ParameterName)
+	return nil
+}
+
+func (v *VClusterOps) genSetHealthWatchdogOptions(s *sethealthwatchdog.Params,
+	certs *tls.HTTPSCerts) *vops.VHealthWatchdogSetOptions {
+	opts := vops.VHealthWatchdogSetOptionsFactory()
+
+	opts.RawHosts = append(opts.RawHosts, s.InitiatorIP)
+	opts.DBName = v.VDB.Spec.DBName
+	opts.IsEon = v.VDB.IsEON()
+	opts.IPv6 = net.IsIPv6(s.InitiatorIP)
+
+	opts.Action = s.Action
+	opts.ParameterName = s.ParameterName
+	opts.Value = s.Value
+	opts.PolicySettings = s.PolicySettings
+
+	opts.UserName = v.VDB.GetVerticaUser()
+	v.setAuthentication(&opts.DatabaseOptions, v.VDB.GetVerticaUser(), v.Password, certs)
+
+	return &opts
+}
+
+// GetHealthWatchdog will return the values the health watchdog reports for
+// an action, per host
+func (v *VClusterOps) GetHealthWatchdog(ctx context.Context, opts ...gethealthwatchdog.Option) (
+	[]vops.HealthWatchdogHostValues, error) {
+	v.setupForAPICall("GetHealthWatchdog")
+	defer v.tearDownForAPICall()
+	v.Log.Info("Starting vcluster GetHealthWatchdog")
+
+	certs, err := v.retrieveHTTPSCerts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s := gethealthwatchdog.Params{}
+	s.Make(opts...)
+
+	vcOpts := v.genGetHealthWatchdogOptions(&s, certs)
+	values, err := v.VHealthWatchdogGet(vcOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get health watchdog %s: %w", s.Action, err)
+	}
+	if values == nil {
+		return nil, nil
+	}
+
+	v.Log.Info("Successfully got health watchdog values", "action", s.Action, "hosts", len(*values))
+	return *values, nil
+}
+
+func (v *VClusterOps) genGetHealthWatchdogOptions(s *gethealthwatchdog.Params,
+	certs *tls.HTTPSCerts) *vops.VHealthWatchdogGetOptions {
+	opts := vops.VHealthWatchdogGetValueOptionsFactory()
+
+	opts.RawHosts = append(opts.RawHosts, s.InitiatorIP)
+	opts.DBName = v.VDB.Spec.DBName
+	opts.IsEon = v.VDB.IsEON()
+	opts.IPv6 = net.IsIPv6(s.InitiatorIP)
+
+	opts.Action = s.Action
+	opts.ParameterName = s.ParameterName
+
+	opts.UserName = v.VDB.GetVerticaUser()
+	v.setAuthentication(&opts.DatabaseOptions, v.VDB.GetVerticaUser(), v.Password, certs)
+
+	return &opts
+}
+
+// CancelWatchdogQuery will cancel queries through the health watchdog. It
+// returns the outcome for each session.
+func (v *VClusterOps) CancelWatchdogQuery(ctx context.Context, opts ...cancelwatchdogquery.Option) (
+	[]vops.HealthWatchdogCancelQueryResponse, error) {
+	v.setupForAPICall("CancelWatchdogQuery")
+	defer v.tearDownForAPICall()
+	v.Log.Info("Starting vcluster CancelWatchdogQuery")
+
+	certs, err := v.retrieveHTTPSCerts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s := cancelwatchdogquery.Params{}
+	s.Make(opts...)
+
+	vcOpts := v.genCancelWatchdogQueryOptions(&s, certs)
+	responses, err := v.VHealthWatchdogCancelQuery(vcOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel queries through the health watchdog: %w", err)
+	}
+
+	v.Log.Info("Successfully cancelled queries through the health watchdog", "sessions", len(responses))
+	return responses, nil
+}
+
+func (v *VClusterOps) genCancelWatchdogQueryOptions(s *cancelwatchdogquery.Params,
+	certs *tls.HTTPSCerts) *vops.VHealthWatchdogCancelQueryOptions {
+	opts := vops.VHealthWatchdogCancelQueryOptionsFactory()
+
+	opts.RawHosts = append(opts.RawHosts, s.InitiatorIP)
+	opts.DBName = v.VDB.Spec.DBName
+	opts.IsEon = v.VDB.IsEON()
+	opts.IPv6 = net.IsIPv6(s.InitiatorIP)
+
+	opts.Sessions = s.Sessions
+
+	opts.UserName = v.VDB.GetVerticaUser()
+	v.setAuthentication(&opts.DatabaseOptions, v.VDB.GetVerticaUser(), v.Password, certs)
+
+	return &opts
+}
@@ -0,0 +1,144 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vadmin
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	vops "github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vertica-kubernetes/pkg/test"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/cancelwatchdogquery"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/gethealthwatchdog"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/sethealthwatchdog"
+)
+
+const (
+	TestWatchdogParameter = "QueryRuntimeLimit"
+	TestWatchdogValue     = "600"
+	TestWatchdogSessionID = "v_db_node0001-1234:0x56"
+)
+
+// mock version of VHealthWatchdogSet() that is invoked inside VClusterOps.SetHealthWatchdog()
+func (m *MockVClusterOps) VHealthWatchdogSet(options *vops.VHealthWatchdogSetOptions) error {
+	// verify common options
+	err := m.VerifyCommonOptions(&options.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	// verify hosts and eon mode
+	err = m.VerifyInitiatorIPAndEonMode(&options.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	// verify basic options
+	if options.Action != sethealthwatchdog.ActionSetConfigParameter {
+		return fmt.Errorf("failed to retrieve action: %s", options.Action)
+	}
+	if options.ParameterName != TestWatchdogParameter || options.Value != TestWatchdogValue {
+		return fmt.Errorf("failed to retrieve parameter: %s=%s", options.ParameterName, options.Value)
+	}
+
+	// verify auth options
+	return m.VerifyCerts(&options.DatabaseOptions)
+}
+
+// mock version of VHealthWatchdogGet() that is invoked inside VClusterOps.GetHealthWatchdog()
+func (m *MockVClusterOps) VHealthWatchdogGet(options *vops.VHealthWatchdogGetOptions) (
+	*[]vops.HealthWatchdogHostValues, error) {
+	// verify common options
+	err := m.VerifyCommonOptions(&options.DatabaseOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	// verify hosts and eon mode
+	err = m.VerifyInitiatorIPAndEonMode(&options.DatabaseOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	// verify basic options
+	if options.Action != gethealthwatchdog.ActionCheckClusterHealth {
+		return nil, fmt.Errorf("failed to retrieve action: %s", options.Action)
+	}
+
+	values := []vops.HealthWatchdogHostValues{
+		{Host: TestInitiatorIP, Values: []vops.HealthWatchdogValue{{"session_id": TestWatchdogSessionID}}},
+	}
+	return &values, m.VerifyCerts(&options.DatabaseOptions)
+}
+
+// mock version of VHealthWatchdogCancelQuery() that is invoked inside VClusterOps.CancelWatchdogQuery()
+func (m *MockVClusterOps) VHealthWatchdogCancelQuery(options *vops.VHealthWatchdogCancelQueryOptions) (
+	[]vops.HealthWatchdogCancelQueryResponse, error) {
+	// verify common options
+	err := m.VerifyCommonOptions(&options.DatabaseOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	// verify hosts and eon mode
+	err = m.VerifyInitiatorIPAndEonMode(&options.DatabaseOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	// verify basic options
+	if len(options.Sessions) != 1 || options.Sessions[0].SessionID != TestWatchdogSessionID ||
+		options.Sessions[0].StatementID != 2 {
+		return nil, fmt.Errorf("failed to retrieve sessions: %v", options.Sessions)
+	}
+
+	resp := []vops.HealthWatchdogCancelQueryResponse{
+		{Status: "success", SessionID: TestWatchdogSessionID, StatementID: 2},
+	}
+	return resp, m.VerifyCerts(&options.DatabaseOptions)
+}
+
+var _ = Describe("health_watchdog_vc", func() {
+	ctx := context.Background()
+
+	It("should call vclusterOps library with the health watchdog tasks", func() {
+		dispatcher := mockVClusterOpsDispatcher()
+		dispatcher.VDB.Spec.DBName = TestDBName
+		dispatcher.VDB.Spec.HTTPSNMATLS.Secret = "health-watchdog"
+		test.CreateFakeTLSSecret(ctx, dispatcher.VDB, dispatcher.Client, dispatcher.VDB.Spec.HTTPSNMATLS.Secret)
+		defer test.DeleteSecret(ctx, dispatcher.Client, dispatcher.VDB.Spec.HTTPSNMATLS.Secret)
+
+		Ω(dispatcher.SetHealthWatchdog(ctx,
+			sethealthwatchdog.WithInitiator(TestInitiatorIP),
+			sethealthwatchdog.WithConfigParameter(TestWatchdogParameter, TestWatchdogValue))).Should(Succeed())
+
+		values, err := dispatcher.GetHealthWatchdog(ctx,
+			gethealthwatchdog.WithInitiator(TestInitiatorIP),
+			gethealthwatchdog.WithAction(gethealthwatchdog.ActionCheckClusterHealth))
+		Ω(err).Should(Succeed())
+		Ω(values).Should(HaveLen(1))
+		Ω(values[0].Values).Should(HaveLen(1))
+
+		resp, err := dispatcher.CancelWatchdogQuery(ctx,
+			cancelwatchdogquery.WithInitiator(TestInitiatorIP),
+			cancelwatchdogquery.WithSession(TestWatchdogSessionID, 2))
+		Ω(err).Should(Succeed())
+		Ω(resp).Should(HaveLen(1))
+		Ω(resp[0].Status).Should(Equal("success"))
+	})
+})
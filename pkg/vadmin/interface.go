@@ -28,13 +28,18 @@ import (
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/addnode"
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/addsc"
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/altersc"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/cancelwatchdogquery"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/clusterhealth"
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/createarchive"
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/createdb"
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/describedb"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/droparchive"
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/dropdb"
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/fetchnodedetails"
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/fetchnodestate"
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/getconfigparameter"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/getdrainingstatus"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/gethealthwatchdog"
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/installpackages"
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/manageconnectiondraining"
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/pollhttps"
@@ -42,6 +47,7 @@ import (
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/promotesandboxtomain"
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/reip"
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/removenode"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/removerestorepoint"
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/removesc"
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/renamesc"
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/replicationstart"
@@ -52,7 +58,9 @@ import (
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/rotatetlscerts"
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/sandboxsc"
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/saverestorepoint"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/setclienttlsauth"
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/setconfigparameter"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/sethealthwatchdog"
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/settlsconfig"
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/showrestorepoints"
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/startdb"
@@ -139,6 +147,12 @@ type Dispatcher interface {
 	// SaveRestorePoint will create a restore point to an existing archive
 	SaveRestorePoint(ctx context.Context, opts ...saverestorepoint.Option) error
 
+	// RemoveRestorePoint will remove a restore point from an existing archive
+	RemoveRestorePoint(ctx context.Context, opts ...removerestorepoint.Option) error
+
+	// DropArchive will drop an archive and all of its restore points
+	DropArchive(ctx context.Context, opts ...droparchive.Option) error
+
 	// StopSubcluster will stop a subcluster from Vertica db
 	StopSubcluster(ctx context.Context, opts ...stopsubcluster.Option) error
 
@@ -158,6 +172,9 @@ type Dispatcher interface {
 
 	// ManageConnectionDraining will pause/redirect/resume client connections for a subcluster
 	ManageConnectionDraining(ctx context.Context, opts ...manageconnectiondraining.Option) error
+	// GetDrainingStatus will return the connection draining status of each
+	// subcluster in the main cluster or a sandbox
+	GetDrainingStatus(ctx context.Context, opts ...getdrainingstatus.Option) ([]vops.DrainingStatus, error)
 
 	// RotateNMACerts will rotate nma cert
 	RotateNMACerts(ctx context.Context, opts ...rotatenmacerts.Option) error
@@ -171,6 +188,30 @@ type Dispatcher interface {
 	DropDB(ctx context.Context, opts ...dropdb.Option) error
 	// PollHttps will poll https service
 	PollHTTPS(ctx context.Context, opts ...pollhttps.Option) error
+	// ClusterHealth will look for slow events, lock waits and missing lock
+	// releases that happened in a time range
+	ClusterHealth(ctx context.Context, opts ...clusterhealth.Option) (*ClusterHealthReport, error)
+	// SetHealthWatchdog will set or clear a health watchdog parameter, or set
+	// the watchdog policy
+	SetHealthWatchdog(ctx context.Context, opts ...sethealthwatchdog.Option) error
+	// GetHealthWatchdog will return what the health watchdog reports for an
+	// action, per host
+	GetHealthWatchdog(ctx context.Context, opts ...gethealthwatchdog.Option) ([]vops.HealthWatchdogHostValues, error)
+	// CancelWatchdogQuery will cancel queries through the health watchdog
+	CancelWatchdogQuery(ctx context.Context, opts ...cancelwatchdogquery.Option) ([]vops.HealthWatchdogCancelQueryResponse, error)
+	// SetClientTLSAuth will set the CA certificates trusted to sign client
+	// certificates and change the TLS authentication methods
+	SetClientTLSAuth(ctx context.Context, opts ...setclienttlsauth.Option) error
+}
+
+// ClusterHealthReport has the findings of the cluster health analyses
+type ClusterHealthReport struct {
+	// The cascade of mutex events rooted at the slowest event in the range
+	SlowEvents []vops.SlowEventNode
+	// The longest series of lock waits for each node that had one
+	LockWaits []vops.NodeLockEvents
+	// Locks that were granted but never released
+	MissingReleases []vops.DcLockAttempts
 }
 
 const (
@@ -324,15 +365,23 @@ type VClusterProvider interface {
 	VUnsandbox(options *vops.VUnsandboxOptions) error
 	VCreateArchive(options *vops.VCreateArchiveOptions) error
 	VSaveRestorePoint(options *vops.VSaveRestorePointOptions) error
+	VRemoveRestorePoint(options *vops.VRemoveRestorePointOptions) error
+	VDropArchive(options *vops.VDropArchiveOptions) error
 	VAlterSubclusterType(options *vops.VAlterSubclusterTypeOptions) error
 	VSetConfigurationParameters(options *vops.VSetConfigurationParameterOptions) error
 	VGetConfigurationParameters(options *vops.VGetConfigurationParameterOptions) (string, error)
 	VRenameSubcluster(options *vops.VRenameSubclusterOptions) error
 	VPollSubclusterState(options *vops.VPollSubclusterStateOptions) error
 	VManageConnectionDraining(options *vops.VManageConnectionDrainingOptions) error
+	VGetDrainingStatus(options *vops.VGetDrainingStatusOptions) (vops.DrainingStatusList, error)
 	VRotateNMACerts(options *vops.VRotateNMACertsOptions) error
 	VRotateTLSCerts(options *vops.VRotateTLSCertsOptions) error
 	VSetTLSConfig(options *vops.VSetTLSConfigOptions) error
 	VDropDatabase(options *vops.VDropDatabaseOptions) error
 	VPollHTTPS(options *vops.VPollHTTPSOptions) error
+	VClusterHealth(options *vops.VClusterHealthOptions) error
+	VHealthWatchdogSet(options *vops.VHealthWatchdogSetOptions) error
+	VHealthWatchdogGet(options *vops.VHealthWatchdogGetOptions) (*[]vops.HealthWatchdogHostValues, error)
+	VHealthWatchdogCancelQuery(options *vops.VHealthWatchdogCancelQueryOptions) ([]vops.HealthWatchdogCancelQueryResponse, error)
+	VSetClientTLSAuth(options *vops.VSetClientTLSAuthOptions) error
 }
@@ -19,9 +19,15 @@ import (
 	"context"
 	"errors"
 
+	vops "github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/getdrainingstatus"
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/manageconnectiondraining"
 )
 
 func (a *Admintools) ManageConnectionDraining(_ context.Context, opts ...manageconnectiondraining.Option) error {
 	return errors.New("ManageConnectionDraining is not supported for admintools deployments")
 }
+
+func (a *Admintools) GetDrainingStatus(_ context.Context, _ ...getdrainingstatus.Option) ([]vops.DrainingStatus, error) {
+	return nil, errors.New("GetDrainingStatus is not supported for admintools deployments")
+}
@@ -22,6 +22,7 @@ import (
 	vops "github.com/vertica/vcluster/vclusterops"
 	"github.com/vertica/vertica-kubernetes/pkg/net"
 	"github.com/vertica/vertica-kubernetes/pkg/tls"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/getdrainingstatus"
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/manageconnectiondraining"
 )
 
@@ -70,3 +71,45 @@ func (v *VClusterOps) genManageConnectionDrainingOptions(s *manageconnectiondrai
 
 	return &opts
 }
+
+// GetDrainingStatus returns the connection draining status of each subcluster
+// in the main cluster or a sandbox
+func (v *VClusterOps) GetDrainingStatus(ctx context.Context, opts ...getdrainingstatus.Option) ([]vops.DrainingStatus, error) {
+	v.setupForAPICall("GetDrainingStatus")
+	defer v.tearDownForAPICall()
+	v.Log.Info("Starting vcluster GetDrainingStatus")
+
+	certs, err := v.retrieveHTTPSCerts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s := getdrainingstatus.Params{}
+	s.Make(opts...)
+
+	vcOpts := v.genGetDrainingStatusOptions(&s, certs)
+	dsList, err := v.VGetDrainingStatus(vcOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection draining status: %w", err)
+	}
+
+	return dsList.StatusList, nil
+}
+
+func (v *VClusterOps) genGetDrainingStatusOptions(s *getdrainingstatus.Params,
+	certs *tls.HTTPSCerts) *vops.VGetDrainingStatusOptions {
+	opts := vops.VGetDrainingStatusFactory()
+
+	opts.RawHosts = append(opts.RawHosts, s.InitiatorIP)
+	opts.DBName = v.VDB.Spec.DBName
+
+	opts.Sandbox = s.Sandbox
+
+	opts.IsEon = v.VDB.IsEON()
+	opts.IPv6 = net.IsIPv6(s.InitiatorIP)
+
+	opts.UserName = v.VDB.GetVerticaUser()
+	v.setAuthentication(&opts.DatabaseOptions, v.VDB.GetVerticaUser(), v.Password, certs)
+
+	return &opts
+}
@@ -24,6 +24,7 @@ import (
 
 	vops "github.com/vertica/vcluster/vclusterops"
 	"github.com/vertica/vertica-kubernetes/pkg/test"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/getdrainingstatus"
 	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/manageconnectiondraining"
 )
 
@@ -47,6 +48,31 @@ func (m *MockVClusterOps) VManageConnectionDraining(options *vops.VManageConnect
 	return nil
 }
 
+// mock version of VGetDrainingStatus() that is invoked inside VClusterOps.GetDrainingStatus()
+func (m *MockVClusterOps) VGetDrainingStatus(options *vops.VGetDrainingStatusOptions) (vops.DrainingStatusList, error) {
+	dsList := vops.DrainingStatusList{}
+	// verify basic options
+	err := m.VerifyCommonOptions(&options.DatabaseOptions)
+	if err != nil {
+		return dsList, err
+	}
+
+	err = m.VerifyCerts(&options.DatabaseOptions)
+	if err != nil {
+		return dsList, err
+	}
+
+	if len(options.RawHosts) == 0 || options.RawHosts[0] != TestInitiatorIP {
+		return dsList, fmt.Errorf("failed to retrieve hosts")
+	}
+	if options.Sandbox != TestConfigParamSandbox {
+		return dsList, fmt.Errorf("failed to retrieve sandbox: %s", options.Sandbox)
+	}
+
+	dsList.StatusList = []vops.DrainingStatus{{SubclusterName: TestSCName, Status: "paused"}}
+	return dsList, nil
+}
+
 var _ = Describe("manage_connection_draining_vc", func() {
 	ctx := context.Background()
 
@@ -64,4 +90,20 @@ var _ = Describe("manage_connection_draining_vc", func() {
 		)
 		Ω(err).Should(Succeed())
 	})
+
+	It("should call VGetDrainingStatus in the vcluster-ops library", func() {
+		dispatcher := mockVClusterOpsDispatcher()
+		dispatcher.VDB.Spec.DBName = TestDBName
+		dispatcher.VDB.Spec.HTTPSNMATLS.Secret = "get-draining-status-test-secret"
+		test.CreateFakeTLSSecret(ctx, dispatcher.VDB, dispatcher.Client, dispatcher.VDB.Spec.HTTPSNMATLS.Secret)
+		defer test.DeleteSecret(ctx, dispatcher.Client, dispatcher.VDB.Spec.HTTPSNMATLS.Secret)
+
+		statusList, err := dispatcher.GetDrainingStatus(ctx,
+			getdrainingstatus.WithInitiator(TestInitiatorIP),
+			getdrainingstatus.WithSandbox(TestConfigParamSandbox),
+		)
+		Ω(err).Should(Succeed())
+		Ω(statusList).Should(HaveLen(1))
+		Ω(statusList[0].SubclusterName).Should(Equal(TestSCName))
+	})
 })
@@ -0,0 +1,53 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package cancelwatchdogquery
+
+import vops "github.com/vertica/vcluster/vclusterops"
+
+// Params holds all of the option for a health watchdog cancel query
+// invocation.
+type Params struct {
+	InitiatorIP string
+	// Required arguments. Each entry is a session, or a single statement of a
+	// session, to cancel.
+	Sessions []vops.HealthWatchdogCancelQueryOptions
+}
+
+type Option func(*Params)
+
+// Make will fill in the Params based on the options chosen
+func (s *Params) Make(opts ...Option) {
+	for _, opt := range opts {
+		opt(s)
+	}
+}
+
+func WithInitiator(initiatorIP string) Option {
+	return func(s *Params) {
+		s.InitiatorIP = initiatorIP
+	}
+}
+
+// WithSession adds a query to cancel. A statementID of zero cancels whatever
+// the session is running.
+func WithSession(sessionID string, statementID int64) Option {
+	return func(s *Params) {
+		s.Sessions = append(s.Sessions, vops.HealthWatchdogCancelQueryOptions{
+			SessionID:   sessionID,
+			StatementID: statementID,
+		})
+	}
+}
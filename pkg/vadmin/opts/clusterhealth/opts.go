@@ -0,0 +1,65 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package clusterhealth
+
+import "time"
+
+// Params holds all of the option for a cluster health invocation.
+type Params struct {
+	InitiatorIP string
+	// Required arguments. The analyses look at the events that happened
+	// between the start and end time.
+	StartTime time.Time
+	EndTime   time.Time
+	// Optional arguments. vclusterops picks its own default if a threshold is
+	// not set.
+	LockWaitThreshold  time.Duration
+	SlowEventThreshold time.Duration
+}
+
+type Option func(*Params)
+
+// Make will fill in the Params based on the options chosen
+func (s *Params) Make(opts ...Option) {
+	for _, opt := range opts {
+		opt(s)
+	}
+}
+
+func WithInitiator(initiatorIP string) Option {
+	return func(s *Params) {
+		s.InitiatorIP = initiatorIP
+	}
+}
+
+func WithTimeRange(startTime, endTime time.Time) Option {
+	return func(s *Params) {
+		s.StartTime = startTime
+		s.EndTime = endTime
+	}
+}
+
+func WithLockWaitThreshold(threshold time.Duration) Option {
+	return func(s *Params) {
+		s.LockWaitThreshold = threshold
+	}
+}
+
+func WithSlowEventThreshold(threshold time.Duration) Option {
+	return func(s *Params) {
+		s.SlowEventThreshold = threshold
+	}
+}
@@ -0,0 +1,56 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package gethealthwatchdog
+
+// ActionCheckClusterHealth asks the watchdog for the problems it currently
+// sees in the database, such as runaway queries
+const ActionCheckClusterHealth = "check_cluster_health"
+
+// Params holds all of the option for a health watchdog get invocation.
+type Params struct {
+	InitiatorIP string
+	// Required arguments
+	Action string
+	// Optional arguments
+	ParameterName string
+}
+
+type Option func(*Params)
+
+// Make will fill in the Params based on the options chosen
+func (s *Params) Make(opts ...Option) {
+	for _, opt := range opts {
+		opt(s)
+	}
+}
+
+func WithInitiator(initiatorIP string) Option {
+	return func(s *Params) {
+		s.InitiatorIP = initiatorIP
+	}
+}
+
+func WithAction(action string) Option {
+	return func(s *Params) {
+		s.Action = action
+	}
+}
+
+func WithParameterName(name string) Option {
+	return func(s *Params) {
+		s.ParameterName = name
+	}
+}
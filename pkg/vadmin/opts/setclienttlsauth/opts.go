@@ -0,0 +1,87 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package setclienttlsauth
+
+import vops "github.com/vertica/vcluster/vclusterops"
+
+// Params holds all of the option for a set client tls auth invocation.
+type Params struct {
+	InitiatorIP string
+	// The secret that the server TLS configuration is set from. Its CA
+	// certificates include the ones that sign client certificates. The
+	// server TLS configuration is left as is in the database if empty.
+	TrustedCASecret string
+	Namespace       string
+	TLSMode         string
+	// The changes to the TLS authentication methods
+	DropMethods   []string
+	CreateMethods []vops.ClientTLSAuthMethod
+	Revokes       []vops.ClientTLSAuthGrant
+	Grants        []vops.ClientTLSAuthGrant
+}
+
+type Option func(*Params)
+
+// Make will fill in the Params based on the options chosen
+func (s *Params) Make(opts ...Option) {
+	for _, opt := range opts {
+		opt(s)
+	}
+}
+
+func WithInitiator(initiatorIP string) Option {
+	return func(s *Params) {
+		s.InitiatorIP = initiatorIP
+	}
+}
+
+// WithTrustedCASecret sets the secret, and its namespace, that the server TLS
+// configuration is set from, along with the TLS mode to keep
+func WithTrustedCASecret(secret, namespace, tlsMode string) Option {
+	return func(s *Params) {
+		s.TrustedCASecret = secret
+		s.Namespace = namespace
+		s.TLSMode = tlsMode
+	}
+}
+
+func WithDropMethod(name string) Option {
+	return func(s *Params) {
+		s.DropMethods = append(s.DropMethods, name)
+	}
+}
+
+func WithCreateMethod(name, host string, priority int) Option {
+	return func(s *Params) {
+		s.CreateMethods = append(s.CreateMethods, vops.ClientTLSAuthMethod{
+			Name:     name,
+			Host:     host,
+			Priority: priority,
+		})
+	}
+}
+
+func WithRevoke(method, grantee string) Option {
+	return func(s *Params) {
+		s.Revokes = append(s.Revokes, vops.ClientTLSAuthGrant{Method: method, Grantee: grantee})
+	}
+}
+
+func WithGrant(method, grantee string) Option {
+	return func(s *Params) {
+		s.Grants = append(s.Grants, vops.ClientTLSAuthGrant{Method: method, Grantee: grantee})
+	}
+}
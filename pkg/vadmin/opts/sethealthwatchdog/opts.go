@@ -0,0 +1,72 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package sethealthwatchdog
+
+// The actions the health watchdog set endpoint accepts
+const (
+	ActionSetConfigParameter   = "set_config_parameter"
+	ActionClearConfigParameter = "clear_config_parameter"
+	ActionSetPolicy            = "set_health_watchdog_policy"
+)
+
+// Params holds all of the option for a health watchdog set invocation.
+type Params struct {
+	InitiatorIP string
+	// Required arguments
+	Action string
+	// Arguments for the config parameter actions
+	ParameterName string
+	Value         string
+	// Arguments for the policy action
+	PolicySettings map[string]string
+}
+
+type Option func(*Params)
+
+// Make will fill in the Params based on the options chosen
+func (s *Params) Make(opts ...Option) {
+	for _, opt := range opts {
+		opt(s)
+	}
+}
+
+func WithInitiator(initiatorIP string) Option {
+	return func(s *Params) {
+		s.InitiatorIP = initiatorIP
+	}
+}
+
+func WithConfigParameter(name, value string) Option {
+	return func(s *Params) {
+		s.Action = ActionSetConfigParameter
+		s.ParameterName = name
+		s.Value = value
+	}
+}
+
+func WithClearConfigParameter(name string) Option {
+	return func(s *Params) {
+		s.Action = ActionClearConfigParameter
+		s.ParameterName = name
+	}
+}
+
+func WithPolicy(settings map[string]string) Option {
+	return func(s *Params) {
+		s.Action = ActionSetPolicy
+		s.PolicySettings = settings
+	}
+}
@@ -0,0 +1,72 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vadmin
+
+import (
+	"context"
+
+	vops "github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vertica-kubernetes/pkg/net"
+	"github.com/vertica/vertica-kubernetes/pkg/tls"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/removerestorepoint"
+)
+
+// RemoveRestorePoint will remove a restore point from an archive in the database
+//
+//nolint:dupl
+func (v *VClusterOps) RemoveRestorePoint(ctx context.Context, opts ...removerestorepoint.Option) error {
+	v.setupForAPICall("RemoveRestorePoint")
+	defer v.tearDownForAPICall()
+	v.Log.Info("Starting vcluster RemoveRestorePoint")
+
+	// get the certs
+	certs, err := v.retrieveHTTPSCerts(ctx)
+	if err != nil {
+		return err
+	}
+
+	s := removerestorepoint.Params{}
+	s.Make(opts...)
+
+	// call vclusterOps library to remove the restore point
+	vopts := v.genRemoveRestorePointOptions(&s, certs)
+	err = v.VRemoveRestorePoint(&vopts)
+	if err != nil {
+		v.Log.Error(err, "failed to remove a restore point from archive", "archive name",
+			vopts.ArchiveName, "index", vopts.Index, "sandbox", vopts.Sandbox)
+		return err
+	}
+
+	v.Log.Info("Successfully removed a restore point from archive", "archive name",
+		vopts.ArchiveName, "index", vopts.Index, "sandbox", vopts.Sandbox)
+	return nil
+}
+
+func (v *VClusterOps) genRemoveRestorePointOptions(s *removerestorepoint.Params, certs *tls.HTTPSCerts) vops.VRemoveRestorePointOptions {
+	opts := vops.VRemoveRestorePointFactory()
+
+	opts.DBName = v.VDB.Spec.DBName
+	opts.IsEon = v.VDB.IsEON()
+	opts.RawHosts = append(opts.RawHosts, s.InitiatorIP)
+	opts.ArchiveName = s.ArchiveName
+	opts.Index = s.Index
+	opts.Sandbox = s.Sandbox
+	opts.IPv6 = net.IsIPv6(s.InitiatorIP)
+
+	v.setAuthentication(&opts.DatabaseOptions, v.VDB.GetVerticaUser(), v.Password, certs)
+
+	return opts
+}
@@ -0,0 +1,75 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vadmin
+
+import (
+	"context"
+	"fmt"
+
+	vops "github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vertica-kubernetes/pkg/net"
+	"github.com/vertica/vertica-kubernetes/pkg/tls"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/setclienttlsauth"
+)
+
+// SetClientTLSAuth will set the CA certificates trusted to sign client
+// certificates and change the TLS authentication methods of the database
+func (v *VClusterOps) SetClientTLSAuth(ctx context.Context, opts ...setclienttlsauth.Option) error {
+	v.setupForAPICall("SetClientTLSAuth")
+	defer v.tearDownForAPICall()
+	v.Log.Info("Starting vcluster SetClientTLSAuth")
+
+	certs, err := v.retrieveHTTPSCerts(ctx)
+	if err != nil {
+		return err
+	}
+
+	s := setclienttlsauth.Params{}
+	s.Make(opts...)
+
+	vcOpts := v.genSetClientTLSAuthOptions(&s, certs)
+	err = v.VSetClientTLSAuth(vcOpts)
+	if err != nil {
+		return fmt.Errorf("failed to set client tls authentication: %w", err)
+	}
+
+	v.Log.Info("Successfully set client tls authentication", "trustedCASecret", s.TrustedCASecret,
+		"droppedMethods", s.DropMethods, "createdMethods", len(s.CreateMethods))
+	return nil
+}
+
+func (v *VClusterOps) genSetClientTLSAuthOptions(s *setclienttlsauth.Params,
+	certs *tls.HTTPSCerts) *vops.VSetClientTLSAuthOptions {
+	opts := vops.VSetClientTLSAuthOptionsFactory()
+
+	opts.RawHosts = append(opts.RawHosts, s.InitiatorIP)
+	opts.DBName = v.VDB.Spec.DBName
+	opts.IsEon = v.VDB.IsEON()
+	opts.IPv6 = net.IsIPv6(s.InitiatorIP)
+
+	if s.TrustedCASecret != "" {
+		opts.TrustedCAConfig.SetConfigMap(genTLSConfigurationMap(s.TLSMode, s.TrustedCASecret, s.Namespace))
+	}
+	opts.DropMethods = s.DropMethods
+	opts.CreateMethods = s.CreateMethods
+	opts.Revokes = s.Revokes
+	opts.Grants = s.Grants
+
+	opts.UserName = v.VDB.GetVerticaUser()
+	v.setAuthentication(&opts.DatabaseOptions, v.VDB.GetVerticaUser(), v.Password, certs)
+
+	return &opts
+}
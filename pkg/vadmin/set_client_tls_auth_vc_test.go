@@ -0,0 +1,100 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vadmin
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	vops "github.com/vertica/vcluster/vclusterops"
+	"github.com/vertica/vertica-kubernetes/pkg/test"
+	"github.com/vertica/vertica-kubernetes/pkg/vadmin/opts/setclienttlsauth"
+)
+
+const (
+	TestTrustedCASecret = "vertica-client-tls-ca"
+	TestTLSAuthMethod   = "svc_tls"
+	TestTLSAuthHost     = "10.0.0.0/8"
+	TestTLSAuthGrantee  = "etl"
+)
+
+// mock version of VSetClientTLSAuth() that is invoked inside VClusterOps.SetClientTLSAuth()
+func (m *MockVClusterOps) VSetClientTLSAuth(options *vops.VSetClientTLSAuthOptions) error {
+	// verify common options
+	err := m.VerifyCommonOptions(&options.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	// verify hosts and eon mode
+	err = m.VerifyInitiatorIPAndEonMode(&options.DatabaseOptions)
+	if err != nil {
+		return err
+	}
+
+	// verify basic options
+	configMap := map[string]string{
+		vops.TLSSecretManagerKeyCACertDataKey: "ca.crt",
+		vops.TLSSecretManagerKeyCertDataKey:   "tls.crt",
+		vops.TLSSecretManagerKeyKeyDataKey:    "tls.key",
+		vops.TLSSecretManagerKeySecretManager: vops.K8sSecretManagerType,
+		vops.TLSSecretManagerKeySecretName:    TestTrustedCASecret,
+		vops.TLSSecretManagerKeyNamespace:     TestNamespace,
+		vops.TLSSecretManagerKeyTLSMode:       "try_verify",
+	}
+	if !maps.Equal(options.TrustedCAConfig.ConfigMap, configMap) {
+		return fmt.Errorf("trusted CA configuration not valid: %v", options.TrustedCAConfig.ConfigMap)
+	}
+	if len(options.DropMethods) != 1 || options.DropMethods[0] != TestTLSAuthMethod {
+		return fmt.Errorf("failed to retrieve methods to drop: %v", options.DropMethods)
+	}
+	if len(options.CreateMethods) != 1 || options.CreateMethods[0].Name != TestTLSAuthMethod ||
+		options.CreateMethods[0].Host != TestTLSAuthHost || options.CreateMethods[0].Priority != 5 {
+		return fmt.Errorf("failed to retrieve methods to create: %v", options.CreateMethods)
+	}
+	if len(options.Grants) != 1 || options.Grants[0].Method != TestTLSAuthMethod ||
+		options.Grants[0].Grantee != TestTLSAuthGrantee {
+		return fmt.Errorf("failed to retrieve grants: %v", options.Grants)
+	}
+	if len(options.Revokes) != 0 {
+		return fmt.Errorf("unexpected revokes: %v", options.Revokes)
+	}
+
+	// verify auth options
+	return m.VerifyCerts(&options.DatabaseOptions)
+}
+
+var _ = Describe("set_client_tls_auth_vc", func() {
+	ctx := context.Background()
+
+	It("should call vclusterOps library with set_client_tls_auth task", func() {
+		dispatcher := mockVClusterOpsDispatcher()
+		dispatcher.VDB.Spec.DBName = TestDBName
+		dispatcher.VDB.Spec.HTTPSNMATLS.Secret = "set-client-tls-auth"
+		test.CreateFakeTLSSecret(ctx, dispatcher.VDB, dispatcher.Client, dispatcher.VDB.Spec.HTTPSNMATLS.Secret)
+		defer test.DeleteSecret(ctx, dispatcher.Client, dispatcher.VDB.Spec.HTTPSNMATLS.Secret)
+
+		Ω(dispatcher.SetClientTLSAuth(ctx,
+			setclienttlsauth.WithInitiator(TestInitiatorIP),
+			setclienttlsauth.WithTrustedCASecret(TestTrustedCASecret, TestNamespace, "TRY_VERIFY"),
+			setclienttlsauth.WithDropMethod(TestTLSAuthMethod),
+			setclienttlsauth.WithCreateMethod(TestTLSAuthMethod, TestTLSAuthHost, 5),
+			setclienttlsauth.WithGrant(TestTLSAuthMethod, TestTLSAuthGrantee))).Should(Succeed())
+	})
+})
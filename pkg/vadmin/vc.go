@@ -74,6 +74,12 @@ func genTLSConfigurationMap(tlsMode, secretNameInVdb, secretNamespace string) ma
 	switch {
 	case secrets.IsGSMSecret(secretNameInVdb):
 		return configMap
+	case secrets.IsMirroredSecret(secretNameInVdb):
+		// Vertica cannot read from Vault or Azure Key Vault. It reads the
+		// Kubernetes secret that the operator mirrors the secret into.
+		secretName = secrets.GetMirroredSecretName(secretNameInVdb)
+		secretManager = vops.K8sSecretManagerType
+		configMap[vops.TLSSecretManagerKeyNamespace] = secretNamespace
 	case secrets.IsAWSSecretsManagerSecret(secretNameInVdb):
 		region, _ := secrets.GetAWSRegion(secretNameInVdb)
 		configMap[vops.TLSSecretManagerKeyAWSRegion] = region
@@ -53,6 +53,47 @@ func RefreshCurrentSize(ctx context.Context, c client.Client, log logr.Logger, r
 	})
 }
 
+// SetScheduledSize records the active schedule window and the size it requires
+func SetScheduledSize(ctx context.Context, c client.Client, log logr.Logger, req *ctrl.Request,
+	windowName string, scheduledSize int32) error {
+	return vasStatusUpdater(ctx, c, log, req, func(vas *vapi.VerticaAutoscaler) {
+		vas.Status.ActiveScheduleWindow = windowName
+		vas.Status.ScheduledSize = scheduledSize
+	})
+}
+
+// SetVerticaMetrics records the last values sampled for the vertica metrics
+func SetVerticaMetrics(ctx context.Context, c client.Client, log logr.Logger, req *ctrl.Request,
+	verticaMetrics []vapi.VerticaMetricStatus) error {
+	return vasStatusUpdater(ctx, c, log, req, func(vas *vapi.VerticaAutoscaler) {
+		vas.Status.VerticaMetrics = verticaMetrics
+	})
+}
+
+// SetScaleInVictims records the subclusters chosen for removal on scale in
+func SetScaleInVictims(ctx context.Context, c client.Client, log logr.Logger, req *ctrl.Request,
+	victims []vapi.ScaleInVictim) error {
+	return vasStatusUpdater(ctx, c, log, req, func(vas *vapi.VerticaAutoscaler) {
+		vas.Status.ScaleInVictims = victims
+	})
+}
+
+// SetForecast records the latest forecast of the predictive policy
+func SetForecast(ctx context.Context, c client.Client, log logr.Logger, req *ctrl.Request,
+	forecast *vapi.PredictiveForecast) error {
+	return vasStatusUpdater(ctx, c, log, req, func(vas *vapi.VerticaAutoscaler) {
+		vas.Status.Forecast = forecast
+	})
+}
+
+// SetPolicyEvaluation records the last evaluation of the scaling policy
+func SetPolicyEvaluation(ctx context.Context, c client.Client, log logr.Logger, req *ctrl.Request,
+	evaluation *vapi.ScalingPolicyEvaluation) error {
+	return vasStatusUpdater(ctx, c, log, req, func(vas *vapi.VerticaAutoscaler) {
+		vas.Status.PolicyEvaluation = evaluation
+	})
+}
+
 // UpdateCondition will update a condition status.  This is a no-op if the
 // status condition is already set.
 func UpdateCondition(ctx context.Context, clnt client.Client, log logr.Logger,
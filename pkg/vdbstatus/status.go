@@ -139,3 +139,78 @@ func UpdateObservedConfigObjects(ctx context.Context, clnt client.Client, vdb *v
 		return nil
 	})
 }
+
+// SetConfigurationParametersPendingRestart will set the configuration
+// parameters that need a restart to take effect and update the input vdb.
+func SetConfigurationParametersPendingRestart(ctx context.Context, clnt client.Client, vdb *vapi.VerticaDB,
+	params []string) error {
+	return Update(ctx, clnt, vdb, func(vdb *vapi.VerticaDB) error {
+		vdb.Status.ConfigurationParametersPendingRestart = params
+		return nil
+	})
+}
+
+// SetAppliedConfigurationParameters will set the configuration parameters
+// that the operator has set in the database and update the input vdb.
+func SetAppliedConfigurationParameters(ctx context.Context, clnt client.Client, vdb *vapi.VerticaDB,
+	params []vapi.AppliedConfigurationParameter) error {
+	return Update(ctx, clnt, vdb, func(vdb *vapi.VerticaDB) error {
+		vdb.Status.AppliedConfigurationParameters = params
+		return nil
+	})
+}
+
+// UpdateHealthWatchdog will apply the given change to the health watchdog
+// state and update the input vdb. The state is created if it doesn't exist.
+func UpdateHealthWatchdog(ctx context.Context, clnt client.Client, vdb *vapi.VerticaDB,
+	updateFunc func(*vapi.HealthWatchdogStatus)) error {
+	return Update(ctx, clnt, vdb, func(vdb *vapi.VerticaDB) error {
+		if vdb.Status.HealthWatchdog == nil {
+			vdb.Status.HealthWatchdog = &vapi.HealthWatchdogStatus{}
+		}
+		updateFunc(vdb.Status.HealthWatchdog)
+		return nil
+	})
+}
+
+// ClearHealthWatchdog will remove the health watchdog state and update the
+// input vdb
+func ClearHealthWatchdog(ctx context.Context, clnt client.Client, vdb *vapi.VerticaDB) error {
+	return Update(ctx, clnt, vdb, func(vdb *vapi.VerticaDB) error {
+		vdb.Status.HealthWatchdog = nil
+		return nil
+	})
+}
+
+// SetClientTLSAuth will set the client certificate authentication state and
+// update the input vdb. Pass nil to clear the state.
+func SetClientTLSAuth(ctx context.Context, clnt client.Client, vdb *vapi.VerticaDB,
+	state *vapi.ClientTLSAuthStatus) error {
+	return Update(ctx, clnt, vdb, func(vdb *vapi.VerticaDB) error {
+		vdb.Status.ClientTLSAuth = state
+		return nil
+	})
+}
+
+// UpdatePasswordRotation will apply the given change to the password
+// rotation state and update the input vdb. The state is created if it doesn't
+// exist.
+func UpdatePasswordRotation(ctx context.Context, clnt client.Client, vdb *vapi.VerticaDB,
+	updateFunc func(*vapi.PasswordRotationStatus)) error {
+	return Update(ctx, clnt, vdb, func(vdb *vapi.VerticaDB) error {
+		if vdb.Status.PasswordRotation == nil {
+			vdb.Status.PasswordRotation = &vapi.PasswordRotationStatus{}
+		}
+		updateFunc(vdb.Status.PasswordRotation)
+		return nil
+	})
+}
+
+// SetPlan will set the plan of actions in the status and update the input
+// vdb. Pass nil to clear the plan.
+func SetPlan(ctx context.Context, clnt client.Client, vdb *vapi.VerticaDB, plan *vapi.ReconcilePlan) error {
+	return Update(ctx, clnt, vdb, func(vdb *vapi.VerticaDB) error {
+		vdb.Status.Plan = plan
+		return nil
+	})
+}
@@ -40,6 +40,12 @@ func GetScrutinizeInitContainer(cnts []corev1.Container) *corev1.Container {
 	return getNamedContainer(cnts, names.ScrutinizeInitContainer)
 }
 
+// GetScrutinizeAnalysisContainer returns a pointer to the container that runs
+// the cluster health analysis in the scrutinize pod
+func GetScrutinizeAnalysisContainer(cnts []corev1.Container) *corev1.Container {
+	return getNamedContainer(cnts, names.ScrutinizeAnalysisContainer)
+}
+
 func getNamedContainer(cnts []corev1.Container, cntName string) *corev1.Container {
 	for i := range cnts {
 		if cnts[i].Name == cntName {
@@ -93,6 +99,24 @@ func FindScrutinizeInitContainerStatus(pod *corev1.Pod) *corev1.ContainerStatus
 	return findContainerStatus(pod.Status.InitContainerStatuses, names.ScrutinizeInitContainer)
 }
 
+// FindScrutinizeAnalysisContainerStatus will return the status of the
+// analysis init container
+func FindScrutinizeAnalysisContainerStatus(pod *corev1.Pod) *corev1.ContainerStatus {
+	return findContainerStatus(pod.Status.InitContainerStatuses, names.ScrutinizeAnalysisContainer)
+}
+
+// FindWorkloadReplayContainerStatus will return the status of the init
+// container that runs the workload capture or replay
+func FindWorkloadReplayContainerStatus(pod *corev1.Pod) *corev1.ContainerStatus {
+	return findContainerStatus(pod.Status.InitContainerStatuses, names.WorkloadReplayContainer)
+}
+
+// FindWorkloadReplayMainContainerStatus will return the status of the
+// container that holds the output of the workload capture or replay
+func FindWorkloadReplayMainContainerStatus(pod *corev1.Pod) *corev1.ContainerStatus {
+	return findContainerStatus(pod.Status.ContainerStatuses, names.WorkloadReplayMainContainer)
+}
+
 // findContainerStatus is a helper to return status for a named container
 func findContainerStatus(cntStatuses []corev1.ContainerStatus, containerName string) *corev1.ContainerStatus {
 	for i := range cntStatuses {
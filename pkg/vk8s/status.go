@@ -0,0 +1,87 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vk8s
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// StatusObject is a custom resource whose status can be updated with
+// UpdateStatus. T is the resource type, so PT is the pointer to it.
+type StatusObject[T any] interface {
+	*T
+	client.Object
+}
+
+// ConditionsObject is a custom resource whose status has a state and a list
+// of conditions that UpdateConditions can set
+type ConditionsObject[T any] interface {
+	StatusObject[T]
+	SetStatusConditions(state string, conditions []*metav1.Condition)
+}
+
+// UpdateStatus will update the status of the object using the given update
+// function. The function is applied to the latest copy of the object. The
+// input object is updated in-place with the new status.
+func UpdateStatus[T any, PT StatusObject[T]](ctx context.Context, clnt client.Client, log logr.Logger,
+	obj PT, updateFunc func(PT) error) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		// Always fetch the latest to minimize the chance of getting a conflict error.
+		err := clnt.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				log.Info("Resource not found.  Ignoring since object must be deleted", "name", obj.GetName())
+				return nil
+			}
+			return err
+		}
+		// We will calculate the status for the object. This update is done on a
+		// copy. If anything differs from the original then we will do a single
+		// update. The update function only changes the status, so comparing
+		// and copying the whole object is the same as doing it for the status.
+		objChg, ok := obj.DeepCopyObject().(PT)
+		if !ok {
+			return fmt.Errorf("failed to copy %s", obj.GetName())
+		}
+		// Refresh the status using the users provided function
+		if err := updateFunc(objChg); err != nil {
+			return err
+		}
+		if reflect.DeepEqual(obj, objChg) {
+			return nil
+		}
+		log.Info("Updating status", "name", obj.GetName())
+		*obj = *objChg
+		return clnt.Status().Update(ctx, obj)
+	})
+}
+
+// UpdateConditions will set the given conditions and state in the status
+func UpdateConditions[T any, PT ConditionsObject[T]](ctx context.Context, clnt client.Client, log logr.Logger,
+	obj PT, conditions []*metav1.Condition, state string) error {
+	return UpdateStatus(ctx, clnt, log, obj, func(objChg PT) error {
+		objChg.SetStatusConditions(state, conditions)
+		return nil
+	})
+}
@@ -0,0 +1,75 @@
+/*
+ (c) Copyright [2021-2024] Open Text.
+ Licensed under the Apache License, Version 2.0 (the "License");
+ You may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package vk8s
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	vapi "github.com/vertica/vertica-kubernetes/api/v1"
+	"github.com/vertica/vertica-kubernetes/api/v1beta1"
+	"github.com/vertica/vertica-kubernetes/pkg/test"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("vk8s/status", func() {
+	ctx := context.Background()
+
+	It("should update status conditions and state", func() {
+		vusr := v1beta1.MakeVusr()
+		Expect(k8sClient.Create(ctx, vusr)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vusr)).Should(Succeed()) }()
+
+		cond := metav1.Condition{Type: v1beta1.UserReady, Status: metav1.ConditionTrue, Reason: vapi.UnknownReason}
+		Expect(UpdateConditions(ctx, k8sClient, logger, vusr, []*metav1.Condition{&cond}, "Ready")).Should(Succeed())
+		fetchVusr := &v1beta1.VerticaUser{}
+		Expect(k8sClient.Get(ctx, vusr.ExtractNamespacedName(), fetchVusr)).Should(Succeed())
+		for _, v := range []*v1beta1.VerticaUser{vusr, fetchVusr} {
+			Expect(v.Status.State).Should(Equal("Ready"))
+			Expect(v.Status.Conditions).Should(HaveLen(1))
+			Expect(v.Status.Conditions[0]).Should(test.EqualMetaV1Condition(cond))
+		}
+	})
+
+	It("should update the status with the update function", func() {
+		vbackup := v1beta1.MakeVbackup()
+		Expect(k8sClient.Create(ctx, vbackup)).Should(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, vbackup)).Should(Succeed()) }()
+
+		Expect(UpdateStatus(ctx, k8sClient, logger, vbackup, func(v *v1beta1.VerticaBackup) error {
+			v.Status.State = "Running"
+			return nil
+		})).Should(Succeed())
+		fetchVbackup := &v1beta1.VerticaBackup{}
+		Expect(k8sClient.Get(ctx, vbackup.ExtractNamespacedName(), fetchVbackup)).Should(Succeed())
+		Expect(vbackup.Status.State).Should(Equal("Running"))
+		Expect(fetchVbackup.Status.State).Should(Equal("Running"))
+
+		// Nothing is updated when the status doesn't change
+		rv := fetchVbackup.ResourceVersion
+		Expect(UpdateStatus(ctx, k8sClient, logger, vbackup, func(*v1beta1.VerticaBackup) error {
+			return nil
+		})).Should(Succeed())
+		Expect(k8sClient.Get(ctx, vbackup.ExtractNamespacedName(), fetchVbackup)).Should(Succeed())
+		Expect(fetchVbackup.ResourceVersion).Should(Equal(rv))
+	})
+
+	It("should ignore an object that was deleted", func() {
+		vusr := v1beta1.MakeVusr()
+		Expect(UpdateConditions(ctx, k8sClient, logger, vusr, nil, "Ready")).Should(Succeed())
+	})
+})
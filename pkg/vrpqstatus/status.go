@@ -84,3 +84,21 @@ func Update(ctx context.Context, clnt client.Client, log logr.Logger,
 	}
 	return updateImpl(ctx, clnt, log, vrpq, refreshConditionInPlace)
 }
+
+// UpdateReviveCopy will update the conditions and state along with the outcome
+// of a restore. Unlike Update, the restore points from the query are left
+// untouched.
+func UpdateReviveCopy(ctx context.Context, clnt client.Client, log logr.Logger,
+	vrpq *vapi.VerticaRestorePointsQuery, conditions []*metav1.Condition, state string,
+	restorePoint *vclusterops.RestorePoint, vdbName string) error {
+	refreshReviveCopyInPlace := func(vrpq *vapi.VerticaRestorePointsQuery) error {
+		vrpq.Status.State = state
+		for _, condition := range conditions {
+			meta.SetStatusCondition(&vrpq.Status.Conditions, *condition)
+		}
+		vrpq.Status.RevivedRestorePoint = restorePoint
+		vrpq.Status.RevivedVerticaDBName = vdbName
+		return nil
+	}
+	return updateImpl(ctx, clnt, log, vrpq, refreshReviveCopyInPlace)
+}
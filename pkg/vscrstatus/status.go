@@ -62,6 +62,7 @@ func UpdateStatus(ctx context.Context, clnt client.Client, vscr *v1beta1.Vertica
 		vscr.Status.PodName = vscrChgStatus.PodName
 		vscr.Status.PodUID = vscrChgStatus.PodUID
 		vscr.Status.TarballName = vscrChgStatus.TarballName
+		vscr.Status.AnalysisReportName = vscrChgStatus.AnalysisReportName
 		vscr.Status.State = vscrChgStatus.State
 		for _, condition := range vscrChgStatus.Conditions {
 			meta.SetStatusCondition(&vscr.Status.Conditions, condition)
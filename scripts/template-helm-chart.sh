@@ -186,6 +186,13 @@ do
     perl -i -0777 -pe 's/(CONCURRENCY_VERTICASCRUTINIZE: ).*/$1\{\{ .Values.reconcileConcurrency.verticascrutinize | quote \}\}/g' $f
     perl -i -0777 -pe 's/(CONCURRENCY_SANDBOXCONFIGMAP: ).*/$1\{\{ .Values.reconcileConcurrency.sandboxconfigmap | quote \}\}/g' $f
     perl -i -0777 -pe 's/(CONCURRENCY_VERTICAREPLICATOR: ).*/$1\{\{ .Values.reconcileConcurrency.verticareplicator | quote \}\}/g' $f
+    perl -i -0777 -pe 's/(CONCURRENCY_VERTICARESTOREPOINTSCHEDULE: ).*/$1\{\{ .Values.reconcileConcurrency.verticarestorepointschedule | quote \}\}/g' $f
+    perl -i -0777 -pe 's/(CONCURRENCY_VERTICAUSER: ).*/$1\{\{ .Values.reconcileConcurrency.verticauser | quote \}\}/g' $f
+    perl -i -0777 -pe 's/(CONCURRENCY_VERTICAROLE: ).*/$1\{\{ .Values.reconcileConcurrency.verticarole | quote \}\}/g' $f
+    perl -i -0777 -pe 's/(CONCURRENCY_VERTICARESOURCEPOOL: ).*/$1\{\{ .Values.reconcileConcurrency.verticaresourcepool | quote \}\}/g' $f
+    perl -i -0777 -pe 's/(CONCURRENCY_VERTICABACKUP: ).*/$1\{\{ .Values.reconcileConcurrency.verticabackup | quote \}\}/g' $f
+    perl -i -0777 -pe 's/(CONCURRENCY_VERTICAHEALTHCHECK: ).*/$1\{\{ .Values.reconcileConcurrency.verticahealthcheck | quote \}\}/g' $f
+    perl -i -0777 -pe 's/(CONCURRENCY_VERTICAWORKLOADREPLAY: ).*/$1\{\{ .Values.reconcileConcurrency.verticaworkloadreplay | quote \}\}/g' $f
 done
 
 # 21. Add permissions to manager ClusterRole to allow it to patch the CRD. This
@@ -235,6 +242,11 @@ do
   perl -i -0777 -pe 's/(CONTROLLERS_SCOPE:).*/$1 {{ quote .Values.controllers.scope }}/g' $fn
   perl -i -0777 -pe 's/(VDB_MAX_BACKOFF_DURATION:).*/$1 {{ quote .Values.controllers.vdbMaxBackoffDuration }}/g' $fn
   perl -i -0777 -pe 's/(SANDBOX_MAX_BACKOFF_DURATION:).*/$1 {{ quote .Values.controllers.sandboxMaxBackoffDuration }}/g' $fn
+  perl -i -0777 -pe 's/(VAULT_ADDR:).*/$1 {{ quote .Values.vault.addr }}/g' $fn
+  perl -i -0777 -pe 's/(VAULT_ROLE:).*/$1 {{ quote .Values.vault.role }}/g' $fn
+  perl -i -0777 -pe 's/(VAULT_AUTH_PATH:).*/$1 {{ quote .Values.vault.authPath }}/g' $fn
+  perl -i -0777 -pe 's/(VAULT_CACERT:).*/$1 {{ if and (empty .Values.vault.caCert) .Values.vault.caSecret }}"\/etc\/vault-ca\/ca.crt"{{ else }}{{ quote .Values.vault.caCert }}{{ end }}/g' $fn
+  perl -i -0777 -pe 's/(VAULT_NAMESPACE:).*/$1 {{ quote .Values.vault.namespace }}/g' $fn
   # Update the webhook-cert-secret configMap entry to include the actual name of the secret
   perl -i -0777 -pe 's/(WEBHOOK_CERT_SECRET: )(.*)/$1\{\{ include "vdb-op.certSecret" . \}\}/g' $fn
   perl -i -0777 -pe 's/(LOG_LEVEL: )(.*)/$1\{{ quote .Values.logging.level }}\n  LOG_FILE_PATH: {{ default "" .Values.logging.filePath | quote }}\n  LOG_MAX_FILE_SIZE: {{ default "" .Values.logging.maxFileSize | quote }}\n  LOG_MAX_FILE_AGE: {{ default "" .Values.logging.maxFileAge | quote }}\n  LOG_MAX_FILE_ROTATION: {{ default "" .Values.logging.maxFileRotation | quote }}\n  DEV_MODE: {{ default "" .Values.logging.dev | quote }}/g' $fn
@@ -272,3 +284,7 @@ do
   perl -i -0777 -pe 's/name: \{\{ include "vdb-op.name" \. \}\}-alloy-sa/name: alloy-vertica-sa/g' $f
   echo "{{- end }}" >> $f
 done
+
+# 29. Conditionally mount the CA cert of the Vault server in the operator pod
+perl -i -0777 -pe 's/(\n( *)volumeMounts:\n)/$1\{\{- if .Values.vault.caSecret \}\}\n$2- mountPath: \/etc\/vault-ca\n$2  name: vault-ca\n$2  readOnly: true\n\{\{- end \}\}\n/' $TEMPLATE_DIR/verticadb-operator-manager-deployment.yaml
+perl -i -0777 -pe 's/(\n( *)volumes:\n)/$1\{\{- if .Values.vault.caSecret \}\}\n$2- name: vault-ca\n$2  secret:\n$2    secretName: \{\{ .Values.vault.caSecret \}\}\n\{\{- end \}\}\n/' $TEMPLATE_DIR/verticadb-operator-manager-deployment.yaml